@@ -110,9 +110,17 @@ type (
 	Diff struct {
 		// SkipChanges configures the skip changes policy.
 		SkipChanges *SkipChanges `spec:"skip"`
+		// RenameSchemas hints the differ about schemas that were renamed.
+		RenameSchemas []*RenameSchema `spec:"rename_schema"`
 		schemahcl.DefaultExtension
 	}
 
+	// RenameSchema represents a schema rename hint.
+	RenameSchema struct {
+		From string `spec:"from"`
+		To   string `spec:"to"`
+	}
+
 	// SkipChanges represents the skip changes policy.
 	SkipChanges struct {
 		AddSchema        bool `spec:"add_schema"`
@@ -256,6 +264,9 @@ func (d *Diff) Extend(global *Diff) *Diff {
 	if d.SkipChanges == nil {
 		d.SkipChanges = global.SkipChanges
 	}
+	if d.RenameSchemas == nil {
+		d.RenameSchemas = global.RenameSchemas
+	}
 	return d
 }
 
@@ -265,6 +276,9 @@ func (d *Diff) Options() (opts []schema.DiffOption) {
 	opts = append(opts, func(opts *schema.DiffOptions) {
 		opts.Extra = d.DefaultExtension
 	})
+	for _, r := range d.RenameSchemas {
+		opts = append(opts, schema.DiffRenameSchema(r.From, r.To))
+	}
 	if d.SkipChanges == nil {
 		return
 	}
@@ -312,4 +312,9 @@ func TestDiff_Options(t *testing.T) {
 	opts = schema.NewDiffOptions(d.Options()...)
 	require.True(t, opts.Skipped(&schema.DropSchema{}))
 	require.True(t, opts.Skipped(&schema.DropTable{}))
+
+	d.RenameSchemas = []*RenameSchema{{From: "old", To: "new"}}
+	require.Len(t, d.Options(), 3)
+	opts = schema.NewDiffOptions(d.Options()...)
+	require.Equal(t, map[string]string{"old": "new"}, opts.RenameSchemas)
 }
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"ariga.io/atlas/sql/schema"
@@ -289,6 +290,188 @@ func (r *TypeRegistry) Type(typ *Type, extra []*Attr) (schema.Type, error) {
 	return r.parser(printType)
 }
 
+// ParseType parses the raw type (e.g. "geometry(3)") using the TypeSpec that matches its
+// name, and reports if such a TypeSpec exists in the registry. The type arguments are mapped
+// to the attributes of the TypeSpec by their order, and the result is created by the FromSpec
+// function of the TypeSpec, if it is defined, or by setting the fields of its RType otherwise.
+//
+// Drivers use it for parsing custom types that were registered by users, as it allows
+// describing such types once, for both the HCL and the database representations.
+func (r *TypeRegistry) ParseType(raw string) (schema.Type, bool, error) {
+	name, args, ok := splitType(raw)
+	if !ok {
+		return nil, false, nil
+	}
+	var spec *TypeSpec
+	for _, ts := range r.r {
+		if strings.EqualFold(ts.T, name) {
+			spec = ts
+			break
+		}
+	}
+	if spec == nil {
+		return nil, false, nil
+	}
+	typ := &Type{T: spec.T}
+Args:
+	for i, a := range args {
+		if i >= len(spec.Attributes) {
+			return nil, true, fmt.Errorf("specutil: unexpected argument %q for type %q", a, spec.T)
+		}
+		attr := spec.Attributes[i]
+		switch attr.Kind {
+		case reflect.Int, reflect.Int64:
+			v, err := strconv.Atoi(a)
+			if err != nil {
+				return nil, true, fmt.Errorf("specutil: invalid %q argument for type %q: %w", attr.Name, spec.T, err)
+			}
+			typ.Attrs = append(typ.Attrs, IntAttr(attr.Name, v))
+		case reflect.Bool:
+			v, err := strconv.ParseBool(a)
+			if err != nil {
+				return nil, true, fmt.Errorf("specutil: invalid %q argument for type %q: %w", attr.Name, spec.T, err)
+			}
+			typ.Attrs = append(typ.Attrs, BoolAttr(attr.Name, v))
+		case reflect.String:
+			typ.Attrs = append(typ.Attrs, StringAttr(attr.Name, a))
+		case reflect.Slice:
+			// Slice attributes are last and hold the rest of the arguments.
+			typ.Attrs = append(typ.Attrs, StringsAttr(attr.Name, args[i:]...))
+			break Args
+		default:
+			return nil, true, fmt.Errorf("specutil: unsupported attr kind %s for attribute %q of %q", attr.Kind, attr.Name, spec.Name)
+		}
+	}
+	if spec.FromSpec != nil {
+		t, err := spec.FromSpec(typ)
+		return t, true, err
+	}
+	t, err := specType(spec, name, typ)
+	return t, true, err
+}
+
+// FormatType formats the schema.Type using the TypeSpec that matches it, and reports if
+// such a TypeSpec exists in the registry. The Format function of the TypeSpec is used for
+// formatting the type, if it is defined. See ParseType for more info.
+func (r *TypeRegistry) FormatType(t schema.Type) (string, bool, error) {
+	rv := reflect.Indirect(reflect.ValueOf(t))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return "", false, nil
+	}
+	spec, ok := r.findType(rv)
+	if !ok {
+		return "", false, nil
+	}
+	typ, err := r.Convert(t)
+	if err != nil {
+		return "", true, err
+	}
+	if spec.Format != nil {
+		f, err := spec.Format(typ)
+		return f, true, err
+	}
+	f, err := r.PrintType(typ)
+	return f, true, err
+}
+
+// specType creates a schema.Type from the RType of the spec, and sets its T
+// field and the fields that match the attributes of the type.
+func specType(spec *TypeSpec, name string, typ *Type) (schema.Type, error) {
+	if spec.RType == nil || spec.RType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("specutil: missing FromSpec or RType for type %q", spec.T)
+	}
+	rv := reflect.New(spec.RType)
+	t, ok := rv.Interface().(schema.Type)
+	if !ok {
+		return nil, fmt.Errorf("specutil: %s does not implement schema.Type", rv.Type())
+	}
+	rv = rv.Elem()
+	if f := rv.FieldByName("T"); f.IsValid() && f.Kind() == reflect.String {
+		f.SetString(name)
+	}
+	for _, a := range typ.Attrs {
+		f := rv.FieldByName(inflect.Camelize(a.K))
+		if !f.IsValid() {
+			continue
+		}
+		if f.Kind() == reflect.Ptr {
+			f.Set(reflect.New(f.Type().Elem()))
+			f = f.Elem()
+		}
+		switch f.Kind() {
+		case reflect.Int, reflect.Int64:
+			v, err := a.Int()
+			if err != nil {
+				return nil, err
+			}
+			f.SetInt(int64(v))
+		case reflect.Bool:
+			v, err := a.Bool()
+			if err != nil {
+				return nil, err
+			}
+			f.SetBool(v)
+		case reflect.String:
+			v, err := a.String()
+			if err != nil {
+				return nil, err
+			}
+			f.SetString(v)
+		case reflect.Slice:
+			v, err := a.Strings()
+			if err != nil {
+				return nil, err
+			}
+			f.Set(reflect.ValueOf(v))
+		default:
+			return nil, fmt.Errorf("specutil: unsupported field kind %s for attribute %q of %q", f.Kind(), a.K, spec.Name)
+		}
+	}
+	return t, nil
+}
+
+// splitType splits the raw type into its name and arguments. Quoted
+// arguments (e.g. enum values) are unquoted.
+func splitType(raw string) (string, []string, bool) {
+	raw = strings.TrimSpace(raw)
+	i := strings.IndexByte(raw, '(')
+	if i == -1 {
+		return raw, nil, true
+	}
+	if !strings.HasSuffix(raw, ")") {
+		return "", nil, false
+	}
+	var (
+		args   []string
+		quote  byte
+		name   = strings.TrimSpace(raw[:i])
+		arg, b = "", []byte(raw[i+1 : len(raw)-1])
+	)
+	for j := 0; j < len(b); j++ {
+		switch c := b[j]; {
+		case quote != 0 && c == quote && j+1 < len(b) && b[j+1] == quote:
+			// Escaped quote (e.g. 'a''b').
+			arg += string(c)
+			j++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			arg += string(c)
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ',':
+			args = append(args, strings.TrimSpace(arg))
+			arg = ""
+		default:
+			arg += string(c)
+		}
+	}
+	if quote != 0 {
+		return "", nil, false
+	}
+	return name, append(args, strings.TrimSpace(arg)), true
+}
+
 // TypeSpecOption configures a schemahcl.TypeSpec.
 type TypeSpecOption func(*TypeSpec)
 
@@ -227,3 +227,51 @@ func unsignedTypeAttr() *TypeAttr {
 		Kind: reflect.Bool,
 	}
 }
+
+func TestRegistry_ParseFormatType(t *testing.T) {
+	type GeoType struct {
+		schema.Type
+		T    string
+		Size int
+	}
+	r := &TypeRegistry{}
+	err := r.Register(
+		&TypeSpec{Name: "geo", T: "geo", Attributes: []*TypeAttr{SizeTypeAttr(false)}, RType: reflect.TypeOf(GeoType{})},
+		NewTypeSpec("label", WithAttributes(&TypeAttr{Name: "values", Kind: reflect.Slice}), WithFromSpec(func(t *Type) (schema.Type, error) {
+			vs, err := t.Attrs[0].Strings()
+			if err != nil {
+				return nil, err
+			}
+			return &schema.EnumType{T: "label", Values: vs}, nil
+		})),
+	)
+	require.NoError(t, err)
+
+	typ, ok, err := r.ParseType("GEO(3)")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, &GeoType{T: "GEO", Size: 3}, typ)
+	f, ok, err := r.FormatType(&GeoType{T: "geo", Size: 3})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "geo(3)", f)
+	typ, ok, err = r.ParseType("geo")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, &GeoType{T: "geo"}, typ)
+	_, ok, err = r.ParseType("geo(a)")
+	require.True(t, ok)
+	require.Error(t, err)
+
+	typ, ok, err = r.ParseType("label('a', 'b''c', \"d,e\")")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, &schema.EnumType{T: "label", Values: []string{"a", "b'c", "d,e"}}, typ)
+
+	_, ok, err = r.ParseType("int")
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, ok, err = r.FormatType(&schema.IntegerType{T: "int"})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,289 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// BigQuery data types, as defined in its documentation.
+// https://cloud.google.com/bigquery/docs/reference/standard-sql/data-types
+const (
+	TypeInt64      = "INT64"
+	TypeNumeric    = "NUMERIC"
+	TypeBigNumeric = "BIGNUMERIC"
+	TypeFloat64    = "FLOAT64"
+	TypeBool       = "BOOL"
+	TypeString     = "STRING"
+	TypeBytes      = "BYTES"
+	TypeDate       = "DATE"
+	TypeDateTime   = "DATETIME"
+	TypeTime       = "TIME"
+	TypeTimestamp  = "TIMESTAMP"
+	TypeInterval   = "INTERVAL"
+	TypeJSON       = "JSON"
+	TypeGeography  = "GEOGRAPHY"
+	TypeArray      = "ARRAY"
+	TypeStruct     = "STRUCT"
+)
+
+// FormatType converts schema type to its column form in the database.
+func FormatType(t schema.Type) (string, error) {
+	var f string
+	switch t := t.(type) {
+	case *schema.BoolType:
+		f = TypeBool
+	case *schema.IntegerType:
+		f = TypeInt64
+	case *schema.FloatType:
+		f = TypeFloat64
+	case *schema.DecimalType:
+		f = strings.ToUpper(t.T)
+		if f == "" {
+			f = TypeNumeric
+		}
+		switch {
+		case t.Precision > 0 && t.Scale > 0:
+			f = fmt.Sprintf("%s(%d, %d)", f, t.Precision, t.Scale)
+		case t.Precision > 0:
+			f = fmt.Sprintf("%s(%d)", f, t.Precision)
+		}
+	case *schema.StringType:
+		f = TypeString
+		if t.Size > 0 {
+			f = fmt.Sprintf("%s(%d)", f, t.Size)
+		}
+	case *schema.BinaryType:
+		f = TypeBytes
+		if t.Size != nil && *t.Size > 0 {
+			f = fmt.Sprintf("%s(%d)", f, *t.Size)
+		}
+	case *schema.TimeType:
+		f = strings.ToUpper(t.T)
+	case *IntervalType:
+		f = TypeInterval
+	case *schema.JSONType:
+		f = TypeJSON
+	case *schema.SpatialType:
+		f = TypeGeography
+	case *ArrayType:
+		if t.Type == nil {
+			return "", fmt.Errorf("bigquery: missing element type for ARRAY type")
+		}
+		e, err := FormatType(t.Type)
+		if err != nil {
+			return "", err
+		}
+		f = fmt.Sprintf("%s<%s>", TypeArray, e)
+	case *StructType:
+		fields := make([]string, len(t.Fields))
+		for i, c := range t.Fields {
+			ft, err := FormatType(c.Type.Type)
+			if err != nil {
+				return "", err
+			}
+			fields[i] = fmt.Sprintf("%s %s", c.Name, ft)
+			if !c.Type.Null {
+				fields[i] += " NOT NULL"
+			}
+		}
+		f = fmt.Sprintf("%s<%s>", TypeStruct, strings.Join(fields, ", "))
+	case *schema.UnsupportedType:
+		f = t.T
+	default:
+		return "", fmt.Errorf("bigquery: unexpected type: %T", t)
+	}
+	return f, nil
+}
+
+// ParseType returns the schema.Type value represented by the given raw type.
+// Parameterized types (e.g. STRING(10)), and nested types (ARRAY and STRUCT)
+// are parsed recursively.
+func ParseType(raw string) (schema.Type, error) {
+	s := strings.TrimSpace(raw)
+	name, args, err := typeArgs(s)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case TypeInt64, "INT", "SMALLINT", "INTEGER", "BIGINT", "TINYINT", "BYTEINT":
+		return &schema.IntegerType{T: TypeInt64}, nil
+	case TypeFloat64:
+		return &schema.FloatType{T: TypeFloat64}, nil
+	case TypeNumeric, "DECIMAL", TypeBigNumeric, "BIGDECIMAL":
+		t := &schema.DecimalType{T: TypeNumeric}
+		if strings.HasPrefix(name, "BIG") {
+			t.T = TypeBigNumeric
+		}
+		if len(args) > 2 {
+			return nil, fmt.Errorf("bigquery: unexpected decimal type: %q", raw)
+		}
+		if len(args) > 0 {
+			if t.Precision, err = strconv.Atoi(args[0]); err != nil {
+				return nil, fmt.Errorf("bigquery: parse precision %q: %w", args[0], err)
+			}
+		}
+		if len(args) > 1 {
+			if t.Scale, err = strconv.Atoi(args[1]); err != nil {
+				return nil, fmt.Errorf("bigquery: parse scale %q: %w", args[1], err)
+			}
+		}
+		return t, nil
+	case TypeBool, "BOOLEAN":
+		return &schema.BoolType{T: TypeBool}, nil
+	case TypeString, TypeBytes:
+		var size int
+		if len(args) == 1 {
+			if size, err = strconv.Atoi(args[0]); err != nil {
+				return nil, fmt.Errorf("bigquery: parse size %q: %w", args[0], err)
+			}
+		}
+		if name == TypeString {
+			return &schema.StringType{T: TypeString, Size: size}, nil
+		}
+		t := &schema.BinaryType{T: TypeBytes}
+		if size > 0 {
+			t.Size = &size
+		}
+		return t, nil
+	case TypeDate, TypeDateTime, TypeTime, TypeTimestamp:
+		return &schema.TimeType{T: name}, nil
+	case TypeInterval:
+		return &IntervalType{T: TypeInterval}, nil
+	case TypeJSON:
+		return &schema.JSONType{T: TypeJSON}, nil
+	case TypeGeography:
+		return &schema.SpatialType{T: TypeGeography}, nil
+	case TypeArray:
+		inner, ok := elemType(s, TypeArray)
+		if !ok {
+			return nil, fmt.Errorf("bigquery: unexpected array type: %q", raw)
+		}
+		e, err := ParseType(inner)
+		if err != nil {
+			return nil, err
+		}
+		return &ArrayType{T: TypeArray, Type: e}, nil
+	case TypeStruct:
+		inner, ok := elemType(s, TypeStruct)
+		if !ok {
+			return nil, fmt.Errorf("bigquery: unexpected struct type: %q", raw)
+		}
+		return parseStruct(inner)
+	default:
+		return &schema.UnsupportedType{T: s}, nil
+	}
+}
+
+// parseStruct parses the fields of a STRUCT type. e.g. "a INT64 NOT NULL, b STRING".
+func parseStruct(inner string) (*StructType, error) {
+	t := &StructType{T: TypeStruct}
+	for _, f := range splitTop(inner) {
+		i := strings.IndexAny(f, " \t\n")
+		if i == -1 {
+			return nil, fmt.Errorf("bigquery: unexpected struct field: %q", f)
+		}
+		name, rest := strings.Trim(f[:i], "`"), strings.TrimSpace(f[i:])
+		// Field options (e.g. descriptions) are not modeled by Atlas.
+		if j := indexTop(strings.ToUpper(rest), " OPTIONS"); j != -1 {
+			rest = strings.TrimSpace(rest[:j])
+		}
+		null := true
+		if u := strings.ToUpper(rest); strings.HasSuffix(u, " NOT NULL") {
+			rest, null = strings.TrimSpace(rest[:len(rest)-len(" NOT NULL")]), false
+		}
+		ft, err := ParseType(rest)
+		if err != nil {
+			return nil, err
+		}
+		t.Fields = append(t.Fields, &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Type: ft, Raw: rest, Null: null},
+		})
+	}
+	return t, nil
+}
+
+// typeArgs splits the given type into its (upper-cased) name and its parenthesized arguments.
+// Nested types (e.g. ARRAY<INT64>) are returned without arguments.
+func typeArgs(s string) (string, []string, error) {
+	i := strings.IndexAny(s, "(<")
+	if i == -1 {
+		return strings.ToUpper(s), nil, nil
+	}
+	name := strings.ToUpper(strings.TrimSpace(s[:i]))
+	if s[i] == '<' {
+		return name, nil, nil
+	}
+	if !strings.HasSuffix(s, ")") {
+		return "", nil, fmt.Errorf("bigquery: unexpected type: %q", s)
+	}
+	args := splitTop(s[i+1 : len(s)-1])
+	return name, args, nil
+}
+
+// elemType returns the inner part of a nested type. e.g. INT64 for ARRAY<INT64>.
+func elemType(s, name string) (string, bool) {
+	s = strings.TrimSpace(s[len(name):])
+	if !strings.HasPrefix(s, "<") || !strings.HasSuffix(s, ">") {
+		return "", false
+	}
+	return strings.TrimSpace(s[1 : len(s)-1]), true
+}
+
+// splitTop splits the given list by its top-level commas, ignoring
+// the ones that are nested in brackets, parentheses or quotes.
+func splitTop(s string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '(', '<', '[':
+			depth++
+		case ')', '>', ']':
+			depth--
+		case '\'', '"', '`':
+			for i++; i < len(s) && s[i] != c; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if p := strings.TrimSpace(s[start:]); p != "" {
+		parts = append(parts, p)
+	}
+	return parts
+}
+
+// indexTop returns the index of the first top-level occurrence of sub in s, or -1.
+func indexTop(s, sub string) int {
+	var depth int
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '<', '[':
+			depth++
+		case ')', '>', ']':
+			depth--
+		default:
+			if depth == 0 && strings.HasPrefix(s[i:], sub) {
+				return i
+			}
+		}
+	}
+	return -1
+}
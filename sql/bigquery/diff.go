@@ -0,0 +1,204 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultDiff provides basic diffing capabilities for BigQuery.
+// Note, it is recommended to call Open, create a new Driver and use its
+// Differ when a database connection is available.
+var DefaultDiff schema.Differ = &sqlx.Diff{DiffDriver: &diff{&conn{ExecQuerier: sqlx.NoRows}}}
+
+// A diff provides a BigQuery implementation for sqlx.DiffDriver.
+type diff struct{ *conn }
+
+// SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
+func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
+	// No special dataset attribute diffing for BigQuery.
+	return nil
+}
+
+// SchemaObjectDiff returns a changeset for migrating schema objects from
+// one state to the other.
+func (*diff) SchemaObjectDiff(_, _ *schema.Schema) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (*diff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
+	var changes []schema.Change
+	var p1, p2 PartitionBy
+	switch fromHas, toHas := sqlx.Has(from.Attrs, &p1), sqlx.Has(to.Attrs, &p2); {
+	case !fromHas && toHas:
+		changes = append(changes, &schema.AddAttr{A: &p2})
+	case fromHas && !toHas:
+		changes = append(changes, &schema.DropAttr{A: &p1})
+	case fromHas && toHas && normExpr(p1.X) != normExpr(p2.X):
+		changes = append(changes, &schema.ModifyAttr{From: &p1, To: &p2})
+	}
+	var c1, c2 ClusterBy
+	switch fromHas, toHas := sqlx.Has(from.Attrs, &c1), sqlx.Has(to.Attrs, &c2); {
+	case !fromHas && toHas:
+		changes = append(changes, &schema.AddAttr{A: &c2})
+	case fromHas && !toHas:
+		changes = append(changes, &schema.DropAttr{A: &c1})
+	case fromHas && toHas && strings.Join(c1.Columns, ",") != strings.Join(c2.Columns, ","):
+		changes = append(changes, &schema.ModifyAttr{From: &c1, To: &c2})
+	}
+	changes = append(changes, optionsDiff(from.Attrs, to.Attrs)...)
+	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// optionsDiff returns the changes for migrating the table options from one state to the other.
+func optionsDiff(from, to []schema.Attr) []schema.Change {
+	var (
+		changes []schema.Change
+		toOpts  = options(to)
+	)
+	for _, o1 := range options(from) {
+		o2, ok := toOpts[o1.K]
+		switch {
+		case !ok:
+			changes = append(changes, &schema.DropAttr{A: o1})
+		case optionValue(o1.V) != optionValue(o2.V):
+			changes = append(changes, &schema.ModifyAttr{From: o1, To: o2})
+		}
+	}
+	for _, o2 := range to {
+		if o2, ok := o2.(*Option); ok && !hasOption(from, o2.K) {
+			changes = append(changes, &schema.AddAttr{A: o2})
+		}
+	}
+	return changes
+}
+
+// options returns the table options keyed by their names.
+func options(attrs []schema.Attr) map[string]*Option {
+	opts := make(map[string]*Option)
+	for _, a := range attrs {
+		if o, ok := a.(*Option); ok {
+			opts[o.K] = o
+		}
+	}
+	return opts
+}
+
+// hasOption reports if the given option exists in the attributes.
+func hasOption(attrs []schema.Attr, k string) bool {
+	_, ok := options(attrs)[k]
+	return ok
+}
+
+// optionValue returns the normalized value of an option used for comparison.
+// BigQuery returns string values double-quoted, and keywords in upper case.
+func optionValue(v string) string {
+	if sqlx.IsQuoted(v, '"', '\'') {
+		if u, err := unquote(v); err == nil {
+			return strconv.Quote(u)
+		}
+	}
+	return strings.ToUpper(v)
+}
+
+// normExpr returns the normalized form of an expression used for comparison.
+func normExpr(x string) string {
+	x = strings.ReplaceAll(strings.ToUpper(x), "`", "")
+	return strings.Join(strings.Fields(x), " ")
+}
+
+func (*diff) ViewAttrChanged(_, _ *schema.View) bool {
+	return false // Not implemented.
+}
+
+// ColumnChange returns the schema changes (if any) for migrating one column to the other.
+func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column) (schema.ChangeKind, error) {
+	change := sqlx.CommentChange(from.Attrs, to.Attrs)
+	// ARRAY columns are always REPEATED, and cannot be NULL.
+	if from.Type.Null != to.Type.Null && !isArray(to) {
+		change |= schema.ChangeNull
+	}
+	changed, err := d.typeChanged(from, to)
+	if err != nil {
+		return schema.NoChange, err
+	}
+	if changed {
+		change |= schema.ChangeType
+	}
+	if d1, d2 := defaultKey(from), defaultKey(to); d1 != d2 {
+		change |= schema.ChangeDefault
+	}
+	return change, nil
+}
+
+// typeChanged reports if the column type was changed.
+func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
+	fromT, toT := from.Type.Type, to.Type.Type
+	if fromT == nil || toT == nil {
+		return false, fmt.Errorf("bigquery: missing type information for column %q", from.Name)
+	}
+	from1, err := FormatType(fromT)
+	if err != nil {
+		return false, err
+	}
+	to1, err := FormatType(toT)
+	if err != nil {
+		return false, err
+	}
+	return !strings.EqualFold(strings.ReplaceAll(from1, " ", ""), strings.ReplaceAll(to1, " ", "")), nil
+}
+
+// defaultKey returns the default value of the column used for comparison.
+// String literals are compared by their values, as BigQuery returns them
+// quoted with either single or double quotes.
+func defaultKey(c *schema.Column) string {
+	x, ok := sqlx.DefaultValue(c)
+	if !ok {
+		return ""
+	}
+	if sqlx.IsQuoted(x, '"', '\'') {
+		if u, err := unquote(x); err == nil {
+			return strconv.Quote(u)
+		}
+	}
+	return normExpr(x)
+}
+
+// unquote returns the value of a single or double-quoted string literal.
+func unquote(s string) (string, error) {
+	if s[0] == '\'' {
+		s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// IsGeneratedIndexName reports if the index name was generated by the database.
+func (*diff) IsGeneratedIndexName(_ *schema.Table, _ *schema.Index) bool {
+	return false
+}
+
+// IndexAttrChanged reports if the index attributes were changed.
+func (*diff) IndexAttrChanged(_, _ []schema.Attr) bool {
+	return false // Not implemented.
+}
+
+// IndexPartAttrChanged reports if the index-part attributes were changed.
+func (*diff) IndexPartAttrChanged(_, _ *schema.Index, _ int) bool {
+	return false // Not implemented.
+}
+
+// ReferenceChanged reports if the foreign key referential action was changed.
+func (*diff) ReferenceChanged(_, _ schema.ReferenceOption) bool {
+	return false // Referential actions are not supported by BigQuery.
+}
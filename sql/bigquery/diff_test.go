@@ -0,0 +1,75 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_TableDiff(t *testing.T) {
+	type testcase struct {
+		name        string
+		from, to    *schema.Table
+		wantChanges []schema.Change
+		wantErr     bool
+	}
+	tests := []testcase{
+		{
+			name: "no changes",
+			from: schema.NewTable("users").AddAttrs(&PartitionBy{X: "DATE(`ts`)"}, &Option{K: "friendly_name", V: `"users"`}),
+			to:   schema.NewTable("users").AddAttrs(&PartitionBy{X: "date(ts)"}, &Option{K: "friendly_name", V: "'users'"}),
+		},
+		{
+			name: "table attributes",
+			from: schema.NewTable("users").AddAttrs(&PartitionBy{X: "DATE(ts)"}, &ClusterBy{Columns: []string{"id"}}, &Option{K: "require_partition_filter", V: "true"}),
+			to:   schema.NewTable("users").AddAttrs(&ClusterBy{Columns: []string{"id", "name"}}, &Option{K: "require_partition_filter", V: "false"}, &Option{K: "friendly_name", V: `"users"`}, &schema.Comment{Text: "users"}),
+			wantChanges: []schema.Change{
+				&schema.DropAttr{A: &PartitionBy{X: "DATE(ts)"}},
+				&schema.ModifyAttr{From: &ClusterBy{Columns: []string{"id"}}, To: &ClusterBy{Columns: []string{"id", "name"}}},
+				&schema.ModifyAttr{From: &Option{K: "require_partition_filter", V: "true"}, To: &Option{K: "require_partition_filter", V: "false"}},
+				&schema.AddAttr{A: &Option{K: "friendly_name", V: `"users"`}},
+				&schema.AddAttr{A: &schema.Comment{Text: "users"}},
+			},
+		},
+		func() testcase {
+			var (
+				from = schema.NewTable("users").
+					AddColumns(
+						schema.NewColumn("a").SetType(&schema.StringType{T: TypeString}),
+						schema.NewNullColumn("b").SetType(&schema.DecimalType{T: TypeNumeric, Precision: 10, Scale: 2}),
+						schema.NewNullColumn("c").SetType(&schema.StringType{T: TypeString}).SetDefault(&schema.Literal{V: `"x"`}),
+						schema.NewColumn("d").SetType(&ArrayType{T: TypeArray, Type: &schema.IntegerType{T: TypeInt64}}),
+					)
+				to = schema.NewTable("users").
+					AddColumns(
+						schema.NewNullColumn("a").SetType(&schema.StringType{T: TypeString}),
+						schema.NewNullColumn("b").SetType(&schema.DecimalType{T: TypeNumeric, Precision: 12, Scale: 2}),
+						schema.NewNullColumn("c").SetType(&schema.StringType{T: TypeString}).SetDefault(&schema.Literal{V: "'x'"}),
+						schema.NewNullColumn("d").SetType(&ArrayType{T: TypeArray, Type: &schema.IntegerType{T: TypeInt64}}),
+					)
+			)
+			return testcase{
+				name: "columns",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeNull},
+					&schema.ModifyColumn{From: from.Columns[1], To: to.Columns[1], Change: schema.ChangeType},
+				},
+			}
+		}(),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := DefaultDiff.TableDiff(tt.from, tt.to)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.EqualValues(t, tt.wantChanges, changes)
+		})
+	}
+}
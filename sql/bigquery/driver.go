@@ -0,0 +1,99 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+)
+
+type (
+	// Driver represents a BigQuery driver for introspecting database schemas,
+	// generating diff between schema elements and apply migrations changes.
+	Driver struct {
+		*conn
+		schema.Differ
+		schema.Inspector
+		migrate.PlanApplier
+	}
+
+	// database connection and its information.
+	conn struct {
+		schema.ExecQuerier
+	}
+)
+
+// DriverName holds the name used for registration.
+const DriverName = "bigquery"
+
+func init() {
+	sqlclient.Register(
+		DriverName,
+		sqlclient.DriverOpener(Open),
+		sqlclient.RegisterCodec(MarshalHCL, EvalHCL),
+		sqlclient.RegisterURLParser(parser{}),
+	)
+}
+
+// Open opens a new BigQuery driver. Note, BigQuery projects do not expose their
+// version, and therefore, no queries are executed on open.
+func Open(db schema.ExecQuerier) (migrate.Driver, error) {
+	c := &conn{ExecQuerier: db}
+	return &Driver{
+		conn:        c,
+		Differ:      &sqlx.Diff{DiffDriver: &diff{c}},
+		Inspector:   &inspect{c},
+		PlanApplier: &planApply{c},
+	}, nil
+}
+
+// CheckClean implements migrate.CleanChecker.
+func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error {
+	r, err := d.InspectRealm(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		switch n := len(s.Tables); {
+		case n > 1:
+			return &migrate.NotCleanError{Reason: fmt.Sprintf("found multiple tables in dataset %q: %d", s.Name, n)}
+		case n == 1 && (revT == nil || s.Tables[0].Name != revT.Name || revT.Schema != "" && s.Name != revT.Schema):
+			return &migrate.NotCleanError{Reason: fmt.Sprintf("found table %q in dataset %q", s.Tables[0].Name, s.Name)}
+		}
+	}
+	return nil
+}
+
+// Capabilities implements migrate.Capable. Column types can
+// only be widened, and are therefore not reported as modifiable.
+func (d *Driver) Capabilities() migrate.Capabilities {
+	return migrate.Capabilities{
+		DropColumn:   true,
+		RenameColumn: true,
+	}
+}
+
+type parser struct{}
+
+// ParseURL implements the sqlclient.URLParser interface. The host of
+// the URL is the project, and the (optional) path is the dataset.
+// For example: bigquery://project/dataset?location=US.
+func (parser) ParseURL(u *url.URL) *sqlclient.URL {
+	return &sqlclient.URL{URL: u, DSN: u.String(), Schema: strings.TrimPrefix(u.Path, "/")}
+}
+
+// ChangeSchema implements the sqlclient.SchemaChanger interface.
+func (parser) ChangeSchema(u *url.URL, s string) *url.URL {
+	nu := *u
+	nu.Path = "/" + s
+	return &nu
+}
@@ -0,0 +1,82 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_ParseURL(t *testing.T) {
+	u, err := url.Parse("bigquery://project/dataset?location=US")
+	require.NoError(t, err)
+	ac := parser{}.ParseURL(u)
+	require.Equal(t, "dataset", ac.Schema)
+	require.Equal(t, "bigquery://project/dataset?location=US", ac.DSN)
+
+	u = parser{}.ChangeSchema(u, "other")
+	require.Equal(t, "/other", u.Path)
+	require.Equal(t, "project", u.Host)
+	require.Equal(t, "location=US", u.RawQuery)
+
+	u, err = url.Parse("bigquery://project")
+	require.NoError(t, err)
+	require.Empty(t, parser{}.ParseURL(u).Schema)
+}
+
+func TestDriver_CheckClean(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	// Empty project.
+	m.ExpectQuery(sqltest.Escape(datasetsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}))
+	require.NoError(t, drv.(migrate.CleanChecker).CheckClean(context.Background(), nil))
+
+	// Revision table is ignored.
+	m.ExpectQuery(sqltest.Escape(datasetsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}).AddRow("test"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "`test`"))).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "ddl"}).AddRow("atlas_schema_revisions", "CREATE TABLE ..."))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tableOptionsQuery, "`test`", "?"))).
+		WithArgs("atlas_schema_revisions").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "option_name", "option_value"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "`test`", "?"))).
+		WithArgs("atlas_schema_revisions").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "column_name", "data_type", "is_nullable", "column_default", "clustering_ordinal_position", "description"}).
+			AddRow("atlas_schema_revisions", "version", "STRING", "NO", "NULL", nil, nil))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(primaryKeysQuery, "`test`", "?"))).
+		WithArgs("atlas_schema_revisions").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "constraint_name", "column_name"}))
+	require.NoError(t, drv.(migrate.CleanChecker).CheckClean(context.Background(), &migrate.TableIdent{Name: "atlas_schema_revisions"}))
+
+	// Dataset with tables.
+	m.ExpectQuery(sqltest.Escape(datasetsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}).AddRow("test"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "`test`"))).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "ddl"}).AddRow("users", "CREATE TABLE ..."))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tableOptionsQuery, "`test`", "?"))).
+		WithArgs("users").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "option_name", "option_value"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "`test`", "?"))).
+		WithArgs("users").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "column_name", "data_type", "is_nullable", "column_default", "clustering_ordinal_position", "description"}).
+			AddRow("users", "id", "INT64", "NO", "NULL", nil, nil))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(primaryKeysQuery, "`test`", "?"))).
+		WithArgs("users").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "constraint_name", "column_name"}))
+	err = drv.(migrate.CleanChecker).CheckClean(context.Background(), nil)
+	require.EqualError(t, err, `sql/migrate: connected database is not clean: found table "users" in dataset "test"`)
+}
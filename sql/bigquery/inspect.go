@@ -0,0 +1,396 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// A inspect provides a BigQuery implementation for schema.Inspector.
+type inspect struct{ *conn }
+
+var _ schema.Inspector = (*inspect)(nil)
+
+// InspectRealm returns schema descriptions of all datasets in the project.
+func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	schemas, err := i.datasets(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &schema.InspectRealmOption{}
+	}
+	r := schema.NewRealm(schemas...)
+	if len(schemas) == 0 || !sqlx.ModeInspectRealm(opts).Is(schema.InspectTables) {
+		return sqlx.FilterRealm(r, opts)
+	}
+	for _, s := range schemas {
+		if err := i.inspectTables(ctx, s, nil); err != nil {
+			return nil, err
+		}
+	}
+	sqlx.LinkSchemaTables(schemas)
+	return sqlx.FilterRealm(r, opts)
+}
+
+// InspectSchema returns schema descriptions of the tables in the given dataset.
+// Unlike other databases, BigQuery connections are not attached to a dataset,
+// and therefore, the dataset name is required.
+func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	if name == "" {
+		return nil, fmt.Errorf("bigquery: missing dataset name for inspection")
+	}
+	schemas, err := i.datasets(ctx, &schema.InspectRealmOption{
+		Schemas: []string{name},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch n := len(schemas); {
+	case n == 0:
+		return nil, &schema.NotExistError{Err: fmt.Errorf("bigquery: dataset %q was not found", name)}
+	case n > 1:
+		return nil, fmt.Errorf("bigquery: %d datasets were found for %q", n, name)
+	}
+	if opts == nil {
+		opts = &schema.InspectOptions{}
+	}
+	r := schema.NewRealm(schemas...)
+	if sqlx.ModeInspectSchema(opts).Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r.Schemas[0], opts); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.FilterSchema(r.Schemas[0], opts)
+}
+
+func (i *inspect) inspectTables(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	if err := i.tables(ctx, s, opts); err != nil {
+		return err
+	}
+	if len(s.Tables) == 0 {
+		return nil
+	}
+	if err := i.tableOptions(ctx, s); err != nil {
+		return err
+	}
+	if err := i.columns(ctx, s); err != nil {
+		return err
+	}
+	return i.primaryKeys(ctx, s)
+}
+
+// datasets returns the list of datasets in the project.
+func (i *inspect) datasets(ctx context.Context, opts *schema.InspectRealmOption) ([]*schema.Schema, error) {
+	var (
+		args  []any
+		query = datasetsQuery
+	)
+	if opts != nil && len(opts.Schemas) > 0 {
+		query = fmt.Sprintf(datasetsQueryArgs, nArgs(len(opts.Schemas)))
+		for _, s := range opts.Schemas {
+			args = append(args, s)
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery: querying datasets: %w", err)
+	}
+	names, err := sqlx.ScanStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery: scanning datasets: %w", err)
+	}
+	schemas := make([]*schema.Schema, 0, len(names))
+	for _, n := range names {
+		schemas = append(schemas, schema.New(n))
+	}
+	return schemas, nil
+}
+
+// tables queries and appends the tables of the given dataset.
+func (i *inspect) tables(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	var (
+		args  []any
+		query = fmt.Sprintf(tablesQuery, quote(s.Name))
+	)
+	if opts != nil && len(opts.Tables) > 0 {
+		for _, t := range opts.Tables {
+			args = append(args, t)
+		}
+		query = fmt.Sprintf(tablesQueryArgs, quote(s.Name), nArgs(len(opts.Tables)))
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("bigquery: querying dataset %q tables: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, ddl sql.NullString
+		if err := rows.Scan(&name, &ddl); err != nil {
+			return fmt.Errorf("bigquery: scanning table information: %w", err)
+		}
+		t := schema.NewTable(name.String)
+		s.AddTables(t)
+		if m := rePartition.FindStringSubmatch(ddl.String); len(m) == 2 {
+			t.AddAttrs(&PartitionBy{X: strings.TrimSpace(m[1])})
+		}
+	}
+	return rows.Close()
+}
+
+// tableOptions queries and appends the options of the tables in the given dataset.
+func (i *inspect) tableOptions(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(tableOptionsQuery, quote(s.Name), nArgs(len(s.Tables))), tableNames(s)...)
+	if err != nil {
+		return fmt.Errorf("bigquery: querying dataset %q table options: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, name, value sql.NullString
+		if err := rows.Scan(&table, &name, &value); err != nil {
+			return fmt.Errorf("bigquery: scanning table options: %w", err)
+		}
+		t, ok := s.Table(table.String)
+		if !ok {
+			return fmt.Errorf("bigquery: table %q was not found in dataset", table.String)
+		}
+		switch name.String {
+		case optDescription:
+			d, err := strconv.Unquote(value.String)
+			if err != nil {
+				return fmt.Errorf("bigquery: unquote description of table %q: %w", t.Name, err)
+			}
+			t.SetComment(d)
+		default:
+			t.AddAttrs(&Option{K: name.String, V: value.String})
+		}
+	}
+	return rows.Close()
+}
+
+// columns queries and appends the columns of the tables in the given dataset.
+func (i *inspect) columns(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, quote(s.Name), nArgs(len(s.Tables))), tableNames(s)...)
+	if err != nil {
+		return fmt.Errorf("bigquery: querying dataset %q columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	clustering := make(map[*schema.Table]map[int64]string)
+	for rows.Next() {
+		var (
+			table, name, typ, nullable, defaults, comment sql.NullString
+			cluster                                       sql.NullInt64
+		)
+		if err := rows.Scan(&table, &name, &typ, &nullable, &defaults, &cluster, &comment); err != nil {
+			return fmt.Errorf("bigquery: scanning columns: %w", err)
+		}
+		t, ok := s.Table(table.String)
+		if !ok {
+			return fmt.Errorf("bigquery: table %q was not found in dataset", table.String)
+		}
+		ct, err := ParseType(typ.String)
+		if err != nil {
+			return err
+		}
+		c := schema.NewColumn(name.String)
+		c.Type = &schema.ColumnType{Type: ct, Raw: typ.String, Null: nullable.String == "YES"}
+		// Default values are returned as "NULL" in case they are not set.
+		if sqlx.ValidString(defaults) && !strings.EqualFold(defaults.String, "NULL") {
+			c.Default = defaultExpr(defaults.String)
+		}
+		if sqlx.ValidString(comment) {
+			c.SetComment(comment.String)
+		}
+		t.AddColumns(c)
+		if cluster.Valid {
+			if clustering[t] == nil {
+				clustering[t] = make(map[int64]string)
+			}
+			clustering[t][cluster.Int64] = c.Name
+		}
+	}
+	for t, cs := range clustering {
+		pos := make([]int64, 0, len(cs))
+		for p := range cs {
+			pos = append(pos, p)
+		}
+		sort.Slice(pos, func(i, j int) bool { return pos[i] < pos[j] })
+		by := &ClusterBy{}
+		for _, p := range pos {
+			by.Columns = append(by.Columns, cs[p])
+		}
+		t.AddAttrs(by)
+	}
+	return rows.Close()
+}
+
+// primaryKeys queries and sets the (unenforced) primary keys of the tables in the given dataset.
+func (i *inspect) primaryKeys(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(primaryKeysQuery, quote(s.Name), nArgs(len(s.Tables))), tableNames(s)...)
+	if err != nil {
+		return fmt.Errorf("bigquery: querying dataset %q primary keys: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, name, column sql.NullString
+		if err := rows.Scan(&table, &name, &column); err != nil {
+			return fmt.Errorf("bigquery: scanning primary keys: %w", err)
+		}
+		t, ok := s.Table(table.String)
+		if !ok {
+			return fmt.Errorf("bigquery: table %q was not found in dataset", table.String)
+		}
+		c, ok := t.Column(column.String)
+		if !ok {
+			return fmt.Errorf("bigquery: column %q was not found for primary key %q", column.String, name.String)
+		}
+		if t.PrimaryKey == nil {
+			t.SetPrimaryKey(schema.NewPrimaryKey().SetName(name.String))
+		}
+		t.PrimaryKey.AddColumns(c)
+	}
+	return rows.Close()
+}
+
+// rePartition extracts the partitioning expression from the DDL of the table.
+var rePartition = regexp.MustCompile(`(?is)\)\s*PARTITION\s+BY\s+(.+?)\s*(?:CLUSTER\s+BY|OPTIONS\s*\(|;|$)`)
+
+// defaultExpr returns the schema.Expr of the given column default.
+func defaultExpr(x string) schema.Expr {
+	switch {
+	case sqlx.IsLiteralBool(x), sqlx.IsLiteralNumber(x), sqlx.IsQuoted(x, '"', '\''):
+		return &schema.Literal{V: x}
+	default:
+		return &schema.RawExpr{X: x}
+	}
+}
+
+// tableNames returns the names of the dataset tables as query arguments.
+func tableNames(s *schema.Schema) []any {
+	args := make([]any, len(s.Tables))
+	for i, t := range s.Tables {
+		args[i] = t.Name
+	}
+	return args
+}
+
+// nArgs returns the placeholders for n arguments.
+func nArgs(n int) string {
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+// quote returns the given identifier quoted with backticks.
+func quote(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "\\`") + "`"
+}
+
+// The name of the table option that holds its description.
+const optDescription = "description"
+
+type (
+	// PartitionBy describes the partitioning expression of the table.
+	// For example, DATE(created_at) or RANGE_BUCKET(id, GENERATE_ARRAY(0, 100, 10)).
+	PartitionBy struct {
+		schema.Attr
+		X string
+	}
+
+	// ClusterBy describes the clustering columns of the table.
+	ClusterBy struct {
+		schema.Attr
+		Columns []string
+	}
+
+	// Option describes a table option (e.g. expiration_timestamp) that is not modeled
+	// explicitly by Atlas. The value is kept as an SQL literal. e.g. TRUE or "value".
+	Option struct {
+		schema.Attr
+		K, V string
+	}
+
+	// ArrayType defines an ARRAY type. Columns in REPEATED mode are represented by this type.
+	ArrayType struct {
+		schema.Type        // Underlying element type.
+		T           string // ARRAY.
+	}
+
+	// StructType defines a STRUCT (RECORD) type, and its nested fields.
+	StructType struct {
+		schema.Type
+		T      string
+		Fields []*schema.Column
+	}
+
+	// IntervalType defines an INTERVAL type.
+	IntervalType struct {
+		schema.Type
+		T string
+	}
+)
+
+func init() {
+	schema.RegisterJSON(&PartitionBy{}, &ClusterBy{}, &Option{}, &StructType{}, &IntervalType{})
+}
+
+const (
+	// Query to list the datasets of the project. Datasets are listed from the default
+	// location of the connection, unless the connection configures a different one.
+	datasetsQuery     = "SELECT schema_name FROM INFORMATION_SCHEMA.SCHEMATA ORDER BY schema_name"
+	datasetsQueryArgs = "SELECT schema_name FROM INFORMATION_SCHEMA.SCHEMATA WHERE schema_name IN (%s) ORDER BY schema_name"
+
+	// Query to list the base tables of a dataset, and their DDL.
+	tablesQuery     = "SELECT table_name, ddl FROM %s.INFORMATION_SCHEMA.TABLES WHERE table_type = 'BASE TABLE' ORDER BY table_name"
+	tablesQueryArgs = "SELECT table_name, ddl FROM %s.INFORMATION_SCHEMA.TABLES WHERE table_type = 'BASE TABLE' AND table_name IN (%s) ORDER BY table_name"
+
+	// Query to list the options of the given tables.
+	tableOptionsQuery = "SELECT table_name, option_name, option_value FROM %s.INFORMATION_SCHEMA.TABLE_OPTIONS WHERE table_name IN (%s) ORDER BY table_name, option_name"
+
+	// Query to list the columns of the given tables, and their descriptions.
+	columnsQuery = `
+SELECT
+	c.table_name,
+	c.column_name,
+	c.data_type,
+	c.is_nullable,
+	c.column_default,
+	c.clustering_ordinal_position,
+	p.description
+FROM
+	%[1]s.INFORMATION_SCHEMA.COLUMNS AS c
+	LEFT JOIN %[1]s.INFORMATION_SCHEMA.COLUMN_FIELD_PATHS AS p
+	ON c.table_name = p.table_name AND c.column_name = p.field_path
+WHERE
+	c.table_name IN (%[2]s)
+ORDER BY
+	c.table_name, c.ordinal_position
+`
+
+	// Query to list the primary keys of the given tables.
+	primaryKeysQuery = `
+SELECT
+	k.table_name,
+	k.constraint_name,
+	k.column_name
+FROM
+	%[1]s.INFORMATION_SCHEMA.KEY_COLUMN_USAGE AS k
+	JOIN %[1]s.INFORMATION_SCHEMA.TABLE_CONSTRAINTS AS t
+	ON k.constraint_name = t.constraint_name AND k.table_name = t.table_name
+WHERE
+	t.constraint_type = 'PRIMARY KEY'
+	AND k.table_name IN (%[2]s)
+ORDER BY
+	k.table_name, k.ordinal_position
+`
+)
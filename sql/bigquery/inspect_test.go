@@ -0,0 +1,144 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectSchema(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	_, err = drv.InspectSchema(context.Background(), "", nil)
+	require.EqualError(t, err, "bigquery: missing dataset name for inspection")
+
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(datasetsQueryArgs, "?"))).
+		WithArgs("test").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}).AddRow("test"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "`test`"))).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "ddl"}).
+			AddRow("events", "CREATE TABLE `p.test.events`\n(\n  id INT64 NOT NULL\n)\nPARTITION BY DATE(ts)\nCLUSTER BY id, name\nOPTIONS(\n  description=\"events table\"\n);").
+			AddRow("logs", "CREATE TABLE `p.test.logs`\n(\n  msg STRING\n);"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tableOptionsQuery, "`test`", "?, ?"))).
+		WithArgs("events", "logs").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "option_name", "option_value"}).
+			AddRow("events", "description", `"events table"`).
+			AddRow("events", "require_partition_filter", "true"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "`test`", "?, ?"))).
+		WithArgs("events", "logs").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "column_name", "data_type", "is_nullable", "column_default", "clustering_ordinal_position", "description"}).
+			AddRow("events", "id", "INT64", "NO", "NULL", 1, nil).
+			AddRow("events", "name", "STRING(100)", "YES", `"unknown"`, 2, "event name").
+			AddRow("events", "ts", "TIMESTAMP", "NO", "CURRENT_TIMESTAMP()", nil, nil).
+			AddRow("events", "price", "NUMERIC(10, 2)", "YES", "NULL", nil, nil).
+			AddRow("events", "tags", "ARRAY<STRING>", "NO", "NULL", nil, nil).
+			AddRow("events", "meta", "STRUCT<a INT64, b ARRAY<STRUCT<c STRING NOT NULL>>>", "YES", "NULL", nil, nil).
+			AddRow("logs", "msg", "STRING", "YES", "NULL", nil, nil))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(primaryKeysQuery, "`test`", "?, ?"))).
+		WithArgs("events", "logs").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "constraint_name", "column_name"}).
+			AddRow("events", "events.pk$", "id"))
+	s, err := drv.InspectSchema(context.Background(), "test", nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 2)
+
+	events := s.Tables[0]
+	require.Equal(t, "events", events.Name)
+	require.Equal(t, []schema.Attr{
+		&PartitionBy{X: "DATE(ts)"},
+		&schema.Comment{Text: "events table"},
+		&Option{K: "require_partition_filter", V: "true"},
+		&ClusterBy{Columns: []string{"id", "name"}},
+	}, events.Attrs)
+	require.Len(t, events.Columns, 6)
+	require.Equal(t, &schema.ColumnType{Type: &schema.IntegerType{T: TypeInt64}, Raw: "INT64"}, events.Columns[0].Type)
+	require.Equal(t, &schema.ColumnType{Type: &schema.StringType{T: TypeString, Size: 100}, Raw: "STRING(100)", Null: true}, events.Columns[1].Type)
+	require.Equal(t, &schema.Literal{V: `"unknown"`}, events.Columns[1].Default)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "event name"}}, events.Columns[1].Attrs)
+	require.Equal(t, &schema.RawExpr{X: "CURRENT_TIMESTAMP()"}, events.Columns[2].Default)
+	require.Equal(t, &schema.DecimalType{T: TypeNumeric, Precision: 10, Scale: 2}, events.Columns[3].Type.Type)
+	require.Nil(t, events.Columns[3].Default)
+	require.Equal(t, &ArrayType{T: TypeArray, Type: &schema.StringType{T: TypeString}}, events.Columns[4].Type.Type)
+	require.Equal(t, &StructType{
+		T: TypeStruct,
+		Fields: []*schema.Column{
+			{Name: "a", Type: &schema.ColumnType{Type: &schema.IntegerType{T: TypeInt64}, Raw: "INT64", Null: true}},
+			{Name: "b", Type: &schema.ColumnType{
+				Type: &ArrayType{
+					T: TypeArray,
+					Type: &StructType{
+						T: TypeStruct,
+						Fields: []*schema.Column{
+							{Name: "c", Type: &schema.ColumnType{Type: &schema.StringType{T: TypeString}, Raw: "STRING"}},
+						},
+					},
+				},
+				Raw:  "ARRAY<STRUCT<c STRING NOT NULL>>",
+				Null: true,
+			}},
+		},
+	}, events.Columns[5].Type.Type)
+	require.NotNil(t, events.PrimaryKey)
+	require.Equal(t, "events.pk$", events.PrimaryKey.Name)
+	require.Equal(t, events.Columns[0], events.PrimaryKey.Parts[0].C)
+
+	logs := s.Tables[1]
+	require.Equal(t, "logs", logs.Name)
+	require.Empty(t, logs.Attrs)
+	require.Nil(t, logs.PrimaryKey)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestParseType(t *testing.T) {
+	for _, tt := range []struct {
+		raw  string
+		want schema.Type
+	}{
+		{raw: "INT64", want: &schema.IntegerType{T: TypeInt64}},
+		{raw: "INTEGER", want: &schema.IntegerType{T: TypeInt64}},
+		{raw: "FLOAT64", want: &schema.FloatType{T: TypeFloat64}},
+		{raw: "NUMERIC", want: &schema.DecimalType{T: TypeNumeric}},
+		{raw: "DECIMAL(10)", want: &schema.DecimalType{T: TypeNumeric, Precision: 10}},
+		{raw: "BIGNUMERIC(40, 5)", want: &schema.DecimalType{T: TypeBigNumeric, Precision: 40, Scale: 5}},
+		{raw: "BOOL", want: &schema.BoolType{T: TypeBool}},
+		{raw: "STRING", want: &schema.StringType{T: TypeString}},
+		{raw: "STRING(10)", want: &schema.StringType{T: TypeString, Size: 10}},
+		{raw: "BYTES", want: &schema.BinaryType{T: TypeBytes}},
+		{raw: "DATETIME", want: &schema.TimeType{T: TypeDateTime}},
+		{raw: "TIMESTAMP", want: &schema.TimeType{T: TypeTimestamp}},
+		{raw: "INTERVAL", want: &IntervalType{T: TypeInterval}},
+		{raw: "JSON", want: &schema.JSONType{T: TypeJSON}},
+		{raw: "GEOGRAPHY", want: &schema.SpatialType{T: TypeGeography}},
+		{raw: "ARRAY<INT64>", want: &ArrayType{T: TypeArray, Type: &schema.IntegerType{T: TypeInt64}}},
+		{raw: "RANGE<DATE>", want: &schema.UnsupportedType{T: "RANGE<DATE>"}},
+		{
+			raw: "STRUCT<name STRING OPTIONS(description=\"a, b\"), v NUMERIC(10, 2) NOT NULL>",
+			want: &StructType{
+				T: TypeStruct,
+				Fields: []*schema.Column{
+					{Name: "name", Type: &schema.ColumnType{Type: &schema.StringType{T: TypeString}, Raw: "STRING", Null: true}},
+					{Name: "v", Type: &schema.ColumnType{Type: &schema.DecimalType{T: TypeNumeric, Precision: 10, Scale: 2}, Raw: "NUMERIC(10, 2)"}},
+				},
+			},
+		},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			typ, err := ParseType(tt.raw)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, typ)
+		})
+	}
+}
@@ -0,0 +1,577 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultPlan provides basic planning capabilities for BigQuery.
+// Note, it is recommended to call Open, create a new Driver and use its
+// migrate.PlanApplier when a database connection is available.
+var DefaultPlan migrate.PlanApplier = &planApply{conn: &conn{ExecQuerier: sqlx.NoRows}}
+
+// A planApply provides migration capabilities for schema elements.
+type planApply struct{ *conn }
+
+// PlanChanges returns a migration plan for the given schema changes.
+func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	s := &state{
+		conn: p.conn,
+		Plan: migrate.Plan{
+			Name: name,
+			// BigQuery DDL statements are not transactional.
+			Transactional: false,
+		},
+	}
+	for _, o := range opts {
+		o(&s.PlanOptions)
+	}
+	if err := s.plan(changes); err != nil {
+		return nil, err
+	}
+	if err := sqlx.SetReversible(&s.Plan); err != nil {
+		return nil, err
+	}
+	sqlx.Annotate(&s.Plan, s.Annotations)
+	return &s.Plan, nil
+}
+
+// ApplyChanges applies the changes on the database. An error is returned
+// if the driver is unable to produce a plan to it, or one of the statements
+// is failed or unsupported.
+func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+}
+
+// state represents the state of a planning. It is not part of
+// planApply so that multiple planning/applying can be called
+// in parallel.
+type state struct {
+	*conn
+	migrate.Plan
+	migrate.PlanOptions
+}
+
+// plan builds the migration plan for applying the
+// given changes on the attached connection.
+func (s *state) plan(changes []schema.Change) error {
+	if s.SchemaQualifier != nil {
+		if err := sqlx.CheckChangesScope(s.PlanOptions, changes); err != nil {
+			return err
+		}
+	}
+	for _, c := range changes {
+		var err error
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			s.addSchema(c)
+		case *schema.DropSchema:
+			s.dropSchema(c)
+		case *schema.ModifySchema:
+			// No dataset attributes are supported by Atlas.
+		case *schema.AddTable:
+			err = s.addTable(c)
+		case *schema.DropTable:
+			err = s.dropTable(c)
+		case *schema.ModifyTable:
+			err = s.modifyTable(c)
+		case *schema.RenameTable:
+			s.renameTable(c)
+		default:
+			err = fmt.Errorf("unsupported change %T", c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSchema appends the migrate.Change for creating a dataset.
+func (s *state) addSchema(add *schema.AddSchema) {
+	b := s.Build("CREATE SCHEMA")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Ident(add.S.Name).String(),
+		Source:  add,
+		Reverse: s.Build("DROP SCHEMA").Ident(add.S.Name).String(),
+		Comment: fmt.Sprintf("add new schema named %q", add.S.Name),
+	})
+}
+
+// dropSchema appends the migrate.Change for dropping a dataset and its tables.
+func (s *state) dropSchema(drop *schema.DropSchema) {
+	b := s.Build("DROP SCHEMA")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Ident(drop.S.Name).P("CASCADE").String(),
+		Source:  drop,
+		Comment: fmt.Sprintf("drop schema named %q", drop.S.Name),
+	})
+}
+
+// addTable builds and appends a migration change
+// for creating a table in a dataset.
+func (s *state) addTable(add *schema.AddTable) error {
+	var (
+		errs []string
+		b    = s.Build("CREATE TABLE")
+	)
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	b.Table(add.T)
+	if len(add.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns", add.T.Name)
+	}
+	switch {
+	case len(add.T.Indexes) > 0:
+		return fmt.Errorf("create table %q: indexes are not supported", add.T.Name)
+	case len(add.T.ForeignKeys) > 0:
+		return fmt.Errorf("create table %q: foreign keys are not supported", add.T.Name)
+	}
+	b.WrapIndent(func(b *sqlx.Builder) {
+		b.MapIndent(add.T.Columns, func(i int, b *sqlx.Builder) {
+			if err := s.column(b, add.T.Columns[i]); err != nil {
+				errs = append(errs, err.Error())
+			}
+		})
+		if pk := add.T.PrimaryKey; pk != nil {
+			b.Comma().NL().P("PRIMARY KEY")
+			s.primaryKey(b, pk)
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
+	}
+	if p := (PartitionBy{}); sqlx.Has(add.T.Attrs, &p) {
+		b.P("PARTITION BY", p.X)
+	}
+	if c := (ClusterBy{}); sqlx.Has(add.T.Attrs, &c) {
+		b.P("CLUSTER BY")
+		b.MapComma(c.Columns, func(i int, b *sqlx.Builder) {
+			b.Ident(c.Columns[i])
+		})
+	}
+	var opts []string
+	if c := (schema.Comment{}); sqlx.Has(add.T.Attrs, &c) {
+		opts = append(opts, optDescription+"="+strconv.Quote(c.Text))
+	}
+	for _, a := range add.T.Attrs {
+		if o, ok := a.(*Option); ok {
+			opts = append(opts, o.K+"="+o.V)
+		}
+	}
+	if len(opts) > 0 {
+		b.P("OPTIONS(" + strings.Join(opts, ", ") + ")")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP TABLE").Table(add.T).String(),
+		Comment: fmt.Sprintf("create %q table", add.T.Name),
+	})
+	return nil
+}
+
+// dropTable builds and appends the migrate.Change
+// for dropping a table from a dataset.
+func (s *state) dropTable(drop *schema.DropTable) error {
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	if err := rs.addTable(&schema.AddTable{T: drop.T}); err != nil {
+		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
+	}
+	b := s.Build("DROP TABLE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Table(drop.T).String(),
+		Source:  drop,
+		Reverse: rs.Changes[0].Cmd,
+		Comment: fmt.Sprintf("drop %q table", drop.T.Name),
+	})
+	return nil
+}
+
+// modifyTable builds and appends the migration changes for bringing the table
+// into its modified state. BigQuery supports a limited set of ALTER actions, and
+// therefore, each change is planned as a separate statement. Changes that cannot
+// be undone using DDL (e.g. relaxing a REQUIRED column) are planned without a
+// reverse statement.
+func (s *state) modifyTable(modify *schema.ModifyTable) error {
+	if len(modify.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns; drop the table instead", modify.T.Name)
+	}
+	var err error
+	for _, change := range modify.Changes {
+		switch change := change.(type) {
+		case *schema.AddColumn:
+			err = s.addColumn(modify.T, change)
+		case *schema.DropColumn:
+			err = s.dropColumn(modify.T, change)
+		case *schema.RenameColumn:
+			s.alter(modify.T, change, func(b *sqlx.Builder) {
+				b.P("RENAME COLUMN").Ident(change.From.Name).P("TO").Ident(change.To.Name)
+			}, func(b *sqlx.Builder) {
+				b.P("RENAME COLUMN").Ident(change.To.Name).P("TO").Ident(change.From.Name)
+			})
+		case *schema.ModifyColumn:
+			err = s.modifyColumn(modify.T, change)
+		case *schema.AddAttr:
+			err = s.tableAttr(modify.T, change, change.A, nil)
+		case *schema.DropAttr:
+			err = s.tableAttr(modify.T, change, nil, change.A)
+		case *schema.ModifyAttr:
+			err = s.tableAttr(modify.T, change, change.To, change.From)
+		case *schema.AddPrimaryKey:
+			s.alter(modify.T, change, func(b *sqlx.Builder) {
+				b.P("ADD PRIMARY KEY")
+				s.primaryKey(b, change.P)
+			}, func(b *sqlx.Builder) {
+				b.P("DROP PRIMARY KEY")
+			})
+		case *schema.DropPrimaryKey:
+			s.alter(modify.T, change, func(b *sqlx.Builder) {
+				b.P("DROP PRIMARY KEY")
+			}, func(b *sqlx.Builder) {
+				b.P("ADD PRIMARY KEY")
+				s.primaryKey(b, change.P)
+			})
+		case *schema.ModifyPrimaryKey:
+			s.alter(modify.T, change, func(b *sqlx.Builder) {
+				b.P("DROP PRIMARY KEY,", "ADD PRIMARY KEY")
+				s.primaryKey(b, change.To)
+			}, func(b *sqlx.Builder) {
+				b.P("DROP PRIMARY KEY,", "ADD PRIMARY KEY")
+				s.primaryKey(b, change.From)
+			})
+		case *schema.AddIndex, *schema.DropIndex, *schema.ModifyIndex, *schema.RenameIndex:
+			return fmt.Errorf("alter table %q: indexes are not supported", modify.T.Name)
+		case *schema.AddForeignKey, *schema.DropForeignKey, *schema.ModifyForeignKey:
+			return fmt.Errorf("alter table %q: foreign keys are not supported", modify.T.Name)
+		case *schema.AddCheck, *schema.DropCheck, *schema.ModifyCheck:
+			return fmt.Errorf("alter table %q: check constraints are not supported", modify.T.Name)
+		default:
+			return fmt.Errorf("alter table %q: unsupported change %T", modify.T.Name, change)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn appends the migrate.Change for adding a column to a table.
+func (s *state) addColumn(t *schema.Table, add *schema.AddColumn) error {
+	if !add.C.Type.Null && !isArray(add.C) {
+		return fmt.Errorf("alter table %q: cannot add REQUIRED column %q to an existing table", t.Name, add.C.Name)
+	}
+	var err error
+	s.alter(t, add, func(b *sqlx.Builder) {
+		b.P("ADD COLUMN")
+		err = s.column(b, add.C)
+	}, func(b *sqlx.Builder) {
+		b.P("DROP COLUMN").Ident(add.C.Name)
+	})
+	if err != nil {
+		return fmt.Errorf("alter table %q: %w", t.Name, err)
+	}
+	return nil
+}
+
+// dropColumn appends the migrate.Change for dropping a column from a table.
+// REQUIRED columns cannot be added back to the table, and therefore, dropping
+// them is not reversible.
+func (s *state) dropColumn(t *schema.Table, drop *schema.DropColumn) error {
+	var (
+		err     error
+		reverse func(*sqlx.Builder)
+	)
+	if drop.C.Type.Null || isArray(drop.C) {
+		reverse = func(b *sqlx.Builder) {
+			b.P("ADD COLUMN")
+			err = s.column(b, drop.C)
+		}
+	}
+	s.alter(t, drop, func(b *sqlx.Builder) {
+		b.P("DROP COLUMN").Ident(drop.C.Name)
+	}, reverse)
+	if err != nil {
+		return fmt.Errorf("alter table %q: %w", t.Name, err)
+	}
+	return nil
+}
+
+// modifyColumn appends the migration changes for modifying a column. Each kind
+// of change is planned as a separate ALTER COLUMN statement.
+func (s *state) modifyColumn(t *schema.Table, modify *schema.ModifyColumn) error {
+	from, to := modify.From, modify.To
+	if modify.Change.Is(schema.ChangeNull) && !isArray(to) {
+		if !to.Type.Null {
+			return fmt.Errorf("alter table %q: cannot change column %q from NULLABLE to REQUIRED", t.Name, to.Name)
+		}
+		// REQUIRED columns can be relaxed, but not the opposite.
+		s.alter(t, modify, func(b *sqlx.Builder) {
+			b.P("ALTER COLUMN").Ident(to.Name).P("DROP NOT NULL")
+		}, nil)
+	}
+	if modify.Change.Is(schema.ChangeType) {
+		fromT, err := FormatType(from.Type.Type)
+		if err != nil {
+			return err
+		}
+		toT, err := FormatType(to.Type.Type)
+		if err != nil {
+			return err
+		}
+		if !coercible(from.Type.Type, to.Type.Type) {
+			return fmt.Errorf("alter table %q: cannot change the type of column %q from %s to %s", t.Name, to.Name, fromT, toT)
+		}
+		// Only widening type changes are supported, and
+		// therefore, they cannot be reverted using DDL.
+		s.alter(t, modify, func(b *sqlx.Builder) {
+			b.P("ALTER COLUMN").Ident(to.Name).P("SET DATA TYPE", toT)
+		}, nil)
+	}
+	if modify.Change.Is(schema.ChangeDefault) {
+		s.alter(t, modify, func(b *sqlx.Builder) {
+			s.columnDefault(b, to)
+		}, func(b *sqlx.Builder) {
+			s.columnDefault(b, from)
+		})
+	}
+	if modify.Change.Is(schema.ChangeComment) {
+		s.alter(t, modify, func(b *sqlx.Builder) {
+			b.P("ALTER COLUMN").Ident(to.Name).P("SET OPTIONS(" + optDescription + "=" + description(to.Attrs) + ")")
+		}, func(b *sqlx.Builder) {
+			b.P("ALTER COLUMN").Ident(from.Name).P("SET OPTIONS(" + optDescription + "=" + description(from.Attrs) + ")")
+		})
+	}
+	return nil
+}
+
+// columnDefault writes the ALTER COLUMN clause for setting or dropping the default value of a column.
+func (s *state) columnDefault(b *sqlx.Builder, c *schema.Column) {
+	b.P("ALTER COLUMN").Ident(c.Name)
+	if x, ok := defaultValue(c); ok {
+		b.P("SET DEFAULT", x)
+	} else {
+		b.P("DROP DEFAULT")
+	}
+}
+
+// tableAttr appends the migrate.Change for setting the table attribute "to",
+// or removing the attribute "from" in case "to" is nil.
+func (s *state) tableAttr(t *schema.Table, change schema.Change, to, from schema.Attr) error {
+	opt := func(a schema.Attr, set bool) (string, error) {
+		switch a := a.(type) {
+		case *schema.Comment:
+			if !set {
+				return optDescription + "=NULL", nil
+			}
+			return optDescription + "=" + strconv.Quote(a.Text), nil
+		case *Option:
+			if !set {
+				return a.K + "=NULL", nil
+			}
+			return a.K + "=" + a.V, nil
+		case *PartitionBy:
+			return "", fmt.Errorf("alter table %q: changing the partitioning requires recreating the table", t.Name)
+		case *ClusterBy:
+			return "", fmt.Errorf("alter table %q: changing the clustering requires recreating the table", t.Name)
+		default:
+			return "", fmt.Errorf("alter table %q: unsupported table attribute %T", t.Name, a)
+		}
+	}
+	var cmd, reverse string
+	switch {
+	case to != nil && from != nil:
+		x, err := opt(to, true)
+		if err != nil {
+			return err
+		}
+		r, err := opt(from, true)
+		if err != nil {
+			return err
+		}
+		cmd, reverse = x, r
+	case to != nil:
+		x, err := opt(to, true)
+		if err != nil {
+			return err
+		}
+		r, _ := opt(to, false)
+		cmd, reverse = x, r
+	default:
+		x, err := opt(from, false)
+		if err != nil {
+			return err
+		}
+		r, _ := opt(from, true)
+		cmd, reverse = x, r
+	}
+	s.alter(t, change, func(b *sqlx.Builder) {
+		b.P("SET OPTIONS(" + cmd + ")")
+	}, func(b *sqlx.Builder) {
+		b.P("SET OPTIONS(" + reverse + ")")
+	})
+	return nil
+}
+
+// alter appends an ALTER TABLE statement with the given action. The statement
+// is planned without a reverse in case the reverse action is nil.
+func (s *state) alter(t *schema.Table, change schema.Change, action, reverse func(*sqlx.Builder)) {
+	b := s.Build("ALTER TABLE").Table(t)
+	action(b)
+	c := &migrate.Change{
+		Cmd: b.String(),
+		Source: &schema.ModifyTable{
+			T:       t,
+			Changes: []schema.Change{change},
+		},
+		Comment: fmt.Sprintf("modify %q table", t.Name),
+	}
+	if reverse != nil {
+		b := s.Build("ALTER TABLE").Table(t)
+		reverse(b)
+		c.Reverse = b.String()
+	}
+	s.append(c)
+}
+
+func (s *state) renameTable(c *schema.RenameTable) {
+	s.append(&migrate.Change{
+		Source:  c,
+		Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
+		// The new name of the table cannot be qualified.
+		Cmd:     s.Build("ALTER TABLE").Table(c.From).P("RENAME TO").Ident(c.To.Name).String(),
+		Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Ident(c.From.Name).String(),
+	})
+}
+
+// column writes the column definition to the builder.
+func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
+	typ, err := FormatType(c.Type.Type)
+	if err != nil {
+		return fmt.Errorf("format type for column %q: %w", c.Name, err)
+	}
+	b.Ident(c.Name).P(typ)
+	// ARRAY columns (REPEATED mode) cannot be nullable.
+	if !c.Type.Null && !isArray(c) {
+		b.P("NOT NULL")
+	}
+	if x, ok := defaultValue(c); ok {
+		b.P("DEFAULT", x)
+	}
+	if x := (schema.Comment{}); sqlx.Has(c.Attrs, &x) {
+		b.P("OPTIONS(" + optDescription + "=" + strconv.Quote(x.Text) + ")")
+	}
+	return nil
+}
+
+// primaryKey writes the (unenforced) primary key columns to the builder.
+func (s *state) primaryKey(b *sqlx.Builder, pk *schema.Index) {
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(pk.Parts, func(i int, b *sqlx.Builder) {
+			if c := pk.Parts[i].C; c != nil {
+				b.Ident(c.Name)
+			}
+		})
+	})
+	// BigQuery does not enforce primary keys.
+	b.P("NOT ENFORCED")
+}
+
+func (s *state) append(c *migrate.Change) {
+	s.Changes = append(s.Changes, c)
+}
+
+// Build instantiates a new builder and writes the given phrase to it.
+func (s *state) Build(phrases ...string) *sqlx.Builder {
+	b := &sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`', Schema: s.SchemaQualifier, Indent: s.Indent}
+	return b.P(phrases...)
+}
+
+// defaultValue returns the SQL form of the column default value.
+func defaultValue(c *schema.Column) (string, bool) {
+	switch x := schema.UnderlyingExpr(c.Default).(type) {
+	case *schema.Literal:
+		v := x.V
+		switch c.Type.Type.(type) {
+		case *schema.StringType, *schema.TimeType:
+			if !sqlx.IsQuoted(v, '"', '\'') {
+				v = strconv.Quote(v)
+			}
+		}
+		return v, true
+	case *schema.RawExpr:
+		return x.X, true
+	}
+	return "", false
+}
+
+// description returns the column description as an SQL string, or NULL.
+func description(attrs []schema.Attr) string {
+	if c := (schema.Comment{}); sqlx.Has(attrs, &c) && c.Text != "" {
+		return strconv.Quote(c.Text)
+	}
+	return "NULL"
+}
+
+// isArray reports if the column is an ARRAY (REPEATED) column.
+func isArray(c *schema.Column) bool {
+	_, ok := c.Type.Type.(*ArrayType)
+	return ok
+}
+
+// coercible reports if the type "from" can be coerced to the type "to"
+// using ALTER COLUMN SET DATA TYPE. BigQuery supports only widening
+// coercions, such as INT64 to NUMERIC or STRING(10) to STRING.
+func coercible(from, to schema.Type) bool {
+	switch from := from.(type) {
+	case *schema.IntegerType:
+		switch to.(type) {
+		case *schema.DecimalType, *schema.FloatType:
+			return true
+		}
+	case *schema.DecimalType:
+		switch to := to.(type) {
+		case *schema.FloatType:
+			return true
+		case *schema.DecimalType:
+			switch {
+			case strings.EqualFold(from.T, TypeBigNumeric) && !strings.EqualFold(to.T, TypeBigNumeric):
+				return false
+			case to.Precision == 0:
+				return true
+			case from.Precision == 0:
+				return false
+			}
+			return to.Scale >= from.Scale && to.Precision-to.Scale >= from.Precision-from.Scale
+		}
+	case *schema.StringType:
+		if to, ok := to.(*schema.StringType); ok {
+			return to.Size == 0 || from.Size > 0 && to.Size >= from.Size
+		}
+	case *schema.BinaryType:
+		if to, ok := to.(*schema.BinaryType); ok {
+			return to.Size == nil || *to.Size == 0 || from.Size != nil && *from.Size > 0 && *to.Size >= *from.Size
+		}
+	}
+	return false
+}
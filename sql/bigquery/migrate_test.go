@@ -0,0 +1,276 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanChanges(t *testing.T) {
+	users := &schema.Table{
+		Name:   "users",
+		Schema: schema.New("test"),
+		Columns: []*schema.Column{
+			{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: TypeInt64}}},
+			{Name: "name", Type: &schema.ColumnType{Type: &schema.StringType{T: TypeString}, Null: true}, Default: &schema.Literal{V: "unknown"}, Attrs: []schema.Attr{&schema.Comment{Text: "user's name"}}},
+			{Name: "created", Type: &schema.ColumnType{Type: &schema.TimeType{T: TypeTimestamp}}, Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP()"}},
+			{Name: "tags", Type: &schema.ColumnType{Type: &ArrayType{T: TypeArray, Type: &schema.StringType{T: TypeString}}}},
+			{Name: "address", Type: &schema.ColumnType{Type: &StructType{T: TypeStruct, Fields: []*schema.Column{
+				{Name: "city", Type: &schema.ColumnType{Type: &schema.StringType{T: TypeString}}},
+				{Name: "zip", Type: &schema.ColumnType{Type: &schema.StringType{T: TypeString}, Null: true}},
+			}}, Null: true}},
+		},
+		Attrs: []schema.Attr{
+			&PartitionBy{X: "DATE(created)"},
+			&ClusterBy{Columns: []string{"id", "name"}},
+			&schema.Comment{Text: "users table"},
+			&Option{K: "require_partition_filter", V: "true"},
+		},
+	}
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	tests := []struct {
+		name    string
+		changes []schema.Change
+		plan    *migrate.Plan
+		wantErr string
+	}{
+		{
+			name: "add schema",
+			changes: []schema.Change{
+				&schema.AddSchema{S: schema.New("test"), Extra: []schema.Clause{&schema.IfNotExists{}}},
+				&schema.DropSchema{S: schema.New("old")},
+			},
+			plan: &migrate.Plan{
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE SCHEMA IF NOT EXISTS `test`", Reverse: "DROP SCHEMA `test`"},
+					{Cmd: "DROP SCHEMA `old` CASCADE"},
+				},
+			},
+		},
+		{
+			name:    "create table",
+			changes: []schema.Change{&schema.AddTable{T: users}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE TABLE `test`.`users` (`id` INT64 NOT NULL, `name` STRING DEFAULT \"unknown\" OPTIONS(description=\"user's name\"), `created` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP(), `tags` ARRAY<STRING>, `address` STRUCT<city STRING NOT NULL, zip STRING>, PRIMARY KEY (`id`) NOT ENFORCED) PARTITION BY DATE(created) CLUSTER BY `id`, `name` OPTIONS(description=\"users table\", require_partition_filter=true)",
+						Reverse: "DROP TABLE `test`.`users`",
+					},
+				},
+			},
+		},
+		{
+			name:    "drop table",
+			changes: []schema.Change{&schema.DropTable{T: schema.NewTable("t").SetSchema(schema.New("test")).AddColumns(schema.NewNullColumn("a").SetType(&schema.BoolType{T: TypeBool}))}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: "DROP TABLE `test`.`t`", Reverse: "CREATE TABLE `test`.`t` (`a` BOOL)"},
+				},
+			},
+		},
+		{
+			name:    "rename table",
+			changes: []schema.Change{&schema.RenameTable{From: users, To: schema.NewTable("members").SetSchema(users.Schema)}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: "ALTER TABLE `test`.`users` RENAME TO `members`", Reverse: "ALTER TABLE `test`.`members` RENAME TO `users`"},
+				},
+			},
+		},
+		{
+			name: "add and drop columns",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewNullColumn("age").SetType(&schema.IntegerType{T: TypeInt64})},
+						&schema.DropColumn{C: users.Columns[1]},
+						&schema.RenameColumn{From: schema.NewColumn("a"), To: schema.NewColumn("b")},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: "ALTER TABLE `test`.`users` ADD COLUMN `age` INT64", Reverse: "ALTER TABLE `test`.`users` DROP COLUMN `age`"},
+					{Cmd: "ALTER TABLE `test`.`users` DROP COLUMN `name`", Reverse: "ALTER TABLE `test`.`users` ADD COLUMN `name` STRING DEFAULT \"unknown\" OPTIONS(description=\"user's name\")"},
+					{Cmd: "ALTER TABLE `test`.`users` RENAME COLUMN `a` TO `b`", Reverse: "ALTER TABLE `test`.`users` RENAME COLUMN `b` TO `a`"},
+				},
+			},
+		},
+		{
+			name: "drop required column",
+			changes: []schema.Change{
+				&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.DropColumn{C: users.Columns[2]}}},
+			},
+			plan: &migrate.Plan{
+				Changes: []*migrate.Change{
+					{Cmd: "ALTER TABLE `test`.`users` DROP COLUMN `created`"},
+				},
+			},
+		},
+		{
+			name: "modify columns",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   schema.NewColumn("c").SetType(&schema.IntegerType{T: TypeInt64}),
+							To:     schema.NewNullColumn("c").SetType(&schema.DecimalType{T: TypeNumeric}),
+							Change: schema.ChangeNull | schema.ChangeType,
+						},
+						&schema.ModifyColumn{
+							From:   schema.NewNullColumn("d").SetType(&schema.StringType{T: TypeString}).SetComment("d"),
+							To:     schema.NewNullColumn("d").SetType(&schema.StringType{T: TypeString}).SetDefault(&schema.Literal{V: "'x'"}),
+							Change: schema.ChangeDefault | schema.ChangeComment,
+						},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Changes: []*migrate.Change{
+					{Cmd: "ALTER TABLE `test`.`users` ALTER COLUMN `c` DROP NOT NULL"},
+					{Cmd: "ALTER TABLE `test`.`users` ALTER COLUMN `c` SET DATA TYPE NUMERIC"},
+					{Cmd: "ALTER TABLE `test`.`users` ALTER COLUMN `d` SET DEFAULT 'x'", Reverse: "ALTER TABLE `test`.`users` ALTER COLUMN `d` DROP DEFAULT"},
+					{Cmd: "ALTER TABLE `test`.`users` ALTER COLUMN `d` SET OPTIONS(description=NULL)", Reverse: "ALTER TABLE `test`.`users` ALTER COLUMN `d` SET OPTIONS(description=\"d\")"},
+				},
+			},
+		},
+		{
+			name: "table options",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyAttr{From: &schema.Comment{Text: "users table"}, To: &schema.Comment{Text: "users"}},
+						&schema.AddAttr{A: &Option{K: "labels", V: `[("org", "dev")]`}},
+						&schema.DropAttr{A: &Option{K: "require_partition_filter", V: "true"}},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: "ALTER TABLE `test`.`users` SET OPTIONS(description=\"users\")", Reverse: "ALTER TABLE `test`.`users` SET OPTIONS(description=\"users table\")"},
+					{Cmd: "ALTER TABLE `test`.`users` SET OPTIONS(labels=[(\"org\", \"dev\")])", Reverse: "ALTER TABLE `test`.`users` SET OPTIONS(labels=NULL)"},
+					{Cmd: "ALTER TABLE `test`.`users` SET OPTIONS(require_partition_filter=NULL)", Reverse: "ALTER TABLE `test`.`users` SET OPTIONS(require_partition_filter=true)"},
+				},
+			},
+		},
+		{
+			name: "primary key",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.DropPrimaryKey{P: users.PrimaryKey},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: "ALTER TABLE `test`.`users` DROP PRIMARY KEY", Reverse: "ALTER TABLE `test`.`users` ADD PRIMARY KEY (`id`) NOT ENFORCED"},
+				},
+			},
+		},
+		{
+			name: "add required column",
+			changes: []schema.Change{
+				&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.AddColumn{C: schema.NewIntColumn("a", TypeInt64)}}},
+			},
+			wantErr: `alter table "users": cannot add REQUIRED column "a" to an existing table`,
+		},
+		{
+			name: "set not null",
+			changes: []schema.Change{
+				&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.ModifyColumn{
+					From:   schema.NewNullIntColumn("a", TypeInt64),
+					To:     schema.NewIntColumn("a", TypeInt64),
+					Change: schema.ChangeNull,
+				}}},
+			},
+			wantErr: `alter table "users": cannot change column "a" from NULLABLE to REQUIRED`,
+		},
+		{
+			name: "narrow type",
+			changes: []schema.Change{
+				&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.ModifyColumn{
+					From:   schema.NewNullColumn("a").SetType(&schema.StringType{T: TypeString}),
+					To:     schema.NewNullColumn("a").SetType(&schema.StringType{T: TypeString, Size: 10}),
+					Change: schema.ChangeType,
+				}}},
+			},
+			wantErr: `alter table "users": cannot change the type of column "a" from STRING to STRING(10)`,
+		},
+		{
+			name: "partitioning",
+			changes: []schema.Change{
+				&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.ModifyAttr{From: &PartitionBy{X: "DATE(created)"}, To: &PartitionBy{X: "DATE_TRUNC(created, MONTH)"}}}},
+			},
+			wantErr: `alter table "users": changing the partitioning requires recreating the table`,
+		},
+		{
+			name: "clustering",
+			changes: []schema.Change{
+				&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.DropAttr{A: &ClusterBy{Columns: []string{"id"}}}}},
+			},
+			wantErr: `alter table "users": changing the clustering requires recreating the table`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", tt.changes)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, plan.Transactional)
+			require.Equal(t, tt.plan.Reversible, plan.Reversible)
+			require.Len(t, plan.Changes, len(tt.plan.Changes))
+			for i, c := range plan.Changes {
+				require.Equal(t, tt.plan.Changes[i].Cmd, c.Cmd)
+				require.Equal(t, tt.plan.Changes[i].Reverse, c.Reverse)
+			}
+		})
+	}
+}
+
+func TestCoercible(t *testing.T) {
+	p := func(n int) *int { return &n }
+	for _, tt := range []struct {
+		from, to schema.Type
+		want     bool
+	}{
+		{from: &schema.IntegerType{T: TypeInt64}, to: &schema.DecimalType{T: TypeNumeric}, want: true},
+		{from: &schema.IntegerType{T: TypeInt64}, to: &schema.FloatType{T: TypeFloat64}, want: true},
+		{from: &schema.FloatType{T: TypeFloat64}, to: &schema.IntegerType{T: TypeInt64}},
+		{from: &schema.DecimalType{T: TypeNumeric, Precision: 10, Scale: 2}, to: &schema.DecimalType{T: TypeNumeric, Precision: 12, Scale: 4}, want: true},
+		{from: &schema.DecimalType{T: TypeNumeric, Precision: 10, Scale: 2}, to: &schema.DecimalType{T: TypeNumeric, Precision: 10, Scale: 4}},
+		{from: &schema.DecimalType{T: TypeBigNumeric}, to: &schema.DecimalType{T: TypeNumeric}},
+		{from: &schema.StringType{T: TypeString, Size: 10}, to: &schema.StringType{T: TypeString}, want: true},
+		{from: &schema.BinaryType{T: TypeBytes, Size: p(10)}, to: &schema.BinaryType{T: TypeBytes, Size: p(5)}},
+	} {
+		require.Equal(t, tt.want, coercible(tt.from, tt.to))
+	}
+}
+
+func TestDefaultPlan(t *testing.T) {
+	err := DefaultPlan.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t1").AddColumns(schema.NewNullIntColumn("a", TypeInt64))},
+	})
+	require.EqualError(t, err, `create "t1" table: cannot execute statements without a database connection. use Open to create a new Driver`)
+}
@@ -0,0 +1,124 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package bigquery
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSpec(t *testing.T) {
+	f := `
+schema "test" {
+}
+
+table "events" {
+  schema = schema.test
+  column "id" {
+    type = INT64
+  }
+  column "ts" {
+    type    = TIMESTAMP
+    default = sql("CURRENT_TIMESTAMP()")
+  }
+  column "name" {
+    null    = true
+    type    = STRING(100)
+    comment = "event name"
+  }
+  column "price" {
+    null = true
+    type = NUMERIC(10,2)
+  }
+  column "tags" {
+    type = sql("ARRAY<STRING>")
+  }
+  column "meta" {
+    null = true
+    type = sql("STRUCT<a INT64, b STRING NOT NULL>")
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  partition_by = "DATE(ts)"
+  cluster_by   = ["id", "name"]
+  comment      = "events table"
+  option "require_partition_filter" {
+    value = "true"
+  }
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	tbl, ok := s.Table("events")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{
+		&schema.Comment{Text: "events table"},
+		&PartitionBy{X: "DATE(ts)"},
+		&ClusterBy{Columns: []string{"id", "name"}},
+		&Option{K: "require_partition_filter", V: "true"},
+	}, tbl.Attrs)
+	require.Equal(t, &schema.IntegerType{T: TypeInt64}, tbl.Columns[0].Type.Type)
+	require.Equal(t, &schema.RawExpr{X: "CURRENT_TIMESTAMP()"}, tbl.Columns[1].Default)
+	require.Equal(t, &schema.StringType{T: TypeString, Size: 100}, tbl.Columns[2].Type.Type)
+	require.Equal(t, &schema.DecimalType{T: TypeNumeric, Precision: 10, Scale: 2}, tbl.Columns[3].Type.Type)
+	require.Equal(t, &ArrayType{T: TypeArray, Type: &schema.StringType{T: TypeString}}, tbl.Columns[4].Type.Type)
+	require.IsType(t, &StructType{}, tbl.Columns[5].Type.Type)
+	require.Len(t, tbl.Columns[5].Type.Type.(*StructType).Fields, 2)
+	require.NotNil(t, tbl.PrimaryKey)
+
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, `table "events" {
+  schema       = schema.test
+  comment      = "events table"
+  partition_by = "DATE(ts)"
+  cluster_by   = ["id", "name"]
+  column "id" {
+    null = false
+    type = INT64
+  }
+  column "ts" {
+    null    = false
+    type    = TIMESTAMP
+    default = sql("CURRENT_TIMESTAMP()")
+  }
+  column "name" {
+    null    = true
+    type    = STRING(100)
+    comment = "event name"
+  }
+  column "price" {
+    null = true
+    type = NUMERIC(10,2)
+  }
+  column "tags" {
+    null = false
+    type = sql("ARRAY<STRING>")
+  }
+  column "meta" {
+    null = true
+    type = sql("STRUCT<a INT64, b STRING NOT NULL>")
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  option "require_partition_filter" {
+    value = "true"
+  }
+}
+schema "test" {
+}
+`, string(buf))
+
+	var s2 schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &s2, nil))
+	changes, err := DefaultDiff.SchemaDiff(&s, &s2)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
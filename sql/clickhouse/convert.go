@@ -0,0 +1,314 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// ClickHouse data types, as defined in its documentation.
+// https://clickhouse.com/docs/en/sql-reference/data-types
+const (
+	TypeInt8        = "Int8"
+	TypeInt16       = "Int16"
+	TypeInt32       = "Int32"
+	TypeInt64       = "Int64"
+	TypeInt128      = "Int128"
+	TypeInt256      = "Int256"
+	TypeUInt8       = "UInt8"
+	TypeUInt16      = "UInt16"
+	TypeUInt32      = "UInt32"
+	TypeUInt64      = "UInt64"
+	TypeUInt128     = "UInt128"
+	TypeUInt256     = "UInt256"
+	TypeFloat32     = "Float32"
+	TypeFloat64     = "Float64"
+	TypeDecimal     = "Decimal"
+	TypeBool        = "Bool"
+	TypeString      = "String"
+	TypeFixedString = "FixedString"
+	TypeUUID        = "UUID"
+	TypeDate        = "Date"
+	TypeDate32      = "Date32"
+	TypeDateTime    = "DateTime"
+	TypeDateTime64  = "DateTime64"
+	TypeEnum8       = "Enum8"
+	TypeEnum16      = "Enum16"
+	TypeJSON        = "JSON"
+
+	// Type modifiers.
+	typeNullable       = "Nullable"
+	typeLowCardinality = "LowCardinality"
+)
+
+// FormatType converts schema type to its column form in the database.
+// Note, the Nullable and LowCardinality modifiers are not part of the
+// type, but set by the column definition. See columnType for more info.
+func FormatType(t schema.Type) (string, error) {
+	var f string
+	switch t := t.(type) {
+	case *schema.BoolType:
+		f = TypeBool
+	case *schema.IntegerType:
+		f = t.T
+		if f == "" {
+			f = TypeInt64
+			if t.Unsigned {
+				f = TypeUInt64
+			}
+		}
+	case *schema.FloatType:
+		f = t.T
+		if f == "" {
+			f = TypeFloat64
+		}
+	case *schema.DecimalType:
+		if t.Precision == 0 {
+			return "", fmt.Errorf("clickhouse: missing precision for decimal type")
+		}
+		f = fmt.Sprintf("%s(%d, %d)", TypeDecimal, t.Precision, t.Scale)
+	case *schema.StringType:
+		f = TypeString
+		if t.T == TypeFixedString {
+			if t.Size <= 0 {
+				return "", fmt.Errorf("clickhouse: invalid size %d for FixedString type", t.Size)
+			}
+			f = fmt.Sprintf("%s(%d)", TypeFixedString, t.Size)
+		}
+	case *schema.UUIDType:
+		f = TypeUUID
+	case *schema.TimeType:
+		f = t.T
+		if t.T == TypeDateTime64 {
+			p := 3
+			if t.Precision != nil {
+				p = *t.Precision
+			}
+			f = fmt.Sprintf("%s(%d)", TypeDateTime64, p)
+		}
+	case *schema.EnumType:
+		if len(t.Values) == 0 {
+			return "", fmt.Errorf("clickhouse: missing values for enum type")
+		}
+		f = t.T
+		if f == "" {
+			f = TypeEnum8
+		}
+		vs := make([]string, len(t.Values))
+		for i, v := range t.Values {
+			q, err := sqlx.SingleQuote(v)
+			if err != nil {
+				return "", err
+			}
+			vs[i] = fmt.Sprintf("%s = %d", q, i+1)
+		}
+		f = fmt.Sprintf("%s(%s)", f, strings.Join(vs, ", "))
+	case *schema.JSONType:
+		f = t.T
+		if f == "" {
+			f = TypeJSON
+		}
+	case *schema.UnsupportedType:
+		f = t.T
+	default:
+		return "", fmt.Errorf("clickhouse: unexpected type: %T", t)
+	}
+	return f, nil
+}
+
+// ParseType returns the schema.Type value represented by the given raw type.
+// Types that are not modeled by Atlas (e.g. Array, Map or Tuple) are returned
+// as schema.UnsupportedType, and are kept as-is.
+func ParseType(raw string) (schema.Type, error) {
+	name, args, err := typeArgs(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case TypeInt8, TypeInt16, TypeInt32, TypeInt64, TypeInt128, TypeInt256:
+		return &schema.IntegerType{T: name}, nil
+	case TypeUInt8, TypeUInt16, TypeUInt32, TypeUInt64, TypeUInt128, TypeUInt256:
+		return &schema.IntegerType{T: name, Unsigned: true}, nil
+	case TypeFloat32, TypeFloat64:
+		return &schema.FloatType{T: name}, nil
+	case TypeBool:
+		return &schema.BoolType{T: name}, nil
+	case TypeString:
+		return &schema.StringType{T: name}, nil
+	case TypeFixedString:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("clickhouse: unexpected FixedString type: %q", raw)
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("clickhouse: parse FixedString size %q: %w", args[0], err)
+		}
+		return &schema.StringType{T: name, Size: n}, nil
+	case TypeUUID:
+		return &schema.UUIDType{T: name}, nil
+	case TypeDecimal, "Decimal32", "Decimal64", "Decimal128", "Decimal256":
+		return parseDecimal(raw, name, args)
+	case TypeDate, TypeDate32:
+		return &schema.TimeType{T: name}, nil
+	case TypeDateTime, TypeDateTime64:
+		// Timezones are not modeled by Atlas, and
+		// therefore, such types are kept as-is.
+		if name == TypeDateTime && len(args) > 0 || len(args) > 1 {
+			return &schema.UnsupportedType{T: raw}, nil
+		}
+		t := &schema.TimeType{T: name}
+		if name == TypeDateTime64 {
+			p := 3
+			if len(args) == 1 {
+				if p, err = strconv.Atoi(args[0]); err != nil {
+					return nil, fmt.Errorf("clickhouse: parse DateTime64 precision %q: %w", args[0], err)
+				}
+			}
+			t.Precision = &p
+		}
+		return t, nil
+	case TypeEnum8, TypeEnum16:
+		return parseEnum(raw, name, args)
+	case TypeJSON:
+		return &schema.JSONType{T: name}, nil
+	default:
+		return &schema.UnsupportedType{T: raw}, nil
+	}
+}
+
+// parseDecimal parses the Decimal(P, S) and DecimalN(S) types to their common form.
+func parseDecimal(raw, name string, args []string) (schema.Type, error) {
+	var (
+		p, s int
+		err  error
+	)
+	switch name {
+	case TypeDecimal:
+		if len(args) == 0 || len(args) > 2 {
+			return nil, fmt.Errorf("clickhouse: unexpected decimal type: %q", raw)
+		}
+		if p, err = strconv.Atoi(args[0]); err != nil {
+			return nil, fmt.Errorf("clickhouse: parse decimal precision %q: %w", args[0], err)
+		}
+		if len(args) == 2 {
+			if s, err = strconv.Atoi(args[1]); err != nil {
+				return nil, fmt.Errorf("clickhouse: parse decimal scale %q: %w", args[1], err)
+			}
+		}
+	default:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("clickhouse: unexpected decimal type: %q", raw)
+		}
+		if s, err = strconv.Atoi(args[0]); err != nil {
+			return nil, fmt.Errorf("clickhouse: parse decimal scale %q: %w", args[0], err)
+		}
+		p = map[string]int{"Decimal32": 9, "Decimal64": 18, "Decimal128": 38, "Decimal256": 76}[name]
+	}
+	return &schema.DecimalType{T: TypeDecimal, Precision: p, Scale: s}, nil
+}
+
+// parseEnum parses Enum8 and Enum16 types. Enums with values that are not
+// numbered sequentially (from 1) are kept as-is, as Atlas does not model
+// the numeric values of enums.
+func parseEnum(raw, name string, args []string) (schema.Type, error) {
+	e := &schema.EnumType{T: name}
+	for i, a := range args {
+		v, n := a, ""
+		if j := strings.LastIndexByte(a, '='); j > 0 && sqlx.IsQuoted(strings.TrimSpace(a[:j]), '\'') {
+			v, n = strings.TrimSpace(a[:j]), strings.TrimSpace(a[j+1:])
+		}
+		if n != "" && n != strconv.Itoa(i+1) {
+			return &schema.UnsupportedType{T: raw}, nil
+		}
+		u, err := sqlx.Unquote(v)
+		if err != nil {
+			return nil, fmt.Errorf("clickhouse: parse enum value %q: %w", v, err)
+		}
+		e.Values = append(e.Values, u)
+	}
+	return e, nil
+}
+
+// parseColumnType parses the raw type of column, and returns its
+// schema.ColumnType, and if it is defined as LowCardinality.
+func parseColumnType(raw string) (*schema.ColumnType, bool, error) {
+	var (
+		lc bool
+		s  = strings.TrimSpace(raw)
+		ct = &schema.ColumnType{Raw: raw}
+	)
+	if inner, ok := unwrapType(s, typeLowCardinality); ok {
+		s, lc = inner, true
+	}
+	if inner, ok := unwrapType(s, typeNullable); ok {
+		s, ct.Null = inner, true
+	}
+	t, err := ParseType(s)
+	if err != nil {
+		return nil, false, err
+	}
+	ct.Type = t
+	return ct, lc, nil
+}
+
+// columnType returns the type of the column in its database form,
+// with its Nullable and LowCardinality modifiers.
+func columnType(c *schema.Column) (string, error) {
+	f, err := FormatType(c.Type.Type)
+	if err != nil {
+		return "", err
+	}
+	if c.Type.Null {
+		f = fmt.Sprintf("%s(%s)", typeNullable, f)
+	}
+	if sqlx.Has(c.Attrs, &LowCardinality{}) {
+		f = fmt.Sprintf("%s(%s)", typeLowCardinality, f)
+	}
+	return f, nil
+}
+
+// unwrapType returns the inner type of the given modifier. e.g. Nullable(String).
+func unwrapType(s, modifier string) (string, bool) {
+	if !strings.HasPrefix(s, modifier+"(") || !strings.HasSuffix(s, ")") {
+		return "", false
+	}
+	return strings.TrimSpace(s[len(modifier)+1 : len(s)-1]), true
+}
+
+// typeArgs splits the given type into its name and arguments.
+func typeArgs(raw string) (string, []string, error) {
+	s := strings.TrimSpace(raw)
+	i := strings.IndexByte(s, '(')
+	if i == -1 {
+		return s, nil, nil
+	}
+	if !strings.HasSuffix(s, ")") {
+		return "", nil, fmt.Errorf("clickhouse: unexpected type: %q", raw)
+	}
+	args, err := splitExprs(s[i+1 : len(s)-1])
+	if err != nil {
+		return "", nil, fmt.Errorf("clickhouse: unexpected type: %q", raw)
+	}
+	return strings.TrimSpace(s[:i]), args, nil
+}
+
+// splitExprs splits a comma-separated list of expressions.
+func splitExprs(s string) ([]string, error) {
+	var exprs []string
+	for s = strings.TrimSpace(s); s != ""; {
+		i := sqlx.ExprLastIndex(s)
+		if i == -1 {
+			return nil, fmt.Errorf("clickhouse: unexpected expression list: %q", s)
+		}
+		exprs = append(exprs, strings.TrimSpace(s[:i+1]))
+		s = strings.TrimSpace(strings.TrimPrefix(s[i+1:], ","))
+	}
+	return exprs, nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultDiff provides basic diffing capabilities for ClickHouse.
+// Note, it is recommended to call Open, create a new Driver and use its
+// Differ when a database connection is available.
+var DefaultDiff schema.Differ = &sqlx.Diff{DiffDriver: &diff{&conn{ExecQuerier: sqlx.NoRows}}}
+
+// A diff provides a ClickHouse implementation for sqlx.DiffDriver.
+type diff struct{ *conn }
+
+// SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
+func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
+	// No special schema attribute diffing for ClickHouse.
+	return nil
+}
+
+// SchemaObjectDiff returns a changeset for migrating schema objects from
+// one state to the other.
+func (*diff) SchemaObjectDiff(_, _ *schema.Schema) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (*diff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
+	var changes []schema.Change
+	if e1, e2 := engine(from.Attrs), engine(to.Attrs); !strings.EqualFold(e1.V, e2.V) {
+		changes = append(changes, &schema.ModifyAttr{
+			From: e1,
+			To:   e2,
+		})
+	}
+	for _, c := range []schema.Change{
+		keyDiff(from.Attrs, to.Attrs, &OrderBy{}, &OrderBy{}),
+		keyDiff(from.Attrs, to.Attrs, &PartitionBy{}, &PartitionBy{}),
+		keyDiff(from.Attrs, to.Attrs, &TTL{}, &TTL{}),
+	} {
+		if c != nil {
+			changes = append(changes, c)
+		}
+	}
+	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// keyDiff returns the change (if any) of a table key (or TTL) expression.
+// The x1 and x2 arguments are the pointers that are filled by the lookup.
+func keyDiff(from, to []schema.Attr, x1, x2 schema.Attr) schema.Change {
+	fromHas, toHas := sqlx.Has(from, x1), sqlx.Has(to, x2)
+	switch {
+	case !fromHas && toHas:
+		return &schema.AddAttr{A: x2}
+	case fromHas && !toHas:
+		return &schema.DropAttr{A: x1}
+	case fromHas && toHas && keyExpr(x1) != keyExpr(x2):
+		return &schema.ModifyAttr{From: x1, To: x2}
+	}
+	return nil
+}
+
+// keyExpr returns the normalized expression of the given key attribute.
+// ClickHouse formats the key expressions, and returns them without the
+// wrapping tuple. e.g. "ORDER BY (a, b)" is returned as "a, b".
+func keyExpr(a schema.Attr) string {
+	var x string
+	switch a := a.(type) {
+	case *OrderBy:
+		x = a.X
+	case *PartitionBy:
+		x = a.X
+	case *TTL:
+		x = a.X
+	}
+	x = strings.TrimSpace(x)
+	if strings.HasPrefix(x, "tuple(") && strings.HasSuffix(x, ")") {
+		x = x[len("tuple(") : len(x)-1]
+	}
+	for len(x) > 1 && x[0] == '(' && closingParen(x) == len(x)-1 {
+		x = strings.TrimSpace(x[1 : len(x)-1])
+	}
+	return strings.Join(strings.Fields(strings.ReplaceAll(x, "`", "")), " ")
+}
+
+// engine returns the engine of the table. MergeTree is the default.
+func engine(attrs []schema.Attr) *Engine {
+	if e := (Engine{}); sqlx.Has(attrs, &e) && e.V != "" {
+		return &e
+	}
+	return &Engine{V: EngineMergeTree}
+}
+
+func (*diff) ViewAttrChanged(_, _ *schema.View) bool {
+	return false // Not implemented.
+}
+
+// ColumnChange returns the schema changes (if any) for migrating one column to the other.
+func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column) (schema.ChangeKind, error) {
+	change := sqlx.CommentChange(from.Attrs, to.Attrs)
+	if from.Type.Null != to.Type.Null {
+		change |= schema.ChangeNull
+	}
+	changed, err := d.typeChanged(from, to)
+	if err != nil {
+		return schema.NoChange, err
+	}
+	if changed {
+		change |= schema.ChangeType
+	}
+	if d.defaultChanged(from, to) {
+		change |= schema.ChangeDefault
+	}
+	if d.generatedChanged(from, to) {
+		change |= schema.ChangeGenerated
+	}
+	return change, nil
+}
+
+// typeChanged reports if the column type was changed, including its LowCardinality modifier.
+func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
+	fromT, toT := from.Type.Type, to.Type.Type
+	if fromT == nil || toT == nil {
+		return false, fmt.Errorf("clickhouse: missing type information for column %q", from.Name)
+	}
+	if sqlx.Has(from.Attrs, &LowCardinality{}) != sqlx.Has(to.Attrs, &LowCardinality{}) {
+		return true, nil
+	}
+	from1, err := FormatType(fromT)
+	if err != nil {
+		return false, err
+	}
+	to1, err := FormatType(toT)
+	if err != nil {
+		return false, err
+	}
+	return !strings.EqualFold(strings.ReplaceAll(from1, " ", ""), strings.ReplaceAll(to1, " ", "")), nil
+}
+
+// defaultChanged reports if the default value of a column was changed.
+func (d *diff) defaultChanged(from, to *schema.Column) bool {
+	d1, ok1 := defaultKey(from)
+	d2, ok2 := defaultKey(to)
+	return ok1 != ok2 || d1 != d2
+}
+
+// defaultKey returns the default value of the column used for comparison.
+// String literals are compared by their values, as they are returned
+// quoted by the database.
+func defaultKey(c *schema.Column) (string, bool) {
+	x, ok := sqlx.DefaultValue(c)
+	if !ok {
+		return "", false
+	}
+	if sqlx.IsQuoted(x, '\'') {
+		if u, err := sqlx.Unquote(x); err == nil {
+			return u, true
+		}
+	}
+	return x, true
+}
+
+// generatedChanged reports if the generated expression of a column was changed.
+func (*diff) generatedChanged(from, to *schema.Column) bool {
+	var (
+		fromX, toX     schema.GeneratedExpr
+		fromHas, toHas = sqlx.Has(from.Attrs, &fromX), sqlx.Has(to.Attrs, &toX)
+	)
+	return fromHas != toHas || fromHas && (fromX.Expr != toX.Expr || generatedType(fromX.Type) != generatedType(toX.Type))
+}
+
+// IsGeneratedIndexName reports if the index name was generated by the database.
+func (*diff) IsGeneratedIndexName(_ *schema.Table, _ *schema.Index) bool {
+	return false
+}
+
+// IndexAttrChanged reports if the index attributes were changed.
+func (*diff) IndexAttrChanged(_, _ []schema.Attr) bool {
+	return false // Not implemented.
+}
+
+// IndexPartAttrChanged reports if the index-part attributes were changed.
+func (*diff) IndexPartAttrChanged(_, _ *schema.Index, _ int) bool {
+	return false // Not implemented.
+}
+
+// ReferenceChanged reports if the foreign key referential action was changed.
+func (*diff) ReferenceChanged(_, _ schema.ReferenceOption) bool {
+	return false // Foreign keys are not supported by ClickHouse.
+}
+
+// Normalize implements the sqlx.Normalizer interface.
+func (*diff) Normalize(_, to *schema.Table) error {
+	// In ClickHouse, the primary key defaults to the sorting key of
+	// the table, and the sorting key defaults to the primary key if
+	// it is not set. Hence, a primary key that is identical to ORDER
+	// BY is not reported by the inspection, and it is ignored here.
+	if to.PrimaryKey == nil {
+		return nil
+	}
+	pk := &OrderBy{X: primaryKeyExpr(to.PrimaryKey)}
+	if o := (OrderBy{}); !sqlx.Has(to.Attrs, &o) {
+		to.AddAttrs(pk)
+		to.PrimaryKey = nil
+	} else if keyExpr(pk) == keyExpr(&o) {
+		to.PrimaryKey = nil
+	}
+	return nil
+}
+
+// primaryKeyExpr returns the expression of the primary key.
+func primaryKeyExpr(pk *schema.Index) string {
+	parts := make([]string, len(pk.Parts))
+	for i, p := range pk.Parts {
+		switch {
+		case p.C != nil:
+			parts[i] = p.C.Name
+		case p.X != nil:
+			if x, ok := schema.UnderlyingExpr(p.X).(*schema.RawExpr); ok {
+				parts[i] = x.X
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// generatedType returns the MATERIALIZED or ALIAS
+// type of the generated column. MATERIALIZED is the default.
+func generatedType(s string) string {
+	switch s = strings.ToUpper(s); s {
+	case "", "STORED":
+		return kindMaterialized
+	case "VIRTUAL":
+		return kindAlias
+	default:
+		return s
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_TableDiff(t *testing.T) {
+	type testcase struct {
+		name        string
+		from, to    *schema.Table
+		wantChanges []schema.Change
+		wantErr     bool
+	}
+	tests := []testcase{
+		{
+			name: "no changes",
+			from: schema.NewTable("users").AddAttrs(&Engine{V: EngineMergeTree}, &OrderBy{X: "id, ts"}),
+			to:   schema.NewTable("users").AddAttrs(&OrderBy{X: "(id, ts)"}),
+		},
+		{
+			name: "table attributes",
+			from: schema.NewTable("users").AddAttrs(&Engine{V: EngineMergeTree}, &OrderBy{X: "id"}, &TTL{X: "ts + toIntervalDay(1)"}),
+			to:   schema.NewTable("users").AddAttrs(&Engine{V: "ReplacingMergeTree"}, &OrderBy{X: "id, ts"}, &PartitionBy{X: "toDate(ts)"}, &schema.Comment{Text: "users"}),
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{From: &Engine{V: EngineMergeTree}, To: &Engine{V: "ReplacingMergeTree"}},
+				&schema.ModifyAttr{From: &OrderBy{X: "id"}, To: &OrderBy{X: "id, ts"}},
+				&schema.AddAttr{A: &PartitionBy{X: "toDate(ts)"}},
+				&schema.DropAttr{A: &TTL{X: "ts + toIntervalDay(1)"}},
+				&schema.AddAttr{A: &schema.Comment{Text: "users"}},
+			},
+		},
+		func() testcase {
+			var (
+				from = schema.NewTable("users").
+					AddColumns(
+						schema.NewColumn("a").SetType(&schema.StringType{T: TypeString}),
+						schema.NewColumn("b").SetType(&schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}),
+						schema.NewColumn("c").SetType(&schema.StringType{T: TypeString}).SetDefault(&schema.Literal{V: "'x'"}),
+						schema.NewColumn("d").SetType(&schema.TimeType{T: TypeDate}).SetGeneratedExpr(&schema.GeneratedExpr{Expr: "toDate(ts)", Type: "MATERIALIZED"}),
+					)
+				to = schema.NewTable("users").
+					AddColumns(
+						schema.NewNullColumn("a").SetType(&schema.StringType{T: TypeString}),
+						schema.NewColumn("b").SetType(&schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}),
+						schema.NewColumn("c").SetType(&schema.StringType{T: TypeString}).SetDefault(&schema.Literal{V: "x"}),
+						schema.NewColumn("d").SetType(&schema.TimeType{T: TypeDate}).SetGeneratedExpr(&schema.GeneratedExpr{Expr: "toDate(ts)", Type: "ALIAS"}),
+					)
+			)
+			from.Columns[1].AddAttrs(&LowCardinality{})
+			return testcase{
+				name: "columns",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeNull},
+					&schema.ModifyColumn{From: from.Columns[1], To: to.Columns[1], Change: schema.ChangeType},
+					&schema.ModifyColumn{From: from.Columns[3], To: to.Columns[3], Change: schema.ChangeGenerated},
+				},
+			}
+		}(),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := DefaultDiff.TableDiff(tt.from, tt.to)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.EqualValues(t, tt.wantChanges, changes)
+		})
+	}
+}
+
+func TestDiff_Normalize(t *testing.T) {
+	// Primary key that is identical to the sorting key.
+	from := schema.NewTable("users").
+		AddColumns(schema.NewColumn("id").SetType(&schema.IntegerType{T: TypeUInt64, Unsigned: true})).
+		AddAttrs(&Engine{V: EngineMergeTree}, &OrderBy{X: "id"})
+	to := schema.NewTable("users").
+		AddColumns(schema.NewColumn("id").SetType(&schema.IntegerType{T: TypeUInt64, Unsigned: true})).
+		AddAttrs(&OrderBy{X: "(id)"})
+	to.SetPrimaryKey(schema.NewPrimaryKey(to.Columns[0]))
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// Primary key without a sorting key.
+	to = schema.NewTable("users").
+		AddColumns(schema.NewColumn("id").SetType(&schema.IntegerType{T: TypeUInt64, Unsigned: true}))
+	to.SetPrimaryKey(schema.NewPrimaryKey(to.Columns[0]))
+	changes, err = DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
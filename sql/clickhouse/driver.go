@@ -0,0 +1,121 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+)
+
+type (
+	// Driver represents a ClickHouse driver for introspecting database schemas,
+	// generating diff between schema elements and apply migrations changes.
+	Driver struct {
+		*conn
+		schema.Differ
+		schema.Inspector
+		migrate.PlanApplier
+	}
+
+	// database connection and its information.
+	conn struct {
+		schema.ExecQuerier
+		// System variables that are set on `Open`.
+		version string
+	}
+)
+
+// DriverName holds the name used for registration.
+const DriverName = "clickhouse"
+
+func init() {
+	sqlclient.Register(
+		DriverName,
+		sqlclient.DriverOpener(Open),
+		sqlclient.RegisterCodec(MarshalHCL, EvalHCL),
+		sqlclient.RegisterURLParser(parser{}),
+	)
+}
+
+// Open opens a new ClickHouse driver.
+func Open(db schema.ExecQuerier) (migrate.Driver, error) {
+	c := &conn{ExecQuerier: db}
+	rows, err := db.QueryContext(context.Background(), "SELECT version()")
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse: query version: %w", err)
+	}
+	if err := sqlx.ScanOne(rows, &c.version); err != nil {
+		return nil, fmt.Errorf("clickhouse: scan version: %w", err)
+	}
+	return &Driver{
+		conn:        c,
+		Differ:      &sqlx.Diff{DiffDriver: &diff{c}},
+		Inspector:   &inspect{c},
+		PlanApplier: &planApply{c},
+	}, nil
+}
+
+// CheckClean implements migrate.CleanChecker.
+func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error {
+	r, err := d.InspectRealm(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		switch n := len(s.Tables); {
+		case n > 1:
+			return &migrate.NotCleanError{Reason: fmt.Sprintf("found multiple tables in schema %q: %d", s.Name, n)}
+		case n == 1 && (revT == nil || s.Tables[0].Name != revT.Name || revT.Schema != "" && s.Name != revT.Schema):
+			return &migrate.NotCleanError{Reason: fmt.Sprintf("found table %q in schema %q", s.Tables[0].Name, s.Name)}
+		}
+	}
+	return nil
+}
+
+// Capabilities implements migrate.Capable.
+func (d *Driver) Capabilities() migrate.Capabilities {
+	return migrate.Capabilities{
+		DropColumn:   true,
+		RenameColumn: true,
+		ModifyColumn: true,
+	}
+}
+
+// Version returns the version of the connected database.
+func (d *Driver) Version() string {
+	return d.conn.version
+}
+
+type parser struct{}
+
+// ParseURL implements the sqlclient.URLParser interface.
+func (parser) ParseURL(u *url.URL) *sqlclient.URL {
+	return &sqlclient.URL{URL: u, DSN: u.String(), Schema: strings.TrimPrefix(u.Path, "/")}
+}
+
+// ChangeSchema implements the sqlclient.SchemaChanger interface.
+func (parser) ChangeSchema(u *url.URL, s string) *url.URL {
+	nu := *u
+	nu.Path = "/" + s
+	return &nu
+}
+
+// ClickHouse table engines and column kinds that are
+// used by the inspection and the planning of changes.
+const (
+	// EngineMergeTree is the default table engine.
+	EngineMergeTree = "MergeTree"
+
+	kindDefault      = "DEFAULT"
+	kindMaterialized = "MATERIALIZED"
+	kindAlias        = "ALIAS"
+)
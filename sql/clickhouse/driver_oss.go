@@ -0,0 +1,11 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+//go:build !ent
+
+package clickhouse
+
+import "ariga.io/atlas/schemahcl"
+
+var specOptions []schemahcl.Option
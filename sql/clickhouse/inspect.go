@@ -0,0 +1,419 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// A inspect provides a ClickHouse implementation for schema.Inspector.
+type inspect struct{ *conn }
+
+var _ schema.Inspector = (*inspect)(nil)
+
+// InspectRealm returns schema descriptions of all resources in the given realm.
+func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	schemas, err := i.databases(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &schema.InspectRealmOption{}
+	}
+	r := schema.NewRealm(schemas...)
+	if len(schemas) == 0 || !sqlx.ModeInspectRealm(opts).Is(schema.InspectTables) {
+		return sqlx.FilterRealm(r, opts)
+	}
+	if err := i.inspectTables(ctx, r, nil); err != nil {
+		return nil, err
+	}
+	sqlx.LinkSchemaTables(schemas)
+	return sqlx.FilterRealm(r, opts)
+}
+
+// InspectSchema returns schema descriptions of the tables in the given schema.
+// If the schema name is empty, the result will be the attached schema.
+func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (s *schema.Schema, err error) {
+	schemas, err := i.databases(ctx, &schema.InspectRealmOption{
+		Schemas: []string{name},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch n := len(schemas); {
+	case n == 0:
+		return nil, &schema.NotExistError{Err: fmt.Errorf("clickhouse: schema %q was not found", name)}
+	case n > 1:
+		return nil, fmt.Errorf("clickhouse: %d schemas were found for %q", n, name)
+	}
+	if opts == nil {
+		opts = &schema.InspectOptions{}
+	}
+	r := schema.NewRealm(schemas...)
+	if sqlx.ModeInspectSchema(opts).Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, opts); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.FilterSchema(r.Schemas[0], opts)
+}
+
+func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
+	pks, err := i.tables(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		if len(s.Tables) == 0 {
+			continue
+		}
+		if err := i.columns(ctx, s); err != nil {
+			return err
+		}
+	}
+	for t, x := range pks {
+		if err := primaryKey(t, x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// databases returns the list of all schemas (databases) in the server, excluding
+// the system ones. If the given schema name is empty, the current database is used.
+func (i *inspect) databases(ctx context.Context, opts *schema.InspectRealmOption) ([]*schema.Schema, error) {
+	var (
+		args  []any
+		query = databasesQuery
+	)
+	if opts != nil {
+		switch n := len(opts.Schemas); {
+		case n == 1 && opts.Schemas[0] == "":
+			query = databasesQueryCurrent
+		case n > 0:
+			query = fmt.Sprintf(databasesQueryArgs, nArgs(n))
+			for _, s := range opts.Schemas {
+				args = append(args, s)
+			}
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse: querying schemas: %w", err)
+	}
+	names, err := sqlx.ScanStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse: scanning schemas: %w", err)
+	}
+	schemas := make([]*schema.Schema, 0, len(names))
+	for _, n := range names {
+		schemas = append(schemas, schema.New(n))
+	}
+	return schemas, nil
+}
+
+// tables queries and appends the tables of the given schemas. The primary keys
+// of the tables are returned separately, as they are set after the columns are
+// inspected, and only if they were defined explicitly (not by the sorting key).
+func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.InspectOptions) (map[*schema.Table]string, error) {
+	var (
+		args  []any
+		query = fmt.Sprintf(tablesQuery, nArgs(len(realm.Schemas)))
+	)
+	for _, s := range realm.Schemas {
+		args = append(args, s.Name)
+	}
+	if opts != nil && len(opts.Tables) > 0 {
+		for _, t := range opts.Tables {
+			args = append(args, t)
+		}
+		query = fmt.Sprintf(tablesQueryArgs, nArgs(len(realm.Schemas)), nArgs(len(opts.Tables)))
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse: querying schema tables: %w", err)
+	}
+	defer rows.Close()
+	pks := make(map[*schema.Table]string)
+	for rows.Next() {
+		var (
+			database, name, engine, partition, sorting, primary, comment sql.NullString
+		)
+		if err := rows.Scan(&database, &name, &engine, &partition, &sorting, &primary, &comment); err != nil {
+			return nil, fmt.Errorf("clickhouse: scanning table information: %w", err)
+		}
+		s, ok := realm.Schema(database.String)
+		if !ok {
+			return nil, fmt.Errorf("clickhouse: schema %q was not found in realm", database.String)
+		}
+		t := schema.NewTable(name.String)
+		s.AddTables(t)
+		if err := engineAttrs(t, engine.String); err != nil {
+			return nil, err
+		}
+		if sqlx.ValidString(partition) {
+			t.AddAttrs(&PartitionBy{X: partition.String})
+		}
+		if sqlx.ValidString(sorting) {
+			t.AddAttrs(&OrderBy{X: sorting.String})
+		}
+		if sqlx.ValidString(primary) && primary.String != sorting.String {
+			pks[t] = primary.String
+		}
+		if sqlx.ValidString(comment) {
+			t.SetComment(comment.String)
+		}
+	}
+	return pks, rows.Close()
+}
+
+// columns queries and appends the columns of the tables in the given schema.
+func (i *inspect) columns(ctx context.Context, s *schema.Schema) error {
+	args := []any{s.Name}
+	for _, t := range s.Tables {
+		args = append(args, t.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, nArgs(len(s.Tables))), args...)
+	if err != nil {
+		return fmt.Errorf("clickhouse: querying schema %q columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := i.addColumn(s, rows); err != nil {
+			return fmt.Errorf("clickhouse: %w", err)
+		}
+	}
+	return rows.Close()
+}
+
+// addColumn scans the current row and adds a new column from it to the scope (table).
+func (i *inspect) addColumn(s *schema.Schema, rows *sql.Rows) error {
+	var tableName, name, typ, kind, expr, comment sql.NullString
+	if err := rows.Scan(&tableName, &name, &typ, &kind, &expr, &comment); err != nil {
+		return err
+	}
+	t, ok := s.Table(tableName.String)
+	if !ok {
+		return fmt.Errorf("table %q was not found in schema", tableName.String)
+	}
+	ct, lc, err := parseColumnType(typ.String)
+	if err != nil {
+		return err
+	}
+	c := schema.NewColumn(name.String)
+	c.Type = ct
+	if lc {
+		c.AddAttrs(&LowCardinality{})
+	}
+	if sqlx.ValidString(expr) {
+		switch strings.ToUpper(kind.String) {
+		case kindMaterialized, kindAlias:
+			c.SetGeneratedExpr(&schema.GeneratedExpr{Expr: expr.String, Type: strings.ToUpper(kind.String)})
+		default:
+			c.Default = defaultExpr(expr.String)
+		}
+	}
+	if sqlx.ValidString(comment) {
+		c.SetComment(comment.String)
+	}
+	t.AddColumns(c)
+	return nil
+}
+
+// primaryKey sets the primary key of the table from its key expression.
+func primaryKey(t *schema.Table, x string) error {
+	exprs, err := splitExprs(x)
+	if err != nil {
+		return err
+	}
+	idx := schema.NewPrimaryKey()
+	for _, x := range exprs {
+		if c, ok := t.Column(strings.Trim(x, "`")); ok {
+			idx.AddColumns(c)
+		} else {
+			idx.AddExprs(&schema.RawExpr{X: x})
+		}
+	}
+	t.SetPrimaryKey(idx)
+	return nil
+}
+
+// The engine name, optionally followed by its arguments,
+// and the TTL clause of the "engine_full" column.
+var (
+	reEngine = regexp.MustCompile(`^\s*(\w+)`)
+	reTTL    = regexp.MustCompile(`(?s)\sTTL\s+(.+?)(?:\s+SETTINGS\s+.*)?$`)
+)
+
+// engineAttrs extracts the engine and the TTL of the table
+// from the "engine_full" column, and appends them to the table.
+func engineAttrs(t *schema.Table, full string) error {
+	m := reEngine.FindStringSubmatch(full)
+	if len(m) != 2 {
+		return fmt.Errorf("clickhouse: unexpected engine %q for table %q", full, t.Name)
+	}
+	e := m[1]
+	if rest := full[len(m[0]):]; strings.HasPrefix(rest, "(") {
+		j := closingParen(rest)
+		if j == -1 {
+			return fmt.Errorf("clickhouse: unexpected engine arguments %q for table %q", rest, t.Name)
+		}
+		e += rest[:j+1]
+	}
+	t.AddAttrs(&Engine{V: e})
+	if m := reTTL.FindStringSubmatch(full); len(m) == 2 {
+		t.AddAttrs(&TTL{X: strings.TrimSpace(m[1])})
+	}
+	return nil
+}
+
+// closingParen returns the index of the parenthesis that closes the
+// one at the start of s, or -1 if it was not found.
+func closingParen(s string) int {
+	var depth int
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		case '\'', '"', '`':
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			i = j
+		}
+	}
+	return -1
+}
+
+// defaultExpr returns the schema.Expr of the given column default.
+func defaultExpr(x string) schema.Expr {
+	switch {
+	case sqlx.IsLiteralBool(x), sqlx.IsLiteralNumber(x), sqlx.IsQuoted(x, '\''):
+		return &schema.Literal{V: x}
+	default:
+		return &schema.RawExpr{X: x}
+	}
+}
+
+// nArgs returns the placeholders for n arguments.
+func nArgs(n int) string {
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+type (
+	// Engine describes the engine of the table, optionally with its
+	// arguments. For example, MergeTree or ReplacingMergeTree(ver).
+	Engine struct {
+		schema.Attr
+		V string
+	}
+
+	// OrderBy describes the sorting key of MergeTree tables.
+	OrderBy struct {
+		schema.Attr
+		X string
+	}
+
+	// PartitionBy describes the partition key of MergeTree tables.
+	PartitionBy struct {
+		schema.Attr
+		X string
+	}
+
+	// TTL describes the TTL expression of MergeTree tables.
+	TTL struct {
+		schema.Attr
+		X string
+	}
+
+	// LowCardinality describes a column that is stored using dictionary encoding.
+	LowCardinality struct {
+		schema.Attr
+	}
+)
+
+func init() {
+	schema.RegisterJSON(&Engine{}, &OrderBy{}, &PartitionBy{}, &TTL{}, &LowCardinality{})
+}
+
+const (
+	// Query to list the schemas (databases), excluding the system ones.
+	databasesQuery        = "SELECT name FROM system.databases WHERE name NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema') ORDER BY name"
+	databasesQueryArgs    = "SELECT name FROM system.databases WHERE name IN (%s) ORDER BY name"
+	databasesQueryCurrent = "SELECT name FROM system.databases WHERE name = currentDatabase()"
+
+	// Query to list the tables of the given schemas. Views and dictionaries are excluded.
+	tablesQuery = `
+SELECT
+	database,
+	name,
+	engine_full,
+	partition_key,
+	sorting_key,
+	primary_key,
+	comment
+FROM
+	system.tables
+WHERE
+	database IN (%s)
+	AND is_temporary = 0
+	AND engine NOT IN ('View', 'MaterializedView', 'LiveView', 'WindowView', 'Dictionary')
+	AND name NOT LIKE '.inner%%'
+ORDER BY
+	database, name
+`
+	tablesQueryArgs = `
+SELECT
+	database,
+	name,
+	engine_full,
+	partition_key,
+	sorting_key,
+	primary_key,
+	comment
+FROM
+	system.tables
+WHERE
+	database IN (%s)
+	AND name IN (%s)
+	AND is_temporary = 0
+	AND engine NOT IN ('View', 'MaterializedView', 'LiveView', 'WindowView', 'Dictionary')
+ORDER BY
+	database, name
+`
+
+	// Query to list the columns of the given tables.
+	columnsQuery = `
+SELECT
+	table,
+	name,
+	type,
+	default_kind,
+	default_expression,
+	comment
+FROM
+	system.columns
+WHERE
+	database = ?
+	AND table IN (%s)
+ORDER BY
+	table, position
+`
+)
@@ -0,0 +1,122 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectSchema(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("23.8.2.7")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(databasesQueryCurrent)).
+		WillReturnRows(sqltest.Rows(`
+ name
+------
+ test
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "?"))).
+		WithArgs("test").
+		WillReturnRows(sqlmock.NewRows([]string{"database", "name", "engine_full", "partition_key", "sorting_key", "primary_key", "comment"}).
+			AddRow("test", "events", "ReplacingMergeTree(version) PARTITION BY toYYYYMM(ts) ORDER BY (id, ts) TTL ts + toIntervalDay(30) SETTINGS index_granularity = 8192", "toYYYYMM(ts)", "id, ts", "id", "events table").
+			AddRow("test", "logs", "Log", "", "", "", ""))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "?, ?"))).
+		WithArgs("test", "events", "logs").
+		WillReturnRows(sqlmock.NewRows([]string{"table", "name", "type", "default_kind", "default_expression", "comment"}).
+			AddRow("events", "id", "UInt64", "", "", "").
+			AddRow("events", "ts", "DateTime", "DEFAULT", "now()", "").
+			AddRow("events", "name", "LowCardinality(Nullable(String))", "", "", "event name").
+			AddRow("events", "price", "Decimal(18, 4)", "DEFAULT", "0", "").
+			AddRow("events", "day", "Date", "MATERIALIZED", "toDate(ts)", "").
+			AddRow("events", "kind", "Enum8('a' = 1, 'b' = 2)", "DEFAULT", "'a'", "").
+			AddRow("events", "tags", "Array(String)", "", "", "").
+			AddRow("events", "version", "UInt32", "ALIAS", "id + 1", "").
+			AddRow("logs", "msg", "FixedString(16)", "", "", ""))
+	s, err := drv.InspectSchema(context.Background(), "", nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 2)
+
+	events := s.Tables[0]
+	require.Equal(t, "events", events.Name)
+	require.Equal(t, []schema.Attr{
+		&Engine{V: "ReplacingMergeTree(version)"},
+		&TTL{X: "ts + toIntervalDay(30)"},
+		&PartitionBy{X: "toYYYYMM(ts)"},
+		&OrderBy{X: "id, ts"},
+		&schema.Comment{Text: "events table"},
+	}, events.Attrs)
+	require.Equal(t, []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "UInt64", Unsigned: true}, Raw: "UInt64"}},
+		{Name: "ts", Type: &schema.ColumnType{Type: &schema.TimeType{T: "DateTime"}, Raw: "DateTime"}, Default: &schema.RawExpr{X: "now()"}},
+		{Name: "name", Type: &schema.ColumnType{Type: &schema.StringType{T: "String"}, Raw: "LowCardinality(Nullable(String))", Null: true}, Attrs: []schema.Attr{&LowCardinality{}, &schema.Comment{Text: "event name"}}},
+		{Name: "price", Type: &schema.ColumnType{Type: &schema.DecimalType{T: "Decimal", Precision: 18, Scale: 4}, Raw: "Decimal(18, 4)"}, Default: &schema.Literal{V: "0"}},
+		{Name: "day", Type: &schema.ColumnType{Type: &schema.TimeType{T: "Date"}, Raw: "Date"}, Attrs: []schema.Attr{&schema.GeneratedExpr{Expr: "toDate(ts)", Type: "MATERIALIZED"}}},
+		{Name: "kind", Type: &schema.ColumnType{Type: &schema.EnumType{T: "Enum8", Values: []string{"a", "b"}}, Raw: "Enum8('a' = 1, 'b' = 2)"}, Default: &schema.Literal{V: "'a'"}},
+		{Name: "tags", Type: &schema.ColumnType{Type: &schema.UnsupportedType{T: "Array(String)"}, Raw: "Array(String)"}},
+		{Name: "version", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "UInt32", Unsigned: true}, Raw: "UInt32"}, Attrs: []schema.Attr{&schema.GeneratedExpr{Expr: "id + 1", Type: "ALIAS"}}},
+	}, columnsOf(events))
+	require.NotNil(t, events.PrimaryKey)
+	require.Len(t, events.PrimaryKey.Parts, 1)
+	require.Equal(t, events.Columns[0], events.PrimaryKey.Parts[0].C)
+
+	logs := s.Tables[1]
+	require.Equal(t, []schema.Attr{&Engine{V: "Log"}}, logs.Attrs)
+	require.Nil(t, logs.PrimaryKey)
+	require.Equal(t, &schema.StringType{T: "FixedString", Size: 16}, logs.Columns[0].Type.Type)
+}
+
+func TestParseType(t *testing.T) {
+	p := func(i int) *int { return &i }
+	for _, tt := range []struct {
+		raw string
+		typ schema.Type
+	}{
+		{raw: "Int8", typ: &schema.IntegerType{T: TypeInt8}},
+		{raw: "UInt256", typ: &schema.IntegerType{T: TypeUInt256, Unsigned: true}},
+		{raw: "Float32", typ: &schema.FloatType{T: TypeFloat32}},
+		{raw: "Decimal(10, 2)", typ: &schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}},
+		{raw: "Decimal(10)", typ: &schema.DecimalType{T: TypeDecimal, Precision: 10}},
+		{raw: "Decimal64(4)", typ: &schema.DecimalType{T: TypeDecimal, Precision: 18, Scale: 4}},
+		{raw: "Bool", typ: &schema.BoolType{T: TypeBool}},
+		{raw: "String", typ: &schema.StringType{T: TypeString}},
+		{raw: "FixedString(3)", typ: &schema.StringType{T: TypeFixedString, Size: 3}},
+		{raw: "UUID", typ: &schema.UUIDType{T: TypeUUID}},
+		{raw: "Date32", typ: &schema.TimeType{T: TypeDate32}},
+		{raw: "DateTime", typ: &schema.TimeType{T: TypeDateTime}},
+		{raw: "DateTime('UTC')", typ: &schema.UnsupportedType{T: "DateTime('UTC')"}},
+		{raw: "DateTime64", typ: &schema.TimeType{T: TypeDateTime64, Precision: p(3)}},
+		{raw: "DateTime64(6)", typ: &schema.TimeType{T: TypeDateTime64, Precision: p(6)}},
+		{raw: "Enum16('a,b' = 1, 'c' = 2)", typ: &schema.EnumType{T: TypeEnum16, Values: []string{"a,b", "c"}}},
+		{raw: "Enum8('a' = 1, 'b' = 5)", typ: &schema.UnsupportedType{T: "Enum8('a' = 1, 'b' = 5)"}},
+		{raw: "Map(String, UInt64)", typ: &schema.UnsupportedType{T: "Map(String, UInt64)"}},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			typ, err := ParseType(tt.raw)
+			require.NoError(t, err)
+			require.Equal(t, tt.typ, typ)
+		})
+	}
+}
+
+func columnsOf(t *schema.Table) []*schema.Column {
+	columns := make([]*schema.Column, len(t.Columns))
+	for i, c := range t.Columns {
+		cp := *c
+		cp.Indexes = nil
+		columns[i] = &cp
+	}
+	return columns
+}
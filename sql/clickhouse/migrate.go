@@ -0,0 +1,520 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultPlan provides basic planning capabilities for ClickHouse.
+// Note, it is recommended to call Open, create a new Driver and use its
+// migrate.PlanApplier when a database connection is available.
+var DefaultPlan migrate.PlanApplier = &planApply{conn: &conn{ExecQuerier: sqlx.NoRows}}
+
+// A planApply provides migration capabilities for schema elements.
+type planApply struct{ *conn }
+
+// PlanChanges returns a migration plan for the given schema changes.
+func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	s := &state{
+		conn: p.conn,
+		Plan: migrate.Plan{
+			Name: name,
+			// ClickHouse does not support transactional DDL.
+			Transactional: false,
+		},
+	}
+	for _, o := range opts {
+		o(&s.PlanOptions)
+	}
+	if err := s.plan(changes); err != nil {
+		return nil, err
+	}
+	if err := sqlx.SetReversible(&s.Plan); err != nil {
+		return nil, err
+	}
+	sqlx.Annotate(&s.Plan, s.Annotations)
+	return &s.Plan, nil
+}
+
+// ApplyChanges applies the changes on the database. An error is returned
+// if the driver is unable to produce a plan to it, or one of the statements
+// is failed or unsupported.
+func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+}
+
+// state represents the state of a planning. It is not part of
+// planApply so that multiple planning/applying can be called
+// in parallel.
+type state struct {
+	*conn
+	migrate.Plan
+	migrate.PlanOptions
+}
+
+// plan builds the migration plan for applying the
+// given changes on the attached connection.
+func (s *state) plan(changes []schema.Change) error {
+	if s.SchemaQualifier != nil {
+		if err := sqlx.CheckChangesScope(s.PlanOptions, changes); err != nil {
+			return err
+		}
+	}
+	for _, c := range changes {
+		var err error
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			s.addSchema(c)
+		case *schema.DropSchema:
+			s.dropSchema(c)
+		case *schema.ModifySchema:
+			// No schema attributes are supported by ClickHouse.
+		case *schema.AddTable:
+			err = s.addTable(c)
+		case *schema.DropTable:
+			err = s.dropTable(c)
+		case *schema.ModifyTable:
+			err = s.modifyTable(c)
+		case *schema.RenameTable:
+			s.renameTable(c)
+		default:
+			err = fmt.Errorf("unsupported change %T", c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSchema appends the migrate.Change for creating a database.
+func (s *state) addSchema(add *schema.AddSchema) {
+	b := s.Build("CREATE DATABASE")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Ident(add.S.Name).String(),
+		Source:  add,
+		Reverse: s.Build("DROP DATABASE").Ident(add.S.Name).String(),
+		Comment: fmt.Sprintf("add new schema named %q", add.S.Name),
+	})
+}
+
+// dropSchema appends the migrate.Change for dropping a database.
+func (s *state) dropSchema(drop *schema.DropSchema) {
+	b := s.Build("DROP DATABASE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Ident(drop.S.Name).String(),
+		Source:  drop,
+		Comment: fmt.Sprintf("drop schema named %q", drop.S.Name),
+	})
+}
+
+// addTable builds and appends a migration change
+// for creating a table in a schema.
+func (s *state) addTable(add *schema.AddTable) error {
+	var (
+		errs []string
+		b    = s.Build("CREATE TABLE")
+	)
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	b.Table(add.T)
+	if len(add.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns", add.T.Name)
+	}
+	switch {
+	case len(add.T.Indexes) > 0:
+		return fmt.Errorf("create table %q: indexes are not supported", add.T.Name)
+	case len(add.T.ForeignKeys) > 0:
+		return fmt.Errorf("create table %q: foreign keys are not supported", add.T.Name)
+	}
+	b.WrapIndent(func(b *sqlx.Builder) {
+		b.MapIndent(add.T.Columns, func(i int, b *sqlx.Builder) {
+			if err := s.column(b, add.T.Columns[i]); err != nil {
+				errs = append(errs, err.Error())
+			}
+		})
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
+	}
+	e := engine(add.T.Attrs)
+	b.P("ENGINE", "=", e.V)
+	if p := (PartitionBy{}); sqlx.Has(add.T.Attrs, &p) {
+		b.P("PARTITION BY", keyClause(p.X))
+	}
+	var o OrderBy
+	switch hasO := sqlx.Has(add.T.Attrs, &o); {
+	case add.T.PrimaryKey != nil && hasO:
+		b.P("PRIMARY KEY", keyClause(primaryKeyExpr(add.T.PrimaryKey)), "ORDER BY", keyClause(o.X))
+	case add.T.PrimaryKey != nil:
+		b.P("ORDER BY", keyClause(primaryKeyExpr(add.T.PrimaryKey)))
+	case hasO:
+		b.P("ORDER BY", keyClause(o.X))
+	// The sorting key is required by the MergeTree family.
+	case strings.Contains(e.V, EngineMergeTree):
+		b.P("ORDER BY", "tuple()")
+	}
+	if t := (TTL{}); sqlx.Has(add.T.Attrs, &t) {
+		b.P("TTL", t.X)
+	}
+	if c := (schema.Comment{}); sqlx.Has(add.T.Attrs, &c) {
+		b.P("COMMENT", quote(c.Text))
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP TABLE").Table(add.T).String(),
+		Comment: fmt.Sprintf("create %q table", add.T.Name),
+	})
+	return nil
+}
+
+// dropTable builds and appends the migrate.Change
+// for dropping a table from a schema.
+func (s *state) dropTable(drop *schema.DropTable) error {
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	if err := rs.addTable(&schema.AddTable{T: drop.T}); err != nil {
+		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
+	}
+	b := s.Build("DROP TABLE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Table(drop.T).String(),
+		Source:  drop,
+		Reverse: rs.Changes[0].Cmd,
+		Comment: fmt.Sprintf("drop %q table", drop.T.Name),
+	})
+	return nil
+}
+
+// modifyTable builds and appends the migration changes for
+// bringing the table into its modified state. Changes that
+// rewrite the data of the table (mutations), are executed
+// in a separate statement, and wait for their completion.
+func (s *state) modifyTable(modify *schema.ModifyTable) error {
+	var (
+		alter, mutations []schema.Change
+		keys             = keyColumns(modify.T)
+	)
+	if len(modify.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns; drop the table instead", modify.T.Name)
+	}
+	for _, change := range modify.Changes {
+		switch change := change.(type) {
+		case *schema.ModifyColumn:
+			if !change.Change.Is(schema.ChangeType | schema.ChangeNull) {
+				alter = append(alter, change)
+				continue
+			}
+			if keys[change.From.Name] {
+				return fmt.Errorf("alter table %q: cannot modify the type of key column %q", modify.T.Name, change.From.Name)
+			}
+			mutations = append(mutations, change)
+		case *schema.ModifyAttr:
+			switch change.To.(type) {
+			case *Engine:
+				return fmt.Errorf("alter table %q: changing the table engine requires recreating the table", modify.T.Name)
+			case *PartitionBy:
+				return fmt.Errorf("alter table %q: changing the partition key requires recreating the table", modify.T.Name)
+			}
+			alter = append(alter, change)
+		case *schema.AddAttr, *schema.DropAttr:
+			if a := attrOf(change); a != nil {
+				switch a.(type) {
+				case *PartitionBy:
+					return fmt.Errorf("alter table %q: changing the partition key requires recreating the table", modify.T.Name)
+				case *OrderBy:
+					if _, ok := change.(*schema.DropAttr); ok {
+						return fmt.Errorf("alter table %q: dropping the sorting key requires recreating the table", modify.T.Name)
+					}
+				}
+			}
+			alter = append(alter, change)
+		case *schema.AddPrimaryKey, *schema.DropPrimaryKey, *schema.ModifyPrimaryKey:
+			return fmt.Errorf("alter table %q: changing the primary key requires recreating the table", modify.T.Name)
+		case *schema.AddIndex, *schema.DropIndex, *schema.ModifyIndex, *schema.RenameIndex:
+			return fmt.Errorf("alter table %q: indexes are not supported", modify.T.Name)
+		case *schema.AddForeignKey, *schema.DropForeignKey, *schema.ModifyForeignKey:
+			return fmt.Errorf("alter table %q: foreign keys are not supported", modify.T.Name)
+		case *schema.AddCheck, *schema.DropCheck, *schema.ModifyCheck:
+			return fmt.Errorf("alter table %q: check constraints are not supported", modify.T.Name)
+		default:
+			alter = append(alter, change)
+		}
+	}
+	if len(alter) > 0 {
+		if err := s.alterTable(modify.T, alter, false); err != nil {
+			return err
+		}
+	}
+	if len(mutations) > 0 {
+		if err := s.alterTable(modify.T, mutations, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterTable modifies the given table by executing on it a list of changes in one SQL
+// statement. Mutations are executed synchronously, in order to ensure the statements
+// that follow them are executed only after the data of the table was rewritten.
+func (s *state) alterTable(t *schema.Table, changes []schema.Change, mutation bool) error {
+	var (
+		reverse    []schema.Change
+		reversible = true
+	)
+	build := func(changes []schema.Change) (string, error) {
+		b := s.Build("ALTER TABLE").Table(t)
+		err := b.MapCommaErr(changes, func(i int, b *sqlx.Builder) error {
+			switch change := changes[i].(type) {
+			case *schema.AddColumn:
+				b.P("ADD COLUMN")
+				if err := s.column(b, change.C); err != nil {
+					return err
+				}
+				reverse = append(reverse, &schema.DropColumn{C: change.C})
+			case *schema.DropColumn:
+				b.P("DROP COLUMN").Ident(change.C.Name)
+				reverse = append(reverse, &schema.AddColumn{C: change.C})
+			case *schema.ModifyColumn:
+				b.P("MODIFY COLUMN")
+				if err := s.column(b, change.To); err != nil {
+					return err
+				}
+				// Modifying a column does not remove its default or
+				// generated expression, unless it is done explicitly.
+				if k := exprKind(change.From); k != "" && exprKind(change.To) == "" {
+					b.Comma().P("MODIFY COLUMN").Ident(change.To.Name).P("REMOVE", k)
+				}
+				reverse = append(reverse, &schema.ModifyColumn{
+					From:   change.To,
+					To:     change.From,
+					Change: change.Change,
+				})
+			case *schema.RenameColumn:
+				b.P("RENAME COLUMN").Ident(change.From.Name).P("TO").Ident(change.To.Name)
+				reverse = append(reverse, &schema.RenameColumn{From: change.To, To: change.From})
+			case *schema.AddAttr:
+				if err := s.tableAttr(b, change.A, nil); err != nil {
+					return err
+				}
+				reverse = append(reverse, &schema.DropAttr{A: change.A})
+			case *schema.DropAttr:
+				if err := s.tableAttr(b, nil, change.A); err != nil {
+					return err
+				}
+				// Sorting keys cannot be dropped,
+				// and therefore, cannot be restored.
+				if _, ok := change.A.(*TTL); ok {
+					reverse = append(reverse, &schema.AddAttr{A: change.A})
+				} else {
+					reversible = false
+				}
+			case *schema.ModifyAttr:
+				if err := s.tableAttr(b, change.To, change.From); err != nil {
+					return err
+				}
+				reverse = append(reverse, &schema.ModifyAttr{From: change.To, To: change.From})
+			default:
+				return fmt.Errorf("unsupported change %T", change)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if mutation {
+			b.P("SETTINGS mutations_sync = 2")
+		}
+		return b.String(), nil
+	}
+	cmd, err := build(changes)
+	if err != nil {
+		return fmt.Errorf("alter table %q: %v", t.Name, err)
+	}
+	change := &migrate.Change{
+		Cmd: cmd,
+		Source: &schema.ModifyTable{
+			T:       t,
+			Changes: changes,
+		},
+		Comment: fmt.Sprintf("modify %q table", t.Name),
+	}
+	if reversible {
+		// Changes should be reverted in
+		// a reversed order they were created.
+		sqlx.ReverseChanges(reverse)
+		if change.Reverse, err = build(reverse); err != nil {
+			return fmt.Errorf("reversed alter table %q: %v", t.Name, err)
+		}
+	}
+	s.append(change)
+	return nil
+}
+
+// tableAttr writes the ALTER TABLE clause for setting the table attribute "to",
+// or removing the attribute "from" in case "to" is nil.
+func (s *state) tableAttr(b *sqlx.Builder, to, from schema.Attr) error {
+	switch a := to.(type) {
+	case *OrderBy:
+		b.P("MODIFY ORDER BY", keyClause(a.X))
+	case *TTL:
+		b.P("MODIFY TTL", a.X)
+	case *schema.Comment:
+		b.P("MODIFY COMMENT", quote(a.Text))
+	case nil:
+		switch from.(type) {
+		case *TTL:
+			b.P("REMOVE TTL")
+		case *schema.Comment:
+			b.P("MODIFY COMMENT", quote(""))
+		default:
+			return fmt.Errorf("unsupported table attribute removal %T", from)
+		}
+	default:
+		return fmt.Errorf("unsupported table attribute %T", to)
+	}
+	return nil
+}
+
+func (s *state) renameTable(c *schema.RenameTable) {
+	s.append(&migrate.Change{
+		Source:  c,
+		Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
+		Cmd:     s.Build("RENAME TABLE").Table(c.From).P("TO").Table(c.To).String(),
+		Reverse: s.Build("RENAME TABLE").Table(c.To).P("TO").Table(c.From).String(),
+	})
+}
+
+// column writes the column definition to the builder.
+func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
+	typ, err := columnType(c)
+	if err != nil {
+		return fmt.Errorf("format type for column %q: %w", c.Name, err)
+	}
+	b.Ident(c.Name).P(typ)
+	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
+		if c.Default != nil {
+			return fmt.Errorf("column %q cannot have both default value and generated expression", c.Name)
+		}
+		b.P(generatedType(x.Type), x.Expr)
+	} else {
+		s.columnDefault(b, c)
+	}
+	if x := (schema.Comment{}); sqlx.Has(c.Attrs, &x) {
+		b.P("COMMENT", quote(x.Text))
+	}
+	return nil
+}
+
+// columnDefault writes the default value of column to the builder.
+func (s *state) columnDefault(b *sqlx.Builder, c *schema.Column) {
+	switch x := schema.UnderlyingExpr(c.Default).(type) {
+	case *schema.Literal:
+		v := x.V
+		switch c.Type.Type.(type) {
+		case *schema.StringType, *schema.EnumType, *schema.UUIDType, *schema.TimeType:
+			if !sqlx.IsQuoted(v, '\'') {
+				v = quote(v)
+			}
+		}
+		b.P(kindDefault, v)
+	case *schema.RawExpr:
+		b.P(kindDefault, x.X)
+	}
+}
+
+func (s *state) append(c *migrate.Change) {
+	s.Changes = append(s.Changes, c)
+}
+
+// Build instantiates a new builder and writes the given phrase to it.
+func (s *state) Build(phrases ...string) *sqlx.Builder {
+	b := &sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`', Schema: s.SchemaQualifier, Indent: s.Indent}
+	return b.P(phrases...)
+}
+
+// exprKind returns the kind of the column expression: DEFAULT,
+// MATERIALIZED or ALIAS, or an empty string if there is none.
+func exprKind(c *schema.Column) string {
+	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
+		return generatedType(x.Type)
+	}
+	if c.Default != nil {
+		return kindDefault
+	}
+	return ""
+}
+
+// attrOf returns the attribute of the given AddAttr or DropAttr change.
+func attrOf(c schema.Change) schema.Attr {
+	switch c := c.(type) {
+	case *schema.AddAttr:
+		return c.A
+	case *schema.DropAttr:
+		return c.A
+	}
+	return nil
+}
+
+// keyColumns returns the columns that are used by
+// the sorting, partition or primary keys of the table.
+func keyColumns(t *schema.Table) map[string]bool {
+	var (
+		o     OrderBy
+		p     PartitionBy
+		exprs []string
+		keys  = make(map[string]bool)
+	)
+	if sqlx.Has(t.Attrs, &o) {
+		exprs = append(exprs, keyExpr(&o))
+	}
+	if sqlx.Has(t.Attrs, &p) {
+		exprs = append(exprs, keyExpr(&p))
+	}
+	if t.PrimaryKey != nil {
+		exprs = append(exprs, primaryKeyExpr(t.PrimaryKey))
+	}
+	for _, x := range exprs {
+		parts, err := splitExprs(x)
+		if err != nil {
+			continue
+		}
+		for _, p := range parts {
+			keys[strings.Trim(p, "`")] = true
+		}
+	}
+	return keys
+}
+
+// keyClause returns the key expression in its clause form. Keys
+// with multiple expressions are wrapped with parentheses.
+func keyClause(x string) string {
+	if exprs, err := splitExprs(x); err == nil && len(exprs) > 1 {
+		return sqlx.MayWrap(x)
+	}
+	return x
+}
+
+// quote returns the given string as a ClickHouse string literal.
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
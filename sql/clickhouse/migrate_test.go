@@ -0,0 +1,246 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanChanges(t *testing.T) {
+	users := &schema.Table{
+		Name:   "users",
+		Schema: schema.New("test"),
+		Columns: []*schema.Column{
+			{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: TypeUInt64, Unsigned: true}}},
+			{Name: "name", Type: &schema.ColumnType{Type: &schema.StringType{T: TypeString}, Null: true}, Attrs: []schema.Attr{&LowCardinality{}, &schema.Comment{Text: "user's name"}}},
+			{Name: "created", Type: &schema.ColumnType{Type: &schema.TimeType{T: TypeDateTime}}, Default: &schema.RawExpr{X: "now()"}},
+			{Name: "day", Type: &schema.ColumnType{Type: &schema.TimeType{T: TypeDate}}, Attrs: []schema.Attr{&schema.GeneratedExpr{Expr: "toDate(created)"}}},
+			{Name: "status", Type: &schema.ColumnType{Type: &schema.EnumType{Values: []string{"active", "inactive"}}}, Default: &schema.Literal{V: "active"}},
+		},
+		Attrs: []schema.Attr{
+			&PartitionBy{X: "toYYYYMM(created)"},
+			&OrderBy{X: "id, created"},
+			&TTL{X: "created + toIntervalYear(1)"},
+			&schema.Comment{Text: "users table"},
+		},
+	}
+	tests := []struct {
+		name    string
+		changes []schema.Change
+		plan    *migrate.Plan
+		wantErr string
+	}{
+		{
+			name: "add schema",
+			changes: []schema.Change{
+				&schema.AddSchema{S: schema.New("test"), Extra: []schema.Clause{&schema.IfNotExists{}}},
+				&schema.DropSchema{S: schema.New("old")},
+			},
+			plan: &migrate.Plan{
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE DATABASE IF NOT EXISTS `test`", Reverse: "DROP DATABASE `test`"},
+					{Cmd: "DROP DATABASE `old`"},
+				},
+			},
+		},
+		{
+			name:    "create table",
+			changes: []schema.Change{&schema.AddTable{T: users}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE TABLE `test`.`users` (`id` UInt64, `name` LowCardinality(Nullable(String)) COMMENT 'user\\'s name', `created` DateTime DEFAULT now(), `day` Date MATERIALIZED toDate(created), `status` Enum8('active' = 1, 'inactive' = 2) DEFAULT 'active') ENGINE = MergeTree PARTITION BY toYYYYMM(created) ORDER BY (id, created) TTL created + toIntervalYear(1) COMMENT 'users table'",
+						Reverse: "DROP TABLE `test`.`users`",
+					},
+				},
+			},
+		},
+		{
+			name: "create table with engine and primary key",
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: func() *schema.Table {
+						t := schema.NewTable("logs").
+							AddColumns(
+								schema.NewColumn("id").SetType(&schema.IntegerType{T: TypeUInt32, Unsigned: true}),
+								schema.NewColumn("v").SetType(&schema.IntegerType{T: TypeUInt32, Unsigned: true}),
+							).
+							AddAttrs(&Engine{V: "ReplacingMergeTree(v)"}, &OrderBy{X: "id, v"})
+						return t.SetPrimaryKey(schema.NewPrimaryKey(t.Columns[0]))
+					}(),
+				},
+				&schema.AddTable{
+					T: schema.NewTable("tmp").
+						AddColumns(schema.NewColumn("id").SetType(&schema.IntegerType{T: TypeUInt32, Unsigned: true})),
+				},
+				&schema.AddTable{
+					T: schema.NewTable("memory").
+						AddColumns(schema.NewColumn("id").SetType(&schema.IntegerType{T: TypeUInt32, Unsigned: true})).
+						AddAttrs(&Engine{V: "Memory"}),
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE TABLE `logs` (`id` UInt32, `v` UInt32) ENGINE = ReplacingMergeTree(v) PRIMARY KEY id ORDER BY (id, v)", Reverse: "DROP TABLE `logs`"},
+					{Cmd: "CREATE TABLE `tmp` (`id` UInt32) ENGINE = MergeTree ORDER BY tuple()", Reverse: "DROP TABLE `tmp`"},
+					{Cmd: "CREATE TABLE `memory` (`id` UInt32) ENGINE = Memory", Reverse: "DROP TABLE `memory`"},
+				},
+			},
+		},
+		{
+			name:    "drop table",
+			changes: []schema.Change{&schema.DropTable{T: schema.NewTable("tmp").AddColumns(schema.NewColumn("id").SetType(&schema.StringType{T: TypeString}))}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: "DROP TABLE `tmp`", Reverse: "CREATE TABLE `tmp` (`id` String) ENGINE = MergeTree ORDER BY tuple()"},
+				},
+			},
+		},
+		{
+			name: "rename table",
+			changes: []schema.Change{
+				&schema.RenameTable{From: schema.NewTable("a"), To: schema.NewTable("b")},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: "RENAME TABLE `a` TO `b`", Reverse: "RENAME TABLE `b` TO `a`"},
+				},
+			},
+		},
+		{
+			name: "alter table",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewColumn("age").SetType(&schema.IntegerType{T: TypeUInt8, Unsigned: true}).SetDefault(&schema.Literal{V: "0"})},
+						&schema.DropColumn{C: schema.NewColumn("email").SetType(&schema.StringType{T: TypeString})},
+						&schema.RenameColumn{From: schema.NewColumn("a"), To: schema.NewColumn("b")},
+						&schema.ModifyColumn{
+							From:   schema.NewColumn("x").SetType(&schema.StringType{T: TypeString}).SetDefault(&schema.Literal{V: "'a'"}),
+							To:     schema.NewColumn("x").SetType(&schema.StringType{T: TypeString}).SetComment("x"),
+							Change: schema.ChangeDefault | schema.ChangeComment,
+						},
+						&schema.ModifyAttr{From: &OrderBy{X: "id"}, To: &OrderBy{X: "id, created"}},
+						&schema.DropAttr{A: &TTL{X: "created + toIntervalYear(1)"}},
+						&schema.ModifyAttr{From: &schema.Comment{Text: "a"}, To: &schema.Comment{Text: "b"}},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `test`.`users` ADD COLUMN `age` UInt8 DEFAULT 0, DROP COLUMN `email`, RENAME COLUMN `a` TO `b`, MODIFY COLUMN `x` String COMMENT 'x', MODIFY COLUMN `x` REMOVE DEFAULT, MODIFY ORDER BY (id, created), REMOVE TTL, MODIFY COMMENT 'b'",
+						Reverse: "ALTER TABLE `test`.`users` MODIFY COMMENT 'a', MODIFY TTL created + toIntervalYear(1), MODIFY ORDER BY id, MODIFY COLUMN `x` String DEFAULT 'a', RENAME COLUMN `b` TO `a`, ADD COLUMN `email` String, DROP COLUMN `age`",
+					},
+				},
+			},
+		},
+		{
+			name: "mutations",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   users.Columns[1],
+							To:     schema.NewNullColumn("name").SetType(&schema.StringType{T: TypeFixedString, Size: 32}),
+							Change: schema.ChangeType | schema.ChangeComment,
+						},
+						&schema.AddColumn{C: schema.NewColumn("age").SetType(&schema.IntegerType{T: TypeUInt8, Unsigned: true})},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `test`.`users` ADD COLUMN `age` UInt8",
+						Reverse: "ALTER TABLE `test`.`users` DROP COLUMN `age`",
+					},
+					{
+						Cmd:     "ALTER TABLE `test`.`users` MODIFY COLUMN `name` Nullable(FixedString(32)) SETTINGS mutations_sync = 2",
+						Reverse: "ALTER TABLE `test`.`users` MODIFY COLUMN `name` LowCardinality(Nullable(String)) COMMENT 'user\\'s name' SETTINGS mutations_sync = 2",
+					},
+				},
+			},
+		},
+		{
+			name: "key column type",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   users.Columns[0],
+							To:     schema.NewColumn("id").SetType(&schema.IntegerType{T: TypeUInt32, Unsigned: true}),
+							Change: schema.ChangeType,
+						},
+					},
+				},
+			},
+			wantErr: `alter table "users": cannot modify the type of key column "id"`,
+		},
+		{
+			name: "partition key",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyAttr{From: &PartitionBy{X: "toYYYYMM(created)"}, To: &PartitionBy{X: "toDate(created)"}},
+					},
+				},
+			},
+			wantErr: `alter table "users": changing the partition key requires recreating the table`,
+		},
+		{
+			name: "engine",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyAttr{From: &Engine{V: EngineMergeTree}, To: &Engine{V: "Log"}},
+					},
+				},
+			},
+			wantErr: `alter table "users": changing the table engine requires recreating the table`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", tt.changes)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, plan.Transactional)
+			require.Equal(t, tt.plan.Reversible, plan.Reversible)
+			require.Len(t, plan.Changes, len(tt.plan.Changes))
+			for i, c := range plan.Changes {
+				require.Equal(t, tt.plan.Changes[i].Cmd, c.Cmd)
+				require.Equal(t, tt.plan.Changes[i].Reverse, c.Reverse)
+			}
+		})
+	}
+}
+
+func TestDefaultPlan(t *testing.T) {
+	err := DefaultPlan.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t1").AddColumns(schema.NewIntColumn("a", TypeInt32))},
+	})
+	require.EqualError(t, err, `create "t1" table: cannot execute statements without a database connection. use Open to create a new Driver`)
+}
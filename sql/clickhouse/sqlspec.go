@@ -0,0 +1,290 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"fmt"
+	"reflect"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type doc struct {
+	Tables  []*sqlspec.Table  `spec:"table"`
+	Schemas []*sqlspec.Schema `spec:"schema"`
+}
+
+// evalSpec evaluates an Atlas DDL document using an unmarshaler into v by using the input.
+func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
+	switch v := v.(type) {
+	case *schema.Realm:
+		var d doc
+		if err := hclState.Eval(p, &d, input); err != nil {
+			return err
+		}
+		if err := specutil.Scan(v,
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables},
+			&specutil.ScanFuncs{Table: convertTable},
+		); err != nil {
+			return fmt.Errorf("specutil: failed converting to *schema.Realm: %w", err)
+		}
+	case *schema.Schema:
+		var d doc
+		if err := hclState.Eval(p, &d, input); err != nil {
+			return err
+		}
+		if len(d.Schemas) != 1 {
+			return fmt.Errorf("specutil: expecting document to contain a single schema, got %d", len(d.Schemas))
+		}
+		r := &schema.Realm{}
+		if err := specutil.Scan(r,
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables},
+			&specutil.ScanFuncs{Table: convertTable},
+		); err != nil {
+			return err
+		}
+		*v = *r.Schemas[0]
+	case schema.Schema, schema.Realm:
+		return fmt.Errorf("clickhouse: Eval expects a pointer: received %[1]T, expected *%[1]T", v)
+	default:
+		return hclState.Eval(p, v, input)
+	}
+	return nil
+}
+
+// MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
+func MarshalSpec(v any, marshaler schemahcl.Marshaler) ([]byte, error) {
+	var d doc
+	switch s := v.(type) {
+	case *schema.Schema:
+		spec, err := schemaSpec(s)
+		if err != nil {
+			return nil, fmt.Errorf("specutil: failed converting schema to spec: %w", err)
+		}
+		d.Tables = spec.Tables
+		d.Schemas = []*sqlspec.Schema{spec.Schema}
+	case *schema.Realm:
+		for _, s := range s.Schemas {
+			spec, err := schemaSpec(s)
+			if err != nil {
+				return nil, fmt.Errorf("specutil: failed converting schema to spec: %w", err)
+			}
+			d.Tables = append(d.Tables, spec.Tables...)
+			d.Schemas = append(d.Schemas, spec.Schema)
+		}
+		if err := specutil.QualifyTables(d.Tables); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("specutil: failed marshaling spec. %T is not supported", v)
+	}
+	return marshaler.MarshalSpec(&d)
+}
+
+// convertTable converts a sqlspec.Table to a schema.Table.
+func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, error) {
+	t, err := specutil.Table(spec, parent, convertColumn, specutil.PrimaryKey, convertIndex, specutil.Check)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range []struct {
+		name string
+		set  func(string) schema.Attr
+	}{
+		{"engine", func(v string) schema.Attr { return &Engine{V: v} }},
+		{"partition_by", func(v string) schema.Attr { return &PartitionBy{X: v} }},
+		{"order_by", func(v string) schema.Attr { return &OrderBy{X: v} }},
+		{"ttl", func(v string) schema.Attr { return &TTL{X: v} }},
+	} {
+		attr, ok := spec.Attr(a.name)
+		if !ok {
+			continue
+		}
+		v, err := attr.String()
+		if err != nil {
+			return nil, fmt.Errorf("expect string value for attribute table.%s.%s: %w", spec.Name, a.name, err)
+		}
+		t.AddAttrs(a.set(v))
+	}
+	return t, nil
+}
+
+// convertIndex converts a sqlspec.Index into a schema.Index.
+func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
+	return specutil.Index(spec, t)
+}
+
+// convertColumn converts a sqlspec.Column into a schema.Column.
+func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error) {
+	c, err := specutil.Column(spec, convertColumnType)
+	if err != nil {
+		return nil, err
+	}
+	if attr, ok := spec.Attr("low_cardinality"); ok {
+		b, err := attr.Bool()
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			c.AddAttrs(&LowCardinality{})
+		}
+	}
+	if err := specutil.ConvertGenExpr(spec.Remain(), c, generatedType); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// convertColumnType converts a sqlspec.Column into a concrete ClickHouse schema.Type.
+func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
+	return TypeRegistry.Type(spec.Type, spec.Extra.Attrs)
+}
+
+// schemaSpec converts from a concrete ClickHouse schema to Atlas specification.
+func schemaSpec(s *schema.Schema) (*specutil.SchemaSpec, error) {
+	return specutil.FromSchema(s, tableSpec, viewSpec)
+}
+
+// tableSpec converts from a concrete ClickHouse sqlspec.Table to a schema.Table.
+func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
+	spec, err := specutil.FromTable(
+		t,
+		columnSpec,
+		specutil.FromPrimaryKey,
+		indexSpec,
+		specutil.FromForeignKey,
+		specutil.FromCheck,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if e := (Engine{}); sqlx.Has(t.Attrs, &e) && e.V != EngineMergeTree {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("engine", e.V))
+	}
+	if p := (PartitionBy{}); sqlx.Has(t.Attrs, &p) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("partition_by", p.X))
+	}
+	if o := (OrderBy{}); sqlx.Has(t.Attrs, &o) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("order_by", o.X))
+	}
+	if x := (TTL{}); sqlx.Has(t.Attrs, &x) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("ttl", x.X))
+	}
+	return spec, nil
+}
+
+// viewSpec returns an error, as views are not supported by the driver.
+func viewSpec(v *schema.View) (*sqlspec.View, error) {
+	return nil, fmt.Errorf("clickhouse: view %q is not supported", v.Name)
+}
+
+func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
+	return specutil.FromIndex(idx)
+}
+
+// columnSpec converts from a concrete ClickHouse schema.Column into a sqlspec.Column.
+func columnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error) {
+	s, err := specutil.FromColumn(c, columnTypeSpec)
+	if err != nil {
+		return nil, err
+	}
+	if sqlx.Has(c.Attrs, &LowCardinality{}) {
+		s.Extra.Attrs = append(s.Extra.Attrs, schemahcl.BoolAttr("low_cardinality", true))
+	}
+	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
+		s.Extra.Children = append(s.Extra.Children, specutil.FromGenExpr(x, generatedType))
+	}
+	return s, nil
+}
+
+// columnTypeSpec converts from a concrete ClickHouse schema.Type into sqlspec.Column Type.
+func columnTypeSpec(t schema.Type) (*sqlspec.Column, error) {
+	st, err := TypeRegistry.Convert(t)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlspec.Column{Type: st}, nil
+}
+
+// enumSpec returns the TypeSpec of the Enum8 and Enum16 types.
+func enumSpec(name string) *schemahcl.TypeSpec {
+	return &schemahcl.TypeSpec{
+		Name: name,
+		T:    name,
+		Attributes: []*schemahcl.TypeAttr{
+			{Name: "values", Kind: reflect.Slice, Required: true},
+		},
+		RType: reflect.TypeOf(schema.EnumType{}),
+		FromSpec: func(t *schemahcl.Type) (schema.Type, error) {
+			if len(t.Attrs) != 1 || t.Attrs[0].K != "values" {
+				return nil, fmt.Errorf("invalid enum type spec: %v", t)
+			}
+			v, err := t.Attrs[0].Strings()
+			if err != nil {
+				return nil, err
+			}
+			return &schema.EnumType{T: name, Values: v}, nil
+		},
+	}
+}
+
+// TypeRegistry contains the supported TypeSpecs for the clickhouse driver.
+var TypeRegistry = schemahcl.NewRegistry(
+	schemahcl.WithFormatter(FormatType),
+	schemahcl.WithParser(ParseType),
+	schemahcl.WithSpecs(
+		enumSpec(TypeEnum8),
+		enumSpec(TypeEnum16),
+		schemahcl.NewTypeSpec(TypeInt8),
+		schemahcl.NewTypeSpec(TypeInt16),
+		schemahcl.NewTypeSpec(TypeInt32),
+		schemahcl.NewTypeSpec(TypeInt64),
+		schemahcl.NewTypeSpec(TypeInt128),
+		schemahcl.NewTypeSpec(TypeInt256),
+		schemahcl.NewTypeSpec(TypeUInt8),
+		schemahcl.NewTypeSpec(TypeUInt16),
+		schemahcl.NewTypeSpec(TypeUInt32),
+		schemahcl.NewTypeSpec(TypeUInt64),
+		schemahcl.NewTypeSpec(TypeUInt128),
+		schemahcl.NewTypeSpec(TypeUInt256),
+		schemahcl.NewTypeSpec(TypeFloat32),
+		schemahcl.NewTypeSpec(TypeFloat64),
+		schemahcl.NewTypeSpec(TypeDecimal, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr(), schemahcl.ScaleTypeAttr())),
+		schemahcl.NewTypeSpec(TypeBool),
+		schemahcl.NewTypeSpec(TypeString),
+		schemahcl.NewTypeSpec(TypeFixedString, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(true))),
+		schemahcl.NewTypeSpec(TypeUUID),
+		schemahcl.NewTypeSpec(TypeDate),
+		schemahcl.NewTypeSpec(TypeDate32),
+		schemahcl.NewTypeSpec(TypeDateTime),
+		schemahcl.NewTypeSpec(TypeDateTime64, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.NewTypeSpec(TypeJSON),
+	),
+)
+
+var (
+	hclState = schemahcl.New(append(
+		specOptions,
+		schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
+		schemahcl.WithScopedEnums("table.column.as.type", kindMaterialized, kindAlias),
+	)...)
+	// MarshalHCL marshals v into an Atlas HCL DDL document.
+	MarshalHCL = schemahcl.MarshalerFunc(func(v any) ([]byte, error) {
+		return MarshalSpec(v, hclState)
+	})
+	// EvalHCL implements the schemahcl.Evaluator interface.
+	EvalHCL = schemahcl.EvalFunc(evalSpec)
+
+	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
+	// of from an hclparse.Parser instance.
+	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
+)
@@ -0,0 +1,134 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package clickhouse
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSpec(t *testing.T) {
+	f := `
+schema "test" {
+}
+
+table "events" {
+  schema = schema.test
+  column "id" {
+    type = UInt64
+  }
+  column "ts" {
+    type    = DateTime64(6)
+    default = sql("now64()")
+  }
+  column "name" {
+    null            = true
+    type            = String
+    low_cardinality = true
+    comment         = "event name"
+  }
+  column "price" {
+    type = Decimal(18,4)
+  }
+  column "code" {
+    type = FixedString(3)
+  }
+  column "kind" {
+    type = Enum8("a","b")
+  }
+  column "day" {
+    type = Date
+    as {
+      expr = "toDate(ts)"
+      type = ALIAS
+    }
+  }
+  engine       = "ReplacingMergeTree(ts)"
+  partition_by = "toYYYYMM(ts)"
+  order_by     = "id, ts"
+  ttl          = "ts + toIntervalDay(30)"
+  comment      = "events table"
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	tbl, ok := s.Table("events")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{
+		&schema.Comment{Text: "events table"},
+		&Engine{V: "ReplacingMergeTree(ts)"},
+		&PartitionBy{X: "toYYYYMM(ts)"},
+		&OrderBy{X: "id, ts"},
+		&TTL{X: "ts + toIntervalDay(30)"},
+	}, tbl.Attrs)
+	p := 6
+	require.Equal(t, &schema.IntegerType{T: TypeUInt64, Unsigned: true}, tbl.Columns[0].Type.Type)
+	require.Equal(t, &schema.TimeType{T: TypeDateTime64, Precision: &p}, tbl.Columns[1].Type.Type)
+	require.Equal(t, &schema.RawExpr{X: "now64()"}, tbl.Columns[1].Default)
+	require.True(t, tbl.Columns[2].Type.Null)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "event name"}, &LowCardinality{}}, tbl.Columns[2].Attrs)
+	require.Equal(t, &schema.DecimalType{T: TypeDecimal, Precision: 18, Scale: 4}, tbl.Columns[3].Type.Type)
+	require.Equal(t, &schema.StringType{T: TypeFixedString, Size: 3}, tbl.Columns[4].Type.Type)
+	require.Equal(t, &schema.EnumType{T: TypeEnum8, Values: []string{"a", "b"}}, tbl.Columns[5].Type.Type)
+	require.Equal(t, []schema.Attr{&schema.GeneratedExpr{Expr: "toDate(ts)", Type: "ALIAS"}}, tbl.Columns[6].Attrs)
+
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, `table "events" {
+  schema       = schema.test
+  comment      = "events table"
+  engine       = "ReplacingMergeTree(ts)"
+  partition_by = "toYYYYMM(ts)"
+  order_by     = "id, ts"
+  ttl          = "ts + toIntervalDay(30)"
+  column "id" {
+    null = false
+    type = UInt64
+  }
+  column "ts" {
+    null    = false
+    type    = DateTime64(6)
+    default = sql("now64()")
+  }
+  column "name" {
+    null            = true
+    type            = String
+    comment         = "event name"
+    low_cardinality = true
+  }
+  column "price" {
+    null = false
+    type = Decimal(18,4)
+  }
+  column "code" {
+    null = false
+    type = FixedString(3)
+  }
+  column "kind" {
+    null = false
+    type = Enum8("a","b")
+  }
+  column "day" {
+    null = false
+    type = Date
+    as {
+      expr = "toDate(ts)"
+      type = ALIAS
+    }
+  }
+}
+schema "test" {
+}
+`, string(buf))
+
+	var s2 schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &s2, nil))
+	changes, err := DefaultDiff.SchemaDiff(&s, &s2)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
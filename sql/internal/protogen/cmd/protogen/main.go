@@ -0,0 +1,31 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Command protogen generates Go code for a .proto file of this module.
+//
+//	go run ariga.io/atlas/sql/internal/protogen/cmd/protogen -o schemapb/schema.pb.go schema.proto
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"ariga.io/atlas/sql/internal/protogen"
+)
+
+func main() {
+	out := flag.String("o", "", "output file")
+	flag.Parse()
+	if flag.NArg() != 1 || *out == "" {
+		log.Fatal("usage: protogen -o <output> <file.proto>")
+	}
+	b, err := protogen.Generate(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
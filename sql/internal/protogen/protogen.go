@@ -0,0 +1,350 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package protogen generates Go code for the protobuf messages that are defined
+// in the .proto files of this module. The generated types encode and decode the
+// protobuf wire format using the protowire package, and therefore, they do not
+// depend on the protobuf runtime. Only the subset of the language used by these
+// files is supported: messages, nested messages, scalar, repeated and
+// map<string, string> fields.
+package protogen
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type (
+	// File describes a parsed .proto file.
+	File struct {
+		Package  string // Go package name.
+		Messages []*Message
+	}
+
+	// Message describes a message definition.
+	Message struct {
+		Name   string // Qualified name. e.g. Trigger.Event.
+		Doc    []string
+		Fields []*Field
+	}
+
+	// Field describes a message field.
+	Field struct {
+		Name     string
+		Type     string
+		Num      int
+		Repeated bool
+		Doc      []string
+	}
+)
+
+var (
+	reMessage = regexp.MustCompile(`^message\s+(\w+)\s*{$`)
+	reField   = regexp.MustCompile(`^(repeated\s+)?(map<\s*string\s*,\s*string\s*>|[\w.]+)\s+(\w+)\s*=\s*(\d+)\s*;$`)
+	rePackage = regexp.MustCompile(`^option\s+go_package\s*=\s*"([\w./-]+)"\s*;$`)
+)
+
+// Parse parses the messages of the given .proto file.
+func Parse(file string) (*File, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		doc   []string
+		scope []*Message
+		f     = &File{}
+		sc    = bufio.NewScanner(bytes.NewReader(b))
+	)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "//") {
+			doc = append(doc, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+			continue
+		}
+		if i := strings.Index(line, "//"); i != -1 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "":
+		case rePackage.MatchString(line):
+			f.Package = path.Base(rePackage.FindStringSubmatch(line)[1])
+		case reMessage.MatchString(line):
+			name := reMessage.FindStringSubmatch(line)[1]
+			if len(scope) > 0 {
+				name = scope[len(scope)-1].Name + "." + name
+			}
+			m := &Message{Name: name, Doc: doc}
+			f.Messages = append(f.Messages, m)
+			scope = append(scope, m)
+		case line == "}" && len(scope) > 0:
+			scope = scope[:len(scope)-1]
+		case len(scope) > 0:
+			m := reField.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("protogen: unexpected line in message %q: %q", scope[len(scope)-1].Name, line)
+			}
+			num, _ := strconv.Atoi(m[4])
+			fd := &Field{Repeated: m[1] != "", Type: strings.ReplaceAll(m[2], " ", ""), Name: m[3], Num: num, Doc: doc}
+			scope[len(scope)-1].Fields = append(scope[len(scope)-1].Fields, fd)
+		case strings.HasPrefix(line, "syntax"), strings.HasPrefix(line, "package"), strings.HasPrefix(line, "option"):
+		default:
+			return nil, fmt.Errorf("protogen: unsupported statement %q", line)
+		}
+		if line != "" {
+			doc = nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(scope) > 0 {
+		return nil, fmt.Errorf("protogen: message %q was not closed", scope[len(scope)-1].Name)
+	}
+	if f.Package == "" {
+		return nil, fmt.Errorf("protogen: missing go_package option in %s", file)
+	}
+	return f, nil
+}
+
+// Generate generates the Go code of the messages defined in the given .proto file.
+func Generate(file string) ([]byte, error) {
+	f, err := Parse(file)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{File: f, msgs: make(map[string]*Message)}
+	for _, m := range f.Messages {
+		g.msgs[m.Name] = m
+	}
+	g.P("// Code generated by protogen from %s. DO NOT EDIT.", pathBase(file))
+	g.P("")
+	g.P("package %s", f.Package)
+	g.P("")
+	g.P(`import "ariga.io/atlas/sql/internal/protowire"`)
+	for _, m := range f.Messages {
+		if err := g.message(m); err != nil {
+			return nil, err
+		}
+	}
+	return format.Source(g.buf.Bytes())
+}
+
+type generator struct {
+	*File
+	buf  bytes.Buffer
+	msgs map[string]*Message
+}
+
+// P prints a line to the generated code.
+func (g *generator) P(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format+"\n", args...)
+}
+
+func (g *generator) message(m *Message) error {
+	name := goName(m.Name)
+	g.P("")
+	for _, l := range m.Doc {
+		g.P("// %s", l)
+	}
+	g.P("type %s struct {", name)
+	for _, fd := range m.Fields {
+		t, err := g.goType(m, fd)
+		if err != nil {
+			return fmt.Errorf("protogen: message %q: field %q: %w", m.Name, fd.Name, err)
+		}
+		for _, l := range fd.Doc {
+			g.P("// %s", l)
+		}
+		g.P("%s %s `json:\"%s,omitempty\"`", camel(fd.Name), t, fd.Name)
+	}
+	g.P("}")
+	// Encoding.
+	g.P("")
+	g.P("// Marshal returns the protobuf encoding of m.")
+	g.P("func (m *%s) Marshal() ([]byte, error) {", name)
+	g.P("var e protowire.Encoder")
+	g.P("m.encode(&e)")
+	g.P("return e.Bytes(), nil")
+	g.P("}")
+	g.P("")
+	g.P("func (m *%s) encode(e *protowire.Encoder) {", name)
+	for _, fd := range m.Fields {
+		g.encodeField(m, fd)
+	}
+	g.P("}")
+	// Decoding.
+	g.P("")
+	g.P("// Unmarshal parses the protobuf encoding of m.")
+	g.P("func (m *%s) Unmarshal(b []byte) error {", name)
+	g.P("d := protowire.NewDecoder(b)")
+	g.P("for !d.Done() {")
+	g.P("num, typ, err := d.Next()")
+	g.P("if err != nil {")
+	g.P("return err")
+	g.P("}")
+	g.P("switch {")
+	for _, fd := range m.Fields {
+		g.decodeField(m, fd)
+	}
+	g.P("default:")
+	g.P("err = d.Skip(typ)")
+	g.P("}")
+	g.P("if err != nil {")
+	g.P("return err")
+	g.P("}")
+	g.P("}")
+	g.P("return nil")
+	g.P("}")
+	return nil
+}
+
+// Scalar types and their Go types, wire types and protowire methods.
+var scalars = map[string]struct{ goType, wire, method string }{
+	"string": {"string", "BytesType", "String"},
+	"bytes":  {"[]byte", "BytesType", "Bytes"},
+	"bool":   {"bool", "VarintType", "Bool"},
+	"int32":  {"int32", "VarintType", "Int32"},
+	"int64":  {"int64", "VarintType", "Int64"},
+	"uint32": {"uint32", "VarintType", "Uint32"},
+	"uint64": {"uint64", "VarintType", "Uint64"},
+	"double": {"float64", "Fixed64Type", "Double"},
+}
+
+func (g *generator) goType(m *Message, fd *Field) (string, error) {
+	if fd.Type == "map<string,string>" {
+		return "map[string]string", nil
+	}
+	var t string
+	if s, ok := scalars[fd.Type]; ok {
+		if fd.Repeated && s.wire != "BytesType" {
+			return "", fmt.Errorf("repeated %s fields are not supported", fd.Type)
+		}
+		t = s.goType
+	} else {
+		msg, ok := g.lookup(m, fd.Type)
+		if !ok {
+			return "", fmt.Errorf("unknown type %q", fd.Type)
+		}
+		t = "*" + goName(msg.Name)
+	}
+	if fd.Repeated {
+		t = "[]" + t
+	}
+	return t, nil
+}
+
+func (g *generator) encodeField(m *Message, fd *Field) {
+	name := "m." + camel(fd.Name)
+	if fd.Type == "map<string,string>" {
+		g.P("e.StringMap(%d, %s)", fd.Num, name)
+		return
+	}
+	s, scalar := scalars[fd.Type]
+	method := s.method
+	if fd.Type == "bytes" {
+		method = "BytesField"
+	}
+	switch {
+	case fd.Repeated && scalar:
+		g.P("for _, v := range %s {", name)
+		g.P("e.%s(%d, v)", method, fd.Num)
+		g.P("}")
+	case fd.Repeated:
+		g.P("for _, v := range %s {", name)
+		g.P("e.Message(%d, v.encode)", fd.Num)
+		g.P("}")
+	case !scalar:
+		g.P("if %s != nil {", name)
+		g.P("e.Message(%d, %s.encode)", fd.Num, name)
+		g.P("}")
+	default:
+		// Fields with zero values are not encoded.
+		cond := map[string]string{"string": name + ` != ""`, "bytes": "len(" + name + ") > 0", "bool": name}[fd.Type]
+		if cond == "" {
+			cond = name + " != 0"
+		}
+		g.P("if %s {", cond)
+		g.P("e.%s(%d, %s)", method, fd.Num, name)
+		g.P("}")
+	}
+}
+
+func (g *generator) decodeField(m *Message, fd *Field) {
+	name := "m." + camel(fd.Name)
+	if fd.Type == "map<string,string>" {
+		g.P("case num == %d && typ == protowire.BytesType:", fd.Num)
+		g.P("if %s == nil {", name)
+		g.P("%s = make(map[string]string)", name)
+		g.P("}")
+		g.P("err = d.StringMapEntry(%s)", name)
+		return
+	}
+	if s, ok := scalars[fd.Type]; ok {
+		g.P("case num == %d && typ == protowire.%s:", fd.Num, s.wire)
+		if fd.Repeated {
+			g.P("var v %s", s.goType)
+			g.P("if v, err = d.%s(); err == nil {", s.method)
+			g.P("%s = append(%s, v)", name, name)
+			g.P("}")
+		} else {
+			g.P("%s, err = d.%s()", name, s.method)
+		}
+		return
+	}
+	msg, _ := g.lookup(m, fd.Type)
+	g.P("case num == %d && typ == protowire.BytesType:", fd.Num)
+	g.P("v := &%s{}", goName(msg.Name))
+	g.P("if err = d.Message(v.Unmarshal); err == nil {")
+	if fd.Repeated {
+		g.P("%s = append(%s, v)", name, name)
+	} else {
+		g.P("%s = v", name)
+	}
+	g.P("}")
+}
+
+// lookup resolves the message type name in the scope of the given message.
+func (g *generator) lookup(m *Message, name string) (*Message, bool) {
+	for scope := m.Name; scope != ""; {
+		if msg, ok := g.msgs[scope+"."+name]; ok {
+			return msg, true
+		}
+		i := strings.LastIndexByte(scope, '.')
+		if i == -1 {
+			break
+		}
+		scope = scope[:i]
+	}
+	msg, ok := g.msgs[name]
+	return msg, ok
+}
+
+// goName returns the Go name of a message. Similar to protoc-gen-go,
+// nested messages are prefixed with the names of their parents.
+func goName(name string) string {
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+// camel returns the Go name of a field. e.g. seq_no => SeqNo.
+func camel(s string) string {
+	parts := strings.Split(s, "_")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+func pathBase(p string) string {
+	return path.Base(strings.ReplaceAll(p, "\\", "/"))
+}
@@ -0,0 +1,276 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package protowire implements the subset of the protobuf wire format that is used
+// by the code generated by protogen. It allows the generated messages to be encoded
+// and decoded without depending on the protobuf runtime.
+package protowire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Type is the wire type of a field.
+type Type int8
+
+// Wire types.
+const (
+	VarintType  Type = 0
+	Fixed64Type Type = 1
+	BytesType   Type = 2
+	Fixed32Type Type = 5
+)
+
+// Encoder appends encoded fields to a buffer.
+type Encoder struct {
+	b []byte
+}
+
+// Bytes returns the encoded fields.
+func (e *Encoder) Bytes() []byte {
+	return e.b
+}
+
+// Uint64 encodes a varint field.
+func (e *Encoder) Uint64(num int, v uint64) {
+	e.tag(num, VarintType)
+	e.varint(v)
+}
+
+// Int64 encodes an int64 field.
+func (e *Encoder) Int64(num int, v int64) {
+	e.Uint64(num, uint64(v))
+}
+
+// Int32 encodes an int32 field. Negative values are sign-extended, as
+// defined by the protobuf encoding.
+func (e *Encoder) Int32(num int, v int32) {
+	e.Uint64(num, uint64(int64(v)))
+}
+
+// Uint32 encodes an uint32 field.
+func (e *Encoder) Uint32(num int, v uint32) {
+	e.Uint64(num, uint64(v))
+}
+
+// Bool encodes a bool field.
+func (e *Encoder) Bool(num int, v bool) {
+	var u uint64
+	if v {
+		u = 1
+	}
+	e.Uint64(num, u)
+}
+
+// Double encodes a double field.
+func (e *Encoder) Double(num int, v float64) {
+	e.tag(num, Fixed64Type)
+	e.b = binary.LittleEndian.AppendUint64(e.b, math.Float64bits(v))
+}
+
+// String encodes a string field.
+func (e *Encoder) String(num int, v string) {
+	e.tag(num, BytesType)
+	e.varint(uint64(len(v)))
+	e.b = append(e.b, v...)
+}
+
+// BytesField encodes a bytes field.
+func (e *Encoder) BytesField(num int, v []byte) {
+	e.tag(num, BytesType)
+	e.varint(uint64(len(v)))
+	e.b = append(e.b, v...)
+}
+
+// Message encodes a message field using the given function.
+func (e *Encoder) Message(num int, f func(*Encoder)) {
+	var m Encoder
+	f(&m)
+	e.BytesField(num, m.b)
+}
+
+// StringMap encodes a map<string, string> field. Entries
+// are sorted by their keys to keep the encoding stable.
+func (e *Encoder) StringMap(num int, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e.Message(num, func(e *Encoder) {
+			e.String(1, k)
+			e.String(2, m[k])
+		})
+	}
+}
+
+func (e *Encoder) tag(num int, t Type) {
+	e.varint(uint64(num)<<3 | uint64(t))
+}
+
+func (e *Encoder) varint(v uint64) {
+	e.b = binary.AppendUvarint(e.b, v)
+}
+
+// Decoder reads encoded fields from a buffer.
+type Decoder struct {
+	b []byte
+}
+
+// NewDecoder returns a decoder that reads the given buffer.
+func NewDecoder(b []byte) *Decoder {
+	return &Decoder{b: b}
+}
+
+// Done reports if all fields were read.
+func (d *Decoder) Done() bool {
+	return len(d.b) == 0
+}
+
+var errTruncated = errors.New("protowire: unexpected end of buffer")
+
+// Next reads the tag of the next field.
+func (d *Decoder) Next() (int, Type, error) {
+	v, err := d.Uint64()
+	if err != nil {
+		return 0, 0, err
+	}
+	num, t := v>>3, Type(v&7)
+	if num == 0 || num > math.MaxInt32 {
+		return 0, 0, fmt.Errorf("protowire: invalid field number %d", num)
+	}
+	return int(num), t, nil
+}
+
+// Uint64 reads a varint.
+func (d *Decoder) Uint64() (uint64, error) {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.b = d.b[n:]
+	return v, nil
+}
+
+// Int64 reads an int64 varint.
+func (d *Decoder) Int64() (int64, error) {
+	v, err := d.Uint64()
+	return int64(v), err
+}
+
+// Int32 reads an int32 varint.
+func (d *Decoder) Int32() (int32, error) {
+	v, err := d.Uint64()
+	return int32(v), err
+}
+
+// Uint32 reads an uint32 varint.
+func (d *Decoder) Uint32() (uint32, error) {
+	v, err := d.Uint64()
+	return uint32(v), err
+}
+
+// Bool reads a bool varint.
+func (d *Decoder) Bool() (bool, error) {
+	v, err := d.Uint64()
+	return v != 0, err
+}
+
+// Double reads a fixed64 double.
+func (d *Decoder) Double() (float64, error) {
+	if len(d.b) < 8 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint64(d.b)
+	d.b = d.b[8:]
+	return math.Float64frombits(v), nil
+}
+
+// Bytes reads a length-delimited field. The returned slice is a copy.
+func (d *Decoder) Bytes() ([]byte, error) {
+	n, err := d.Uint64()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.b)) {
+		return nil, errTruncated
+	}
+	b := append([]byte(nil), d.b[:n]...)
+	d.b = d.b[n:]
+	return b, nil
+}
+
+// String reads a string field.
+func (d *Decoder) String() (string, error) {
+	b, err := d.Bytes()
+	return string(b), err
+}
+
+// Message reads a message field and decodes it using the given function.
+func (d *Decoder) Message(f func([]byte) error) error {
+	b, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+	return f(b)
+}
+
+// StringMapEntry reads an entry of a map<string, string> field into m.
+func (d *Decoder) StringMapEntry(m map[string]string) error {
+	return d.Message(func(b []byte) error {
+		var (
+			k, v string
+			ed   = NewDecoder(b)
+		)
+		for !ed.Done() {
+			num, t, err := ed.Next()
+			if err != nil {
+				return err
+			}
+			switch {
+			case num == 1 && t == BytesType:
+				k, err = ed.String()
+			case num == 2 && t == BytesType:
+				v, err = ed.String()
+			default:
+				err = ed.Skip(t)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		m[k] = v
+		return nil
+	})
+}
+
+// Skip skips a field of the given type. It is used for fields that are
+// unknown to the decoded message, as defined by the protobuf encoding.
+func (d *Decoder) Skip(t Type) error {
+	switch t {
+	case VarintType:
+		_, err := d.Uint64()
+		return err
+	case Fixed64Type, Fixed32Type:
+		n := 8
+		if t == Fixed32Type {
+			n = 4
+		}
+		if len(d.b) < n {
+			return errTruncated
+		}
+		d.b = d.b[n:]
+		return nil
+	case BytesType:
+		_, err := d.Bytes()
+		return err
+	default:
+		return fmt.Errorf("protowire: unsupported wire type %d", t)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package protowire
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoder(t *testing.T) {
+	var e Encoder
+	e.Int32(1, 150)
+	e.String(2, "testing")
+	e.Message(3, func(e *Encoder) { e.Int32(1, 150) })
+	// Known encodings from the protobuf documentation.
+	require.Equal(t, []byte{
+		0x08, 0x96, 0x01,
+		0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		0x1a, 0x03, 0x08, 0x96, 0x01,
+	}, e.Bytes())
+
+	e = Encoder{}
+	e.Int32(1, -1)
+	require.Equal(t, []byte{0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, e.Bytes())
+}
+
+func TestRoundTrip(t *testing.T) {
+	var e Encoder
+	e.Int64(1, math.MinInt64)
+	e.Uint64(2, math.MaxUint64)
+	e.Int32(3, math.MinInt32)
+	e.Uint32(4, math.MaxUint32)
+	e.Bool(5, true)
+	e.Double(6, math.Pi)
+	e.String(7, "a")
+	e.BytesField(8, []byte("b"))
+	e.StringMap(9, map[string]string{"k2": "v2", "k1": "v1"})
+	// Unknown fields are skipped.
+	e.Double(100, 1)
+	e.String(101, "unknown")
+
+	d := NewDecoder(e.Bytes())
+	next := func(num int, typ Type) {
+		n, tp, err := d.Next()
+		require.NoError(t, err)
+		require.Equal(t, num, n)
+		require.Equal(t, typ, tp)
+	}
+	next(1, VarintType)
+	i64, err := d.Int64()
+	require.NoError(t, err)
+	require.Equal(t, int64(math.MinInt64), i64)
+	next(2, VarintType)
+	u64, err := d.Uint64()
+	require.NoError(t, err)
+	require.Equal(t, uint64(math.MaxUint64), u64)
+	next(3, VarintType)
+	i32, err := d.Int32()
+	require.NoError(t, err)
+	require.Equal(t, int32(math.MinInt32), i32)
+	next(4, VarintType)
+	u32, err := d.Uint32()
+	require.NoError(t, err)
+	require.Equal(t, uint32(math.MaxUint32), u32)
+	next(5, VarintType)
+	b, err := d.Bool()
+	require.NoError(t, err)
+	require.True(t, b)
+	next(6, Fixed64Type)
+	f, err := d.Double()
+	require.NoError(t, err)
+	require.Equal(t, math.Pi, f)
+	next(7, BytesType)
+	s, err := d.String()
+	require.NoError(t, err)
+	require.Equal(t, "a", s)
+	next(8, BytesType)
+	bs, err := d.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), bs)
+	m := make(map[string]string)
+	for i := 0; i < 2; i++ {
+		next(9, BytesType)
+		require.NoError(t, d.StringMapEntry(m))
+	}
+	require.Equal(t, map[string]string{"k1": "v1", "k2": "v2"}, m)
+	for !d.Done() {
+		_, typ, err := d.Next()
+		require.NoError(t, err)
+		require.NoError(t, d.Skip(typ))
+	}
+
+	d = NewDecoder([]byte{0x12, 0x07, 't'})
+	_, _, err = d.Next()
+	require.NoError(t, err)
+	_, err = d.String()
+	require.Equal(t, errTruncated, err)
+	_, _, err = NewDecoder([]byte{0x00}).Next()
+	require.EqualError(t, err, "protowire: invalid field number 0")
+}
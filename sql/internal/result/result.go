@@ -0,0 +1,23 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package result provides helpers for statement results that are shared by the
+// migrate package and the drivers. It does not import other packages of this
+// module, and therefore, it can be used by both migrate and sqlx.
+package result
+
+import "database/sql"
+
+// RowsAffected returns the number of rows affected by the statement, or -1 if it
+// is not reported by the driver. It is used for setting LogStmtDone.RowsAffected.
+func RowsAffected(res sql.Result) int64 {
+	if res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
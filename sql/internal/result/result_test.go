@@ -0,0 +1,19 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowsAffected(t *testing.T) {
+	require.Equal(t, int64(-1), RowsAffected(nil))
+	require.Equal(t, int64(2), RowsAffected(sqlmock.NewResult(0, 2)))
+	require.Equal(t, int64(-1), RowsAffected(sqlmock.NewErrorResult(errors.New("not supported"))))
+}
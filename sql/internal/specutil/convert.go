@@ -23,6 +23,8 @@ type (
 	ConvertTableColumnFunc func(*sqlspec.Column, *schema.Table) (*schema.Column, error)
 	ConvertViewFunc        func(*sqlspec.View, *schema.Schema) (*schema.View, error)
 	ConvertViewColumnFunc  func(*sqlspec.Column, *schema.View) (*schema.Column, error)
+	ConvertFuncFunc        func(*sqlspec.Func, *schema.Schema) (*schema.Func, error)
+	ConvertProcFunc        func(*sqlspec.Proc, *schema.Schema) (*schema.Proc, error)
 	ConvertTypeFunc        func(*sqlspec.Column) (schema.Type, error)
 	ConvertPrimaryKeyFunc  func(*sqlspec.PrimaryKey, *schema.Table) (*schema.Index, error)
 	ConvertIndexFunc       func(*sqlspec.Index, *schema.Table) (*schema.Index, error)
@@ -44,12 +46,19 @@ type (
 		Schemas []*sqlspec.Schema
 		Tables  []*sqlspec.Table
 		Views   []*sqlspec.View
+		Funcs   []*sqlspec.Func
+		Procs   []*sqlspec.Proc
 	}
 	// ScanFuncs represents a set of scan functions
 	// used to convert the HCL document to the Realm.
+	// Func and Proc are optional, and documents that
+	// define functions or procedures without them are
+	// rejected.
 	ScanFuncs struct {
 		Table ConvertTableFunc
 		View  ConvertViewFunc
+		Func  ConvertFuncFunc
+		Proc  ConvertProcFunc
 	}
 )
 
@@ -110,6 +119,42 @@ func Scan(r *schema.Realm, doc *ScanDoc, funcs *ScanFuncs) error {
 			viewDeps[v] = refs
 		}
 	}
+	for _, sf := range doc.Funcs {
+		if funcs.Func == nil {
+			return fmt.Errorf("specutil: functions are not supported by this driver. function: %q", sf.Name)
+		}
+		name, err := SchemaName(sf.Schema)
+		if err != nil {
+			return fmt.Errorf("specutil: cannot extract schema name for function %q: %w", sf.Name, err)
+		}
+		s, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("specutil: schema %q not found for function %q", name, sf.Name)
+		}
+		f, err := funcs.Func(sf, s)
+		if err != nil {
+			return fmt.Errorf("specutil: cannot convert function %q: %w", sf.Name, err)
+		}
+		s.AddFuncs(f)
+	}
+	for _, sp := range doc.Procs {
+		if funcs.Proc == nil {
+			return fmt.Errorf("specutil: procedures are not supported by this driver. procedure: %q", sp.Name)
+		}
+		name, err := SchemaName(sp.Schema)
+		if err != nil {
+			return fmt.Errorf("specutil: cannot extract schema name for procedure %q: %w", sp.Name, err)
+		}
+		s, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("specutil: schema %q not found for procedure %q", name, sp.Name)
+		}
+		p, err := funcs.Proc(sp, s)
+		if err != nil {
+			return fmt.Errorf("specutil: cannot convert procedure %q: %w", sp.Name, err)
+		}
+		s.AddProcs(p)
+	}
 	// Link views' dependencies.
 	for v, refs := range viewDeps {
 		for i, r := range refs {
@@ -231,22 +276,11 @@ func Column(spec *sqlspec.Column, conv ConvertTypeFunc) (*schema.Column, error)
 		},
 	}
 	if d := spec.Default; !d.IsNull() {
-		switch {
-		case d.Type() == cty.String:
-			out.Default = &schema.Literal{V: d.AsString()}
-		case d.Type() == cty.Number:
-			out.Default = &schema.Literal{V: d.AsBigFloat().String()}
-		case d.Type() == cty.Bool:
-			out.Default = &schema.Literal{V: strconv.FormatBool(d.True())}
-		case d.Type().IsCapsuleType():
-			x, ok := d.EncapsulatedValue().(*schemahcl.RawExpr)
-			if !ok {
-				return nil, fmt.Errorf("invalid default value %q", d.Type().FriendlyName())
-			}
-			out.Default = &schema.RawExpr{X: x.X}
-		default:
-			return nil, fmt.Errorf("unsupported value type for default: %T", d)
+		x, err := defaultExpr(d)
+		if err != nil {
+			return nil, err
 		}
+		out.Default = x
 	}
 	ct, err := conv(spec)
 	if err != nil {
@@ -385,6 +419,211 @@ func linkForeignKeys(tbl *schema.Table, fks []*sqlspec.ForeignKey) error {
 	return nil
 }
 
+// defaultExpr converts the default value of a column or an argument to a schema.Expr.
+func defaultExpr(d cty.Value) (schema.Expr, error) {
+	switch {
+	case d.Type() == cty.String:
+		return &schema.Literal{V: d.AsString()}, nil
+	case d.Type() == cty.Number:
+		return &schema.Literal{V: d.AsBigFloat().String()}, nil
+	case d.Type() == cty.Bool:
+		return &schema.Literal{V: strconv.FormatBool(d.True())}, nil
+	case d.Type().IsCapsuleType():
+		x, ok := d.EncapsulatedValue().(*schemahcl.RawExpr)
+		if !ok {
+			return nil, fmt.Errorf("invalid default value %q", d.Type().FriendlyName())
+		}
+		return &schema.RawExpr{X: x.X}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type for default: %T", d)
+	}
+}
+
+// Func converts a sqlspec.Func to a schema.Func.
+func Func(spec *sqlspec.Func, parent *schema.Schema, conv ConvertTypeFunc) (*schema.Func, error) {
+	body, err := funcBody(spec.Extra, "function", spec.Name)
+	if err != nil {
+		return nil, err
+	}
+	f := &schema.Func{Name: spec.Name, Schema: parent, Lang: spec.Lang, Body: body}
+	if f.Args, err = funcArgs(spec.Args, conv); err != nil {
+		return nil, fmt.Errorf("specutil: function %q: %w", spec.Name, err)
+	}
+	switch {
+	case spec.Return != nil && len(spec.Columns) > 0:
+		return nil, fmt.Errorf("specutil: function %q cannot define both a return type and columns", spec.Name)
+	case spec.Return != nil:
+		if f.Ret, err = conv(&sqlspec.Column{Type: spec.Return}); err != nil {
+			return nil, fmt.Errorf("specutil: function %q return type: %w", spec.Name, err)
+		}
+	}
+	for _, c := range spec.Columns {
+		t, err := conv(c)
+		if err != nil {
+			return nil, fmt.Errorf("specutil: function %q column %q: %w", spec.Name, c.Name, err)
+		}
+		f.RetTable = append(f.RetTable, schema.NewColumn(c.Name).SetType(t))
+	}
+	if err := convertCommentFromSpec(spec, &f.Attrs); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Proc converts a sqlspec.Proc to a schema.Proc.
+func Proc(spec *sqlspec.Proc, parent *schema.Schema, conv ConvertTypeFunc) (*schema.Proc, error) {
+	body, err := funcBody(spec.Extra, "procedure", spec.Name)
+	if err != nil {
+		return nil, err
+	}
+	p := &schema.Proc{Name: spec.Name, Schema: parent, Lang: spec.Lang, Body: body}
+	if p.Args, err = funcArgs(spec.Args, conv); err != nil {
+		return nil, fmt.Errorf("specutil: procedure %q: %w", spec.Name, err)
+	}
+	if err := convertCommentFromSpec(spec, &p.Attrs); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// funcBody returns the body of a function or a procedure from its 'as' attribute.
+func funcBody(r schemahcl.Resource, typ, name string) (string, error) {
+	as, ok := r.Attr("as")
+	if !ok {
+		return "", fmt.Errorf("specutil: missing 'as' definition for %s %q", typ, name)
+	}
+	body, err := as.String()
+	if err != nil {
+		return "", fmt.Errorf("specutil: expect string definition for attribute %s.%s.as: %w", typ, name, err)
+	}
+	// Heredoc definitions end with a newline that is not part of the body.
+	return strings.TrimRight(body, "\n"), nil
+}
+
+// funcArgs converts the argument specs of a function or a procedure.
+func funcArgs(specs []*sqlspec.FuncArg, conv ConvertTypeFunc) ([]*schema.FuncArg, error) {
+	args := make([]*schema.FuncArg, 0, len(specs))
+	for _, s := range specs {
+		t, err := conv(&sqlspec.Column{Name: s.Name, Type: s.Type})
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", s.Name, err)
+		}
+		a := &schema.FuncArg{Name: s.Name, Type: t}
+		if m, ok := s.Attr("mode"); ok {
+			v, err := m.String()
+			if err != nil {
+				return nil, fmt.Errorf("argument %q mode: %w", s.Name, err)
+			}
+			a.Mode = schema.FuncArgMode(strings.ToUpper(v))
+		}
+		if d := s.Default; !d.IsNull() {
+			if a.Default, err = defaultExpr(d); err != nil {
+				return nil, fmt.Errorf("argument %q: %w", s.Name, err)
+			}
+		}
+		args = append(args, a)
+	}
+	return args, nil
+}
+
+// FromFunc converts a schema.Func to a sqlspec.Func.
+func FromFunc(f *schema.Func, typeSpec ColumnTypeSpecFunc) (*sqlspec.Func, error) {
+	args, err := fromFuncArgs(f.Args, typeSpec)
+	if err != nil {
+		return nil, fmt.Errorf("specutil: function %q: %w", f.Name, err)
+	}
+	spec := &sqlspec.Func{Name: f.Name, Lang: f.Lang, Args: args}
+	if f.Ret != nil {
+		ct, err := typeSpec(f.Ret)
+		if err != nil {
+			return nil, fmt.Errorf("specutil: function %q return type: %w", f.Name, err)
+		}
+		spec.Return = ct.Type
+	}
+	for _, c := range f.RetTable {
+		if c.Type == nil {
+			return nil, fmt.Errorf("specutil: missing type for column %q of function %q", c.Name, f.Name)
+		}
+		ct, err := typeSpec(c.Type.Type)
+		if err != nil {
+			return nil, fmt.Errorf("specutil: function %q column %q: %w", f.Name, c.Name, err)
+		}
+		spec.Columns = append(spec.Columns, &sqlspec.Column{Name: c.Name, Type: ct.Type})
+	}
+	convertCommentFromSchema(f.Attrs, &spec.Extra.Attrs)
+	spec.Extra.Attrs = append(spec.Extra.Attrs, bodyAttr(f.Body))
+	return spec, nil
+}
+
+// FromProc converts a schema.Proc to a sqlspec.Proc.
+func FromProc(p *schema.Proc, typeSpec ColumnTypeSpecFunc) (*sqlspec.Proc, error) {
+	args, err := fromFuncArgs(p.Args, typeSpec)
+	if err != nil {
+		return nil, fmt.Errorf("specutil: procedure %q: %w", p.Name, err)
+	}
+	spec := &sqlspec.Proc{Name: p.Name, Lang: p.Lang, Args: args}
+	convertCommentFromSchema(p.Attrs, &spec.Extra.Attrs)
+	spec.Extra.Attrs = append(spec.Extra.Attrs, bodyAttr(p.Body))
+	return spec, nil
+}
+
+// FromFuncs converts the functions and the procedures of the schema
+// to specs, and appends them to the spec of the schema.
+func FromFuncs(s *schema.Schema, spec *SchemaSpec, typeSpec ColumnTypeSpecFunc) error {
+	for _, f := range s.Funcs {
+		fs, err := FromFunc(f, typeSpec)
+		if err != nil {
+			return err
+		}
+		if s.Name != "" {
+			fs.Schema = SchemaRef(s.Name)
+		}
+		spec.Funcs = append(spec.Funcs, fs)
+	}
+	for _, p := range s.Procs {
+		ps, err := FromProc(p, typeSpec)
+		if err != nil {
+			return err
+		}
+		if s.Name != "" {
+			ps.Schema = SchemaRef(s.Name)
+		}
+		spec.Procs = append(spec.Procs, ps)
+	}
+	return nil
+}
+
+// fromFuncArgs converts the arguments of a function or a procedure to specs.
+func fromFuncArgs(args []*schema.FuncArg, typeSpec ColumnTypeSpecFunc) ([]*sqlspec.FuncArg, error) {
+	specs := make([]*sqlspec.FuncArg, 0, len(args))
+	for _, a := range args {
+		ct, err := typeSpec(a.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", a.Name, err)
+		}
+		s := &sqlspec.FuncArg{Name: a.Name, Type: ct.Type}
+		if a.Mode != "" && a.Mode != schema.FuncArgModeIn {
+			s.Extra.Attrs = append(s.Extra.Attrs, VarAttr("mode", string(a.Mode)))
+		}
+		if a.Default != nil {
+			if s.Default, err = ExprValue(a.Default); err != nil {
+				return nil, fmt.Errorf("argument %q: %w", a.Name, err)
+			}
+		}
+		specs = append(specs, s)
+	}
+	return specs, nil
+}
+
+// bodyAttr returns the 'as' attribute of a function or a procedure body. Similar
+// to views, multi-line bodies are formatted as indented heredoc with two spaces.
+func bodyAttr(body string) *schemahcl.Attr {
+	if lines := strings.Split(body, "\n"); len(lines) > 1 {
+		body = fmt.Sprintf("<<-SQL\n  %s\n  SQL", strings.Join(lines, "\n  "))
+	}
+	return schemahcl.StringAttr("as", body)
+}
+
 // FromSchema converts a schema.Schema into sqlspec.Schema and []sqlspec.Table.
 func FromSchema(s *schema.Schema, specT TableSpecFunc, specV ViewSpecFunc) (*SchemaSpec, error) {
 	var (
@@ -769,6 +1008,31 @@ func ColumnByRef(t *schema.Table, ref *schemahcl.Ref) (*schema.Column, error) {
 	return c, nil
 }
 
+// TableByRef returns a table from the realm by its reference.
+func TableByRef(r *schema.Realm, ref *schemahcl.Ref) (*schema.Table, error) {
+	qualifier, name, err := tableName(ref)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*schema.Table
+	for _, s := range r.Schemas {
+		if qualifier != "" && s.Name != qualifier {
+			continue
+		}
+		if t, ok := s.Table(name); ok {
+			matches = append(matches, t)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return nil, fmt.Errorf("specutil: referenced table %q not found", name)
+	default:
+		return nil, fmt.Errorf("specutil: multiple referenced tables found for %q", name)
+	}
+}
+
 func externalRef(ref *schemahcl.Ref, sch *schema.Schema) (*schema.Table, *schema.Column, error) {
 	qualifier, name, err := tableName(ref)
 	if err != nil {
@@ -879,6 +1143,17 @@ func qualifiedExternalColRef(cName, tName, sName string) *schemahcl.Ref {
 	})
 }
 
+// TableRef returns the reference of a table by its name and an optional qualifier.
+func TableRef(name, qualifier string) *schemahcl.Ref {
+	v := []string{name}
+	if qualifier != "" {
+		v = []string{qualifier, name}
+	}
+	return schemahcl.BuildRef([]schemahcl.PathIndex{
+		{T: "table", V: v},
+	})
+}
+
 // SchemaRef returns the schemahcl.Ref to the schema with the given name.
 func SchemaRef(name string) *schemahcl.Ref {
 	return schemahcl.BuildRef([]schemahcl.PathIndex{
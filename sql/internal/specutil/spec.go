@@ -27,18 +27,30 @@ type (
 		Schema *sqlspec.Schema
 		Tables []*sqlspec.Table
 		Views  []*sqlspec.View
+		Funcs  []*sqlspec.Func
+		Procs  []*sqlspec.Proc
+		// Extra holds driver-specific blocks (e.g. SQLite virtual tables)
+		// that are marshaled after the functions and before the schemas.
+		Extra []*schemahcl.Resource
 	}
+	// doc holds the schema blocks in the remaining children,
+	// after any extra blocks defined by the driver.
 	doc struct {
-		Tables  []*sqlspec.Table  `spec:"table"`
-		Views   []*sqlspec.View   `spec:"view"`
-		Schemas []*sqlspec.Schema `spec:"schema"`
+		Tables []*sqlspec.Table `spec:"table"`
+		Views  []*sqlspec.View  `spec:"view"`
+		Funcs  []*sqlspec.Func  `spec:"function"`
+		Procs  []*sqlspec.Proc  `spec:"procedure"`
+		schemahcl.DefaultExtension
 	}
 )
 
 // Marshal marshals v into an Atlas DDL document using a schemahcl.Marshaler. Marshal uses the given
 // schemaSpec function to convert a *schema.Schema into *sqlspec.Schema, []*sqlspec.Table and []*sqlspec.View.
 func Marshal(v any, marshaler schemahcl.Marshaler, convertFunc func(*schema.Schema) (*SchemaSpec, error)) ([]byte, error) {
-	d := &doc{}
+	var (
+		d       = &doc{}
+		schemas []*sqlspec.Schema
+	)
 	switch s := v.(type) {
 	case *schema.Schema:
 		spec, err := convertFunc(s)
@@ -47,7 +59,10 @@ func Marshal(v any, marshaler schemahcl.Marshaler, convertFunc func(*schema.Sche
 		}
 		d.Tables = spec.Tables
 		d.Views = spec.Views
-		d.Schemas = []*sqlspec.Schema{spec.Schema}
+		d.Funcs = spec.Funcs
+		d.Procs = spec.Procs
+		d.Extra.Children = spec.Extra
+		schemas = []*sqlspec.Schema{spec.Schema}
 	case *schema.Realm:
 		for _, s := range s.Schemas {
 			spec, err := convertFunc(s)
@@ -56,7 +71,10 @@ func Marshal(v any, marshaler schemahcl.Marshaler, convertFunc func(*schema.Sche
 			}
 			d.Tables = append(d.Tables, spec.Tables...)
 			d.Views = append(d.Views, spec.Views...)
-			d.Schemas = append(d.Schemas, spec.Schema)
+			d.Funcs = append(d.Funcs, spec.Funcs...)
+			d.Procs = append(d.Procs, spec.Procs...)
+			d.Extra.Children = append(d.Extra.Children, spec.Extra...)
+			schemas = append(schemas, spec.Schema)
 		}
 		if err := QualifyTables(d.Tables); err != nil {
 			return nil, err
@@ -64,15 +82,36 @@ func Marshal(v any, marshaler schemahcl.Marshaler, convertFunc func(*schema.Sche
 		if err := QualifyViews(d.Views); err != nil {
 			return nil, err
 		}
+		if err := QualifyFuncs(d.Funcs, d.Procs); err != nil {
+			return nil, err
+		}
 		if err := QualifyReferences(d.Tables, s); err != nil {
 			return nil, err
 		}
 	default:
 		return nil, fmt.Errorf("specutil: failed marshaling spec. %T is not supported", v)
 	}
+	for _, s := range schemas {
+		r, err := ExtraBlock("schema", s)
+		if err != nil {
+			return nil, err
+		}
+		d.Extra.Children = append(d.Extra.Children, r)
+	}
 	return marshaler.MarshalSpec(d)
 }
 
+// ExtraBlock scans the given spec into a block of the given type,
+// to be added to the Extra blocks of a SchemaSpec.
+func ExtraBlock(typ string, spec any) (*schemahcl.Resource, error) {
+	r := &schemahcl.Resource{}
+	if err := r.Scan(spec); err != nil {
+		return nil, fmt.Errorf("specutil: failed scanning %s block: %w", typ, err)
+	}
+	r.Type = typ
+	return r, nil
+}
+
 // QualifyTables sets the Qualifier field equal to the schema
 // name in any tables with duplicate names in the provided specs.
 func QualifyTables(specs []*sqlspec.Table) error {
@@ -117,6 +156,44 @@ func QualifyViews(specs []*sqlspec.View) error {
 	return nil
 }
 
+// QualifyFuncs sets the Qualifier field equal to the schema name in any
+// functions or procedures with duplicate names in the provided specs.
+func QualifyFuncs(funcs []*sqlspec.Func, procs []*sqlspec.Proc) error {
+	seenF := make(map[string]*sqlspec.Func, len(funcs))
+	for _, f := range funcs {
+		if s, ok := seenF[f.Name]; ok {
+			schemaName, err := SchemaName(s.Schema)
+			if err != nil {
+				return err
+			}
+			s.Qualifier = schemaName
+			schemaName, err = SchemaName(f.Schema)
+			if err != nil {
+				return err
+			}
+			f.Qualifier = schemaName
+		}
+		seenF[f.Name] = f
+	}
+	seenP := make(map[string]*sqlspec.Proc, len(procs))
+	for _, p := range procs {
+		if s, ok := seenP[p.Name]; ok {
+			schemaName, err := SchemaName(s.Schema)
+			if err != nil {
+				return err
+			}
+			s.Qualifier = schemaName
+			schemaName, err = SchemaName(p.Schema)
+			if err != nil {
+				return err
+			}
+			p.Qualifier = schemaName
+		}
+		seenP[p.Name] = p
+	}
+	return nil
+}
+
 // QualifyReferences qualifies any reference with qualifier.
 func QualifyReferences(tableSpecs []*sqlspec.Table, realm *schema.Realm) error {
 	type cref struct{ s, t string }
@@ -7,6 +7,7 @@ package sqlx
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
@@ -24,6 +25,20 @@ type DevDriver struct {
 	// PathObject allows providing a custom function to patch
 	// objects that hold a schema reference.
 	PatchObject func(*schema.Schema, schema.Object)
+	// SkipObject allows providing a custom function to skip schema objects
+	// that cannot be created in the dev database (e.g. objects that depend on
+	// realm-level objects). Skipped objects are not normalized, and they are
+	// added as-is to the schemas of the normalized realm.
+	SkipObject func(schema.Object) bool
+	// RelinkObject allows providing a custom function to link realm-level
+	// objects to the schema objects of the normalized realm. The function
+	// returns a linked copy of the object, and should not modify it.
+	RelinkObject func(*schema.Realm, schema.Object) (schema.Object, error)
+	// CarryAttr allows providing a custom function to select schema and table
+	// attributes that are not applied to the dev database (e.g. privileges that
+	// are granted to roles that might not exist there). Selected attributes are
+	// carried over as-is to the schemas and tables of the normalized result.
+	CarryAttr func(schema.Attr) bool
 }
 
 // NormalizeRealm implements the schema.Normalizer interface.
@@ -49,11 +64,20 @@ func (d *DevDriver) NormalizeRealm(ctx context.Context, r *schema.Realm) (nr *sc
 	}()
 	var (
 		changes []schema.Change
+		applied = r
+		skipped = make(map[string][]schema.Object)
 		opts    = &schema.InspectRealmOption{
 			Schemas: make([]string, 0, len(r.Schemas)),
 		}
 	)
-	for _, s := range r.Schemas {
+	// Attributes that are carried over are removed from a copy of the realm.
+	if d.CarryAttr != nil {
+		applied = r.Clone()
+		for _, s := range applied.Schemas {
+			d.stripAttrs(s)
+		}
+	}
+	for _, s := range applied.Schemas {
 		opts.Schemas = append(opts.Schemas, s.Name)
 		changes = append(changes, &schema.AddSchema{
 			S: s,
@@ -70,14 +94,50 @@ func (d *DevDriver) NormalizeRealm(ctx context.Context, r *schema.Realm) (nr *sc
 			changes = append(changes, &schema.AddView{V: v})
 		}
 		for _, o := range s.Objects {
+			if d.SkipObject != nil && d.SkipObject(o) {
+				skipped[s.Name] = append(skipped[s.Name], o)
+				continue
+			}
 			changes = append(changes, &schema.AddObject{O: o})
 		}
 	}
 	if err := d.Driver.ApplyChanges(ctx, changes); err != nil {
 		return nil, err
 	}
-	nr, err = d.Driver.InspectRealm(ctx, opts)
-	return
+	if nr, err = d.Driver.InspectRealm(ctx, opts); err != nil {
+		return nil, err
+	}
+	for name, objs := range skipped {
+		s, ok := nr.Schema(name)
+		if !ok {
+			return nil, fmt.Errorf("schema %q was not found in the dev database", name)
+		}
+		s.AddObjects(objs...)
+	}
+	for _, s := range r.Schemas {
+		if ns, ok := nr.Schema(s.Name); ok {
+			d.carryAttrs(s, ns)
+		}
+	}
+	// Realm-level objects (e.g. publications) are not created in the dev database,
+	// and are therefore kept as-is, but their references to schema objects (e.g.
+	// tables) are relinked to the objects of the normalized realm.
+	for _, o := range r.Objects {
+		if d.RelinkObject != nil {
+			if o, err = d.RelinkObject(nr, o); err != nil {
+				return nil, err
+			}
+		}
+		nr.Objects = append(nr.Objects, o)
+	}
+	// Realm attributes that are not returned by the
+	// dev inspection are preserved in their given form.
+	for _, a := range r.Attrs {
+		if !hasAttr(nr.Attrs, a) {
+			nr.Attrs = append(nr.Attrs, a)
+		}
+	}
+	return nr, nil
 }
 
 // NormalizeSchema returns the normal representation of the given database. See NormalizeRealm for more info.
@@ -103,41 +163,60 @@ func (d *DevDriver) NormalizeSchema(ctx context.Context, s *schema.Schema) (*sch
 	if err != nil {
 		return nil, err
 	}
+	// The schema is created in the dev schema. Hence, it is applied using a copy
+	// to avoid modifying the given schema and its objects. Note, tables and views
+	// are linked to the copy when it is cloned.
+	var (
+		skipped []schema.Object
+		objects []schema.Change
+		sc      = s.Clone()
+		copies  = make(map[schema.Object]schema.Object)
+	)
+	sc.Name = dev.Name
+	d.stripAttrs(sc)
 	// Modify dev-schema attributes if needed.
 	changes, err := d.Driver.SchemaDiff(
 		schema.New(dev.Name).AddAttrs(dev.Attrs...),
-		schema.New(dev.Name).AddAttrs(s.Attrs...),
+		schema.New(dev.Name).AddAttrs(sc.Attrs...),
 	)
 	if err != nil {
 		return nil, err
 	}
-	prevName := s.Name
-	s.Name = dev.Name
-	for _, t := range s.Tables {
-		// If objects are not strongly connected.
-		if t.Schema != s {
-			t.Schema = s
+	for i, o := range sc.Objects {
+		if d.SkipObject != nil && d.SkipObject(o) {
+			skipped = append(skipped, o)
+			continue
+		}
+		if oc, ok := copyObject(o); ok {
+			copies[o], sc.Objects[i] = oc, oc
 		}
+		if d.PatchObject != nil {
+			d.PatchObject(sc, sc.Objects[i])
+		}
+		objects = append(objects, &schema.AddObject{O: sc.Objects[i]})
+	}
+	for _, t := range sc.Tables {
 		for _, c := range t.Columns {
-			if e, ok := c.Type.Type.(*schema.EnumType); ok && e.Schema != s {
-				e.Schema = s
+			if c.Type == nil {
+				continue
+			}
+			if o, ok := c.Type.Type.(schema.Object); ok && isPointer(o) {
+				if oc, ok := copies[o].(schema.Type); ok {
+					c.Type.Type = oc
+				}
+			}
+			if e, ok := c.Type.Type.(*schema.EnumType); ok && e.Schema != sc {
+				ec := *e
+				ec.Schema = sc
+				c.Type.Type = &ec
 			}
 		}
 		changes = append(changes, &schema.AddTable{T: t})
 	}
-	for _, v := range s.Views {
-		// If objects are not strongly connected.
-		if v.Schema != s {
-			v.Schema = s
-		}
+	for _, v := range sc.Views {
 		changes = append(changes, &schema.AddView{V: v})
 	}
-	for _, o := range s.Objects {
-		if d.PatchObject != nil {
-			d.PatchObject(s, o)
-		}
-		changes = append(changes, &schema.AddObject{O: o})
-	}
+	changes = append(changes, objects...)
 	if err := d.Driver.ApplyChanges(ctx, changes, func(opts *migrate.PlanOptions) {
 		noQualifier := ""
 		opts.SchemaQualifier = &noQualifier
@@ -149,10 +228,80 @@ func (d *DevDriver) NormalizeSchema(ctx context.Context, s *schema.Schema) (*sch
 	if err != nil {
 		return nil, err
 	}
+	ns.AddObjects(skipped...)
 	// Preserve the original schema name and attributes.
-	ns.Name = prevName
-	for _, a := range s.Attrs {
+	ns.Name = s.Name
+	for _, a := range sc.Attrs {
 		schema.ReplaceOrAppend(&ns.Attrs, a)
 	}
+	d.carryAttrs(s, ns)
 	return ns, err
 }
+
+// stripAttrs removes the attributes that are carried over
+// from the given schema and its tables. Note, the schema is
+// expected to be a copy, as its attributes are modified.
+func (d *DevDriver) stripAttrs(s *schema.Schema) {
+	if d.CarryAttr == nil {
+		return
+	}
+	s.Attrs = d.filterAttrs(s.Attrs, false)
+	for _, t := range s.Tables {
+		t.Attrs = d.filterAttrs(t.Attrs, false)
+	}
+}
+
+// carryAttrs carries the selected attributes of the given
+// schema and its tables over to their normalized forms.
+func (d *DevDriver) carryAttrs(from, to *schema.Schema) {
+	if d.CarryAttr == nil {
+		return
+	}
+	to.Attrs = append(to.Attrs, d.filterAttrs(from.Attrs, true)...)
+	for _, t1 := range from.Tables {
+		if t2, ok := to.Table(t1.Name); ok {
+			t2.Attrs = append(t2.Attrs, d.filterAttrs(t1.Attrs, true)...)
+		}
+	}
+}
+
+// filterAttrs returns the attributes that are selected (or
+// not selected) by the CarryAttr function.
+func (d *DevDriver) filterAttrs(attrs []schema.Attr, carry bool) []schema.Attr {
+	var f []schema.Attr
+	for _, a := range attrs {
+		if d.CarryAttr(a) == carry {
+			f = append(f, a)
+		}
+	}
+	return f
+}
+
+// hasAttr reports if the attributes contain an attribute of the same type as a.
+func hasAttr(attrs []schema.Attr, a schema.Attr) bool {
+	t := reflect.TypeOf(a)
+	for i := range attrs {
+		if reflect.TypeOf(attrs[i]) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// copyObject returns a shallow copy of the given object, if it is a struct pointer.
+func copyObject(o schema.Object) (schema.Object, bool) {
+	if !isPointer(o) || reflect.ValueOf(o).Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	v := reflect.ValueOf(o)
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	oc, ok := c.Interface().(schema.Object)
+	return oc, ok
+}
+
+// isPointer reports if v holds a non-nil pointer, and can be used as a map key.
+func isPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && !rv.IsNil()
+}
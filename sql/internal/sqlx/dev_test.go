@@ -36,6 +36,158 @@ func TestDriver_NormalizeRealm(t *testing.T) {
 		// operation noop for schema like "public" in Postgres.
 		Extra: []schema.Clause{&schema.IfNotExists{}},
 	}, drv.changes[0])
+
+	// Realm attributes are preserved.
+	r.Attrs = []schema.Attr{&schema.Comment{Text: "realm"}}
+	normal, err = dev.NormalizeRealm(context.Background(), r)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "realm"}}, normal.Attrs)
+
+	// Attributes returned by the dev database are not overridden.
+	drv.realm.Attrs = []schema.Attr{&schema.Charset{V: "utf8mb4"}}
+	r.Attrs = []schema.Attr{&schema.Charset{V: "utf8"}, &schema.Comment{Text: "realm"}}
+	normal, err = dev.NormalizeRealm(context.Background(), r)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Attr{&schema.Charset{V: "utf8mb4"}, &schema.Comment{Text: "realm"}}, normal.Attrs)
+
+	// Realm-level objects are kept, and relinked to the normalized realm.
+	var (
+		skip = &skipObject{}
+		link = &skipObject{}
+	)
+	r.Attrs, r.Objects = nil, []schema.Object{skip}
+	dev.RelinkObject = func(nr *schema.Realm, o schema.Object) (schema.Object, error) {
+		require.True(t, nr == drv.realm)
+		return link, nil
+	}
+	normal, err = dev.NormalizeRealm(context.Background(), r)
+	require.NoError(t, err)
+	require.True(t, normal.Objects[len(normal.Objects)-1] == schema.Object(link))
+	require.Equal(t, []schema.Object{skip}, r.Objects)
+}
+
+func TestDriver_NormalizeRealmSkipObjects(t *testing.T) {
+	var (
+		drv = &mockDriver{
+			realm: schema.NewRealm(schema.New("test")),
+		}
+		dev = &DevDriver{
+			Driver: drv,
+			SkipObject: func(o schema.Object) bool {
+				_, ok := o.(*skipObject)
+				return ok
+			},
+		}
+		enum = &schema.EnumType{T: "status", Values: []string{"a"}}
+		skip = &skipObject{}
+		r    = schema.NewRealm(schema.New("test").AddObjects(enum, skip))
+	)
+	normal, err := dev.NormalizeRealm(context.Background(), r)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.AddSchema{S: r.Schemas[0], Extra: []schema.Clause{&schema.IfNotExists{}}},
+		&schema.AddObject{O: enum},
+	}, drv.changes, "skipped objects are not created in the dev database")
+	require.Equal(t, []schema.Object{skip}, normal.Schemas[0].Objects)
+
+	drv = &mockDriver{
+		realm: schema.NewRealm(schema.New("dev")),
+	}
+	dev.Driver = drv
+	ns, err := dev.NormalizeSchema(context.Background(), r.Schemas[0])
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.AddObject{O: enum}}, drv.changes)
+	require.Equal(t, []schema.Object{skip}, ns.Objects)
+}
+
+func TestDriver_NormalizeCarryAttrs(t *testing.T) {
+	var (
+		drv = &mockDriver{
+			realm: schema.NewRealm(schema.New("test").AddTables(schema.NewTable("t"))),
+		}
+		dev = &DevDriver{
+			Driver: drv,
+			CarryAttr: func(a schema.Attr) bool {
+				_, ok := a.(*carryAttr)
+				return ok
+			},
+		}
+		a1, a2, a3 = &carryAttr{V: "1"}, &carryAttr{V: "2"}, &carryAttr{V: "3"}
+		r          = schema.NewRealm(
+			schema.New("test").
+				AddAttrs(a1, a2, &schema.Comment{Text: "c"}).
+				AddTables(schema.NewTable("t").AddAttrs(a3)),
+		)
+	)
+	normal, err := dev.NormalizeRealm(context.Background(), r)
+	require.NoError(t, err)
+	require.Len(t, drv.changes, 2)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "c"}}, drv.changes[0].(*schema.AddSchema).S.Attrs, "carried attributes are not applied")
+	require.Empty(t, drv.changes[1].(*schema.AddTable).T.Attrs)
+	require.Equal(t, []schema.Attr{a1, a2}, normal.Schemas[0].Attrs)
+	require.Equal(t, []schema.Attr{a3}, normal.Schemas[0].Tables[0].Attrs)
+	// The given realm is not modified.
+	require.Equal(t, []schema.Attr{a1, a2, &schema.Comment{Text: "c"}}, r.Schemas[0].Attrs)
+	require.Equal(t, []schema.Attr{a3}, r.Schemas[0].Tables[0].Attrs)
+
+	drv = &mockDriver{
+		realm: schema.NewRealm(schema.New("dev").AddTables(schema.NewTable("t"))),
+	}
+	dev.Driver = drv
+	ns, err := dev.NormalizeSchema(context.Background(), r.Schemas[0])
+	require.NoError(t, err)
+	require.Len(t, drv.changes, 1)
+	require.Empty(t, drv.changes[0].(*schema.AddTable).T.Attrs)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "c"}, a1, a2}, ns.Attrs)
+	require.Equal(t, []schema.Attr{a3}, ns.Tables[0].Attrs)
+}
+
+type carryAttr struct {
+	schema.Attr
+	V string
+}
+
+type skipObject struct {
+	schema.Object
+}
+
+func TestDriver_NormalizeSchema(t *testing.T) {
+	var (
+		drv = &mockDriver{
+			realm: schema.NewRealm(schema.New("dev").AddTables(schema.NewTable("t"))),
+		}
+		dev = &DevDriver{
+			Driver: drv,
+		}
+		enum = &schema.EnumType{T: "status", Values: []string{"a"}}
+		s    = schema.New("test").
+			SetComment("c").
+			AddTables(schema.NewTable("t").AddColumns(schema.NewEnumColumn("c", schema.EnumName("status"), schema.EnumValues("a")))).
+			AddObjects(enum)
+	)
+	s.Tables[0].Columns[0].Type.Type = enum
+	dev.PatchObject = func(s *schema.Schema, o schema.Object) {
+		if e, ok := o.(*schema.EnumType); ok {
+			e.Schema = s
+		}
+	}
+	normal, err := dev.NormalizeSchema(context.Background(), s)
+	require.NoError(t, err)
+	require.Equal(t, "test", normal.Name)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "c"}}, normal.Attrs)
+	require.Len(t, drv.changes, 2)
+	add := drv.changes[0].(*schema.AddTable)
+	require.Equal(t, "t", add.T.Name)
+	require.Equal(t, "dev", add.T.Schema.Name)
+	e := drv.changes[1].(*schema.AddObject).O.(*schema.EnumType)
+	require.True(t, e.Schema == add.T.Schema)
+	require.True(t, add.T.Columns[0].Type.Type == e, "columns are linked to the patched objects")
+
+	// The given schema and its objects are not modified.
+	require.Equal(t, "test", s.Name)
+	require.True(t, s.Tables[0].Schema == s)
+	require.True(t, s.Tables[0].Columns[0].Type.Type == enum)
+	require.Nil(t, enum.Schema)
 }
 
 type mockDriver struct {
@@ -52,6 +204,16 @@ func (m *mockDriver) InspectRealm(_ context.Context, opts *schema.InspectRealmOp
 	return m.realm, nil
 }
 
+func (m *mockDriver) InspectSchema(context.Context, string, *schema.InspectOptions) (*schema.Schema, error) {
+	s := *m.realm.Schemas[0]
+	s.Attrs = nil
+	return &s, nil
+}
+
+func (m *mockDriver) SchemaDiff(_, _ *schema.Schema, _ ...schema.DiffOption) ([]schema.Change, error) {
+	return nil, nil
+}
+
 func (m *mockDriver) ApplyChanges(_ context.Context, changes []schema.Change, _ ...migrate.PlanOption) error {
 	m.changes = append(m.changes, changes...)
 	return nil
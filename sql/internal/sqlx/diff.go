@@ -81,11 +81,61 @@ type (
 		FindTable(*schema.Schema, string) (*schema.Table, error)
 	}
 
-	// ChangesAnnotator is an optional interface allows DiffDriver to annotate
+	// FuncDiffer is an optional interface allowing DiffDriver to report if a
+	// function was changed, instead of comparing its arguments, return type,
+	// language and body. For example, in order to compare types by their
+	// names, or to ignore the formatting of the body.
+	FuncDiffer interface {
+		FuncChanged(from, to *schema.Func) bool
+	}
+
+	// ProcDiffer is like FuncDiffer, but for procedures.
+	ProcDiffer interface {
+		ProcChanged(from, to *schema.Proc) bool
+	}
+
+	// TriggerDiffer is an optional interface allowing DiffDriver to report if
+	// a trigger was changed, instead of comparing its action time, events,
+	// FOR EACH clause and body. For example, in order to ignore formatting.
+	TriggerDiffer interface {
+		TriggerChanged(from, to *schema.Trigger) bool
+	}
+
+	// ChangesAnnotator is an optional interface that allows DiffDriver to annotate
 	// changes with additional driver-specific attributes before they are returned.
 	ChangesAnnotator interface {
 		AnnotateChanges([]schema.Change, *schema.DiffOptions) error
 	}
+
+	// ViewDefDiffer is an optional interface that allows DiffDriver to compare
+	// view definitions in a dialect-specific way. For example, ignoring their
+	// formatting in case the database stores them as-is.
+	ViewDefDiffer interface {
+		ViewDefChanged(from, to *schema.View) bool
+	}
+
+	// IndexCommentDiffer is an optional interface that allows DiffDriver to report if
+	// the comment of an index was changed, instead of comparing their comment attributes.
+	// For example, databases that do not support comments and never report them on
+	// inspection, should not report comment changes.
+	IndexCommentDiffer interface {
+		IndexCommentChanged(from, to *schema.Index) bool
+	}
+
+	// ConversionClassifier is an optional interface that allows DiffDriver to classify
+	// the conversion of column values, when the type of a column is changed. The result
+	// is attached to the ModifyColumn change, allowing linters and executors to detect
+	// lossy changes. For example, changing a varchar(255) column to varchar(50).
+	ConversionClassifier interface {
+		TypeConversion(from, to *schema.Column) schema.TypeConversion
+	}
+
+	// RealmObjectDiffer is an optional interface that allows DiffDriver to diff objects
+	// that are not bound to a specific schema (e.g. foreign servers). The returned
+	// object drops are applied after all other changes in the realm.
+	RealmObjectDiffer interface {
+		RealmObjectDiff(from, to *schema.Realm) ([]schema.Change, error)
+	}
 )
 
 // RealmDiff implements the schema.Differ for Realm objects and returns a list of changes
@@ -93,35 +143,272 @@ type (
 func (d *Diff) RealmDiff(from, to *schema.Realm, options ...schema.DiffOption) ([]schema.Change, error) {
 	var (
 		changes schema.Changes
+		dropO   []schema.Change
 		opts    = schema.NewDiffOptions(options...)
 	)
-	// Drop or modify schema.
-	for _, s1 := range from.Schemas {
+	// Add, drop or modify realm-level objects.
+	if od, ok := d.DiffDriver.(RealmObjectDiffer); ok {
+		change, err := od.RealmObjectDiff(from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range change {
+			if _, ok := c.(*schema.DropObject); ok {
+				dropO = append(dropO, c)
+			} else {
+				changes = opts.AddOrSkip(changes, c)
+			}
+		}
+	}
+	// Tables that were moved between schemas are diffed separately, after
+	// their new schemas were created and before their old ones are dropped.
+	moved, err := d.movedTables(from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	var moves, dropS []schema.Change
+	// Drop, rename or modify schema.
+	renamed := make(map[string]bool)
+	for _, s1 := range ordered(from.Schemas, schemaN) {
 		s2, ok := to.Schema(s1.Name)
 		if !ok {
-			changes = opts.AddOrSkip(changes, &schema.DropSchema{S: s1})
-			continue
+			s2, ok = renamedTo(from, to, s1, opts)
+			if !ok {
+				change, err := d.moveTables(s1, s1, moved, opts)
+				if err != nil {
+					return nil, err
+				}
+				if len(change) > 0 {
+					moves = append(moves, change...)
+					dropS = opts.AddOrSkip(dropS, &schema.DropSchema{S: s1})
+				} else {
+					changes = opts.AddOrSkip(changes, &schema.DropSchema{S: s1})
+				}
+				continue
+			}
+			renamed[s2.Name] = true
+			changes = opts.AddOrSkip(changes, &schema.RenameSchema{From: s1, To: s2})
+		}
+		s1c := withoutTables(s1, moved)
+		if s1.Name != s2.Name {
+			// The rest of the changes are computed as if the schema
+			// was already renamed, as they are executed after it.
+			s1c = schemaAs(s1c, s2.Name)
 		}
-		change, err := d.schemaDiff(s1, s2, opts)
+		change, err := d.moveTables(s1, s1c, moved, opts)
 		if err != nil {
 			return nil, err
 		}
+		moves = append(moves, change...)
+		if change, err = d.schemaDiff(s1c, withoutTables(s2, moved), opts); err != nil {
+			return nil, err
+		}
 		changes = append(changes, change...)
 	}
 	// Add schemas.
-	for _, s1 := range to.Schemas {
-		if _, ok := from.Schema(s1.Name); ok {
+	for _, s1 := range ordered(to.Schemas, schemaN) {
+		if _, ok := from.Schema(s1.Name); ok || renamed[s1.Name] {
 			continue
 		}
 		changes = opts.AddOrSkip(changes, &schema.AddSchema{S: s1})
-		for _, t := range s1.Tables {
+		s1 = withoutTables(s1, moved)
+		seqs, _ := sequenceDiff(schema.New(s1.Name), s1, opts)
+		changes = opts.AddOrSkip(changes, seqs...)
+		tables, views := orderedTables(opts, s1.Tables), ordered(s1.Views, viewN)
+		for _, t := range tables {
 			changes = opts.AddOrSkip(changes, &schema.AddTable{T: t})
 		}
-		for _, v := range s1.Views {
+		for _, v := range views {
 			changes = opts.AddOrSkip(changes, &schema.AddView{V: v})
 		}
+		changes = opts.AddOrSkip(changes, d.funcDiff(schema.New(s1.Name), s1, opts)...)
+		for _, t := range tables {
+			changes = opts.AddOrSkip(changes, d.triggerDiff(nil, t.Triggers, opts)...)
+		}
+		for _, v := range views {
+			changes = opts.AddOrSkip(changes, d.triggerDiff(nil, v.Triggers, opts)...)
+		}
+	}
+	changes = append(changes, moves...)
+	changes = append(changes, dropS...)
+	changes = opts.AddOrSkip(changes, dropO...)
+	return d.mayAnnotate(ignoreAttrs(changes, opts), opts)
+}
+
+// movedTables returns the tables of the current state that were moved to another schema in
+// the desired state, either by the hints of the options, or by the detection heuristic. The
+// detected moves are recorded in the options, as foreign keys follow the tables they reference.
+func (d *Diff) movedTables(from, to *schema.Realm, opts *schema.DiffOptions) (map[*schema.Table]*schema.Table, error) {
+	if len(opts.MoveTables) == 0 && !opts.DetectRenames || opts.Skipped(&schema.RenameTable{}) {
+		return nil, nil
+	}
+	var dropped, added []*schema.Table
+	for _, s1 := range from.Schemas {
+		s2, ok := to.Schema(s1.Name)
+		if !ok {
+			s2, ok = renamedTo(from, to, s1, opts)
+		}
+		for _, t1 := range s1.Tables {
+			if !ok {
+				dropped = append(dropped, t1)
+				continue
+			}
+			switch _, err := d.findTable(s2, t1.Name, opts); {
+			case schema.IsNotExistError(err):
+				dropped = append(dropped, t1)
+			case err != nil:
+				return nil, err
+			}
+		}
 	}
-	return d.mayAnnotate(changes, opts)
+	for _, s2 := range to.Schemas {
+		s1, ok := from.Schema(s2.Name)
+		for _, r := range from.Schemas {
+			if s, renamed := renamedTo(from, to, r, opts); renamed && s == s2 {
+				s1, ok = r, true
+			}
+		}
+		for _, t2 := range s2.Tables {
+			if !ok {
+				added = append(added, t2)
+				continue
+			}
+			switch _, err := d.findTable(s1, t2.Name, opts); {
+			case schema.IsNotExistError(err):
+				added = append(added, t2)
+			case err != nil:
+				return nil, err
+			}
+		}
+	}
+	moved := hintedRenames(dropped, added, opts.MoveTables, qualifiedName)
+	if opts.DetectRenames {
+		detectRenames(dropped, added, moved, func(t1, t2 *schema.Table) bool {
+			return t1.Name == t2.Name && qualifiedName(t1) != qualifiedName(t2) && d.similarTables(t1, t2, opts)
+		})
+	}
+	for t1, t2 := range moved {
+		if opts.MoveTables == nil {
+			opts.MoveTables = make(map[string]string)
+		}
+		opts.MoveTables[qualifiedName(t1)] = qualifiedName(t2)
+	}
+	return moved, nil
+}
+
+// moveTables returns the changes for moving the tables of the schema s to other schemas.
+// The schema c is the schema the tables reside in when the changes are executed. e.g., a
+// renamed schema.
+func (d *Diff) moveTables(s, c *schema.Schema, moved map[*schema.Table]*schema.Table, opts *schema.DiffOptions) ([]schema.Change, error) {
+	var changes []schema.Change
+	for _, t1 := range orderedTables(opts, s.Tables) {
+		t2, ok := moved[t1]
+		if !ok {
+			continue
+		}
+		if c.Name != s.Name {
+			tc := *t1
+			tc.Schema = c
+			t1 = &tc
+		}
+		changes = opts.AddOrSkip(changes, &schema.RenameTable{From: t1, To: t2})
+		change, err := d.tableDiff(t1, t2, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(change) > 0 {
+			changes = opts.AddOrSkip(changes, &schema.ModifyTable{
+				T:       t2,
+				Changes: change,
+			})
+		}
+		changes = opts.AddOrSkip(changes, d.triggerDiff(t1.Triggers, t2.Triggers, opts)...)
+	}
+	return changes, nil
+}
+
+// withoutTables returns a shallow copy of the schema without its tables that were moved
+// from or to it. The schema itself is returned in case none of its tables were moved.
+func withoutTables(s *schema.Schema, moved map[*schema.Table]*schema.Table) *schema.Schema {
+	if len(moved) == 0 {
+		return s
+	}
+	to := make(map[*schema.Table]bool, len(moved))
+	for _, t2 := range moved {
+		to[t2] = true
+	}
+	tables := make([]*schema.Table, 0, len(s.Tables))
+	for _, t := range s.Tables {
+		if _, ok := moved[t]; !ok && !to[t] {
+			tables = append(tables, t)
+		}
+	}
+	if len(tables) == len(s.Tables) {
+		return s
+	}
+	c := *s
+	c.Tables = tables
+	return &c
+}
+
+// qualifiedName returns the name of the table, qualified with its schema name.
+func qualifiedName(t *schema.Table) string {
+	if t.Schema == nil || t.Schema.Name == "" {
+		return t.Name
+	}
+	return t.Schema.Name + "." + t.Name
+}
+
+// renamedTo returns the schema in the desired state that the given schema is
+// renamed to, if it was hinted as renamed and the new name does not exist in
+// the current state.
+func renamedTo(from, to *schema.Realm, s *schema.Schema, opts *schema.DiffOptions) (*schema.Schema, bool) {
+	name, ok := opts.RenameSchemas[s.Name]
+	if !ok {
+		return nil, false
+	}
+	if _, ok := from.Schema(name); ok {
+		return nil, false
+	}
+	return to.Schema(name)
+}
+
+// schemaAs returns a shallow copy of the schema, its tables, views, functions,
+// procedures and sequences, as if it was named after the given name.
+func schemaAs(s *schema.Schema, name string) *schema.Schema {
+	c := *s
+	c.Name = name
+	c.Tables = make([]*schema.Table, len(s.Tables))
+	for i, t := range s.Tables {
+		tc := *t
+		tc.Schema = &c
+		c.Tables[i] = &tc
+	}
+	c.Views = make([]*schema.View, len(s.Views))
+	for i, v := range s.Views {
+		vc := *v
+		vc.Schema = &c
+		c.Views[i] = &vc
+	}
+	c.Funcs = make([]*schema.Func, len(s.Funcs))
+	for i, f := range s.Funcs {
+		fc := *f
+		fc.Schema = &c
+		c.Funcs[i] = &fc
+	}
+	c.Procs = make([]*schema.Proc, len(s.Procs))
+	for i, p := range s.Procs {
+		pc := *p
+		pc.Schema = &c
+		c.Procs[i] = &pc
+	}
+	c.Sequences = make([]*schema.Sequence, len(s.Sequences))
+	for i, sq := range s.Sequences {
+		sc := *sq
+		sc.Schema = &c
+		c.Sequences[i] = &sc
+	}
+	return &c
 }
 
 // SchemaDiff implements the schema.Differ interface and returns a list of
@@ -132,7 +419,7 @@ func (d *Diff) SchemaDiff(from, to *schema.Schema, options ...schema.DiffOption)
 	if err != nil {
 		return nil, err
 	}
-	return d.mayAnnotate(changes, opts)
+	return d.mayAnnotate(ignoreAttrs(changes, opts), opts)
 }
 
 func (d *Diff) schemaDiff(from, to *schema.Schema, opts *schema.DiffOptions) ([]schema.Change, error) {
@@ -154,9 +441,27 @@ func (d *Diff) schemaDiff(from, to *schema.Schema, opts *schema.DiffOptions) ([]
 	}
 	changes = opts.AddOrSkip(changes, change...)
 
-	// Drop or modify tables.
-	for _, t1 := range from.Tables {
-		switch t2, err := d.findTable(to, t1.Name); {
+	// Sequences are created before the tables that use them
+	// (e.g. in their default values), and dropped after them.
+	seqs, dropS := sequenceDiff(from, to, opts)
+	changes = opts.AddOrSkip(changes, seqs...)
+
+	// Trigger changes are planned after their tables and views
+	// were created or modified, as they may depend on other ones.
+	var triggers []schema.Change
+
+	// Drop, rename or modify tables.
+	renamed, err := d.renamedTables(from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, t1 := range orderedTables(opts, from.Tables) {
+		t2, err := d.findTable(to, t1.Name, opts)
+		if r, ok := renamed[t1]; ok {
+			t2, err = r, nil
+			changes = opts.AddOrSkip(changes, &schema.RenameTable{From: t1, To: t2})
+		}
+		switch {
 		case schema.IsNotExistError(err):
 			changes = opts.AddOrSkip(changes, &schema.DropTable{T: t1})
 		case err != nil:
@@ -172,35 +477,302 @@ func (d *Diff) schemaDiff(from, to *schema.Schema, opts *schema.DiffOptions) ([]
 					Changes: change,
 				})
 			}
+			triggers = append(triggers, d.triggerDiff(t1.Triggers, t2.Triggers, opts)...)
 		}
 	}
 	// Add tables.
-	for _, t1 := range to.Tables {
-		switch _, err := d.findTable(from, t1.Name); {
+	added := make(map[*schema.Table]bool, len(renamed))
+	for _, t2 := range renamed {
+		added[t2] = true
+	}
+	for _, t1 := range orderedTables(opts, to.Tables) {
+		switch _, err := d.findTable(from, t1.Name, opts); {
+		case added[t1]:
 		case schema.IsNotExistError(err):
 			changes = opts.AddOrSkip(changes, &schema.AddTable{T: t1})
+			triggers = append(triggers, d.triggerDiff(nil, t1.Triggers, opts)...)
 		case err != nil:
 			return nil, err
 		}
 	}
 	// Drop or modify views.
-	for _, v1 := range from.Views {
+	for _, v1 := range ordered(from.Views, viewN) {
 		v2, ok := to.View(v1.Name)
 		if !ok {
 			changes = opts.AddOrSkip(changes, &schema.DropView{V: v1})
 			continue
 		}
-		if TrimViewExtra(v1.Def) != TrimViewExtra(v2.Def) || d.ViewAttrChanged(v1, v2) {
+		if v1.Materialized() != v2.Materialized() || d.viewDefChanged(v1, v2) || d.ViewAttrChanged(v1, v2) {
 			changes = opts.AddOrSkip(changes, &schema.ModifyView{From: v1, To: v2})
 		}
+		triggers = append(triggers, d.triggerDiff(v1.Triggers, v2.Triggers, opts)...)
 	}
 	// Add views.
-	for _, v1 := range to.Views {
+	for _, v1 := range ordered(to.Views, viewN) {
 		if _, ok := from.View(v1.Name); !ok {
 			changes = opts.AddOrSkip(changes, &schema.AddView{V: v1})
+			triggers = append(triggers, d.triggerDiff(nil, v1.Triggers, opts)...)
 		}
 	}
-	return changes, nil
+	changes = opts.AddOrSkip(changes, d.funcDiff(from, to, opts)...)
+	changes = opts.AddOrSkip(changes, triggers...)
+	return opts.AddOrSkip(changes, dropS...), nil
+}
+
+// sequenceDiff returns the changes for adding or modifying the sequences of the
+// schema, and separately, the changes for dropping them. Sequences that are owned
+// by dropped tables are dropped with them, and therefore, are not returned.
+func sequenceDiff(from, to *schema.Schema, opts *schema.DiffOptions) (changes, drops []schema.Change) {
+	name := func(s *schema.Sequence) string { return s.Name }
+	for _, s1 := range ordered(from.Sequences, name) {
+		switch s2, ok := to.Sequence(s1.Name); {
+		case !ok && s1.Owner.T != nil && !hasTable(to, s1.Owner.T.Name):
+			// Dropped with its owner table.
+		case !ok:
+			drops = append(drops, &schema.DropSequence{S: s1})
+		case sequenceChanged(s1, s2):
+			changes = append(changes, &schema.ModifySequence{From: s1, To: s2})
+		}
+	}
+	for _, s2 := range ordered(to.Sequences, name) {
+		if _, ok := from.Sequence(s2.Name); !ok {
+			changes = append(changes, &schema.AddSequence{S: s2})
+		}
+	}
+	return changes, drops
+}
+
+// ordered returns the objects in the order they are diffed, which is the alphabetical
+// order of their names. The states are not modified, and a sorted copy is returned.
+func ordered[T any](s []T, name func(T) string) []T {
+	if len(s) < 2 {
+		return s
+	}
+	sorted := make([]T, len(s))
+	copy(sorted, s)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return name(sorted[i]) < name(sorted[j])
+	})
+	return sorted
+}
+
+// orderedTables returns the tables in the order they are diffed. By default, tables
+// are sorted by their dependencies and then by their names. i.e., a table comes after
+// the tables it references. If the SortByName option is set, tables are sorted only
+// by their names.
+func orderedTables(opts *schema.DiffOptions, s []*schema.Table) []*schema.Table {
+	sorted := ordered(s, tableN)
+	if opts.SortByName || len(sorted) < 2 {
+		return sorted
+	}
+	var (
+		visit   func(*schema.Table)
+		tables  = make([]*schema.Table, 0, len(sorted))
+		visited = make(map[*schema.Table]bool, len(sorted))
+		exists  = make(map[*schema.Table]bool, len(sorted))
+	)
+	for _, t := range sorted {
+		exists[t] = true
+	}
+	visit = func(t *schema.Table) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		// Referenced tables are visited first. Cyclic references
+		// are broken by the order the tables are visited.
+		for _, fk := range ordered(t.ForeignKeys, fkN) {
+			if r := fk.RefTable; r != nil && r != t && exists[r] {
+				visit(r)
+			}
+		}
+		tables = append(tables, t)
+	}
+	for _, t := range sorted {
+		visit(t)
+	}
+	return tables
+}
+
+// Name functions of the objects that are passed to ordered.
+var (
+	schemaN = func(s *schema.Schema) string { return s.Name }
+	tableN  = func(t *schema.Table) string { return t.Name }
+	viewN   = func(v *schema.View) string { return v.Name }
+	indexN  = func(i *schema.Index) string { return i.Name }
+	fkN     = func(f *schema.ForeignKey) string { return f.Symbol }
+)
+
+// hasTable reports if the schema has a table with the given name.
+func hasTable(s *schema.Schema, name string) bool {
+	_, ok := s.Table(name)
+	return ok
+}
+
+// sequenceChanged reports if the sequence options or its owner were changed.
+func sequenceChanged(s1, s2 *schema.Sequence) bool {
+	if s1.Start != s2.Start || s1.Increment != s2.Increment || s1.Min != s2.Min || s1.Max != s2.Max ||
+		s1.Cache != s2.Cache || s1.Cycle != s2.Cycle || !reflect.DeepEqual(s1.Type, s2.Type) {
+		return true
+	}
+	o1, o2 := s1.Owner, s2.Owner
+	switch {
+	case (o1.T == nil) != (o2.T == nil), (o1.C == nil) != (o2.C == nil):
+		return true
+	case o1.T != nil && o1.T.Name != o2.T.Name, o1.C != nil && o1.C.Name != o2.C.Name:
+		return true
+	}
+	return false
+}
+
+// funcDiff returns the changes for migrating the functions and the procedures of the schema.
+func (d *Diff) funcDiff(from, to *schema.Schema, opts *schema.DiffOptions) []schema.Change {
+	var (
+		changes []schema.Change
+		funcN   = func(f *schema.Func) string { return f.Name }
+		procN   = func(p *schema.Proc) string { return p.Name }
+	)
+	for _, f1 := range ordered(from.Funcs, funcN) {
+		switch f2, ok := to.Func(f1.Name); {
+		case !ok:
+			changes = append(changes, &schema.DropFunc{F: f1})
+		case d.funcChanged(f1, f2):
+			changes = append(changes, &schema.ModifyFunc{From: f1, To: f2})
+		}
+	}
+	for _, f2 := range ordered(to.Funcs, funcN) {
+		if _, ok := from.Func(f2.Name); !ok {
+			changes = append(changes, &schema.AddFunc{F: f2})
+		}
+	}
+	for _, p1 := range ordered(from.Procs, procN) {
+		switch p2, ok := to.Proc(p1.Name); {
+		case !ok:
+			changes = append(changes, &schema.DropProc{P: p1})
+		case d.procChanged(p1, p2):
+			changes = append(changes, &schema.ModifyProc{From: p1, To: p2})
+		}
+	}
+	for _, p2 := range ordered(to.Procs, procN) {
+		if _, ok := from.Proc(p2.Name); !ok {
+			changes = append(changes, &schema.AddProc{P: p2})
+		}
+	}
+	return changes
+}
+
+// funcChanged reports if the function definition was changed.
+func (d *Diff) funcChanged(f1, f2 *schema.Func) bool {
+	if fd, ok := d.DiffDriver.(FuncDiffer); ok {
+		return fd.FuncChanged(f1, f2)
+	}
+	if !strings.EqualFold(f1.Lang, f2.Lang) || TrimViewExtra(f1.Body) != TrimViewExtra(f2.Body) ||
+		argsChanged(f1.Args, f2.Args) || !reflect.DeepEqual(f1.Ret, f2.Ret) || len(f1.RetTable) != len(f2.RetTable) {
+		return true
+	}
+	for i, c1 := range f1.RetTable {
+		c2 := f2.RetTable[i]
+		if c1.Name != c2.Name || c1.Type == nil || c2.Type == nil || !reflect.DeepEqual(c1.Type.Type, c2.Type.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// procChanged reports if the procedure definition was changed.
+func (d *Diff) procChanged(p1, p2 *schema.Proc) bool {
+	if pd, ok := d.DiffDriver.(ProcDiffer); ok {
+		return pd.ProcChanged(p1, p2)
+	}
+	return !strings.EqualFold(p1.Lang, p2.Lang) || TrimViewExtra(p1.Body) != TrimViewExtra(p2.Body) || argsChanged(p1.Args, p2.Args)
+}
+
+// argsChanged reports if the arguments of a function or a procedure were changed.
+func argsChanged(from, to []*schema.FuncArg) bool {
+	if len(from) != len(to) {
+		return true
+	}
+	for i, a1 := range from {
+		a2 := to[i]
+		if a1.Name != a2.Name || modeOf(a1) != modeOf(a2) ||
+			!reflect.DeepEqual(a1.Type, a2.Type) || !reflect.DeepEqual(a1.Default, a2.Default) {
+			return true
+		}
+	}
+	return false
+}
+
+// modeOf returns the mode of the argument. IN is the default mode.
+func modeOf(a *schema.FuncArg) schema.FuncArgMode {
+	if a.Mode == "" {
+		return schema.FuncArgModeIn
+	}
+	return schema.FuncArgMode(strings.ToUpper(string(a.Mode)))
+}
+
+// triggerDiff returns the changes for migrating the triggers of a table or a view.
+// Note, triggers of dropped tables and views are dropped with them, and therefore,
+// their changes are not returned.
+func (d *Diff) triggerDiff(from, to []*schema.Trigger, opts *schema.DiffOptions) []schema.Change {
+	var (
+		changes  []schema.Change
+		triggerN = func(t *schema.Trigger) string { return t.Name }
+	)
+	for _, t1 := range ordered(from, triggerN) {
+		switch t2, ok := findTrigger(to, t1.Name); {
+		case !ok:
+			changes = append(changes, &schema.DropTrigger{T: t1})
+		case d.triggerChanged(t1, t2):
+			changes = append(changes, &schema.ModifyTrigger{From: t1, To: t2})
+		}
+	}
+	for _, t2 := range ordered(to, triggerN) {
+		if _, ok := findTrigger(from, t2.Name); !ok {
+			changes = append(changes, &schema.AddTrigger{T: t2})
+		}
+	}
+	return changes
+}
+
+// triggerChanged reports if the trigger definition was changed.
+func (d *Diff) triggerChanged(t1, t2 *schema.Trigger) bool {
+	if td, ok := d.DiffDriver.(TriggerDiffer); ok {
+		return td.TriggerChanged(t1, t2)
+	}
+	if t1.ActionTime != t2.ActionTime || t1.For != t2.For || len(t1.Events) != len(t2.Events) ||
+		TrimViewExtra(t1.Body) != TrimViewExtra(t2.Body) {
+		return true
+	}
+	for i, e1 := range t1.Events {
+		e2 := t2.Events[i]
+		if !strings.EqualFold(e1.Name, e2.Name) || len(e1.Columns) != len(e2.Columns) {
+			return true
+		}
+		for j, c := range e1.Columns {
+			if c.Name != e2.Columns[j].Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findTrigger returns the trigger with the given name.
+func findTrigger(triggers []*schema.Trigger, name string) (*schema.Trigger, bool) {
+	for _, t := range triggers {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// viewDefChanged reports if the view definition was changed.
+func (d *Diff) viewDefChanged(v1, v2 *schema.View) bool {
+	if vd, ok := d.DiffDriver.(ViewDefDiffer); ok {
+		return vd.ViewDefChanged(v1, v2)
+	}
+	return TrimViewExtra(v1.Def) != TrimViewExtra(v2.Def)
 }
 
 // TableDiff implements the schema.TableDiffer interface and returns a list of
@@ -214,7 +786,7 @@ func (d *Diff) TableDiff(from, to *schema.Table, options ...schema.DiffOption) (
 	if err != nil {
 		return nil, err
 	}
-	return d.mayAnnotate(changes, opts)
+	return d.mayAnnotate(ignoreAttrs(changes, opts), opts)
 }
 
 // tableDiff implements the table diffing but skips the table name check.
@@ -239,9 +811,35 @@ func (d *Diff) tableDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]sc
 	}
 	changes = append(changes, change...)
 
+	// Rename columns. The rest of the table is diffed as if the columns were
+	// already renamed (e.g. their indexes), as it is executed after them.
+	renamed, err := d.renamedColumns(from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, c1 := range from.Columns {
+		if c2, ok := renamed[c1]; ok {
+			changes = opts.AddOrSkip(changes, &schema.RenameColumn{From: c1, To: c2})
+			c1, name := c1, c1.Name
+			c1.Name = c2.Name
+			defer func() { c1.Name = name }()
+		}
+	}
+	// Similarly, columns that were matched case-insensitively are
+	// diffed as if they have the same names (e.g. their indexes).
+	if opts.CaseInsensitive {
+		for _, c1 := range from.Columns {
+			if c2, ok := findColumn(to, c1.Name, opts); ok && c1.Name != c2.Name {
+				c1, name := c1, c1.Name
+				c1.Name = c2.Name
+				defer func() { c1.Name = name }()
+			}
+		}
+	}
+
 	// Drop or modify columns.
 	for _, c1 := range from.Columns {
-		c2, ok := to.Column(c1.Name)
+		c2, ok := findColumn(to, c1.Name, opts)
 		if !ok {
 			changes = opts.AddOrSkip(changes, &schema.DropColumn{C: c1})
 			continue
@@ -251,16 +849,20 @@ func (d *Diff) tableDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]sc
 			return nil, err
 		}
 		if change != schema.NoChange {
-			changes = opts.AddOrSkip(changes, &schema.ModifyColumn{
+			m := &schema.ModifyColumn{
 				From:   c1,
 				To:     c2,
 				Change: change,
-			})
+			}
+			if cc, ok := d.DiffDriver.(ConversionClassifier); ok && change.Is(schema.ChangeType) {
+				m.Conversion = cc.TypeConversion(c1, c2)
+			}
+			changes = opts.AddOrSkip(changes, m)
 		}
 	}
 	// Add columns.
 	for _, c1 := range to.Columns {
-		if _, ok := from.Column(c1.Name); !ok {
+		if _, ok := findColumn(from, c1.Name, opts); !ok {
 			changes = opts.AddOrSkip(changes, &schema.AddColumn{
 				C: c1,
 			})
@@ -272,13 +874,13 @@ func (d *Diff) tableDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]sc
 	changes = append(changes, d.indexDiff(from, to, opts)...)
 
 	// Drop or modify foreign-keys.
-	for _, fk1 := range from.ForeignKeys {
+	for _, fk1 := range ordered(from.ForeignKeys, fkN) {
 		fk2, ok := to.ForeignKey(fk1.Symbol)
 		if !ok {
 			changes = opts.AddOrSkip(changes, &schema.DropForeignKey{F: fk1})
 			continue
 		}
-		if change := d.fkChange(fk1, fk2); change != schema.NoChange {
+		if change := d.fkChange(fk1, fk2, opts); change != schema.NoChange {
 			changes = opts.AddOrSkip(changes, &schema.ModifyForeignKey{
 				From:   fk1,
 				To:     fk2,
@@ -287,7 +889,7 @@ func (d *Diff) tableDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]sc
 		}
 	}
 	// Add foreign-keys.
-	for _, fk1 := range to.ForeignKeys {
+	for _, fk1 := range ordered(to.ForeignKeys, fkN) {
 		if _, ok := from.ForeignKey(fk1.Symbol); !ok {
 			changes = opts.AddOrSkip(changes, &schema.AddForeignKey{F: fk1})
 		}
@@ -295,6 +897,143 @@ func (d *Diff) tableDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]sc
 	return changes, nil
 }
 
+// renamedTables returns the tables of the current state that were renamed in the
+// desired state, either by the hints of the options, or by the detection heuristic.
+// The detected renames are recorded in the options.
+func (d *Diff) renamedTables(from, to *schema.Schema, opts *schema.DiffOptions) (map[*schema.Table]*schema.Table, error) {
+	if len(opts.RenameTables) == 0 && !opts.DetectRenames || opts.Skipped(&schema.RenameTable{}) {
+		return nil, nil
+	}
+	var dropped, added []*schema.Table
+	for _, t1 := range from.Tables {
+		switch _, err := d.findTable(to, t1.Name, opts); {
+		case schema.IsNotExistError(err):
+			dropped = append(dropped, t1)
+		case err != nil:
+			return nil, err
+		}
+	}
+	for _, t2 := range to.Tables {
+		switch _, err := d.findTable(from, t2.Name, opts); {
+		case schema.IsNotExistError(err):
+			added = append(added, t2)
+		case err != nil:
+			return nil, err
+		}
+	}
+	renamed := hintedRenames(dropped, added, opts.RenameTables, func(t *schema.Table) string { return t.Name })
+	if opts.DetectRenames {
+		detectRenames(dropped, added, renamed, func(t1, t2 *schema.Table) bool {
+			return d.similarTables(t1, t2, opts)
+		})
+		// Record the detected renames, as foreign keys follow the tables they reference.
+		for t1, t2 := range renamed {
+			if opts.RenameTables == nil {
+				opts.RenameTables = make(map[string]string)
+			}
+			if _, ok := opts.RenameTables[t1.Name]; !ok {
+				opts.RenameTables[t1.Name] = t2.Name
+			}
+		}
+	}
+	return renamed, nil
+}
+
+// similarTables reports if the given tables have the same columns.
+func (d *Diff) similarTables(t1, t2 *schema.Table, opts *schema.DiffOptions) bool {
+	if len(t1.Columns) == 0 || len(t1.Columns) != len(t2.Columns) {
+		return false
+	}
+	for _, c1 := range t1.Columns {
+		c2, ok := findColumn(t2, c1.Name, opts)
+		if !ok || !d.similarColumns(t1, c1, c2) {
+			return false
+		}
+	}
+	return true
+}
+
+// renamedColumns returns the columns of the current table that were renamed in the
+// desired table, either by the hints of the options, or by the detection heuristic.
+func (d *Diff) renamedColumns(from, to *schema.Table, opts *schema.DiffOptions) (map[*schema.Column]*schema.Column, error) {
+	if len(opts.RenameColumns[to.Name]) == 0 && !opts.DetectRenames || opts.Skipped(&schema.RenameColumn{}) {
+		return nil, nil
+	}
+	var dropped, added []*schema.Column
+	for _, c1 := range from.Columns {
+		if _, ok := findColumn(to, c1.Name, opts); !ok {
+			dropped = append(dropped, c1)
+		}
+	}
+	for _, c2 := range to.Columns {
+		if _, ok := findColumn(from, c2.Name, opts); !ok {
+			added = append(added, c2)
+		}
+	}
+	renamed := hintedRenames(dropped, added, opts.RenameColumns[to.Name], func(c *schema.Column) string { return c.Name })
+	if opts.DetectRenames {
+		detectRenames(dropped, added, renamed, func(c1, c2 *schema.Column) bool {
+			return d.similarColumns(from, c1, c2)
+		})
+	}
+	return renamed, nil
+}
+
+// similarColumns reports if the given columns are identical, except for their names.
+func (d *Diff) similarColumns(t1 *schema.Table, c1, c2 *schema.Column) bool {
+	change, err := d.ColumnChange(t1, c1, c2)
+	return err == nil && change == schema.NoChange
+}
+
+// hintedRenames matches the dropped elements with the added elements they were renamed to.
+func hintedRenames[T comparable](dropped, added []T, hints map[string]string, name func(T) string) map[T]T {
+	renamed := make(map[T]T)
+	for _, e1 := range dropped {
+		to, ok := hints[name(e1)]
+		if !ok {
+			continue
+		}
+		for _, e2 := range added {
+			if name(e2) == to {
+				renamed[e1] = e2
+				break
+			}
+		}
+	}
+	return renamed
+}
+
+// detectRenames matches the dropped elements that were not matched yet with the added elements
+// that are similar to them. A match is made only if it is unique for both of its elements.
+func detectRenames[T comparable](dropped, added []T, renamed map[T]T, similar func(T, T) bool) {
+	matched := make(map[T]bool, len(renamed))
+	for _, e2 := range renamed {
+		matched[e2] = true
+	}
+	candidates := func(e T, es []T, similar func(T, T) bool) (c []T) {
+		for _, e1 := range es {
+			if _, ok := renamed[e1]; !ok && !matched[e1] && similar(e, e1) {
+				c = append(c, e1)
+			}
+		}
+		return c
+	}
+	for _, e1 := range dropped {
+		if _, ok := renamed[e1]; ok {
+			continue
+		}
+		c2 := candidates(e1, added, similar)
+		if len(c2) != 1 {
+			continue
+		}
+		c1 := candidates(c2[0], dropped, func(e2, e1 T) bool { return similar(e1, e2) })
+		if len(c1) == 1 && c1[0] == e1 {
+			renamed[e1] = c2[0]
+			matched[c2[0]] = true
+		}
+	}
+}
+
 func (d *Diff) mayAnnotate(changes []schema.Change, opts *schema.DiffOptions) ([]schema.Change, error) {
 	r, ok := d.DiffDriver.(ChangesAnnotator)
 	if ok {
@@ -335,7 +1074,7 @@ func (d *Diff) indexDiff(from, to *schema.Table, opts *schema.DiffOptions) []sch
 		exists  = make(map[*schema.Index]bool)
 	)
 	// Drop or modify indexes.
-	for _, idx1 := range from.Indexes {
+	for _, idx1 := range ordered(from.Indexes, indexN) {
 		idx2, ok := to.Index(idx1.Name)
 		// Found directly.
 		if ok {
@@ -360,7 +1099,7 @@ func (d *Diff) indexDiff(from, to *schema.Table, opts *schema.DiffOptions) []sch
 		changes = opts.AddOrSkip(changes, &schema.DropIndex{I: idx1})
 	}
 	// Add indexes.
-	for _, idx := range to.Indexes {
+	for _, idx := range ordered(to.Indexes, indexN) {
 		if exists[idx] {
 			continue
 		}
@@ -381,7 +1120,13 @@ func (d *Diff) indexChange(from, to *schema.Index) schema.ChangeKind {
 		change |= schema.ChangeAttr
 	}
 	change |= d.partsChange(from, to)
-	change |= CommentChange(from.Attrs, to.Attrs)
+	if cd, ok := d.DiffDriver.(IndexCommentDiffer); ok {
+		if cd.IndexCommentChanged(from, to) {
+			change |= schema.ChangeComment
+		}
+	} else {
+		change |= CommentChange(from.Attrs, to.Attrs)
+	}
 	return change
 }
 
@@ -413,10 +1158,10 @@ func (d *Diff) partsChange(fromI, toI *schema.Index) schema.ChangeKind {
 }
 
 // fkChange returns the schema changes (if any) for migrating one index to the other.
-func (d *Diff) fkChange(from, to *schema.ForeignKey) schema.ChangeKind {
+func (d *Diff) fkChange(from, to *schema.ForeignKey, opts *schema.DiffOptions) schema.ChangeKind {
 	var change schema.ChangeKind
 	switch {
-	case from.Table.Name != to.Table.Name:
+	case refTableChanged(from, to, opts):
 		change |= schema.ChangeRefTable | schema.ChangeRefColumn
 	case len(from.RefColumns) != len(to.RefColumns):
 		change |= schema.ChangeRefColumn
@@ -459,7 +1204,47 @@ func (d *Diff) similarUnnamedIndex(t *schema.Table, idx1 *schema.Index) (*schema
 	return nil, false
 }
 
-func (d *Diff) findTable(s *schema.Schema, name string) (*schema.Table, error) {
+// refTableChanged reports if the referenced table of the foreign key was changed. Foreign
+// keys follow the tables they reference when these tables are renamed or moved to another
+// schema (or when their schema is renamed), and therefore, the referenced table of the
+// current state is compared by its name in the desired state.
+func refTableChanged(from, to *schema.ForeignKey, opts *schema.DiffOptions) bool {
+	if from.RefTable == nil || to.RefTable == nil {
+		return from.RefTable != to.RefTable
+	}
+	var s1, s2, name = schemaName(from.RefTable), schemaName(to.RefTable), from.RefTable.Name
+	if q, ok := opts.MoveTables[qualifiedName(from.RefTable)]; ok && q == qualifiedName(to.RefTable) {
+		return false
+	}
+	if n, ok := opts.RenameSchemas[s1]; ok {
+		s1 = n
+	}
+	if n, ok := opts.RenameTables[name]; ok && n == to.RefTable.Name {
+		name = n
+	}
+	if opts.CaseInsensitive {
+		return !strings.EqualFold(name, to.RefTable.Name) || s1 != "" && s2 != "" && !strings.EqualFold(s1, s2)
+	}
+	return name != to.RefTable.Name || s1 != "" && s2 != "" && s1 != s2
+}
+
+// schemaName returns the schema name of the table, if it has one.
+func schemaName(t *schema.Table) string {
+	if t.Schema == nil {
+		return ""
+	}
+	return t.Schema.Name
+}
+
+func (d *Diff) findTable(s *schema.Schema, name string, opts *schema.DiffOptions) (*schema.Table, error) {
+	if opts.CaseInsensitive {
+		for _, t := range s.Tables {
+			if strings.EqualFold(t.Name, name) {
+				return t, nil
+			}
+		}
+		return nil, &schema.NotExistError{Err: fmt.Errorf("table %q was not found", name)}
+	}
 	if f, ok := d.DiffDriver.(TableFinder); ok {
 		return f.FindTable(s, name)
 	}
@@ -470,6 +1255,82 @@ func (d *Diff) findTable(s *schema.Schema, name string) (*schema.Table, error) {
 	return t, nil
 }
 
+// findColumn returns the column with the given name, respecting the case-sensitivity of the options.
+func findColumn(t *schema.Table, name string, opts *schema.DiffOptions) (*schema.Column, bool) {
+	if opts.CaseInsensitive {
+		for _, c := range t.Columns {
+			if strings.EqualFold(c.Name, name) {
+				return c, true
+			}
+		}
+		return nil, false
+	}
+	return t.Column(name)
+}
+
+// ignoreAttrs removes the changes of the attributes that are ignored by the options, and
+// the changes to elements that were modified only by them (e.g. a column comment change).
+func ignoreAttrs(changes []schema.Change, opts *schema.DiffOptions) []schema.Change {
+	if !opts.IgnoreComments && !opts.IgnoreCharsets {
+		return changes
+	}
+	var kinds schema.ChangeKind
+	if opts.IgnoreComments {
+		kinds |= schema.ChangeComment
+	}
+	if opts.IgnoreCharsets {
+		kinds |= schema.ChangeCharset | schema.ChangeCollate
+	}
+	ignored := func(a schema.Attr) bool {
+		switch a.(type) {
+		case *schema.Comment:
+			return opts.IgnoreComments
+		case *schema.Charset, *schema.Collation:
+			return opts.IgnoreCharsets
+		}
+		return false
+	}
+	filtered := make([]schema.Change, 0, len(changes))
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddAttr:
+			if ignored(c.A) {
+				continue
+			}
+		case *schema.DropAttr:
+			if ignored(c.A) {
+				continue
+			}
+		case *schema.ModifyAttr:
+			if ignored(c.From) || ignored(c.To) {
+				continue
+			}
+		case *schema.ModifyColumn:
+			if c.Change &^= kinds; c.Change == schema.NoChange {
+				continue
+			}
+		case *schema.ModifyIndex:
+			if c.Change &^= kinds; c.Change == schema.NoChange {
+				continue
+			}
+		case *schema.ModifyPrimaryKey:
+			if c.Change &^= kinds; c.Change == schema.NoChange {
+				continue
+			}
+		case *schema.ModifySchema:
+			if c.Changes = ignoreAttrs(c.Changes, opts); len(c.Changes) == 0 {
+				continue
+			}
+		case *schema.ModifyTable:
+			if c.Changes = ignoreAttrs(c.Changes, opts); len(c.Changes) == 0 {
+				continue
+			}
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
 // CommentChange reports if the element comment was changed.
 func CommentChange(from, to []schema.Attr) schema.ChangeKind {
 	var c1, c2 schema.Comment
@@ -574,8 +1435,19 @@ func CommentDiff(from, to []schema.Attr) schema.Change {
 	return nil
 }
 
+// GeneratedChanged reports if the generation expression or the type of a generated column was
+// changed, or if the column was changed from or to a generated column. Expressions are compared
+// after they were wrapped with parentheses, and types after they were normalized by the driver,
+// as the default type is driver-specific (e.g. VIRTUAL in MySQL and STORED in PostgreSQL).
+func GeneratedChanged(from, to *schema.Column, normalize func(string) string) bool {
+	x1, fromHas := from.Generated()
+	x2, toHas := to.Generated()
+	return fromHas != toHas || fromHas && (MayWrap(x1.Expr) != MayWrap(x2.Expr) || normalize(x1.Type) != normalize(x2.Type))
+}
+
 // CheckDiff computes the change diff between the 2 tables. A compare
-// function is provided to check if a Check object was modified.
+// function is provided to check if a Check object was modified. If no
+// function was provided, checks are compared by their enforcement.
 func CheckDiff(from, to *schema.Table, compare ...func(c1, c2 *schema.Check) bool) []schema.Change {
 	var changes []schema.Change
 	// Drop or modify checks.
@@ -585,7 +1457,8 @@ func CheckDiff(from, to *schema.Table, compare ...func(c1, c2 *schema.Check) boo
 			changes = append(changes, &schema.DropCheck{
 				C: c1,
 			})
-		case len(compare) == 1 && !compare[0](c1, c2):
+		case len(compare) == 0 && c1.Enforced() != c2.Enforced(),
+			len(compare) == 1 && !compare[0](c1, c2):
 			changes = append(changes, &schema.ModifyCheck{
 				From: c1,
 				To:   c2,
@@ -672,3 +1545,189 @@ func SingleQuote(s string) (string, error) {
 func TrimViewExtra(s string) string {
 	return strings.Trim(s, " \n\t;")
 }
+
+// ClassifyConversion classifies the conversion of values from one column type to another. The
+// sizes map (lowercase) type names to the maximum size of their values: in bytes for integer
+// and floating-point types, and in characters (or bytes) for string and binary types without
+// an explicit size. e.g. "bigint": 8, "text": 65535. Conversions between types of the same
+// family that cannot be classified (e.g. missing sizes) are reported as unknown.
+func ClassifyConversion(from, to schema.Type, sizes map[string]int64) schema.TypeConversion {
+	var (
+		size = func(t string, s int) (int64, bool) {
+			if s > 0 {
+				return int64(s), true
+			}
+			n, ok := sizes[strings.ToLower(t)]
+			return n, ok
+		}
+		widening = func(ok bool) schema.TypeConversion {
+			if ok {
+				return schema.ConversionWidening
+			}
+			return schema.ConversionNarrowing
+		}
+		// toString classifies the conversion of values with
+		// the given number of characters to a string type.
+		toString = func(t *schema.StringType, n int64) schema.TypeConversion {
+			if s, ok := size(t.T, t.Size); ok {
+				return widening(s >= n)
+			}
+			return schema.ConversionUnknown
+		}
+	)
+	switch f := from.(type) {
+	case *schema.BoolType:
+		switch to.(type) {
+		case *schema.BoolType, *schema.IntegerType:
+			return schema.ConversionWidening
+		}
+	case *schema.IntegerType:
+		fs, ok := size(f.T, 0)
+		if !ok {
+			return schema.ConversionUnknown
+		}
+		switch t := to.(type) {
+		case *schema.IntegerType:
+			ts, ok := size(t.T, 0)
+			if !ok {
+				return schema.ConversionUnknown
+			}
+			return widening(f.Unsigned == t.Unsigned && ts >= fs || f.Unsigned && !t.Unsigned && ts > fs)
+		case *schema.DecimalType:
+			if t.Precision == 0 {
+				return schema.ConversionUnknown
+			}
+			return widening((f.Unsigned || !t.Unsigned) && int64(t.Precision-t.Scale) >= intDigits(fs, f.Unsigned))
+		case *schema.StringType:
+			// Digits and sign.
+			return toString(t, intDigits(fs, f.Unsigned)+1)
+		case *schema.BoolType:
+			return schema.ConversionNarrowing
+		case *schema.FloatType:
+			return schema.ConversionUnknown
+		}
+	case *schema.DecimalType:
+		switch t := to.(type) {
+		case *schema.DecimalType:
+			if f.Precision == 0 || t.Precision == 0 {
+				return schema.ConversionUnknown
+			}
+			return widening((f.Unsigned || !t.Unsigned) && t.Scale >= f.Scale && t.Precision-t.Scale >= f.Precision-f.Scale)
+		case *schema.IntegerType, *schema.FloatType:
+			return schema.ConversionNarrowing
+		case *schema.StringType:
+			if f.Precision == 0 {
+				return schema.ConversionUnknown
+			}
+			// Digits, sign and decimal point.
+			return toString(t, int64(f.Precision)+2)
+		}
+	case *schema.FloatType:
+		switch t := to.(type) {
+		case *schema.FloatType:
+			if strings.EqualFold(f.T, t.T) {
+				if t.Precision == 0 || f.Precision != 0 && f.Precision <= t.Precision {
+					return schema.ConversionWidening
+				}
+				return schema.ConversionUnknown
+			}
+			fs, ok1 := size(f.T, 0)
+			ts, ok2 := size(t.T, 0)
+			if !ok1 || !ok2 {
+				return schema.ConversionUnknown
+			}
+			return widening(ts >= fs)
+		case *schema.IntegerType, *schema.DecimalType:
+			return schema.ConversionNarrowing
+		case *schema.StringType:
+			return schema.ConversionUnknown
+		}
+	case *schema.StringType:
+		switch t := to.(type) {
+		case *schema.StringType:
+			if fs, ok := size(f.T, f.Size); ok {
+				return toString(t, fs)
+			}
+			return schema.ConversionUnknown
+		case *schema.EnumType:
+			return schema.ConversionNarrowing
+		}
+	case *schema.EnumType:
+		switch t := to.(type) {
+		case *schema.EnumType:
+			values := make(map[string]bool, len(t.Values))
+			for _, v := range t.Values {
+				values[v] = true
+			}
+			for _, v := range f.Values {
+				if !values[v] {
+					return schema.ConversionNarrowing
+				}
+			}
+			return schema.ConversionWidening
+		case *schema.StringType:
+			var n int
+			for _, v := range f.Values {
+				if len(v) > n {
+					n = len(v)
+				}
+			}
+			return toString(t, int64(n))
+		}
+	case *schema.BinaryType:
+		if t, ok := to.(*schema.BinaryType); ok {
+			var fn, tn int
+			if f.Size != nil {
+				fn = *f.Size
+			}
+			if t.Size != nil {
+				tn = *t.Size
+			}
+			fs, ok1 := size(f.T, fn)
+			ts, ok2 := size(t.T, tn)
+			if !ok1 || !ok2 {
+				return schema.ConversionUnknown
+			}
+			return widening(ts >= fs)
+		}
+	case *schema.TimeType:
+		if t, ok := to.(*schema.TimeType); ok {
+			var (
+				ft, tt   = strings.ToLower(f.T), strings.ToLower(t.T)
+				datetime = func(t string) bool {
+					return strings.HasPrefix(t, "timestamp") || strings.HasPrefix(t, "datetime")
+				}
+			)
+			switch {
+			case ft == tt && f.Precision == nil && t.Precision == nil:
+				return schema.ConversionWidening
+			case ft == tt && f.Precision != nil && t.Precision != nil:
+				return widening(*t.Precision >= *f.Precision)
+			case ft == "date" && datetime(tt):
+				return schema.ConversionWidening
+			case datetime(ft) && tt == "date":
+				return schema.ConversionNarrowing
+			}
+			return schema.ConversionUnknown
+		}
+	}
+	_, ok1 := from.(*schema.UnsupportedType)
+	_, ok2 := to.(*schema.UnsupportedType)
+	if ok1 || ok2 || reflect.TypeOf(from) == reflect.TypeOf(to) {
+		return schema.ConversionUnknown
+	}
+	return schema.ConversionIncompatible
+}
+
+// intDigits returns the number of decimal digits of the maximum
+// value of an integer type with the given size in bytes.
+func intDigits(size int64, unsigned bool) int64 {
+	if size > 8 {
+		size = 8
+	}
+	m := uint64(1)<<(8*size) - 1
+	if !unsigned {
+		m = uint64(1)<<(8*size-1) - 1
+	}
+	return int64(len(strconv.FormatUint(m, 10)))
+}
@@ -8,11 +8,101 @@ import (
 	"encoding/csv"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"ariga.io/atlas/sql/schema"
 )
 
+// FilterRealm filters resources in the realm based on the Include and Exclude patterns
+// of the given options. Resources are included first, and then excluded.
+func FilterRealm(r *schema.Realm, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	r, err := IncludeRealm(r, opts.Include)
+	if err != nil {
+		return nil, err
+	}
+	return ExcludeRealm(r, opts.Exclude)
+}
+
+// FilterSchema filters resources in the schema based on the Include and Exclude patterns
+// of the given options. Resources are included first, and then excluded.
+func FilterSchema(s *schema.Schema, opts *schema.InspectOptions) (*schema.Schema, error) {
+	s, err := IncludeSchema(s, opts.Include)
+	if err != nil {
+		return nil, err
+	}
+	return ExcludeSchema(s, opts.Exclude)
+}
+
+// IncludeRealm filters out the resources in the realm that do not match any of the given patterns.
+func IncludeRealm(r *schema.Realm, patterns []string) (*schema.Realm, error) {
+	if len(patterns) == 0 {
+		return r, nil
+	}
+	globs, err := split(patterns)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []*schema.Schema
+	for _, s := range r.Schemas {
+		var (
+			included, all bool
+			objects       []string
+		)
+		for i, g := range globs {
+			if len(g) > 2 {
+				return nil, fmt.Errorf("too many parts in pattern: %q", patterns[i])
+			}
+			match, err := match(g[0], s.Name)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+			// A single glob includes the whole schema.
+			if included = true; len(g) == 1 {
+				all = true
+			} else {
+				objects = append(objects, g[1])
+			}
+		}
+		if !included {
+			continue
+		}
+		if !all {
+			if err := includeS(s, objects); err != nil {
+				return nil, err
+			}
+		}
+		schemas = append(schemas, s)
+	}
+	r.Schemas = schemas
+	return r, nil
+}
+
+// IncludeSchema filters out the resources in the schema that do not match any of the given patterns.
+func IncludeSchema(s *schema.Schema, patterns []string) (*schema.Schema, error) {
+	if len(patterns) == 0 {
+		return s, nil
+	}
+	globs, err := split(patterns)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]string, len(globs))
+	for i, g := range globs {
+		if len(g) > 1 {
+			return nil, fmt.Errorf("too many parts in pattern: %q", patterns[i])
+		}
+		objects[i] = g[0]
+	}
+	if err := includeS(s, objects); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 // ExcludeRealm filters resources in the realm based on the given patterns.
 func ExcludeRealm(r *schema.Realm, patterns []string) (*schema.Realm, error) {
 	if len(patterns) == 0 {
@@ -29,7 +119,7 @@ Filter:
 			if len(g) > 3 {
 				return nil, fmt.Errorf("too many parts in pattern: %q", patterns[i])
 			}
-			match, err := filepath.Match(g[0], s.Name)
+			match, err := match(g[0], s.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -91,7 +181,7 @@ func split(patterns []string) ([][]string, error) {
 func excludeS(s *schema.Schema, glob []string) error {
 	var tables []*schema.Table
 	for _, t := range s.Tables {
-		match, err := filepath.Match(glob[0], t.Name)
+		match, err := match(glob[0], t.Name)
 		if err != nil {
 			return err
 		}
@@ -109,14 +199,61 @@ func excludeS(s *schema.Schema, glob []string) error {
 		tables = append(tables, t)
 	}
 	s.Tables = tables
-	return nil
+	if len(glob) > 1 {
+		return nil
+	}
+	return filterS(s, func(name string) (bool, error) {
+		return match(glob[0], name)
+	})
+}
+
+// includeS filters out the tables, views, functions, procedures and sequences
+// of the schema that do not match any of the given patterns. Other objects (e.g.
+// enum types) are kept, as the included resources may depend on them.
+func includeS(s *schema.Schema, patterns []string) error {
+	matchAny := func(name string) (bool, error) {
+		for _, p := range patterns {
+			if m, err := match(p, name); m || err != nil {
+				return m, err
+			}
+		}
+		return false, nil
+	}
+	excluded := func(name string) (bool, error) {
+		m, err := matchAny(name)
+		return !m, err
+	}
+	tables, err := filter(s.Tables, func(t *schema.Table) (bool, error) {
+		return excluded(t.Name)
+	})
+	if err != nil {
+		return err
+	}
+	s.Tables = tables
+	return filterS(s, excluded)
+}
+
+// filterS filters out the views, functions, procedures and
+// sequences of the schema that their names match f.
+func filterS(s *schema.Schema, f func(string) (bool, error)) (err error) {
+	if s.Views, err = filter(s.Views, func(v *schema.View) (bool, error) { return f(v.Name) }); err != nil {
+		return err
+	}
+	if s.Funcs, err = filter(s.Funcs, func(fn *schema.Func) (bool, error) { return f(fn.Name) }); err != nil {
+		return err
+	}
+	if s.Procs, err = filter(s.Procs, func(p *schema.Proc) (bool, error) { return f(p.Name) }); err != nil {
+		return err
+	}
+	s.Sequences, err = filter(s.Sequences, func(sq *schema.Sequence) (bool, error) { return f(sq.Name) })
+	return err
 }
 
 func excludeT(t *schema.Table, pattern string) (err error) {
 	ex := make(map[*schema.Index]struct{})
 	ef := make(map[*schema.ForeignKey]struct{})
 	t.Columns, err = filter(t.Columns, func(c *schema.Column) (bool, error) {
-		match, err := filepath.Match(pattern, c.Name)
+		match, err := match(pattern, c.Name)
 		if !match || err != nil {
 			return false, err
 		}
@@ -132,28 +269,45 @@ func excludeT(t *schema.Table, pattern string) (err error) {
 		if _, ok := ex[idx]; ok {
 			return true, nil
 		}
-		return filepath.Match(pattern, idx.Name)
+		return match(pattern, idx.Name)
 	})
 	t.ForeignKeys, err = filter(t.ForeignKeys, func(fk *schema.ForeignKey) (bool, error) {
 		if _, ok := ef[fk]; ok {
 			return true, nil
 		}
-		return filepath.Match(pattern, fk.Symbol)
+		return match(pattern, fk.Symbol)
 	})
 	t.Attrs, err = filter(t.Attrs, func(a schema.Attr) (bool, error) {
 		c, ok := a.(*schema.Check)
 		if !ok {
 			return false, nil
 		}
-		match, err := filepath.Match(pattern, c.Name)
+		match, err := match(pattern, c.Name)
 		if !match || err != nil {
 			return false, err
 		}
 		return true, nil
 	})
+	t.Triggers, err = filter(t.Triggers, func(tr *schema.Trigger) (bool, error) {
+		return match(pattern, tr.Name)
+	})
 	return
 }
 
+// match reports whether the name matches the pattern. Patterns that are wrapped
+// with slashes are matched as regular expressions (e.g. /^tmp_\d+$/), and the
+// rest as glob patterns (e.g. tmp_*).
+func match(pattern, name string) (bool, error) {
+	if n := len(pattern); n > 1 && pattern[0] == '/' && pattern[n-1] == '/' {
+		re, err := regexp.Compile(pattern[1 : n-1])
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(name), nil
+	}
+	return filepath.Match(pattern, name)
+}
+
 func filter[T any](s []T, f func(T) (bool, error)) ([]T, error) {
 	r := make([]T, 0, len(s))
 	for i := range s {
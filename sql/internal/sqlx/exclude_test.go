@@ -204,4 +204,72 @@ func TestExcludeSchema(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, r.Schemas, 1)
 	require.Len(t, r.Schemas[0].Tables, 1)
+
+	// Views and other objects are excluded like tables.
+	r.Schemas[0].AddViews(schema.NewView("v1", "SELECT 1"), schema.NewView("tmp_v2", "SELECT 2"))
+	r.Schemas[0].AddSequences(schema.NewSequence("t1_seq"))
+	_, err = ExcludeSchema(r.Schemas[0], []string{"/^tmp_/", "*_seq"})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas[0].Tables, 1)
+	require.Len(t, r.Schemas[0].Views, 1)
+	require.Equal(t, "v1", r.Schemas[0].Views[0].Name)
+	require.Empty(t, r.Schemas[0].Sequences)
+}
+
+func TestIncludeRealm(t *testing.T) {
+	newRealm := func() *schema.Realm {
+		return schema.NewRealm(
+			schema.New("s1").
+				AddTables(schema.NewTable("users"), schema.NewTable("tmp_1"), schema.NewTable("tmp_a")).
+				AddViews(schema.NewView("tmp_users", "SELECT 1")),
+			schema.New("s2").AddTables(schema.NewTable("users")),
+			schema.New("vendor").AddTables(schema.NewTable("users")),
+		)
+	}
+	r, err := IncludeRealm(newRealm(), []string{"s2"})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 1)
+	require.Equal(t, "s2", r.Schemas[0].Name)
+	require.Len(t, r.Schemas[0].Tables, 1)
+
+	r, err = IncludeRealm(newRealm(), []string{"s*.tmp_*", "s2"})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 2)
+	require.Len(t, r.Schemas[0].Tables, 2)
+	require.Len(t, r.Schemas[0].Views, 1)
+	require.Len(t, r.Schemas[1].Tables, 1, "schema is included as a whole")
+
+	r, err = IncludeRealm(newRealm(), []string{`*."/^tmp_\d+$/"`})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 3)
+	require.Len(t, r.Schemas[0].Tables, 1)
+	require.Equal(t, "tmp_1", r.Schemas[0].Tables[0].Name)
+	require.Empty(t, r.Schemas[0].Views)
+	require.Empty(t, r.Schemas[1].Tables)
+
+	_, err = IncludeRealm(newRealm(), []string{"s1.t.c"})
+	require.EqualError(t, err, `too many parts in pattern: "s1.t.c"`)
+	_, err = IncludeRealm(newRealm(), []string{"/[/"})
+	require.Error(t, err)
+
+	// Resources are included first, and then excluded.
+	r, err = FilterRealm(newRealm(), &schema.InspectRealmOption{Include: []string{"s1"}, Exclude: []string{"s1.tmp_*"}})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 1)
+	require.Len(t, r.Schemas[0].Tables, 1)
+	require.Empty(t, r.Schemas[0].Views)
+}
+
+func TestIncludeSchema(t *testing.T) {
+	s := schema.New("s1").
+		AddTables(schema.NewTable("users"), schema.NewTable("pets"), schema.NewTable("tmp_users")).
+		AddFuncs(&schema.Func{Name: "users_count"}).
+		AddObjects(&schema.EnumType{T: "status"})
+	s, err := IncludeSchema(s, []string{"users*"})
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 1)
+	require.Len(t, s.Funcs, 1)
+	require.Len(t, s.Objects, 1, "driver-specific objects are not filtered")
+	_, err = IncludeSchema(s, []string{"t.c"})
+	require.EqualError(t, err, `too many parts in pattern: "t.c"`)
 }
@@ -10,7 +10,9 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
+	"ariga.io/atlas/sql/internal/result"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 )
@@ -45,13 +47,35 @@ func ApplyChanges(ctx context.Context, changes []schema.Change, p execPlanner, o
 	if err != nil {
 		return err
 	}
+	var o migrate.PlanOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.DryRun != nil {
+		o.DryRun(plan)
+		return nil
+	}
+	if o.Approver != nil {
+		if plan, err = migrate.Approve(ctx, o.Approver, plan); err != nil {
+			return err
+		}
+	}
+	l := o.Logger
+	if l == nil {
+		l = migrate.NopLogger{}
+	}
 	for i, c := range plan.Changes {
-		if _, err := p.ExecContext(ctx, c.Cmd, c.Args...); err != nil {
+		l.Log(migrate.LogStmt{SQL: c.Cmd})
+		start := time.Now()
+		res, err := p.ExecContext(ctx, c.Cmd, c.Args...)
+		if err != nil {
+			l.Log(migrate.LogError{SQL: c.Cmd, Error: err})
 			if c.Comment != "" {
 				err = fmt.Errorf("%s: %w", c.Comment, err)
 			}
 			return &ApplyError{err: err.Error(), applied: i}
 		}
+		l.Log(migrate.LogStmtDone{SQL: c.Cmd, Duration: time.Since(start), RowsAffected: result.RowsAffected(res)})
 	}
 	return nil
 }
@@ -82,7 +106,9 @@ func SetReversible(p *migrate.Plan) error {
 		if err != nil {
 			return err
 		}
-		if len(stmts) == 0 {
+		// An empty, non-nil list of statements marks a
+		// change that requires no statements to revert.
+		if stmts == nil {
 			reversible = false
 		}
 	}
@@ -90,6 +116,16 @@ func SetReversible(p *migrate.Plan) error {
 	return nil
 }
 
+// Annotate copies the annotations of the source
+// changes to their planned statements, if any.
+func Annotate(p *migrate.Plan, a schema.Annotations) {
+	for _, c := range p.Changes {
+		if kv := a.Lookup(c.Source); len(kv) > 0 {
+			c.Annotations = kv
+		}
+	}
+}
+
 // DetachCycles takes a list of schema changes, and detaches
 // references between changes if there is at least one circular
 // reference in the changeset. More explicitly, it postpones fks
@@ -104,7 +140,7 @@ func DetachCycles(changes []schema.Change) ([]schema.Change, error) {
 	}
 	planned := make([]schema.Change, len(changes))
 	copy(planned, changes)
-	sort.Slice(planned, func(i, j int) bool {
+	sort.SliceStable(planned, func(i, j int) bool {
 		return sorted[table(planned[i])] < sorted[table(planned[j])]
 	})
 	return planned, nil
@@ -204,8 +240,10 @@ func sortMap(changes []schema.Change) (map[string]int, error) {
 		sorted[name] = len(sorted)
 		return false
 	}
-	for node := range deps {
-		if visit(node) {
+	// Tables are visited in the order they appear in the changeset,
+	// to keep the planned order stable between runs.
+	for _, c := range changes {
+		if node := table(c); node != "" && visit(node) {
 			return nil, errCycle
 		}
 	}
@@ -328,7 +366,7 @@ func CheckChangesScope(opts migrate.PlanOptions, changes []schema.Change) error
 				names[c.S.Name] = struct{}{}
 				continue
 			}
-		case *schema.AddSchema, *schema.DropSchema:
+		case *schema.AddSchema, *schema.DropSchema, *schema.RenameSchema:
 			return fmt.Errorf("%T is not allowed when migration plan is scoped to one schema", c)
 		case *schema.AddTable:
 			t = c.T
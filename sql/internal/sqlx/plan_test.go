@@ -106,6 +106,20 @@ func TestDetachCycles(t *testing.T) {
 	planned, err = DetachCycles(changes)
 	require.NoError(t, err)
 	require.Equal(t, []schema.Change{changes[1], changes[0]}, planned)
+
+	// Tables without dependencies keep their order in the changeset.
+	for i := 0; i < 10; i++ {
+		var (
+			a, b, c = schema.NewTable("a"), schema.NewTable("b"), schema.NewTable("c")
+			d       = schema.NewTable("d").AddColumns(schema.NewIntColumn("id", "int"))
+			e       = schema.NewTable("e").AddColumns(schema.NewIntColumn("d_id", "int"))
+		)
+		e.AddForeignKeys(schema.NewForeignKey("d").AddColumns(e.Columns...).SetRefTable(d).AddRefColumns(d.Columns...))
+		changes = []schema.Change{&schema.AddTable{T: e}, &schema.AddTable{T: c}, &schema.AddTable{T: a}, &schema.AddTable{T: d}, &schema.AddTable{T: b}}
+		planned, err = DetachCycles(changes)
+		require.NoError(t, err)
+		require.Equal(t, []schema.Change{changes[3], changes[0], changes[1], changes[2], changes[4]}, planned)
+	}
 }
 
 func TestCheckChangesScope(t *testing.T) {
@@ -54,6 +54,32 @@ func SingleConn(ctx context.Context, conn schema.ExecQuerier) (ExecQueryCloser,
 	return nil, fmt.Errorf("cannot obtain a single connection from %T", conn)
 }
 
+// PinConn binds the ExecQuerier pointed by conn to a single connection, in case it is a pool of
+// connections (e.g. sql.DB). The returned function restores the ExecQuerier and releases the
+// connection back to the pool. ExecQueriers that are already bound to a single connection are
+// left unchanged.
+func PinConn(ctx context.Context, conn *schema.ExecQuerier) (func() error, error) {
+	c, err := SingleConn(ctx, *conn)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := c.(nopCloser); ok {
+		return c.Close, nil
+	}
+	prev := *conn
+	*conn = c
+	return func() error {
+		*conn = prev
+		return c.Close()
+	}, nil
+}
+
+// InTx reports if the given ExecQuerier is a transaction.
+func InTx(conn schema.ExecQuerier) bool {
+	_, ok := conn.(driver.Tx)
+	return ok
+}
+
 // ValidString reports if the given string is not null and valid.
 func ValidString(s sql.NullString) bool {
 	return s.Valid && s.String != "" && strings.ToLower(s.String) != "null"
@@ -202,7 +228,7 @@ func ValuesEqual(v1, v2 []string) bool {
 // ModeInspectSchema returns the InspectMode or its default.
 func ModeInspectSchema(o *schema.InspectOptions) schema.InspectMode {
 	if o == nil || o.Mode == 0 {
-		return schema.InspectSchemas | schema.InspectTables | schema.InspectViews
+		return schema.InspectSchemas | schema.InspectTables | schema.InspectViews | schema.InspectTriggers
 	}
 	return o.Mode
 }
@@ -210,7 +236,7 @@ func ModeInspectSchema(o *schema.InspectOptions) schema.InspectMode {
 // ModeInspectRealm returns the InspectMode or its default.
 func ModeInspectRealm(o *schema.InspectRealmOption) schema.InspectMode {
 	if o == nil || o.Mode == 0 {
-		return schema.InspectSchemas | schema.InspectTables | schema.InspectViews
+		return schema.InspectSchemas | schema.InspectTables | schema.InspectViews | schema.InspectTriggers
 	}
 	return o.Mode
 }
@@ -266,6 +292,12 @@ func (b *Builder) Table(t *schema.Table) *Builder {
 	return b.mayQualify(t.Schema, t.Name)
 }
 
+// SchemaResource writes the identifier of a schema resource (e.g. index or trigger)
+// to the builder, prefixed with the schema name if exists.
+func (b *Builder) SchemaResource(s *schema.Schema, name string) *Builder {
+	return b.mayQualify(s, name)
+}
+
 // TableResource writes the table's resource identifier to the builder, prefixed
 // with the schema name if exists.
 func (b *Builder) TableResource(t *schema.Table, r any) *Builder {
@@ -554,6 +586,53 @@ func ExprLastIndex(expr string) int {
 	return -1
 }
 
+// AttrFormatters maps custom attribute types (i.e. attributes that are not supported
+// by the driver) to functions that format them to their SQL representation, allowing
+// options that are not modeled by Atlas to be planned on table and column definitions.
+type AttrFormatters map[reflect.Type]func(schema.Attr) string
+
+// Register registers the function for formatting attributes of the same type as a.
+func (f AttrFormatters) Register(a schema.Attr, fn func(schema.Attr) string) {
+	f[reflect.TypeOf(a)] = fn
+}
+
+// Format returns the SQL representation of the attribute, and reports if it is a custom
+// attribute. schema.RawClause attributes are returned as is.
+func (f AttrFormatters) Format(a schema.Attr) (string, bool) {
+	if c, ok := a.(*schema.RawClause); ok {
+		return c.X, true
+	}
+	if fn, ok := f[reflect.TypeOf(a)]; ok {
+		return fn(a), true
+	}
+	return "", false
+}
+
+// Custom returns the SQL representation of the custom attributes in the list.
+func (f AttrFormatters) Custom(attrs []schema.Attr) []string {
+	var clauses []string
+	for _, a := range attrs {
+		if c, ok := f.Format(a); ok && c != "" {
+			clauses = append(clauses, c)
+		}
+	}
+	return clauses
+}
+
+// Changed reports if the custom attributes of the two lists are different.
+func (f AttrFormatters) Changed(from, to []schema.Attr) bool {
+	c1, c2 := f.Custom(from), f.Custom(to)
+	if len(c1) != len(c2) {
+		return true
+	}
+	for i := range c1 {
+		if c1[i] != c2[i] {
+			return true
+		}
+	}
+	return false
+}
+
 // ReverseChanges reverses the order of the changes.
 func ReverseChanges(c []schema.Change) {
 	for i, n := 0, len(c); i < n/2; i++ {
@@ -6,6 +6,7 @@ package sqlx
 
 import (
 	"strconv"
+	"strings"
 	"testing"
 
 	"ariga.io/atlas/sql/schema"
@@ -247,6 +248,81 @@ func TestReverseChanges(t *testing.T) {
 	}
 }
 
+func TestClassifyConversion(t *testing.T) {
+	var (
+		size  = func(n int) *int { return &n }
+		sizes = map[string]int64{"int": 4, "bigint": 8, "text": 65535, "float": 4, "double": 8}
+	)
+	for i, tt := range []struct {
+		from, to schema.Type
+		want     schema.TypeConversion
+	}{
+		{from: &schema.IntegerType{T: "int"}, to: &schema.IntegerType{T: "bigint"}, want: schema.ConversionWidening},
+		{from: &schema.IntegerType{T: "bigint"}, to: &schema.IntegerType{T: "int"}, want: schema.ConversionNarrowing},
+		{from: &schema.IntegerType{T: "int"}, to: &schema.IntegerType{T: "int", Unsigned: true}, want: schema.ConversionNarrowing},
+		{from: &schema.IntegerType{T: "int", Unsigned: true}, to: &schema.IntegerType{T: "bigint"}, want: schema.ConversionWidening},
+		{from: &schema.IntegerType{T: "int", Unsigned: true}, to: &schema.IntegerType{T: "int"}, want: schema.ConversionNarrowing},
+		{from: &schema.IntegerType{T: "int"}, to: &schema.DecimalType{T: "decimal", Precision: 10}, want: schema.ConversionWidening},
+		{from: &schema.IntegerType{T: "int"}, to: &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}, want: schema.ConversionNarrowing},
+		{from: &schema.IntegerType{T: "int"}, to: &schema.StringType{T: "varchar", Size: 11}, want: schema.ConversionWidening},
+		{from: &schema.IntegerType{T: "smallint"}, to: &schema.IntegerType{T: "int"}, want: schema.ConversionUnknown},
+		{from: &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}, to: &schema.DecimalType{T: "decimal", Precision: 12, Scale: 4}, want: schema.ConversionWidening},
+		{from: &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}, to: &schema.DecimalType{T: "decimal", Precision: 10, Scale: 4}, want: schema.ConversionNarrowing},
+		{from: &schema.FloatType{T: "double"}, to: &schema.FloatType{T: "float"}, want: schema.ConversionNarrowing},
+		{from: &schema.StringType{T: "varchar", Size: 255}, to: &schema.StringType{T: "varchar", Size: 50}, want: schema.ConversionNarrowing},
+		{from: &schema.StringType{T: "varchar", Size: 255}, to: &schema.StringType{T: "text"}, want: schema.ConversionWidening},
+		{from: &schema.StringType{T: "text"}, to: &schema.StringType{T: "varchar", Size: 255}, want: schema.ConversionNarrowing},
+		{from: &schema.EnumType{Values: []string{"a", "b"}}, to: &schema.EnumType{Values: []string{"a", "b", "c"}}, want: schema.ConversionWidening},
+		{from: &schema.EnumType{Values: []string{"a", "b"}}, to: &schema.EnumType{Values: []string{"a"}}, want: schema.ConversionNarrowing},
+		{from: &schema.BinaryType{T: "varbinary", Size: size(10)}, to: &schema.BinaryType{T: "varbinary", Size: size(5)}, want: schema.ConversionNarrowing},
+		{from: &schema.TimeType{T: "date"}, to: &schema.TimeType{T: "datetime"}, want: schema.ConversionWidening},
+		{from: &schema.TimeType{T: "timestamp", Precision: size(6)}, to: &schema.TimeType{T: "timestamp", Precision: size(3)}, want: schema.ConversionNarrowing},
+		{from: &schema.JSONType{T: "json"}, to: &schema.IntegerType{T: "int"}, want: schema.ConversionIncompatible},
+		{from: &schema.JSONType{T: "json"}, to: &schema.JSONType{T: "jsonb"}, want: schema.ConversionUnknown},
+	} {
+		require.Equal(t, tt.want, ClassifyConversion(tt.from, tt.to, sizes), strconv.Itoa(i))
+	}
+}
+
+func TestCheckDiff(t *testing.T) {
+	from := schema.NewTable("t").AddChecks(
+		schema.NewCheck().SetName("c1").SetExpr("a > 0"),
+		schema.NewCheck().SetName("c2").SetExpr("b > 0"),
+		schema.NewCheck().SetName("c3").SetExpr("c > 0"),
+	)
+	to := schema.NewTable("t").AddChecks(
+		schema.NewCheck().SetName("c1").SetExpr("a > 0").SetEnforced(true),
+		schema.NewCheck().SetName("c2").SetExpr("b > 0").SetEnforced(false),
+		schema.NewCheck().SetName("c4").SetExpr("d > 0"),
+	)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyCheck{From: from.Attrs[1].(*schema.Check), To: to.Attrs[1].(*schema.Check)},
+		&schema.DropCheck{C: from.Attrs[2].(*schema.Check)},
+		&schema.AddCheck{C: to.Attrs[2].(*schema.Check)},
+	}, CheckDiff(from, to))
+	// Custom comparison.
+	require.Len(t, CheckDiff(from, to, func(_, _ *schema.Check) bool { return true }), 2)
+}
+
+func TestGeneratedChanged(t *testing.T) {
+	virtual := func(s string) string {
+		if s == "" {
+			return schema.GeneratedVirtual
+		}
+		return strings.ToUpper(s)
+	}
+	from := schema.NewIntColumn("c", "int").SetGeneratedExpr(&schema.GeneratedExpr{Expr: "a*2"})
+	to := schema.NewIntColumn("c", "int").SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(a*2)", Type: "virtual"})
+	require.False(t, GeneratedChanged(from, to, virtual))
+	to.SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(a*2)", Type: schema.GeneratedStored})
+	require.True(t, GeneratedChanged(from, to, virtual))
+	to.SetGeneratedExpr(&schema.GeneratedExpr{Expr: "a*3"})
+	require.True(t, GeneratedChanged(from, to, virtual))
+	require.True(t, GeneratedChanged(from, schema.NewIntColumn("c", "int"), virtual))
+	require.True(t, GeneratedChanged(schema.NewIntColumn("c", "int"), to, virtual))
+	require.False(t, GeneratedChanged(schema.NewIntColumn("c", "int"), schema.NewIntColumn("c", "int"), virtual))
+}
+
 func TestIsUint(t *testing.T) {
 	require.True(t, IsUint("1"))
 	require.False(t, IsUint("-1"))
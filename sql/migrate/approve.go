@@ -0,0 +1,97 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// Approver is consulted by ApplyChanges between planning the changes and applying them on the
+	// database. It receives the full plan, and decides if it can be applied as is, denied, or replaced
+	// with a modified plan. For example, an Approver can prompt the user on the terminal, wait for an
+	// approval from a chat channel, or evaluate the plan against a policy engine.
+	Approver interface {
+		Approve(context.Context, *Plan) (*Approval, error)
+	}
+
+	// The ApproverFunc type is an adapter to allow the
+	// use of ordinary functions as plan approvers.
+	ApproverFunc func(context.Context, *Plan) (*Approval, error)
+
+	// Approval describes the decision of an Approver on a plan.
+	Approval struct {
+		Decision Decision
+		// Plan to apply instead of the planned one. Set only
+		// if the Decision is PlanModified.
+		Plan *Plan
+		// Reason describes the decision, e.g. the user who approved
+		// the plan, or the policy that was violated by it.
+		Reason string
+	}
+
+	// Decision of an Approver on a plan.
+	Decision uint8
+
+	// PlanDeniedError is returned when an Approver denies a plan.
+	PlanDeniedError struct {
+		Plan   string // Name of the plan.
+		Reason string // Reason of the denial, if given.
+	}
+)
+
+// List of approval decisions. Note, the zero value denies the plan.
+const (
+	PlanDenied   Decision = iota // The plan must not be applied.
+	PlanApproved                 // The plan can be applied as is.
+	PlanModified                 // The modified plan can be applied instead.
+)
+
+// Approve calls f(ctx, p).
+func (f ApproverFunc) Approve(ctx context.Context, p *Plan) (*Approval, error) {
+	return f(ctx, p)
+}
+
+// Error implements the error interface.
+func (e *PlanDeniedError) Error() string {
+	msg := fmt.Sprintf("sql/migrate: plan %q was denied", e.Plan)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// ApplyWithApprover returns a PlanOption that makes ApplyChanges consult the given
+// Approver before the planned changes are applied. Dry-runs are not approved.
+func ApplyWithApprover(a Approver) PlanOption {
+	return func(o *PlanOptions) {
+		o.Approver = a
+	}
+}
+
+// Approve consults the given Approver on the plan, and returns the plan that can be applied,
+// which is either the given plan or the one that was modified by the Approver. A PlanDeniedError
+// is returned in case the plan was denied.
+func Approve(ctx context.Context, a Approver, p *Plan) (*Plan, error) {
+	r, err := a.Approve(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("sql/migrate: approve plan %q: %w", p.Name, err)
+	}
+	if r == nil {
+		return nil, &PlanDeniedError{Plan: p.Name}
+	}
+	switch r.Decision {
+	case PlanApproved:
+		return p, nil
+	case PlanModified:
+		if r.Plan == nil {
+			return nil, fmt.Errorf("sql/migrate: approve plan %q: modified plan was not provided", p.Name)
+		}
+		return r.Plan, nil
+	default:
+		return nil, &PlanDeniedError{Plan: p.Name, Reason: r.Reason}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprove(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		plan = &migrate.Plan{Name: "add_t", Changes: []*migrate.Change{{Cmd: "CREATE TABLE t(c int)"}}}
+	)
+	approve := func(r *migrate.Approval, err error) migrate.Approver {
+		return migrate.ApproverFunc(func(_ context.Context, p *migrate.Plan) (*migrate.Approval, error) {
+			require.Equal(t, plan, p)
+			return r, err
+		})
+	}
+	p, err := migrate.Approve(ctx, approve(&migrate.Approval{Decision: migrate.PlanApproved}, nil), plan)
+	require.NoError(t, err)
+	require.Equal(t, plan, p)
+
+	modified := &migrate.Plan{Name: "add_t"}
+	p, err = migrate.Approve(ctx, approve(&migrate.Approval{Decision: migrate.PlanModified, Plan: modified}, nil), plan)
+	require.NoError(t, err)
+	require.Equal(t, modified, p)
+	_, err = migrate.Approve(ctx, approve(&migrate.Approval{Decision: migrate.PlanModified}, nil), plan)
+	require.EqualError(t, err, `sql/migrate: approve plan "add_t": modified plan was not provided`)
+
+	_, err = migrate.Approve(ctx, approve(&migrate.Approval{Decision: migrate.PlanDenied, Reason: "rejected by a8m"}, nil), plan)
+	var derr *migrate.PlanDeniedError
+	require.ErrorAs(t, err, &derr)
+	require.EqualError(t, err, `sql/migrate: plan "add_t" was denied: rejected by a8m`)
+	_, err = migrate.Approve(ctx, approve(&migrate.Approval{}, nil), plan)
+	require.EqualError(t, err, `sql/migrate: plan "add_t" was denied`)
+	_, err = migrate.Approve(ctx, approve(nil, nil), plan)
+	require.ErrorAs(t, err, &derr)
+
+	_, err = migrate.Approve(ctx, approve(nil, errors.New("timeout")), plan)
+	require.EqualError(t, err, `sql/migrate: approve plan "add_t": timeout`)
+}
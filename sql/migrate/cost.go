@@ -0,0 +1,139 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"fmt"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// Cost describes the estimated cost class of a Change. Classes are
+// ordered by their impact, from the cheapest to the most risky one.
+type Cost uint
+
+// List of cost classes.
+const (
+	CostUnknown      Cost = iota // Cost was not estimated.
+	CostMetadata                 // Metadata-only change, e.g. creating a table or renaming a column.
+	CostIndexBuild               // Builds an index, or scans the table for validating a constraint.
+	CostTableRewrite             // Rewrites (copies) the table, e.g. changing a column type.
+	CostDataLoss                 // Drops data, e.g. dropping a table or a column.
+)
+
+var costNames = [...]string{
+	CostUnknown:      "unknown",
+	CostMetadata:     "metadata",
+	CostIndexBuild:   "index_build",
+	CostTableRewrite: "table_rewrite",
+	CostDataLoss:     "data_loss",
+}
+
+// String implements fmt.Stringer.
+func (c Cost) String() string {
+	if int(c) < len(costNames) {
+		return costNames[c]
+	}
+	return fmt.Sprintf("Cost(%d)", c)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Cost) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Cost) UnmarshalText(b []byte) error {
+	for i, n := range costNames {
+		if n == string(b) {
+			*c = Cost(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("sql/migrate: unknown cost %q", b)
+}
+
+type (
+	// CostEstimator is an optional interface implemented by drivers for estimating the cost of
+	// changes based on their knowledge of the database. e.g. the changes that are applied instantly
+	// or by copying the table. A CostUnknown falls back to the DefaultCost estimation.
+	CostEstimator interface {
+		EstimateCost(schema.Change) Cost
+	}
+
+	// TableStats returns the number of rows in the given table, if it is known.
+	TableStats func(*schema.Table) (rows int64, ok bool)
+)
+
+// EstimateCosts sets the Cost of each change in the plan that has a Source, and returns the highest
+// cost in the plan. Costs are estimated by the driver, if it implements the CostEstimator interface,
+// or by DefaultCost otherwise. If table statistics are provided, changes of tables that are known to
+// be empty are considered metadata-only changes.
+func EstimateCosts(drv Driver, p *Plan, stats TableStats) Cost {
+	var maxC Cost
+	for _, c := range p.Changes {
+		if c.Source == nil {
+			continue
+		}
+		c.Cost = CostUnknown
+		if e, ok := drv.(CostEstimator); ok {
+			c.Cost = e.EstimateCost(c.Source)
+		}
+		if c.Cost == CostUnknown {
+			c.Cost = DefaultCost(c.Source)
+		}
+		if t := changeTable(c.Source); c.Cost > CostMetadata && t != nil && stats != nil {
+			if rows, ok := stats(t); ok && rows == 0 {
+				c.Cost = CostMetadata
+			}
+		}
+		if c.Cost > maxC {
+			maxC = c.Cost
+		}
+	}
+	return maxC
+}
+
+// DefaultCost returns the cost of the given change, as estimated
+// for common databases without relying on specific driver knowledge.
+func DefaultCost(c schema.Change) Cost {
+	switch c := c.(type) {
+	case *schema.DropSchema, *schema.DropTable, *schema.DropColumn:
+		return CostDataLoss
+	case *schema.ModifyTable:
+		maxC := CostMetadata
+		for _, c := range c.Changes {
+			if cc := DefaultCost(c); cc > maxC {
+				maxC = cc
+			}
+		}
+		return maxC
+	case *schema.ModifyColumn:
+		switch {
+		case c.Change.Is(schema.ChangeType):
+			return CostTableRewrite
+		case c.Change.Is(schema.ChangeNull) && c.From.Type != nil && c.From.Type.Null && c.To.Type != nil && !c.To.Type.Null:
+			return CostIndexBuild
+		}
+		return CostMetadata
+	case *schema.AddPrimaryKey, *schema.ModifyPrimaryKey:
+		return CostTableRewrite
+	case *schema.AddIndex, *schema.ModifyIndex, *schema.AddForeignKey, *schema.ModifyForeignKey, *schema.AddCheck, *schema.ModifyCheck:
+		return CostIndexBuild
+	default:
+		return CostMetadata
+	}
+}
+
+// changeTable returns the existing table that is affected by the change, if any.
+func changeTable(c schema.Change) *schema.Table {
+	switch c := c.(type) {
+	case *schema.DropTable:
+		return c.T
+	case *schema.ModifyTable:
+		return c.T
+	}
+	return nil
+}
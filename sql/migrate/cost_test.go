@@ -0,0 +1,89 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultCost(t *testing.T) {
+	var (
+		t1 = schema.NewTable("t1")
+		c1 = schema.NewIntColumn("c1", "int")
+		c2 = schema.NewNullIntColumn("c2", "int")
+	)
+	for _, tt := range []struct {
+		change schema.Change
+		cost   migrate.Cost
+	}{
+		{change: &schema.AddTable{T: t1}, cost: migrate.CostMetadata},
+		{change: &schema.DropTable{T: t1}, cost: migrate.CostDataLoss},
+		{change: &schema.ModifyTable{T: t1}, cost: migrate.CostMetadata},
+		{change: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddColumn{C: c1}}}, cost: migrate.CostMetadata},
+		{change: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddColumn{C: c1}, &schema.AddIndex{I: schema.NewIndex("i")}}}, cost: migrate.CostIndexBuild},
+		{change: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.ModifyColumn{From: c2, To: c1, Change: schema.ChangeNull}}}, cost: migrate.CostIndexBuild},
+		{change: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.ModifyColumn{From: c1, To: c2, Change: schema.ChangeNull}}}, cost: migrate.CostMetadata},
+		{change: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.ModifyColumn{From: c1, To: c2, Change: schema.ChangeType | schema.ChangeNull}}}, cost: migrate.CostTableRewrite},
+		{change: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddPrimaryKey{P: schema.NewPrimaryKey(c1)}}}, cost: migrate.CostTableRewrite},
+		{change: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddIndex{}, &schema.DropColumn{C: c2}}}, cost: migrate.CostDataLoss},
+	} {
+		require.Equal(t, tt.cost, migrate.DefaultCost(tt.change))
+	}
+}
+
+func TestEstimateCosts(t *testing.T) {
+	var (
+		t1   = schema.NewTable("t1")
+		t2   = schema.NewTable("t2")
+		plan = &migrate.Plan{
+			Changes: []*migrate.Change{
+				{Cmd: "CREATE TABLE t3(c int)", Source: &schema.AddTable{T: schema.NewTable("t3")}},
+				{Cmd: "CREATE INDEX i ON t1(c)", Source: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddIndex{I: schema.NewIndex("i")}}}},
+				{Cmd: "DROP TABLE t2", Source: &schema.DropTable{T: t2}},
+				{Cmd: "VACUUM"},
+			},
+		}
+	)
+	require.Equal(t, migrate.CostDataLoss, migrate.EstimateCosts(&mockDriver{}, plan, nil))
+	require.Equal(t, migrate.CostMetadata, plan.Changes[0].Cost)
+	require.Equal(t, migrate.CostIndexBuild, plan.Changes[1].Cost)
+	require.Equal(t, migrate.CostDataLoss, plan.Changes[2].Cost)
+	require.Equal(t, migrate.CostUnknown, plan.Changes[3].Cost)
+
+	// Changes of empty tables are metadata-only.
+	require.Equal(t, migrate.CostIndexBuild, migrate.EstimateCosts(&mockDriver{}, plan, func(t *schema.Table) (int64, bool) {
+		return 0, t.Name == "t2"
+	}))
+	require.Equal(t, migrate.CostIndexBuild, plan.Changes[1].Cost)
+	require.Equal(t, migrate.CostMetadata, plan.Changes[2].Cost)
+
+	// Driver estimations take precedence.
+	require.Equal(t, migrate.CostDataLoss, migrate.EstimateCosts(costDriver{&mockDriver{}}, plan, nil))
+	require.Equal(t, migrate.CostTableRewrite, plan.Changes[1].Cost)
+	require.Equal(t, migrate.CostDataLoss, plan.Changes[2].Cost)
+
+	b, err := json.Marshal(plan.Changes[1])
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"Cost":"table_rewrite"`)
+	var c migrate.Change
+	require.NoError(t, json.Unmarshal(b, &c))
+	require.Equal(t, migrate.CostTableRewrite, c.Cost)
+}
+
+// costDriver estimates all table modifications as table rewrites.
+type costDriver struct{ *mockDriver }
+
+func (costDriver) EstimateCost(c schema.Change) migrate.Cost {
+	if _, ok := c.(*schema.ModifyTable); ok {
+		return migrate.CostTableRewrite
+	}
+	return migrate.CostUnknown
+}
@@ -15,13 +15,17 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
 	"time"
+
+	"ariga.io/atlas/sql/schema"
 )
 
 type (
@@ -59,9 +63,27 @@ type (
 		// Format formats the given Plan into one or more migration files.
 		Format(*Plan) ([]File, error)
 	}
+
+	// DirFormatter is an optional interface implemented by Formatters that format
+	// plans based on the files of the migration directory, e.g. sequential naming.
+	// If implemented, it is used by the Planner instead of the Format method.
+	DirFormatter interface {
+		Formatter
+		// FormatDir formats the given Plan into one or more
+		// migration files that are written to the given Dir.
+		FormatDir(Dir, *Plan) ([]File, error)
+	}
 )
 
 type (
+	// RemoveDir is an optional interface implemented by migration
+	// directories that support removing files, e.g. when squashed.
+	RemoveDir interface {
+		Dir
+		// RemoveFile removes the named file from the migration directory.
+		RemoveFile(string) error
+	}
+
 	// CheckpointDir wraps the functionality used to interact
 	// with a migration directory that support checkpoints.
 	CheckpointDir interface {
@@ -92,6 +114,16 @@ type (
 		// An ErrNotCheckpoint is returned if the file is not a checkpoint file.
 		CheckpointTag() (string, error)
 	}
+
+	// DownFile is implemented by files that hold the statements for reverting
+	// them, e.g. the down files of migration directories of other tools, or the
+	// files of a LocalDir that have a down file. See DownFileName.
+	DownFile interface {
+		File
+		// DownStmts returns the statements for reverting the migration file, or
+		// nil if the migration has no down statements.
+		DownStmts() ([]string, error)
+	}
 )
 
 var (
@@ -137,10 +169,24 @@ func (d *LocalDir) Open(name string) (fs.File, error) {
 
 // WriteFile implements Dir.WriteFile.
 func (d *LocalDir) WriteFile(name string, b []byte) error {
-	return os.WriteFile(filepath.Join(d.path, name), b, 0644)
+	p := filepath.Join(d.path, name)
+	// Files in subdirectories, like down files, create their directory on write.
+	if dir := filepath.Dir(p); dir != filepath.Clean(d.path) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(p, b, 0644)
+}
+
+// RemoveFile implements RemoveDir.RemoveFile.
+func (d *LocalDir) RemoveFile(name string) error {
+	return os.Remove(filepath.Join(d.path, name))
 }
 
 // Files implements Dir.Files. It looks for all files with .sql suffix and orders them by filename.
+// Files that have a file with the same name in the "down" subdirectory are reversible, and their
+// down statements are read from it. See DownFile for more info.
 func (d *LocalDir) Files() ([]File, error) {
 	names, err := fs.Glob(d, "*.sql")
 	if err != nil {
@@ -156,7 +202,14 @@ func (d *LocalDir) Files() ([]File, error) {
 		if err != nil {
 			return nil, fmt.Errorf("sql/migrate: read file %q: %w", n, err)
 		}
-		files = append(files, NewLocalFile(n, b))
+		f := NewLocalFile(n, b)
+		switch down, err := fs.ReadFile(d, DownFileName(n)); {
+		case err == nil:
+			f.down = down
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("sql/migrate: read down file of %q: %w", n, err)
+		}
+		files = append(files, f)
 	}
 	return files, nil
 }
@@ -195,8 +248,15 @@ func (d *LocalDir) FilesFromCheckpoint(name string) ([]File, error) {
 
 // LocalFile is used by LocalDir to implement the Scanner interface.
 type LocalFile struct {
-	n string
-	b []byte
+	n    string
+	b    []byte
+	down []byte // content of the down file, if exists.
+}
+
+// DownFileName returns the name of the down file of the given migration file,
+// relative to its migration directory. e.g. "down/1_init.sql" for "1_init.sql".
+func DownFileName(name string) string {
+	return path.Join("down", name)
 }
 
 var _ CheckpointFile = (*LocalFile)(nil)
@@ -238,6 +298,15 @@ func (f *LocalFile) Stmts() ([]string, error) {
 	return stmts, nil
 }
 
+// DownStmts implements the DownFile interface. It returns the statements of the down file
+// of the migration file, or nil if it has no down file. See DownFileName for more info.
+func (f *LocalFile) DownStmts() ([]string, error) {
+	if f.down == nil {
+		return nil, nil
+	}
+	return (&LocalFile{n: DownFileName(f.n), b: f.down}).Stmts()
+}
+
 // StmtDecls returns the all statement declarations exist in the local file.
 func (f *LocalFile) StmtDecls() ([]*Stmt, error) {
 	return Stmts(string(f.b))
@@ -430,6 +499,15 @@ func (d *MemDir) WriteFile(name string, data []byte) error {
 	return nil
 }
 
+// RemoveFile implements RemoveDir.RemoveFile.
+func (d *MemDir) RemoveFile(name string) error {
+	if _, ok := d.files[name]; !ok {
+		return fs.ErrNotExist
+	}
+	delete(d.files, name)
+	return nil
+}
+
 // WriteCheckpoint is like WriteFile, but marks the file as a checkpoint file.
 func (d *MemDir) WriteCheckpoint(name, tag string, b []byte) error {
 	var (
@@ -463,7 +541,15 @@ func (d *MemDir) SyncWrites(fs ...func(string, []byte) error) {
 func (d *MemDir) Files() ([]File, error) {
 	files := make([]File, 0, len(d.files))
 	for _, f := range d.files {
-		if filepath.Ext(f.Name()) == ".sql" {
+		// Files in subdirectories (e.g. down files) are not migration files.
+		if filepath.Ext(f.Name()) != ".sql" || strings.Contains(f.Name(), "/") {
+			continue
+		}
+		if down, ok := d.files[DownFileName(f.Name())]; ok {
+			f1 := *f
+			f1.down = down.b
+			files = append(files, &f1)
+		} else {
 			files = append(files, f)
 		}
 	}
@@ -487,6 +573,9 @@ var (
 	templateFuncs = template.FuncMap{
 		"upper": strings.ToUpper,
 		"now":   func() string { return time.Now().UTC().Format("20060102150405") },
+		// Functions for composing custom content templates.
+		"comment": commentLines,
+		"stmts":   formatStmts,
 	}
 	// DefaultFormatter is a default implementation for Formatter.
 	DefaultFormatter = TemplateFormatter{
@@ -495,12 +584,64 @@ var (
 				"{{ with .Version }}{{ . }}{{ else }}{{ now }}{{ end }}{{ with .Name }}_{{ . }}{{ end }}.sql",
 			)),
 			C: template.Must(template.New("").Funcs(templateFuncs).Parse(
-				`{{ range .Changes }}{{ with .Comment }}{{ printf "-- %s%s\n" (slice . 0 1 | upper ) (slice . 1) }}{{ end }}{{ printf "%s;\n" .Cmd }}{{ end }}`,
+				`{{ range .Changes }}{{ with .Comment }}{{ printf "-- %s%s\n" (slice . 0 1 | upper ) (slice . 1) }}{{ end }}{{ range $k, $v := .Annotations }}{{ comment (printf "%s: %s" $k $v) }}{{ end }}{{ printf "%s;\n" .Cmd }}{{ end }}`,
 			)),
 		},
 	}
 )
 
+// TemplateFuncs returns the functions that are available to the templates of the DefaultFormatter,
+// to allow custom formatters enforce their conventions (e.g. header comments or ticket ids) without
+// re-implementing the default formatting. For example:
+//
+//	f := migrate.NewTemplateFormatter(
+//		template.Must(template.New("").Funcs(migrate.TemplateFuncs()).Parse("{{ now }}_{{ .Name }}.sql")),
+//		template.Must(template.New("").Funcs(migrate.TemplateFuncs()).Parse(`{{ comment "Ticket: DB-42" }}{{ stmts . }}`)),
+//	)
+func TemplateFuncs() template.FuncMap {
+	funcs := make(template.FuncMap, len(templateFuncs))
+	for k, v := range templateFuncs {
+		funcs[k] = v
+	}
+	return funcs
+}
+
+// commentLines formats the given text as an SQL comment.
+func commentLines(s string) string {
+	var b strings.Builder
+	for _, l := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		b.WriteString(strings.TrimSpace("-- "+l) + "\n")
+	}
+	return b.String()
+}
+
+// annotationLines formats the annotations as SQL comments, sorted by their keys.
+func annotationLines(a map[string]string) string {
+	keys := make([]string, 0, len(a))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(commentLines(k + ": " + a[k]))
+	}
+	return b.String()
+}
+
+// formatStmts formats the changes of the plan as they are formatted by the DefaultFormatter.
+func formatStmts(p *Plan) string {
+	var b strings.Builder
+	for _, c := range p.Changes {
+		if c.Comment != "" {
+			b.WriteString(commentLines(strings.ToUpper(c.Comment[:1]) + c.Comment[1:]))
+		}
+		b.WriteString(annotationLines(c.Annotations))
+		b.WriteString(c.Cmd + ";\n")
+	}
+	return b.String()
+}
+
 // TemplateFormatter implements Formatter by using templates.
 type TemplateFormatter []struct{ N, C *template.Template }
 
@@ -540,6 +681,159 @@ func (t TemplateFormatter) Format(plan *Plan) ([]File, error) {
 	return files, nil
 }
 
+type (
+	// FormatOption configures the Formatter that is returned by NewFormatter.
+	FormatOption func(*formatter)
+
+	// formatter is a configurable Formatter. Files are named as in DefaultFormatter.
+	formatter struct {
+		noComments bool   // omit the comments of the changes.
+		generator  string // generated-by header, if not empty.
+		hash       string // source hash of the header, if not empty.
+		group      bool   // group statements per table.
+		eol        string // line ending.
+	}
+)
+
+// FormatComments configures whether the comments that describe the changes (e.g. `-- Create "users"
+// table`) are written before their statements. Comments are written by default.
+func FormatComments(b bool) FormatOption {
+	return func(f *formatter) {
+		f.noComments = !b
+	}
+}
+
+// FormatHeader adds a header to the formatted files, describing the tool (or the user) that generated
+// them, and the hash of the source (e.g. the HCL files of the desired state) they were generated from.
+// An empty hash is omitted from the header.
+func FormatHeader(generator, hash string) FormatOption {
+	return func(f *formatter) {
+		f.generator, f.hash = generator, hash
+	}
+}
+
+// FormatGroupByTable groups consecutive statements of the same table under a comment with the table name,
+// separated by empty lines. Statements are not reordered, as the order of a plan can not be changed safely.
+func FormatGroupByTable(b bool) FormatOption {
+	return func(f *formatter) {
+		f.group = b
+	}
+}
+
+// FormatLineEnding sets the line ending of the formatted files. Defaults to "\n".
+// Note, the indentation of statements is configured by the PlanWithIndent option.
+func FormatLineEnding(eol string) FormatOption {
+	return func(f *formatter) {
+		f.eol = eol
+	}
+}
+
+// NewFormatter returns a Formatter that names files as the DefaultFormatter, and formats
+// their content according to the given options. For example:
+//
+//	migrate.NewPlanner(drv, dir, migrate.PlanFormat(migrate.NewFormatter(
+//		migrate.FormatHeader("atlas", sum),
+//		migrate.FormatGroupByTable(true),
+//	)))
+func NewFormatter(opts ...FormatOption) Formatter {
+	f := &formatter{eol: "\n"}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Format implements the Formatter interface.
+func (f *formatter) Format(plan *Plan) ([]File, error) {
+	var n, b strings.Builder
+	if err := DefaultFormatter[0].N.Execute(&n, plan); err != nil {
+		return nil, err
+	}
+	if f.generator != "" {
+		b.WriteString(commentLines("Generated by " + f.generator + "."))
+		if f.hash != "" {
+			b.WriteString(commentLines("Source hash: " + f.hash))
+		}
+		b.WriteString("\n")
+	}
+	var last *schema.Table
+	for i, c := range plan.Changes {
+		if t := sourceTable(c.Source); f.group && t != nil && t != last {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(commentLines(fmt.Sprintf("Table %q", t.Name)))
+			last = t
+		}
+		if c.Comment != "" && !f.noComments {
+			b.WriteString(commentLines(strings.ToUpper(c.Comment[:1]) + c.Comment[1:]))
+		}
+		b.WriteString(annotationLines(c.Annotations))
+		b.WriteString(c.Cmd + ";\n")
+	}
+	content := b.String()
+	if f.eol != "\n" {
+		content = strings.ReplaceAll(content, "\n", f.eol)
+	}
+	return []File{NewLocalFile(n.String(), []byte(content))}, nil
+}
+
+// sourceTable returns the table that is created, or affected, by the change, if any.
+func sourceTable(c schema.Change) *schema.Table {
+	switch c := c.(type) {
+	case *schema.AddTable:
+		return c.T
+	case *schema.RenameTable:
+		return c.To
+	}
+	return changeTable(c)
+}
+
+// SequentialFormatter wraps the given Formatter and sets the version of plans that have no version
+// to the next sequential number in the migration directory, padded with zeros to the given width.
+// For example, a directory with a file named "000002_add_users.sql" is followed by "000003_<name>.sql".
+//
+//	migrate.NewPlanner(drv, dir, migrate.PlanFormat(migrate.SequentialFormatter(migrate.DefaultFormatter, 6)))
+func SequentialFormatter(f Formatter, width int) DirFormatter {
+	return &seqFormatter{Formatter: f, width: width}
+}
+
+type seqFormatter struct {
+	Formatter
+	width int
+}
+
+// Format implements the Formatter interface. Plans with no version are
+// formatted as the first version, as the migration directory is unknown.
+func (f *seqFormatter) Format(p *Plan) ([]File, error) {
+	return f.format(p, 1)
+}
+
+// FormatDir implements the DirFormatter interface.
+func (f *seqFormatter) FormatDir(dir Dir, p *Plan) ([]File, error) {
+	files, err := dir.Files()
+	if err != nil {
+		return nil, err
+	}
+	var last uint64
+	for _, file := range files {
+		// Files with non-numeric versions are ignored.
+		if v, err := strconv.ParseUint(file.Version(), 10, 64); err == nil && v > last {
+			last = v
+		}
+	}
+	return f.format(p, last+1)
+}
+
+func (f *seqFormatter) format(p *Plan, v uint64) ([]File, error) {
+	if p.Version != "" {
+		return f.Formatter.Format(p)
+	}
+	p1 := *p
+	p1.Version = fmt.Sprintf("%0*d", f.width, v)
+	return f.Formatter.Format(&p1)
+}
+
 // HashFileName of the migration directory integrity sum file.
 const HashFileName = "atlas.sum"
 
@@ -564,6 +858,18 @@ func NewHashFile(files []File) (HashFile, error) {
 			return nil, err
 		}
 		hs = append(hs, struct{ N, H string }{f.Name(), base64.StdEncoding.EncodeToString(h.Sum(nil))})
+		// Down files are hashed after their migration files,
+		// as changing them changes how the migration is reverted.
+		if lf, ok := f.(*LocalFile); ok && lf.down != nil {
+			n := DownFileName(f.Name())
+			if _, err := h.Write([]byte(n)); err != nil {
+				return nil, err
+			}
+			if _, err := h.Write(lf.down); err != nil {
+				return nil, err
+			}
+			hs = append(hs, struct{ N, H string }{n, base64.StdEncoding.EncodeToString(h.Sum(nil))})
+		}
 	}
 	return hs, nil
 }
@@ -577,6 +883,17 @@ func WriteSumFile(dir Dir, sum HashFile) error {
 	return dir.WriteFile(HashFileName, b)
 }
 
+// Rehash computes the checksum of the Dir and writes it to its sum file. It is
+// used for accepting intentional changes made to the migration files, as the
+// Executor refuses to run on a directory that is out of sync with its sum file.
+func Rehash(dir Dir) error {
+	sum, err := dir.Checksum()
+	if err != nil {
+		return err
+	}
+	return WriteSumFile(dir, sum)
+}
+
 // Sum returns the checksum of the represented hash file.
 func (f HashFile) Sum() string {
 	sha := sha256.New()
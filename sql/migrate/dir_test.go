@@ -7,9 +7,15 @@ package migrate_test
 import (
 	"archive/tar"
 	"bytes"
+	"context"
 	_ "embed"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"path/filepath"
 	"testing"
 	"time"
@@ -130,6 +136,93 @@ func TestValidate(t *testing.T) {
 	require.Equal(t, migrate.ErrChecksumMismatch, migrate.Validate(d))
 }
 
+func TestRehash(t *testing.T) {
+	d, err := migrate.NewLocalDir(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, d.WriteFile("1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.ErrorIs(t, migrate.Validate(d), migrate.ErrChecksumNotFound)
+	require.NoError(t, migrate.Rehash(d))
+	require.NoError(t, migrate.Validate(d))
+
+	// Intentional edits are accepted after re-hashing the directory.
+	require.NoError(t, d.WriteFile("1.sql", []byte("CREATE TABLE t1(c bigint);")))
+	require.ErrorIs(t, migrate.Validate(d), migrate.ErrChecksumMismatch)
+	require.NoError(t, migrate.Rehash(d))
+	require.NoError(t, migrate.Validate(d))
+}
+
+func TestCheckMerge(t *testing.T) {
+	base, branch := &migrate.MemDir{}, &migrate.MemDir{}
+	for _, d := range []*migrate.MemDir{base, branch} {
+		require.NoError(t, d.WriteFile("20220101000000_init.sql", []byte("CREATE TABLE t1(c int);")))
+	}
+	require.NoError(t, base.WriteFile("20220102000000_t2.sql", []byte("CREATE TABLE t2(c int);")))
+	require.NoError(t, base.WriteFile("20220104000000_t3.sql", []byte("CREATE TABLE t3(c int);")))
+	require.NoError(t, migrate.Rehash(base))
+	require.NoError(t, branch.WriteFile("20220102000000_t4.sql", []byte("CREATE TABLE t4(c int);")))
+	require.NoError(t, branch.WriteFile("20220103000000_t5.sql", []byte("CREATE TABLE t5(c int);")))
+	require.NoError(t, branch.WriteFile(migrate.DownFileName("20220103000000_t5.sql"), []byte("DROP TABLE t5;")))
+	require.NoError(t, migrate.Rehash(branch))
+
+	// Files of the branch were added before the last file of the base.
+	r, err := migrate.CheckMerge(branch, base)
+	require.NoError(t, err)
+	require.False(t, r.OK())
+	require.False(t, r.SumMismatch)
+	require.Equal(t, "20220104000000", r.Last)
+	require.Len(t, r.Conflicts, 1)
+	require.Equal(t, "20220102000000", r.Conflicts[0].Version)
+	require.Equal(t, "20220102000000_t4.sql", r.Conflicts[0].Branch.Name())
+	require.Equal(t, "20220102000000_t2.sql", r.Conflicts[0].Base.Name())
+	require.Len(t, r.Unordered, 2)
+	require.Equal(t, "20220102000000_t4.sql", r.Unordered[0].Name())
+	require.Equal(t, "20220103000000_t5.sql", r.Unordered[1].Name())
+
+	// The sum file was taken from the base branch after the merge.
+	for _, n := range []string{"20220102000000_t2.sql", "20220104000000_t3.sql", migrate.HashFileName} {
+		b, err := base.Open(n)
+		require.NoError(t, err)
+		c, err := io.ReadAll(b)
+		require.NoError(t, err)
+		require.NoError(t, branch.WriteFile(n, c))
+	}
+	r, err = migrate.CheckMerge(branch, base)
+	require.NoError(t, err)
+	require.True(t, r.SumMismatch)
+	require.Len(t, r.Conflicts, 1)
+	require.Len(t, r.Unordered, 2)
+
+	require.NoError(t, r.Rebase(branch))
+	require.NoError(t, migrate.Validate(branch))
+	files, err := branch.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 5)
+	for i, n := range []string{
+		"20220101000000_init.sql",
+		"20220102000000_t2.sql",
+		"20220104000000_t3.sql",
+		"20220104000001_t4.sql",
+		"20220104000002_t5.sql",
+	} {
+		require.Equal(t, n, files[i].Name())
+	}
+	require.Equal(t, "CREATE TABLE t4(c int);", string(files[3].Bytes()))
+	down, err := files[4].(*migrate.LocalFile).DownStmts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"DROP TABLE t5;"}, down)
+	_, err = branch.Open(migrate.DownFileName("20220103000000_t5.sql"))
+	require.Error(t, err)
+	r, err = migrate.CheckMerge(branch, base)
+	require.NoError(t, err)
+	require.True(t, r.OK())
+
+	// New versions must not be used by other files.
+	err = migrate.RebaseFiles(branch, "20220104000000", files[4])
+	require.EqualError(t, err, `sql/migrate: rebase: version "20220104000001" of file "20220104000002_t5.sql" is already used by file "20220104000001_t4.sql"`)
+	err = migrate.RebaseFiles(branch, "v1", files[3])
+	require.EqualError(t, err, `sql/migrate: rebase: version "v1" is not numeric`)
+}
+
 func TestHash_MarshalText(t *testing.T) {
 	d, err := migrate.NewLocalDir("testdata/migrate")
 	require.NoError(t, err)
@@ -174,12 +267,26 @@ func TestLocalDir(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "content", string(c))
 
+	// Down files are read from the "down" subdirectory.
+	require.NoError(t, d.WriteFile("1_t.sql", []byte("CREATE TABLE t(c int);")))
+	require.NoError(t, d.WriteFile("2_t.sql", []byte("ALTER TABLE t ADD d int;")))
+	require.NoError(t, d.WriteFile(migrate.DownFileName("2_t.sql"), []byte("ALTER TABLE t DROP d;")))
+	files, err := d.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	down, err := files[0].(migrate.DownFile).DownStmts()
+	require.NoError(t, err)
+	require.Nil(t, down)
+	down, err = files[1].(migrate.DownFile).DownStmts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"ALTER TABLE t DROP d;"}, down)
+
 	// Default Dir implementation.
 	d, err = migrate.NewLocalDir("testdata/migrate/sub")
 	require.NoError(t, err)
 	require.NotNil(t, d)
 
-	files, err := d.Files()
+	files, err = d.Files()
 	require.NoError(t, err)
 	require.Len(t, files, 3)
 	require.Equal(t, "1.a_sub.up.sql", files[0].Name())
@@ -311,6 +418,26 @@ func TestMemDir(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, files, 1) // 1.sql
 
+	// Down files are not migration files.
+	require.NoError(t, d.WriteFile(migrate.DownFileName("1.sql"), []byte("drop table t;")))
+	files, err = d.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	down, err := files[0].(migrate.DownFile).DownStmts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"drop table t;"}, down)
+	// Down files are part of the checksum.
+	hs3, err := d.Checksum()
+	require.NoError(t, err)
+	require.Len(t, hs3, 2)
+	require.Equal(t, hs1[0], hs3[0])
+	require.Equal(t, migrate.DownFileName("1.sql"), hs3[1].N)
+	require.NoError(t, migrate.WriteSumFile(&d, hs3))
+	require.NoError(t, migrate.Validate(&d))
+	require.NoError(t, d.WriteFile(migrate.DownFileName("1.sql"), []byte("drop table t2;")))
+	require.ErrorIs(t, migrate.Validate(&d), migrate.ErrChecksumMismatch)
+	require.NoError(t, d.WriteFile(migrate.DownFileName("1.sql"), []byte("drop table t;")))
+
 	// Sync with additional directory.
 	var d2, d3 migrate.MemDir
 	d.SyncWrites(d2.WriteFile, d3.WriteFile)
@@ -507,3 +634,61 @@ func fileNames(r io.Reader) ([]string, error) {
 	}
 	return out, nil
 }
+
+func TestOpenHTTPDir(t *testing.T) {
+	local, err := migrate.NewLocalDir(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, local.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, local.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);")))
+	require.NoError(t, local.WriteFile(migrate.DownFileName("2_t2.sql"), []byte("DROP TABLE t2;")))
+	require.NoError(t, migrate.Rehash(local))
+	srv := httptest.NewServer(http.StripPrefix("/migrations/", http.FileServer(http.Dir(local.Path()))))
+	t.Cleanup(srv.Close)
+
+	dir, err := migrate.OpenHTTPDir(context.Background(), srv.Client(), srv.URL+"/migrations/")
+	require.NoError(t, err)
+	require.NoError(t, migrate.Validate(dir))
+	files, err := dir.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, "1_t1.sql", files[0].Name())
+	require.Equal(t, "CREATE TABLE t2(c int);", string(files[1].Bytes()))
+	require.EqualError(t, dir.WriteFile("3_t3.sql", nil), "sql/migrate: remote directory is read-only")
+	// Down files are listed in the sum file, and fetched with their migration files.
+	down, err := files[1].(migrate.DownFile).DownStmts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"DROP TABLE t2;"}, down)
+	require.NoError(t, os.RemoveAll(filepath.Join(local.Path(), "down")))
+	require.NoError(t, migrate.Rehash(local))
+
+	// Each file is fetched from its own (e.g. presigned) URL.
+	signed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sig") != "signed-"+path.Base(r.URL.Path) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join(local.Path(), path.Base(r.URL.Path)))
+	}))
+	t.Cleanup(signed.Close)
+	dir, err = migrate.OpenHTTPDirFunc(context.Background(), signed.Client(), func(name string) (string, error) {
+		return fmt.Sprintf("%s/objects/%s?sig=signed-%s", signed.URL, name, name), nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, migrate.Validate(dir))
+	files, err = dir.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	_, err = migrate.OpenHTTPDir(context.Background(), signed.Client(), signed.URL+"/objects/?sig=signed-atlas.sum")
+	require.EqualError(t, err, `sql/migrate: fetch file "1_t1.sql": unexpected status "403 Forbidden"`)
+	_, err = migrate.OpenHTTPDirFunc(context.Background(), signed.Client(), func(name string) (string, error) {
+		return "", errors.New("no credentials")
+	})
+	require.EqualError(t, err, `sql/migrate: resolve url of file "atlas.sum": no credentials`)
+
+	// Missing files fail the loading.
+	require.NoError(t, os.Remove(filepath.Join(local.Path(), "2_t2.sql")))
+	_, err = migrate.OpenHTTPDir(context.Background(), srv.Client(), srv.URL+"/migrations")
+	require.EqualError(t, err, `sql/migrate: fetch file "2_t2.sql": unexpected status "404 Not Found"`)
+	_, err = migrate.OpenHTTPDir(context.Background(), srv.Client(), srv.URL+"/unknown")
+	require.EqualError(t, err, `sql/migrate: fetch file "atlas.sum": unexpected status "404 Not Found"`)
+}
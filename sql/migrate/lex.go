@@ -86,7 +86,8 @@ func newLex(input string) (*lex, error) {
 		if len(parts) == 1 {
 			return nil, l.error(l.pos, "no input found after delimiter %q", d)
 		}
-		l.input = parts[1]
+		// Positions are relative to the original input.
+		l.input, l.total = parts[1], len(parts[0])+1
 	}
 	return l, nil
 }
@@ -104,6 +104,16 @@ cmd7;
 
 	require.Equal(t, "cmd7;", stmts[7].Text)
 	require.Equal(t, []string{""}, stmts[7].Directive("nolint"))
+
+	// Positions are relative to the file, also when the delimiter directive is used.
+	f = "-- atlas:delimiter $$\n\nCREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END$$\n\nCALL p()$$\n"
+	stmts, err = NewLocalFile("f", []byte(f)).StmtDecls()
+	require.NoError(t, err)
+	require.Len(t, stmts, 2)
+	require.Equal(t, "CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END", stmts[0].Text)
+	require.Equal(t, strings.Index(f, "CREATE PROCEDURE"), stmts[0].Pos)
+	require.Equal(t, "CALL p()", stmts[1].Text)
+	require.Equal(t, strings.Index(f, "CALL p()"), stmts[1].Pos)
 }
 
 func TestLex_Errors(t *testing.T) {
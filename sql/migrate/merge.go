@@ -0,0 +1,181 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+type (
+	// MergeReport describes the conflicts between the migration directory of a branch
+	// and the migration directory of the branch it is merged into (e.g. main). The
+	// branch directory can be read either before the merge, or after it.
+	MergeReport struct {
+		// Conflicts holds the versions that were added by both
+		// branches, with different names or different contents.
+		Conflicts []VersionConflict
+		// Unordered holds the files that exist only in the branch directory, and their
+		// versions are not greater than the last version of the base directory.
+		// Executing them as is would create a non-linear history, and they should
+		// be renumbered to follow the base directory. See RebaseFiles.
+		Unordered []File
+		// Last is the last version of the base directory.
+		Last string
+		// SumMismatch indicates that the sum file of the branch directory does
+		// not match its files, e.g. it was taken from one of the branches when
+		// the merge conflict was resolved.
+		SumMismatch bool
+	}
+
+	// VersionConflict describes a version that was added by both branches.
+	VersionConflict struct {
+		Version      string
+		Branch, Base File
+	}
+)
+
+// OK reports if the branch directory can be merged as is.
+func (r *MergeReport) OK() bool {
+	return len(r.Conflicts) == 0 && len(r.Unordered) == 0 && !r.SumMismatch
+}
+
+// Rebase renumbers the files of the report in the branch directory to follow the last
+// version of the base directory, and rewrites its sum file. See RebaseFiles for more info.
+func (r *MergeReport) Rebase(dir Dir) error {
+	if len(r.Unordered) == 0 {
+		if r.SumMismatch {
+			return Rehash(dir)
+		}
+		return nil
+	}
+	return RebaseFiles(dir, r.Last, r.Unordered...)
+}
+
+// CheckMerge compares the migration directory of a branch with the migration directory of the
+// branch it is merged into, and reports the files that conflict with it. Files are matched by
+// their names, and files that exist in both directories with the same contents are ignored.
+//
+//	report, err := migrate.CheckMerge(branch, main)
+//	if err != nil {
+//		return err
+//	}
+//	if !report.OK() {
+//		err = report.Rebase(branch)
+//	}
+func CheckMerge(branch, base Dir) (*MergeReport, error) {
+	bfs, err := base.Files()
+	if err != nil {
+		return nil, fmt.Errorf("sql/migrate: merge: read base files: %w", err)
+	}
+	files, err := branch.Files()
+	if err != nil {
+		return nil, fmt.Errorf("sql/migrate: merge: read branch files: %w", err)
+	}
+	r := &MergeReport{}
+	switch err := Validate(branch); {
+	case errors.Is(err, ErrChecksumMismatch), errors.Is(err, ErrChecksumNotFound):
+		r.SumMismatch = true
+	case err != nil:
+		return nil, fmt.Errorf("sql/migrate: merge: validate branch directory: %w", err)
+	}
+	var (
+		names    = make(map[string]File, len(bfs))
+		versions = make(map[string]File, len(bfs))
+	)
+	for _, f := range bfs {
+		names[f.Name()] = f
+		versions[f.Version()] = f
+	}
+	if len(bfs) > 0 {
+		r.Last = bfs[len(bfs)-1].Version()
+	}
+	for _, f := range files {
+		switch b, ok := names[f.Name()]; {
+		case ok && bytes.Equal(b.Bytes(), f.Bytes()):
+			// The file was not changed by the branch.
+		case ok:
+			r.Conflicts = append(r.Conflicts, VersionConflict{Version: f.Version(), Branch: f, Base: b})
+		case versions[f.Version()] != nil:
+			r.Conflicts = append(r.Conflicts, VersionConflict{Version: f.Version(), Branch: f, Base: versions[f.Version()]})
+			r.Unordered = append(r.Unordered, f)
+		case r.Last != "" && f.Version() <= r.Last:
+			r.Unordered = append(r.Unordered, f)
+		}
+	}
+	return r, nil
+}
+
+// RebaseFiles renumbers the given files to the versions that follow the given version, in the order
+// they were passed, and rewrites the sum file of the directory. The numeric width of the versions is
+// kept, for example, rebasing 20220101000000_a.sql after 20220102000000 renames it to 20220102000001_a.sql.
+// The down files of the given files are renamed as well. All new files are written before the old ones
+// are removed, and an error is returned if one of the new versions is already used by another file.
+// The Dir must implement the RemoveDir interface.
+func RebaseFiles(dir Dir, after string, files ...File) error {
+	rd, ok := dir.(RemoveDir)
+	if !ok {
+		return fmt.Errorf("sql/migrate: rebase: removing files is not supported by %T", dir)
+	}
+	v, err := strconv.ParseUint(after, 10, 64)
+	if err != nil {
+		return fmt.Errorf("sql/migrate: rebase: version %q is not numeric", after)
+	}
+	all, err := dir.Files()
+	if err != nil {
+		return fmt.Errorf("sql/migrate: rebase: read migration files: %w", err)
+	}
+	moved := make(map[string]bool, len(files))
+	for _, f := range files {
+		moved[f.Name()] = true
+	}
+	used := make(map[string]string, len(all))
+	for _, f := range all {
+		if !moved[f.Name()] {
+			used[f.Version()] = f.Name()
+		}
+	}
+	renames := make([][2]string, 0, len(files))
+	for _, f := range files {
+		v++
+		nv := fmt.Sprintf("%0*d", len(after), v)
+		if n, ok := used[nv]; ok {
+			return fmt.Errorf("sql/migrate: rebase: version %q of file %q is already used by file %q", nv, f.Name(), n)
+		}
+		used[nv] = f.Name()
+		renames = append(renames, [2]string{f.Name(), nv + strings.TrimPrefix(f.Name(), f.Version())})
+	}
+	for _, r := range renames {
+		b, err := fs.ReadFile(dir, r[0])
+		if err != nil {
+			return fmt.Errorf("sql/migrate: rebase: read file %q: %w", r[0], err)
+		}
+		if err := dir.WriteFile(r[1], b); err != nil {
+			return fmt.Errorf("sql/migrate: rebase: write file %q: %w", r[1], err)
+		}
+		switch b, err := fs.ReadFile(dir, DownFileName(r[0])); {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return fmt.Errorf("sql/migrate: rebase: read down file of %q: %w", r[0], err)
+		default:
+			if err := dir.WriteFile(DownFileName(r[1]), b); err != nil {
+				return fmt.Errorf("sql/migrate: rebase: write down file of %q: %w", r[1], err)
+			}
+		}
+	}
+	for _, r := range renames {
+		if err := rd.RemoveFile(r[0]); err != nil {
+			return fmt.Errorf("sql/migrate: rebase: remove file %q: %w", r[0], err)
+		}
+		if err := rd.RemoveFile(DownFileName(r[0])); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("sql/migrate: rebase: remove down file of %q: %w", r[0], err)
+		}
+	}
+	return Rehash(dir)
+}
@@ -5,14 +5,21 @@
 package migrate
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
+	"ariga.io/atlas/sql/internal/result"
 	"ariga.io/atlas/sql/schema"
 )
 
@@ -23,16 +30,17 @@ type (
 	// reversible (a down file can be generated to it).
 	Plan struct {
 		// Version and Name of the plan. Provided by the user or auto-generated.
-		Version, Name string
+		Version string `json:"Version,omitempty"`
+		Name    string `json:"Name,omitempty"`
 
 		// Reversible describes if the changeset is reversible.
-		Reversible bool
+		Reversible bool `json:"Reversible,omitempty"`
 
 		// Transactional describes if the changeset is transactional.
-		Transactional bool
+		Transactional bool `json:"Transactional,omitempty"`
 
 		// Changes defines the list of changeset in the plan.
-		Changes []*Change
+		Changes []*Change `json:"Changes,omitempty"`
 	}
 
 	// A Change of migration.
@@ -47,11 +55,20 @@ type (
 		Comment string
 
 		// Reverse contains the "reversed" statement(s) if
-		// the command is reversible.
+		// the command is reversible. An empty, non-nil list
+		// indicates the command is reverted by the reverse of
+		// other changes in the plan and requires no statements.
 		Reverse any // string | []string
 
 		// The Source that caused this change, or nil.
 		Source schema.Change
+
+		// Cost is the estimated cost class of the change. See EstimateCosts.
+		Cost Cost
+
+		// Annotations holds the key-value annotations of the Source
+		// change, if any. See schema.Annotations for more info.
+		Annotations map[string]string
 	}
 )
 
@@ -69,6 +86,59 @@ func (c *Change) ReverseStmts() (cmd []string, err error) {
 	return
 }
 
+// changeJSON is the JSON representation of a Change.
+type changeJSON struct {
+	Cmd        string `json:"Cmd"`
+	Args       []any  `json:"Args,omitempty"`
+	Comment    string `json:"Comment,omitempty"`
+	Reverse    any    `json:"Reverse,omitempty"`
+	SourceType string `json:"SourceType,omitempty"`
+	Cost       Cost   `json:"Cost,omitempty"`
+
+	Annotations map[string]string `json:"Annotations,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The Source of the change is
+// encoded by its type name only, e.g. "AddTable" or "ModifyTable".
+func (c *Change) MarshalJSON() ([]byte, error) {
+	if _, err := c.ReverseStmts(); err != nil {
+		return nil, err
+	}
+	v := changeJSON{Cmd: c.Cmd, Args: c.Args, Comment: c.Comment, Reverse: c.Reverse, Cost: c.Cost, Annotations: c.Annotations}
+	if c.Source != nil {
+		v.SourceType = reflect.Indirect(reflect.ValueOf(c.Source)).Type().Name()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Note, the Source of a decoded
+// change is always nil, and its Args are decoded using the JSON types.
+func (c *Change) UnmarshalJSON(b []byte) error {
+	var v changeJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*c = Change{Cmd: v.Cmd, Args: v.Args, Comment: v.Comment, Cost: v.Cost, Annotations: v.Annotations}
+	switch r := v.Reverse.(type) {
+	case nil:
+	case string:
+		c.Reverse = r
+	case []any:
+		stmts := make([]string, len(r))
+		for i := range r {
+			s, ok := r[i].(string)
+			if !ok {
+				return fmt.Errorf("sql/migrate: unexpected type %T for reverse command", r[i])
+			}
+			stmts[i] = s
+		}
+		c.Reverse = stmts
+	default:
+		return fmt.Errorf("sql/migrate: unexpected type %T for reverse commands", r)
+	}
+	return nil
+}
+
 type (
 	// The Driver interface must be implemented by the different dialects to support database
 	// migration authoring/planning and applying. ExecQuerier, Inspector and Differ, provide
@@ -106,6 +176,18 @@ type (
 		// This is useful to indicate to the driver whether the context is a live database, an empty one, or the
 		// versioned migration workflow.
 		Mode PlanMode
+		// DryRun, if set, makes ApplyChanges pass the planned changes
+		// to the function instead of executing them on the database.
+		DryRun func(*Plan)
+		// Approver, if set, is consulted by ApplyChanges
+		// before the planned changes are executed.
+		Approver Approver
+		// Logger, if set, is called by ApplyChanges with the
+		// statements it executes, their results and errors.
+		Logger Logger
+		// Annotations, if set, are copied by the driver to
+		// the statements that are planned for the changes.
+		Annotations schema.Annotations
 	}
 
 	// PlanMode defines the plan mode to use.
@@ -184,6 +266,43 @@ func SchemaConn(drv Driver, name string, opts *schema.InspectOptions) StateReade
 	})
 }
 
+// NormalizedRealm returns a StateReader that normalizes the realm read by r using the given normalizer,
+// e.g. a driver connected to a dev database. Normalizing a desired state, that is usually defined in
+// HCL or SQL, canonicalizes its type aliases and default expressions to their representation in the
+// database. Hence, it is not reported as changed when compared to an inspected (or replayed) state.
+func NormalizedRealm(n schema.Normalizer, r StateReader) StateReader {
+	return StateReaderFunc(func(ctx context.Context) (*schema.Realm, error) {
+		realm, err := r.ReadState(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if realm, err = n.NormalizeRealm(ctx, realm); err != nil {
+			return nil, fmt.Errorf("sql/migrate: normalize realm: %w", err)
+		}
+		return realm, nil
+	})
+}
+
+// NormalizedSchema is like NormalizedRealm, but it normalizes each schema of the state separately,
+// using the NormalizeSchema method. It is used when the normalizer is connected to a specific schema.
+func NormalizedSchema(n schema.Normalizer, r StateReader) StateReader {
+	return StateReaderFunc(func(ctx context.Context) (*schema.Realm, error) {
+		realm, err := r.ReadState(ctx)
+		if err != nil {
+			return nil, err
+		}
+		nr := &schema.Realm{Attrs: realm.Attrs, Objects: realm.Objects}
+		for _, s := range realm.Schemas {
+			ns, err := n.NormalizeSchema(ctx, s)
+			if err != nil {
+				return nil, fmt.Errorf("sql/migrate: normalize schema %q: %w", s.Name, err)
+			}
+			nr.AddSchemas(ns)
+		}
+		return nr, nil
+	})
+}
+
 type (
 	// Planner can plan the steps to take to migrate from one state to another. It uses the enclosed Dir to
 	// those changes to versioned migration files.
@@ -192,8 +311,11 @@ type (
 		dir      Dir                 // where migration files are stored and read from
 		fmt      Formatter           // how to format a plan to migration files
 		sum      bool                // whether to create a sum file for the migration directory
+		down     bool                // whether to write down files for reversible plans
 		planOpts []PlanOption        // plan options
 		diffOpts []schema.DiffOption // diff options
+		norm     schema.Normalizer   // normalizer of desired states
+		tracer   Tracer              // tracer of planning operations
 	}
 
 	// PlannerOption allows managing a Planner using functional arguments.
@@ -203,7 +325,7 @@ type (
 	RevisionReadWriter interface {
 		// Ident returns an object identifies this history table.
 		Ident() *TableIdent
-		// ReadRevisions returns all revisions.
+		// ReadRevisions returns all revisions, ordered by their versions.
 		ReadRevisions(context.Context) ([]*Revision, error)
 		// ReadRevision returns a revision by version.
 		// Returns ErrRevisionNotExist if the version does not exist.
@@ -242,12 +364,102 @@ type (
 		baselineVer string             // Start the first migration after the given baseline version.
 		allowDirty  bool               // Allow start working on a non-clean database.
 		operator    string             // Revision.OperatorVersion
+		dryRun      bool               // Log the statements instead of executing them.
+		txMode      TxMode             // The transaction mode of the execution.
+		txOpen      TxOpener           // Opens transactions for the execution.
+		tx          *Tx                // The active transaction in TxModeAll.
+		hooks       []ExecHooks        // Hooks to run around the execution of files and statements.
+		lockName    string             // The name of the lock to acquire during execution.
+		lockTimeout time.Duration      // The timeout for acquiring the lock.
+		stmtTimeout time.Duration      // The timeout for executing a single statement.
+		lockWait    time.Duration      // The timeout for waiting on database locks by statements.
+		destPolicy  DestructivePolicy  // The policy for executing destructive statements.
+		destAllowed bool               // Destructive statements were explicitly allowed.
+		order       ExecOrder          // The execution order of pending files.
+		tracer      Tracer             // The Tracer of files and statements executions.
 	}
 
 	// ExecutorOption allows configuring an Executor using functional arguments.
 	ExecutorOption func(*Executor) error
+
+	// TxMode defines how the Executor wraps the execution of migration files in transactions.
+	TxMode string
+
+	// A Tx is a transaction opened by the TxOpener of an Executor.
+	Tx struct {
+		// Driver executes the migration statements within the transaction.
+		Driver Driver
+		// RevisionReadWriter writes the revisions within the transaction.
+		// If nil, the RevisionReadWriter of the Executor is used, and the
+		// revisions are restored in case the transaction is rolled back.
+		RevisionReadWriter RevisionReadWriter
+		// Commit and Rollback end the transaction.
+		Commit, Rollback func() error
+		// revs records the revisions that were written outside the
+		// transaction, in case its RevisionReadWriter is nil.
+		revs *txRevisions
+	}
+
+	// TxOpener opens a transaction for executing migration files.
+	TxOpener func(context.Context) (*Tx, error)
+
+	// ExecHooks are called by the Executor before and after executing migration files and their
+	// statements. An error returned by a hook aborts the execution, and it is recorded on the
+	// revision of the file. Nil hooks are ignored.
+	ExecHooks struct {
+		BeforeFile, AfterFile func(context.Context, File) error
+		BeforeStmt, AfterStmt func(context.Context, File, *Change) error
+	}
 )
 
+// List of transaction modes.
+const (
+	// TxModeNone executes the migration files without transactions.
+	TxModeNone TxMode = "none"
+	// TxModeFile executes each migration file in its own transaction.
+	TxModeFile TxMode = "file"
+	// TxModeAll executes all migration files in one transaction.
+	TxModeAll TxMode = "all"
+)
+
+// ExecOrder defines how the Executor handles migration files that were not applied on the database,
+// but have a lower version than its last revision. e.g. files that were added by a long-lived branch
+// after newer files of other branches were already applied.
+type ExecOrder string
+
+// List of execution orders.
+const (
+	// ExecOrderLinearSkip skips files with a version lower than the last revision. This is the default.
+	ExecOrderLinearSkip ExecOrder = "linear-skip"
+	// ExecOrderLinear fails the execution with a HistoryNonLinearError if there are
+	// files with a version lower than the last revision that were not applied.
+	ExecOrderLinear ExecOrder = "linear"
+	// ExecOrderNonLinear executes files with a version lower than the last revision that were not
+	// (fully) applied, ordered by their versions, before the files that follow the last revision.
+	ExecOrderNonLinear ExecOrder = "non-linear"
+)
+
+// HistoryNonLinearError is returned by the Executor in ExecOrderLinear mode, if there are migration
+// files with a version lower than the last revision of the database that were not applied.
+type HistoryNonLinearError struct {
+	// Last is the version of the last revision.
+	Last string
+	// OutOfOrder holds the files that were not applied.
+	OutOfOrder []File
+}
+
+func (e HistoryNonLinearError) Error() string {
+	names := make([]string, len(e.OutOfOrder))
+	for i, f := range e.OutOfOrder {
+		names[i] = f.Name()
+	}
+	return fmt.Sprintf("sql/migrate: execute: non-linear history: files %q were not applied, but the database is at version %q", names, e.Last)
+}
+
+// directiveTxMode is the file directive for overriding the transaction mode
+// of the Executor for a specific file. e.g., "-- atlas:txmode none".
+const directiveTxMode = "txmode"
+
 const (
 	// RevisionTypeUnknown represents an unknown revision type.
 	// This type is unexpected and exists here to only ensure
@@ -306,6 +518,36 @@ func NewPlanner(drv Driver, dir Dir, opts ...PlannerOption) *Planner {
 	return p
 }
 
+// ApplyDryRun returns a PlanOption that makes ApplyChanges report its planned changes,
+// in their execution order and with their arguments, to the given function instead of
+// executing them. The Transactional field of the reported Plan indicates whether the
+// changes can be executed in a single transaction.
+func ApplyDryRun(fn func(*Plan)) PlanOption {
+	return func(o *PlanOptions) {
+		o.DryRun = fn
+	}
+}
+
+// PlanAnnotations returns a PlanOption that makes the driver copy the annotations of the
+// changes to their planned statements. Formatters render the annotations as comments.
+//
+//	a := make(schema.Annotations).Annotate(changes[0], "ticket", "DB-42")
+//	plan, err := drv.PlanChanges(ctx, "add_users", changes, migrate.PlanAnnotations(a))
+func PlanAnnotations(a schema.Annotations) PlanOption {
+	return func(o *PlanOptions) {
+		o.Annotations = a
+	}
+}
+
+// ApplyWithLogger returns a PlanOption that makes ApplyChanges report the statements
+// it executes to the given Logger, using the LogStmt, LogStmtDone and LogError entries.
+// Note, statements are not associated with migration files, and their File is nil.
+func ApplyWithLogger(l Logger) PlanOption {
+	return func(o *PlanOptions) {
+		o.Logger = l
+	}
+}
+
 // PlanWithSchemaQualifier allows setting a custom schema to prefix tables and
 // other resources. An empty string indicates no prefix.
 //
@@ -345,6 +587,24 @@ func PlanWithDiffOptions(opts ...schema.DiffOption) PlannerOption {
 	}
 }
 
+// PlanWithDownFiles configures the Planner to write the reverse statements of the plans it writes to their
+// down files, in reverse order, allowing the Executor to revert them. Plans that have changes without reverse
+// statements are irreversible, and no down file is written for them. See DownFileName for more info.
+func PlanWithDownFiles(b bool) PlannerOption {
+	return func(p *Planner) {
+		p.down = b
+	}
+}
+
+// PlanWithNormalizer configures the Planner to normalize the desired states it plans, before they are
+// compared to the current state of the migration directory. Usually, the normalizer is the dev database
+// Driver of the Planner. See NormalizedRealm for more info.
+func PlanWithNormalizer(n schema.Normalizer) PlannerOption {
+	return func(p *Planner) {
+		p.norm = n
+	}
+}
+
 // PlanFormat sets the Formatter of a Planner.
 func PlanFormat(fmt Formatter) PlannerOption {
 	return func(p *Planner) {
@@ -381,39 +641,36 @@ func (p *Planner) PlanSchema(ctx context.Context, name string, to StateReader) (
 	return p.plan(ctx, name, to, false)
 }
 
-func (p *Planner) plan(ctx context.Context, name string, to StateReader, realmScope bool) (*Plan, error) {
+func (p *Planner) plan(ctx context.Context, name string, to StateReader, realmScope bool) (plan *Plan, err error) {
+	ctx, span := startSpan(ctx, p.tracer, "atlas.plan", Attr{Key: "atlas.plan.name", Value: name})
+	defer func() {
+		if errors.Is(err, ErrNoPlan) {
+			span.end(nil)
+			return
+		}
+		if plan != nil {
+			for _, c := range plan.Changes {
+				span.event("atlas.plan.change", Attr{Key: "db.statement", Value: c.Cmd}, Attr{Key: "atlas.plan.comment", Value: c.Comment})
+			}
+		}
+		span.end(err)
+	}()
 	current, err := p.current(ctx, realmScope)
 	if err != nil {
 		return nil, err
 	}
-	desired, err := to.ReadState(ctx)
-	if err != nil {
-		return nil, err
-	}
-	var changes []schema.Change
 	switch {
+	case p.norm == nil:
 	case realmScope:
-		changes, err = p.drv.RealmDiff(current, desired, p.diffOpts...)
+		to = NormalizedRealm(p.norm, to)
 	default:
-		switch n, m := len(current.Schemas), len(desired.Schemas); {
-		case n == 0:
-			return nil, errors.New("no schema was found in current state after replaying migration directory")
-		case n > 1:
-			return nil, fmt.Errorf("%d schemas were found in current state after replaying migration directory", len(current.Schemas))
-		case m == 0:
-			return nil, errors.New("no schema was found in desired state")
-		case m > 1:
-			return nil, fmt.Errorf("%d schemas were found in desired state; expect 1", len(desired.Schemas))
-		default:
-			s1, s2 := *current.Schemas[0], *desired.Schemas[0]
-			// Avoid comparing schema names when scope is limited to one schema,
-			// and the schema qualifier is controlled by the caller.
-			if s1.Name != s2.Name {
-				s1.Name = s2.Name
-			}
-			changes, err = p.drv.SchemaDiff(&s1, &s2, p.diffOpts...)
-		}
+		to = NormalizedSchema(p.norm, to)
 	}
+	desired, err := to.ReadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := diffStates(p.drv, current, desired, realmScope, "current state after replaying migration directory", p.diffOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -423,6 +680,62 @@ func (p *Planner) plan(ctx context.Context, name string, to StateReader, realmSc
 	return p.drv.PlanChanges(ctx, name, changes, p.planOpts...)
 }
 
+// Diff reads the given states, and returns the changes for moving from the first state to the second,
+// as computed by the given Differ (e.g. a driver connected to a dev database). Either state can be read
+// from a migration directory by a Replayer. For example, the next migration file can be generated as the
+// difference between the accumulated state of the directory and a desired schema defined in HCL, without
+// connecting to the target database:
+//
+//	changes, err := migrate.Diff(ctx, dev, migrate.NewReplayer(dev, dir), migrate.NormalizedRealm(dev, desired))
+func Diff(ctx context.Context, d schema.Differ, from, to StateReader, opts ...schema.DiffOption) ([]schema.Change, error) {
+	return diff(ctx, d, from, to, true, opts)
+}
+
+// DiffSchema is like Diff, but it expects each state to hold exactly one schema, and it ignores
+// the difference between their names. Use StateReaderFunc(r.ReadSchemaState) for reading the
+// state of a migration directory from the schema that the dev database is connected to.
+func DiffSchema(ctx context.Context, d schema.Differ, from, to StateReader, opts ...schema.DiffOption) ([]schema.Change, error) {
+	return diff(ctx, d, from, to, false, opts)
+}
+
+func diff(ctx context.Context, d schema.Differ, from, to StateReader, realmScope bool, opts []schema.DiffOption) ([]schema.Change, error) {
+	current, err := from.ReadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := to.ReadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return diffStates(d, current, desired, realmScope, "current state", opts)
+}
+
+// diffStates computes the changes between the given states. The source describes
+// the current state in errors, in case the scope is limited to a single schema.
+func diffStates(d schema.Differ, current, desired *schema.Realm, realmScope bool, source string, opts []schema.DiffOption) ([]schema.Change, error) {
+	if realmScope {
+		return d.RealmDiff(current, desired, opts...)
+	}
+	switch n, m := len(current.Schemas), len(desired.Schemas); {
+	case n == 0:
+		return nil, fmt.Errorf("no schema was found in %s", source)
+	case n > 1:
+		return nil, fmt.Errorf("%d schemas were found in %s", n, source)
+	case m == 0:
+		return nil, errors.New("no schema was found in desired state")
+	case m > 1:
+		return nil, fmt.Errorf("%d schemas were found in desired state; expect 1", m)
+	default:
+		s1, s2 := *current.Schemas[0], *desired.Schemas[0]
+		// Avoid comparing schema names when scope is limited to one schema,
+		// and the schema qualifier is controlled by the caller.
+		if s1.Name != s2.Name {
+			s1.Name = s2.Name
+		}
+		return d.SchemaDiff(&s1, &s2, opts...)
+	}
+}
+
 // Checkpoint calculate the current state of the migration directory by executing its files,
 // and return a migration (checkpoint) Plan that represents its states.
 func (p *Planner) Checkpoint(ctx context.Context, name string) (*Plan, error) {
@@ -468,24 +781,17 @@ func (p *Planner) checkpoint(ctx context.Context, name string, realmScope bool)
 
 // current returns the current realm state.
 func (p *Planner) current(ctx context.Context, realmScope bool) (*schema.Realm, error) {
-	from, err := NewExecutor(p.drv, p.dir, NopRevisionReadWriter{})
-	if err != nil {
-		return nil, err
+	r := NewReplayer(p.drv, p.dir)
+	if realmScope {
+		return r.ReadState(ctx)
 	}
-	return from.Replay(ctx, func() StateReader {
-		if realmScope {
-			return RealmConn(p.drv, nil)
-		}
-		// In case the scope is the schema connection,
-		// inspect it and return its connected realm.
-		return SchemaConn(p.drv, "", nil)
-	}())
+	return r.ReadSchemaState(ctx)
 }
 
 // WritePlan writes the given Plan to the Dir based on the configured Formatter.
 func (p *Planner) WritePlan(plan *Plan) error {
 	// Format the plan into files.
-	files, err := p.fmt.Format(plan)
+	files, err := p.format(plan)
 	if err != nil {
 		return err
 	}
@@ -495,9 +801,36 @@ func (p *Planner) WritePlan(plan *Plan) error {
 			return err
 		}
 	}
+	if len(files) == 1 {
+		if err := p.writeDown(plan, files[0].Name()); err != nil {
+			return err
+		}
+	}
 	return p.writeSum()
 }
 
+// writeDown writes the reverse statements of the plan to the down file of the given migration file,
+// if the Planner was configured to write down files, and all changes of the plan are reversible.
+func (p *Planner) writeDown(plan *Plan, name string) error {
+	if !p.down || len(plan.Changes) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for i := len(plan.Changes) - 1; i >= 0; i-- {
+		stmts, err := plan.Changes[i].ReverseStmts()
+		if err != nil {
+			return err
+		}
+		if stmts == nil {
+			return nil
+		}
+		for _, s := range stmts {
+			b.WriteString(s + ";\n")
+		}
+	}
+	return p.dir.WriteFile(DownFileName(name), []byte(b.String()))
+}
+
 // WriteCheckpoint writes the given Plan as a checkpoint file to the Dir based on the configured Formatter.
 func (p *Planner) WriteCheckpoint(plan *Plan, tag string) error {
 	ck, ok := p.dir.(CheckpointDir)
@@ -505,7 +838,7 @@ func (p *Planner) WriteCheckpoint(plan *Plan, tag string) error {
 		return fmt.Errorf("checkpoint is not supported by %T", p.dir)
 	}
 	// Format the plan into files.
-	files, err := p.fmt.Format(plan)
+	files, err := p.format(plan)
 	if err != nil {
 		return err
 	}
@@ -518,16 +851,110 @@ func (p *Planner) WriteCheckpoint(plan *Plan, tag string) error {
 	return p.writeSum()
 }
 
+// Squash merges the migration files in the range of the given versions (inclusive) into one file, by
+// replaying the directory on the dev database of the Planner and planning the changes between its state
+// before the range and its state at the end of it. The squashed file replaces the last file in the range
+// and keeps its name and version. Hence, databases that applied either all the files in the range or none
+// of them are not affected. The Dir must implement the RemoveDir interface.
+func (p *Planner) Squash(ctx context.Context, from, to string) error {
+	rd, ok := p.dir.(RemoveDir)
+	if !ok {
+		return fmt.Errorf("sql/migrate: squash: removing files is not supported by %T", p.dir)
+	}
+	files, err := p.dir.Files()
+	if err != nil {
+		return fmt.Errorf("sql/migrate: squash: select migration files: %w", err)
+	}
+	i := FilesLastIndex(files, func(f File) bool { return f.Version() == from })
+	j := FilesLastIndex(files, func(f File) bool { return f.Version() == to })
+	switch {
+	case i == -1:
+		return fmt.Errorf("sql/migrate: squash: migration with version %q not found", from)
+	case j == -1:
+		return fmt.Errorf("sql/migrate: squash: migration with version %q not found", to)
+	case i > j:
+		return fmt.Errorf("sql/migrate: squash: version %q is greater than version %q", from, to)
+	}
+	for _, f := range files[i : j+1] {
+		if ck, ok := f.(CheckpointFile); ok && ck.IsCheckpoint() {
+			return fmt.Errorf("sql/migrate: squash: checkpoint file %q cannot be squashed", f.Name())
+		}
+	}
+	before, err := p.replay(ctx, files[:i])
+	if err != nil {
+		return err
+	}
+	after, err := p.replay(ctx, files[:j+1])
+	if err != nil {
+		return err
+	}
+	changes, err := p.drv.RealmDiff(before, after, p.diffOpts...)
+	if err != nil {
+		return err
+	}
+	last := files[j]
+	plan := &Plan{Version: last.Version(), Name: last.Desc()}
+	if len(changes) > 0 {
+		if plan, err = p.drv.PlanChanges(ctx, last.Desc(), changes, p.planOpts...); err != nil {
+			return err
+		}
+		plan.Version = last.Version()
+	}
+	formatted, err := p.format(plan)
+	if err != nil {
+		return err
+	}
+	if len(formatted) != 1 {
+		return fmt.Errorf("sql/migrate: squash: expected one migration file, got %d", len(formatted))
+	}
+	for _, f := range files[i:j] {
+		if err := rd.RemoveFile(f.Name()); err != nil {
+			return fmt.Errorf("sql/migrate: squash: remove file %q: %w", f.Name(), err)
+		}
+	}
+	// Down files of the squashed files no longer apply.
+	for _, f := range files[i : j+1] {
+		if err := rd.RemoveFile(DownFileName(f.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("sql/migrate: squash: remove down file of %q: %w", f.Name(), err)
+		}
+	}
+	if err := p.dir.WriteFile(last.Name(), formatted[0].Bytes()); err != nil {
+		return err
+	}
+	if err := p.writeDown(plan, last.Name()); err != nil {
+		return err
+	}
+	return p.writeSum()
+}
+
+// replay returns the state of the dev database after executing the given files.
+func (p *Planner) replay(ctx context.Context, files []File) (*schema.Realm, error) {
+	dir := &MemDir{}
+	for _, f := range files {
+		if err := dir.WriteFile(f.Name(), f.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := Rehash(dir); err != nil {
+		return nil, err
+	}
+	return NewPlanner(p.drv, dir).current(ctx, true)
+}
+
+// format formats the given Plan into files using the configured Formatter.
+func (p *Planner) format(plan *Plan) ([]File, error) {
+	if f, ok := p.fmt.(DirFormatter); ok {
+		return f.FormatDir(p.dir, plan)
+	}
+	return p.fmt.Format(plan)
+}
+
 // writeSum writes the sum file to the Dir, if enabled.
 func (p *Planner) writeSum() error {
 	if !p.sum {
 		return nil
 	}
-	sum, err := p.dir.Checksum()
-	if err != nil {
-		return err
-	}
-	return WriteSumFile(p.dir, sum)
+	return Rehash(p.dir)
 }
 
 var (
@@ -537,6 +964,8 @@ var (
 	ErrSnapshotUnsupported = errors.New("sql/migrate: driver does not support taking a database snapshot")
 	// ErrCleanCheckerUnsupported is returned if there is no CleanChecker given.
 	ErrCleanCheckerUnsupported = errors.New("sql/migrate: driver does not support checking if database is clean")
+	// ErrNoAppliedFiles is returned if there are no applied migration files to revert on the managed database.
+	ErrNoAppliedFiles = errors.New("sql/migrate: no applied migration files")
 	// ErrRevisionNotExist is returned if the requested revision is not found in the storage.
 	ErrRevisionNotExist = errors.New("sql/migrate: revision not found")
 )
@@ -579,6 +1008,9 @@ func NewExecutor(drv Driver, dir Dir, rrw RevisionReadWriter, opts ...ExecutorOp
 	if _, ok := drv.(CleanChecker); !ok {
 		return nil, ErrCleanCheckerUnsupported
 	}
+	if _, ok := drv.(schema.Locker); ex.lockName != "" && !ok {
+		return nil, fmt.Errorf("sql/migrate: execute: driver %T does not support locking", drv)
+	}
 	if ex.baselineVer != "" && ex.allowDirty {
 		return nil, errors.New("sql/migrate: execute: baseline and allow-dirty are mutually exclusive")
 	}
@@ -611,6 +1043,104 @@ func WithLogger(log Logger) ExecutorOption {
 	}
 }
 
+// WithDryRun configures the Executor to log the statements of the pending migration
+// files without executing them, and without writing revisions to the database.
+func WithDryRun(b bool) ExecutorOption {
+	return func(ex *Executor) error {
+		ex.dryRun = b
+		return nil
+	}
+}
+
+// WithTxMode sets the transaction mode of the Executor and the function that is used for opening
+// its transactions. Files can opt out of the transaction mode, unless it is TxModeAll, by using the
+// "txmode" directive. For example, a file starting with the following lines is executed without a
+// transaction:
+//
+//	-- atlas:txmode none
+//
+//	CREATE INDEX CONCURRENTLY ...
+func WithTxMode(m TxMode, open TxOpener) ExecutorOption {
+	return func(ex *Executor) error {
+		switch m {
+		case TxModeNone:
+		case TxModeFile, TxModeAll:
+			if open == nil {
+				return fmt.Errorf("sql/migrate: execute: no transaction opener given for txmode %q", m)
+			}
+		default:
+			return fmt.Errorf("sql/migrate: execute: unknown txmode %q", m)
+		}
+		ex.txMode, ex.txOpen = m, open
+		return nil
+	}
+}
+
+// WithHooks adds the given hooks to the Executor. Hooks are called in the order they were added,
+// and are not called in dry-run mode.
+func WithHooks(h ...ExecHooks) ExecutorOption {
+	return func(ex *Executor) error {
+		ex.hooks = append(ex.hooks, h...)
+		return nil
+	}
+}
+
+// WithLock configures the Executor to acquire the named "advisory lock" of the database for the
+// duration of applying (or reverting) migration files, so that concurrent executions cannot apply
+// migrations to the same database simultaneously. The timeout follows the semantics of schema.Locker.
+// The Driver of the Executor must implement the schema.Locker interface.
+func WithLock(name string, timeout time.Duration) ExecutorOption {
+	return func(ex *Executor) error {
+		if name == "" {
+			return errors.New("sql/migrate: execute: empty lock name")
+		}
+		ex.lockName, ex.lockTimeout = name, timeout
+		return nil
+	}
+}
+
+// WithStmtTimeout limits the execution time of each migration statement to the given duration. The
+// context passed to the Driver is canceled once the timeout is exceeded, and drivers that implement
+// the TimeoutSetter interface are also configured to abort statements on the database side.
+func WithStmtTimeout(d time.Duration) ExecutorOption {
+	return func(ex *Executor) error {
+		if d < 0 {
+			return fmt.Errorf("sql/migrate: execute: negative statement timeout %s", d)
+		}
+		ex.stmtTimeout = d
+		return nil
+	}
+}
+
+// WithLockWaitTimeout limits the time migration statements wait for locks held by other sessions
+// before they fail, instead of blocking (and being blocked by) the application traffic. It requires
+// the Driver of the Executor to implement the TimeoutSetter interface, and it is ignored otherwise.
+func WithLockWaitTimeout(d time.Duration) ExecutorOption {
+	return func(ex *Executor) error {
+		if d < 0 {
+			return fmt.Errorf("sql/migrate: execute: negative lock wait timeout %s", d)
+		}
+		ex.lockWait = d
+		return nil
+	}
+}
+
+// WithExecOrder sets the execution order of the Executor. See ExecOrder for more info.
+//
+// Note, executing files out of order is safe only if they do not depend on the files that were
+// applied before them. The integrity of the directory is validated using its sum file in all modes.
+func WithExecOrder(o ExecOrder) ExecutorOption {
+	return func(ex *Executor) error {
+		switch o {
+		case ExecOrderLinearSkip, ExecOrderLinear, ExecOrderNonLinear:
+		default:
+			return fmt.Errorf("sql/migrate: execute: unknown execution order %q", o)
+		}
+		ex.order = o
+		return nil
+	}
+}
+
 // WithOperatorVersion sets the operator version to save on the revisions
 // when executing migration files.
 func WithOperatorVersion(v string) ExecutorOption {
@@ -622,6 +1152,12 @@ func WithOperatorVersion(v string) ExecutorOption {
 
 // Pending returns all pending (not fully applied) migration files in the migration directory.
 func (e *Executor) Pending(ctx context.Context) ([]File, error) {
+	return e.pending(ctx, true)
+}
+
+// pending returns the pending files. If write is false, the
+// baseline revision is not written to the database.
+func (e *Executor) pending(ctx context.Context, write bool) ([]File, error) {
 	// Don't operate with a broken migration directory.
 	if err := Validate(e.dir); err != nil {
 		return nil, fmt.Errorf("sql/migrate: execute: validate migration directory: %w", err)
@@ -657,8 +1193,10 @@ func (e *Executor) Pending(ctx context.Context) ([]File, error) {
 			}
 			f := migrations[baseline]
 			// Write the first revision in the database as a baseline revision.
-			if err := e.writeRevision(ctx, &Revision{Version: f.Version(), Description: f.Desc(), Type: RevisionTypeBaseline}); err != nil {
-				return nil, err
+			if write {
+				if err := e.writeRevision(ctx, e.rrw, &Revision{Version: f.Version(), Description: f.Desc(), Type: RevisionTypeBaseline}); err != nil {
+					return nil, err
+				}
 			}
 			pending = migrations[baseline+1:]
 
@@ -697,6 +1235,14 @@ func (e *Executor) Pending(ctx context.Context) ([]File, error) {
 			idx++
 		}
 		pending = migrations[idx:]
+		if skipped := outOfOrder(migrations[:idx], revs); len(skipped) > 0 {
+			switch e.order {
+			case ExecOrderLinear:
+				return nil, HistoryNonLinearError{Last: last.Version, OutOfOrder: skipped}
+			case ExecOrderNonLinear:
+				pending = append(skipped, pending...)
+			}
+		}
 	}
 	if len(pending) == 0 {
 		return nil, ErrNoPendingFiles
@@ -704,36 +1250,193 @@ func (e *Executor) Pending(ctx context.Context) ([]File, error) {
 	return pending, nil
 }
 
-// Execute executes the given migration file on the database. If it sees a file, that has been partially applied, it
-// will continue with the next statement in line.
-func (e *Executor) Execute(ctx context.Context, m File) (err error) {
-	hf, err := e.dir.Checksum()
+type (
+	// Status describes the migration status of a database.
+	Status struct {
+		// Current is the version of the last applied revision, if any.
+		Current string `json:"Current,omitempty"`
+		// Applied holds the revisions that were applied on the database.
+		Applied []*Revision `json:"Applied,omitempty"`
+		// Pending holds the files that are pending (or partially applied).
+		Pending []File `json:"-"`
+		// Drift holds the applied revisions whose migration files were
+		// removed from the migration directory, or were edited.
+		Drift []*Drift `json:"Drift,omitempty"`
+	}
+
+	// Drift describes an applied revision that does not match the migration directory.
+	Drift struct {
+		Version     string    `json:"Version"`
+		Description string    `json:"Description"`
+		Kind        DriftKind `json:"Kind"`
+	}
+
+	// DriftKind describes the kind of a Drift.
+	DriftKind string
+)
+
+// List of drift kinds.
+const (
+	DriftMissingFile DriftKind = "missing_file" // The migration file of the revision was not found.
+	DriftEditedFile  DriftKind = "edited_file"  // The applied statements of the migration file were changed.
+)
+
+// MarshalJSON implements json.Marshaler. Pending files are encoded by their names.
+func (s *Status) MarshalJSON() ([]byte, error) {
+	type status Status
+	v := struct {
+		*status
+		Pending []string `json:"Pending,omitempty"`
+	}{status: (*status)(s)}
+	for _, f := range s.Pending {
+		v.Pending = append(v.Pending, f.Name())
+	}
+	return json.Marshal(v)
+}
+
+// Status returns the migration status of the database. Unlike Pending, it does not write
+// the baseline revision to the database in case the Executor was configured with one.
+func (e *Executor) Status(ctx context.Context) (*Status, error) {
+	revs, err := e.rrw.ReadRevisions(ctx)
 	if err != nil {
-		return fmt.Errorf("sql/migrate: execute: compute hash: %w", err)
+		return nil, fmt.Errorf("sql/migrate: status: read revisions: %w", err)
 	}
-	hash, err := hf.SumByName(m.Name())
-	if err != nil {
-		return fmt.Errorf("sql/migrate: execute: scanning checksum from %q: %w", m.Name(), err)
+	s := &Status{Applied: revs}
+	if len(revs) > 0 {
+		s.Current = revs[len(revs)-1].Version
 	}
-	stmts, err := m.Stmts()
+	// A missing partially applied file is reported as drift.
+	var merr *MissingMigrationError
+	if s.Pending, err = e.pending(ctx, false); err != nil && !errors.Is(err, ErrNoPendingFiles) && !errors.As(err, &merr) {
+		return nil, err
+	}
+	files, err := e.dir.Files()
 	if err != nil {
-		return fmt.Errorf("sql/migrate: execute: scanning statements from %q: %w", m.Name(), err)
+		return nil, fmt.Errorf("sql/migrate: status: select migration files: %w", err)
 	}
-	// Create checksums for the statements.
-	var (
-		sums = make([]string, len(stmts))
-		h    = sha256.New()
-	)
-	for i, stmt := range stmts {
-		if _, err := h.Write([]byte(stmt)); err != nil {
-			return err
+	for _, r := range revs {
+		if r.Type == RevisionTypeBaseline {
+			continue
 		}
-		sums[i] = base64.StdEncoding.EncodeToString(h.Sum(nil))
-	}
-	version := m.Version()
+		idx := FilesLastIndex(files, func(f File) bool { return f.Version() == r.Version })
+		if idx == -1 {
+			s.Drift = append(s.Drift, &Drift{Version: r.Version, Description: r.Description, Kind: DriftMissingFile})
+			continue
+		}
+		edited, err := fileEdited(files[idx], r)
+		if err != nil {
+			return nil, err
+		}
+		if edited {
+			s.Drift = append(s.Drift, &Drift{Version: r.Version, Description: r.Description, Kind: DriftEditedFile})
+		}
+	}
+	return s, nil
+}
+
+// fileEdited reports if the statements of the given file were changed after they were applied.
+// Unlike the hashes of the atlas.sum file, which depend on all files that precede the file, the
+// partial hashes of the revision depend only on the applied statements of the file. Revisions
+// without partial hashes (e.g. written by older versions) are not checked.
+func fileEdited(f File, r *Revision) (bool, error) {
+	if len(r.PartialHashes) == 0 {
+		return false, nil
+	}
+	stmts, err := f.Stmts()
+	if err != nil {
+		return false, fmt.Errorf("sql/migrate: status: scanning statements from %q: %w", f.Name(), err)
+	}
+	sums, err := stmtSums(stmts)
+	if err != nil {
+		return false, err
+	}
+	if r.Applied == r.Total && len(stmts) != r.Total {
+		return true, nil
+	}
+	for i, h := range r.PartialHashes {
+		if i >= len(sums) || sums[i] != strings.TrimPrefix(h, "h1:") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// stmtSums returns the checksums of the given statements. The i-th checksum covers
+// the first i+1 statements, and it is recorded in the revision once applied.
+func stmtSums(stmts []string) ([]string, error) {
+	var (
+		sums = make([]string, len(stmts))
+		h    = sha256.New()
+	)
+	for i, stmt := range stmts {
+		if _, err := h.Write([]byte(stmt)); err != nil {
+			return nil, err
+		}
+		sums[i] = base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
+
+// outOfOrder returns the files that were not fully applied on the database, excluding the files
+// that are covered by a baseline revision. The given files precede the last revision.
+func outOfOrder(files []File, revs []*Revision) []File {
+	var (
+		base    string
+		applied = make(map[string]bool, len(revs))
+	)
+	for _, r := range revs {
+		if r.Type.Has(RevisionTypeBaseline) {
+			base = r.Version
+		}
+		applied[r.Version] = r.Applied == r.Total
+	}
+	var skipped []File
+	for _, f := range files {
+		if !applied[f.Version()] && f.Version() > base {
+			skipped = append(skipped, f)
+		}
+	}
+	return skipped
+}
+
+// Execute executes the given migration file on the database. If it sees a file, that has been partially applied, it
+// will continue with the next statement in line. The file is executed in a transaction, unless its transaction mode
+// is TxModeNone. In TxModeAll, the file joins the transaction that was opened for all pending files, if there is one.
+func (e *Executor) Execute(ctx context.Context, m File) error {
+	return e.withTx(ctx, m, func(drv Driver, rrw RevisionReadWriter) error {
+		return e.execute(ctx, m, drv, rrw)
+	})
+}
+
+// execute executes the given migration file using the given Driver and RevisionReadWriter.
+func (e *Executor) execute(ctx context.Context, m File, drv Driver, rrw RevisionReadWriter) (err error) {
+	ctx, span := startSpan(ctx, e.tracer, "atlas.migrate.file",
+		Attr{Key: "atlas.migrate.file", Value: m.Name()},
+		Attr{Key: "atlas.migrate.version", Value: m.Version()},
+	)
+	// Registered first, to report the error of
+	// writing the revision as well (if any).
+	defer func() { span.end(err) }()
+	hf, err := e.dir.Checksum()
+	if err != nil {
+		return fmt.Errorf("sql/migrate: execute: compute hash: %w", err)
+	}
+	hash, err := hf.SumByName(m.Name())
+	if err != nil {
+		return fmt.Errorf("sql/migrate: execute: scanning checksum from %q: %w", m.Name(), err)
+	}
+	stmts, err := m.Stmts()
+	if err != nil {
+		return fmt.Errorf("sql/migrate: execute: scanning statements from %q: %w", m.Name(), err)
+	}
+	sums, err := stmtSums(stmts)
+	if err != nil {
+		return err
+	}
+	version := m.Version()
 	// If there already is a revision with this version in the database,
 	// and it is partially applied, continue where the last attempt was left off.
-	r, err := e.rrw.ReadRevision(ctx, version)
+	r, err := rrw.ReadRevision(ctx, version)
 	if err != nil && !errors.Is(err, ErrRevisionNotExist) {
 		return fmt.Errorf("sql/migrate: execute: read revision: %w", err)
 	}
@@ -747,13 +1450,16 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 			Hash:        hash,
 		}
 	}
+	// Execution starts (or continues) now, and the
+	// revision keeps this point until it is done.
+	r.ExecutedAt = time.Now()
 	// Save once to mark as started in the database.
-	if err = e.writeRevision(ctx, r); err != nil {
+	if err = e.writeRevision(ctx, rrw, r); err != nil {
 		return err
 	}
 	// Make sure to store the Revision information.
 	defer func(ctx context.Context, e *Executor, r *Revision) {
-		if err2 := e.writeRevision(ctx, r); err2 != nil {
+		if err2 := e.writeRevision(ctx, rrw, r); err2 != nil {
 			err = wrap(err2, err)
 		}
 	}(ctx, e, r)
@@ -761,42 +1467,197 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 		// If the file has been applied partially before, check if the
 		// applied statements have not changed.
 		for i := 0; i < r.Applied; i++ {
-			if i > len(sums) || sums[i] != strings.TrimPrefix(r.PartialHashes[i], "h1:") {
+			if i >= len(sums) || i >= len(r.PartialHashes) || sums[i] != strings.TrimPrefix(r.PartialHashes[i], "h1:") {
 				err = HistoryChangedError{m.Name(), i + 1}
-				e.log.Log(LogError{Error: err})
+				e.log.Log(LogError{File: m, Error: err})
 				return err
 			}
 		}
 	}
+	// Execution continues from the first statement that was not applied,
+	// and the error of the previous attempt (if any) no longer applies.
+	r.Error, r.ErrorStmt = "", ""
+	start := time.Now()
 	e.log.Log(LogFile{m, r.Version, r.Description, r.Applied})
+	if err = e.fileHooks(ctx, m, false); err != nil {
+		r.done()
+		r.Error = err.Error()
+		return err
+	}
+	restore, err := e.setTimeouts(ctx, drv)
+	if err != nil {
+		err = fmt.Errorf("sql/migrate: execute: %w", err)
+		r.done()
+		r.Error = err.Error()
+		return err
+	}
+	defer func() {
+		if err2 := restore(ctx); err2 != nil {
+			err = wrap(fmt.Errorf("sql/migrate: execute: restore session timeouts: %w", err2), err)
+		}
+	}()
 	for _, stmt := range stmts[r.Applied:] {
 		e.log.Log(LogStmt{stmt})
-		if _, err = e.drv.ExecContext(ctx, stmt); err != nil {
-			e.log.Log(LogError{SQL: stmt, Error: err})
+		if e.dryRun {
+			continue
+		}
+		c := &Change{Cmd: stmt}
+		if err = e.stmtHooks(ctx, m, c, false); err != nil {
 			r.done()
 			r.ErrorStmt = stmt
 			r.Error = err.Error()
-			return fmt.Errorf("sql/migrate: execute: executing statement %q from version %q: %w", stmt, r.Version, err)
+			return err
+		}
+		var (
+			t   = time.Now()
+			res sql.Result
+		)
+		if res, err = e.execStmt(ctx, drv, stmt); err != nil {
+			e.log.Log(LogError{File: m, SQL: stmt, Error: err})
+			r.done()
+			r.ErrorStmt = stmt
+			r.Error = err.Error()
+			return newExecError(m, r.Applied, stmt, err)
 		}
+		e.log.Log(LogStmtDone{File: m, SQL: stmt, Duration: time.Since(t), RowsAffected: result.RowsAffected(res)})
 		r.PartialHashes = append(r.PartialHashes, "h1:"+sums[r.Applied])
 		r.Applied++
-		if err = e.writeRevision(ctx, r); err != nil {
+		if err = e.writeRevision(ctx, rrw, r); err != nil {
+			return err
+		}
+		if err = e.stmtHooks(ctx, m, c, true); err != nil {
+			r.done()
+			r.Error = err.Error()
 			return err
 		}
 	}
 	r.done()
+	if err = e.fileHooks(ctx, m, true); err != nil {
+		r.Error = err.Error()
+		return err
+	}
+	if !e.dryRun {
+		e.log.Log(LogFileDone{File: m, Duration: time.Since(start)})
+	}
 	return
 }
 
-func (e *Executor) writeRevision(ctx context.Context, r *Revision) error {
-	r.ExecutedAt = time.Now()
+// execStmt executes the given statement, limited by the statement timeout of the Executor, if configured.
+func (e *Executor) execStmt(ctx context.Context, drv Driver, stmt string) (sql.Result, error) {
+	if e.stmtTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.stmtTimeout)
+		defer cancel()
+	}
+	ctx, span := startSpan(ctx, e.tracer, "atlas.migrate.stmt", Attr{Key: "db.statement", Value: stmt})
+	res, err := drv.ExecContext(ctx, stmt)
+	span.end(err)
+	return res, err
+}
+
+// setTimeouts configures the session timeouts of the given driver, if it implements
+// the TimeoutSetter. The returned function restores the previous session settings.
+func (e *Executor) setTimeouts(ctx context.Context, drv Driver) (RestoreFunc, error) {
+	s, ok := drv.(TimeoutSetter)
+	if !ok || e.dryRun || e.stmtTimeout == 0 && e.lockWait == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+	restore, err := s.SetTimeouts(ctx, e.stmtTimeout, e.lockWait)
+	if err != nil {
+		return nil, fmt.Errorf("set session timeouts: %w", err)
+	}
+	return restore, nil
+}
+
+// fileHooks runs the hooks that are called before (or after) executing the given file.
+func (e *Executor) fileHooks(ctx context.Context, f File, after bool) error {
+	if e.dryRun {
+		return nil
+	}
+	for _, h := range e.hooks {
+		fn, when := h.BeforeFile, "before"
+		if after {
+			fn, when = h.AfterFile, "after"
+		}
+		if fn == nil {
+			continue
+		}
+		if err := fn(ctx, f); err != nil {
+			return fmt.Errorf("sql/migrate: execute: %s file hook of %q: %w", when, f.Name(), err)
+		}
+	}
+	return nil
+}
+
+// stmtHooks runs the hooks that are called before (or after) executing the given statement.
+func (e *Executor) stmtHooks(ctx context.Context, f File, c *Change, after bool) error {
+	if e.dryRun {
+		return nil
+	}
+	for _, h := range e.hooks {
+		fn, when := h.BeforeStmt, "before"
+		if after {
+			fn, when = h.AfterStmt, "after"
+		}
+		if fn == nil {
+			continue
+		}
+		if err := fn(ctx, f, c); err != nil {
+			return fmt.Errorf("sql/migrate: execute: %s statement hook of %q: %w", when, f.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (e *Executor) writeRevision(ctx context.Context, rrw RevisionReadWriter, r *Revision) error {
+	if e.dryRun {
+		return nil
+	}
+	if r.ExecutedAt.IsZero() {
+		r.ExecutedAt = time.Now()
+	}
 	r.OperatorVersion = e.operator
-	if err := e.rrw.WriteRevision(ctx, r); err != nil {
+	if err := rrw.WriteRevision(ctx, r); err != nil {
 		return fmt.Errorf("sql/migrate: execute: write revision: %w", err)
 	}
 	return nil
 }
 
+// ExecError is returned by the Executor in case a statement of a migration file failed to execute.
+// It holds the position of the statement in the file, so it can be reported to users (e.g. as CI
+// annotations). The position fields are zero in case they are unknown.
+type ExecError struct {
+	File    string // File name.
+	Version string // File version.
+	Stmt    int    // Statement index in the file, starting from 0.
+	Pos     int    // Statement byte offset in the file.
+	Line    int    // Statement line number in the file, starting from 1.
+	SQL     string // Statement text.
+	Err     error  // Underlying driver error.
+}
+
+// newExecError returns an ExecError for the i-th statement of the file.
+func newExecError(f File, i int, stmt string, err error) ExecError {
+	e := ExecError{File: f.Name(), Version: f.Version(), Stmt: i, SQL: stmt, Err: err}
+	if stmts, err := f.StmtDecls(); err == nil && i < len(stmts) && stmts[i].Text == stmt {
+		e.Pos = stmts[i].Pos
+		if b := f.Bytes(); e.Pos <= len(b) {
+			e.Line = bytes.Count(b[:e.Pos], []byte("\n")) + 1
+		}
+	}
+	return e
+}
+
+// Error implements the error interface.
+func (e ExecError) Error() string {
+	return fmt.Sprintf("sql/migrate: execute: executing statement %q from version %q: %v", e.SQL, e.Version, e.Err)
+}
+
+// Unwrap returns the underlying driver error.
+func (e ExecError) Unwrap() error {
+	return e.Err
+}
+
 // HistoryChangedError is returned if between two execution attempts already applied statements of a file have changed.
 type HistoryChangedError struct {
 	File string
@@ -809,50 +1670,587 @@ func (e HistoryChangedError) Error() string {
 
 // ExecuteN executes n pending migration files. If n<=0 all pending migration files are executed.
 func (e *Executor) ExecuteN(ctx context.Context, n int) (err error) {
-	pending, err := e.Pending(ctx)
+	return e.withLock(ctx, func() error {
+		pending, err := e.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			if n >= len(pending) {
+				n = len(pending)
+			}
+			pending = pending[:n]
+		}
+		return e.exec(ctx, pending)
+	})
+}
+
+// ExecuteTo executes all pending migration files up to and including version. An error is returned if
+// the migration directory has no file with this version, or if it was already applied on the database.
+func (e *Executor) ExecuteTo(ctx context.Context, version string) (err error) {
+	return e.withLock(ctx, func() error {
+		pending, err := e.Pending(ctx)
+		if err != nil && !errors.Is(err, ErrNoPendingFiles) {
+			return err
+		}
+		// Strip pending files greater given version.
+		if idx := FilesLastIndex(pending, func(file File) bool {
+			return file.Version() == version
+		}); idx != -1 {
+			return e.exec(ctx, pending[:idx+1])
+		}
+		files, ferr := e.dir.Files()
+		switch {
+		case ferr != nil:
+			return fmt.Errorf("sql/migrate: execute: select migration files: %w", ferr)
+		case FilesLastIndex(files, func(f File) bool { return f.Version() == version }) == -1:
+			return fmt.Errorf("sql/migrate: execute: migration with version %q not found", version)
+		case err != nil:
+			return err
+		default:
+			return fmt.Errorf("sql/migrate: execute: migration with version %q is already applied", version)
+		}
+	})
+}
+
+// withLock calls fn while holding the lock of the Executor, if it was configured.
+func (e *Executor) withLock(ctx context.Context, fn func() error) (err error) {
+	if e.lockName == "" {
+		return fn()
+	}
+	unlock, err := e.drv.(schema.Locker).Lock(ctx, e.lockName, e.lockTimeout)
+	if err != nil {
+		return fmt.Errorf("sql/migrate: execute: acquire lock %q: %w", e.lockName, err)
+	}
+	defer func() {
+		if err2 := unlock(); err2 != nil {
+			err = wrap(fmt.Errorf("sql/migrate: execute: release lock %q: %w", e.lockName, err2), err)
+		}
+	}()
+	return fn()
+}
+
+func (e *Executor) exec(ctx context.Context, files []File) error {
+	revs, err := e.rrw.ReadRevisions(ctx)
 	if err != nil {
+		return fmt.Errorf("sql/migrate: execute: read revisions: %w", err)
+	}
+	if err := e.checkDestructive(files); err != nil {
 		return err
 	}
-	if n > 0 {
-		if n >= len(pending) {
-			n = len(pending)
+	LogIntro(e.log, revs, files)
+	if err := e.allTx(ctx, func() error {
+		for _, m := range files {
+			if err := e.Execute(ctx, m); err != nil {
+				return err
+			}
 		}
-		pending = pending[:n]
+		return nil
+	}); err != nil {
+		return err
 	}
-	return e.exec(ctx, pending)
+	e.log.Log(LogDone{})
+	return nil
 }
 
-// ExecuteTo executes all pending migration files up to and including version.
-func (e *Executor) ExecuteTo(ctx context.Context, version string) (err error) {
-	pending, err := e.Pending(ctx)
+// withTx calls fn with the Driver and the RevisionReadWriter to use for the given file. The call is wrapped
+// in a transaction, unless the transaction mode of the file is TxModeNone. In TxModeAll, the transaction that
+// was opened by allTx is used, if there is one.
+func (e *Executor) withTx(ctx context.Context, f File, fn func(Driver, RevisionReadWriter) error) error {
+	mode, err := e.txModeFor(f)
 	if err != nil {
 		return err
 	}
-	// Strip pending files greater given version.
-	switch idx := FilesLastIndex(pending, func(file File) bool {
-		return file.Version() == version
-	}); idx {
-	case -1:
-		return fmt.Errorf("sql/migrate: execute: migration with version %q not found", version)
+	switch {
+	case mode == TxModeNone:
+		return fn(e.drv, e.rrw)
+	case mode == TxModeAll && e.tx != nil:
+		return fn(e.tx.Driver, e.txRevisions(e.tx))
+	}
+	tx, err := e.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	return e.endTx(ctx, tx, fn(tx.Driver, e.txRevisions(tx)))
+}
+
+// allTx wraps fn with one transaction in TxModeAll.
+func (e *Executor) allTx(ctx context.Context, fn func() error) error {
+	if e.txOpen == nil || e.txMode != TxModeAll {
+		return fn()
+	}
+	tx, err := e.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	e.tx = tx
+	defer func() { e.tx = nil }()
+	return e.endTx(ctx, tx, fn())
+}
+
+// txModeFor returns the transaction mode of the given file. Files are executed without
+// transactions, and their directives are ignored, if the Executor has no TxOpener.
+func (e *Executor) txModeFor(f File) (TxMode, error) {
+	if e.txOpen == nil {
+		return TxModeNone, nil
+	}
+	d, ok := f.(interface{ Directive(string) []string })
+	if !ok {
+		return e.txMode, nil
+	}
+	switch ds := d.Directive(directiveTxMode); {
+	case len(ds) > 1:
+		return "", fmt.Errorf("sql/migrate: execute: multiple txmode values found in file %q: %q", f.Name(), ds)
+	case len(ds) == 0 || TxMode(ds[0]) == e.txMode:
+		return e.txMode, nil
+	case TxMode(ds[0]) == TxModeAll:
+		return "", fmt.Errorf("sql/migrate: execute: txmode %q is not allowed in file directive %q", TxModeAll, f.Name())
+	case TxMode(ds[0]) == TxModeNone, TxMode(ds[0]) == TxModeFile:
+		if e.txMode == TxModeAll {
+			return "", fmt.Errorf("sql/migrate: execute: cannot set txmode directive to %q in %q when txmode %q is set globally", ds[0], f.Name(), TxModeAll)
+		}
+		return TxMode(ds[0]), nil
 	default:
-		pending = pending[:idx+1]
+		return "", fmt.Errorf("sql/migrate: execute: unknown txmode %q found in file directive %q", ds[0], f.Name())
 	}
-	return e.exec(ctx, pending)
 }
 
-func (e *Executor) exec(ctx context.Context, files []File) error {
-	revs, err := e.rrw.ReadRevisions(ctx)
+// beginTx opens a new transaction. In dry-run mode, no transaction is opened,
+// but its boundaries are logged as if it was.
+func (e *Executor) beginTx(ctx context.Context) (*Tx, error) {
+	var tx *Tx
+	if e.dryRun {
+		nop := func() error { return nil }
+		tx = &Tx{Driver: e.drv, Commit: nop, Rollback: nop}
+	} else {
+		var err error
+		if tx, err = e.txOpen(ctx); err != nil {
+			return nil, fmt.Errorf("sql/migrate: execute: begin transaction: %w", err)
+		}
+	}
+	e.log.Log(LogTx{Op: TxBegin})
+	return tx, nil
+}
+
+// endTx commits the transaction, or rolls it back in case of an error. On rollback, the revisions
+// that were written outside the transaction are restored to their state before it. Otherwise, a
+// retry would skip the statements that were rolled back.
+func (e *Executor) endTx(ctx context.Context, tx *Tx, err error) error {
 	if err != nil {
-		return fmt.Errorf("sql/migrate: execute: read revisions: %w", err)
+		e.log.Log(LogTx{Op: TxRollback})
+		if err2 := tx.Rollback(); err2 != nil {
+			err = wrap(err2, err)
+		}
+		if tx.revs != nil {
+			if err2 := tx.revs.restore(ctx); err2 != nil {
+				err = wrap(err2, err)
+			}
+		}
+		return err
 	}
-	LogIntro(e.log, revs, files)
-	for _, m := range files {
-		if err := e.Execute(ctx, m); err != nil {
+	e.log.Log(LogTx{Op: TxCommit})
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sql/migrate: execute: commit transaction: %w", err)
+	}
+	return nil
+}
+
+// txRevisions returns the RevisionReadWriter to use within the transaction. If the transaction has
+// no RevisionReadWriter, the revisions are written outside of it using the one of the Executor.
+func (e *Executor) txRevisions(tx *Tx) RevisionReadWriter {
+	if tx.RevisionReadWriter != nil {
+		return tx.RevisionReadWriter
+	}
+	if tx.revs == nil {
+		tx.revs = &txRevisions{RevisionReadWriter: e.rrw, before: make(map[string]*Revision)}
+	}
+	return tx.revs
+}
+
+// txRevisions is a RevisionReadWriter that writes the revisions outside the transaction,
+// and records their state before it, to restore them in case the transaction is rolled back.
+type txRevisions struct {
+	RevisionReadWriter
+	versions []string             // Versions of the revisions, in the order they were written.
+	before   map[string]*Revision // State of the revisions before the transaction, or nil.
+}
+
+// WriteRevision implements RevisionReadWriter.WriteRevision.
+func (t *txRevisions) WriteRevision(ctx context.Context, r *Revision) error {
+	if err := t.record(ctx, r.Version); err != nil {
+		return err
+	}
+	return t.RevisionReadWriter.WriteRevision(ctx, r)
+}
+
+// DeleteRevision implements RevisionReadWriter.DeleteRevision.
+func (t *txRevisions) DeleteRevision(ctx context.Context, v string) error {
+	if err := t.record(ctx, v); err != nil {
+		return err
+	}
+	return t.RevisionReadWriter.DeleteRevision(ctx, v)
+}
+
+// record records the state of the revision, if it was not recorded before.
+func (t *txRevisions) record(ctx context.Context, v string) error {
+	if _, ok := t.before[v]; ok {
+		return nil
+	}
+	r, err := t.RevisionReadWriter.ReadRevision(ctx, v)
+	switch {
+	case errors.Is(err, ErrRevisionNotExist):
+		r = nil
+	case err != nil:
+		return fmt.Errorf("sql/migrate: execute: read revision: %w", err)
+	default:
+		// Revisions are modified in place during the execution.
+		c := *r
+		c.PartialHashes = append([]string(nil), r.PartialHashes...)
+		r = &c
+	}
+	t.before[v] = r
+	t.versions = append(t.versions, v)
+	return nil
+}
+
+// restore restores the recorded revisions to their state before the transaction. That is,
+// the applied statements, the partial hashes and the error fields of the failed attempt are
+// reset, and revisions that were created within the transaction are deleted.
+func (t *txRevisions) restore(ctx context.Context) error {
+	for i := len(t.versions) - 1; i >= 0; i-- {
+		v := t.versions[i]
+		if r := t.before[v]; r != nil {
+			if err := t.RevisionReadWriter.WriteRevision(ctx, r); err != nil {
+				return fmt.Errorf("sql/migrate: execute: restore revision: %w", err)
+			}
+		} else if err := t.RevisionReadWriter.DeleteRevision(ctx, v); err != nil && !errors.Is(err, ErrRevisionNotExist) {
+			return fmt.Errorf("sql/migrate: execute: restore revision: %w", err)
+		}
+	}
+	t.versions, t.before = nil, make(map[string]*Revision)
+	return nil
+}
+
+type (
+	downConfig struct {
+		force bool // revert files without down statements
+	}
+	// DownOption configures the reverting of applied migration files.
+	DownOption func(*downConfig)
+)
+
+// DownWithForce allows reverting applied migration files that have no down statements.
+// The revisions of these files are deleted without executing any statement.
+func DownWithForce(b bool) DownOption {
+	return func(c *downConfig) {
+		c.force = b
+	}
+}
+
+// NotReversibleError is returned by Down and DownTo if an applied
+// migration file has no statements for reverting it.
+type NotReversibleError struct{ File string }
+
+func (e NotReversibleError) Error() string {
+	return fmt.Sprintf("sql/migrate: down: migration file %q is not reversible", e.File)
+}
+
+// Down reverts the last n applied migration files, from the latest to the earliest, using the statements
+// of their DownFile implementations, and deletes their revisions. If n<=0, all applied migration files are
+// reverted. Note, the baseline revision and the files it covers cannot be reverted.
+func (e *Executor) Down(ctx context.Context, n int, opts ...DownOption) error {
+	return e.withLock(ctx, func() error {
+		revs, base, err := e.applied(ctx)
+		if err != nil {
 			return err
 		}
+		if n > 0 && n < len(revs) {
+			base, revs = revs[len(revs)-n-1].Version, revs[len(revs)-n:]
+		}
+		return e.down(ctx, revs, base, opts)
+	})
+}
+
+// DownTo reverts all migration files that were applied after the given version.
+func (e *Executor) DownTo(ctx context.Context, version string, opts ...DownOption) error {
+	return e.withLock(ctx, func() error {
+		return e.downTo(ctx, version, opts)
+	})
+}
+
+func (e *Executor) downTo(ctx context.Context, version string, opts []DownOption) error {
+	revs, base, err := e.applied(ctx)
+	if err != nil {
+		return err
+	}
+	switch idx := func() int {
+		for i, r := range revs {
+			if r.Version == version {
+				return i
+			}
+		}
+		return -1
+	}(); {
+	case idx != -1:
+		revs = revs[idx+1:]
+	case version != base:
+		return fmt.Errorf("sql/migrate: down: revision with version %q not found", version)
+	}
+	return e.down(ctx, revs, version, opts)
+}
+
+// applied returns the revisions that can be reverted, and
+// the version of the baseline revision, if there is one.
+func (e *Executor) applied(ctx context.Context) ([]*Revision, string, error) {
+	revs, err := e.rrw.ReadRevisions(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("sql/migrate: down: read revisions: %w", err)
+	}
+	var base string
+	for i := len(revs) - 1; i >= 0; i-- {
+		if revs[i].Type.Has(RevisionTypeBaseline) {
+			base, revs = revs[i].Version, revs[i+1:]
+			break
+		}
+	}
+	return revs, base, nil
+}
+
+func (e *Executor) down(ctx context.Context, revs []*Revision, to string, opts []DownOption) error {
+	c := &downConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(revs) == 0 {
+		return ErrNoAppliedFiles
+	}
+	all, err := e.dir.Files()
+	if err != nil {
+		return fmt.Errorf("sql/migrate: down: select migration files: %w", err)
+	}
+	var (
+		files = make([]File, len(revs))
+		stmts = make([][]string, len(revs))
+	)
+	// Collect all statements before reverting any file.
+	for i, r := range revs {
+		idx := FilesLastIndex(all, func(f File) bool { return f.Version() == r.Version })
+		if idx == -1 {
+			return &MissingMigrationError{r.Version, r.Description}
+		}
+		files[i] = all[idx]
+		// A checkpoint file captures the entire schema at its version,
+		// and there is no prior state in the database to revert to.
+		if ck, ok := files[i].(CheckpointFile); ok && ck.IsCheckpoint() {
+			return fmt.Errorf("sql/migrate: down: checkpoint file %q cannot be reverted", files[i].Name())
+		}
+		if r.Applied != r.Total {
+			return fmt.Errorf("sql/migrate: down: migration file %q is partially applied", files[i].Name())
+		}
+		if f, ok := files[i].(DownFile); ok {
+			if stmts[i], err = f.DownStmts(); err != nil {
+				return fmt.Errorf("sql/migrate: down: scanning down statements from %q: %w", files[i].Name(), err)
+			}
+		}
+		if len(stmts[i]) == 0 && r.Total > 0 && !c.force {
+			return NotReversibleError{File: files[i].Name()}
+		}
+	}
+	e.log.Log(LogExecution{From: revs[len(revs)-1].Version, To: to, Files: files})
+	if err := e.allTx(ctx, func() error {
+		for i := len(revs) - 1; i >= 0; i-- {
+			if err := e.withTx(ctx, files[i], func(drv Driver, rrw RevisionReadWriter) error {
+				return e.revert(ctx, files[i], revs[i], stmts[i], drv, rrw)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
 	e.log.Log(LogDone{})
-	return err
+	return nil
+}
+
+// revert executes the down statements of the given file, and deletes its revision.
+func (e *Executor) revert(ctx context.Context, f File, r *Revision, stmts []string, drv Driver, rrw RevisionReadWriter) (err error) {
+	start := time.Now()
+	e.log.Log(LogFile{File: f, Version: r.Version, Desc: r.Description})
+	restore, err := e.setTimeouts(ctx, drv)
+	if err != nil {
+		return fmt.Errorf("sql/migrate: down: %w", err)
+	}
+	defer func() {
+		if err2 := restore(ctx); err2 != nil {
+			err = wrap(fmt.Errorf("sql/migrate: down: restore session timeouts: %w", err2), err)
+		}
+	}()
+	for _, stmt := range stmts {
+		e.log.Log(LogStmt{stmt})
+		if e.dryRun {
+			continue
+		}
+		t := time.Now()
+		res, err := e.execStmt(ctx, drv, stmt)
+		if err != nil {
+			e.log.Log(LogError{File: f, SQL: stmt, Error: err})
+			return fmt.Errorf("sql/migrate: down: executing statement %q from version %q: %w", stmt, r.Version, err)
+		}
+		e.log.Log(LogStmtDone{File: f, SQL: stmt, Duration: time.Since(t), RowsAffected: result.RowsAffected(res)})
+	}
+	if e.dryRun {
+		return nil
+	}
+	if err := rrw.DeleteRevision(ctx, r.Version); err != nil {
+		return fmt.Errorf("sql/migrate: down: delete revision: %w", err)
+	}
+	e.log.Log(LogFileDone{File: f, Duration: time.Since(start)})
+	return nil
+}
+
+// ErrNotDirty is returned by the repair operations of the Executor
+// in case the last revision of the database is not dirty.
+var ErrNotDirty = errors.New("sql/migrate: repair: last revision is not dirty")
+
+// Dirty returns the last revision of the database in case it is dirty. i.e. its file was
+// partially applied, or its execution failed. A nil revision is returned if it is not dirty.
+func (e *Executor) Dirty(ctx context.Context) (*Revision, error) {
+	revs, err := e.rrw.ReadRevisions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql/migrate: repair: read revisions: %w", err)
+	}
+	if len(revs) == 0 {
+		return nil, nil
+	}
+	if r := revs[len(revs)-1]; r.Applied != r.Total || r.Error != "" {
+		return r, nil
+	}
+	return nil, nil
+}
+
+// MarkApplied marks the dirty revision with the given version as fully applied, and clears
+// its error. It should be used only after the remaining statements of its file were applied
+// (or fixed) manually. The revision is marked as resolved, to keep this action traceable.
+func (e *Executor) MarkApplied(ctx context.Context, version string) error {
+	return e.repair(ctx, version, func(r *Revision) error {
+		r.Applied = r.Total
+		r.Error, r.ErrorStmt = "", ""
+		r.Type |= RevisionTypeResolved
+		return e.writeRevision(ctx, e.rrw, r)
+	})
+}
+
+// DeleteDirty deletes the dirty revision with the given version from the database. Hence,
+// its file is executed from its first statement by the next execution. It should be used
+// only after the applied statements of the file were reverted manually.
+func (e *Executor) DeleteDirty(ctx context.Context, version string) error {
+	return e.repair(ctx, version, func(r *Revision) error {
+		if e.dryRun {
+			return nil
+		}
+		if err := e.rrw.DeleteRevision(ctx, r.Version); err != nil {
+			return fmt.Errorf("sql/migrate: repair: delete revision: %w", err)
+		}
+		return nil
+	})
+}
+
+// RetryFrom re-executes the file of the dirty revision with the given version, starting from
+// its i-th statement (0-based). The statement must not be after the first statement that was
+// not applied, as statements cannot be skipped. Use MarkApplied for skipping statements.
+func (e *Executor) RetryFrom(ctx context.Context, version string, i int) error {
+	if err := e.repair(ctx, version, func(r *Revision) error {
+		if i < 0 || i > r.Applied {
+			return fmt.Errorf("sql/migrate: repair: statement %d is out of range [0, %d]", i, r.Applied)
+		}
+		r.Applied = i
+		if i < len(r.PartialHashes) {
+			r.PartialHashes = r.PartialHashes[:i]
+		}
+		r.Error, r.ErrorStmt = "", ""
+		return e.writeRevision(ctx, e.rrw, r)
+	}); err != nil {
+		return err
+	}
+	return e.ExecuteN(ctx, 1)
+}
+
+// repair calls fn with the last revision of the database, after ensuring it
+// has the given version and is dirty, while holding the Executor lock.
+func (e *Executor) repair(ctx context.Context, version string, fn func(*Revision) error) error {
+	return e.withLock(ctx, func() error {
+		revs, err := e.rrw.ReadRevisions(ctx)
+		if err != nil {
+			return fmt.Errorf("sql/migrate: repair: read revisions: %w", err)
+		}
+		idx := -1
+		for i := range revs {
+			if revs[i].Version == version {
+				idx = i
+			}
+		}
+		switch {
+		case idx == -1:
+			return fmt.Errorf("sql/migrate: repair: revision with version %q not found", version)
+		case idx != len(revs)-1:
+			return fmt.Errorf("sql/migrate: repair: revision %q is not the last revision", version)
+		case revs[idx].Applied == revs[idx].Total && revs[idx].Error == "":
+			return ErrNotDirty
+		default:
+			return fn(revs[idx])
+		}
+	})
+}
+
+// A Replayer computes the state of a migration directory by replaying (executing) its files on a
+// dev database, and inspecting the result. The dev database can be a temporary database (e.g. a
+// container), or an embedded one like an in-memory SQLite database. Replayer implements the
+// StateReader interface, and can be used as the current state for computing the changes to a
+// desired state that is defined in HCL or SQL. For example:
+//
+//	current, err := migrate.NewReplayer(dev, dir).ReadState(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	changes, err := dev.RealmDiff(current, desired)
+//
+// Note, the dev database must be clean, and it is restored to its original state after each replay.
+type Replayer struct {
+	dev  Driver
+	dir  Dir
+	opts []ReplayOption
+}
+
+var _ StateReader = (*Replayer)(nil)
+
+// NewReplayer returns a Replayer that replays the given directory on the dev database.
+func NewReplayer(dev Driver, dir Dir, opts ...ReplayOption) *Replayer {
+	return &Replayer{dev: dev, dir: dir, opts: opts}
+}
+
+// ReadState implements the StateReader interface. It replays the migration
+// directory, and returns the state of the realm of the dev database.
+func (r *Replayer) ReadState(ctx context.Context) (*schema.Realm, error) {
+	return r.replay(ctx, RealmConn(r.dev, nil))
+}
+
+// ReadSchemaState replays the migration directory, and returns the state of the schema that the
+// dev database is connected to, wrapped in its realm. Use StateReaderFunc(r.ReadSchemaState) for
+// passing it as a StateReader.
+func (r *Replayer) ReadSchemaState(ctx context.Context) (*schema.Realm, error) {
+	return r.replay(ctx, SchemaConn(r.dev, "", nil))
+}
+
+func (r *Replayer) replay(ctx context.Context, state StateReader) (*schema.Realm, error) {
+	if _, ok := r.dev.(Snapshoter); !ok {
+		return nil, fmt.Errorf("sql/migrate: replay: driver %T does not support snapshots", r.dev)
+	}
+	ex, err := NewExecutor(r.dev, r.dir, NopRevisionReadWriter{})
+	if err != nil {
+		return nil, err
+	}
+	return ex.Replay(ctx, state, r.opts...)
 }
 
 type (
@@ -924,6 +2322,47 @@ type (
 		CheckClean(context.Context, *TableIdent) error
 	}
 
+	// TimeoutSetter is an optional interface implemented by drivers that can limit the execution time of
+	// statements, and the time they wait for locks held by other sessions (e.g. a table lock), on the
+	// database side. A zero duration leaves the corresponding setting of the session unchanged.
+	//
+	// Settings are scoped to the transaction of the driver, if it is supported by the database, or to a
+	// dedicated connection the driver is bound to until the returned RestoreFunc is called. RestoreFunc
+	// restores the previous settings of the session and releases the connection (if it was acquired).
+	TimeoutSetter interface {
+		SetTimeouts(ctx context.Context, stmt, lock time.Duration) (RestoreFunc, error)
+	}
+
+	// Capable wraps the Capabilities method. It is optionally implemented by
+	// drivers to allow generic code (e.g. planners, analyzers and executors)
+	// to adjust their behavior without depending on a concrete driver.
+	Capable interface {
+		Capabilities() Capabilities
+	}
+
+	// Capabilities describes the features supported by the connected database.
+	Capabilities struct {
+		// TransactionalDDL indicates DDL statements can be executed
+		// within a transaction, and are rolled back on failure.
+		TransactionalDDL bool
+		// Check indicates CHECK constraints are supported.
+		Check bool
+		// ForeignKey indicates foreign key constraints are supported.
+		ForeignKey bool
+		// DropColumn indicates columns can be dropped using the
+		// ALTER TABLE statement, without recreating the table.
+		DropColumn bool
+		// RenameColumn indicates columns can be renamed using the
+		// ALTER TABLE statement, without recreating the table.
+		RenameColumn bool
+		// ModifyColumn indicates the type of existing columns can
+		// be changed, without recreating the table.
+		ModifyColumn bool
+		// OnlineIndex indicates indexes can be created
+		// without blocking writes to their table.
+		OnlineIndex bool
+	}
+
 	// NotCleanError is returned when the connected dev-db is not in a clean state (aka it has schemas and tables).
 	// This check is done to ensure no data is lost by overriding it when working on the dev-db.
 	NotCleanError struct {
@@ -935,6 +2374,79 @@ func (e *NotCleanError) Error() string {
 	return "sql/migrate: connected database is not clean: " + e.Reason
 }
 
+// DriverCapabilities returns the capabilities of the given driver. The second
+// value reports if the driver implements the Capable interface.
+func DriverCapabilities(drv Driver) (Capabilities, bool) {
+	c, ok := drv.(Capable)
+	if !ok {
+		return Capabilities{}, false
+	}
+	return c.Capabilities(), true
+}
+
+type (
+	// DialectPlan holds the migration plan that was created for one of the
+	// dialects passed to PlanDialects.
+	DialectPlan struct {
+		// Dialect is the name the planner was given in PlanDialects.
+		Dialect string
+		// Plan is the migration plan of the dialect, or nil if the
+		// changes are not compatible with it.
+		Plan *Plan
+		// Incompatible holds the changes that cannot be planned by the dialect.
+		Incompatible []*IncompatibleChange
+	}
+
+	// IncompatibleChange describes a change that cannot be planned by a dialect.
+	IncompatibleChange struct {
+		// Change is the incompatible change, or nil if the error
+		// was caused by a combination of changes.
+		Change schema.Change
+		// Err is the error returned by the planner.
+		Err error
+	}
+)
+
+// PlanDialects plans the given changes for each of the given dialects (e.g. MySQL and PostgreSQL),
+// and returns their plans sorted by the dialect names. In case the changes cannot be planned by a
+// dialect, its plan is nil and the incompatible changes are reported.
+//
+//	plans, err := migrate.PlanDialects(ctx, "add_users", changes, map[string]migrate.PlanApplier{
+//		"mysql":    mysql.DefaultPlan,
+//		"postgres": postgres.DefaultPlan,
+//	})
+func PlanDialects(ctx context.Context, name string, changes []schema.Change, planners map[string]PlanApplier, opts ...PlanOption) ([]*DialectPlan, error) {
+	dialects := make([]string, 0, len(planners))
+	for d := range planners {
+		dialects = append(dialects, d)
+	}
+	sort.Strings(dialects)
+	plans := make([]*DialectPlan, 0, len(dialects))
+	for _, d := range dialects {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p := &DialectPlan{Dialect: d}
+		plan, err := planners[d].PlanChanges(ctx, name, changes, opts...)
+		if err == nil {
+			p.Plan = plan
+			plans = append(plans, p)
+			continue
+		}
+		// Plan the changes one by one to report all incompatible changes.
+		for _, c := range changes {
+			if _, cerr := planners[d].PlanChanges(ctx, name, []schema.Change{c}, opts...); cerr != nil {
+				p.Incompatible = append(p.Incompatible, &IncompatibleChange{Change: c, Err: cerr})
+			}
+		}
+		if len(p.Incompatible) == 0 {
+			p.Incompatible = append(p.Incompatible, &IncompatibleChange{Err: err})
+		}
+		plans = append(plans, p)
+	}
+	return plans, nil
+}
+
 // NopRevisionReadWriter is a RevisionReadWriter that does nothing.
 // It is useful for one-time replay of the migration directory.
 type NopRevisionReadWriter struct{}
@@ -1013,11 +2525,34 @@ type (
 		SQL string
 	}
 
+	// LogStmtDone is sent if an SQL statement was executed successfully.
+	LogStmtDone struct {
+		File         File // The File of the statement, or nil if it is not part of a migration file.
+		SQL          string
+		Duration     time.Duration // Execution time of the statement.
+		RowsAffected int64         // Number of rows affected by the statement, or -1 if unknown.
+	}
+
+	// LogFileDone is sent if a migration file was executed successfully.
+	LogFileDone struct {
+		File     File
+		Duration time.Duration // Execution time of the file.
+	}
+
+	// LogTx is sent if a transaction is started or ended.
+	LogTx struct {
+		Op TxOp
+	}
+
+	// TxOp describes an operation on a transaction.
+	TxOp string
+
 	// LogDone is sent if the execution is done.
 	LogDone struct{}
 
 	// LogError is sent if there is an error while execution.
 	LogError struct {
+		File  File   // Set, if Error was caused by a migration file.
 		SQL   string // Set, if Error was caused by a SQL statement.
 		Error error
 	}
@@ -1030,9 +2565,19 @@ type (
 func (LogExecution) logEntry() {}
 func (LogFile) logEntry()      {}
 func (LogStmt) logEntry()      {}
+func (LogStmtDone) logEntry()  {}
+func (LogFileDone) logEntry()  {}
+func (LogTx) logEntry()        {}
 func (LogDone) logEntry()      {}
 func (LogError) logEntry()     {}
 
+// List of transaction operations.
+const (
+	TxBegin    TxOp = "BEGIN"
+	TxCommit   TxOp = "COMMIT"
+	TxRollback TxOp = "ROLLBACK"
+)
+
 // Log implements the Logger interface.
 func (NopLogger) Log(LogEntry) {}
 
@@ -6,11 +6,17 @@ package migrate_test
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	_ "embed"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/fs"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"text/template"
 	"time"
@@ -40,6 +46,47 @@ func TestRevisionType_MarshalText(t *testing.T) {
 	}
 }
 
+func TestPlan_JSON(t *testing.T) {
+	plan := &migrate.Plan{
+		Name:          "add_t1",
+		Reversible:    true,
+		Transactional: true,
+		Changes: []*migrate.Change{
+			{
+				Cmd:     "CREATE TABLE t1(c int)",
+				Comment: `create "t1" table`,
+				Reverse: "DROP TABLE t1",
+				Source:  &schema.AddTable{T: schema.NewTable("t1")},
+			},
+			{
+				Cmd:     "INSERT INTO t1 VALUES (?)",
+				Args:    []any{"a"},
+				Reverse: []string{"DELETE FROM t1", "VACUUM"},
+			},
+		},
+	}
+	b, err := json.Marshal(plan)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"Name": "add_t1",
+		"Reversible": true,
+		"Transactional": true,
+		"Changes": [
+			{"Cmd": "CREATE TABLE t1(c int)", "Comment": "create \"t1\" table", "Reverse": "DROP TABLE t1", "SourceType": "AddTable"},
+			{"Cmd": "INSERT INTO t1 VALUES (?)", "Args": ["a"], "Reverse": ["DELETE FROM t1", "VACUUM"]}
+		]
+	}`, string(b))
+
+	var loaded migrate.Plan
+	require.NoError(t, json.Unmarshal(b, &loaded))
+	plan.Changes[0].Source = nil
+	require.Equal(t, plan, &loaded)
+
+	_, err = json.Marshal(&migrate.Change{Cmd: "SELECT 1", Reverse: 1})
+	require.Error(t, err)
+	require.Error(t, json.Unmarshal([]byte(`{"Cmd": "SELECT 1", "Reverse": [1]}`), &migrate.Change{}))
+}
+
 func TestPlanner_WritePlan(t *testing.T) {
 	p := t.TempDir()
 	d, err := migrate.NewLocalDir(p)
@@ -76,6 +123,163 @@ func TestPlanner_WritePlan(t *testing.T) {
 	requireFileEqual(t, d, "add_t1_and_t2.down.sql", "DROP TABLE t1 IF EXISTS\nDROP TABLE t2\n")
 }
 
+func TestPlanner_WritePlanDownFiles(t *testing.T) {
+	var (
+		ctx = context.Background()
+		drv = &mockDriver{}
+		rrw = &mockRevisionReadWriter{}
+		dir = &migrate.MemDir{}
+		pl  = migrate.NewPlanner(drv, dir, migrate.PlanWithDownFiles(true))
+	)
+	require.NoError(t, pl.WritePlan(&migrate.Plan{
+		Version: "1",
+		Name:    "t1",
+		Changes: []*migrate.Change{
+			{Cmd: "CREATE TABLE t1(c int)", Reverse: "DROP TABLE t1"},
+		},
+	}))
+	require.NoError(t, pl.WritePlan(&migrate.Plan{
+		Version: "2",
+		Name:    "t2",
+		Changes: []*migrate.Change{
+			{Cmd: "CREATE TABLE t2(c int)", Reverse: "DROP TABLE t2"},
+			{Cmd: "ALTER TABLE t1 ADD d int, ADD e int", Reverse: []string{"ALTER TABLE t1 DROP e", "ALTER TABLE t1 DROP d"}},
+			// Changes that are reverted by other changes have no reverse statements.
+			{Cmd: "ALTER TABLE t1 VALIDATE CONSTRAINT d", Reverse: []string{}},
+		},
+	}))
+	requireFileEqual(t, dir, "down/2_t2.sql", "ALTER TABLE t1 DROP e;\nALTER TABLE t1 DROP d;\nDROP TABLE t2;\n")
+	require.NoError(t, migrate.Validate(dir))
+
+	ex, err := migrate.NewExecutor(drv, dir, rrw)
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(ctx, 0))
+	drv.executed = nil
+	require.NoError(t, ex.DownTo(ctx, "1"))
+	require.Equal(t, []string{"ALTER TABLE t1 DROP e;", "ALTER TABLE t1 DROP d;", "DROP TABLE t2;"}, drv.executed)
+	require.Len(t, *rrw, 1)
+
+	// Plans with irreversible changes have no down files, and cannot be reverted.
+	require.NoError(t, pl.WritePlan(&migrate.Plan{
+		Version: "3",
+		Name:    "t3",
+		Changes: []*migrate.Change{
+			{Cmd: "CREATE TABLE t3(c int)", Reverse: "DROP TABLE t3"},
+			{Cmd: "INSERT INTO t3 SELECT c FROM t1"},
+		},
+	}))
+	_, err = dir.Open("down/3_t3.sql")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.NoError(t, ex.ExecuteN(ctx, 0))
+	drv.executed = nil
+	require.ErrorAs(t, ex.DownTo(ctx, "1"), &migrate.NotReversibleError{})
+	require.Empty(t, drv.executed)
+	require.Len(t, *rrw, 3)
+}
+
+func TestPlanner_WritePlanSequential(t *testing.T) {
+	d := &migrate.MemDir{}
+	pl := migrate.NewPlanner(nil, d, migrate.PlanFormat(migrate.SequentialFormatter(migrate.DefaultFormatter, 6)), migrate.PlanWithChecksum(false))
+	require.NoError(t, pl.WritePlan(&migrate.Plan{Name: "t1", Changes: []*migrate.Change{{Cmd: "CREATE TABLE t1(c int)"}}}))
+	require.NoError(t, pl.WritePlan(&migrate.Plan{Name: "t2", Changes: []*migrate.Change{{Cmd: "CREATE TABLE t2(c int)"}}}))
+	requireFileEqual(t, d, "000001_t1.sql", "CREATE TABLE t1(c int);\n")
+	requireFileEqual(t, d, "000002_t2.sql", "CREATE TABLE t2(c int);\n")
+
+	// Explicit versions are kept, and followed by the next sequential number.
+	require.NoError(t, pl.WritePlan(&migrate.Plan{Version: "10", Name: "t3", Changes: []*migrate.Change{{Cmd: "CREATE TABLE t3(c int)"}}}))
+	require.NoError(t, pl.WritePlan(&migrate.Plan{Name: "t4", Changes: []*migrate.Change{{Cmd: "CREATE TABLE t4(c int)"}}}))
+	requireFileEqual(t, d, "10_t3.sql", "CREATE TABLE t3(c int);\n")
+	requireFileEqual(t, d, "000011_t4.sql", "CREATE TABLE t4(c int);\n")
+
+	// Without a directory, the first version is used.
+	files, err := migrate.SequentialFormatter(migrate.DefaultFormatter, 3).Format(&migrate.Plan{Name: "t1"})
+	require.NoError(t, err)
+	require.Equal(t, "001_t1.sql", files[0].Name())
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	f, err := migrate.NewTemplateFormatter(
+		template.Must(template.New("").Funcs(migrate.TemplateFuncs()).Parse("{{ .Version }}_{{ .Name | upper }}.sql")),
+		template.Must(template.New("").Funcs(migrate.TemplateFuncs()).Parse(`{{ comment "Ticket: DB-42\n\nReviewed." }}{{ stmts . }}`)),
+	)
+	require.NoError(t, err)
+	plan := &migrate.Plan{
+		Version: "1",
+		Name:    "t1",
+		Changes: []*migrate.Change{
+			{Cmd: "CREATE TABLE t1(c int)", Comment: "create \"t1\" table"},
+			{Cmd: "CREATE INDEX i ON t1(c)"},
+		},
+	}
+	files, err := f.Format(plan)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "1_T1.sql", files[0].Name())
+	require.Equal(t, "-- Ticket: DB-42\n--\n-- Reviewed.\n-- Create \"t1\" table\nCREATE TABLE t1(c int);\nCREATE INDEX i ON t1(c);\n", string(files[0].Bytes()))
+
+	// Statements are formatted as in the DefaultFormatter.
+	files2, err := migrate.DefaultFormatter.Format(plan)
+	require.NoError(t, err)
+	require.Equal(t, string(files2[0].Bytes()), strings.SplitN(string(files[0].Bytes()), "Reviewed.\n", 2)[1])
+}
+
+func TestNewFormatter(t *testing.T) {
+	var (
+		t1   = schema.NewTable("t1")
+		t2   = schema.NewTable("t2")
+		plan = &migrate.Plan{
+			Version: "1",
+			Name:    "tables",
+			Changes: []*migrate.Change{
+				{Cmd: "CREATE TABLE t1(c int)", Comment: "create \"t1\" table", Source: &schema.AddTable{T: t1}},
+				{Cmd: "CREATE INDEX i ON t1(c)", Comment: "create index \"i\" to table: \"t1\"", Source: &schema.ModifyTable{T: t1}},
+				{Cmd: "CREATE TABLE t2(c int)", Comment: "create \"t2\" table", Source: &schema.AddTable{T: t2}},
+				{Cmd: "VACUUM"},
+			},
+		}
+	)
+	// Defaults to the DefaultFormatter format.
+	files, err := migrate.NewFormatter().Format(plan)
+	require.NoError(t, err)
+	expected, err := migrate.DefaultFormatter.Format(plan)
+	require.NoError(t, err)
+	require.Equal(t, expected, files)
+
+	files, err = migrate.NewFormatter(
+		migrate.FormatComments(false),
+		migrate.FormatHeader("atlas", "h1:abc"),
+		migrate.FormatGroupByTable(true),
+	).Format(plan)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "1_tables.sql", files[0].Name())
+	require.Equal(t, `-- Generated by atlas.
+-- Source hash: h1:abc
+
+-- Table "t1"
+CREATE TABLE t1(c int);
+CREATE INDEX i ON t1(c);
+
+-- Table "t2"
+CREATE TABLE t2(c int);
+VACUUM;
+`, string(files[0].Bytes()))
+
+	files, err = migrate.NewFormatter(migrate.FormatHeader("atlas", ""), migrate.FormatLineEnding("\r\n")).Format(plan)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(files[0].Bytes()), "-- Generated by atlas.\r\n\r\n-- Create \"t1\" table\r\nCREATE TABLE t1(c int);\r\n"))
+	require.NotContains(t, strings.ReplaceAll(string(files[0].Bytes()), "\r\n", ""), "\n")
+
+	// Annotations are rendered as comments, sorted by their keys.
+	plan.Changes[0].Annotations = map[string]string{"ticket": "DB-42", "owner": "payments"}
+	files, err = migrate.NewFormatter(migrate.FormatComments(false)).Format(plan)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(files[0].Bytes()), "-- owner: payments\n-- ticket: DB-42\nCREATE TABLE t1(c int);\n"))
+	expected, err = migrate.DefaultFormatter.Format(plan)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(expected[0].Bytes()), "-- Create \"t1\" table\n-- owner: payments\n-- ticket: DB-42\nCREATE TABLE t1(c int);\n"))
+}
+
 func TestPlanner_WriteCheckpoint(t *testing.T) {
 	p := t.TempDir()
 	d, err := migrate.NewLocalDir(p)
@@ -165,6 +369,38 @@ func TestPlanner_PlanSchema(t *testing.T) {
 	require.Nil(t, plan)
 }
 
+func TestPlanner_PlanNormalized(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		drv  = &mockDriver{realm: *schema.NewRealm(schema.New("test"))}
+		norm = &mockNormalizer{}
+	)
+	d, err := migrate.NewLocalDir(t.TempDir())
+	require.NoError(t, err)
+	desired := func() *schema.Realm {
+		return schema.NewRealm(schema.New("test").AddTables(schema.NewTable("t").AddColumns(schema.NewColumn("c").SetType(&schema.IntegerType{T: "integer"}))))
+	}
+
+	pl := migrate.NewPlanner(drv, d, migrate.PlanWithNormalizer(norm))
+	_, err = pl.Plan(ctx, "realm", migrate.Realm(desired()))
+	require.ErrorIs(t, err, migrate.ErrNoPlan)
+	require.Equal(t, []string{"realm"}, norm.calls)
+	_, err = pl.PlanSchema(ctx, "schema", migrate.Realm(desired()))
+	require.ErrorIs(t, err, migrate.ErrNoPlan)
+	require.Equal(t, []string{"realm", "schema test"}, norm.calls)
+
+	r, err := migrate.NormalizedSchema(norm, migrate.Realm(desired())).ReadState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "int", r.Schemas[0].Tables[0].Columns[0].Type.Type.(*schema.IntegerType).T)
+	require.Equal(t, r, r.Schemas[0].Realm)
+
+	norm.err = errors.New("dev database is not clean")
+	_, err = pl.Plan(ctx, "realm", migrate.Realm(desired()))
+	require.EqualError(t, err, "sql/migrate: normalize realm: dev database is not clean")
+	_, err = pl.PlanSchema(ctx, "schema", migrate.Realm(desired()))
+	require.EqualError(t, err, `sql/migrate: normalize schema "test": dev database is not clean`)
+}
+
 func TestPlanner_Checkpoint(t *testing.T) {
 	var (
 		drv = &mockDriver{}
@@ -215,6 +451,128 @@ func TestPlanner_CheckpointSchema(t *testing.T) {
 	require.Equal(t, &migrate.Plan{Name: "empty"}, plan)
 }
 
+func TestPlanner_Squash(t *testing.T) {
+	var (
+		drv = &mockDriver{}
+		ctx = context.Background()
+	)
+	d, err := migrate.NewLocalDir(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, d.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);\n")))
+	require.NoError(t, d.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);\n")))
+	require.NoError(t, d.WriteFile("3_t2_c2.sql", []byte("ALTER TABLE t2 ADD c2 int;\n")))
+	require.NoError(t, d.WriteFile("4_t3.sql", []byte("CREATE TABLE t3(c int);\n")))
+	require.NoError(t, d.WriteFile(migrate.DownFileName("2_t2.sql"), []byte("DROP TABLE t2;\n")))
+	require.NoError(t, d.WriteFile(migrate.DownFileName("3_t2_c2.sql"), []byte("ALTER TABLE t2 DROP c2;\n")))
+	require.NoError(t, migrate.Rehash(d))
+
+	pl := migrate.NewPlanner(drv, d)
+	require.EqualError(t, pl.Squash(ctx, "0", "3"), `sql/migrate: squash: migration with version "0" not found`)
+	require.EqualError(t, pl.Squash(ctx, "3", "2"), `sql/migrate: squash: version "3" is greater than version "2"`)
+	require.EqualError(t, migrate.NewPlanner(drv, struct{ migrate.Dir }{d}).Squash(ctx, "2", "3"), `sql/migrate: squash: removing files is not supported by struct { migrate.Dir }`)
+
+	drv.changes = []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t2").AddColumns(schema.NewIntColumn("c", "int"), schema.NewIntColumn("c2", "int"))},
+	}
+	drv.plan = &migrate.Plan{
+		Name: "t2_c2",
+		Changes: []*migrate.Change{
+			{Cmd: "CREATE TABLE t2(c int, c2 int)"},
+		},
+	}
+	require.NoError(t, pl.Squash(ctx, "2", "3"))
+	// Files are replayed before and after the squashed range.
+	require.Equal(t, []string{
+		"CREATE TABLE t1(c int);",
+		"CREATE TABLE t1(c int);", "CREATE TABLE t2(c int);", "ALTER TABLE t2 ADD c2 int;",
+	}, drv.executed)
+	files, err := d.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.Equal(t, "1_t1.sql", files[0].Name())
+	require.Equal(t, "3_t2_c2.sql", files[1].Name())
+	require.Equal(t, "4_t3.sql", files[2].Name())
+	requireFileEqual(t, d, "3_t2_c2.sql", "CREATE TABLE t2(c int, c2 int);\n")
+	require.NoError(t, migrate.Validate(d))
+	// Down files of the squashed files are removed.
+	down, err := fs.Glob(d, "down/*")
+	require.NoError(t, err)
+	require.Empty(t, down)
+
+	// Checkpoint files cannot be squashed.
+	require.NoError(t, d.WriteCheckpoint("5_checkpoint.sql", "", []byte("CREATE TABLE t1(c int);\n")))
+	require.NoError(t, migrate.Rehash(d))
+	require.EqualError(t, pl.Squash(ctx, "4", "5"), `sql/migrate: squash: checkpoint file "5_checkpoint.sql" cannot be squashed`)
+}
+
+func TestPlanDialects(t *testing.T) {
+	var (
+		users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+		pets  = schema.NewTable("pets").AddColumns(schema.NewEnumColumn("kind", schema.EnumValues("cat", "dog")))
+	)
+	planners := map[string]migrate.PlanApplier{
+		"b": planFunc(func(changes []schema.Change) (*migrate.Plan, error) {
+			plan := &migrate.Plan{}
+			for _, c := range changes {
+				plan.Changes = append(plan.Changes, &migrate.Change{Cmd: "CREATE TABLE " + c.(*schema.AddTable).T.Name})
+			}
+			return plan, nil
+		}),
+		// Enums are not supported by dialect "a".
+		"a": planFunc(func(changes []schema.Change) (*migrate.Plan, error) {
+			for _, c := range changes {
+				if c.(*schema.AddTable).T.Name == "pets" {
+					return nil, errors.New("enum types are not supported")
+				}
+			}
+			return &migrate.Plan{}, nil
+		}),
+		// Dialect "c" cannot plan more than one change.
+		"c": planFunc(func(changes []schema.Change) (*migrate.Plan, error) {
+			if len(changes) > 1 {
+				return nil, errors.New("too many changes")
+			}
+			return &migrate.Plan{}, nil
+		}),
+	}
+	changes := []schema.Change{&schema.AddTable{T: users}, &schema.AddTable{T: pets}}
+	plans, err := migrate.PlanDialects(context.Background(), "init", changes, planners)
+	require.NoError(t, err)
+	require.Len(t, plans, 3)
+
+	require.Equal(t, "a", plans[0].Dialect)
+	require.Nil(t, plans[0].Plan)
+	require.Len(t, plans[0].Incompatible, 1)
+	require.Equal(t, changes[1], plans[0].Incompatible[0].Change)
+	require.EqualError(t, plans[0].Incompatible[0].Err, "enum types are not supported")
+
+	require.Equal(t, "b", plans[1].Dialect)
+	require.Empty(t, plans[1].Incompatible)
+	require.Len(t, plans[1].Plan.Changes, 2)
+	require.Equal(t, "CREATE TABLE pets", plans[1].Plan.Changes[1].Cmd)
+
+	require.Equal(t, "c", plans[2].Dialect)
+	require.Nil(t, plans[2].Plan)
+	require.Len(t, plans[2].Incompatible, 1)
+	require.Nil(t, plans[2].Incompatible[0].Change)
+	require.EqualError(t, plans[2].Incompatible[0].Err, "too many changes")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = migrate.PlanDialects(ctx, "init", changes, planners)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+type planFunc func([]schema.Change) (*migrate.Plan, error)
+
+func (f planFunc) PlanChanges(_ context.Context, _ string, changes []schema.Change, _ ...migrate.PlanOption) (*migrate.Plan, error) {
+	return f(changes)
+}
+
+func (f planFunc) ApplyChanges(context.Context, []schema.Change, ...migrate.PlanOption) error {
+	return errors.New("not implemented")
+}
+
 func TestExecutor_Replay(t *testing.T) {
 	ctx := context.Background()
 	d, err := migrate.NewLocalDir(filepath.FromSlash("testdata/migrate"))
@@ -261,6 +619,67 @@ func TestExecutor_Replay(t *testing.T) {
 	require.ErrorAs(t, err, new(*migrate.NotCleanError))
 }
 
+func TestReplayer(t *testing.T) {
+	ctx := context.Background()
+	dir, err := migrate.NewLocalDir(filepath.FromSlash("testdata/migrate/sub"))
+	require.NoError(t, err)
+	drv := &mockDriver{realm: schema.Realm{Schemas: []*schema.Schema{schema.New("main")}}}
+	r := migrate.NewReplayer(drv, dir, migrate.ReplayToVersion("1.a"))
+	realm, err := r.ReadState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &drv.realm, realm)
+	require.Equal(t, []string{"CREATE TABLE t_sub(c int);", "ALTER TABLE t_sub ADD c1 int;"}, drv.executed)
+
+	// Schema scope.
+	drv.executed = nil
+	realm, err = migrate.StateReaderFunc(r.ReadSchemaState).ReadState(ctx)
+	require.NoError(t, err)
+	require.Len(t, realm.Schemas, 1)
+	require.Equal(t, "main", realm.Schemas[0].Name)
+	require.Len(t, drv.executed, 2)
+
+	// Replaying requires snapshots.
+	_, err = migrate.NewReplayer(struct{ migrate.Driver }{drv}, dir).ReadState(ctx)
+	require.EqualError(t, err, "sql/migrate: replay: driver struct { migrate.Driver } does not support snapshots")
+}
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	dir, err := migrate.NewLocalDir(filepath.FromSlash("testdata/migrate/sub"))
+	require.NoError(t, err)
+	drv := &mockDriver{
+		realm:   schema.Realm{Schemas: []*schema.Schema{schema.New("main")}},
+		changes: []schema.Change{&schema.AddTable{T: schema.NewTable("t")}},
+	}
+	desired := schema.NewRealm(schema.New("public").AddTables(schema.NewTable("t")))
+	changes, err := migrate.Diff(ctx, drv, migrate.NewReplayer(drv, dir), migrate.Realm(desired))
+	require.NoError(t, err)
+	require.Equal(t, drv.changes, changes)
+	require.Len(t, drv.executed, 5, "directory was replayed")
+
+	// Schema names are ignored in schema scope.
+	d := &schemaDiffer{}
+	_, err = migrate.DiffSchema(ctx, d, migrate.StateReaderFunc(migrate.NewReplayer(drv, dir).ReadSchemaState), migrate.Realm(desired))
+	require.NoError(t, err)
+	require.Equal(t, "public", d.from.Name)
+	require.Equal(t, "public", d.to.Name)
+	require.Equal(t, "main", drv.realm.Schemas[0].Name)
+	_, err = migrate.DiffSchema(ctx, d, migrate.Realm(schema.NewRealm()), migrate.Realm(desired))
+	require.EqualError(t, err, "no schema was found in current state")
+	_, err = migrate.DiffSchema(ctx, d, migrate.Realm(desired), migrate.Realm(schema.NewRealm(schema.New("a"), schema.New("b"))))
+	require.EqualError(t, err, "2 schemas were found in desired state; expect 1")
+}
+
+type schemaDiffer struct {
+	schema.Differ
+	from, to *schema.Schema
+}
+
+func (d *schemaDiffer) SchemaDiff(from, to *schema.Schema, _ ...schema.DiffOption) ([]schema.Change, error) {
+	d.from, d.to = from, to
+	return nil, nil
+}
+
 func TestExecutor_Pending(t *testing.T) {
 	var (
 		drv  = &mockDriver{}
@@ -426,7 +845,7 @@ func TestExecutor(t *testing.T) {
 		"CREATE TABLE t_sub(c int);", "ALTER TABLE t_sub ADD c1 int;", "ALTER TABLE t_sub ADD c2 int;",
 	})
 	requireEqualRevisions(t, []*migrate.Revision{rev1, rev2}, *rrw)
-	require.Len(t, *log, 7)
+	require.Len(t, *log, 12)
 	require.IsType(t, migrate.LogExecution{}, (*log)[0])
 	require.Equal(t, "2.10.x-20", (*log)[0].(migrate.LogExecution).To)
 	require.Len(t, (*log)[0].(migrate.LogExecution).Files, 2)
@@ -434,10 +853,17 @@ func TestExecutor(t *testing.T) {
 	require.Equal(t, "2.10.x-20_description.sql", (*log)[0].(migrate.LogExecution).Files[1].Name())
 	require.IsType(t, migrate.LogFile{}, (*log)[1])
 	require.Equal(t, migrate.LogStmt{SQL: "CREATE TABLE t_sub(c int);"}, (*log)[2])
-	require.Equal(t, migrate.LogStmt{SQL: "ALTER TABLE t_sub ADD c1 int;"}, (*log)[3])
-	require.IsType(t, migrate.LogFile{}, (*log)[4])
-	require.Equal(t, migrate.LogStmt{SQL: "ALTER TABLE t_sub ADD c2 int;"}, (*log)[5])
-	require.Equal(t, migrate.LogDone{}, (*log)[6])
+	require.Equal(t, "CREATE TABLE t_sub(c int);", (*log)[3].(migrate.LogStmtDone).SQL)
+	require.Equal(t, int64(-1), (*log)[3].(migrate.LogStmtDone).RowsAffected, "rows affected are not reported by the driver")
+	require.Equal(t, migrate.LogStmt{SQL: "ALTER TABLE t_sub ADD c1 int;"}, (*log)[4])
+	require.IsType(t, migrate.LogStmtDone{}, (*log)[5])
+	require.Equal(t, "1.a_sub.up.sql", (*log)[6].(migrate.LogFileDone).File.Name())
+	require.IsType(t, migrate.LogFile{}, (*log)[7])
+	require.Equal(t, migrate.LogStmt{SQL: "ALTER TABLE t_sub ADD c2 int;"}, (*log)[8])
+	require.IsType(t, migrate.LogStmtDone{}, (*log)[9])
+	require.Equal(t, "2.10.x-20", (*log)[9].(migrate.LogStmtDone).File.Version())
+	require.Equal(t, "2.10.x-20_description.sql", (*log)[10].(migrate.LogFileDone).File.Name())
+	require.Equal(t, migrate.LogDone{}, (*log)[11])
 
 	// Partly is pending.
 	p, err := ex.Pending(context.Background())
@@ -501,12 +927,21 @@ func TestExecutor(t *testing.T) {
 	h := revs[len(revs)-1].PartialHashes[0]
 	revs[len(revs)-1].PartialHashes[0] += h
 	require.ErrorAs(t, ex.ExecuteN(context.Background(), 1), &migrate.HistoryChangedError{})
+	// Same, if the hashes of the applied statements are missing.
+	revs[len(revs)-1].PartialHashes = nil
+	require.ErrorAs(t, ex.ExecuteN(context.Background(), 1), &migrate.HistoryChangedError{})
 
 	// Re-attempting to migrate will pick up where the execution was left off.
-	revs[len(revs)-1].PartialHashes[0] = h
+	revs[len(revs)-1].PartialHashes = []string{h}
 	*drv = mockDriver{}
 	require.NoError(t, ex.ExecuteN(context.Background(), 1))
 	require.Equal(t, []string{"ALTER TABLE t_sub ADD c4 int;"}, drv.executed)
+	revs, err = rrw.ReadRevisions(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, revs[len(revs)-1].Applied)
+	require.Len(t, revs[len(revs)-1].PartialHashes, 2)
+	require.Empty(t, revs[len(revs)-1].Error, "error of previous attempt should be cleared")
+	require.Empty(t, revs[len(revs)-1].ErrorStmt)
 
 	// Everything is applied.
 	require.ErrorIs(t, ex.ExecuteN(context.Background(), 0), migrate.ErrNoPendingFiles)
@@ -517,6 +952,79 @@ func TestExecutor(t *testing.T) {
 	require.EqualError(t, ex.ExecuteTo(context.Background(), ""), "sql/migrate: execute: migration with version \"\" not found")
 	require.NoError(t, ex.ExecuteTo(context.Background(), "2.10.x-20"))
 	requireEqualRevisions(t, []*migrate.Revision{rev1, rev2}, *rrw)
+	// Version must be ahead of the database state.
+	require.EqualError(t, ex.ExecuteTo(context.Background(), "1.a"), "sql/migrate: execute: migration with version \"1.a\" is already applied")
+	require.EqualError(t, ex.ExecuteTo(context.Background(), "2"), "sql/migrate: execute: migration with version \"2\" not found")
+	require.NoError(t, ex.ExecuteTo(context.Background(), "3"))
+	require.ErrorIs(t, ex.ExecuteTo(context.Background(), "3"), migrate.ErrNoPendingFiles)
+	require.EqualError(t, ex.ExecuteTo(context.Background(), "4"), "sql/migrate: execute: migration with version \"4\" not found")
+}
+
+func TestExecutor_ExecOrder(t *testing.T) {
+	_, err := migrate.NewExecutor(&mockDriver{}, migrate.OpenMemDir(""), &mockRevisionReadWriter{}, migrate.WithExecOrder("random"))
+	require.EqualError(t, err, `sql/migrate: execute: unknown execution order "random"`)
+
+	var (
+		ctx = context.Background()
+		dir = &migrate.MemDir{}
+		rev = func(v string, applied, total int) *migrate.Revision {
+			return &migrate.Revision{Version: v, Description: "t" + v, Applied: applied, Total: total}
+		}
+	)
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);\nCREATE INDEX i2 ON t2(c);")))
+	require.NoError(t, dir.WriteFile("3_t3.sql", []byte("CREATE TABLE t3(c int);")))
+	require.NoError(t, dir.WriteFile("4_t4.sql", []byte("CREATE TABLE t4(c int);")))
+	require.NoError(t, migrate.Rehash(dir))
+
+	// File 2 was merged after 3 was applied.
+	for _, tt := range []struct {
+		opts    []migrate.ExecutorOption
+		wantErr string
+	}{
+		{},
+		{opts: []migrate.ExecutorOption{migrate.WithExecOrder(migrate.ExecOrderLinearSkip)}},
+		{
+			opts:    []migrate.ExecutorOption{migrate.WithExecOrder(migrate.ExecOrderLinear)},
+			wantErr: `sql/migrate: execute: non-linear history: files ["2_t2.sql"] were not applied, but the database is at version "3"`,
+		},
+	} {
+		drv, rrw := &mockDriver{}, &mockRevisionReadWriter{rev("1", 1, 1), rev("3", 1, 1)}
+		ex, err := migrate.NewExecutor(drv, dir, rrw, tt.opts...)
+		require.NoError(t, err)
+		err = ex.ExecuteN(ctx, 0)
+		if tt.wantErr != "" {
+			require.ErrorAs(t, err, &migrate.HistoryNonLinearError{})
+			require.EqualError(t, err, tt.wantErr)
+			require.Empty(t, drv.executed)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, []string{"CREATE TABLE t4(c int);"}, drv.executed)
+	}
+
+	// Out-of-order files are executed first, and partially applied ones are continued.
+	drv, rrw := &mockDriver{}, &mockRevisionReadWriter{rev("1", 1, 1), rev("2", 1, 2), rev("3", 1, 1)}
+	ex, err := migrate.NewExecutor(drv, dir, rrw, migrate.WithExecOrder(migrate.ExecOrderNonLinear))
+	require.NoError(t, err)
+	pending, err := ex.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, "2_t2.sql", pending[0].Name())
+	require.Equal(t, "4_t4.sql", pending[1].Name())
+	h := sha256.Sum256([]byte("CREATE TABLE t2(c int);"))
+	(*rrw)[1].PartialHashes = []string{"h1:" + base64.StdEncoding.EncodeToString(h[:])}
+	require.NoError(t, ex.ExecuteN(ctx, 0))
+	require.Equal(t, []string{"CREATE INDEX i2 ON t2(c);", "CREATE TABLE t4(c int);"}, drv.executed)
+	require.Len(t, *rrw, 4)
+	require.ErrorIs(t, ex.ExecuteN(ctx, 0), migrate.ErrNoPendingFiles)
+
+	// Files covered by a baseline are not executed.
+	drv, rrw = &mockDriver{}, &mockRevisionReadWriter{{Version: "2", Type: migrate.RevisionTypeBaseline}, rev("4", 1, 1)}
+	ex, err = migrate.NewExecutor(drv, dir, rrw, migrate.WithExecOrder(migrate.ExecOrderNonLinear))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(ctx, 0))
+	require.Equal(t, []string{"CREATE TABLE t3(c int);"}, drv.executed)
 }
 
 func TestExecutor_Baseline(t *testing.T) {
@@ -562,9 +1070,624 @@ func TestExecutor_Baseline(t *testing.T) {
 	require.Equal(t, "3", rrw[0].Version)
 	require.Equal(t, "partly", rrw[0].Description)
 	require.Equal(t, migrate.RevisionTypeBaseline, rrw[0].Type)
+
+	// Only the files after the baseline are executed, and the
+	// baseline is ignored once the database has revisions.
+	rrw, *drv = mockRevisionReadWriter{}, mockDriver{dirty: true}
+	ex, err = migrate.NewExecutor(drv, dir, &rrw, migrate.WithBaselineVersion("1.a"))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Equal(t, []string{"ALTER TABLE t_sub ADD c2 int;"}, drv.executed)
+	require.Len(t, rrw, 2)
+	require.Equal(t, migrate.RevisionTypeBaseline, rrw[0].Type)
+	require.Equal(t, migrate.RevisionTypeExecute, rrw[1].Type)
+	files, err = ex.Pending(context.Background())
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "3_partly.sql", files[0].Name())
+	require.Len(t, rrw, 2)
+}
+
+func TestExecutor_DryRun(t *testing.T) {
+	var (
+		rrw mockRevisionReadWriter
+		drv = &mockDriver{}
+		log = &mockLogger{}
+	)
+	dir, err := migrate.NewLocalDir(filepath.Join("testdata/migrate", "sub"))
+	require.NoError(t, err)
+	ex, err := migrate.NewExecutor(drv, dir, &rrw, migrate.WithLogger(log), migrate.WithDryRun(true))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 2))
+	require.Empty(t, drv.executed)
+	require.Empty(t, rrw)
+	require.Len(t, *log, 7)
+	require.Equal(t, migrate.LogStmt{SQL: "CREATE TABLE t_sub(c int);"}, (*log)[2])
+	require.Equal(t, migrate.LogStmt{SQL: "ALTER TABLE t_sub ADD c1 int;"}, (*log)[3])
+	require.Equal(t, migrate.LogStmt{SQL: "ALTER TABLE t_sub ADD c2 int;"}, (*log)[5])
+
+	// Baseline revisions are not written as well.
+	drv.dirty = true
+	ex, err = migrate.NewExecutor(drv, dir, &rrw, migrate.WithDryRun(true), migrate.WithBaselineVersion("1.a"))
+	require.NoError(t, err)
+	files, err := ex.Pending(context.Background())
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Empty(t, rrw)
+}
+
+func TestExecutor_Status(t *testing.T) {
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);")))
+	require.NoError(t, dir.WriteFile("3_t3.sql", []byte("CREATE TABLE t3(c int);")))
+	require.NoError(t, migrate.Rehash(dir))
+	sums, err := dir.Checksum()
+	require.NoError(t, err)
+	h1, err := sums.SumByName("1_t1.sql")
+	require.NoError(t, err)
+	files, err := dir.Files()
+	require.NoError(t, err)
+	stmts, err := files[0].Stmts()
+	require.NoError(t, err)
+	s1 := sha256.Sum256([]byte(stmts[0]))
+	p1 := "h1:" + base64.StdEncoding.EncodeToString(s1[:])
+
+	// Baseline revisions are not written.
+	var (
+		rrw mockRevisionReadWriter
+		drv = &mockDriver{dirty: true}
+	)
+	ex, err := migrate.NewExecutor(drv, dir, &rrw, migrate.WithBaselineVersion("1"))
+	require.NoError(t, err)
+	s, err := ex.Status(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, rrw)
+	require.Empty(t, s.Current)
+	require.Len(t, s.Pending, 2)
+
+	rrw = mockRevisionReadWriter{
+		{Version: "0", Description: "t0", Type: migrate.RevisionTypeExecute, Applied: 1, Total: 1},
+		{Version: "1", Description: "t1", Type: migrate.RevisionTypeExecute, Applied: 1, Total: 1, Hash: h1, PartialHashes: []string{p1}},
+		{Version: "2", Description: "t2", Type: migrate.RevisionTypeExecute, Applied: 1, Total: 1, Hash: "edited", PartialHashes: []string{"h1:edited"}},
+	}
+	ex, err = migrate.NewExecutor(drv, dir, &rrw)
+	require.NoError(t, err)
+	s, err = ex.Status(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "2", s.Current)
+	require.Len(t, s.Applied, 3)
+	require.Len(t, s.Pending, 1)
+	require.Equal(t, "3_t3.sql", s.Pending[0].Name())
+	require.Equal(t, []*migrate.Drift{
+		{Version: "0", Description: "t0", Kind: migrate.DriftMissingFile},
+		{Version: "2", Description: "t2", Kind: migrate.DriftEditedFile},
+	}, s.Drift)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"Current":"2"`)
+	require.Contains(t, string(b), `"Pending":["3_t3.sql"]`)
+	require.Contains(t, string(b), `{"Version":"0","Description":"t0","Kind":"missing_file"}`)
+
+	// Files that were added before applied files do not change the
+	// hashes of the applied files, and they are not reported as drift.
+	dir2 := migrate.OpenMemDir(t.Name() + "_inserted")
+	t.Cleanup(func() { require.NoError(t, dir2.Close()) })
+	require.NoError(t, dir2.WriteFile("1_a.sql", []byte("CREATE TABLE a(c int);")))
+	require.NoError(t, dir2.WriteFile("3_c.sql", []byte("CREATE TABLE c(c int);")))
+	require.NoError(t, migrate.Rehash(dir2))
+	rrw = mockRevisionReadWriter{}
+	ex, err = migrate.NewExecutor(&mockDriver{}, dir2, &rrw, migrate.WithExecOrder(migrate.ExecOrderNonLinear))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.NoError(t, dir2.WriteFile("2_b.sql", []byte("CREATE TABLE b(c int);")))
+	require.NoError(t, migrate.Rehash(dir2))
+	s, err = ex.Status(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, s.Drift)
+	require.Len(t, s.Pending, 1)
+	require.Equal(t, "2_b.sql", s.Pending[0].Name())
+
+	// Editing a file is reported only for this file.
+	require.NoError(t, dir2.WriteFile("1_a.sql", []byte("CREATE TABLE a(c bigint);")))
+	require.NoError(t, migrate.Rehash(dir2))
+	s, err = ex.Status(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []*migrate.Drift{{Version: "1", Description: "a", Kind: migrate.DriftEditedFile}}, s.Drift)
+}
+
+func TestExecutor_Repair(t *testing.T) {
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);\nCREATE TABLE t3(c int);\nCREATE TABLE t4(c int);")))
+	require.NoError(t, migrate.Rehash(dir))
+	var (
+		ctx = context.Background()
+		rrw mockRevisionReadWriter
+		drv = &mockDriver{}
+	)
+	ex, err := migrate.NewExecutor(drv, dir, &rrw)
+	require.NoError(t, err)
+	r, err := ex.Dirty(ctx)
+	require.NoError(t, err)
+	require.Nil(t, r)
+	require.EqualError(t, ex.MarkApplied(ctx, "1"), `sql/migrate: repair: revision with version "1" not found`)
+
+	// Fail on the third statement.
+	drv.failOn(3, errors.New("error"))
+	require.Error(t, ex.ExecuteN(ctx, 0))
+	r, err = ex.Dirty(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "2", r.Version)
+	require.Equal(t, 1, r.Applied)
+	require.EqualError(t, ex.MarkApplied(ctx, "1"), `sql/migrate: repair: revision "1" is not the last revision`)
+	require.EqualError(t, ex.RetryFrom(ctx, "2", 2), `sql/migrate: repair: statement 2 is out of range [0, 1]`)
+
+	// Retry from the first statement of the file.
+	*drv = mockDriver{}
+	require.NoError(t, ex.RetryFrom(ctx, "2", 0))
+	require.Equal(t, []string{"CREATE TABLE t2(c int);", "CREATE TABLE t3(c int);", "CREATE TABLE t4(c int);"}, drv.executed)
+	r, err = ex.Dirty(ctx)
+	require.NoError(t, err)
+	require.Nil(t, r)
+	require.ErrorIs(t, ex.MarkApplied(ctx, "2"), migrate.ErrNotDirty)
+	require.ErrorIs(t, ex.DeleteDirty(ctx, "2"), migrate.ErrNotDirty)
+
+	// Mark the remaining statements as applied.
+	rrw[1].Applied, rrw[1].Error = 2, "error"
+	require.NoError(t, ex.MarkApplied(ctx, "2"))
+	require.Equal(t, 3, rrw[1].Applied)
+	require.Empty(t, rrw[1].Error)
+	require.True(t, rrw[1].Type.Has(migrate.RevisionTypeResolved))
+
+	// Delete the dirty revision.
+	rrw[1].Applied = 2
+	require.NoError(t, ex.DeleteDirty(ctx, "2"))
+	require.Len(t, rrw, 1)
+	files, err := ex.Pending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "2_t2.sql", files[0].Name())
+}
+
+func TestExecutor_Hooks(t *testing.T) {
+	var (
+		calls []string
+		rrw   mockRevisionReadWriter
+		drv   = &mockDriver{}
+		hooks = migrate.ExecHooks{
+			BeforeFile: func(_ context.Context, f migrate.File) error {
+				calls = append(calls, "before file "+f.Version())
+				return nil
+			},
+			AfterFile: func(_ context.Context, f migrate.File) error {
+				calls = append(calls, "after file "+f.Version())
+				return nil
+			},
+			BeforeStmt: func(_ context.Context, _ migrate.File, c *migrate.Change) error {
+				calls = append(calls, "before "+c.Cmd)
+				return nil
+			},
+			AfterStmt: func(_ context.Context, _ migrate.File, c *migrate.Change) error {
+				calls = append(calls, "after "+c.Cmd)
+				return nil
+			},
+		}
+	)
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);\nCREATE TABLE t3(c int);")))
+	require.NoError(t, migrate.Rehash(dir))
+
+	ex, err := migrate.NewExecutor(drv, dir, &rrw, migrate.WithHooks(hooks, migrate.ExecHooks{}))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Equal(t, []string{
+		"before file 1",
+		"before CREATE TABLE t1(c int);",
+		"after CREATE TABLE t1(c int);",
+		"after file 1",
+	}, calls)
+
+	// Hook errors abort the execution.
+	calls = nil
+	ex, err = migrate.NewExecutor(drv, dir, &rrw, migrate.WithHooks(hooks, migrate.ExecHooks{
+		BeforeStmt: func(_ context.Context, _ migrate.File, c *migrate.Change) error {
+			if c.Cmd == "CREATE TABLE t3(c int);" {
+				return errors.New("invalid statement")
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+	err = ex.ExecuteN(context.Background(), 0)
+	require.EqualError(t, err, `sql/migrate: execute: before statement hook of "2_t2.sql": invalid statement`)
+	require.Equal(t, []string{
+		"before file 2",
+		"before CREATE TABLE t2(c int);",
+		"after CREATE TABLE t2(c int);",
+		"before CREATE TABLE t3(c int);",
+	}, calls)
+	require.Equal(t, []string{"CREATE TABLE t1(c int);", "CREATE TABLE t2(c int);"}, drv.executed)
+	require.Equal(t, 1, rrw[1].Applied)
+	require.Equal(t, "CREATE TABLE t3(c int);", rrw[1].ErrorStmt)
+	require.Equal(t, err.Error(), rrw[1].Error)
+
+	// Hooks are not called in dry-run mode.
+	calls = nil
+	ex, err = migrate.NewExecutor(drv, dir, &rrw, migrate.WithHooks(hooks), migrate.WithDryRun(true))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Empty(t, calls)
+}
+
+func TestExecutor_ExecError(t *testing.T) {
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t.sql", []byte("CREATE TABLE t1(c int);\n\n-- Create t2.\nCREATE TABLE t2(\n  c int\n);\nCREATE TABLE t3(c int);\n")))
+	require.NoError(t, migrate.Rehash(dir))
+	var (
+		rrw mockRevisionReadWriter
+		drv = &mockDriver{}
+	)
+	drv.failOn(2, errors.New("table exists"))
+	ex, err := migrate.NewExecutor(drv, dir, &rrw)
+	require.NoError(t, err)
+	err = ex.ExecuteN(context.Background(), 0)
+	require.EqualError(t, err, `sql/migrate: execute: executing statement "CREATE TABLE t2(\n  c int\n);" from version "1": table exists`)
+	var ee migrate.ExecError
+	require.ErrorAs(t, err, &ee)
+	require.Equal(t, "1_t.sql", ee.File)
+	require.Equal(t, "1", ee.Version)
+	require.Equal(t, 1, ee.Stmt)
+	require.Equal(t, 39, ee.Pos)
+	require.Equal(t, 4, ee.Line)
+	require.Equal(t, "CREATE TABLE t2(\n  c int\n);", ee.SQL)
+	require.EqualError(t, errors.Unwrap(err), "table exists")
+}
+
+func TestExecuteTargets(t *testing.T) {
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);")))
+	require.NoError(t, migrate.Rehash(dir))
+
+	var (
+		drvs    = make([]*mockDriver, 4)
+		rrws    = make([]*mockRevisionReadWriter, 4)
+		targets = make([]*migrate.Target, 4)
+	)
+	for i := range targets {
+		drvs[i], rrws[i] = &mockDriver{}, &mockRevisionReadWriter{}
+		targets[i] = &migrate.Target{Name: fmt.Sprintf("tenant_%d", i), Driver: drvs[i], RevisionReadWriter: rrws[i]}
+	}
+	// One target is already up-to-date, and another one fails.
+	*rrws[1] = mockRevisionReadWriter{
+		{Version: "1", Description: "t1", Type: migrate.RevisionTypeExecute, Applied: 1, Total: 1},
+		{Version: "2", Description: "t2", Type: migrate.RevisionTypeExecute, Applied: 1, Total: 1},
+	}
+	drvs[2].failOn(2, errors.New("table exists"))
+	err := migrate.ExecuteTargets(context.Background(), dir, targets, 2, migrate.WithOperatorVersion("op"))
+	require.EqualError(t, err, `sql/migrate: execute: 1 target(s) failed: target "tenant_2": sql/migrate: execute: executing statement "CREATE TABLE t2(c int);" from version "2": table exists`)
+	var terr migrate.TargetsError
+	require.ErrorAs(t, err, &terr)
+	require.Len(t, terr, 1)
+	require.Equal(t, "tenant_2", terr[0].Target)
+	require.ErrorAs(t, terr[0], &migrate.ExecError{})
+
+	for _, i := range []int{0, 3} {
+		require.Equal(t, []string{"CREATE TABLE t1(c int);", "CREATE TABLE t2(c int);"}, drvs[i].executed)
+		require.Len(t, *rrws[i], 2)
+		require.Equal(t, "op", (*rrws[i])[1].OperatorVersion)
+	}
+	require.Empty(t, drvs[1].executed)
+	require.Equal(t, []string{"CREATE TABLE t1(c int);"}, drvs[2].executed)
+
+	// Retrying continues the failed target only.
+	*drvs[2] = mockDriver{}
+	require.NoError(t, migrate.ExecuteTargets(context.Background(), dir, targets, 0))
+	require.Equal(t, []string{"CREATE TABLE t2(c int);"}, drvs[2].executed)
+}
+
+func TestExecutor_Lock(t *testing.T) {
+	_, err := migrate.NewExecutor(&mockDriver{}, migrate.OpenMemDir(""), &mockRevisionReadWriter{}, migrate.WithLock("atlas", time.Second))
+	require.EqualError(t, err, "sql/migrate: execute: driver *migrate_test.mockDriver does not support locking")
+
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, migrate.Rehash(dir))
+	var (
+		rrw mockRevisionReadWriter
+		drv = &lockDriver{mockDriver: &mockDriver{}}
+	)
+	ex, err := migrate.NewExecutor(drv, dir, &rrw, migrate.WithLock("atlas", time.Second))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Equal(t, []string{"lock atlas 1s", "unlock atlas"}, drv.ops)
+	require.Equal(t, []string{"CREATE TABLE t1(c int);"}, drv.executed)
+
+	// Execution fails if the lock is held by another session.
+	drv.ops, drv.locked = nil, true
+	err = ex.Down(context.Background(), 1)
+	require.ErrorIs(t, err, schema.ErrLocked)
+	require.EqualError(t, err, `sql/migrate: execute: acquire lock "atlas": sql/schema: lock is held by other session`)
+	require.Len(t, rrw, 1)
+}
+
+func TestExecutor_Timeouts(t *testing.T) {
+	_, err := migrate.NewExecutor(&mockDriver{}, migrate.OpenMemDir(""), &mockRevisionReadWriter{}, migrate.WithStmtTimeout(-time.Second))
+	require.EqualError(t, err, "sql/migrate: execute: negative statement timeout -1s")
+
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);\nSLEEP;")))
+	require.NoError(t, migrate.Rehash(dir))
+	var (
+		rrw mockRevisionReadWriter
+		drv = &timeoutDriver{mockDriver: &mockDriver{}}
+	)
+	ex, err := migrate.NewExecutor(drv, dir, &rrw, migrate.WithStmtTimeout(10*time.Millisecond), migrate.WithLockWaitTimeout(time.Second))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Equal(t, []string{"10ms 1s", "restore"}, drv.timeouts)
+
+	// Statements that exceed the timeout are canceled,
+	// and the session timeouts are restored afterwards.
+	err = ex.ExecuteN(context.Background(), 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	var exErr migrate.ExecError
+	require.ErrorAs(t, err, &exErr)
+	require.Equal(t, "SLEEP;", exErr.SQL)
+	require.Equal(t, []string{"CREATE TABLE t1(c int);", "CREATE TABLE t2(c int);"}, drv.executed)
+	require.Equal(t, []string{"10ms 1s", "restore", "10ms 1s", "restore"}, drv.timeouts)
+	require.Equal(t, 1, rrw[1].Applied)
+	require.Equal(t, "SLEEP;", rrw[1].ErrorStmt)
+
+	// Session setup errors abort the execution.
+	drv.timeouts = append(drv.timeouts, "fail")
+	err = ex.ExecuteN(context.Background(), 1)
+	require.EqualError(t, err, "sql/migrate: execute: set session timeouts: unexpected timeouts")
+	require.Equal(t, err.Error(), rrw[1].Error)
+	require.Equal(t, 1, rrw[1].Applied)
+
+	// Timeouts are not configured in dry-run.
+	drv.timeouts = nil
+	ex, err = migrate.NewExecutor(drv, dir, &rrw, migrate.WithStmtTimeout(time.Second), migrate.WithDryRun(true))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Empty(t, drv.timeouts)
+}
+
+func TestExecutor_TxMode(t *testing.T) {
+	var (
+		ops  []string
+		drv  = &mockDriver{}
+		open = func(context.Context) (*migrate.Tx, error) {
+			ops = append(ops, "begin")
+			return &migrate.Tx{
+				Driver:   drv,
+				Commit:   func() error { ops = append(ops, "commit"); return nil },
+				Rollback: func() error { ops = append(ops, "rollback"); return nil },
+			}, nil
+		}
+	)
+	_, err := migrate.NewExecutor(drv, migrate.OpenMemDir(""), &mockRevisionReadWriter{}, migrate.WithTxMode(migrate.TxModeFile, nil))
+	require.EqualError(t, err, `sql/migrate: execute: no transaction opener given for txmode "file"`)
+	_, err = migrate.NewExecutor(drv, migrate.OpenMemDir(""), &mockRevisionReadWriter{}, migrate.WithTxMode("unknown", open))
+	require.EqualError(t, err, `sql/migrate: execute: unknown txmode "unknown"`)
+
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_idx.sql", []byte("-- atlas:txmode none\n\nCREATE INDEX CONCURRENTLY i ON t1(c);")))
+	require.NoError(t, dir.WriteFile("3_t2.sql", []byte("CREATE TABLE t2(c int);")))
+	require.NoError(t, migrate.Rehash(dir))
+
+	// Each file in its own transaction, unless it opts out.
+	log := &mockLogger{}
+	ex, err := migrate.NewExecutor(drv, dir, &mockRevisionReadWriter{}, migrate.WithLogger(log), migrate.WithTxMode(migrate.TxModeFile, open))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Equal(t, []string{"begin", "commit", "begin", "commit"}, ops)
+	require.Len(t, drv.executed, 3)
+	var txs []migrate.TxOp
+	for _, l := range *log {
+		if l, ok := l.(migrate.LogTx); ok {
+			txs = append(txs, l.Op)
+		}
+	}
+	require.Equal(t, []migrate.TxOp{migrate.TxBegin, migrate.TxCommit, migrate.TxBegin, migrate.TxCommit}, txs)
+
+	// Failures roll back the transaction.
+	ops, *drv = nil, mockDriver{}
+	drv.failOn(1, errors.New("error"))
+	ex, err = migrate.NewExecutor(drv, dir, &mockRevisionReadWriter{}, migrate.WithTxMode(migrate.TxModeFile, open))
+	require.NoError(t, err)
+	require.Error(t, ex.ExecuteN(context.Background(), 0))
+	require.Equal(t, []string{"begin", "rollback"}, ops)
+
+	// Revisions that are written outside the transaction are restored on rollback,
+	// and a retry executes the statements that were rolled back.
+	multi := migrate.OpenMemDir(t.Name() + "_multi")
+	t.Cleanup(func() { require.NoError(t, multi.Close()) })
+	require.NoError(t, multi.WriteFile("1_t.sql", []byte("CREATE TABLE t1(c int);\nCREATE TABLE t2(c int);\nCREATE TABLE t3(c int);")))
+	require.NoError(t, migrate.Rehash(multi))
+	rrw := &mockRevisionReadWriter{}
+	*drv = mockDriver{}
+	drv.failOn(2, errors.New("error"))
+	ex, err = migrate.NewExecutor(drv, multi, rrw, migrate.WithTxMode(migrate.TxModeFile, open))
+	require.NoError(t, err)
+	require.Error(t, ex.ExecuteN(context.Background(), 0))
+	require.Empty(t, *rrw, "revision created in the transaction is deleted")
+	*drv = mockDriver{}
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Equal(t, []string{"CREATE TABLE t1(c int);", "CREATE TABLE t2(c int);", "CREATE TABLE t3(c int);"}, drv.executed)
+
+	// A file that was partially applied without a transaction.
+	rrw.clean()
+	*drv = mockDriver{}
+	drv.failOn(2, errors.New("error"))
+	ex, err = migrate.NewExecutor(drv, multi, rrw)
+	require.NoError(t, err)
+	require.Error(t, ex.ExecuteN(context.Background(), 0))
+	require.Len(t, *rrw, 1)
+	require.Equal(t, 1, (*rrw)[0].Applied)
+	require.Len(t, (*rrw)[0].PartialHashes, 1)
+	require.Equal(t, "CREATE TABLE t2(c int);", (*rrw)[0].ErrorStmt)
+	// Its retry fails within a transaction, after executing the second statement.
+	*drv = mockDriver{}
+	drv.failOn(2, errors.New("error"))
+	ex, err = migrate.NewExecutor(drv, multi, rrw, migrate.WithTxMode(migrate.TxModeFile, open))
+	require.NoError(t, err)
+	require.Error(t, ex.ExecuteN(context.Background(), 0))
+	require.Equal(t, []string{"CREATE TABLE t2(c int);"}, drv.executed)
+	require.Len(t, *rrw, 1)
+	require.Equal(t, 1, (*rrw)[0].Applied)
+	require.Len(t, (*rrw)[0].PartialHashes, 1)
+	require.Equal(t, "CREATE TABLE t2(c int);", (*rrw)[0].ErrorStmt)
+	*drv = mockDriver{}
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Equal(t, []string{"CREATE TABLE t2(c int);", "CREATE TABLE t3(c int);"}, drv.executed)
+	require.Equal(t, 3, (*rrw)[0].Applied)
+	require.Empty(t, (*rrw)[0].Error)
+
+	// Files cannot opt out of one transaction for all files.
+	ops, *drv = nil, mockDriver{}
+	ex, err = migrate.NewExecutor(drv, dir, &mockRevisionReadWriter{}, migrate.WithTxMode(migrate.TxModeAll, open))
+	require.NoError(t, err)
+	require.EqualError(t, ex.ExecuteN(context.Background(), 0), `sql/migrate: execute: cannot set txmode directive to "none" in "2_idx.sql" when txmode "all" is set globally`)
+	require.Equal(t, []string{"begin", "rollback"}, ops)
+
+	ops, *drv = nil, mockDriver{}
+	ex, err = migrate.NewExecutor(drv, dir, &mockRevisionReadWriter{}, migrate.WithTxMode(migrate.TxModeAll, open))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteTo(context.Background(), "1"))
+	require.Equal(t, []string{"begin", "commit"}, ops)
+
+	// Dry-run logs the transaction boundaries without opening transactions.
+	ops, *drv, *log = nil, mockDriver{}, nil
+	ex, err = migrate.NewExecutor(drv, dir, &mockRevisionReadWriter{}, migrate.WithLogger(log), migrate.WithTxMode(migrate.TxModeFile, open), migrate.WithDryRun(true))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Empty(t, ops)
+	require.Empty(t, drv.executed)
+	require.Equal(t, migrate.LogTx{Op: migrate.TxBegin}, (*log)[1])
+	require.IsType(t, migrate.LogFile{}, (*log)[2])
+	require.Equal(t, migrate.LogTx{Op: migrate.TxCommit}, (*log)[4])
+}
+
+func TestExecutor_Down(t *testing.T) {
+	var (
+		drv = &mockDriver{}
+		rrw = &mockRevisionReadWriter{}
+		mem = migrate.OpenMemDir(t.Name())
+		dir = &downDir{MemDir: mem, down: map[string][]string{
+			"1_t1.sql": {"DROP TABLE t1;"},
+			"2_t2.sql": {"DROP TABLE t2;"},
+		}}
+	)
+	t.Cleanup(func() { require.NoError(t, mem.Close()) })
+	require.NoError(t, mem.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, mem.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);")))
+	require.NoError(t, mem.WriteFile("3_t3.sql", []byte("CREATE TABLE t3(c int);")))
+	require.NoError(t, migrate.Rehash(mem))
+	ex, err := migrate.NewExecutor(drv, dir, rrw)
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Len(t, *rrw, 3)
+
+	// Files without down statements are reverted only if forced.
+	require.ErrorAs(t, ex.Down(context.Background(), 1), &migrate.NotReversibleError{})
+	require.Len(t, *rrw, 3)
+	*drv = mockDriver{}
+	require.NoError(t, ex.Down(context.Background(), 1, migrate.DownWithForce(true)))
+	require.Empty(t, drv.executed)
+	require.Len(t, *rrw, 2)
+
+	require.NoError(t, ex.Down(context.Background(), 1))
+	require.Equal(t, []string{"DROP TABLE t2;"}, drv.executed)
+	require.Len(t, *rrw, 1)
+	require.ErrorIs(t, ex.DownTo(context.Background(), "1"), migrate.ErrNoAppliedFiles)
+	require.EqualError(t, ex.DownTo(context.Background(), "2"), `sql/migrate: down: revision with version "2" not found`)
+
+	// Re-apply and revert all files up to the first one.
+	require.NoError(t, ex.ExecuteTo(context.Background(), "2"))
+	*drv = mockDriver{}
+	require.NoError(t, ex.DownTo(context.Background(), "1"))
+	require.Equal(t, []string{"DROP TABLE t2;"}, drv.executed)
+	require.NoError(t, ex.Down(context.Background(), 0))
+	require.Equal(t, []string{"DROP TABLE t2;", "DROP TABLE t1;"}, drv.executed)
+	require.Empty(t, *rrw)
+	require.ErrorIs(t, ex.Down(context.Background(), 0), migrate.ErrNoAppliedFiles)
+
+	// Partially applied files are not reverted.
+	*drv = mockDriver{}
+	drv.failOn(1, errors.New("error"))
+	require.Error(t, ex.ExecuteN(context.Background(), 1))
+	require.EqualError(t, ex.Down(context.Background(), 0), `sql/migrate: down: migration file "1_t1.sql" is partially applied`)
+
+	// Checkpoint files are not reverted.
+	cdir := migrate.OpenMemDir(t.Name() + "_checkpoint")
+	t.Cleanup(func() { require.NoError(t, cdir.Close()) })
+	require.NoError(t, cdir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, cdir.WriteCheckpoint("2_checkpoint.sql", "", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, cdir.WriteFile("3_t2.sql", []byte("CREATE TABLE t2(c int);")))
+	require.NoError(t, migrate.Rehash(cdir))
+	*drv, *rrw = mockDriver{}, mockRevisionReadWriter{}
+	ex, err = migrate.NewExecutor(drv, cdir, rrw)
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Len(t, *rrw, 2)
+	require.EqualError(t, ex.Down(context.Background(), 0, migrate.DownWithForce(true)), `sql/migrate: down: checkpoint file "2_checkpoint.sql" cannot be reverted`)
+	require.NoError(t, ex.Down(context.Background(), 1, migrate.DownWithForce(true)))
+	require.Len(t, *rrw, 1)
 }
 
 type (
+	// downDir wraps the files of a MemDir with down statements.
+	downDir struct {
+		*migrate.MemDir
+		down map[string][]string
+	}
+	downFile struct {
+		*migrate.LocalFile
+		down []string
+	}
+)
+
+func (d *downDir) Files() ([]migrate.File, error) {
+	files, err := d.MemDir.Files()
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range files {
+		files[i] = &downFile{LocalFile: f.(*migrate.LocalFile), down: d.down[f.Name()]}
+	}
+	return files, nil
+}
+
+func (f *downFile) DownStmts() ([]string, error) { return f.down, nil }
+
+type (
+	// mockNormalizer normalizes integer types to "int".
+	mockNormalizer struct {
+		calls []string
+		err   error
+	}
+	lockDriver struct {
+		*mockDriver
+		ops    []string
+		locked bool
+	}
+	timeoutDriver struct {
+		*mockDriver
+		timeouts []string
+	}
 	mockDriver struct {
 		migrate.Driver
 		plan        *migrate.Plan
@@ -662,7 +1785,11 @@ func (rrw *mockRevisionReadWriter) WriteRevision(_ context.Context, r *migrate.R
 			return nil
 		}
 	}
-	*rrw = append(*rrw, r)
+	// Revisions are ordered by their versions.
+	i := sort.Search(len(*rrw), func(i int) bool { return (*rrw)[i].Version > r.Version })
+	*rrw = append(*rrw, nil)
+	copy((*rrw)[i+1:], (*rrw)[i:])
+	(*rrw)[i] = r
 	return nil
 }
 
@@ -734,3 +1861,63 @@ func requireFileEqual(t *testing.T, d migrate.Dir, name, contents string) {
 	require.NoError(t, err)
 	require.Equal(t, contents, string(c))
 }
+
+func (d *timeoutDriver) SetTimeouts(_ context.Context, stmt, lock time.Duration) (migrate.RestoreFunc, error) {
+	if len(d.timeouts) > 0 && d.timeouts[len(d.timeouts)-1] == "fail" {
+		return nil, errors.New("unexpected timeouts")
+	}
+	d.timeouts = append(d.timeouts, fmt.Sprintf("%s %s", stmt, lock))
+	return func(context.Context) error {
+		d.timeouts = append(d.timeouts, "restore")
+		return nil
+	}, nil
+}
+
+func (d *timeoutDriver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if query == "SLEEP;" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return d.mockDriver.ExecContext(ctx, query, args...)
+}
+
+func (n *mockNormalizer) NormalizeRealm(ctx context.Context, r *schema.Realm) (*schema.Realm, error) {
+	n.calls = append(n.calls, "realm")
+	if n.err != nil {
+		return nil, n.err
+	}
+	for _, s := range r.Schemas {
+		n.normalize(s)
+	}
+	return r, nil
+}
+
+func (n *mockNormalizer) NormalizeSchema(_ context.Context, s *schema.Schema) (*schema.Schema, error) {
+	n.calls = append(n.calls, "schema "+s.Name)
+	if n.err != nil {
+		return nil, n.err
+	}
+	n.normalize(s)
+	return s, nil
+}
+
+func (*mockNormalizer) normalize(s *schema.Schema) {
+	for _, t := range s.Tables {
+		for _, c := range t.Columns {
+			if it, ok := c.Type.Type.(*schema.IntegerType); ok && it.T == "integer" {
+				it.T = "int"
+			}
+		}
+	}
+}
+
+func (d *lockDriver) Lock(_ context.Context, name string, timeout time.Duration) (schema.UnlockFunc, error) {
+	if d.locked {
+		return nil, schema.ErrLocked
+	}
+	d.ops = append(d.ops, fmt.Sprintf("lock %s %s", name, timeout))
+	return func() error {
+		d.ops = append(d.ops, "unlock "+name)
+		return nil
+	}, nil
+}
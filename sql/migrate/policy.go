@@ -0,0 +1,155 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DestructivePolicy defines how the Executor handles pending migration
+// files that contain destructive statements, e.g. dropping a table.
+type DestructivePolicy string
+
+// List of destructive policies.
+const (
+	// DestructiveAllow executes destructive statements as any other statement.
+	DestructiveAllow DestructivePolicy = "allow"
+	// DestructiveWarn executes destructive statements, and reports them to the
+	// Logger of the Executor using the LogDestructive entry before execution.
+	DestructiveWarn DestructivePolicy = "warn"
+	// DestructiveError fails the execution if there are destructive statements.
+	DestructiveError DestructivePolicy = "error"
+	// DestructiveRequireFlag fails the execution if there are destructive statements,
+	// unless they were explicitly allowed by the caller. See WithDestructiveOverride.
+	DestructiveRequireFlag DestructivePolicy = "require-flag"
+)
+
+type (
+	// LogDestructive is sent if destructive statements are about to be
+	// executed, before the execution of the pending files has started.
+	LogDestructive struct {
+		File  File
+		Stmts []string
+	}
+
+	// DestructiveStmtsError is returned by the Executor in case a pending file contains
+	// destructive statements, and they are not allowed by the policy of the Executor.
+	DestructiveStmtsError struct {
+		File   string            // Name of the file.
+		Stmts  []string          // Destructive statements of the file.
+		Policy DestructivePolicy // Policy of the Executor.
+	}
+)
+
+func (LogDestructive) logEntry() {}
+
+// Error implements the error interface.
+func (e DestructiveStmtsError) Error() string {
+	msg := fmt.Sprintf("sql/migrate: execute: destructive statements in file %q are not allowed: %q", e.File, e.Stmts)
+	if e.Policy == DestructiveRequireFlag {
+		msg += " (explicit override is required)"
+	}
+	return msg
+}
+
+// WithDestructivePolicy sets the policy of the Executor for executing destructive statements. The policy
+// is checked for all pending files before any of them is executed. Statements are considered destructive
+// if they are reported by the DestructiveStmt function. The default policy is DestructiveAllow.
+func WithDestructivePolicy(p DestructivePolicy) ExecutorOption {
+	return func(ex *Executor) error {
+		switch p {
+		case DestructiveAllow, DestructiveWarn, DestructiveError, DestructiveRequireFlag:
+		default:
+			return fmt.Errorf("sql/migrate: execute: unknown destructive policy %q", p)
+		}
+		ex.destPolicy = p
+		return nil
+	}
+}
+
+// WithDestructiveOverride allows executing destructive statements under the DestructiveRequireFlag
+// policy, and is usually set by an explicit flag of the caller. Statements are still reported to the
+// Logger of the Executor. The override has no effect on the other policies.
+func WithDestructiveOverride(b bool) ExecutorOption {
+	return func(ex *Executor) error {
+		ex.destAllowed = b
+		return nil
+	}
+}
+
+// checkDestructive checks the given files against the destructive policy of the Executor.
+func (e *Executor) checkDestructive(files []File) error {
+	if e.destPolicy == "" || e.destPolicy == DestructiveAllow {
+		return nil
+	}
+	type found struct {
+		f     File
+		stmts []string
+	}
+	var all []found
+	for _, f := range files {
+		stmts, err := f.Stmts()
+		if err != nil {
+			return fmt.Errorf("sql/migrate: execute: scanning statements from %q: %w", f.Name(), err)
+		}
+		var ds []string
+		for _, s := range stmts {
+			if DestructiveStmt(s) {
+				ds = append(ds, s)
+			}
+		}
+		if len(ds) == 0 {
+			continue
+		}
+		if e.destPolicy == DestructiveError || e.destPolicy == DestructiveRequireFlag && !e.destAllowed {
+			return DestructiveStmtsError{File: f.Name(), Stmts: ds, Policy: e.destPolicy}
+		}
+		all = append(all, found{f: f, stmts: ds})
+	}
+	for _, d := range all {
+		e.log.Log(LogDestructive{File: d.f, Stmts: d.stmts})
+	}
+	return nil
+}
+
+// DestructiveStmt reports if the given statement is destructive, i.e. it drops a schema, a table,
+// a column or a partition, or it truncates a table. The detection is based on the leading keywords
+// of the statement and its ALTER TABLE clauses, and it does not require a connection to the database.
+func DestructiveStmt(stmt string) bool {
+	fs := strings.Fields(strings.NewReplacer(",", " , ", ";", " ").Replace(strings.ToUpper(stmt)))
+	switch {
+	case len(fs) > 0 && fs[0] == "TRUNCATE":
+		return true
+	case len(fs) > 1 && fs[0] == "DROP":
+		switch fs[1] {
+		case "SCHEMA", "DATABASE", "TABLE":
+			return true
+		}
+	case len(fs) > 2 && fs[0] == "ALTER" && fs[1] == "TABLE":
+		i := 2
+		for i < len(fs) && (fs[i] == "IF" || fs[i] == "EXISTS" || fs[i] == "ONLY") {
+			i++
+		}
+		// Clauses start after the table name, or after a comma.
+		for i, c := i+1, i+1; i < len(fs)-1; i++ {
+			if fs[i] == "," {
+				c = i + 1
+			}
+			if i != c || fs[i] != "DROP" {
+				continue
+			}
+			switch fs[i+1] {
+			// Dropping indexes and constraints does not drop data. Otherwise, a column
+			// (or a partition) is dropped. Note, the COLUMN keyword is optional in some
+			// databases, like MySQL and PostgreSQL.
+			case "INDEX", "KEY", "CONSTRAINT", "FOREIGN", "PRIMARY", "CHECK":
+			default:
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestructiveStmt(t *testing.T) {
+	for stmt, expected := range map[string]bool{
+		"CREATE TABLE t(c int);":                                  false,
+		"DROP TABLE t;":                                           true,
+		"drop table if exists `t`;":                               true,
+		"DROP SCHEMA s CASCADE;":                                  true,
+		"DROP DATABASE d;":                                        true,
+		"DROP INDEX i;":                                           false,
+		"DROP VIEW v;":                                            false,
+		"TRUNCATE t;":                                             true,
+		"ALTER TABLE t ADD COLUMN c int;":                         false,
+		"ALTER TABLE t DROP COLUMN c;":                            true,
+		"ALTER TABLE t ADD COLUMN c int,DROP c;":                  true,
+		"ALTER TABLE t DROP INDEX i, DROP FOREIGN KEY f;":         false,
+		"ALTER TABLE t DROP CONSTRAINT c;":                        false,
+		"ALTER TABLE t ALTER COLUMN c DROP NOT NULL;":             false,
+		"ALTER TABLE t ALTER COLUMN c DROP DEFAULT;":              false,
+		"ALTER TABLE t DROP PARTITION p1;":                        true,
+		"ALTER TABLE IF EXISTS ONLY t DROP c;":                    true,
+		"ALTER TABLE t ALTER COLUMN c DROP DEFAULT, DROP d;":      true,
+		"INSERT INTO t(c) VALUES ('DROP TABLE t');":               false,
+		"ALTER TABLE t RENAME COLUMN drop TO c;":                  false,
+		"ALTER TABLE t DROP PRIMARY KEY, ADD PRIMARY KEY (c, d);": false,
+	} {
+		require.Equal(t, expected, migrate.DestructiveStmt(stmt), stmt)
+	}
+}
+
+func TestExecutor_DestructivePolicy(t *testing.T) {
+	_, err := migrate.NewExecutor(&mockDriver{}, migrate.OpenMemDir(""), &mockRevisionReadWriter{}, migrate.WithDestructivePolicy("deny"))
+	require.EqualError(t, err, `sql/migrate: execute: unknown destructive policy "deny"`)
+
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_t2.sql", []byte("ALTER TABLE t1 DROP COLUMN c;\nDROP TABLE t1;")))
+	require.NoError(t, migrate.Rehash(dir))
+
+	for _, p := range []migrate.DestructivePolicy{migrate.DestructiveError, migrate.DestructiveRequireFlag} {
+		var (
+			drv = &mockDriver{}
+			rrw = &mockRevisionReadWriter{}
+		)
+		ex, err := migrate.NewExecutor(drv, dir, rrw, migrate.WithDestructivePolicy(p))
+		require.NoError(t, err)
+		err = ex.ExecuteN(context.Background(), 0)
+		var derr migrate.DestructiveStmtsError
+		require.ErrorAs(t, err, &derr)
+		require.Equal(t, "2_t2.sql", derr.File)
+		require.Equal(t, []string{"ALTER TABLE t1 DROP COLUMN c;", "DROP TABLE t1;"}, derr.Stmts)
+		require.Equal(t, p, derr.Policy)
+		// No file is executed if one of the pending files is not allowed.
+		require.Empty(t, drv.executed)
+		require.Empty(t, *rrw)
+		// Files without destructive statements are not affected.
+		require.NoError(t, ex.ExecuteN(context.Background(), 1))
+		require.Len(t, drv.executed, 1)
+	}
+
+	// The override has no effect on the error policy.
+	ex, err := migrate.NewExecutor(&mockDriver{}, dir, &mockRevisionReadWriter{}, migrate.WithDestructivePolicy(migrate.DestructiveError), migrate.WithDestructiveOverride(true))
+	require.NoError(t, err)
+	require.EqualError(t, ex.ExecuteN(context.Background(), 0), `sql/migrate: execute: destructive statements in file "2_t2.sql" are not allowed: ["ALTER TABLE t1 DROP COLUMN c;" "DROP TABLE t1;"]`)
+	ex, err = migrate.NewExecutor(&mockDriver{}, dir, &mockRevisionReadWriter{}, migrate.WithDestructivePolicy(migrate.DestructiveRequireFlag))
+	require.NoError(t, err)
+	require.EqualError(t, ex.ExecuteN(context.Background(), 0), `sql/migrate: execute: destructive statements in file "2_t2.sql" are not allowed: ["ALTER TABLE t1 DROP COLUMN c;" "DROP TABLE t1;"] (explicit override is required)`)
+
+	for _, opts := range [][]migrate.ExecutorOption{
+		{migrate.WithDestructivePolicy(migrate.DestructiveWarn)},
+		{migrate.WithDestructivePolicy(migrate.DestructiveRequireFlag), migrate.WithDestructiveOverride(true)},
+	} {
+		var (
+			drv = &mockDriver{}
+			log = &mockLogger{}
+		)
+		ex, err := migrate.NewExecutor(drv, dir, &mockRevisionReadWriter{}, append(opts, migrate.WithLogger(log))...)
+		require.NoError(t, err)
+		require.NoError(t, ex.ExecuteN(context.Background(), 0))
+		require.Len(t, drv.executed, 3)
+		d, ok := (*log)[0].(migrate.LogDestructive)
+		require.True(t, ok)
+		require.Equal(t, "2_t2.sql", d.File.Name())
+		require.Equal(t, []string{"ALTER TABLE t1 DROP COLUMN c;", "DROP TABLE t1;"}, d.Stmts)
+		require.IsType(t, migrate.LogExecution{}, (*log)[1])
+	}
+}
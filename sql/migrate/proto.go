@@ -0,0 +1,185 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"ariga.io/atlas/sql/migrate/migratepb"
+)
+
+//go:generate go run ariga.io/atlas/sql/internal/protogen/cmd/protogen -o migratepb/migrate.pb.go migrate.proto
+
+// Types of the Arg message.
+const (
+	argNull   = "null"
+	argInt    = "int"
+	argUint   = "uint"
+	argFloat  = "float"
+	argBool   = "bool"
+	argString = "string"
+	argBytes  = "bytes"
+	argTime   = "time"
+)
+
+// PlanToProto returns the protobuf representation of the plan, as defined in migrate.proto.
+// The Source of its changes is encoded by its type name only, e.g. "AddTable".
+func PlanToProto(p *Plan) (*migratepb.Plan, error) {
+	m := &migratepb.Plan{
+		Version:       p.Version,
+		Name:          p.Name,
+		Reversible:    p.Reversible,
+		Transactional: p.Transactional,
+	}
+	for _, c := range p.Changes {
+		mc, err := ChangeToProto(c)
+		if err != nil {
+			return nil, err
+		}
+		m.Changes = append(m.Changes, mc)
+	}
+	return m, nil
+}
+
+// PlanFromProto returns the plan represented by the given message.
+func PlanFromProto(m *migratepb.Plan) (*Plan, error) {
+	p := &Plan{
+		Version:       m.Version,
+		Name:          m.Name,
+		Reversible:    m.Reversible,
+		Transactional: m.Transactional,
+	}
+	for _, mc := range m.Changes {
+		c, err := ChangeFromProto(mc)
+		if err != nil {
+			return nil, err
+		}
+		p.Changes = append(p.Changes, c)
+	}
+	return p, nil
+}
+
+// ChangeToProto returns the protobuf representation of the change.
+func ChangeToProto(c *Change) (*migratepb.Change, error) {
+	reverse, err := c.ReverseStmts()
+	if err != nil {
+		return nil, err
+	}
+	m := &migratepb.Change{
+		Cmd:          c.Cmd,
+		Comment:      c.Comment,
+		Cost:         uint32(c.Cost),
+		Annotations:  c.Annotations,
+		ReverseEmpty: reverse != nil && len(reverse) == 0,
+	}
+	if len(reverse) > 0 {
+		m.Reverse = reverse
+	}
+	if c.Source != nil {
+		m.SourceType = reflect.Indirect(reflect.ValueOf(c.Source)).Type().Name()
+	}
+	for _, a := range c.Args {
+		ma, err := argToProto(a)
+		if err != nil {
+			return nil, err
+		}
+		m.Args = append(m.Args, ma)
+	}
+	return m, nil
+}
+
+// ChangeFromProto returns the change represented by the given message. Note,
+// the Source of a decoded change is always nil, and integer arguments are
+// decoded as int64 (or uint64) values.
+func ChangeFromProto(m *migratepb.Change) (*Change, error) {
+	c := &Change{
+		Cmd:         m.Cmd,
+		Comment:     m.Comment,
+		Cost:        Cost(m.Cost),
+		Annotations: m.Annotations,
+	}
+	switch {
+	case len(m.Reverse) == 1:
+		c.Reverse = m.Reverse[0]
+	case len(m.Reverse) > 1:
+		c.Reverse = m.Reverse
+	case m.ReverseEmpty:
+		c.Reverse = []string{}
+	}
+	for _, ma := range m.Args {
+		a, err := argFromProto(ma)
+		if err != nil {
+			return nil, err
+		}
+		c.Args = append(c.Args, a)
+	}
+	return c, nil
+}
+
+func argToProto(a any) (*migratepb.Arg, error) {
+	switch a := a.(type) {
+	case nil:
+		return &migratepb.Arg{Type: argNull}, nil
+	case int:
+		return &migratepb.Arg{Type: argInt, Int: int64(a)}, nil
+	case int8:
+		return &migratepb.Arg{Type: argInt, Int: int64(a)}, nil
+	case int16:
+		return &migratepb.Arg{Type: argInt, Int: int64(a)}, nil
+	case int32:
+		return &migratepb.Arg{Type: argInt, Int: int64(a)}, nil
+	case int64:
+		return &migratepb.Arg{Type: argInt, Int: a}, nil
+	case uint:
+		return &migratepb.Arg{Type: argUint, Uint: uint64(a)}, nil
+	case uint8:
+		return &migratepb.Arg{Type: argUint, Uint: uint64(a)}, nil
+	case uint16:
+		return &migratepb.Arg{Type: argUint, Uint: uint64(a)}, nil
+	case uint32:
+		return &migratepb.Arg{Type: argUint, Uint: uint64(a)}, nil
+	case uint64:
+		return &migratepb.Arg{Type: argUint, Uint: a}, nil
+	case float32:
+		return &migratepb.Arg{Type: argFloat, Float: float64(a)}, nil
+	case float64:
+		return &migratepb.Arg{Type: argFloat, Float: a}, nil
+	case bool:
+		return &migratepb.Arg{Type: argBool, Bool: a}, nil
+	case string:
+		return &migratepb.Arg{Type: argString, String: a}, nil
+	case []byte:
+		return &migratepb.Arg{Type: argBytes, Bytes: a}, nil
+	case time.Time:
+		return &migratepb.Arg{Type: argTime, String: a.Format(time.RFC3339Nano)}, nil
+	default:
+		return nil, fmt.Errorf("sql/migrate: unsupported argument type %T", a)
+	}
+}
+
+func argFromProto(m *migratepb.Arg) (any, error) {
+	switch m.Type {
+	case argNull:
+		return nil, nil
+	case argInt:
+		return m.Int, nil
+	case argUint:
+		return m.Uint, nil
+	case argFloat:
+		return m.Float, nil
+	case argBool:
+		return m.Bool, nil
+	case argString:
+		return m.String, nil
+	case argBytes:
+		return m.Bytes, nil
+	case argTime:
+		return time.Parse(time.RFC3339Nano, m.String)
+	default:
+		return nil, fmt.Errorf("sql/migrate: unexpected argument type %q", m.Type)
+	}
+}
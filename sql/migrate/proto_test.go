@@ -0,0 +1,73 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate_test
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"ariga.io/atlas/sql/internal/protogen"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/migrate/migratepb"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan_Proto(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	p := &migrate.Plan{
+		Version:       "1",
+		Name:          "init",
+		Reversible:    true,
+		Transactional: true,
+		Changes: []*migrate.Change{
+			{
+				Cmd:         "CREATE TABLE t (c int)",
+				Reverse:     "DROP TABLE t",
+				Source:      &schema.AddTable{T: schema.NewTable("t")},
+				Cost:        migrate.CostMetadata,
+				Annotations: map[string]string{"a": "b"},
+			},
+			{
+				Cmd:     "INSERT INTO t VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+				Args:    []any{nil, int64(math.MaxInt64), uint64(math.MaxUint64), 1.5, true, "s", []byte("b"), now},
+				Reverse: []string{"DELETE FROM t", "VACUUM"},
+			},
+			{
+				Cmd:     "ALTER TABLE t ADD COLUMN d int",
+				Reverse: []string{},
+			},
+		},
+	}
+	m, err := migrate.PlanToProto(p)
+	require.NoError(t, err)
+	require.Equal(t, "AddTable", m.Changes[0].SourceType)
+	b, err := m.Marshal()
+	require.NoError(t, err)
+	var m2 migratepb.Plan
+	require.NoError(t, m2.Unmarshal(b))
+	require.Equal(t, m, &m2)
+
+	got, err := migrate.PlanFromProto(&m2)
+	require.NoError(t, err)
+	p.Changes[0].Source = nil
+	require.Equal(t, p, got)
+
+	_, err = migrate.PlanToProto(&migrate.Plan{Changes: []*migrate.Change{{Args: []any{struct{}{}}}}})
+	require.EqualError(t, err, "sql/migrate: unsupported argument type struct {}")
+	_, err = migrate.PlanFromProto(&migratepb.Plan{Changes: []*migratepb.Change{{Args: []*migratepb.Arg{{Type: "unknown"}}}}})
+	require.EqualError(t, err, `sql/migrate: unexpected argument type "unknown"`)
+}
+
+func TestProto_Generated(t *testing.T) {
+	want, err := protogen.Generate("migrate.proto")
+	require.NoError(t, err)
+	got, err := os.ReadFile("migratepb/migrate.pb.go")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got), "run go generate to update the generated code")
+}
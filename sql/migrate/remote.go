@@ -0,0 +1,115 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// RemoteDir is a read-only Dir that was loaded from a remote location,
+// like an object storage bucket or an artifact registry. See OpenHTTPDir.
+type RemoteDir struct {
+	*MemDir
+}
+
+var _ CheckpointDir = (*RemoteDir)(nil)
+
+// errReadOnly is returned when trying to modify a RemoteDir.
+var errReadOnly = errors.New("sql/migrate: remote directory is read-only")
+
+// WriteFile implements Dir.WriteFile.
+func (*RemoteDir) WriteFile(string, []byte) error {
+	return errReadOnly
+}
+
+// WriteCheckpoint implements CheckpointDir.WriteCheckpoint.
+func (*RemoteDir) WriteCheckpoint(string, string, []byte) error {
+	return errReadOnly
+}
+
+// RemoveFile implements RemoveDir.RemoveFile.
+func (*RemoteDir) RemoveFile(string) error {
+	return errReadOnly
+}
+
+// OpenHTTPDir loads the migration directory that is served under the given base URL, using the
+// atlas.sum file of the directory as its index. Files are fetched once, and the directory is then
+// read from memory. Down files are listed in the atlas.sum file, and hence, fetched as well. The
+// URL of each file is the base URL joined with the file name. Hence, object storage buckets can
+// be read using their public HTTP endpoints, or by passing a client whose transport authenticates
+// its requests. If c is nil, http.DefaultClient is used.
+//
+//	dir, err := migrate.OpenHTTPDir(ctx, nil, "https://bucket.s3.amazonaws.com/migrations/")
+//
+// Use OpenHTTPDirFunc for directories whose files have their own URLs, like presigned URLs.
+func OpenHTTPDir(ctx context.Context, c *http.Client, base string) (*RemoteDir, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("sql/migrate: parse remote directory url: %w", err)
+	}
+	return OpenHTTPDirFunc(ctx, c, func(name string) (string, error) {
+		fu := *u
+		fu.Path = path.Join(u.Path, name)
+		return fu.String(), nil
+	})
+}
+
+// OpenHTTPDirFunc is like OpenHTTPDir, but the URL of each file, including the atlas.sum
+// file, is returned by the given function. For example, a function that presigns the
+// object of each file in a bucket.
+func OpenHTTPDirFunc(ctx context.Context, c *http.Client, fileURL func(name string) (string, error)) (*RemoteDir, error) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	fetch := func(name string) ([]byte, error) {
+		u, err := fileURL(name)
+		if err != nil {
+			return nil, fmt.Errorf("sql/migrate: resolve url of file %q: %w", name, err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("sql/migrate: fetch file %q: %w", name, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("sql/migrate: fetch file %q: unexpected status %q", name, resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}
+	b, err := fetch(HashFileName)
+	if err != nil {
+		return nil, err
+	}
+	var sum HashFile
+	if err := sum.UnmarshalText(b); err != nil {
+		return nil, fmt.Errorf("sql/migrate: read remote %s file: %w", HashFileName, err)
+	}
+	dir := &MemDir{}
+	for _, f := range sum {
+		b, err := fetch(f.N)
+		if err != nil {
+			return nil, err
+		}
+		if err := dir.WriteFile(f.N, b); err != nil {
+			return nil, err
+		}
+	}
+	// The sum file is written as is, to let
+	// Validate detect files that were modified.
+	if err := dir.WriteFile(HashFileName, b); err != nil {
+		return nil, err
+	}
+	return &RemoteDir{MemDir: dir}, nil
+}
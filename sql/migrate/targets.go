@@ -0,0 +1,105 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type (
+	// A Target is a database, or a schema, that migration files are executed on
+	// by ExecuteTargets. For example, the schema of a tenant in a multi-tenant
+	// application. Each target tracks its revisions separately.
+	Target struct {
+		// Name of the target used for reporting errors.
+		Name string
+		// Driver and RevisionReadWriter of the target.
+		Driver             Driver
+		RevisionReadWriter RevisionReadWriter
+		// Options are additional options for the Executor of the target,
+		// applied after the options that are shared by all targets.
+		Options []ExecutorOption
+	}
+
+	// TargetError describes a failure of executing migration files on a target.
+	TargetError struct {
+		Target string
+		Err    error
+	}
+
+	// TargetsError is returned by ExecuteTargets in case the execution failed on
+	// some of the targets. Errors are ordered by the order of the given targets.
+	TargetsError []*TargetError
+)
+
+// Error implements the error interface.
+func (e *TargetError) Error() string {
+	return fmt.Sprintf("target %q: %v", e.Target, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TargetError) Unwrap() error {
+	return e.Err
+}
+
+// Error implements the error interface.
+func (e TargetsError) Error() string {
+	errs := make([]string, len(e))
+	for i := range e {
+		errs[i] = e[i].Error()
+	}
+	return fmt.Sprintf("sql/migrate: execute: %d target(s) failed: %s", len(e), strings.Join(errs, "; "))
+}
+
+// ExecuteTargets executes the pending files of the migration directory on all targets, with up to n
+// concurrent executions. If n <= 0, all targets are executed concurrently. A failure on one target does
+// not stop the execution on the others, and all failures are reported in a TargetsError. Targets that
+// have no pending files are skipped.
+//
+// Note, the given options are shared by the executors of all targets. Hence, loggers or hooks that are
+// not safe for concurrent use should be passed to each target using its Options.
+func ExecuteTargets(ctx context.Context, dir Dir, targets []*Target, n int, opts ...ExecutorOption) error {
+	if n <= 0 || n > len(targets) {
+		n = len(targets)
+	}
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, n)
+		errs = make([]error, len(targets))
+	)
+	for i, t := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, t *Target) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			ex, err := NewExecutor(t.Driver, dir, t.RevisionReadWriter, append(opts[:len(opts):len(opts)], t.Options...)...)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if err := ex.ExecuteN(ctx, 0); err != nil && !errors.Is(err, ErrNoPendingFiles) {
+				errs[i] = err
+			}
+		}(i, t)
+	}
+	wg.Wait()
+	var terr TargetsError
+	for i, err := range errs {
+		if err != nil {
+			terr = append(terr, &TargetError{Target: targets[i].Name, Err: err})
+		}
+	}
+	if len(terr) > 0 {
+		return terr
+	}
+	return nil
+}
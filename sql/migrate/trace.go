@@ -0,0 +1,146 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// Tracer starts spans for the operations of the Planner, the Executor and the connections that
+	// were wrapped by TraceConn. It allows reporting them to existing observability stacks, without
+	// depending on a specific one. For example, an OpenTelemetry adapter can start an OpenTelemetry
+	// span in Start, and in Span.End, end it and record the duration of the operation in a histogram.
+	//
+	// The following span names are reported:
+	//
+	//	atlas.plan          - Planning changes. Each planned change is reported as an event.
+	//	atlas.migrate.file  - Executing a migration file.
+	//	atlas.migrate.stmt  - Executing a statement of a migration file.
+	//	atlas.query         - Running a query on a traced connection, e.g. an inspection query.
+	//	atlas.exec          - Executing a statement on a traced connection.
+	Tracer interface {
+		// Start starts a span with the given name and attributes, and returns
+		// a context that holds it, to be used as the parent of nested spans.
+		Start(ctx context.Context, name string, attrs ...Attr) (context.Context, Span)
+	}
+
+	// A Span describes an operation that was started by a Tracer.
+	Span interface {
+		// AddEvent records an event that happened during the operation.
+		AddEvent(name string, attrs ...Attr)
+		// End is called once the operation is done, with its duration and its error, if any.
+		End(d time.Duration, err error)
+	}
+
+	// Attr describes an attribute of a span or an event.
+	Attr struct {
+		Key   string
+		Value any
+	}
+
+	// span wraps the Span of a Tracer, and measures the duration of its operation.
+	// Its methods are no-op in case no Tracer was configured, and the span is nil.
+	span struct {
+		Span
+		start time.Time
+	}
+
+	// tracedConn wraps an ExecQuerier with a Tracer.
+	tracedConn struct {
+		schema.ExecQuerier
+		t Tracer
+	}
+
+	// tracedDB is a tracedConn that can obtain single connections, like sql.DB.
+	tracedDB struct {
+		*tracedConn
+		db interface {
+			Conn(context.Context) (*sql.Conn, error)
+		}
+	}
+)
+
+// WithTracer sets the Tracer of an Executor. Spans are started for each
+// migration file and for each statement that is executed by the Executor.
+func WithTracer(t Tracer) ExecutorOption {
+	return func(ex *Executor) error {
+		ex.tracer = t
+		return nil
+	}
+}
+
+// PlanWithTracer sets the Tracer of a Planner. Spans are started for each planning
+// operation, and the planned changes are reported as events of their spans.
+func PlanWithTracer(t Tracer) PlannerOption {
+	return func(p *Planner) {
+		p.tracer = t
+	}
+}
+
+// TraceConn wraps the given connection with the Tracer, and starts a span for every query or
+// statement that is executed on it. Drivers that were opened with the returned connection report
+// their inspection queries, and the statements they execute. Note, the duration of a query span
+// does not include reading its rows, and connections that were obtained from the returned one
+// using its Conn method (e.g. for acquiring database locks) are not traced.
+//
+//	drv, err := mysql.Open(migrate.TraceConn(db, tracer))
+func TraceConn(conn schema.ExecQuerier, t Tracer) schema.ExecQuerier {
+	c := &tracedConn{ExecQuerier: conn, t: t}
+	if db, ok := conn.(interface {
+		Conn(context.Context) (*sql.Conn, error)
+	}); ok {
+		return &tracedDB{tracedConn: c, db: db}
+	}
+	return c
+}
+
+// QueryContext implements schema.ExecQuerier.
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startSpan(ctx, c.t, "atlas.query", Attr{Key: "db.statement", Value: query})
+	rows, err := c.ExecQuerier.QueryContext(ctx, query, args...)
+	span.end(err)
+	return rows, err
+}
+
+// ExecContext implements schema.ExecQuerier.
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startSpan(ctx, c.t, "atlas.exec", Attr{Key: "db.statement", Value: query})
+	res, err := c.ExecQuerier.ExecContext(ctx, query, args...)
+	span.end(err)
+	return res, err
+}
+
+// Conn returns a single connection from the underlying database.
+func (c *tracedDB) Conn(ctx context.Context) (*sql.Conn, error) {
+	return c.db.Conn(ctx)
+}
+
+// startSpan starts a span using the given Tracer, if it is not nil.
+func startSpan(ctx context.Context, t Tracer, name string, attrs ...Attr) (context.Context, *span) {
+	if t == nil {
+		return ctx, nil
+	}
+	ctx, s := t.Start(ctx, name, attrs...)
+	return ctx, &span{Span: s, start: time.Now()}
+}
+
+// event records an event on the span.
+func (s *span) event(name string, attrs ...Attr) {
+	if s != nil {
+		s.AddEvent(name, attrs...)
+	}
+}
+
+// end ends the span with the duration since it was started.
+func (s *span) end(err error) {
+	if s != nil {
+		s.End(time.Since(s.start), err)
+	}
+}
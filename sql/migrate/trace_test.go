@@ -0,0 +1,151 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	mockTracer struct {
+		spans []*mockSpan
+	}
+	mockSpan struct {
+		name   string
+		parent *mockSpan
+		attrs  []migrate.Attr
+		events []string
+		ended  bool
+		err    error
+	}
+	spanKey struct{}
+)
+
+func (t *mockTracer) Start(ctx context.Context, name string, attrs ...migrate.Attr) (context.Context, migrate.Span) {
+	s := &mockSpan{name: name, attrs: attrs}
+	s.parent, _ = ctx.Value(spanKey{}).(*mockSpan)
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (s *mockSpan) AddEvent(name string, attrs ...migrate.Attr) {
+	s.events = append(s.events, name+": "+attrs[0].Value.(string))
+}
+
+func (s *mockSpan) End(d time.Duration, err error) {
+	if d < 0 {
+		panic("negative duration")
+	}
+	s.ended, s.err = true, err
+}
+
+func TestExecutor_Tracer(t *testing.T) {
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1_t1.sql", []byte("CREATE TABLE t1(c int);")))
+	require.NoError(t, dir.WriteFile("2_t2.sql", []byte("CREATE TABLE t2(c int);\nCREATE TABLE t3(c int);")))
+	require.NoError(t, migrate.Rehash(dir))
+
+	var (
+		tr  = &mockTracer{}
+		drv = &mockDriver{}
+	)
+	drv.failOn(3, errors.New("table exists"))
+	ex, err := migrate.NewExecutor(drv, dir, &mockRevisionReadWriter{}, migrate.WithTracer(tr))
+	require.NoError(t, err)
+	require.Error(t, ex.ExecuteN(context.Background(), 0))
+	require.Len(t, tr.spans, 5)
+	f1, s1, f2, s2, s3 := tr.spans[0], tr.spans[1], tr.spans[2], tr.spans[3], tr.spans[4]
+	for _, s := range []*mockSpan{s1, s2, s3} {
+		require.True(t, s.ended)
+		require.Equal(t, "atlas.migrate.stmt", s.name)
+		require.Equal(t, "db.statement", s.attrs[0].Key)
+	}
+	require.True(t, f1.ended)
+	require.True(t, f2.ended)
+	require.Equal(t, []migrate.Attr{{Key: "atlas.migrate.file", Value: "1_t1.sql"}, {Key: "atlas.migrate.version", Value: "1"}}, f1.attrs)
+	require.NoError(t, f1.err)
+	require.Equal(t, f1, s1.parent)
+	require.Equal(t, "CREATE TABLE t1(c int);", s1.attrs[0].Value)
+	require.Equal(t, "2_t2.sql", f2.attrs[0].Value)
+	require.Error(t, f2.err)
+	require.Equal(t, f2, s2.parent)
+	require.NoError(t, s2.err)
+	require.Equal(t, f2, s3.parent)
+	require.EqualError(t, s3.err, "table exists")
+}
+
+func TestPlanner_Tracer(t *testing.T) {
+	var (
+		tr  = &mockTracer{}
+		drv = &mockDriver{
+			changes: []schema.Change{&schema.AddTable{T: schema.NewTable("t1")}},
+			plan: &migrate.Plan{
+				Changes: []*migrate.Change{{Cmd: "CREATE TABLE t1(c int)", Comment: "create table t1"}},
+			},
+		}
+	)
+	d, err := migrate.NewLocalDir(t.TempDir())
+	require.NoError(t, err)
+	pl := migrate.NewPlanner(drv, d, migrate.PlanWithTracer(tr))
+	_, err = pl.Plan(context.Background(), "t1", migrate.Realm(schema.NewRealm()))
+	require.NoError(t, err)
+	require.Len(t, tr.spans, 1)
+	require.Equal(t, "atlas.plan", tr.spans[0].name)
+	require.Equal(t, []migrate.Attr{{Key: "atlas.plan.name", Value: "t1"}}, tr.spans[0].attrs)
+	require.Equal(t, []string{"atlas.plan.change: CREATE TABLE t1(c int)"}, tr.spans[0].events)
+	require.True(t, tr.spans[0].ended)
+	require.NoError(t, tr.spans[0].err)
+
+	// Matched states are not reported as errors.
+	drv.changes = nil
+	_, err = pl.Plan(context.Background(), "t1", migrate.Realm(schema.NewRealm()))
+	require.ErrorIs(t, err, migrate.ErrNoPlan)
+	require.Len(t, tr.spans, 2)
+	require.True(t, tr.spans[1].ended)
+	require.NoError(t, tr.spans[1].err)
+	require.Empty(t, tr.spans[1].events)
+}
+
+func TestTraceConn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectExec("DROP TABLE t").WillReturnError(errors.New("no such table"))
+	tr := &mockTracer{}
+	conn := migrate.TraceConn(db, tr)
+	// Single connections can be obtained from traced databases.
+	_, ok := conn.(interface {
+		Conn(context.Context) (*sql.Conn, error)
+	})
+	require.True(t, ok)
+	rows, err := conn.QueryContext(context.Background(), "SELECT 1")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	_, err = conn.ExecContext(context.Background(), "DROP TABLE t")
+	require.Error(t, err)
+	require.Len(t, tr.spans, 2)
+	require.Equal(t, "atlas.query", tr.spans[0].name)
+	require.Equal(t, []migrate.Attr{{Key: "db.statement", Value: "SELECT 1"}}, tr.spans[0].attrs)
+	require.NoError(t, tr.spans[0].err)
+	require.Equal(t, "atlas.exec", tr.spans[1].name)
+	require.EqualError(t, tr.spans[1].err, "no such table")
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	_, ok = migrate.TraceConn(&mockDriver{}, tr).(interface {
+		Conn(context.Context) (*sql.Conn, error)
+	})
+	require.False(t, ok)
+}
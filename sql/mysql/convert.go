@@ -16,6 +16,9 @@ import (
 // FormatType converts schema type to its column form in the database.
 // An error is returned if the type cannot be recognized.
 func FormatType(t schema.Type) (string, error) {
+	if f, ok, err := customTypes.FormatType(t); ok {
+		return f, err
+	}
 	var f string
 	switch t := t.(type) {
 	case *BitType:
@@ -113,6 +116,9 @@ func FormatType(t schema.Type) (string, error) {
 // ParseType returns the schema.Type value represented by the given raw type.
 // The raw value is expected to follow the format in MySQL information schema.
 func ParseType(raw string) (schema.Type, error) {
+	if t, ok, err := customTypes.ParseType(raw); ok {
+		return t, err
+	}
 	parts, size, unsigned, err := parseColumn(raw)
 	if err != nil {
 		return nil, err
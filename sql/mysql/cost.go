@@ -0,0 +1,129 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+var _ migrate.CostEstimator = (*Driver)(nil)
+
+// EstimateCost implements migrate.CostEstimator. Unlike the default estimation, it takes
+// into account the changes that InnoDB applies instantly or in-place, such as adding a
+// column (since MySQL 8.0.12 and MariaDB 10.3.2), increasing the size of a VARCHAR column
+// or appending values to an ENUM column, and the changes that rebuild the table, such as
+// changing the nullability of a column.
+func (d *Driver) EstimateCost(c schema.Change) migrate.Cost {
+	m, ok := c.(*schema.ModifyTable)
+	if !ok {
+		return migrate.CostUnknown
+	}
+	maxC := migrate.CostMetadata
+	for _, c := range m.Changes {
+		cc := d.changeCost(m.T, c)
+		if cc == migrate.CostUnknown {
+			cc = migrate.DefaultCost(c)
+		}
+		if cc > maxC {
+			maxC = cc
+		}
+	}
+	return maxC
+}
+
+// changeCost returns the cost of a table change, or CostUnknown
+// if it is not different from the default estimation.
+func (d *Driver) changeCost(t *schema.Table, c schema.Change) migrate.Cost {
+	switch c := c.(type) {
+	case *schema.AddColumn:
+		x := &schema.GeneratedExpr{}
+		switch {
+		case sqlx.Has(c.C.Attrs, x) && strings.EqualFold(x.Type, "STORED"), sqlx.Has(c.C.Attrs, &AutoIncrement{}):
+			return migrate.CostTableRewrite
+		case d.TiDB(), d.SupportsInstantAddColumn():
+			return migrate.CostMetadata
+		}
+		return migrate.CostTableRewrite
+	case *schema.ModifyColumn:
+		switch {
+		case c.Change.Is(schema.ChangeType) && (c.From.Type == nil || c.To.Type == nil || !d.inplaceType(t, c.From, c.To)):
+			return migrate.CostTableRewrite
+		// Changing the nullability of a column rebuilds the table in-place.
+		case c.Change.Is(schema.ChangeNull), c.Change.Is(schema.ChangeGenerated):
+			return migrate.CostTableRewrite
+		}
+		return migrate.CostMetadata
+	}
+	return migrate.CostUnknown
+}
+
+// inplaceType reports if the type of a column can be changed without rebuilding the table.
+func (d *Driver) inplaceType(t *schema.Table, from, to *schema.Column) bool {
+	switch fromT := from.Type.Type.(type) {
+	case *schema.StringType:
+		toT, ok := to.Type.Type.(*schema.StringType)
+		if !ok || fromT.T != TypeVarchar || toT.T != TypeVarchar || toT.Size < fromT.Size {
+			return false
+		}
+		// The size of a VARCHAR column can be increased in-place, as long as the number
+		// of length bytes is not changed: 1 byte for values up to 255 bytes, and 2 bytes
+		// for larger values.
+		n := d.charsetMaxLen(t, from)
+		return fromT.Size*n <= 255 == (toT.Size*n <= 255)
+	case *schema.EnumType:
+		toT, ok := to.Type.Type.(*schema.EnumType)
+		// Values can be appended in-place, as long as the storage size of the type is
+		// not changed: 1 byte for up to 255 values, and 2 bytes for larger enums.
+		return ok && appendedValues(fromT.Values, toT.Values) && len(fromT.Values) <= 255 == (len(toT.Values) <= 255)
+	case *SetType:
+		toT, ok := to.Type.Type.(*SetType)
+		return ok && appendedValues(fromT.Values, toT.Values) && setSize(len(fromT.Values)) == setSize(len(toT.Values))
+	}
+	return false
+}
+
+// charsetMaxLen returns the maximum number of bytes per character of the column charset.
+func (d *Driver) charsetMaxLen(t *schema.Table, c *schema.Column) int {
+	cs := &schema.Charset{V: d.charset}
+	switch {
+	case sqlx.Has(c.Attrs, cs), sqlx.Has(t.Attrs, cs):
+	case t.Schema != nil && sqlx.Has(t.Schema.Attrs, cs):
+	}
+	switch strings.ToLower(cs.V) {
+	case "ascii", "binary", "latin1":
+		return 1
+	case "ucs2":
+		return 2
+	case "utf8", "utf8mb3":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// setSize returns the storage size in bytes of a SET type with n members.
+func setSize(n int) int {
+	if b := (n + 7) / 8; b <= 4 {
+		return b
+	}
+	return 8
+}
+
+// appendedValues reports if the "to" values are the "from" values with new values appended.
+func appendedValues(from, to []string) bool {
+	if len(to) < len(from) {
+		return false
+	}
+	for i := range from {
+		if from[i] != to[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_EstimateCost(t *testing.T) {
+	var (
+		drv    = &Driver{conn: &conn{V: "8.0.30", charset: "utf8mb4"}}
+		drv57  = &Driver{conn: &conn{V: "5.7.40", charset: "utf8mb4"}}
+		maria  = &Driver{conn: &conn{V: "10.6.1-MariaDB", charset: "utf8mb4"}}
+		tbl    = schema.NewTable("t")
+		latin1 = schema.NewTable("t").AddAttrs(&schema.Charset{V: "latin1"})
+		col    = func(name string, t schema.Type, null bool) *schema.Column {
+			return schema.NewColumn(name).SetType(t).SetNull(null)
+		}
+		varchar = func(size int) *schema.StringType { return &schema.StringType{T: TypeVarchar, Size: size} }
+		enum    = func(vs ...string) *schema.EnumType { return &schema.EnumType{T: TypeEnum, Values: vs} }
+		modify  = func(from, to *schema.Column, k schema.ChangeKind) schema.Change {
+			return &schema.ModifyColumn{From: from, To: to, Change: k}
+		}
+	)
+	for _, tt := range []struct {
+		drv    *Driver
+		table  *schema.Table
+		change schema.Change
+		cost   migrate.Cost
+	}{
+		// Adding columns.
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInt}, false).SetDefault(&schema.Literal{V: "1"})}, cost: migrate.CostMetadata},
+		{drv: maria, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInt}, true)}, cost: migrate.CostMetadata},
+		{drv: drv57, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInt}, true)}, cost: migrate.CostTableRewrite},
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInt}, true).SetGeneratedExpr(&schema.GeneratedExpr{Expr: "a + 1", Type: "STORED"})}, cost: migrate.CostTableRewrite},
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInt}, true).SetGeneratedExpr(&schema.GeneratedExpr{Expr: "a + 1", Type: "VIRTUAL"})}, cost: migrate.CostMetadata},
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInt}, false).AddAttrs(&AutoIncrement{})}, cost: migrate.CostTableRewrite},
+		// Modifying columns.
+		{drv: drv, change: modify(col("c", varchar(10), true), col("c", varchar(60), true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", varchar(10), true), col("c", varchar(64), true), schema.ChangeType), cost: migrate.CostTableRewrite},
+		{drv: drv, table: latin1, change: modify(col("c", varchar(10), true), col("c", varchar(255), true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", varchar(10), true).AddAttrs(&schema.Charset{V: "latin1"}), col("c", varchar(255), true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", varchar(100), true), col("c", varchar(200), true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", varchar(20), true), col("c", varchar(10), true), schema.ChangeType), cost: migrate.CostTableRewrite},
+		{drv: drv, change: modify(col("c", enum("a", "b"), true), col("c", enum("a", "b", "c"), true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", enum("a", "b"), true), col("c", enum("b", "a"), true), schema.ChangeType), cost: migrate.CostTableRewrite},
+		{drv: drv, change: modify(col("c", &SetType{Values: []string{"a"}}, true), col("c", &SetType{Values: []string{"a", "b"}}, true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", &schema.IntegerType{T: TypeInt}, true), col("c", &schema.IntegerType{T: TypeBigInt}, true), schema.ChangeType), cost: migrate.CostTableRewrite},
+		{drv: drv, change: modify(col("c", varchar(10), false), col("c", varchar(10), true), schema.ChangeNull), cost: migrate.CostTableRewrite},
+		{drv: drv, change: modify(col("c", varchar(10), true), col("c", varchar(10), true).SetDefault(&schema.Literal{V: "'a'"}), schema.ChangeDefault), cost: migrate.CostMetadata},
+		// Default estimation.
+		{drv: drv, change: &schema.AddIndex{I: schema.NewIndex("i")}, cost: migrate.CostIndexBuild},
+		{drv: drv, change: &schema.DropColumn{C: col("c", varchar(10), true)}, cost: migrate.CostDataLoss},
+	} {
+		if tt.table == nil {
+			tt.table = tbl
+		}
+		c := &schema.ModifyTable{T: tt.table, Changes: []schema.Change{tt.change}}
+		require.Equal(t, tt.cost, tt.drv.EstimateCost(c), "change: %#v", tt.change)
+	}
+	require.Equal(t, migrate.CostUnknown, drv.EstimateCost(&schema.AddTable{T: tbl}))
+}
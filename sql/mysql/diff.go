@@ -77,6 +77,7 @@ func (d *diff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
 	if change := d.engineChange(from.Attrs, to.Attrs); change != noChange {
 		changes = append(changes, change)
 	}
+	changes = append(changes, customAttrChanges(from.Attrs, to.Attrs)...)
 	if !d.SupportsCheck() && sqlx.Has(to.Attrs, &schema.Check{}) {
 		return nil, fmt.Errorf("version %q does not support CHECK constraints", d.V)
 	}
@@ -86,7 +87,7 @@ func (d *diff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
 	// using "MODIFY COLUMN".
 	var checks []schema.Change
 	for _, c := range sqlx.CheckDiff(from, to, func(c1, c2 *schema.Check) bool {
-		return enforced(c1.Attrs) == enforced(c2.Attrs)
+		return c1.Enforced() == c2.Enforced()
 	}) {
 		drop, ok := c.(*schema.DropCheck)
 		if !ok || !strings.HasPrefix(drop.C.Expr, "json_valid") {
@@ -139,9 +140,42 @@ func (d *diff) ColumnChange(fromT *schema.Table, from, to *schema.Column) (schem
 	if changed {
 		change |= schema.ChangeCollate
 	}
+	// Custom attributes are not inspected from the database,
+	// and compared only if they exist in the current state.
+	if len(customAttrs.Custom(from.Attrs)) > 0 && customAttrs.Changed(from.Attrs, to.Attrs) {
+		change |= schema.ChangeAttr
+	}
 	return change, nil
 }
 
+// typeSizes holds the sizes of the types that are used for classifying type conversions.
+var typeSizes = map[string]int64{
+	TypeTinyInt:    1,
+	TypeSmallInt:   2,
+	TypeMediumInt:  3,
+	TypeInt:        4,
+	"integer":      4,
+	TypeBigInt:     8,
+	TypeFloat:      4,
+	TypeDouble:     8,
+	TypeReal:       8,
+	TypeChar:       1,
+	TypeBinary:     1,
+	TypeTinyText:   1<<8 - 1,
+	TypeText:       1<<16 - 1,
+	TypeMediumText: 1<<24 - 1,
+	TypeLongText:   1<<32 - 1,
+	TypeTinyBlob:   1<<8 - 1,
+	TypeBlob:       1<<16 - 1,
+	TypeMediumBlob: 1<<24 - 1,
+	TypeLongBlob:   1<<32 - 1,
+}
+
+// TypeConversion classifies the conversion of the column values, when its type is changed.
+func (*diff) TypeConversion(from, to *schema.Column) schema.TypeConversion {
+	return sqlx.ClassifyConversion(from.Type.Type, to.Type.Type, typeSizes)
+}
+
 // IsGeneratedIndexName reports if the index name was generated by the database.
 func (d *diff) IsGeneratedIndexName(_ *schema.Table, idx *schema.Index) bool {
 	// Auto-generated index names for functional/expression indexes. See.
@@ -421,15 +455,6 @@ func indexType(attr []schema.Attr) *IndexType {
 	return t
 }
 
-// enforced returns the ENFORCED attribute for the CHECK
-// constraint. A CHECK is ENFORCED if not state otherwise.
-func enforced(attr []schema.Attr) bool {
-	if e := (Enforced{}); sqlx.Has(attr, &e) {
-		return e.V
-	}
-	return true
-}
-
 // noChange describes a zero change.
 var noChange struct{ schema.Change }
 
@@ -477,7 +502,19 @@ func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 		toT := toT.(*SetType)
 		changed = !sqlx.ValuesEqual(fromT.Values, toT.Values)
 	default:
-		return false, &sqlx.UnsupportedTypeError{Type: fromT}
+		// Custom types are compared by their formatted definitions.
+		if _, ok, _ := customTypes.FormatType(fromT); !ok {
+			return false, &sqlx.UnsupportedTypeError{Type: fromT}
+		}
+		t1, err := FormatType(fromT)
+		if err != nil {
+			return false, err
+		}
+		t2, err := FormatType(toT)
+		if err != nil {
+			return false, err
+		}
+		changed = t1 != t2
 	}
 	return changed, nil
 }
@@ -526,16 +563,9 @@ func (d *diff) defaultChanged(from, to *schema.Column) (bool, error) {
 
 // generatedChanged reports if the generated expression of a column was changed.
 func (*diff) generatedChanged(from, to *schema.Column) (bool, error) {
-	var (
-		fromX, toX     schema.GeneratedExpr
-		fromHas, toHas = sqlx.Has(from.Attrs, &fromX), sqlx.Has(to.Attrs, &toX)
-	)
-	if !fromHas && !toHas || fromHas && toHas && sqlx.MayWrap(fromX.Expr) == sqlx.MayWrap(toX.Expr) && storedOrVirtual(fromX.Type) == storedOrVirtual(toX.Type) {
-		return false, nil
-	}
 	// Checking validity of the change is done
 	// by the planner (checkChangeGenerated).
-	return true, nil
+	return sqlx.GeneratedChanged(from, to, storedOrVirtual), nil
 }
 
 // equalIntValues report if the 2 int default values are ~equal.
@@ -678,3 +708,23 @@ func (d *diff) defaultCharset(attrs *[]schema.Attr) error {
 	}
 	return nil
 }
+
+// customAttrChanges returns the custom table options that were added or changed. Custom
+// attributes are not inspected from the database, and therefore, they are compared only if
+// they exist in the current state. Removed options are ignored, as they cannot be reset.
+func customAttrChanges(from, to []schema.Attr) []schema.Change {
+	exists := make(map[string]bool)
+	for _, c := range customAttrs.Custom(from) {
+		exists[c] = true
+	}
+	if len(exists) == 0 {
+		return nil
+	}
+	var changes []schema.Change
+	for _, a := range to {
+		if c, ok := customAttrs.Format(a); ok && c != "" && !exists[c] {
+			changes = append(changes, &schema.AddAttr{A: a})
+		}
+	}
+	return changes
+}
@@ -476,10 +476,10 @@ func TestDiff_TableDiff(t *testing.T) {
 				from: from,
 				to:   to,
 				wantChanges: []schema.Change{
+					&schema.ModifyIndex{From: from.Indexes[3], To: to.Indexes[3], Change: schema.ChangeParts},
 					&schema.ModifyIndex{From: from.Indexes[0], To: to.Indexes[0], Change: schema.ChangeUnique},
-					&schema.DropIndex{I: from.Indexes[1]},
 					&schema.ModifyIndex{From: from.Indexes[2], To: to.Indexes[2], Change: schema.ChangeParts},
-					&schema.ModifyIndex{From: from.Indexes[3], To: to.Indexes[3], Change: schema.ChangeParts},
+					&schema.DropIndex{I: from.Indexes[1]},
 					&schema.ModifyIndex{From: from.Indexes[4], To: to.Indexes[4], Change: schema.ChangeAttr},
 					&schema.AddIndex{I: to.Indexes[1]},
 				},
@@ -669,8 +669,8 @@ func TestDiff_SchemaDiff(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, []schema.Change{
 		&schema.ModifySchema{S: to, Changes: []schema.Change{&schema.ModifyAttr{From: from.Attrs[0], To: to.Attrs[0]}}},
-		&schema.ModifyTable{T: to.Tables[0], Changes: []schema.Change{&schema.AddColumn{C: to.Tables[0].Columns[0]}}},
 		&schema.DropTable{T: from.Tables[1]},
+		&schema.ModifyTable{T: to.Tables[0], Changes: []schema.Change{&schema.AddColumn{C: to.Tables[0].Columns[0]}}},
 		&schema.AddTable{T: to.Tables[1]},
 	}, changes)
 }
@@ -782,9 +782,9 @@ func TestDiff_RealmDiff(t *testing.T) {
 	changes, err := drv.RealmDiff(from, to)
 	require.NoError(t, err)
 	require.EqualValues(t, []schema.Change{
+		&schema.DropSchema{S: from.Schemas[1]},
 		&schema.ModifySchema{S: to.Schemas[0], Changes: []schema.Change{&schema.ModifyAttr{From: from.Schemas[0].Attrs[0], To: to.Schemas[0].Attrs[0]}}},
 		&schema.ModifyTable{T: to.Schemas[0].Tables[0], Changes: []schema.Change{&schema.AddColumn{C: to.Schemas[0].Tables[0].Columns[0]}}},
-		&schema.DropSchema{S: from.Schemas[1]},
 		&schema.AddSchema{S: to.Schemas[1]},
 		&schema.AddTable{T: to.Schemas[1].Tables[0]},
 	}, changes)
@@ -876,3 +876,108 @@ func TestSkipChanges(t *testing.T) {
 		require.IsType(t, &schema.DropColumn{}, changes[0].(*schema.ModifyTable).Changes[0])
 	})
 }
+
+func TestDiff_TypeConversion(t *testing.T) {
+	from := schema.NewTable("users").
+		SetSchema(schema.New("public")).
+		AddColumns(
+			schema.NewStringColumn("name", TypeVarchar, schema.StringSize(255)),
+			schema.NewIntColumn("age", TypeInt),
+			schema.NewStringColumn("bio", TypeText),
+			schema.NewIntColumn("score", TypeInt),
+		)
+	to := schema.NewTable("users").
+		SetSchema(schema.New("public")).
+		AddColumns(
+			schema.NewStringColumn("name", TypeVarchar, schema.StringSize(50)),
+			schema.NewIntColumn("age", TypeBigInt),
+			schema.NewJSONColumn("bio", TypeJSON),
+			schema.NewIntColumn("score", TypeInt).SetComment("score"),
+		)
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+	for i, c := range []schema.TypeConversion{schema.ConversionNarrowing, schema.ConversionWidening, schema.ConversionIncompatible, schema.ConversionUnknown} {
+		require.Equal(t, c, changes[i].(*schema.ModifyColumn).Conversion, changes[i].(*schema.ModifyColumn).To.Name)
+	}
+}
+
+func TestDiffOptions(t *testing.T) {
+	t.Run("SkipDrops", func(t *testing.T) {
+		from := schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("age", "int")),
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int")),
+		)
+		to := schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("name", "int")),
+		)
+		changes, err := DefaultDiff.SchemaDiff(from, to, schema.DiffSkipDrops())
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, []schema.Change{&schema.AddColumn{C: to.Tables[0].Columns[1]}}, changes[0].(*schema.ModifyTable).Changes)
+	})
+
+	t.Run("IgnoreAttrs", func(t *testing.T) {
+		from := schema.NewTable("users").
+			SetSchema(schema.New("public")).
+			SetComment("users table").
+			AddColumns(
+				schema.NewStringColumn("name", "varchar(255)").SetComment("name"),
+				schema.NewStringColumn("email", "varchar(255)").SetCharset("latin1").SetCollation("latin1_swedish_ci"),
+				schema.NewIntColumn("age", "int").SetComment("age"),
+			)
+		to := schema.NewTable("users").
+			SetSchema(schema.New("public")).
+			SetComment("all users").
+			AddColumns(
+				schema.NewStringColumn("name", "varchar(255)").SetComment("full name"),
+				schema.NewStringColumn("email", "varchar(255)").SetCharset("utf8mb4").SetCollation("utf8mb4_bin"),
+				schema.NewIntColumn("age", "bigint").SetComment("age in years"),
+			)
+		changes, err := DefaultDiff.TableDiff(from, to)
+		require.NoError(t, err)
+		require.Len(t, changes, 4)
+
+		changes, err = DefaultDiff.TableDiff(from, to, schema.DiffIgnoreComments())
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, "email", changes[0].(*schema.ModifyColumn).To.Name)
+		require.Equal(t, schema.ChangeCharset|schema.ChangeCollate, changes[0].(*schema.ModifyColumn).Change)
+		require.Equal(t, "age", changes[1].(*schema.ModifyColumn).To.Name)
+		require.Equal(t, schema.ChangeType, changes[1].(*schema.ModifyColumn).Change)
+
+		changes, err = DefaultDiff.TableDiff(from, to, schema.DiffIgnoreComments(), schema.DiffIgnoreCharsets())
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, "age", changes[0].(*schema.ModifyColumn).To.Name)
+
+		// Tables that were modified only by ignored attributes are not reported.
+		from.Columns[2].Type.Type = to.Columns[2].Type.Type
+		changes, err = DefaultDiff.SchemaDiff(from.Schema, to.Schema, schema.DiffIgnoreComments(), schema.DiffIgnoreCharsets())
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("CaseInsensitive", func(t *testing.T) {
+		from := schema.New("public").AddTables(
+			schema.NewTable("Users").AddColumns(schema.NewIntColumn("ID", "int")),
+		)
+		from.Tables[0].AddIndexes(schema.NewIndex("users_id").AddColumns(from.Tables[0].Columns[0]))
+		to := schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("age", "int")),
+		)
+		to.Tables[0].AddIndexes(schema.NewIndex("users_id").AddColumns(to.Tables[0].Columns[0]))
+		changes, err := DefaultDiff.SchemaDiff(from, to)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.IsType(t, &schema.DropTable{}, changes[0])
+		require.IsType(t, &schema.AddTable{}, changes[1])
+
+		changes, err = DefaultDiff.SchemaDiff(from, to, schema.DiffCaseInsensitive())
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, []schema.Change{&schema.AddColumn{C: to.Tables[0].Columns[1]}}, changes[0].(*schema.ModifyTable).Changes)
+		require.Equal(t, "Users", from.Tables[0].Name)
+		require.Equal(t, "ID", from.Tables[0].Columns[0].Name)
+	})
+}
@@ -42,6 +42,8 @@ type (
 	}
 )
 
+var _ schema.Normalizer = (*Driver)(nil)
+
 // DriverName holds the name used for registration.
 const DriverName = "mysql"
 
@@ -58,21 +60,20 @@ func init() {
 
 // Open opens a new MySQL driver.
 func Open(db schema.ExecQuerier) (migrate.Driver, error) {
-	c := &conn{ExecQuerier: db}
-	rows, err := db.QueryContext(context.Background(), variablesQuery)
+	c, err := connect(db)
 	if err != nil {
-		return nil, fmt.Errorf("mysql: query system variables: %w", err)
-	}
-	if err := sqlx.ScanOne(rows, &c.V, &c.collate, &c.charset, &c.lcnames); err != nil {
-		return nil, fmt.Errorf("mysql: scan system variables: %w", err)
+		return nil, err
 	}
-	if c.TiDB() {
+	switch {
+	case c.TiDB():
 		return &Driver{
 			conn:        c,
 			Differ:      &sqlx.Diff{DiffDriver: &tdiff{diff{conn: c}}},
 			Inspector:   &tinspect{inspect{c}},
 			PlanApplier: &tplanApply{planApply{c}},
 		}, nil
+	case c.SingleStore():
+		return singleStoreDriver(c), nil
 	}
 	return &Driver{
 		conn:        c,
@@ -82,13 +83,31 @@ func Open(db schema.ExecQuerier) (migrate.Driver, error) {
 	}, nil
 }
 
-func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+// connect returns a new connection with its system variables loaded.
+func connect(db schema.ExecQuerier) (*conn, error) {
+	c := &conn{ExecQuerier: db}
+	rows, err := db.QueryContext(context.Background(), variablesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("mysql: query system variables: %w", err)
+	}
+	if err := sqlx.ScanOne(rows, &c.V, &c.collate, &c.charset, &c.lcnames); err != nil {
+		return nil, fmt.Errorf("mysql: scan system variables: %w", err)
+	}
+	return c, nil
+}
+
+func opener(ctx context.Context, u *url.URL) (*sqlclient.Client, error) {
+	return openURL(ctx, u, Open)
+}
+
+// openURL opens a new client for the given URL using the driver open function.
+func openURL(_ context.Context, u *url.URL, open func(schema.ExecQuerier) (migrate.Driver, error)) (*sqlclient.Client, error) {
 	ur := parser{}.ParseURL(u)
 	db, err := sql.Open(DriverName, ur.DSN)
 	if err != nil {
 		return nil, err
 	}
-	drv, err := Open(db)
+	drv, err := open(db)
 	if err != nil {
 		if cerr := db.Close(); cerr != nil {
 			err = fmt.Errorf("%w: %v", err, cerr)
@@ -140,6 +159,46 @@ func (d *Driver) Lock(ctx context.Context, name string, timeout time.Duration) (
 	}, nil
 }
 
+// SetTimeouts implements migrate.TimeoutSetter by setting the lock_wait_timeout variable, which
+// applies to metadata locks acquired by DDL statements. MySQL does not support limiting the execution
+// time of DDL statements, and the statement timeout is ignored. The variable has no transaction scope.
+// Hence, outside of transactions, the driver is pinned to a dedicated connection, and in both cases,
+// the returned function restores the previous value of the variable (and releases the connection).
+func (d *Driver) SetTimeouts(ctx context.Context, _, lock time.Duration) (migrate.RestoreFunc, error) {
+	if lock <= 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+	release := func() error { return nil }
+	if !sqlx.InTx(d.ExecQuerier) {
+		var err error
+		if release, err = sqlx.PinConn(ctx, &d.ExecQuerier); err != nil {
+			return nil, err
+		}
+	}
+	var prev int64
+	rows, err := d.QueryContext(ctx, "SELECT @@SESSION.lock_wait_timeout")
+	if err == nil {
+		err = sqlx.ScanOne(rows, &prev)
+	}
+	// The variable is set in seconds, and the minimum value is 1.
+	if secs := int64((lock + time.Second - 1) / time.Second); err == nil {
+		_, err = d.ExecContext(ctx, fmt.Sprintf("SET SESSION lock_wait_timeout = %d", secs))
+	}
+	if err != nil {
+		if err1 := release(); err1 != nil {
+			err = fmt.Errorf("%w: release connection: %v", err, err1)
+		}
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		_, err := d.ExecContext(ctx, fmt.Sprintf("SET SESSION lock_wait_timeout = %d", prev))
+		if err1 := release(); err1 != nil && err == nil {
+			err = err1
+		}
+		return err
+	}, nil
+}
+
 // Snapshot implements migrate.Snapshoter.
 func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
 	// If the connection is bound to a schema, we can restore the state if the schema has no tables.
@@ -217,6 +276,26 @@ func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error
 	return nil
 }
 
+// Capabilities implements migrate.Capable.
+func (d *Driver) Capabilities() migrate.Capabilities {
+	c := migrate.Capabilities{
+		Check:        d.SupportsCheck(),
+		ForeignKey:   true,
+		DropColumn:   true,
+		RenameColumn: true,
+		ModifyColumn: true,
+		OnlineIndex:  true,
+	}
+	switch {
+	case d.TiDB():
+		// CHECK constraints and foreign keys are parsed but not enforced by TiDB.
+		c.Check, c.ForeignKey = false, false
+	case d.SingleStore():
+		c.Check, c.ForeignKey, c.ModifyColumn = false, false, false
+	}
+	return c
+}
+
 // Version returns the version of the connected database.
 func (d *Driver) Version() string {
 	return string(d.conn.V)
@@ -398,7 +477,7 @@ const (
 	defaultGen    = "default_generated"
 	autoIncrement = "auto_increment"
 
-	virtual    = "VIRTUAL"
-	stored     = "STORED"
+	virtual    = schema.GeneratedVirtual
+	stored     = schema.GeneratedStored
 	persistent = "PERSISTENT"
 )
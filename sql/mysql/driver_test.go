@@ -197,6 +197,21 @@ func TestDriver_Version(t *testing.T) {
 	require.Equal(t, "8.0.13", drv.(vr).Version())
 }
 
+func TestDriver_Capabilities(t *testing.T) {
+	for v, check := range map[string]bool{"5.7.32": false, "8.0.16": true, "10.2.1-MariaDB": true, "5.7.25-TiDB-v6.1.0": false} {
+		db, m, err := sqlmock.New()
+		require.NoError(t, err)
+		mock{m}.version(v)
+		drv, err := Open(db)
+		require.NoError(t, err)
+		c, ok := migrate.DriverCapabilities(drv)
+		require.True(t, ok)
+		require.False(t, c.TransactionalDDL)
+		require.Equal(t, check, c.Check, v)
+		require.True(t, c.DropColumn)
+	}
+}
+
 type mockInspector struct {
 	schema.Inspector
 	realm  *schema.Realm
@@ -223,3 +238,44 @@ func (m *mockOpener) Conn(ctx context.Context) (*sql.Conn, error) {
 	m.opened++
 	return m.DB.Conn(ctx)
 }
+
+func TestDriver_SetTimeouts(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv := &Driver{conn: &conn{ExecQuerier: db}}
+	var d migrate.TimeoutSetter = drv
+	// Statement timeout is not supported for DDLs.
+	restore, err := d.SetTimeouts(context.Background(), time.Second, 0)
+	require.NoError(t, err)
+	require.NoError(t, restore(context.Background()))
+
+	// Outside of transactions, the driver is pinned to a dedicated connection.
+	m.ExpectQuery(sqltest.Escape("SELECT @@SESSION.lock_wait_timeout")).
+		WillReturnRows(sqlmock.NewRows([]string{"lock_wait_timeout"}).AddRow(31536000))
+	m.ExpectExec(sqltest.Escape("SET SESSION lock_wait_timeout = 2")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SET SESSION lock_wait_timeout = 31536000")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	restore, err = d.SetTimeouts(context.Background(), time.Second, 1500*time.Millisecond)
+	require.NoError(t, err)
+	_, ok := drv.ExecQuerier.(*sql.Conn)
+	require.True(t, ok, "driver should be pinned to a dedicated connection")
+	require.NoError(t, restore(context.Background()))
+	require.Equal(t, db, drv.ExecQuerier)
+
+	// In transactions, the previous value is restored as well.
+	m.ExpectBegin()
+	m.ExpectQuery(sqltest.Escape("SELECT @@SESSION.lock_wait_timeout")).
+		WillReturnRows(sqlmock.NewRows([]string{"lock_wait_timeout"}).AddRow(50))
+	m.ExpectExec(sqltest.Escape("SET SESSION lock_wait_timeout = 1")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SET SESSION lock_wait_timeout = 50")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	d = &Driver{conn: &conn{ExecQuerier: tx}}
+	restore, err = d.SetTimeouts(context.Background(), 0, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, restore(context.Background()))
+	require.NoError(t, m.ExpectationsWereMet())
+}
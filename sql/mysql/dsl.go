@@ -0,0 +1,46 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import "ariga.io/atlas/sql/schema"
+
+// The functions below extend the schema builder API (e.g. schema.NewTable) with
+// columns of MySQL-specific types and attributes. For example:
+//
+//	users := schema.NewTable("users").
+//		AddColumns(
+//			mysql.NewAutoIncrementColumn("id", mysql.TypeBigInt),
+//			mysql.NewSetColumn("roles", "admin", "user"),
+//		)
+
+// NewAutoIncrementColumn creates a new IntegerType column with the AUTO_INCREMENT attribute.
+func NewAutoIncrementColumn(name, typ string) *schema.Column {
+	return schema.NewIntColumn(name, typ).
+		AddAttrs(&AutoIncrement{})
+}
+
+// NewSetColumn creates a new SET column with the given values.
+func NewSetColumn(name string, values ...string) *schema.Column {
+	return schema.NewColumn(name).
+		SetType(&SetType{Values: values})
+}
+
+// NewNullSetColumn creates a new nullable SET column with the given values.
+func NewNullSetColumn(name string, values ...string) *schema.Column {
+	return NewSetColumn(name, values...).
+		SetNull(true)
+}
+
+// NewBitColumn creates a new BIT column with the given size (number of bits).
+func NewBitColumn(name string, size int) *schema.Column {
+	return schema.NewColumn(name).
+		SetType(&BitType{T: TypeBit, Size: size})
+}
+
+// NewNullBitColumn creates a new nullable BIT column with the given size.
+func NewNullBitColumn(name string, size int) *schema.Column {
+	return NewBitColumn(name, size).
+		SetNull(true)
+}
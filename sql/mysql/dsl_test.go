@@ -0,0 +1,28 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSL_Columns(t *testing.T) {
+	users := schema.NewTable("users").
+		SetSchema(schema.New("s")).
+		AddColumns(
+			NewAutoIncrementColumn("id", TypeBigInt),
+			NewSetColumn("roles", "admin", "user"),
+			NewNullBitColumn("flags", 8),
+		)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: users}})
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE `s`.`users` (`id` bigint NOT NULL AUTO_INCREMENT, `roles` set('admin','user') NOT NULL, `flags` bit(8) NULL, PRIMARY KEY (`id`))", plan.Changes[0].Cmd)
+}
@@ -0,0 +1,149 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"fmt"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// addFunc appends the change for creating the function.
+func (s *state) addFunc(add *schema.AddFunc) error {
+	create, err := s.createFunc(add.F)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: s.Build("DROP FUNCTION").SchemaResource(add.F.Schema, add.F.Name).String(),
+		Comment: fmt.Sprintf("create function %q", add.F.Name),
+	})
+	return nil
+}
+
+// dropFunc appends the change for dropping the function.
+func (s *state) dropFunc(drop *schema.DropFunc) error {
+	create, err := s.createFunc(drop.F)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     s.Build("DROP FUNCTION").SchemaResource(drop.F.Schema, drop.F.Name).String(),
+		Reverse: create,
+		Comment: fmt.Sprintf("drop function %q", drop.F.Name),
+	})
+	return nil
+}
+
+// modifyFunc appends the changes for modifying the function. MySQL
+// cannot alter the body of a function, and therefore, it is recreated.
+func (s *state) modifyFunc(modify *schema.ModifyFunc) error {
+	if err := s.dropFunc(&schema.DropFunc{F: modify.From}); err != nil {
+		return err
+	}
+	return s.addFunc(&schema.AddFunc{F: modify.To})
+}
+
+// addProc appends the change for creating the procedure.
+func (s *state) addProc(add *schema.AddProc) error {
+	create, err := s.createProc(add.P)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: s.Build("DROP PROCEDURE").SchemaResource(add.P.Schema, add.P.Name).String(),
+		Comment: fmt.Sprintf("create procedure %q", add.P.Name),
+	})
+	return nil
+}
+
+// dropProc appends the change for dropping the procedure.
+func (s *state) dropProc(drop *schema.DropProc) error {
+	create, err := s.createProc(drop.P)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     s.Build("DROP PROCEDURE").SchemaResource(drop.P.Schema, drop.P.Name).String(),
+		Reverse: create,
+		Comment: fmt.Sprintf("drop procedure %q", drop.P.Name),
+	})
+	return nil
+}
+
+// modifyProc appends the changes for modifying the procedure. Similar
+// to functions, procedures are dropped and created.
+func (s *state) modifyProc(modify *schema.ModifyProc) error {
+	if err := s.dropProc(&schema.DropProc{P: modify.From}); err != nil {
+		return err
+	}
+	return s.addProc(&schema.AddProc{P: modify.To})
+}
+
+// createFunc returns the CREATE FUNCTION statement of the function.
+func (s *state) createFunc(f *schema.Func) (string, error) {
+	if f.Ret == nil {
+		return "", fmt.Errorf("mysql: missing return type for function %q", f.Name)
+	}
+	b := s.Build("CREATE FUNCTION").SchemaResource(f.Schema, f.Name)
+	if err := funcArgs(b, f.Args, false); err != nil {
+		return "", fmt.Errorf("mysql: function %q: %w", f.Name, err)
+	}
+	t, err := FormatType(f.Ret)
+	if err != nil {
+		return "", fmt.Errorf("mysql: format return type of function %q: %w", f.Name, err)
+	}
+	return b.P("RETURNS", t, f.Body).String(), nil
+}
+
+// createProc returns the CREATE PROCEDURE statement of the procedure.
+func (s *state) createProc(p *schema.Proc) (string, error) {
+	b := s.Build("CREATE PROCEDURE").SchemaResource(p.Schema, p.Name)
+	if err := funcArgs(b, p.Args, true); err != nil {
+		return "", fmt.Errorf("mysql: procedure %q: %w", p.Name, err)
+	}
+	return b.P(p.Body).String(), nil
+}
+
+// funcArgs writes the arguments of a function or a procedure to the builder.
+// MySQL does not support default values, and only procedures accept modes.
+func funcArgs(b *sqlx.Builder, args []*schema.FuncArg, modes bool) (err error) {
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(args, func(i int, b *sqlx.Builder) {
+			a := args[i]
+			switch {
+			case err != nil:
+				return
+			case a.Name == "":
+				err = fmt.Errorf("missing name for argument %d", i)
+			case a.Default != nil:
+				err = fmt.Errorf("default value is not supported for argument %q", a.Name)
+			case a.Mode == schema.FuncArgModeVariadic, !modes && a.Mode != "" && a.Mode != schema.FuncArgModeIn:
+				err = fmt.Errorf("unsupported mode %s for argument %q", a.Mode, a.Name)
+			}
+			if err != nil {
+				return
+			}
+			if modes && a.Mode != "" {
+				b.P(string(a.Mode))
+			}
+			t, err1 := FormatType(a.Type)
+			if err1 != nil {
+				err = fmt.Errorf("format type of argument %q: %w", a.Name, err1)
+				return
+			}
+			b.Ident(a.Name).P(t)
+		})
+	})
+	return err
+}
@@ -45,7 +45,7 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 			}
 		}
 	}
-	return sqlx.ExcludeRealm(r, opts.Exclude)
+	return sqlx.FilterRealm(r, opts)
 }
 
 // InspectSchema returns schema descriptions of the tables in the given schema.
@@ -76,7 +76,7 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 			return nil, err
 		}
 	}
-	return sqlx.ExcludeSchema(r.Schemas[0], opts.Exclude)
+	return sqlx.FilterSchema(r.Schemas[0], opts)
 }
 
 func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
@@ -816,10 +816,9 @@ type (
 	}
 
 	// Enforced attribute defines the ENFORCED flag for CHECK constraint.
-	Enforced struct {
-		schema.Attr
-		V bool // V indicates if the CHECK is enforced or not.
-	}
+	//
+	// Deprecated: use schema.Enforced instead.
+	Enforced = schema.Enforced
 
 	// The DisplayWidth represents a display width of an integer type.
 	DisplayWidth struct {
@@ -872,6 +871,13 @@ type (
 	}
 )
 
+func init() {
+	schema.RegisterJSON(
+		&AutoIncrement{}, &CreateOptions{}, &CreateStmt{}, &Engine{}, &OnUpdate{}, &SubPart{}, &DisplayWidth{},
+		&ZeroFill{}, &IndexType{}, &IndexParser{}, &BitType{}, &SetType{}, &ShardKey{}, &SortKey{}, &Storage{},
+	)
+}
+
 // addIndex adds an index to the list of indexes
 // that needs further processing.
 func (s *showTable) addFullText(idx *schema.Index) {
@@ -94,6 +94,16 @@ func (v V) SupportsViewUsage() bool {
 	return !v.Maria() && v.GTE("8.0.13")
 }
 
+// SupportsInstantAddColumn reports if the version supports adding
+// columns instantly (ALGORITHM=INSTANT), without rebuilding the table.
+func (v V) SupportsInstantAddColumn() bool {
+	u := "8.0.12"
+	if v.Maria() {
+		u = "10.3.2"
+	}
+	return v.GTE(u)
+}
+
 // CharsetToCollate returns the mapping from charset to its default collation.
 func (v V) CharsetToCollate(conn schema.ExecQuerier) (map[string]string, error) {
 	name := "is/charset2collate"
@@ -154,6 +164,11 @@ func (v V) TiDB() bool {
 	return strings.Index(string(v), "TiDB") > 0
 }
 
+// SingleStore reports if the MySQL version is SingleStore (formerly MemSQL).
+func (v V) SingleStore() bool {
+	return strings.Contains(string(v), "SingleStore") || strings.Contains(string(v), "MemSQL")
+}
+
 // Compare returns an integer comparing two versions according to
 // semantic version precedence.
 func (v V) Compare(w string) int {
@@ -47,6 +47,7 @@ func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema
 	if err := sqlx.SetReversible(&s.Plan); err != nil {
 		return nil, err
 	}
+	sqlx.Annotate(&s.Plan, s.Annotations)
 	return &s.Plan, nil
 }
 
@@ -66,6 +67,30 @@ type state struct {
 	migrate.PlanOptions
 }
 
+// customAttrs holds the custom attributes that were registered by RegisterAttr.
+var customAttrs = make(sqlx.AttrFormatters)
+
+// RegisterAttr registers a function for formatting custom attributes of the same type
+// as a (i.e. table and column options that are not modeled by Atlas) when they are planned
+// in table and column definitions. schema.RawClause attributes are formatted as is, and
+// do not require registration. For example:
+//
+//	type Compression struct {
+//		schema.Attr
+//		V string
+//	}
+//
+//	mysql.RegisterAttr(&Compression{}, func(a schema.Attr) string {
+//		return "COMPRESSION " + a.(*Compression).V
+//	})
+//
+// Since custom attributes are not inspected from the database, changes to them are
+// detected only if they exist in the current state (e.g. when comparing two desired
+// states), and removed table options are ignored. RegisterAttr is not safe for concurrent use, and should be called on initialization.
+func RegisterAttr(a schema.Attr, f func(schema.Attr) string) {
+	customAttrs.Register(a, f)
+}
+
 // plan builds the migration plan for applying the
 // given changes on the attached connection.
 func (s *state) plan(changes []schema.Change) error {
@@ -82,7 +107,7 @@ func (s *state) plan(changes []schema.Change) error {
 	if err != nil {
 		return err
 	}
-	var views []schema.Change
+	var views, funcs, dropF, trigs []schema.Change
 	for _, c := range planned {
 		switch c := c.(type) {
 		case *schema.AddTable:
@@ -95,6 +120,12 @@ func (s *state) plan(changes []schema.Change) error {
 			s.renameTable(c)
 		case *schema.AddView, *schema.DropView, *schema.ModifyView, *schema.RenameView:
 			views = append(views, c)
+		case *schema.AddTrigger, *schema.DropTrigger, *schema.ModifyTrigger:
+			trigs = append(trigs, c)
+		case *schema.AddFunc, *schema.ModifyFunc, *schema.AddProc, *schema.ModifyProc:
+			funcs = append(funcs, c)
+		case *schema.DropFunc, *schema.DropProc:
+			dropF = append(dropF, c)
 		default:
 			err = fmt.Errorf("unsupported change %T", c)
 		}
@@ -117,6 +148,48 @@ func (s *state) plan(changes []schema.Change) error {
 			s.renameView(c)
 		}
 	}
+	// Functions and procedures are created after the objects they may
+	// depend on, and before the triggers that may call them.
+	for _, c := range funcs {
+		switch c := c.(type) {
+		case *schema.AddFunc:
+			err = s.addFunc(c)
+		case *schema.ModifyFunc:
+			err = s.modifyFunc(c)
+		case *schema.AddProc:
+			err = s.addProc(c)
+		case *schema.ModifyProc:
+			err = s.modifyProc(c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Triggers are planned after the tables they are defined on.
+	for _, c := range trigs {
+		switch c := c.(type) {
+		case *schema.AddTrigger:
+			err = s.addTrigger(c)
+		case *schema.DropTrigger:
+			err = s.dropTrigger(c)
+		case *schema.ModifyTrigger:
+			err = s.modifyTrigger(c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range dropF {
+		switch c := c.(type) {
+		case *schema.DropFunc:
+			err = s.dropFunc(c)
+		case *schema.DropProc:
+			err = s.dropProc(c)
+		}
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -233,8 +306,12 @@ func (s *state) modifySchema(modify *schema.ModifySchema) error {
 func (s *state) addTable(add *schema.AddTable) error {
 	var (
 		errs []string
-		b    = s.Build("CREATE TABLE")
+		b    = s.Build("CREATE")
 	)
+	if s.SingleStore() && storage(add.T.Attrs).V == StorageRowstore {
+		b.P(StorageRowstore)
+	}
+	b.P("TABLE")
 	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
 		b.P("IF NOT EXISTS")
 	}
@@ -271,6 +348,9 @@ func (s *state) addTable(add *schema.AddTable) error {
 				s.check(b, c)
 			}
 		}
+		if s.SingleStore() {
+			singleStoreTable(b, add.T)
+		}
 	})
 	if len(errs) > 0 {
 		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
@@ -687,6 +767,10 @@ func (s *state) tableAttr(b *sqlx.Builder, c schema.Change, attrs ...schema.Attr
 			b.P("COLLATE", a.V)
 		case *schema.Comment:
 			b.P("COMMENT", quote(a.Text))
+		default:
+			if c, ok := customAttrs.Format(a); ok {
+				b.P(c)
+			}
 		}
 	}
 }
@@ -722,6 +806,10 @@ func (*state) attr(b *sqlx.Builder, attrs ...schema.Attr) {
 			b.P("COLLATE", a.V)
 		case *schema.Comment:
 			b.P("COMMENT", quote(a.Text))
+		default:
+			if c, ok := customAttrs.Format(a); ok {
+				b.P(c)
+			}
 		}
 	}
 }
@@ -750,6 +838,39 @@ func (s *state) columnDefault(b *sqlx.Builder, c *schema.Column) {
 	}
 }
 
+// ValidateDefault reports an error if the column has a literal default value that is not
+// supported by its type, as BLOB, TEXT, GEOMETRY and JSON columns can have only expression
+// default values. It can be used as the driver-specific validation of schema.Validate:
+//
+//	err := schema.Validate(r, schema.ValidateColumnDefault(mysql.ValidateDefault))
+func ValidateDefault(_ *schema.Table, c *schema.Column) error {
+	x, ok := c.Default.(*schema.Literal)
+	if !ok || c.Type == nil {
+		return nil
+	}
+	var typ string
+	switch t := c.Type.Type.(type) {
+	case *schema.BinaryType:
+		switch t.T {
+		case TypeTinyBlob, TypeBlob, TypeMediumBlob, TypeLongBlob:
+			typ = t.T
+		}
+	case *schema.StringType:
+		switch t.T {
+		case TypeTinyText, TypeText, TypeMediumText, TypeLongText:
+			typ = t.T
+		}
+	case *schema.JSONType:
+		typ = t.T
+	case *schema.SpatialType:
+		typ = t.T
+	}
+	if typ != "" {
+		return fmt.Errorf("%s column cannot have a literal default value %s (use an expression instead)", typ, x.V)
+	}
+	return nil
+}
+
 // Build instantiates a new builder and writes the given phrase to it.
 func (s *state) Build(phrases ...string) *sqlx.Builder {
 	b := &sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`', Schema: s.SchemaQualifier, Indent: s.Indent}
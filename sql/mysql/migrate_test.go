@@ -6,6 +6,7 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"strings"
 	"testing"
@@ -188,6 +189,76 @@ func TestMigrate_DetachCycles(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMigrate_ApplyChangesDryRun(t *testing.T) {
+	drv, mk, err := newMigrate("8.0.13")
+	require.NoError(t, err)
+	var plan *migrate.Plan
+	err = drv.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddSchema{S: &schema.Schema{Name: "test"}},
+		&schema.DropTable{T: &schema.Table{Name: "users", Columns: []*schema.Column{{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}}}}}},
+	}, migrate.ApplyDryRun(func(p *migrate.Plan) { plan = p }))
+	require.NoError(t, err)
+	require.NotNil(t, plan)
+	require.False(t, plan.Transactional)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, "CREATE DATABASE `test`", plan.Changes[0].Cmd)
+	require.Equal(t, "DROP TABLE `users`", plan.Changes[1].Cmd)
+	require.NoError(t, mk.ExpectationsWereMet())
+}
+
+func TestMigrate_ApplyChangesApprover(t *testing.T) {
+	drv, mk, err := newMigrate("8.0.13")
+	require.NoError(t, err)
+	changes := []schema.Change{
+		&schema.AddSchema{S: &schema.Schema{Name: "test"}},
+		&schema.DropTable{T: &schema.Table{Name: "users", Columns: []*schema.Column{{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}}}}}},
+	}
+	err = drv.ApplyChanges(context.Background(), changes, migrate.ApplyWithApprover(migrate.ApproverFunc(func(_ context.Context, p *migrate.Plan) (*migrate.Approval, error) {
+		require.Len(t, p.Changes, 2)
+		return &migrate.Approval{Decision: migrate.PlanDenied, Reason: "dropping tables is not allowed"}, nil
+	})))
+	require.EqualError(t, err, `sql/migrate: plan "apply" was denied: dropping tables is not allowed`)
+
+	// Only the modified plan is applied.
+	mk.ExpectExec(sqltest.Escape("CREATE DATABASE `test`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = drv.ApplyChanges(context.Background(), changes, migrate.ApplyWithApprover(migrate.ApproverFunc(func(_ context.Context, p *migrate.Plan) (*migrate.Approval, error) {
+		p.Changes = p.Changes[:1]
+		return &migrate.Approval{Decision: migrate.PlanModified, Plan: p}, nil
+	})))
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+}
+
+func TestMigrate_ApplyChangesLogger(t *testing.T) {
+	drv, mk, err := newMigrate("8.0.13")
+	require.NoError(t, err)
+	mk.ExpectExec(sqltest.Escape("CREATE DATABASE `test`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mk.ExpectExec(sqltest.Escape("DROP TABLE `users`")).
+		WillReturnError(errors.New("table is locked"))
+	var logs []migrate.LogEntry
+	err = drv.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddSchema{S: &schema.Schema{Name: "test"}},
+		&schema.DropTable{T: &schema.Table{Name: "users", Columns: []*schema.Column{{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "bigint"}}}}}},
+	}, migrate.ApplyWithLogger(logFunc(func(e migrate.LogEntry) { logs = append(logs, e) })))
+	require.Error(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	require.Len(t, logs, 4)
+	require.Equal(t, migrate.LogStmt{SQL: "CREATE DATABASE `test`"}, logs[0])
+	done, ok := logs[1].(migrate.LogStmtDone)
+	require.True(t, ok)
+	require.Equal(t, "CREATE DATABASE `test`", done.SQL)
+	require.Equal(t, int64(1), done.RowsAffected)
+	require.Nil(t, done.File)
+	require.Equal(t, migrate.LogStmt{SQL: "DROP TABLE `users`"}, logs[2])
+	require.Equal(t, migrate.LogError{SQL: "DROP TABLE `users`", Error: errors.New("table is locked")}, logs[3])
+}
+
+type logFunc func(migrate.LogEntry)
+
+func (f logFunc) Log(e migrate.LogEntry) { f(e) }
+
 func TestPlanChanges(t *testing.T) {
 	tests := []struct {
 		version  string
@@ -228,6 +299,97 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			changes: func() []schema.Change {
+				users := schema.NewTable("users").SetSchema(schema.New("test")).AddColumns(schema.NewIntColumn("id", "int"))
+				users.AddTriggers(
+					&schema.Trigger{Name: "users_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, For: schema.TriggerForRow, Body: "INSERT INTO logs VALUES (NEW.id)"},
+					&schema.Trigger{Name: "users_bu", ActionTime: schema.TriggerTimeBefore, Events: []schema.TriggerEvent{schema.TriggerEventUpdate}, Body: "SET NEW.id = OLD.id"},
+					&schema.Trigger{Name: "users_bu", ActionTime: schema.TriggerTimeBefore, Events: []schema.TriggerEvent{schema.TriggerEventUpdate}, Body: "BEGIN\n  SET NEW.id = OLD.id + 1;\nEND"},
+				)
+				return []schema.Change{
+					&schema.AddTrigger{T: users.Triggers[0]},
+					&schema.ModifyTrigger{From: users.Triggers[1], To: users.Triggers[2]},
+				}
+			}(),
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE TRIGGER `test`.`users_ai` AFTER INSERT ON `test`.`users` FOR EACH ROW INSERT INTO logs VALUES (NEW.id)",
+						Reverse: "DROP TRIGGER `test`.`users_ai`",
+					},
+					{
+						Cmd:     "DROP TRIGGER `test`.`users_bu`",
+						Reverse: "CREATE TRIGGER `test`.`users_bu` BEFORE UPDATE ON `test`.`users` FOR EACH ROW SET NEW.id = OLD.id",
+					},
+					{
+						Cmd:     "CREATE TRIGGER `test`.`users_bu` BEFORE UPDATE ON `test`.`users` FOR EACH ROW BEGIN\n  SET NEW.id = OLD.id + 1;\nEND",
+						Reverse: "DROP TRIGGER `test`.`users_bu`",
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTrigger{T: &schema.Trigger{Name: "t", Table: schema.NewTable("users"), ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert, schema.TriggerEventDelete}, Body: "SET @a = 1"}},
+			},
+			// MySQL triggers are fired by exactly one event.
+			wantErr: true,
+		},
+		{
+			changes: func() []schema.Change {
+				test := schema.New("test")
+				add := &schema.Func{
+					Name:   "add",
+					Schema: test,
+					Args:   []*schema.FuncArg{{Name: "a", Type: &schema.IntegerType{T: "int"}}, {Name: "b", Type: &schema.IntegerType{T: "int"}}},
+					Ret:    &schema.IntegerType{T: "int"},
+					Body:   "DETERMINISTIC RETURN a + b",
+				}
+				add2 := *add
+				add2.Body = "DETERMINISTIC RETURN a + b + 1"
+				log := &schema.Proc{
+					Name:   "log",
+					Schema: test,
+					Args:   []*schema.FuncArg{{Name: "msg", Type: &schema.StringType{T: "varchar", Size: 255}}, {Name: "n", Type: &schema.IntegerType{T: "int"}, Mode: schema.FuncArgModeOut}},
+					Body:   "BEGIN\n  INSERT INTO logs VALUES (msg);\n  SET n = 1;\nEND",
+				}
+				return []schema.Change{
+					&schema.ModifyFunc{From: add, To: &add2},
+					&schema.AddProc{P: log},
+					&schema.DropFunc{F: add},
+				}
+			}(),
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "DROP FUNCTION `test`.`add`",
+						Reverse: "CREATE FUNCTION `test`.`add` (`a` int, `b` int) RETURNS int DETERMINISTIC RETURN a + b",
+					},
+					{
+						Cmd:     "CREATE FUNCTION `test`.`add` (`a` int, `b` int) RETURNS int DETERMINISTIC RETURN a + b + 1",
+						Reverse: "DROP FUNCTION `test`.`add`",
+					},
+					{
+						Cmd:     "CREATE PROCEDURE `test`.`log` (`msg` varchar(255), OUT `n` int) BEGIN\n  INSERT INTO logs VALUES (msg);\n  SET n = 1;\nEND",
+						Reverse: "DROP PROCEDURE `test`.`log`",
+					},
+					{
+						Cmd:     "DROP FUNCTION `test`.`add`",
+						Reverse: "CREATE FUNCTION `test`.`add` (`a` int, `b` int) RETURNS int DETERMINISTIC RETURN a + b",
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddFunc{F: &schema.Func{Name: "f", Schema: schema.New("test"), Args: []*schema.FuncArg{{Name: "a", Type: &schema.IntegerType{T: "int"}, Mode: schema.FuncArgModeOut}}, Ret: &schema.IntegerType{T: "int"}, Body: "RETURN 1"}},
+			},
+			// MySQL functions accept only IN arguments.
+			wantErr: true,
+		},
 		{
 			changes: []schema.Change{
 				&schema.AddTable{
@@ -1353,3 +1515,72 @@ func newMigrate(version string) (migrate.PlanApplier, *mock, error) {
 }
 
 func join(lines ...string) string { return strings.Join(lines, "\n") }
+
+func TestValidateDefault(t *testing.T) {
+	tbl := schema.NewTable("t").AddColumns(
+		schema.NewStringColumn("a", TypeVarchar).SetDefault(&schema.Literal{V: "'a'"}),
+		schema.NewStringColumn("b", TypeText).SetDefault(&schema.RawExpr{X: "('b')"}),
+		schema.NewStringColumn("c", TypeText).SetDefault(&schema.Literal{V: "'c'"}),
+		schema.NewColumn("d").SetType(&schema.JSONType{T: TypeJSON}).SetDefault(&schema.Literal{V: "'{}'"}),
+	)
+	require.NoError(t, ValidateDefault(tbl, tbl.Columns[0]))
+	require.NoError(t, ValidateDefault(tbl, tbl.Columns[1]))
+	require.EqualError(t, ValidateDefault(tbl, tbl.Columns[2]), "text column cannot have a literal default value 'c' (use an expression instead)")
+	err := schema.Validate(schema.NewRealm(schema.New("public").AddTables(tbl)), schema.ValidateColumnDefault(ValidateDefault))
+	require.EqualError(t, err, `invalid schema: schema "public" table "t" column "c": text column cannot have a literal default value 'c' (use an expression instead), schema "public" table "t" column "d": json column cannot have a literal default value '{}' (use an expression instead)`)
+}
+
+type keyBlockSize struct {
+	schema.Attr
+	V int
+}
+
+func TestRegisterAttr(t *testing.T) {
+	RegisterAttr(&keyBlockSize{}, func(a schema.Attr) string {
+		return "KEY_BLOCK_SIZE=" + strconv.Itoa(a.(*keyBlockSize).V)
+	})
+	newT := func(size int) *schema.Table {
+		return schema.NewTable("t").
+			SetSchema(schema.New("s")).
+			AddColumns(schema.NewIntColumn("a", "int").AddAttrs(&schema.RawClause{X: "INVISIBLE"})).
+			AddAttrs(&schema.RawClause{X: "ROW_FORMAT=COMPRESSED"}, &keyBlockSize{V: size})
+	}
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: newT(8)}})
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE `s`.`t` (`a` int NOT NULL INVISIBLE) ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8", plan.Changes[0].Cmd)
+
+	from, to := newT(8), newT(16)
+	to.Columns[0].Attrs[0] = &schema.RawClause{X: "VISIBLE"}
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.AddAttr{A: &keyBlockSize{V: 16}},
+		&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeAttr},
+	}, changes)
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.ModifyTable{T: to, Changes: changes}})
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE `s`.`t` KEY_BLOCK_SIZE=16, MODIFY COLUMN `a` int NOT NULL VISIBLE", plan.Changes[0].Cmd)
+
+	// Custom attributes are not compared with states that do not hold them (e.g. inspected).
+	from = schema.NewTable("t").SetSchema(schema.New("s")).AddColumns(schema.NewIntColumn("a", "int"))
+	changes, err = DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestPlanChanges_Annotations(t *testing.T) {
+	var (
+		users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+		add   = &schema.AddColumn{C: schema.NewIntColumn("age", "int")}
+		a     = make(schema.Annotations).
+			Annotate(add, "ticket", "DB-42")
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: users},
+		&schema.ModifyTable{T: users, Changes: []schema.Change{add}},
+	}, migrate.PlanAnnotations(a))
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Nil(t, plan.Changes[0].Annotations)
+	require.Equal(t, map[string]string{"ticket": "DB-42"}, plan.Changes[1].Annotations)
+}
@@ -27,6 +27,12 @@ var (
 	codeImplicitUpdate = sqlcheck.Code("MY101")
 	// codeInlineRef is a MySQL specific code for reporting columns with inline references.
 	codeInlineRef = sqlcheck.Code("MY102")
+	// codeImplicitCommit is a MySQL specific code for reporting statements
+	// that implicitly commit the data changes made before them.
+	codeImplicitCommit = sqlcheck.Code("MY103")
+	// codeNoRollback is a MySQL specific code for reporting rollbacks
+	// of transactions that cannot revert the DDL statements in them.
+	codeNoRollback = sqlcheck.Code("MY104")
 )
 
 func addNotNull(p *datadepend.ColumnPass) (diags []sqlcheck.Diagnostic, err error) {
@@ -197,6 +203,92 @@ func inlineRefs(_ context.Context, p *sqlcheck.Pass) error {
 	return nil
 }
 
+// List of statement kinds that are relevant for detecting implicit commits.
+const (
+	stmtOther = iota
+	stmtDML
+	stmtDDL
+	stmtBegin
+	stmtCommit
+	stmtRollback
+)
+
+// stmtKind returns the kind of the given statement. Statements that cause an implicit commit
+// in MySQL are classified as DDL. See: https://dev.mysql.com/doc/refman/8.0/en/implicit-commit.html
+func stmtKind(s string) int {
+	fs := strings.Fields(strings.ToUpper(strings.TrimSuffix(strings.TrimSpace(s), ";")))
+	if len(fs) == 0 {
+		return stmtOther
+	}
+	switch fs[0] {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE", "LOAD":
+		return stmtDML
+	case "CREATE", "DROP":
+		// Temporary tables do not cause an implicit commit.
+		if len(fs) > 1 && fs[1] == "TEMPORARY" {
+			return stmtOther
+		}
+		return stmtDDL
+	case "ALTER", "RENAME", "TRUNCATE", "GRANT", "REVOKE", "LOCK", "ANALYZE", "OPTIMIZE", "REPAIR", "INSTALL", "UNINSTALL":
+		return stmtDDL
+	case "BEGIN", "START":
+		return stmtBegin
+	case "COMMIT":
+		return stmtCommit
+	case "ROLLBACK":
+		return stmtRollback
+	}
+	return stmtOther
+}
+
+// implicitCommits is an analyzer function that detects failure points in files that mix DML and DDL
+// statements. Since MySQL commits implicitly before (and after) executing DDL statements, the data
+// changes made before a DDL statement cannot be rolled back if one of the statements after it fails,
+// and explicit rollbacks do not revert the DDL statements executed in their transactions. The analyzer
+// works on the statements of the file, and therefore it can also annotate planned changes that were
+// not written yet to a migration file, by analyzing the file returned by sqlcheck.PlanFile.
+func implicitCommits(_ context.Context, p *sqlcheck.Pass) error {
+	var (
+		dml   int  // DML statements that were not committed yet.
+		ddl   bool // DDL statements that were executed in the current transaction.
+		diags []sqlcheck.Diagnostic
+	)
+	for _, c := range p.File.Changes {
+		s := c.Stmt
+		switch stmtKind(s.Text) {
+		case stmtDML:
+			dml++
+		case stmtDDL:
+			if dml > 0 {
+				diags = append(diags, sqlcheck.Diagnostic{
+					Pos:  s.Pos,
+					Code: codeImplicitCommit,
+					Text: fmt.Sprintf(
+						"Statement implicitly commits the data changes of %d preceding statement(s), and they are not rolled back in case a later statement fails",
+						dml,
+					),
+				})
+			}
+			dml, ddl = 0, true
+		case stmtBegin, stmtCommit:
+			dml, ddl = 0, false
+		case stmtRollback:
+			if ddl {
+				diags = append(diags, sqlcheck.Diagnostic{
+					Pos:  s.Pos,
+					Code: codeNoRollback,
+					Text: "Rolling back a transaction does not revert the DDL statements that were executed in it, as they are committed implicitly",
+				})
+			}
+			dml, ddl = 0, false
+		}
+	}
+	if len(diags) > 0 {
+		p.Reporter.WriteReport(sqlcheck.Report{Text: "implicit commits detected", Diagnostics: diags})
+	}
+	return nil
+}
+
 func init() {
 	sqlcheck.Register(mysql.DriverName, func(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 		ds, err := destructive.New(r)
@@ -221,6 +313,6 @@ func init() {
 		if err != nil {
 			return nil, err
 		}
-		return []sqlcheck.Analyzer{ds, dd, cd, bc, nm, sqlcheck.AnalyzerFunc(inlineRefs)}, nil
+		return []sqlcheck.Analyzer{ds, dd, cd, bc, nm, sqlcheck.AnalyzerFunc(inlineRefs), sqlcheck.AnalyzerFunc(implicitCommits)}, nil
 	})
 }
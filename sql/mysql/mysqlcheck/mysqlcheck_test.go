@@ -229,6 +229,57 @@ func TestDataDepend_Maria_ImplicitUpdate(t *testing.T) {
 
 }
 
+func TestImplicitCommits(t *testing.T) {
+	var (
+		reports []sqlcheck.Report
+		pass    = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{
+				Name:   "mysql",
+				Driver: devDriver(t, "8.0.19"),
+			},
+			File: sqlcheck.PlanFile(&migrate.Plan{
+				Name: "backfill",
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE TEMPORARY TABLE tmp (id int)"},
+					{Cmd: "INSERT INTO tmp SELECT id FROM users"},
+					{Cmd: "UPDATE users SET name = 'a8m'"},
+					{Cmd: "ALTER TABLE users ADD COLUMN age int"},
+					{Cmd: "DELETE FROM users WHERE age IS NULL"},
+					{Cmd: "COMMIT"},
+					{Cmd: "START TRANSACTION"},
+					{Cmd: "INSERT INTO users (name) VALUES ('a8m')"},
+					{Cmd: "DROP TABLE tmp"},
+					{Cmd: "ROLLBACK"},
+					{Cmd: "BEGIN"},
+					{Cmd: "DELETE FROM users"},
+					{Cmd: "ROLLBACK"},
+				},
+			}),
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				reports = append(reports, r)
+			}),
+		}
+	)
+	azs, err := sqlcheck.AnalyzerFor(mysql.DriverName, nil)
+	require.NoError(t, err)
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	require.Len(t, reports, 1)
+	require.Equal(t, "implicit commits detected", reports[0].Text)
+	stmts, err := pass.File.StmtDecls()
+	require.NoError(t, err)
+	diags := reports[0].Diagnostics
+	require.Len(t, diags, 3)
+	require.Equal(t, "MY103", diags[0].Code)
+	require.Equal(t, stmts[3].Pos, diags[0].Pos)
+	require.Equal(t, "Statement implicitly commits the data changes of 2 preceding statement(s), and they are not rolled back in case a later statement fails", diags[0].Text)
+	require.Equal(t, "MY103", diags[1].Code)
+	require.Equal(t, stmts[8].Pos, diags[1].Pos)
+	require.Equal(t, "Statement implicitly commits the data changes of 1 preceding statement(s), and they are not rolled back in case a later statement fails", diags[1].Text)
+	require.Equal(t, "MY104", diags[2].Code)
+	require.Equal(t, stmts[9].Pos, diags[2].Pos)
+	require.Equal(t, "Rolling back a transaction does not revert the DDL statements that were executed in it, as they are committed implicitly", diags[2].Text)
+}
+
 type testFile struct {
 	name string
 	migrate.File
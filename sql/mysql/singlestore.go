@@ -0,0 +1,371 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql/internal/mysqlversion"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+)
+
+type (
+	// splanApply decorates MySQL planApply.
+	splanApply struct{ planApply }
+	// sdiff decorates MySQL diff.
+	sdiff struct{ diff }
+	// sinspect decorates MySQL inspect.
+	sinspect struct{ inspect }
+
+	// ShardKey describes the SHARD KEY of a SingleStore table, which
+	// defines how the table rows are distributed between partitions.
+	ShardKey struct {
+		schema.Attr
+		Columns []string
+	}
+
+	// SortKey describes the SORT KEY of a SingleStore columnstore table.
+	SortKey struct {
+		schema.Attr
+		Columns []string
+	}
+
+	// Storage describes the storage type of SingleStore table.
+	// ROWSTORE or COLUMNSTORE, which is the default.
+	Storage struct {
+		schema.Attr
+		V string
+	}
+)
+
+// SingleStore storage types.
+const (
+	StorageRowstore    = "ROWSTORE"
+	StorageColumnstore = "COLUMNSTORE"
+)
+
+// DriverNameSingleStore holds the name used for registering the SingleStore flavor.
+const DriverNameSingleStore = "singlestore"
+
+func init() {
+	sqlclient.Register(
+		DriverNameSingleStore,
+		sqlclient.OpenerFunc(func(ctx context.Context, u *url.URL) (*sqlclient.Client, error) {
+			return openURL(ctx, u, OpenSingleStore)
+		}),
+		sqlclient.RegisterDriverOpener(OpenSingleStore),
+		sqlclient.RegisterCodec(MarshalHCL, EvalHCL),
+		sqlclient.RegisterFlavours("memsql"),
+		sqlclient.RegisterURLParser(parser{}),
+	)
+}
+
+// OpenSingleStore opens a new MySQL driver for a SingleStore (formerly MemSQL) database.
+// SingleStore reports a MySQL-compatible version, and therefore, the flavor is set
+// explicitly instead of being detected by the server version.
+func OpenSingleStore(db schema.ExecQuerier) (migrate.Driver, error) {
+	c, err := connect(db)
+	if err != nil {
+		return nil, err
+	}
+	if !c.SingleStore() {
+		c.V = mysqlversion.V(string(c.V) + "-SingleStore")
+	}
+	return singleStoreDriver(c), nil
+}
+
+// singleStoreDriver returns the decorated driver of the SingleStore flavor.
+func singleStoreDriver(c *conn) *Driver {
+	return &Driver{
+		conn:        c,
+		Differ:      &sqlx.Diff{DiffDriver: &sdiff{diff{conn: c}}},
+		Inspector:   &sinspect{inspect{c}},
+		PlanApplier: &splanApply{planApply{c}},
+	}
+}
+
+// PlanChanges returns a migration plan for the given schema changes. SingleStore does not
+// support some ALTER combinations (e.g. modifying a column with other changes in the same
+// statement). Therefore, changes are broken down, and planned one by one, similar to TiDB.
+func (p *splanApply) PlanChanges(ctx context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	if err := singleStoreCheck(changes); err != nil {
+		return nil, err
+	}
+	planned, err := sqlx.DetachCycles(changes)
+	if err != nil {
+		return nil, err
+	}
+	planned = flat(planned)
+	sort.SliceStable(planned, func(i, j int) bool {
+		return priority(planned[i]) < priority(planned[j])
+	})
+	s := &state{
+		conn: p.conn,
+		Plan: migrate.Plan{
+			Name: name,
+			// A plan is reversible, if all
+			// its changes are reversible.
+			Reversible:    true,
+			Transactional: false,
+		},
+	}
+	for _, c := range planned {
+		// Use the planner of MySQL with each "atomic" change.
+		plan, err := p.planApply.PlanChanges(ctx, name, []schema.Change{c}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if !plan.Reversible {
+			s.Plan.Reversible = false
+		}
+		s.Plan.Changes = append(s.Plan.Changes, plan.Changes...)
+	}
+	return &s.Plan, nil
+}
+
+func (p *splanApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+}
+
+// singleStoreCheck returns an error if one of the changes is not supported by SingleStore.
+func singleStoreCheck(changes []schema.Change) error {
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddTable:
+			if len(c.T.ForeignKeys) > 0 {
+				return fmt.Errorf("create table %q: foreign keys are not supported by SingleStore", c.T.Name)
+			}
+		case *schema.ModifyTable:
+			keys := singleStoreKeys(c.T)
+			for _, change := range c.Changes {
+				switch change := change.(type) {
+				case *schema.AddAttr, *schema.DropAttr, *schema.ModifyAttr:
+					switch a := changeAttr(change); a.(type) {
+					case *ShardKey:
+						return fmt.Errorf("alter table %q: changing the shard key requires recreating the table", c.T.Name)
+					case *SortKey:
+						return fmt.Errorf("alter table %q: changing the sort key requires recreating the table", c.T.Name)
+					case *Storage:
+						return fmt.Errorf("alter table %q: changing the table storage requires recreating the table", c.T.Name)
+					}
+				case *schema.ModifyColumn:
+					if keys[change.From.Name] && change.Change.Is(schema.ChangeType) {
+						return fmt.Errorf("alter table %q: cannot modify the type of shard or sort key column %q", c.T.Name, change.From.Name)
+					}
+				case *schema.AddPrimaryKey, *schema.DropPrimaryKey, *schema.ModifyPrimaryKey:
+					return fmt.Errorf("alter table %q: changing the primary key requires recreating the table", c.T.Name)
+				case *schema.AddForeignKey, *schema.DropForeignKey, *schema.ModifyForeignKey:
+					return fmt.Errorf("alter table %q: foreign keys are not supported by SingleStore", c.T.Name)
+				case *schema.AddCheck, *schema.DropCheck, *schema.ModifyCheck:
+					return fmt.Errorf("alter table %q: check constraints are not supported by SingleStore", c.T.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// changeAttr returns the attribute of the given table attribute change.
+func changeAttr(c schema.Change) schema.Attr {
+	switch c := c.(type) {
+	case *schema.AddAttr:
+		return c.A
+	case *schema.DropAttr:
+		return c.A
+	case *schema.ModifyAttr:
+		return c.To
+	}
+	return nil
+}
+
+// singleStoreKeys returns the columns that are used by the shard and sort keys of the table.
+func singleStoreKeys(t *schema.Table) map[string]bool {
+	var (
+		shard ShardKey
+		sortK SortKey
+		keys  = make(map[string]bool)
+	)
+	if sqlx.Has(t.Attrs, &shard) {
+		for _, c := range shard.Columns {
+			keys[c] = true
+		}
+	}
+	if sqlx.Has(t.Attrs, &sortK) {
+		for _, c := range sortK.Columns {
+			name, _, _ := strings.Cut(strings.TrimSpace(c), " ")
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (d *sdiff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
+	changes, err := d.diff.TableAttrDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var fromS, toS ShardKey
+	switch fromHas, toHas := sqlx.Has(from.Attrs, &fromS), sqlx.Has(to.Attrs, &toS); {
+	case fromHas && !toHas:
+		changes = append(changes, &schema.DropAttr{A: &fromS})
+	case !fromHas && toHas:
+		changes = append(changes, &schema.AddAttr{A: &toS})
+	case fromHas && toHas && !keyEqual(fromS.Columns, toS.Columns):
+		changes = append(changes, &schema.ModifyAttr{From: &fromS, To: &toS})
+	}
+	var fromK, toK SortKey
+	switch fromHas, toHas := sqlx.Has(from.Attrs, &fromK), sqlx.Has(to.Attrs, &toK); {
+	case fromHas && !toHas:
+		changes = append(changes, &schema.DropAttr{A: &fromK})
+	case !fromHas && toHas:
+		changes = append(changes, &schema.AddAttr{A: &toK})
+	case fromHas && toHas && !keyEqual(fromK.Columns, toK.Columns):
+		changes = append(changes, &schema.ModifyAttr{From: &fromK, To: &toK})
+	}
+	if s1, s2 := storage(from.Attrs), storage(to.Attrs); s1.V != s2.V {
+		changes = append(changes, &schema.ModifyAttr{From: s1, To: s2})
+	}
+	return changes, nil
+}
+
+// keyEqual reports if the two key column lists are equal.
+func keyEqual(k1, k2 []string) bool {
+	if len(k1) != len(k2) {
+		return false
+	}
+	for i := range k1 {
+		if !strings.EqualFold(strings.Join(strings.Fields(k1[i]), " "), strings.Join(strings.Fields(k2[i]), " ")) {
+			return false
+		}
+	}
+	return true
+}
+
+// storage returns the storage type of the table. COLUMNSTORE is the default.
+func storage(attrs []schema.Attr) *Storage {
+	if s := (Storage{}); sqlx.Has(attrs, &s) && s.V != "" {
+		return &Storage{V: strings.ToUpper(s.V)}
+	}
+	return &Storage{V: StorageColumnstore}
+}
+
+func (i *sinspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	s, err := i.inspect.InspectSchema(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	return i.patchSchema(ctx, s)
+}
+
+func (i *sinspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	r, err := i.inspect.InspectRealm(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range r.Schemas {
+		if _, err := i.patchSchema(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+var (
+	// e.g. CREATE ROWSTORE TABLE `t`.
+	reRowstore = regexp.MustCompile(`(?i)^\s*CREATE\s+ROWSTORE\s+`)
+	// e.g. SHARD KEY `__SHARDKEY` (`id`), or SORT KEY `name` (`ts` DESC).
+	reShardKey = regexp.MustCompile("(?i)\\bSHARD\\s+KEY\\s*(`[^`]*`)?\\s*\\(([^)]*)\\)")
+	reSortKey  = regexp.MustCompile("(?i)\\bSORT\\s+KEY\\s*(`[^`]*`)?\\s*\\(([^)]*)\\)")
+)
+
+// patchSchema extracts the SingleStore-specific table options from the
+// CREATE TABLE statement of each table, as they are not exposed by the
+// information schema.
+func (i *sinspect) patchSchema(ctx context.Context, s *schema.Schema) (*schema.Schema, error) {
+	for _, t := range s.Tables {
+		var c CreateStmt
+		if !sqlx.Has(t.Attrs, &c) {
+			c1, err := i.createStmt(ctx, t)
+			if err != nil {
+				return nil, err
+			}
+			c = *c1
+		}
+		if reRowstore.MatchString(c.S) {
+			t.AddAttrs(&Storage{V: StorageRowstore})
+		}
+		if m := reShardKey.FindStringSubmatch(c.S); m != nil {
+			t.AddAttrs(&ShardKey{Columns: keyColumns(m[2])})
+			dropIndex(t, m[1])
+		}
+		if m := reSortKey.FindStringSubmatch(c.S); m != nil {
+			t.AddAttrs(&SortKey{Columns: keyColumns(m[2])})
+			dropIndex(t, m[1])
+		}
+	}
+	return s, nil
+}
+
+// keyColumns returns the columns of a shard or sort key. e.g. "`a`, `b` DESC".
+func keyColumns(s string) []string {
+	var columns []string
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(strings.ReplaceAll(c, "`", "")); c != "" {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// dropIndex removes the index that represents a shard or sort key from the table
+// indexes, as SingleStore reports these keys in its information schema as well.
+func dropIndex(t *schema.Table, name string) {
+	if name = strings.Trim(name, "`"); name == "" {
+		return
+	}
+	for i, idx := range t.Indexes {
+		if idx.Name == name {
+			t.Indexes = append(t.Indexes[:i], t.Indexes[i+1:]...)
+			return
+		}
+	}
+}
+
+// singleStoreTable writes the shard and sort keys of the table to the builder.
+func singleStoreTable(b *sqlx.Builder, t *schema.Table) {
+	var (
+		shard ShardKey
+		sortK SortKey
+	)
+	if sqlx.Has(t.Attrs, &shard) {
+		b.Comma().NL().P("SHARD KEY")
+		b.Wrap(func(b *sqlx.Builder) {
+			b.MapComma(shard.Columns, func(i int, b *sqlx.Builder) {
+				b.Ident(shard.Columns[i])
+			})
+		})
+	}
+	if sqlx.Has(t.Attrs, &sortK) {
+		b.Comma().NL().P("SORT KEY")
+		b.Wrap(func(b *sqlx.Builder) {
+			b.MapComma(sortK.Columns, func(i int, b *sqlx.Builder) {
+				name, order, _ := strings.Cut(sortK.Columns[i], " ")
+				b.Ident(name)
+				if order = strings.TrimSpace(order); order != "" {
+					b.P(strings.ToUpper(order))
+				}
+			})
+		})
+	}
+}
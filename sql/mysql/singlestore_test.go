@@ -0,0 +1,232 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSingleStore(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("5.7.32")
+	drv, err := OpenSingleStore(db)
+	require.NoError(t, err)
+	require.True(t, drv.(*Driver).SingleStore())
+	require.IsType(t, &splanApply{}, drv.(*Driver).PlanApplier)
+	require.IsType(t, &sinspect{}, drv.(*Driver).Inspector)
+
+	db, m, err = sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("5.7.32-SingleStore")
+	drv, err = Open(db)
+	require.NoError(t, err)
+	require.IsType(t, &splanApply{}, drv.(*Driver).PlanApplier)
+}
+
+func TestSingleStore_PlanChanges(t *testing.T) {
+	users := func() *schema.Table {
+		t := schema.NewTable("users").
+			AddColumns(
+				schema.NewIntColumn("id", "bigint"),
+				schema.NewIntColumn("ts", "bigint"),
+			)
+		t.SetSchema(schema.New("test"))
+		return t
+	}
+	tests := []struct {
+		changes  []schema.Change
+		wantPlan *migrate.Plan
+		wantErr  string
+	}{
+		{
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: func() *schema.Table {
+						t := users()
+						t.AddAttrs(&ShardKey{Columns: []string{"id"}}, &SortKey{Columns: []string{"ts DESC"}})
+						return t
+					}(),
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE TABLE `test`.`users` (`id` bigint NOT NULL, `ts` bigint NOT NULL, SHARD KEY (`id`), SORT KEY (`ts` DESC))",
+						Reverse: "DROP TABLE `test`.`users`",
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: func() *schema.Table {
+						t := users()
+						t.AddAttrs(&Storage{V: StorageRowstore})
+						return t
+					}(),
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE ROWSTORE TABLE `test`.`users` (`id` bigint NOT NULL, `ts` bigint NOT NULL)",
+						Reverse: "DROP TABLE `test`.`users`",
+					},
+				},
+			},
+		},
+		// Column changes are split into separate statements.
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users(),
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewNullStringColumn("name", "varchar(255)")},
+						&schema.DropColumn{C: schema.NewIntColumn("ts", "bigint")},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `test`.`users` ADD COLUMN `name` varchar(255) NULL",
+						Reverse: "ALTER TABLE `test`.`users` DROP COLUMN `name`",
+					},
+					{
+						Cmd:     "ALTER TABLE `test`.`users` DROP COLUMN `ts`",
+						Reverse: "ALTER TABLE `test`.`users` ADD COLUMN `ts` bigint NOT NULL",
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users(),
+					Changes: []schema.Change{
+						&schema.AddAttr{A: &ShardKey{Columns: []string{"id"}}},
+					},
+				},
+			},
+			wantErr: `alter table "users": changing the shard key requires recreating the table`,
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users(),
+					Changes: []schema.Change{
+						&schema.ModifyAttr{From: &Storage{V: StorageColumnstore}, To: &Storage{V: StorageRowstore}},
+					},
+				},
+			},
+			wantErr: `alter table "users": changing the table storage requires recreating the table`,
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: func() *schema.Table {
+						t := users()
+						t.AddAttrs(&SortKey{Columns: []string{"ts DESC"}})
+						return t
+					}(),
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   schema.NewIntColumn("ts", "bigint"),
+							To:     schema.NewIntColumn("ts", "int"),
+							Change: schema.ChangeType,
+						},
+					},
+				},
+			},
+			wantErr: `alter table "users": cannot modify the type of shard or sort key column "ts"`,
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users(),
+					Changes: []schema.Change{
+						&schema.AddPrimaryKey{P: schema.NewPrimaryKey(schema.NewIntColumn("id", "bigint"))},
+					},
+				},
+			},
+			wantErr: `alter table "users": changing the primary key requires recreating the table`,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			db, m, err := sqlmock.New()
+			require.NoError(t, err)
+			mock{m}.version("5.7.32")
+			drv, err := OpenSingleStore(db)
+			require.NoError(t, err)
+			plan, err := drv.PlanChanges(context.Background(), "wantPlan", tt.changes)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPlan.Reversible, plan.Reversible)
+			require.Len(t, plan.Changes, len(tt.wantPlan.Changes))
+			for i, c := range plan.Changes {
+				require.Equal(t, tt.wantPlan.Changes[i].Cmd, c.Cmd)
+				require.Equal(t, tt.wantPlan.Changes[i].Reverse, c.Reverse)
+			}
+		})
+	}
+}
+
+func TestSingleStore_TableAttrDiff(t *testing.T) {
+	d := &sdiff{diff{conn: &conn{ExecQuerier: sqlx.NoRows, V: "5.7.32-SingleStore"}}}
+	from := schema.NewTable("t").SetSchema(schema.New("test")).AddAttrs(&ShardKey{Columns: []string{"id"}}, &SortKey{Columns: []string{"ts"}})
+	to := schema.NewTable("t").SetSchema(schema.New("test")).AddAttrs(&ShardKey{Columns: []string{"id"}}, &Storage{V: "columnstore"})
+	changes, err := d.TableAttrDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, &schema.DropAttr{A: &SortKey{Columns: []string{"ts"}}}, changes[0])
+
+	to = schema.NewTable("t").SetSchema(schema.New("test")).AddAttrs(&ShardKey{Columns: []string{"name"}}, &SortKey{Columns: []string{"ts"}}, &Storage{V: StorageRowstore})
+	changes, err = d.TableAttrDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.IsType(t, &schema.ModifyAttr{}, changes[0])
+	require.Equal(t, &schema.ModifyAttr{From: &Storage{V: StorageColumnstore}, To: &Storage{V: StorageRowstore}}, changes[1])
+}
+
+func TestSingleStore_PatchSchema(t *testing.T) {
+	tbl := schema.NewTable("t").
+		AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("ts", "bigint")).
+		AddIndexes(schema.NewIndex("__SHARDKEY").AddColumns(schema.NewIntColumn("id", "bigint")), schema.NewIndex("ts"))
+	tbl.AddAttrs(&CreateStmt{S: "CREATE ROWSTORE TABLE `t` (\n  `id` bigint NOT NULL,\n  `ts` bigint NOT NULL,\n  SHARD KEY `__SHARDKEY` (`id`),\n  SORT KEY `__UNORDERED` (`ts` DESC)\n)"})
+	s := schema.New("test").AddTables(tbl)
+	_, err := (&sinspect{}).patchSchema(context.Background(), s)
+	require.NoError(t, err)
+	require.Len(t, tbl.Indexes, 1)
+	require.Equal(t, "ts", tbl.Indexes[0].Name)
+	var (
+		shard ShardKey
+		sortK SortKey
+		store Storage
+	)
+	require.True(t, sqlx.Has(tbl.Attrs, &shard))
+	require.Equal(t, []string{"id"}, shard.Columns)
+	require.True(t, sqlx.Has(tbl.Attrs, &sortK))
+	require.Equal(t, []string{"ts DESC"}, sortK.Columns)
+	require.True(t, sqlx.Has(tbl.Attrs, &store))
+	require.Equal(t, StorageRowstore, store.V)
+}
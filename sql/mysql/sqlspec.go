@@ -23,6 +23,8 @@ import (
 type doc struct {
 	Tables  []*sqlspec.Table  `spec:"table"`
 	Views   []*sqlspec.View   `spec:"view"`
+	Funcs   []*sqlspec.Func   `spec:"function"`
+	Procs   []*sqlspec.Proc   `spec:"procedure"`
 	Schemas []*sqlspec.Schema `spec:"schema"`
 }
 
@@ -35,8 +37,8 @@ func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
 			return err
 		}
 		if err := specutil.Scan(v,
-			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views},
-			&specutil.ScanFuncs{Table: convertTable, View: convertView},
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views, Funcs: d.Funcs, Procs: d.Procs},
+			&specutil.ScanFuncs{Table: convertTable, View: convertView, Func: convertFunc, Proc: convertProc},
 		); err != nil {
 			return fmt.Errorf("mysql: failed converting to *schema.Realm: %w", err)
 		}
@@ -59,8 +61,8 @@ func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
 		}
 		r := &schema.Realm{}
 		if err := specutil.Scan(r,
-			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views},
-			&specutil.ScanFuncs{Table: convertTable, View: convertView},
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views, Funcs: d.Funcs, Procs: d.Procs},
+			&specutil.ScanFuncs{Table: convertTable, View: convertView, Func: convertFunc, Proc: convertProc},
 		); err != nil {
 			return err
 		}
@@ -82,11 +84,31 @@ func MarshalSpec(v any, marshaler schemahcl.Marshaler) ([]byte, error) {
 }
 
 var (
-	hclState = schemahcl.New(
+	hclState = newState()
+	// MarshalHCL marshals v into an Atlas HCL DDL document.
+	MarshalHCL = schemahcl.MarshalerFunc(func(v any) ([]byte, error) {
+		return MarshalSpec(v, hclState)
+	})
+	// EvalHCL implements the schemahcl.Evaluator interface.
+	EvalHCL = schemahcl.EvalFunc(evalSpec)
+
+	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
+	// of from an hclparse.Parser instance.
+	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
+)
+
+// newState returns the HCL state of the driver, configured with the types of the TypeRegistry.
+func newState() *schemahcl.State {
+	return schemahcl.New(
 		append(
 			specOptions,
 			schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
 			schemahcl.WithTypes("view.column.type", TypeRegistry.Specs()),
+			schemahcl.WithTypes("function.arg.type", TypeRegistry.Specs()),
+			schemahcl.WithTypes("function.return", TypeRegistry.Specs()),
+			schemahcl.WithTypes("procedure.arg.type", TypeRegistry.Specs()),
+			schemahcl.WithScopedEnums("function.arg.mode", string(schema.FuncArgModeIn)),
+			schemahcl.WithScopedEnums("procedure.arg.mode", string(schema.FuncArgModeIn), string(schema.FuncArgModeOut), string(schema.FuncArgModeInOut)),
 			schemahcl.WithScopedEnums("view.check_option", schema.ViewCheckOptionLocal, schema.ViewCheckOptionCascaded),
 			schemahcl.WithScopedEnums("table.engine", EngineInnoDB, EngineMyISAM, EngineMemory, EngineCSV, EngineNDB),
 			schemahcl.WithScopedEnums("table.index.type", IndexTypeBTree, IndexTypeHash, IndexTypeFullText, IndexTypeSpatial),
@@ -97,17 +119,7 @@ var (
 			schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
 		)...,
 	)
-	// MarshalHCL marshals v into an Atlas HCL DDL document.
-	MarshalHCL = schemahcl.MarshalerFunc(func(v any) ([]byte, error) {
-		return MarshalSpec(v, hclState)
-	})
-	// EvalHCL implements the schemahcl.Evaluator interface.
-	EvalHCL = schemahcl.EvalFunc(evalSpec)
-
-	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
-	// of from an hclparse.Parser instance.
-	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
-)
+}
 
 // convertTable converts a sqlspec.Table to a schema.Table. Table conversion is done without converting
 // ForeignKeySpecs into ForeignKeys, as the target tables do not necessarily exist in the schema
@@ -136,6 +148,28 @@ func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, er
 		}
 		t.AddAttrs(&Engine{V: v})
 	}
+	// SingleStore table options.
+	if attr, ok := spec.Attr("shard_key"); ok {
+		columns, err := attr.Strings()
+		if err != nil {
+			return nil, fmt.Errorf("expect list of strings for attribute table.%s.shard_key: %w", spec.Name, err)
+		}
+		t.AddAttrs(&ShardKey{Columns: columns})
+	}
+	if attr, ok := spec.Attr("sort_key"); ok {
+		columns, err := attr.Strings()
+		if err != nil {
+			return nil, fmt.Errorf("expect list of strings for attribute table.%s.sort_key: %w", spec.Name, err)
+		}
+		t.AddAttrs(&SortKey{Columns: columns})
+	}
+	if attr, ok := spec.Attr("storage"); ok {
+		v, err := attr.String()
+		if err != nil {
+			return nil, err
+		}
+		t.AddAttrs(&Storage{V: strings.ToUpper(v)})
+	}
 	return t, err
 }
 
@@ -268,12 +302,25 @@ func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
 	return TypeRegistry.Type(spec.Type, spec.Extra.Attrs)
 }
 
+// convertFunc converts a sqlspec.Func to a schema.Func.
+func convertFunc(spec *sqlspec.Func, parent *schema.Schema) (*schema.Func, error) {
+	return specutil.Func(spec, parent, convertColumnType)
+}
+
+// convertProc converts a sqlspec.Proc to a schema.Proc.
+func convertProc(spec *sqlspec.Proc, parent *schema.Schema) (*schema.Proc, error) {
+	return specutil.Proc(spec, parent, convertColumnType)
+}
+
 // schemaSpec converts from a concrete MySQL schema to Atlas specification.
 func schemaSpec(s *schema.Schema) (*specutil.SchemaSpec, error) {
 	spec, err := specutil.FromSchema(s, tableSpec, viewSpec)
 	if err != nil {
 		return nil, err
 	}
+	if err := specutil.FromFuncs(s, spec, columnTypeSpec); err != nil {
+		return nil, err
+	}
 	if c, ok := sqlx.Charset(s.Attrs, nil); ok {
 		spec.Schema.Extra.Attrs = append(spec.Schema.Extra.Attrs, schemahcl.StringAttr("charset", c))
 	}
@@ -313,6 +360,16 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 		}
 		ts.Extra.Attrs = append(ts.Extra.Attrs, attr)
 	}
+	if k := (ShardKey{}); sqlx.Has(t.Attrs, &k) {
+		ts.Extra.Attrs = append(ts.Extra.Attrs, schemahcl.StringsAttr("shard_key", k.Columns...))
+	}
+	if k := (SortKey{}); sqlx.Has(t.Attrs, &k) {
+		ts.Extra.Attrs = append(ts.Extra.Attrs, schemahcl.StringsAttr("sort_key", k.Columns...))
+	}
+	// Marshal the storage attribute only if it is not COLUMNSTORE (default).
+	if s := (Storage{}); sqlx.Has(t.Attrs, &s) && !strings.EqualFold(s.V, StorageColumnstore) {
+		ts.Extra.Attrs = append(ts.Extra.Attrs, schemahcl.StringAttr("storage", s.V))
+	}
 	return ts, nil
 }
 
@@ -459,6 +516,40 @@ func convertCharset(spec specutil.Attrer, attrs *[]schema.Attr) error {
 	return nil
 }
 
+// customTypes holds the custom types that were registered by RegisterTypes.
+var customTypes = schemahcl.NewRegistry()
+
+// RegisterTypes registers custom types in the driver (e.g. types that are provided by
+// extensions or proprietary types). Registered types are used for parsing inspected column
+// types, formatting types in migration plans, and converting types from and to HCL. Types
+// are parsed from their database representation by their TypeSpec, in which the type
+// arguments are mapped to the attributes of the spec by their order. For example:
+//
+//	type GeoType struct {
+//		schema.Type
+//		T    string
+//		Size int
+//	}
+//
+//	mysql.RegisterTypes(&schemahcl.TypeSpec{
+//		Name:       "geo",
+//		T:          "geo",
+//		Attributes: []*schemahcl.TypeAttr{schemahcl.SizeTypeAttr(false)},
+//		RType:      reflect.TypeOf(GeoType{}),
+//	})
+//
+// RegisterTypes is not safe for concurrent use, and should be called on initialization.
+func RegisterTypes(specs ...*schemahcl.TypeSpec) error {
+	if err := customTypes.Register(specs...); err != nil {
+		return err
+	}
+	if err := TypeRegistry.Register(specs...); err != nil {
+		return err
+	}
+	hclState = newState()
+	return nil
+}
+
 // TypeRegistry contains the supported TypeSpecs for the mysql driver.
 var TypeRegistry = schemahcl.NewRegistry(
 	schemahcl.WithFormatter(FormatType),
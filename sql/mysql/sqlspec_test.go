@@ -1819,3 +1819,75 @@ schema "nati.schema" {
 }
 `, string(got))
 }
+
+func TestMarshalSpec_Funcs(t *testing.T) {
+	s := schema.New("test")
+	s.AddFuncs(&schema.Func{
+		Name: "add",
+		Args: []*schema.FuncArg{{Name: "a", Type: &schema.IntegerType{T: TypeInt}}, {Name: "b", Type: &schema.IntegerType{T: TypeInt}}},
+		Ret:  &schema.IntegerType{T: TypeInt},
+		Body: "DETERMINISTIC RETURN a + b",
+	})
+	s.AddProcs(&schema.Proc{
+		Name: "log",
+		Args: []*schema.FuncArg{{Name: "msg", Type: &schema.StringType{T: TypeVarchar, Size: 255}}, {Name: "n", Type: &schema.IntegerType{T: TypeInt}, Mode: schema.FuncArgModeOut}},
+		Body: "BEGIN\n  INSERT INTO logs VALUES (msg);\n  SET n = 1;\nEND",
+	})
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `function "add" {
+  schema = schema.test
+  return = int
+  as     = "DETERMINISTIC RETURN a + b"
+  arg "a" {
+    type = int
+  }
+  arg "b" {
+    type = int
+  }
+}
+procedure "log" {
+  schema = schema.test
+  as     = <<-SQL
+  BEGIN
+    INSERT INTO logs VALUES (msg);
+    SET n = 1;
+  END
+  SQL
+  arg "msg" {
+    type = varchar(255)
+  }
+  arg "n" {
+    type = int
+    mode = OUT
+  }
+}
+schema "test" {
+}
+`, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Funcs, 1)
+	require.Len(t, got.Procs, 1)
+	require.Equal(t, s.Funcs[0].Args, got.Funcs[0].Args)
+	require.Equal(t, s.Funcs[0].Ret, got.Funcs[0].Ret)
+	require.Equal(t, s.Funcs[0].Body, got.Funcs[0].Body)
+	require.Equal(t, s.Procs[0].Args, got.Procs[0].Args)
+	require.Equal(t, s.Procs[0].Body, got.Procs[0].Body)
+	require.Equal(t, "test", got.Procs[0].Schema.Name)
+
+	// Functions accept only IN arguments.
+	err = EvalHCLBytes([]byte(`
+schema "test" {}
+function "f" {
+  schema = schema.test
+  return = int
+  as     = "RETURN 1"
+  arg "a" {
+    type = int
+    mode = OUT
+  }
+}
+`), &got, nil)
+	require.Error(t, err)
+}
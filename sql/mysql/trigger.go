@@ -0,0 +1,73 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"fmt"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// addTrigger appends the change for creating the trigger.
+func (s *state) addTrigger(add *schema.AddTrigger) error {
+	create, drop, err := s.createDropTrigger(add.T)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create trigger %q", add.T.Name),
+	})
+	return nil
+}
+
+// dropTrigger appends the change for dropping the trigger.
+func (s *state) dropTrigger(drop *schema.DropTrigger) error {
+	create, cmd, err := s.createDropTrigger(drop.T)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     cmd,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop trigger %q", drop.T.Name),
+	})
+	return nil
+}
+
+// modifyTrigger appends the changes for modifying the trigger.
+// MySQL does not support altering triggers, and therefore, it
+// is recreated.
+func (s *state) modifyTrigger(modify *schema.ModifyTrigger) error {
+	if err := s.dropTrigger(&schema.DropTrigger{T: modify.From}); err != nil {
+		return err
+	}
+	return s.addTrigger(&schema.AddTrigger{T: modify.To})
+}
+
+// createDropTrigger returns the CREATE and DROP statements of the trigger.
+// MySQL triggers are defined on tables, fired by exactly one event, for
+// each row.
+func (s *state) createDropTrigger(tr *schema.Trigger) (string, string, error) {
+	switch {
+	case tr.Table == nil:
+		return "", "", fmt.Errorf("mysql: trigger %q must be defined on a table", tr.Name)
+	case len(tr.Events) != 1:
+		return "", "", fmt.Errorf("mysql: trigger %q must be fired by exactly one event, got %d", tr.Name, len(tr.Events))
+	case len(tr.Events[0].Columns) > 0:
+		return "", "", fmt.Errorf("mysql: UPDATE OF columns is not supported. trigger: %q", tr.Name)
+	case tr.ActionTime != schema.TriggerTimeBefore && tr.ActionTime != schema.TriggerTimeAfter:
+		return "", "", fmt.Errorf("mysql: unexpected action time %q for trigger %q", tr.ActionTime, tr.Name)
+	case tr.For == schema.TriggerForStmt:
+		return "", "", fmt.Errorf("mysql: trigger %q cannot be fired for each statement", tr.Name)
+	}
+	create := s.Build("CREATE TRIGGER").SchemaResource(tr.Table.Schema, tr.Name).
+		P(string(tr.ActionTime), tr.Events[0].Name, "ON").Table(tr.Table).P("FOR EACH ROW", tr.Body)
+	return create.String(), s.Build("DROP TRIGGER").SchemaResource(tr.Table.Schema, tr.Name).String(), nil
+}
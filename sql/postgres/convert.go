@@ -17,6 +17,9 @@ import (
 // FormatType converts schema type to its column form in the database.
 // An error is returned if the type cannot be recognized.
 func FormatType(t schema.Type) (string, error) {
+	if f, ok, err := customTypes.FormatType(t); ok {
+		return f, err
+	}
 	var f string
 	switch t := t.(type) {
 	case *ArrayType:
@@ -62,7 +65,7 @@ func FormatType(t schema.Type) (string, error) {
 		}
 	case *schema.StringType:
 		switch f = strings.ToLower(t.T); f {
-		case TypeText, typeName:
+		case TypeText, typeName, TypeCIText:
 		// CHAR(n) is alias for CHARACTER(n). If not length was
 		// specified, the definition is equivalent to CHARACTER(1).
 		case TypeChar, TypeCharacter:
@@ -78,6 +81,13 @@ func FormatType(t schema.Type) (string, error) {
 			if t.Size != 0 {
 				f = fmt.Sprintf("%s(%d)", TypeCharVar, t.Size)
 			}
+		// STRING is a CockroachDB alias for TEXT, and STRING(n) for
+		// CHARACTER VARYING(n). Both are formatted in their standard form.
+		case TypeString:
+			f = TypeText
+			if t.Size != 0 {
+				f = fmt.Sprintf("%s(%d)", TypeCharVar, t.Size)
+			}
 		default:
 			return "", fmt.Errorf("postgres: unexpected string type: %q", t.T)
 		}
@@ -162,6 +172,15 @@ func FormatType(t schema.Type) (string, error) {
 		}
 	case *UserDefinedType:
 		f = strings.ToLower(t.T)
+	case *VectorType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeVector, TypeHalfVec, TypeSparseVec:
+			if t.Dim > 0 {
+				f = fmt.Sprintf("%s(%d)", f, t.Dim)
+			}
+		default:
+			return "", fmt.Errorf("postgres: unexpected vector type: %q", t.T)
+		}
 	case *XMLType:
 		f = strings.ToLower(t.T)
 	case *schema.UnsupportedType:
@@ -176,6 +195,9 @@ func FormatType(t schema.Type) (string, error) {
 // The raw value is expected to follow the format in PostgreSQL information schema
 // or as an input for the CREATE TABLE statement.
 func ParseType(typ string) (schema.Type, error) {
+	if t, ok, err := customTypes.ParseType(typ); ok {
+		return t, err
+	}
 	var (
 		err error
 		d   *columnDesc
@@ -210,7 +232,7 @@ func columnType(c *columnDesc) (schema.Type, error) {
 		typ = &schema.BoolType{T: t}
 	case TypeBytea:
 		typ = &schema.BinaryType{T: t}
-	case TypeCharacter, TypeChar, TypeCharVar, TypeVarChar, TypeText, typeName:
+	case TypeCharacter, TypeChar, TypeCharVar, TypeVarChar, TypeText, typeName, TypeString:
 		// A `character` column without length specifier is equivalent to `character(1)`,
 		// but `varchar` without length accepts strings of any size (same as `text`).
 		typ = &schema.StringType{T: t, Size: int(c.size)}
@@ -279,7 +301,21 @@ func columnType(c *columnDesc) (schema.Type, error) {
 		typeRegOper, typeRegOperator, typeRegProc, typeRegProcedure, typeRegRole, typeRegType:
 		typ = &OIDType{T: t}
 	case TypeUserDefined:
+		if t, ok, err := customTypes.ParseType(c.fmtype); ok {
+			return t, err
+		}
 		typ = &UserDefinedType{T: c.fmtype}
+		// Types that are provided by common extensions
+		// are parsed from their formatted definition.
+		if d, err := parseColumn(c.fmtype); err == nil && extensionType(d.typ) {
+			return columnType(d)
+		}
+	case TypeCIText:
+		typ = &schema.StringType{T: t}
+	case TypeVector, TypeHalfVec, TypeSparseVec:
+		typ = &VectorType{T: t, Dim: c.size}
+	case TypeHStore, TypeLTree, TypeLQuery:
+		typ = &UserDefinedType{T: t}
 	default:
 		typ = &schema.UnsupportedType{T: t}
 	}
@@ -297,6 +333,16 @@ func columnType(c *columnDesc) (schema.Type, error) {
 	return typ, nil
 }
 
+// extensionType reports if the given type is provided by one of the common
+// extensions, such as pgvector, citext, hstore or ltree.
+func extensionType(t string) bool {
+	switch strings.ToLower(t) {
+	case TypeVector, TypeHalfVec, TypeSparseVec, TypeCIText, TypeHStore, TypeLTree, TypeLQuery:
+		return true
+	}
+	return false
+}
+
 // reArray parses array declaration. See: https://postgresql.org/docs/current/arrays.html.
 var reArray = regexp.MustCompile(`(?i)(.+?)(( +ARRAY( *\[[ \d]*] *)*)+|( *\[[ \d]*] *)+)$`)
 
@@ -353,7 +399,7 @@ func parseColumn(s string) (*columnDesc, error) {
 		}
 	)
 	switch c.parts[0] {
-	case TypeVarChar, TypeCharVar, TypeChar, TypeCharacter:
+	case TypeVarChar, TypeCharVar, TypeChar, TypeCharacter, TypeString:
 		if err := parseCharParts(c.parts, c); err != nil {
 			return nil, err
 		}
@@ -374,6 +420,13 @@ func parseColumn(s string) (*columnDesc, error) {
 		if err := parseBitParts(parts, c); err != nil {
 			return nil, err
 		}
+	case TypeVector, TypeHalfVec, TypeSparseVec:
+		if len(parts) > 1 {
+			c.size, err = strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("postgres: parse vector dimensions %q: %w", parts[1], err)
+			}
+		}
 	case TypeDouble, TypeFloat8:
 		c.precision = 53
 	case TypeReal, TypeFloat4:
@@ -0,0 +1,114 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+var _ migrate.CostEstimator = (*Driver)(nil)
+
+// EstimateCost implements migrate.CostEstimator. Unlike the default estimation, it takes into
+// account the changes that PostgreSQL applies without rewriting the table, such as adding a
+// column with a constant default (since v11), increasing the size of a VARCHAR column, or
+// adding a constraint with the NOT VALID clause.
+func (d *Driver) EstimateCost(c schema.Change) migrate.Cost {
+	m, ok := c.(*schema.ModifyTable)
+	if !ok {
+		return migrate.CostUnknown
+	}
+	maxC := migrate.CostMetadata
+	for _, c := range m.Changes {
+		cc := d.changeCost(c)
+		if cc == migrate.CostUnknown {
+			cc = migrate.DefaultCost(c)
+		}
+		if cc > maxC {
+			maxC = cc
+		}
+	}
+	return maxC
+}
+
+// changeCost returns the cost of a table change, or CostUnknown
+// if it is not different from the default estimation.
+func (d *Driver) changeCost(c schema.Change) migrate.Cost {
+	switch c := c.(type) {
+	case *schema.AddColumn:
+		switch {
+		// Generated and identity columns are computed for all rows.
+		case sqlx.Has(c.C.Attrs, &schema.GeneratedExpr{}), sqlx.Has(c.C.Attrs, &Identity{}):
+			return migrate.CostTableRewrite
+		case c.C.Type != nil && isSerial(c.C.Type.Type):
+			return migrate.CostTableRewrite
+		case c.C.Default == nil:
+			return migrate.CostMetadata
+		}
+		// Since v11, non-volatile defaults are stored in the catalog. Expressions
+		// are not known to be non-volatile (e.g. random()), and therefore, they are
+		// considered as rewriting the table.
+		if _, ok := c.C.Default.(*schema.Literal); ok && d.version >= 11_00_00 {
+			return migrate.CostMetadata
+		}
+		return migrate.CostTableRewrite
+	case *schema.ModifyColumn:
+		switch {
+		case c.Change.Is(schema.ChangeType) && (c.From.Type == nil || c.To.Type == nil || !binaryCoercible(c.From.Type.Type, c.To.Type.Type)):
+			return migrate.CostTableRewrite
+		case c.Change.Is(schema.ChangeGenerated):
+			return migrate.CostTableRewrite
+		case c.Change.Is(schema.ChangeNull) && c.To.Type != nil && !c.To.Type.Null:
+			// Setting NOT NULL scans the table for validating no NULL values exist.
+			return migrate.CostIndexBuild
+		}
+		return migrate.CostMetadata
+	case *schema.AddForeignKey:
+		if sqlx.Has(c.Extra, &NotValid{}) {
+			return migrate.CostMetadata
+		}
+	case *schema.AddCheck:
+		if sqlx.Has(c.Extra, &NotValid{}) {
+			return migrate.CostMetadata
+		}
+	}
+	return migrate.CostUnknown
+}
+
+// binaryCoercible reports if a column of type "from" can be converted to type "to"
+// without rewriting the table, as their values are binary compatible.
+func binaryCoercible(from, to schema.Type) bool {
+	switch from := from.(type) {
+	case *schema.StringType:
+		to, ok := to.(*schema.StringType)
+		if !ok || !isVarChar(from.T) {
+			return false
+		}
+		switch {
+		case to.T == TypeText:
+			return true
+		case isVarChar(to.T):
+			return to.Size == 0 || from.Size != 0 && to.Size >= from.Size
+		}
+	case *schema.DecimalType:
+		to, ok := to.(*schema.DecimalType)
+		if !ok {
+			return false
+		}
+		// Unconstrained numeric, or increasing the precision without changing the scale.
+		return to.Precision == 0 || from.Precision != 0 && to.Scale == from.Scale && to.Precision >= from.Precision
+	}
+	return false
+}
+
+func isVarChar(t string) bool {
+	return t == TypeVarChar || t == TypeCharVar
+}
+
+func isSerial(t schema.Type) bool {
+	_, ok := t.(*SerialType)
+	return ok
+}
@@ -0,0 +1,68 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_EstimateCost(t *testing.T) {
+	var (
+		drv   = &Driver{conn: &conn{version: 15_00_00}}
+		drv10 = &Driver{conn: &conn{version: 10_00_00}}
+		tbl   = schema.NewTable("t")
+		col   = func(name string, t schema.Type, null bool) *schema.Column {
+			return schema.NewColumn(name).SetType(t).SetNull(null)
+		}
+		varchar = func(size int) *schema.StringType { return &schema.StringType{T: TypeCharVar, Size: size} }
+		numeric = func(p, s int) *schema.DecimalType { return &schema.DecimalType{T: TypeNumeric, Precision: p, Scale: s} }
+		modify  = func(from, to *schema.Column, k schema.ChangeKind) schema.Change {
+			return &schema.ModifyColumn{From: from, To: to, Change: k}
+		}
+	)
+	for _, tt := range []struct {
+		drv    *Driver
+		change schema.Change
+		cost   migrate.Cost
+	}{
+		// Changes that are not table modifications fall back to the default estimation.
+		{drv: drv, change: &schema.AddTable{T: tbl}, cost: migrate.CostUnknown},
+		// Adding columns.
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInteger}, true)}, cost: migrate.CostMetadata},
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInteger}, false).SetDefault(&schema.Literal{V: "1"})}, cost: migrate.CostMetadata},
+		{drv: drv10, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInteger}, false).SetDefault(&schema.Literal{V: "1"})}, cost: migrate.CostTableRewrite},
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.UUIDType{T: TypeUUID}, false).SetDefault(&schema.RawExpr{X: "gen_random_uuid()"})}, cost: migrate.CostTableRewrite},
+		{drv: drv, change: &schema.AddColumn{C: col("c", &SerialType{T: TypeSerial}, false)}, cost: migrate.CostTableRewrite},
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInteger}, false).AddAttrs(&Identity{Generation: "ALWAYS"})}, cost: migrate.CostTableRewrite},
+		{drv: drv, change: &schema.AddColumn{C: col("c", &schema.IntegerType{T: TypeInteger}, true).SetGeneratedExpr(&schema.GeneratedExpr{Expr: "a + 1", Type: "STORED"})}, cost: migrate.CostTableRewrite},
+		// Modifying columns.
+		{drv: drv, change: modify(col("c", varchar(10), true), col("c", varchar(20), true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", varchar(10), true), col("c", varchar(0), true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", varchar(10), true), col("c", &schema.StringType{T: TypeText}, true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", varchar(20), true), col("c", varchar(10), true), schema.ChangeType), cost: migrate.CostTableRewrite},
+		{drv: drv, change: modify(col("c", &schema.StringType{T: TypeText}, true), col("c", varchar(10), true), schema.ChangeType), cost: migrate.CostTableRewrite},
+		{drv: drv, change: modify(col("c", numeric(10, 2), true), col("c", numeric(12, 2), true), schema.ChangeType), cost: migrate.CostMetadata},
+		{drv: drv, change: modify(col("c", numeric(10, 2), true), col("c", numeric(12, 3), true), schema.ChangeType), cost: migrate.CostTableRewrite},
+		{drv: drv, change: modify(col("c", &schema.IntegerType{T: TypeInteger}, true), col("c", &schema.IntegerType{T: TypeBigInt}, true), schema.ChangeType), cost: migrate.CostTableRewrite},
+		{drv: drv, change: modify(col("c", varchar(10), true), col("c", varchar(20), false), schema.ChangeType|schema.ChangeNull), cost: migrate.CostIndexBuild},
+		{drv: drv, change: modify(col("c", varchar(10), false), col("c", varchar(10), true), schema.ChangeNull), cost: migrate.CostMetadata},
+		// Constraints.
+		{drv: drv, change: &schema.AddCheck{C: schema.NewCheck().SetExpr("c > 0")}, cost: migrate.CostIndexBuild},
+		{drv: drv, change: &schema.AddCheck{C: schema.NewCheck().SetExpr("c > 0"), Extra: []schema.Clause{&NotValid{}}}, cost: migrate.CostMetadata},
+		{drv: drv, change: &schema.AddForeignKey{F: schema.NewForeignKey("fk"), Extra: []schema.Clause{&NotValid{}}}, cost: migrate.CostMetadata},
+		{drv: drv, change: &schema.DropColumn{C: col("c", varchar(10), true)}, cost: migrate.CostDataLoss},
+	} {
+		c := tt.change
+		if _, ok := c.(*schema.AddTable); !ok {
+			c = &schema.ModifyTable{T: tbl, Changes: []schema.Change{c}}
+		}
+		require.Equal(t, tt.cost, tt.drv.EstimateCost(c), "change: %#v", tt.change)
+	}
+}
@@ -23,6 +23,7 @@ type (
 		migrate.Driver
 		migrate.Snapshoter
 		migrate.CleanChecker
+		migrate.Capable
 		schema.Normalizer
 	}
 	noLockDriver struct {
@@ -172,6 +173,13 @@ func (cd *crdbDiff) normalize(table *schema.Table) {
 				// Character without length specifier
 				// is equivalent to character(1).
 				t.Size = 1
+			// STRING is an alias for TEXT, and STRING(n)
+			// is an alias for CHARACTER VARYING(n).
+			case TypeString:
+				t.T = TypeText
+				if t.Size > 0 {
+					t.T = TypeCharVar
+				}
 			}
 		case *enumType:
 			c.Type.Type = &schema.EnumType{T: t.T, Values: t.Values}
@@ -262,6 +270,7 @@ func (i *inspect) crdbAddIndexes(s *schema.Schema, rows *sql.Rows) error {
 const (
 	TypeInt64    = "int64"
 	TypeGeometry = "geometry"
+	TypeString   = "string" // text or character varying(n).
 )
 
 const (
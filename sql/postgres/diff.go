@@ -8,7 +8,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -32,7 +34,7 @@ func (*diff) SchemaAttrDiff(from, to *schema.Schema) []schema.Change {
 	if change := sqlx.CommentDiff(skipDefaultComment(from), skipDefaultComment(to)); change != nil {
 		changes = append(changes, change)
 	}
-	return changes
+	return append(changes, privilegesDiff(from.Attrs, to.Attrs, schemaPrivileges)...)
 }
 
 func skipDefaultComment(s *schema.Schema) []schema.Attr {
@@ -43,14 +45,34 @@ func skipDefaultComment(s *schema.Schema) []schema.Attr {
 	return attrs
 }
 
+// RealmObjectDiff returns a changeset for migrating realm-level objects
+// (e.g. foreign servers or publications) from one state to the other.
+func (*diff) RealmObjectDiff(from, to *schema.Realm) ([]schema.Change, error) {
+	for _, r := range []*schema.Realm{from, to} {
+		for _, o := range r.Objects {
+			switch o.(type) {
+			case *ForeignServer, *UserMapping, *Publication:
+			default:
+				return nil, fmt.Errorf("unsupported realm object type %T", o)
+			}
+		}
+	}
+	return append(foreignServersDiff(from, to), publicationsDiff(from, to)...), nil
+}
+
 // SchemaObjectDiff returns a changeset for migrating schema objects from
 // one state to the other.
 func (*diff) SchemaObjectDiff(from, to *schema.Schema) ([]schema.Change, error) {
 	var changes []schema.Change
 	// Drop or modify enums.
 	for _, o1 := range from.Objects {
-		e1, ok := o1.(*schema.EnumType)
-		if !ok {
+		var e1 *schema.EnumType
+		switch o1 := o1.(type) {
+		case *schema.EnumType:
+			e1 = o1
+		case *ForeignTable:
+			continue // Handled by foreignTablesDiff.
+		default:
 			return nil, fmt.Errorf("unsupported object type %T", o1)
 		}
 		o2, ok := to.Object(func(o schema.Object) bool {
@@ -67,8 +89,13 @@ func (*diff) SchemaObjectDiff(from, to *schema.Schema) ([]schema.Change, error)
 	}
 	// Add new enums.
 	for _, o1 := range to.Objects {
-		e1, ok := o1.(*schema.EnumType)
-		if !ok {
+		var e1 *schema.EnumType
+		switch o1 := o1.(type) {
+		case *schema.EnumType:
+			e1 = o1
+		case *ForeignTable:
+			continue // Handled by foreignTablesDiff.
+		default:
 			return nil, fmt.Errorf("unsupported object type %T", o1)
 		}
 		if _, ok := from.Object(func(o schema.Object) bool {
@@ -78,7 +105,12 @@ func (*diff) SchemaObjectDiff(from, to *schema.Schema) ([]schema.Change, error)
 			changes = append(changes, &schema.AddObject{O: e1})
 		}
 	}
-	return changes, nil
+	// Foreign tables are handled separately.
+	fts, err := foreignTablesDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, fts...), nil
 }
 
 // TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
@@ -87,6 +119,10 @@ func (d *diff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
 	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
 		changes = append(changes, change)
 	}
+	changes = append(changes, privilegesDiff(from.Attrs, to.Attrs, tablePrivileges)...)
+	if change := tableStorageDiff(from.Attrs, to.Attrs); change != nil {
+		changes = append(changes, change)
+	}
 	if err := d.partitionChanged(from, to); err != nil {
 		return nil, err
 	}
@@ -117,6 +153,9 @@ func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column) (schema.Ch
 	if identityChanged(from.Attrs, to.Attrs) {
 		change |= schema.ChangeAttr
 	}
+	if collationChanged(from.Attrs, to.Attrs) {
+		change |= schema.ChangeCollate
+	}
 	if changed, err = d.generatedChanged(from, to); err != nil {
 		return schema.NoChange, err
 	}
@@ -153,17 +192,48 @@ func (d *diff) defaultChanged(from, to *schema.Column) (bool, error) {
 	return true, nil
 }
 
+// typeSizes holds the sizes of the types that are used for classifying type
+// conversions. Text types without a length limit have an unlimited size.
+var typeSizes = map[string]int64{
+	TypeSmallInt:  2,
+	TypeInt2:      2,
+	TypeInteger:   4,
+	TypeInt:       4,
+	TypeInt4:      4,
+	TypeBigInt:    8,
+	TypeInt8:      8,
+	TypeReal:      4,
+	TypeFloat4:    4,
+	TypeDouble:    8,
+	TypeFloat8:    8,
+	TypeCharacter: 1,
+	TypeChar:      1,
+	TypeCharVar:   math.MaxInt64,
+	TypeVarChar:   math.MaxInt64,
+	TypeText:      math.MaxInt64,
+	TypeBytea:     math.MaxInt64,
+}
+
+// TypeConversion classifies the conversion of the column values, when its type is changed.
+func (*diff) TypeConversion(from, to *schema.Column) schema.TypeConversion {
+	return sqlx.ClassifyConversion(from.Type.Type, to.Type.Type, typeSizes)
+}
+
 // generatedChanged reports if the generated expression of a column was changed.
 func (*diff) generatedChanged(from, to *schema.Column) (bool, error) {
-	var fromX, toX schema.GeneratedExpr
-	switch fromHas, toHas := sqlx.Has(from.Attrs, &fromX), sqlx.Has(to.Attrs, &toX); {
-	case fromHas && toHas && sqlx.MayWrap(fromX.Expr) != sqlx.MayWrap(toX.Expr):
+	if !sqlx.GeneratedChanged(from, to, generatedType) {
+		return false, nil
+	}
+	_, fromHas := from.Generated()
+	_, toHas := to.Generated()
+	switch {
+	case fromHas && toHas:
 		return false, fmt.Errorf("changing the generation expression for a column %q is not supported", from.Name)
-	case !fromHas && toHas:
+	case toHas:
 		return false, fmt.Errorf("changing column %q to generated column is not supported (drop and add is required)", from.Name)
 	default:
 		// Only DROP EXPRESSION is supported.
-		return fromHas && !toHas, nil
+		return true, nil
 	}
 }
 
@@ -227,8 +297,7 @@ func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
 	if indexNullsDistinct(to) != indexNullsDistinct(from) {
 		return true
 	}
-	var p1, p2 IndexPredicate
-	if sqlx.Has(from, &p1) != sqlx.Has(to, &p2) || (p1.P != p2.P && p1.P != sqlx.MayWrap(p2.P)) {
+	if predicateChanged(from, to) {
 		return true
 	}
 	if indexIncludeChanged(from, to) {
@@ -236,7 +305,12 @@ func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
 	}
 	s1, ok1 := indexStorageParams(from)
 	s2, ok2 := indexStorageParams(to)
-	return ok1 != ok2 || ok1 && *s1 != *s2
+	return ok1 != ok2 || ok1 && (!brinStorageEqual(s1, s2) || optionsChanged(s1.Params, s2.Params))
+}
+
+// brinStorageEqual reports if the BRIN storage parameters of the indexes are equal.
+func brinStorageEqual(s1, s2 *IndexStorageParams) bool {
+	return s1.AutoSummarize == s2.AutoSummarize && s1.PagesPerRange == s2.PagesPerRange
 }
 
 // IndexPartAttrChanged reports if the index-part attributes were changed.
@@ -285,6 +359,10 @@ type DiffOptions struct {
 		Add  bool `spec:"add"`
 		Drop bool `spec:"drop"`
 	} `spec:"concurrent_index"`
+	NotValid struct {
+		ForeignKey bool `spec:"foreign_key"`
+		Check      bool `spec:"check"`
+	} `spec:"not_valid"`
 }
 
 // AnnotateChanges implements the sqlx.ChangeAnnotator interface.
@@ -315,6 +393,15 @@ func (*diff) AnnotateChanges(changes []schema.Change, opts *schema.DiffOptions)
 				if extra.ConcurrentIndex.Drop {
 					c.Extra = append(c.Extra, &Concurrently{})
 				}
+			case *schema.AddForeignKey:
+				if extra.NotValid.ForeignKey {
+					c.Extra = append(c.Extra, &NotValid{})
+				}
+			case *schema.AddCheck:
+				// Unnamed constraints cannot be validated later.
+				if extra.NotValid.Check && c.C.Name != "" {
+					c.Extra = append(c.Extra, &NotValid{})
+				}
 			}
 		}
 	}
@@ -333,7 +420,7 @@ func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 	switch fromT := fromT.(type) {
 	case *schema.BinaryType, *BitType, *schema.BoolType, *schema.DecimalType, *schema.FloatType, *IntervalType,
 		*schema.IntegerType, *schema.JSONType, *OIDType, *RangeType, *SerialType, *schema.SpatialType,
-		*schema.StringType, *schema.TimeType, *TextSearchType, *NetworkType, *schema.UUIDType:
+		*schema.StringType, *schema.TimeType, *TextSearchType, *NetworkType, *schema.UUIDType, *VectorType:
 		t1, err := FormatType(toT)
 		if err != nil {
 			return false, err
@@ -377,7 +464,19 @@ func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 			changed = t1 != t2
 		}
 	default:
-		return false, &sqlx.UnsupportedTypeError{Type: fromT}
+		// Custom types are compared by their formatted definitions.
+		if _, ok, _ := customTypes.FormatType(fromT); !ok {
+			return false, &sqlx.UnsupportedTypeError{Type: fromT}
+		}
+		t1, err := FormatType(fromT)
+		if err != nil {
+			return false, err
+		}
+		t2, err := FormatType(toT)
+		if err != nil {
+			return false, err
+		}
+		changed = t1 != t2
 	}
 	return changed, nil
 }
@@ -428,6 +527,147 @@ const (
 	defaultSeqIncrement = 1
 )
 
+// The privileges that are granted by the ALL PRIVILEGES keyword.
+var (
+	schemaPrivileges = []string{"CREATE", "USAGE"}
+	tablePrivileges  = []string{"DELETE", "INSERT", "REFERENCES", "SELECT", "TRIGGER", "TRUNCATE", "UPDATE"}
+)
+
+// privilegesDiff returns the changes for granting or revoking the declared privileges.
+// Grantees that are not declared in the desired state are ignored, in order to keep
+// privileges that are managed outside of Atlas untouched. A grantee that is declared
+// with an empty list of privileges, has all its inspected privileges revoked.
+func privilegesDiff(from, to []schema.Attr, all []string) []schema.Change {
+	var changes []schema.Change
+	for _, a := range to {
+		p2, ok := a.(*Privilege)
+		if !ok {
+			continue
+		}
+		p1, ok := privilege(from, p2.Grantee, p2.GrantOption)
+		switch {
+		case !ok && len(p2.Privileges) > 0:
+			changes = append(changes, &schema.AddAttr{A: p2})
+		case ok && !sqlx.ValuesEqual(privilegeSet(p1, all), privilegeSet(p2, all)):
+			changes = append(changes, &schema.ModifyAttr{From: p1, To: p2})
+		}
+	}
+	return changes
+}
+
+// privilege returns the privilege attribute of the given grantee.
+func privilege(attrs []schema.Attr, grantee string, opt bool) (*Privilege, bool) {
+	for _, a := range attrs {
+		if p, ok := a.(*Privilege); ok && p.GrantOption == opt && granteeName(p.Grantee) == granteeName(grantee) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// privilegeSet returns the sorted and uppercased set of privileges held
+// by p, after expanding the ALL [PRIVILEGES] keyword using the given list.
+func privilegeSet(p *Privilege, all []string) []string {
+	set := make(map[string]struct{}, len(p.Privileges))
+	for _, v := range p.Privileges {
+		switch v = strings.ToUpper(strings.TrimSpace(v)); v {
+		case "ALL", "ALL PRIVILEGES":
+			for _, a := range all {
+				set[a] = struct{}{}
+			}
+		default:
+			set[v] = struct{}{}
+		}
+	}
+	privs := make([]string, 0, len(set))
+	for v := range set {
+		privs = append(privs, v)
+	}
+	sort.Strings(privs)
+	return privs
+}
+
+// granteeName returns the normalized name of the grantee.
+func granteeName(s string) string {
+	if s == "" || strings.EqualFold(s, "PUBLIC") {
+		return "PUBLIC"
+	}
+	return s
+}
+
+// predicateChanged reports if the predicate of a partial index was changed. Predicates are
+// compared after trimming redundant parentheses and whitespaces, because the expressions
+// returned by pg_get_expr are formatted differently from the ones written by users. Other
+// differences (e.g. implicit casts) are resolved when the desired state is normalized by
+// the dev database.
+func predicateChanged(from, to []schema.Attr) bool {
+	var p1, p2 IndexPredicate
+	if sqlx.Has(from, &p1) != sqlx.Has(to, &p2) {
+		return true
+	}
+	return p1.P != p2.P && normalizeExpr(p1.P) != normalizeExpr(p2.P)
+}
+
+// normalizeExpr collapses the whitespaces outside of string literals
+// and identifiers, and strips the outer parentheses of the expression.
+func normalizeExpr(x string) string {
+	var b strings.Builder
+	for i := 0; i < len(x); i++ {
+		switch c := x[i]; {
+		case c == '\'', c == '"':
+			j := strings.IndexByte(x[i+1:], c)
+			if j == -1 {
+				j = len(x) - i - 2
+			}
+			b.WriteString(x[i : i+j+2])
+			i += j + 1
+		case unicode.IsSpace(rune(c)):
+			for i+1 < len(x) && unicode.IsSpace(rune(x[i+1])) {
+				i++
+			}
+			// Skip whitespaces at the edges and around parentheses.
+			if s := b.String(); s != "" && s[len(s)-1] != '(' && i+1 < len(x) && x[i+1] != ')' {
+				b.WriteByte(' ')
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	s := b.String()
+	for len(s) > 1 && s[0] == '(' && s[len(s)-1] == ')' && wrapped(s) {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
+
+// wrapped reports if the first parenthesis in the expression is closed by the last one.
+func wrapped(x string) bool {
+	depth := 0
+	for i := 0; i < len(x); i++ {
+		switch c := x[i]; c {
+		case '\'', '"':
+			for i++; i < len(x) && x[i] != c; i++ {
+			}
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i == len(x)-1
+			}
+		}
+	}
+	return false
+}
+
+// collationChanged reports if the column collation was changed. A missing
+// collation attribute indicates the column uses the default collation.
+func collationChanged(from, to []schema.Attr) bool {
+	var c1, c2 schema.Collation
+	sqlx.Has(from, &c1)
+	sqlx.Has(to, &c2)
+	return c1.V != c2.V
+}
+
 // identityChanged reports if one of the identity attributes was changed.
 func identityChanged(from, to []schema.Attr) bool {
 	i1, ok1 := identity(from)
@@ -493,7 +733,7 @@ func indexStorageParams(attrs []schema.Attr) (*IndexStorageParams, bool) {
 	if !sqlx.Has(attrs, s) {
 		return nil, false
 	}
-	if !s.AutoSummarize && (s.PagesPerRange == 0 || s.PagesPerRange == defaultPagePerRange) {
+	if !s.AutoSummarize && (s.PagesPerRange == 0 || s.PagesPerRange == defaultPagePerRange) && len(s.Params) == 0 {
 		return nil, false
 	}
 	return s, true
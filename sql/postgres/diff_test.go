@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqltest"
 	"ariga.io/atlas/sql/schema"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -58,6 +59,67 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "privileges",
+			from: schema.NewTable("users").
+				AddAttrs(
+					&Privilege{Grantee: "app", Privileges: []string{"SELECT"}},
+					&Privilege{Grantee: "admin", Privileges: []string{"ALL"}},
+					&Privilege{Grantee: "PUBLIC", Privileges: []string{"SELECT"}},
+					&Privilege{Grantee: "unmanaged", Privileges: []string{"SELECT"}},
+				),
+			to: schema.NewTable("users").
+				AddAttrs(
+					&Privilege{Grantee: "app", Privileges: []string{"insert", "SELECT"}},
+					&Privilege{Grantee: "admin", Privileges: []string{"DELETE", "INSERT", "REFERENCES", "SELECT", "TRIGGER", "TRUNCATE", "UPDATE"}},
+					&Privilege{Grantee: "", Privileges: []string{}},
+					&Privilege{Grantee: "reader", Privileges: []string{"SELECT"}},
+				),
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{
+					From: &Privilege{Grantee: "app", Privileges: []string{"SELECT"}},
+					To:   &Privilege{Grantee: "app", Privileges: []string{"insert", "SELECT"}},
+				},
+				&schema.ModifyAttr{
+					From: &Privilege{Grantee: "PUBLIC", Privileges: []string{"SELECT"}},
+					To:   &Privilege{Grantee: "", Privileges: []string{}},
+				},
+				&schema.AddAttr{
+					A: &Privilege{Grantee: "reader", Privileges: []string{"SELECT"}},
+				},
+			},
+		},
+		{
+			name: "change vector dimensions",
+			from: schema.NewTable("items").AddColumns(schema.NewColumn("embedding").SetType(&VectorType{T: TypeVector, Dim: 3})),
+			to:   schema.NewTable("items").AddColumns(schema.NewColumn("embedding").SetType(&VectorType{T: TypeVector, Dim: 1536})),
+			wantChanges: []schema.Change{
+				&schema.ModifyColumn{
+					From:   schema.NewColumn("embedding").SetType(&VectorType{T: TypeVector, Dim: 3}),
+					To:     schema.NewColumn("embedding").SetType(&VectorType{T: TypeVector, Dim: 1536}),
+					Change: schema.ChangeType,
+				},
+			},
+		},
+		{
+			name: "storage parameters",
+			from: schema.NewTable("logs").
+				AddAttrs(&TableStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}}}),
+			to: schema.NewTable("logs"),
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{
+					From: &TableStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}}},
+					To:   &TableStorageParams{},
+				},
+			},
+		},
+		{
+			name: "storage parameters unchanged",
+			from: schema.NewTable("logs").
+				AddAttrs(&TableStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}, {N: "autovacuum_enabled", V: "false"}}}),
+			to: schema.NewTable("logs").
+				AddAttrs(&TableStorageParams{Params: []struct{ N, V string }{{N: "autovacuum_enabled", V: "false"}, {N: "fillfactor", V: "70"}}}),
+		},
 		{
 			name: "drop partition key",
 			from: schema.NewTable("logs").
@@ -238,6 +300,36 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			}
 		}(),
+		func() testcase {
+			var (
+				from = schema.NewTable("users").
+					SetSchema(schema.New("public")).
+					AddColumns(
+						schema.NewStringColumn("c1", "text"),
+						schema.NewStringColumn("c2", "text").SetCollation("C"),
+						schema.NewStringColumn("c3", "text").SetCollation("C"),
+						schema.NewStringColumn("c4", "text").SetCollation("C"),
+					)
+				to = schema.NewTable("users").
+					SetSchema(schema.New("public")).
+					AddColumns(
+						schema.NewStringColumn("c1", "text").SetCollation("C"),
+						schema.NewStringColumn("c2", "text").SetCollation("C"),
+						schema.NewStringColumn("c3", "text").SetCollation("en_US"),
+						schema.NewStringColumn("c4", "text"),
+					)
+			)
+			return testcase{
+				name: "collation",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeCollate},
+					&schema.ModifyColumn{From: from.Columns[2], To: to.Columns[2], Change: schema.ChangeCollate},
+					&schema.ModifyColumn{From: from.Columns[3], To: to.Columns[3], Change: schema.ChangeCollate},
+				},
+			}
+		}(),
 		// Modify enum type or values.
 		func() testcase {
 			var (
@@ -264,8 +356,8 @@ func TestDiff_TableDiff(t *testing.T) {
 				from: from,
 				to:   to,
 				wantChanges: []schema.Change{
-					&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeType},
-					&schema.ModifyColumn{From: from.Columns[2], To: to.Columns[2], Change: schema.ChangeType},
+					&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeType, Conversion: schema.ConversionWidening},
+					&schema.ModifyColumn{From: from.Columns[2], To: to.Columns[2], Change: schema.ChangeType, Conversion: schema.ConversionWidening},
 				},
 			}
 		}(),
@@ -329,6 +421,10 @@ func TestDiff_TableDiff(t *testing.T) {
 				{Name: "c5_include_added", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}},
 				{Name: "c5_include_dropped", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexInclude{Columns: from.Columns[:1]}}},
 				{Name: "c6_nulls_not_distinct", Unique: true, Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexNullsDistinct{V: true}}},
+				{Name: "c7_predicate_format", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "((c7 > 0) AND (c7 < 'a  b'))"}}},
+				{Name: "c7_predicate_changed", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "((c7 > 0) AND (c7 < 'a  b'))"}}},
+				{Name: "c8_params_changed", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}}}}},
+				{Name: "c8_params_order", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}, {N: "deduplicate_items", V: "off"}}}}},
 			}
 			to.Indexes = []*schema.Index{
 				{Name: "c1_index", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[0]}}},
@@ -340,6 +436,10 @@ func TestDiff_TableDiff(t *testing.T) {
 				{Name: "c5_include_added", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexInclude{Columns: from.Columns[:1]}}},
 				{Name: "c5_include_dropped", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}},
 				{Name: "c6_nulls_not_distinct", Unique: true, Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexNullsDistinct{V: false}}},
+				{Name: "c7_predicate_format", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "( (c7 > 0)\n  AND (c7 < 'a  b') )"}}},
+				{Name: "c7_predicate_changed", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "(c7 > 0) AND (c7 < 'a b')"}}},
+				{Name: "c8_params_changed", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "80"}}}}},
+				{Name: "c8_params_order", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexStorageParams{Params: []struct{ N, V string }{{N: "deduplicate_items", V: "off"}, {N: "fillfactor", V: "70"}}}}},
 			}
 			return testcase{
 				name: "indexes",
@@ -353,6 +453,8 @@ func TestDiff_TableDiff(t *testing.T) {
 					&schema.ModifyIndex{From: from.Indexes[6], To: to.Indexes[6], Change: schema.ChangeAttr},
 					&schema.ModifyIndex{From: from.Indexes[7], To: to.Indexes[7], Change: schema.ChangeAttr},
 					&schema.ModifyIndex{From: from.Indexes[8], To: to.Indexes[8], Change: schema.ChangeAttr},
+					&schema.ModifyIndex{From: from.Indexes[10], To: to.Indexes[10], Change: schema.ChangeAttr},
+					&schema.ModifyIndex{From: from.Indexes[11], To: to.Indexes[11], Change: schema.ChangeAttr},
 					&schema.AddIndex{I: to.Indexes[1]},
 				},
 			}
@@ -501,8 +603,8 @@ func TestDiff_SchemaDiff(t *testing.T) {
 		&schema.DropObject{O: from.Objects[0]},
 		&schema.ModifyObject{From: from.Objects[1], To: to.Objects[0]},
 		&schema.AddObject{O: to.Objects[2]},
-		&schema.ModifyTable{T: to.Tables[0], Changes: []schema.Change{&schema.AddColumn{C: to.Tables[0].Columns[0]}}},
 		&schema.DropTable{T: from.Tables[1]},
+		&schema.ModifyTable{T: to.Tables[0], Changes: []schema.Change{&schema.AddColumn{C: to.Tables[0].Columns[0]}}},
 		&schema.AddTable{T: to.Tables[1]},
 	}, changes)
 
@@ -569,6 +671,83 @@ func TestDiff_SchemaDiff(t *testing.T) {
 	})
 }
 
+func TestDiff_ForeignObjects(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	var (
+		from = schema.NewRealm(schema.New("public")).AddObjects(
+			&ForeignServer{Name: "dropped", Wrapper: "postgres_fdw"},
+			&ForeignServer{Name: "modified", Wrapper: "postgres_fdw", Options: []FDWOption{{"host", "a"}}},
+			&UserMapping{User: "public", Server: "modified"},
+			&UserMapping{User: "app", Server: "dropped"},
+		)
+		to = schema.NewRealm(schema.New("public")).AddObjects(
+			&ForeignServer{Name: "modified", Wrapper: "postgres_fdw", Options: []FDWOption{{"host", "b"}}},
+			&UserMapping{User: "PUBLIC", Server: "modified"},
+			&UserMapping{User: "app", Server: "added"},
+			&ForeignServer{Name: "added", Wrapper: "file_fdw"},
+		)
+	)
+	from.Schemas[0].AddObjects(
+		&ForeignTable{Name: "unchanged", Server: "modified", Columns: []*schema.Column{schema.NewIntColumn("id", "int")}},
+		&ForeignTable{Name: "dropped", Server: "dropped"},
+	)
+	to.Schemas[0].AddObjects(
+		&ForeignTable{Name: "unchanged", Server: "modified", Columns: []*schema.Column{schema.NewIntColumn("id", "int")}},
+		&ForeignTable{Name: "added", Server: "added", Options: []FDWOption{{"filename", "/tmp/a.csv"}}},
+	)
+	changes, err := drv.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.EqualValues(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[1], To: to.Objects[0]},
+		&schema.AddObject{O: to.Objects[3]},
+		&schema.AddObject{O: to.Objects[2]},
+		&schema.DropObject{O: from.Schemas[0].Objects[1]},
+		&schema.AddObject{O: to.Schemas[0].Objects[1]},
+		&schema.DropObject{O: from.Objects[3]},
+		&schema.DropObject{O: from.Objects[0]},
+	}, changes)
+}
+
+func TestCRDBDiff_StringAlias(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(paramsQuery)).
+		WillReturnRows(sqltest.Rows(`
+  setting
+------------
+ 130000
+ 13.0.0
+ en_US.utf8
+ en_US.utf8
+ cockroach
+`))
+	d, err := Open(db)
+	require.NoError(t, err)
+	from := schema.NewTable("users").
+		SetSchema(schema.New("public")).
+		AddColumns(
+			schema.NewStringColumn("name", TypeText),
+			schema.NewStringColumn("nick", TypeCharVar, schema.StringSize(32)),
+			schema.NewIntColumn("id", TypeBigInt),
+		)
+	from.SetPrimaryKey(schema.NewPrimaryKey(from.Columns[2]))
+	to := schema.NewTable("users").
+		SetSchema(schema.New("public")).
+		AddColumns(
+			schema.NewStringColumn("name", TypeString),
+			schema.NewStringColumn("nick", TypeString, schema.StringSize(32)),
+			schema.NewIntColumn("id", TypeInt8),
+		)
+	to.SetPrimaryKey(schema.NewPrimaryKey(to.Columns[2]))
+	changes, err := d.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
 func TestDefaultDiff(t *testing.T) {
 	changes, err := DefaultDiff.SchemaDiff(
 		schema.New("public").
@@ -607,3 +786,531 @@ concurrent_index {
 	require.Equal(t, `CREATE INDEX CONCURRENTLY "users_pkey_new" ON "public"."users" ("id")`, plan.Changes[1].Cmd)
 	require.Equal(t, `DROP INDEX CONCURRENTLY "public"."users_pkey_new"`, plan.Changes[1].Reverse)
 }
+
+func TestDiff_AnnotateNotValid(t *testing.T) {
+	var cfg struct {
+		schemahcl.DefaultExtension
+	}
+	// language=hcl
+	err := schemahcl.New().EvalBytes([]byte(`
+not_valid {
+  foreign_key = true
+  check       = true
+}
+`), &cfg, nil)
+	require.NoError(t, err)
+	var (
+		users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+		from  = schema.New("public").AddTables(
+			users,
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("owner_id", "int")),
+		)
+		to = schema.New("public").AddTables(
+			users,
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("owner_id", "int")),
+		)
+	)
+	to.Tables[1].
+		AddForeignKeys(schema.NewForeignKey("owner_fk").AddColumns(to.Tables[1].Columns[0]).SetRefTable(users).AddRefColumns(users.Columns[0])).
+		AddChecks(schema.NewCheck().SetName("owner_positive").SetExpr(`("owner_id" > 0)`))
+	changes, err := DefaultDiff.SchemaDiff(from, to, func(opts *schema.DiffOptions) { opts.Extra = cfg.DefaultExtension })
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "changes", changes)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 3)
+	require.Equal(t, `ALTER TABLE "public"."pets" ADD CONSTRAINT "owner_positive" CHECK ("owner_id" > 0) NOT VALID, ADD CONSTRAINT "owner_fk" FOREIGN KEY ("owner_id") REFERENCES "public"."users" ("id") NOT VALID`, plan.Changes[0].Cmd)
+	require.Equal(t, `ALTER TABLE "public"."pets" VALIDATE CONSTRAINT "owner_positive"`, plan.Changes[1].Cmd)
+	require.Equal(t, `ALTER TABLE "public"."pets" VALIDATE CONSTRAINT "owner_fk"`, plan.Changes[2].Cmd)
+	require.True(t, plan.Reversible)
+	stmts, err := plan.Changes[1].ReverseStmts()
+	require.NoError(t, err)
+	require.Empty(t, stmts)
+}
+
+func TestDiff_Publications(t *testing.T) {
+	var (
+		users = schema.NewTable("users").SetSchema(schema.New("public"))
+		pets  = schema.NewTable("pets").SetSchema(schema.New("public"))
+		from  = schema.NewRealm().AddObjects(
+			&Publication{Name: "dropped", AllTables: true},
+			&Publication{Name: "tables", Tables: []*schema.Table{users}},
+			&Publication{Name: "ops", AllTables: true},
+			&Publication{Name: "unchanged", Tables: []*schema.Table{users}},
+		)
+		to = schema.NewRealm().AddObjects(
+			&Publication{Name: "tables", Tables: []*schema.Table{users, pets}},
+			&Publication{Name: "ops", AllTables: true, Publish: []string{"INSERT"}},
+			// Tables are compared by their names.
+			&Publication{Name: "unchanged", Tables: []*schema.Table{schema.NewTable("users").SetSchema(schema.New("public"))}, Publish: []string{"insert", "update", "delete", "truncate"}},
+			&Publication{Name: "added", Tables: []*schema.Table{pets}},
+		)
+	)
+	changes, err := DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.EqualValues(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[1], To: to.Objects[0]},
+		&schema.ModifyObject{From: from.Objects[2], To: to.Objects[1]},
+		&schema.AddObject{O: to.Objects[3]},
+		&schema.DropObject{O: from.Objects[0]},
+	}, changes)
+}
+
+func TestRedshiftDiff(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape("SELECT version()")).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("PostgreSQL 8.0.2 on i686-pc-linux-gnu, Redshift 1.0.60103"))
+	d, err := OpenRedshift(db)
+	require.NoError(t, err)
+	from := schema.NewTable("events").
+		SetSchema(schema.New("public")).
+		AddColumns(
+			schema.NewIntColumn("id", TypeBigInt).AddAttrs(&Encode{T: "AZ64"}),
+			schema.NewIntColumn("user_id", TypeInteger).AddAttrs(&Encode{T: "AZ64"}),
+		)
+	from.AddAttrs(&DistStyle{S: DistStyleAuto})
+	to := schema.NewTable("events").
+		SetSchema(schema.New("public")).
+		AddColumns(
+			// Encodings that are not defined are chosen by Redshift.
+			schema.NewIntColumn("id", TypeBigInt),
+			schema.NewIntColumn("user_id", TypeInteger).AddAttrs(&Encode{T: "zstd"}),
+		)
+	to.AddAttrs(&DistStyle{Key: to.Columns[1]})
+	changes, err := d.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, &schema.ModifyAttr{From: &DistStyle{S: DistStyleAuto}, To: &DistStyle{S: DistStyleKey, Key: to.Columns[1]}}, changes[0])
+	require.Equal(t, schema.ChangeAttr, changes[1].(*schema.ModifyColumn).Change)
+	require.Equal(t, "user_id", changes[1].(*schema.ModifyColumn).To.Name)
+}
+
+func TestDiff_RenameSchema(t *testing.T) {
+	from := schema.NewRealm(
+		schema.New("old").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int")),
+		),
+	)
+	to := schema.NewRealm(
+		schema.New("new").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("age", "int")),
+		),
+	)
+	// Without a hint, the schema is dropped and recreated.
+	changes, err := DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.IsType(t, &schema.DropSchema{}, changes[0])
+	require.IsType(t, &schema.AddSchema{}, changes[1])
+	require.IsType(t, &schema.AddTable{}, changes[2])
+
+	changes, err = DefaultDiff.RealmDiff(from, to, schema.DiffRenameSchema("old", "new"))
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, &schema.RenameSchema{From: from.Schemas[0], To: to.Schemas[0]}, changes[0])
+	modify, ok := changes[2].(*schema.ModifyTable)
+	require.True(t, ok)
+	require.Equal(t, to.Schemas[0].Tables[0], modify.T)
+	require.Equal(t, []schema.Change{&schema.AddColumn{C: to.Schemas[0].Tables[0].Columns[1]}}, modify.Changes)
+	drop, ok := changes[1].(*schema.DropTable)
+	require.True(t, ok)
+	require.Equal(t, "pets", drop.T.Name)
+	require.Equal(t, "new", drop.T.Schema.Name, "dropped tables are qualified with the new schema name")
+	require.Equal(t, "old", from.Schemas[0].Tables[1].Schema.Name, "current state is not modified")
+}
+
+func TestDiff_Renames(t *testing.T) {
+	var (
+		from = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("name", "text")),
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner", "int")),
+		)
+		to = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("full_name", "text")),
+			schema.NewTable("animals").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner_id", "bigint")),
+		)
+	)
+	from.Tables[0].AddIndexes(schema.NewIndex("users_name").AddColumns(from.Tables[0].Columns[1]))
+	to.Tables[0].AddIndexes(schema.NewIndex("users_name").AddColumns(to.Tables[0].Columns[1]))
+	// Without hints, tables and columns are dropped and added.
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.IsType(t, &schema.DropTable{}, changes[0])
+	require.IsType(t, &schema.ModifyTable{}, changes[1])
+	require.IsType(t, &schema.AddTable{}, changes[2])
+
+	changes, err = DefaultDiff.SchemaDiff(from, to, schema.DiffRenameTable("pets", "animals"), schema.DiffRenameColumn("animals", "owner", "owner_id"))
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, &schema.RenameTable{From: from.Tables[1], To: to.Tables[1]}, changes[0])
+	require.IsType(t, &schema.ModifyTable{}, changes[2])
+	modify, ok := changes[1].(*schema.ModifyTable)
+	require.True(t, ok)
+	require.Equal(t, to.Tables[1], modify.T)
+	require.Len(t, modify.Changes, 2)
+	require.Equal(t, &schema.RenameColumn{From: from.Tables[1].Columns[1], To: to.Tables[1].Columns[1]}, modify.Changes[0])
+	require.Equal(t, "owner", modify.Changes[0].(*schema.RenameColumn).From.Name, "current state is not modified")
+	require.Equal(t, schema.ChangeType, modify.Changes[1].(*schema.ModifyColumn).Change)
+	require.Equal(t, "pets", from.Tables[1].Name)
+
+	// Identical columns are detected as renamed, and their indexes are not changed.
+	changes, err = DefaultDiff.SchemaDiff(from, to, schema.DiffDetectRenames())
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	modify, ok = changes[1].(*schema.ModifyTable)
+	require.True(t, ok)
+	require.Equal(t, []schema.Change{&schema.RenameColumn{From: from.Tables[0].Columns[1], To: to.Tables[0].Columns[1]}}, modify.Changes)
+	// The type of "owner" was changed. Hence, "pets" is not similar to "animals".
+	require.IsType(t, &schema.DropTable{}, changes[0])
+	require.IsType(t, &schema.AddTable{}, changes[2])
+
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "rename", changes[1:2])
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, `ALTER TABLE "public"."users" RENAME COLUMN "name" TO "full_name"`, plan.Changes[0].Cmd)
+
+	// Similar tables are detected as renamed, unless the match is ambiguous.
+	to.Tables[1].Columns[1].Name = "owner"
+	to.Tables[1].Columns[1].Type.Type = &schema.IntegerType{T: "int"}
+	changes, err = DefaultDiff.SchemaDiff(from, to, schema.DiffDetectRenames())
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, &schema.RenameTable{From: from.Tables[1], To: to.Tables[1]}, changes[0])
+	to.AddTables(schema.NewTable("pets2").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner", "int")))
+	changes, err = DefaultDiff.SchemaDiff(from, to, schema.DiffDetectRenames())
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+	require.IsType(t, &schema.DropTable{}, changes[0])
+	require.IsType(t, &schema.ModifyTable{}, changes[1])
+	require.IsType(t, &schema.AddTable{}, changes[2])
+	require.IsType(t, &schema.AddTable{}, changes[3])
+}
+
+func TestDiff_MoveTables(t *testing.T) {
+	newRealm := func(usersIn string) *schema.Realm {
+		var (
+			public = schema.New("public")
+			auth   = schema.New("auth")
+			users  = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+			pets   = schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner_id", "int"))
+		)
+		pets.AddForeignKeys(schema.NewForeignKey("owner").AddColumns(pets.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+		public.AddTables(pets)
+		if usersIn == "auth" {
+			auth.AddTables(users)
+			return schema.NewRealm(public, auth)
+		}
+		public.AddTables(users)
+		return schema.NewRealm(public)
+	}
+	from, to := newRealm("public"), newRealm("auth")
+	users1, users2 := from.Schemas[0].Tables[1], to.Schemas[1].Tables[0]
+
+	// Without hints, the table is dropped from its schema and added to the
+	// new one, and the foreign-key that references it is pointed to it.
+	changes, err := DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+	require.Equal(t, &schema.DropTable{T: users1}, changes[0])
+	modify, ok := changes[1].(*schema.ModifyTable)
+	require.True(t, ok)
+	require.Equal(t, schema.ChangeRefTable|schema.ChangeRefColumn, modify.Changes[0].(*schema.ModifyForeignKey).Change)
+	require.Equal(t, &schema.AddSchema{S: to.Schemas[1]}, changes[2])
+	require.Equal(t, &schema.AddTable{T: users2}, changes[3])
+
+	// Moved tables are moved after their new schema was created,
+	// and the foreign-keys that reference them are not changed.
+	for _, opt := range []schema.DiffOption{schema.DiffMoveTable("public.users", "auth.users"), schema.DiffDetectRenames()} {
+		changes, err = DefaultDiff.RealmDiff(from, to, opt)
+		require.NoError(t, err)
+		require.Equal(t, []schema.Change{
+			&schema.AddSchema{S: to.Schemas[1]},
+			&schema.RenameTable{From: users1, To: users2},
+		}, changes)
+	}
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "move", changes)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, `ALTER TABLE "public"."users" SET SCHEMA "auth"`, plan.Changes[1].Cmd)
+	require.Equal(t, `ALTER TABLE "auth"."users" SET SCHEMA "public"`, plan.Changes[1].Reverse)
+
+	// Schemas are dropped after their tables were moved out.
+	changes, err = DefaultDiff.RealmDiff(to, from, schema.DiffMoveTable("auth.users", "public.users"))
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.RenameTable{From: users2, To: users1},
+		&schema.DropSchema{S: to.Schemas[1]},
+	}, changes)
+
+	// Trigger changes of moved tables respect the skipped changes.
+	tr := &schema.Trigger{Name: "users_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, For: schema.TriggerForRow, Body: "EXECUTE FUNCTION f()"}
+	users1.AddTriggers(tr)
+	changes, err = DefaultDiff.RealmDiff(from, to, schema.DiffMoveTable("public.users", "auth.users"))
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.AddSchema{S: to.Schemas[1]},
+		&schema.RenameTable{From: users1, To: users2},
+		&schema.DropTrigger{T: tr},
+	}, changes)
+	changes, err = DefaultDiff.RealmDiff(from, to, schema.DiffMoveTable("public.users", "auth.users"), schema.DiffSkipChanges(&schema.DropTrigger{}))
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.AddSchema{S: to.Schemas[1]},
+		&schema.RenameTable{From: users1, To: users2},
+	}, changes)
+}
+
+func TestDiff_SortByName(t *testing.T) {
+	newSchema := func(names ...string) *schema.Schema {
+		s := schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+		)
+		for _, n := range names {
+			switch n {
+			case "pets":
+				pets := schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("zoo_id", "int"))
+				s.AddTables(pets)
+			default:
+				s.AddTables(schema.NewTable(n).AddColumns(schema.NewIntColumn("id", "int")))
+			}
+		}
+		// "pets" references "zoos", and therefore comes after it.
+		if pets, ok := s.Table("pets"); ok {
+			zoos, _ := s.Table("zoos")
+			pets.AddForeignKeys(schema.NewForeignKey("pets_zoo").AddColumns(pets.Columns[1]).SetRefTable(zoos).AddRefColumns(zoos.Columns[0]))
+		}
+		users := s.Tables[0]
+		users.AddColumns(schema.NewIntColumn("b", "int"), schema.NewIntColumn("a", "int"))
+		users.AddIndexes(
+			schema.NewIndex("users_b").AddColumns(users.Columns[1]),
+			schema.NewIndex("users_a").AddColumns(users.Columns[2]),
+		)
+		return s
+	}
+	from := schema.New("public").AddTables(
+		schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+	)
+	names := func(changes []schema.Change) []string {
+		var names []string
+		for _, c := range changes {
+			switch c := c.(type) {
+			case *schema.AddTable:
+				names = append(names, c.T.Name)
+			case *schema.ModifyTable:
+				names = append(names, c.T.Name)
+				for _, c := range c.Changes {
+					switch c := c.(type) {
+					case *schema.AddColumn:
+						names = append(names, c.C.Name)
+					case *schema.AddIndex:
+						names = append(names, c.I.Name)
+					}
+				}
+			}
+		}
+		return names
+	}
+	// By default, tables are sorted by their dependencies and then by their names,
+	// and the same changes are returned regardless of the order of the states.
+	for _, to := range []*schema.Schema{newSchema("pets", "zoos", "groups"), newSchema("groups", "zoos", "pets")} {
+		changes, err := DefaultDiff.SchemaDiff(from, to)
+		require.NoError(t, err)
+		require.Equal(t, []string{"users", "b", "a", "users_a", "users_b", "groups", "zoos", "pets"}, names(changes))
+	}
+
+	// Tables are sorted only by their names, and columns are kept in their ordinal position.
+	to := newSchema("pets", "zoos", "groups")
+	changes, err := DefaultDiff.SchemaDiff(from, to, schema.DiffSortByName())
+	require.NoError(t, err)
+	require.Equal(t, []string{"users", "b", "a", "users_a", "users_b", "groups", "pets", "zoos"}, names(changes))
+	// The states are not changed.
+	require.Equal(t, []string{"users", "pets", "zoos", "groups"}, []string{to.Tables[0].Name, to.Tables[1].Name, to.Tables[2].Name, to.Tables[3].Name})
+}
+
+func TestYugabyteDiff(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(paramsQuery)).
+		WillReturnRows(sqltest.Rows(`
+  setting
+------------
+ 110002
+ 11.2-YB-2.18.0.0-b0
+ en_US.utf8
+ en_US.utf8
+`))
+	d, err := Open(db)
+	require.NoError(t, err)
+	from := schema.NewTable("events").
+		SetSchema(schema.New("public")).
+		AddColumns(schema.NewIntColumn("id", TypeBigInt)).
+		AddAttrs(&TabletSplit{Tablets: 3})
+	// Tables inherit the colocation mode of their database, and
+	// the number of tablets is changed by automatic splitting.
+	to := schema.NewTable("events").
+		SetSchema(schema.New("public")).
+		AddColumns(schema.NewIntColumn("id", TypeBigInt)).
+		AddAttrs(&TabletSplit{Tablets: 8})
+	changes, err := d.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	to.AddAttrs(&Colocation{V: true})
+	changes, err = d.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.ModifyAttr{From: &Colocation{}, To: &Colocation{V: true}}}, changes)
+}
+
+func TestDiff_MaterializedView(t *testing.T) {
+	from := schema.New("public").AddViews(
+		schema.NewView("v1", "SELECT 1"),
+		schema.NewMaterializedView("v2", "SELECT 2"),
+	)
+	to := schema.New("public").AddViews(
+		schema.NewMaterializedView("v1", "SELECT 1"),
+		schema.NewMaterializedView("v2", "SELECT 2"),
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyView{From: from.Views[0], To: to.Views[0]},
+	}, changes)
+}
+
+func TestDiff_Triggers(t *testing.T) {
+	var (
+		from = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("name", "text")),
+		)
+		to = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("name", "text")),
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int")),
+		)
+		trigger = func(name string, events ...schema.TriggerEvent) *schema.Trigger {
+			return &schema.Trigger{
+				Name:       name,
+				ActionTime: schema.TriggerTimeAfter,
+				Events:     events,
+				For:        schema.TriggerForRow,
+				Body:       "EXECUTE FUNCTION audit()",
+			}
+		}
+	)
+	from.Tables[0].AddTriggers(
+		trigger("users_ai", schema.TriggerEventInsert),
+		trigger("users_au", schema.TriggerEventUpdateOf(from.Tables[0].Columns[0])),
+		trigger("users_ad", schema.TriggerEventDelete),
+	)
+	to.Tables[0].AddTriggers(
+		trigger("users_ai", schema.TriggerEventInsert),
+		trigger("users_au", schema.TriggerEventUpdateOf(to.Tables[0].Columns[1])),
+		trigger("users_bi", schema.TriggerEventInsert),
+	)
+	to.Tables[0].Triggers[0].Body += ";"
+	to.Tables[1].AddTriggers(trigger("pets_ai", schema.TriggerEventInsert))
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.AddTable{T: to.Tables[1]},
+		&schema.DropTrigger{T: from.Tables[0].Triggers[2]},
+		&schema.ModifyTrigger{From: from.Tables[0].Triggers[1], To: to.Tables[0].Triggers[1]},
+		&schema.AddTrigger{T: to.Tables[0].Triggers[2]},
+		&schema.AddTrigger{T: to.Tables[1].Triggers[0]},
+	}, changes)
+
+	changes, err = DefaultDiff.SchemaDiff(from, to, schema.DiffSkipDrops())
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+}
+
+func TestDiff_Funcs(t *testing.T) {
+	var (
+		from = schema.New("public").
+			AddFuncs(
+				&schema.Func{Name: "f1", Args: []*schema.FuncArg{{Name: "a", Type: &schema.IntegerType{T: "integer"}}}, Ret: &schema.IntegerType{T: "integer"}, Lang: "sql", Body: "SELECT a + 1"},
+				&schema.Func{Name: "f2", Ret: &schema.IntegerType{T: "integer"}, Lang: "sql", Body: "SELECT 1"},
+				&schema.Func{Name: "f3", Ret: &schema.IntegerType{T: "integer"}, Lang: "sql", Body: "SELECT 1"},
+			).
+			AddProcs(
+				&schema.Proc{Name: "p1", Lang: "plpgsql", Body: "BEGIN END"},
+			)
+		to = schema.New("public").
+			AddFuncs(
+				&schema.Func{Name: "f1", Args: []*schema.FuncArg{{Name: "a", Type: &schema.IntegerType{T: "integer"}, Mode: "in"}}, Ret: &schema.IntegerType{T: "integer"}, Lang: "SQL", Body: "SELECT a + 1;"},
+				&schema.Func{Name: "f2", Ret: &schema.IntegerType{T: "bigint"}, Lang: "sql", Body: "SELECT 1"},
+				&schema.Func{Name: "f4", Ret: &schema.IntegerType{T: "integer"}, Lang: "sql", Body: "SELECT 1"},
+			).
+			AddProcs(
+				&schema.Proc{Name: "p1", Args: []*schema.FuncArg{{Name: "a", Type: &schema.IntegerType{T: "integer"}, Mode: schema.FuncArgModeInOut}}, Lang: "plpgsql", Body: "BEGIN END"},
+				&schema.Proc{Name: "p2", Lang: "plpgsql", Body: "BEGIN END"},
+			)
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyFunc{From: from.Funcs[1], To: to.Funcs[1]},
+		&schema.DropFunc{F: from.Funcs[2]},
+		&schema.AddFunc{F: to.Funcs[2]},
+		&schema.ModifyProc{From: from.Procs[0], To: to.Procs[0]},
+		&schema.AddProc{P: to.Procs[1]},
+	}, changes)
+
+	// Functions, procedures and triggers of added schemas.
+	to.AddTables(schema.NewTable("t").AddTriggers(&schema.Trigger{Name: "t_ai", Body: "EXECUTE FUNCTION f4()"}))
+	changes, err = DefaultDiff.RealmDiff(schema.NewRealm(), schema.NewRealm(to))
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.AddSchema{S: to},
+		&schema.AddTable{T: to.Tables[0]},
+		&schema.AddFunc{F: to.Funcs[0]},
+		&schema.AddFunc{F: to.Funcs[1]},
+		&schema.AddFunc{F: to.Funcs[2]},
+		&schema.AddProc{P: to.Procs[0]},
+		&schema.AddProc{P: to.Procs[1]},
+		&schema.AddTrigger{T: to.Tables[0].Triggers[0]},
+	}, changes)
+}
+
+func TestDiff_Sequences(t *testing.T) {
+	var (
+		from = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint")),
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "bigint")),
+		)
+		to = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint")),
+		)
+	)
+	from.AddSequences(
+		schema.NewSequence("users_id_seq").SetStart(1).SetIncrement(1).SetOwner(from.Tables[0], from.Tables[0].Columns[0]),
+		schema.NewSequence("pets_id_seq").SetOwner(from.Tables[1], from.Tables[1].Columns[0]),
+		schema.NewSequence("s1"),
+		schema.NewSequence("s2").SetStart(10),
+	)
+	to.AddSequences(
+		schema.NewSequence("users_id_seq").SetStart(1).SetIncrement(1).SetOwner(to.Tables[0], to.Tables[0].Columns[0]),
+		schema.NewSequence("s2").SetStart(100),
+		schema.NewSequence("s3"),
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifySequence{From: from.Sequences[3], To: to.Sequences[1]},
+		&schema.AddSequence{S: to.Sequences[2]},
+		&schema.DropTable{T: from.Tables[1]},
+		&schema.DropSequence{S: from.Sequences[2]},
+	}, changes)
+
+	// Owner changes.
+	to.Sequences[0].Owner = schema.SequenceOwner{}
+	changes, err = DefaultDiff.SchemaDiff(from, to, schema.DiffSkipDrops())
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifySequence{From: from.Sequences[3], To: to.Sequences[1]},
+		&schema.ModifySequence{From: from.Sequences[0], To: to.Sequences[0]},
+		&schema.AddSequence{S: to.Sequences[2]},
+	}, changes)
+}
@@ -12,6 +12,7 @@ import (
 	"hash/fnv"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"ariga.io/atlas/sql/internal/sqlx"
@@ -36,13 +37,17 @@ type (
 		// The schema in the `search_path` parameter (if given).
 		schema string
 		// System variables that are set on `Open`.
-		collate string
-		ctype   string
-		version int
-		crdb    bool
+		collate  string
+		ctype    string
+		version  int
+		crdb     bool
+		redshift bool
+		yugabyte bool
 	}
 )
 
+var _ schema.Normalizer = (*Driver)(nil)
+
 // DriverName holds the name used for registration.
 const DriverName = "postgres"
 
@@ -55,6 +60,13 @@ func init() {
 		sqlclient.RegisterCodec(MarshalHCL, EvalHCL),
 		sqlclient.RegisterURLParser(parser{}),
 	)
+	sqlclient.Register(
+		RedshiftDriverName,
+		sqlclient.OpenerFunc(redshiftOpener),
+		sqlclient.RegisterDriverOpener(OpenRedshift),
+		sqlclient.RegisterCodec(MarshalHCL, EvalHCL),
+		sqlclient.RegisterURLParser(redshiftParser{}),
+	)
 }
 
 func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
@@ -95,18 +107,31 @@ func Open(db schema.ExecQuerier) (migrate.Driver, error) {
 	if err != nil {
 		return nil, fmt.Errorf("postgres: failed scanning rows: %w", err)
 	}
-	if len(params) != 3 && len(params) != 4 {
+	if len(params) != 4 && len(params) != 5 {
 		return nil, fmt.Errorf("postgres: unexpected number of rows: %d", len(params))
 	}
-	c.ctype, c.collate = params[1], params[2]
+	c.ctype, c.collate = params[2], params[3]
 	if c.version, err = strconv.Atoi(params[0]); err != nil {
 		return nil, fmt.Errorf("postgres: malformed version: %s: %w", params[0], err)
 	}
 	if c.version < 10_00_00 {
 		return nil, fmt.Errorf("postgres: unsupported postgres version: %d", c.version)
 	}
+	// YugabyteDB reports its version as a suffix of the PostgreSQL
+	// version it is based on. e.g. 11.2-YB-2.18.0.0-b0.
+	if c.yugabyte = strings.Contains(params[1], "-YB-"); c.yugabyte {
+		// Advisory locks are not supported by all YugabyteDB versions.
+		return noLockDriver{
+			&Driver{
+				conn:        c,
+				Differ:      &sqlx.Diff{DiffDriver: &ybDiff{diff{c}}},
+				Inspector:   &ybInspect{inspect{c}},
+				PlanApplier: &planApply{c},
+			},
+		}, nil
+	}
 	// Means we are connected to CockroachDB because we have a result for name='crdb_version'. see `paramsQuery`.
-	if c.crdb = len(params) == 4; c.crdb {
+	if c.crdb = len(params) == 5; c.crdb {
 		return noLockDriver{
 			&Driver{
 				conn:        c,
@@ -132,6 +157,19 @@ func (d *Driver) dev() *sqlx.DevDriver {
 				e.Schema = s
 			}
 		},
+		// Foreign tables cannot be created without their servers, which are
+		// realm-level objects, and they are not inspected by default.
+		SkipObject: func(o schema.Object) bool {
+			_, ok := o.(*ForeignTable)
+			return ok
+		},
+		RelinkObject: relinkPublication,
+		// Privileges are not inspected by default, and might be granted
+		// to roles that do not exist in the dev database.
+		CarryAttr: func(a schema.Attr) bool {
+			_, ok := a.(*Privilege)
+			return ok
+		},
 	}
 }
 
@@ -174,6 +212,69 @@ func (d *Driver) Lock(ctx context.Context, name string, timeout time.Duration) (
 	}, nil
 }
 
+// SetTimeouts implements migrate.TimeoutSetter by setting the statement_timeout and
+// the lock_timeout parameters. In transactions, the parameters are set using SET LOCAL,
+// and they are reset by the database when the transaction ends. Otherwise, the driver
+// is pinned to a dedicated connection until the returned function restores the previous
+// values of the parameters and releases the connection. Redshift does not support the
+// lock_timeout parameter, and statements wait for locks until they time out.
+func (d *Driver) SetTimeouts(ctx context.Context, stmt, lock time.Duration) (migrate.RestoreFunc, error) {
+	var params [][2]string
+	if stmt > 0 {
+		params = append(params, [2]string{"statement_timeout", strconv.FormatInt(stmt.Milliseconds(), 10)})
+	}
+	if lock > 0 && !d.redshift {
+		params = append(params, [2]string{"lock_timeout", strconv.FormatInt(lock.Milliseconds(), 10)})
+	}
+	nop := func(context.Context) error { return nil }
+	if len(params) == 0 {
+		return nop, nil
+	}
+	if sqlx.InTx(d.ExecQuerier) {
+		for _, p := range params {
+			if _, err := d.ExecContext(ctx, fmt.Sprintf("SET LOCAL %s = %s", p[0], p[1])); err != nil {
+				return nil, err
+			}
+		}
+		return nop, nil
+	}
+	release, err := sqlx.PinConn(ctx, &d.ExecQuerier)
+	if err != nil {
+		return nil, err
+	}
+	var prev [][2]string
+	restore := func(ctx context.Context) error {
+		var err error
+		for _, p := range prev {
+			if _, err1 := d.ExecContext(ctx, "SELECT set_config($1, $2, false)", p[0], p[1]); err1 != nil && err == nil {
+				err = err1
+			}
+		}
+		if err1 := release(); err1 != nil && err == nil {
+			err = err1
+		}
+		return err
+	}
+	for _, p := range params {
+		var v string
+		rows, err := d.QueryContext(ctx, "SELECT current_setting($1)", p[0])
+		if err == nil {
+			err = sqlx.ScanOne(rows, &v)
+		}
+		if err == nil {
+			_, err = d.ExecContext(ctx, fmt.Sprintf("SET %s = %s", p[0], p[1]))
+		}
+		if err != nil {
+			if err1 := restore(ctx); err1 != nil {
+				err = fmt.Errorf("%w: restore timeouts: %v", err, err1)
+			}
+			return nil, err
+		}
+		prev = append(prev, [2]string{p[0], v})
+	}
+	return restore, nil
+}
+
 // Snapshot implements migrate.Snapshoter.
 func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
 	// Postgres will only then be considered bound to a schema if the `search_path` was given.
@@ -272,6 +373,31 @@ func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error
 	return nil
 }
 
+// Capabilities implements migrate.Capable.
+func (d *Driver) Capabilities() migrate.Capabilities {
+	c := migrate.Capabilities{
+		TransactionalDDL: true,
+		Check:            true,
+		ForeignKey:       true,
+		DropColumn:       true,
+		RenameColumn:     true,
+		ModifyColumn:     true,
+		OnlineIndex:      true,
+	}
+	switch {
+	case d.crdb:
+		c.TransactionalDDL = false
+	case d.yugabyte:
+		// Changes that require a table rewrite are not supported by YugabyteDB.
+		c.TransactionalDDL, c.ModifyColumn = false, false
+	case d.redshift:
+		// Redshift does not support CHECK constraints and indexes, and supports
+		// only increasing the size of VARCHAR columns.
+		c.Check, c.ModifyColumn, c.OnlineIndex = false, false, false
+	}
+	return c
+}
+
 // Version returns the version of the connected database.
 func (d *Driver) Version() string {
 	return strconv.Itoa(d.conn.version)
@@ -425,6 +551,15 @@ const (
 	TypeDateRange      = "daterange"
 	TypeDateMultiRange = "datemultirange"
 
+	// Types provided by common extensions.
+	TypeVector    = "vector"    // pgvector.
+	TypeHalfVec   = "halfvec"   // pgvector.
+	TypeSparseVec = "sparsevec" // pgvector.
+	TypeHStore    = "hstore"
+	TypeCIText    = "citext"
+	TypeLTree     = "ltree"
+	TypeLQuery    = "lquery"
+
 	// PostgreSQL internal object types and their aliases.
 	typeOID           = "oid"
 	typeRegClass      = "regclass"
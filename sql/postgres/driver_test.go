@@ -6,6 +6,7 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"io"
 	"testing"
 	"time"
@@ -153,6 +154,35 @@ func TestDriver_Version(t *testing.T) {
 	require.Equal(t, "130000", drv.(vr).Version())
 }
 
+func TestDriver_Capabilities(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	c, ok := migrate.DriverCapabilities(drv)
+	require.True(t, ok)
+	require.Equal(t, migrate.Capabilities{
+		TransactionalDDL: true,
+		Check:            true,
+		ForeignKey:       true,
+		DropColumn:       true,
+		RenameColumn:     true,
+		ModifyColumn:     true,
+		OnlineIndex:      true,
+	}, c)
+
+	c = noLockDriver{&Driver{conn: &conn{crdb: true}}}.Capabilities()
+	require.False(t, c.TransactionalDDL)
+	require.True(t, c.ModifyColumn)
+	c = noLockDriver{&Driver{conn: &conn{yugabyte: true}}}.Capabilities()
+	require.False(t, c.TransactionalDDL)
+	require.False(t, c.ModifyColumn)
+	c = (&Driver{conn: &conn{redshift: true}}).Capabilities()
+	require.True(t, c.TransactionalDDL)
+	require.False(t, c.Check)
+}
+
 type mockInspector struct {
 	schema.Inspector
 	realm  *schema.Realm
@@ -169,3 +199,81 @@ func (m *mockInspector) InspectSchema(context.Context, string, *schema.InspectOp
 func (m *mockInspector) InspectRealm(context.Context, *schema.InspectRealmOption) (*schema.Realm, error) {
 	return m.realm, nil
 }
+
+func TestDriver_SetTimeouts(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	// Outside of transactions, the previous values are restored.
+	m.ExpectQuery(sqltest.Escape("SELECT current_setting($1)")).
+		WithArgs("statement_timeout").
+		WillReturnRows(sqlmock.NewRows([]string{"current_setting"}).AddRow("0"))
+	m.ExpectExec(sqltest.Escape("SET statement_timeout = 1500")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectQuery(sqltest.Escape("SELECT current_setting($1)")).
+		WithArgs("lock_timeout").
+		WillReturnRows(sqlmock.NewRows([]string{"current_setting"}).AddRow("1s"))
+	m.ExpectExec(sqltest.Escape("SET lock_timeout = 100")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SELECT set_config($1, $2, false)")).
+		WithArgs("statement_timeout", "0").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SELECT set_config($1, $2, false)")).
+		WithArgs("lock_timeout", "1s").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	drv := &Driver{conn: &conn{ExecQuerier: db}}
+	var d migrate.TimeoutSetter = drv
+	restore, err := d.SetTimeouts(context.Background(), 1500*time.Millisecond, 100*time.Millisecond)
+	require.NoError(t, err)
+	_, ok := drv.ExecQuerier.(*sql.Conn)
+	require.True(t, ok, "driver should be pinned to a dedicated connection")
+	require.NoError(t, restore(context.Background()))
+	require.Equal(t, db, drv.ExecQuerier)
+
+	// In transactions, the parameters are set locally.
+	m.ExpectBegin()
+	m.ExpectExec(sqltest.Escape("SET LOCAL statement_timeout = 60000")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SET LOCAL lock_timeout = 1000")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	d = &Driver{conn: &conn{ExecQuerier: tx}}
+	restore, err = d.SetTimeouts(context.Background(), time.Minute, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, restore(context.Background()))
+
+	// Lock timeout is not supported by Redshift.
+	m.ExpectExec(sqltest.Escape("SET LOCAL statement_timeout = 60000")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	d = &Driver{conn: &conn{ExecQuerier: tx, redshift: true}}
+	_, err = d.SetTimeouts(context.Background(), time.Minute, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_RelinkPublication(t *testing.T) {
+	var (
+		users = schema.NewTable("users")
+		r     = schema.NewRealm(schema.New("public").AddTables(users))
+		p     = &Publication{Name: "pub", Tables: []*schema.Table{users}}
+		nr    = schema.NewRealm(schema.New("public").AddTables(schema.NewTable("users")))
+	)
+	r.AddObjects(p)
+	o, err := relinkPublication(nr, p)
+	require.NoError(t, err)
+	require.Equal(t, []*schema.Table{nr.Schemas[0].Tables[0]}, o.(*Publication).Tables)
+	require.True(t, o.(*Publication).Tables[0] == nr.Schemas[0].Tables[0])
+	// The given publication is not modified.
+	require.True(t, p.Tables[0] == users)
+
+	_, err = relinkPublication(schema.NewRealm(schema.New("public")), p)
+	require.EqualError(t, err, `postgres: table "users" of publication "pub" was not found`)
+	_, err = relinkPublication(schema.NewRealm(), p)
+	require.EqualError(t, err, `postgres: schema "public" of publication "pub" was not found`)
+
+	// Other objects are returned as-is.
+	s := &ForeignServer{Name: "srv"}
+	o, err = relinkPublication(nr, s)
+	require.NoError(t, err)
+	require.True(t, o == schema.Object(s))
+}
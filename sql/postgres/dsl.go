@@ -0,0 +1,57 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import "ariga.io/atlas/sql/schema"
+
+// The functions below extend the schema builder API (e.g. schema.NewTable) with
+// columns of PostgreSQL-specific types and attributes. For example:
+//
+//	users := schema.NewTable("users").
+//		AddColumns(
+//			postgres.NewIdentityColumn("id", postgres.TypeBigInt),
+//			postgres.NewUUIDColumn("uid"),
+//		)
+
+// NewSerialColumn creates a new SerialType column. e.g. bigserial.
+func NewSerialColumn(name, typ string) *schema.Column {
+	return schema.NewColumn(name).
+		SetType(&SerialType{T: typ})
+}
+
+// NewIdentityColumn creates a new IntegerType column, defined as an identity column
+// with the default generation (BY DEFAULT) and sequence options. The Identity
+// attribute can be replaced to configure them:
+//
+//	c := NewIdentityColumn("id", TypeBigInt)
+//	schema.ReplaceOrAppend(&c.Attrs, &Identity{Generation: "ALWAYS", Sequence: &Sequence{Start: 1000}})
+func NewIdentityColumn(name, typ string) *schema.Column {
+	return schema.NewIntColumn(name, typ).
+		AddAttrs(&Identity{})
+}
+
+// NewUUIDColumn creates a new UUID column.
+func NewUUIDColumn(name string) *schema.Column {
+	return schema.NewColumn(name).
+		SetType(&schema.UUIDType{T: TypeUUID})
+}
+
+// NewNullUUIDColumn creates a new nullable UUID column.
+func NewNullUUIDColumn(name string) *schema.Column {
+	return NewUUIDColumn(name).
+		SetNull(true)
+}
+
+// NewVectorColumn creates a new pgvector column with the given number of dimensions.
+func NewVectorColumn(name string, dim int64) *schema.Column {
+	return schema.NewColumn(name).
+		SetType(&VectorType{T: TypeVector, Dim: dim})
+}
+
+// NewNullVectorColumn creates a new nullable pgvector column with the given number of dimensions.
+func NewNullVectorColumn(name string, dim int64) *schema.Column {
+	return NewVectorColumn(name, dim).
+		SetNull(true)
+}
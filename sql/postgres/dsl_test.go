@@ -0,0 +1,29 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSL_Columns(t *testing.T) {
+	users := schema.NewTable("users").
+		SetSchema(schema.New("s")).
+		AddColumns(
+			NewIdentityColumn("id", TypeBigInt),
+			NewSerialColumn("seq", TypeSerial),
+			NewNullUUIDColumn("uid"),
+			NewVectorColumn("embedding", 3),
+		)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: users}})
+	require.NoError(t, err)
+	require.Equal(t, `CREATE TABLE "s"."users" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "seq" serial NOT NULL, "uid" uuid NULL, "embedding" vector(3) NOT NULL, PRIMARY KEY ("id"))`, plan.Changes[0].Cmd)
+}
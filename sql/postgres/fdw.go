@@ -0,0 +1,614 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// ForeignServer describes a foreign server. Foreign servers are not bound
+	// to a specific schema, and therefore, are stored in the realm objects.
+	// https://www.postgresql.org/docs/current/sql-createserver.html
+	ForeignServer struct {
+		schema.Object
+		Name    string
+		Wrapper string // Foreign-data wrapper. e.g. postgres_fdw.
+		Type    string // Optional server type.
+		Version string // Optional server version.
+		Options []FDWOption
+	}
+
+	// UserMapping describes a mapping of a user to a foreign server.
+	// User mappings are stored in the realm objects.
+	// https://www.postgresql.org/docs/current/sql-createusermapping.html
+	UserMapping struct {
+		schema.Object
+		User    string // Role name. PUBLIC stands for all roles.
+		Server  string
+		Options []FDWOption
+	}
+
+	// FDWOption is an option of a foreign-data object, as
+	// defined in its OPTIONS clause. e.g. host 'localhost'.
+	FDWOption struct {
+		N, V string
+	}
+
+	// ForeignTable describes a foreign table. Foreign tables are stored
+	// in the objects of the schema they reside in.
+	// https://www.postgresql.org/docs/current/sql-createforeigntable.html
+	ForeignTable struct {
+		schema.Object
+		Name    string
+		Schema  *schema.Schema
+		Columns []*schema.Column
+		Server  string
+		Options []FDWOption
+	}
+)
+
+// inspectForeign queries and appends the foreign-data objects of the realm.
+func (i *inspect) inspectForeign(ctx context.Context, r *schema.Realm) error {
+	if err := i.foreignServers(ctx, r); err != nil {
+		return err
+	}
+	if err := i.userMappings(ctx, r); err != nil {
+		return err
+	}
+	return i.foreignTables(ctx, r)
+}
+
+// foreignServers queries and appends the foreign servers to the realm.
+func (i *inspect) foreignServers(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, foreignServersQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying foreign servers: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name, wrapper         string
+			typ, version, options sql.NullString
+		)
+		if err := rows.Scan(&name, &wrapper, &typ, &version, &options); err != nil {
+			return fmt.Errorf("postgres: scanning foreign server: %w", err)
+		}
+		opts, err := parseOptions[FDWOption](options.String)
+		if err != nil {
+			return fmt.Errorf("postgres: parsing options of foreign server %q: %w", name, err)
+		}
+		r.AddObjects(&ForeignServer{Name: name, Wrapper: wrapper, Type: typ.String, Version: version.String, Options: opts})
+	}
+	return rows.Close()
+}
+
+// userMappings queries and appends the user mappings to the realm.
+func (i *inspect) userMappings(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, userMappingsQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying user mappings: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			user, server string
+			options      sql.NullString
+		)
+		if err := rows.Scan(&user, &server, &options); err != nil {
+			return fmt.Errorf("postgres: scanning user mapping: %w", err)
+		}
+		opts, err := parseOptions[FDWOption](options.String)
+		if err != nil {
+			return fmt.Errorf("postgres: parsing options of user mapping %q for server %q: %w", user, server, err)
+		}
+		// The "public" user stands for the PUBLIC mapping.
+		if user == "public" {
+			user = "PUBLIC"
+		}
+		r.AddObjects(&UserMapping{User: user, Server: server, Options: opts})
+	}
+	return rows.Close()
+}
+
+// foreignTables queries and appends the foreign tables and their columns to the realm schemas.
+func (i *inspect) foreignTables(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(foreignTablesQuery, nArgs(0, len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying foreign tables: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, name, server string
+			options          sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &server, &options); err != nil {
+			return fmt.Errorf("postgres: scanning foreign table: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("postgres: schema %q for foreign table %q was not found in inspection", ns, name)
+		}
+		opts, err := parseOptions[FDWOption](options.String)
+		if err != nil {
+			return fmt.Errorf("postgres: parsing options of foreign table %q: %w", name, err)
+		}
+		s.AddObjects(&ForeignTable{Name: name, Schema: s, Server: server, Options: opts})
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		fts := foreignTablesOf(s)
+		if len(fts) == 0 {
+			continue
+		}
+		if err := i.columns(ctx, s, queryScope{
+			exec: func(ctx context.Context, q string, s *schema.Schema) (*sql.Rows, error) {
+				args := []any{s.Name}
+				for _, t := range fts {
+					args = append(args, t.Name)
+				}
+				return i.QueryContext(ctx, fmt.Sprintf(q, nArgs(1, len(fts))), args...)
+			},
+			append: func(_ *schema.Schema, table string, c *schema.Column) error {
+				for _, t := range fts {
+					if t.Name == table {
+						t.Columns = append(t.Columns, c)
+						return nil
+					}
+				}
+				return fmt.Errorf("foreign table %q was not found in schema", table)
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foreignTablesOf returns the foreign tables of the given schema.
+func foreignTablesOf(s *schema.Schema) []*ForeignTable {
+	var fts []*ForeignTable
+	for _, o := range s.Objects {
+		if t, ok := o.(*ForeignTable); ok {
+			fts = append(fts, t)
+		}
+	}
+	return fts
+}
+
+// parseOptions parses the text representation of an options array
+// (e.g. {host=localhost,"dbname=a,b"}) as stored in the system catalogs.
+func parseOptions[T option](s string) ([]T, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "{}" {
+		return nil, nil
+	}
+	if s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("unexpected options array: %q", s)
+	}
+	var (
+		opts  []T
+		elems []string
+		b     strings.Builder
+		inQ   bool
+	)
+	for i := 1; i < len(s)-1; i++ {
+		switch c := s[i]; {
+		case c == '\\' && inQ && i+1 < len(s)-1:
+			i++
+			b.WriteByte(s[i])
+		case c == '"':
+			inQ = !inQ
+		case c == ',' && !inQ:
+			elems = append(elems, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	elems = append(elems, b.String())
+	for _, e := range elems {
+		n, v, ok := strings.Cut(e, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid option: %q", e)
+		}
+		opts = append(opts, T(struct{ N, V string }{N: n, V: v}))
+	}
+	return opts, nil
+}
+
+// foreignServersDiff returns a changeset for migrating the
+// foreign servers and user mappings from one state to the other.
+func foreignServersDiff(from, to *schema.Realm) []schema.Change {
+	var (
+		changes []schema.Change
+		dropS   []schema.Change
+	)
+	for _, o := range from.Objects {
+		switch o1 := o.(type) {
+		case *ForeignServer:
+			switch o2, ok := findServer(to, o1.Name); {
+			case !ok:
+				dropS = append(dropS, &schema.DropObject{O: o1})
+			case o1.Wrapper != o2.Wrapper || o1.Type != o2.Type || o1.Version != o2.Version || optionsChanged(o1.Options, o2.Options):
+				changes = append(changes, &schema.ModifyObject{From: o1, To: o2})
+			}
+		case *UserMapping:
+			switch o2, ok := findMapping(to, o1.User, o1.Server); {
+			case !ok:
+				// User mappings are dropped before their servers.
+				changes = append(changes, &schema.DropObject{O: o1})
+			case optionsChanged(o1.Options, o2.Options):
+				changes = append(changes, &schema.ModifyObject{From: o1, To: o2})
+			}
+		}
+	}
+	changes = append(changes, dropS...)
+	var addM []schema.Change
+	for _, o := range to.Objects {
+		switch o2 := o.(type) {
+		case *ForeignServer:
+			if _, ok := findServer(from, o2.Name); !ok {
+				changes = append(changes, &schema.AddObject{O: o2})
+			}
+		case *UserMapping:
+			// User mappings are created after their servers.
+			if _, ok := findMapping(from, o2.User, o2.Server); !ok {
+				addM = append(addM, &schema.AddObject{O: o2})
+			}
+		}
+	}
+	return append(changes, addM...)
+}
+
+// foreignTablesDiff returns a changeset for migrating the foreign tables of a schema.
+func foreignTablesDiff(from, to *schema.Schema) ([]schema.Change, error) {
+	var changes []schema.Change
+	for _, t1 := range foreignTablesOf(from) {
+		t2, ok := findForeignTable(to, t1.Name)
+		if !ok {
+			changes = append(changes, &schema.DropObject{O: t1})
+			continue
+		}
+		changed, err := foreignTableChanged(t1, t2)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			changes = append(changes, &schema.ModifyObject{From: t1, To: t2})
+		}
+	}
+	for _, t1 := range foreignTablesOf(to) {
+		if _, ok := findForeignTable(from, t1.Name); !ok {
+			changes = append(changes, &schema.AddObject{O: t1})
+		}
+	}
+	return changes, nil
+}
+
+// foreignTableChanged reports if the foreign table definition was changed.
+func foreignTableChanged(from, to *ForeignTable) (bool, error) {
+	if from.Server != to.Server || optionsChanged(from.Options, to.Options) || len(from.Columns) != len(to.Columns) {
+		return true, nil
+	}
+	for i, c1 := range from.Columns {
+		c2 := to.Columns[i]
+		if c1.Name != c2.Name || c1.Type.Null != c2.Type.Null {
+			return true, nil
+		}
+		t1, err := FormatType(c1.Type.Type)
+		if err != nil {
+			return false, err
+		}
+		t2, err := FormatType(c2.Type.Type)
+		if err != nil {
+			return false, err
+		}
+		if t1 != t2 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// option is the constraint of the option types, such as
+// FDWOption and the parameters of the storage attributes.
+type option interface {
+	~struct{ N, V string }
+}
+
+// optionsChanged reports if the options were changed, regardless of their order.
+func optionsChanged[T option](from, to []T) bool {
+	if len(from) != len(to) {
+		return true
+	}
+	for _, o := range from {
+		o1 := struct{ N, V string }(o)
+		if v, ok := optionValue(to, o1.N); !ok || v != o1.V {
+			return true
+		}
+	}
+	return false
+}
+
+func optionValue[T option](opts []T, name string) (string, bool) {
+	for _, o := range opts {
+		if o := struct{ N, V string }(o); o.N == name {
+			return o.V, true
+		}
+	}
+	return "", false
+}
+
+func findServer(r *schema.Realm, name string) (*ForeignServer, bool) {
+	o, ok := r.Object(func(o schema.Object) bool {
+		s, ok := o.(*ForeignServer)
+		return ok && s.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*ForeignServer), true
+}
+
+func findMapping(r *schema.Realm, user, server string) (*UserMapping, bool) {
+	o, ok := r.Object(func(o schema.Object) bool {
+		m, ok := o.(*UserMapping)
+		return ok && granteeName(m.User) == granteeName(user) && m.Server == server
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*UserMapping), true
+}
+
+func findForeignTable(s *schema.Schema, name string) (*ForeignTable, bool) {
+	o, ok := s.Object(func(o schema.Object) bool {
+		t, ok := o.(*ForeignTable)
+		return ok && t.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*ForeignTable), true
+}
+
+// addForeign appends the statements for creating a foreign-data object.
+func (s *state) addForeign(c *schema.AddObject) error {
+	create, drop, err := s.createDropForeign(c.O)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  c,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create %s", foreignDesc(c.O)),
+	})
+	return nil
+}
+
+// dropForeign appends the statement for dropping a foreign-data object.
+func (s *state) dropForeign(c *schema.DropObject) error {
+	create, drop, err := s.createDropForeign(c.O)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  c,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop %s", foreignDesc(c.O)),
+	})
+	return nil
+}
+
+// modifyForeign appends the statements for modifying a foreign-data object.
+func (s *state) modifyForeign(c *schema.ModifyObject) error {
+	if s.crdb {
+		return fmt.Errorf("%s is not supported by CockroachDB", foreignDesc(c.From))
+	}
+	switch from := c.From.(type) {
+	case *ForeignServer:
+		to := c.To.(*ForeignServer)
+		// Only the version and the options of a server can be altered.
+		if from.Wrapper == to.Wrapper && from.Type == to.Type {
+			b, r := s.Build("ALTER SERVER").Ident(to.Name), s.Build("ALTER SERVER").Ident(to.Name)
+			if from.Version != to.Version {
+				b.P("VERSION", quote(to.Version))
+				r.P("VERSION", quote(from.Version))
+			}
+			alterOptions(b, from.Options, to.Options)
+			alterOptions(r, to.Options, from.Options)
+			s.append(&migrate.Change{
+				Source:  c,
+				Cmd:     b.String(),
+				Reverse: r.String(),
+				Comment: fmt.Sprintf("modify %s", foreignDesc(to)),
+			})
+			return nil
+		}
+	case *UserMapping:
+		to := c.To.(*UserMapping)
+		b, r := s.Build("ALTER USER MAPPING FOR"), s.Build("ALTER USER MAPPING FOR")
+		granteeIdent(b, granteeName(to.User)).P("SERVER").Ident(to.Server)
+		granteeIdent(r, granteeName(to.User)).P("SERVER").Ident(to.Server)
+		alterOptions(b, from.Options, to.Options)
+		alterOptions(r, to.Options, from.Options)
+		s.append(&migrate.Change{
+			Source:  c,
+			Cmd:     b.String(),
+			Reverse: r.String(),
+			Comment: fmt.Sprintf("modify %s", foreignDesc(to)),
+		})
+		return nil
+	}
+	// Other modifications require recreating the object.
+	if err := s.dropForeign(&schema.DropObject{O: c.From}); err != nil {
+		return err
+	}
+	return s.addForeign(&schema.AddObject{O: c.To})
+}
+
+// createDropForeign returns the statements for creating and dropping a foreign-data object.
+func (s *state) createDropForeign(o schema.Object) (string, string, error) {
+	// CockroachDB does not support foreign-data wrappers.
+	if s.crdb {
+		return "", "", fmt.Errorf("%s is not supported by CockroachDB", foreignDesc(o))
+	}
+	switch o := o.(type) {
+	case *ForeignServer:
+		b := s.Build("CREATE SERVER").Ident(o.Name)
+		if o.Type != "" {
+			b.P("TYPE", quote(o.Type))
+		}
+		if o.Version != "" {
+			b.P("VERSION", quote(o.Version))
+		}
+		b.P("FOREIGN DATA WRAPPER").Ident(o.Wrapper)
+		options(b, o.Options)
+		return b.String(), s.Build("DROP SERVER").Ident(o.Name).String(), nil
+	case *UserMapping:
+		b, d := s.Build("CREATE USER MAPPING FOR"), s.Build("DROP USER MAPPING FOR")
+		granteeIdent(b, granteeName(o.User)).P("SERVER").Ident(o.Server)
+		granteeIdent(d, granteeName(o.User)).P("SERVER").Ident(o.Server)
+		options(b, o.Options)
+		return b.String(), d.String(), nil
+	case *ForeignTable:
+		var (
+			errs []string
+			t    = &schema.Table{Name: o.Name, Schema: o.Schema}
+			b    = s.Build("CREATE FOREIGN TABLE").Table(t)
+		)
+		b.WrapIndent(func(b *sqlx.Builder) {
+			b.MapIndent(o.Columns, func(i int, b *sqlx.Builder) {
+				if err := s.column(b, o.Columns[i]); err != nil {
+					errs = append(errs, err.Error())
+				}
+			})
+		})
+		if len(errs) > 0 {
+			return "", "", fmt.Errorf("create foreign table %q: %s", o.Name, strings.Join(errs, ", "))
+		}
+		b.P("SERVER").Ident(o.Server)
+		options(b, o.Options)
+		return b.String(), s.Build("DROP FOREIGN TABLE").Table(t).String(), nil
+	default:
+		return "", "", fmt.Errorf("unsupported object %T", o)
+	}
+}
+
+// foreignDesc returns a short description of the foreign-data object.
+func foreignDesc(o schema.Object) string {
+	switch o := o.(type) {
+	case *ForeignServer:
+		return fmt.Sprintf("foreign server %q", o.Name)
+	case *UserMapping:
+		return fmt.Sprintf("user mapping for %q on server %q", granteeName(o.User), o.Server)
+	case *ForeignTable:
+		return fmt.Sprintf("foreign table %q", o.Name)
+	default:
+		return fmt.Sprintf("object %T", o)
+	}
+}
+
+// options writes the OPTIONS clause to the builder, if there are options.
+func options(b *sqlx.Builder, opts []FDWOption) {
+	if len(opts) == 0 {
+		return
+	}
+	b.P("OPTIONS").Wrap(func(b *sqlx.Builder) {
+		b.MapComma(opts, func(i int, b *sqlx.Builder) {
+			b.P(opts[i].N, quote(opts[i].V))
+		})
+	})
+}
+
+// alterOptions writes the OPTIONS clause for migrating
+// the options from one state to the other.
+func alterOptions(b *sqlx.Builder, from, to []FDWOption) {
+	var clauses []string
+	for _, o := range to {
+		switch v, ok := optionValue(from, o.N); {
+		case !ok:
+			clauses = append(clauses, fmt.Sprintf("ADD %s %s", o.N, quote(o.V)))
+		case v != o.V:
+			clauses = append(clauses, fmt.Sprintf("SET %s %s", o.N, quote(o.V)))
+		}
+	}
+	for _, o := range from {
+		if _, ok := optionValue(to, o.N); !ok {
+			clauses = append(clauses, fmt.Sprintf("DROP %s", o.N))
+		}
+	}
+	if len(clauses) > 0 {
+		b.P("OPTIONS").Wrap(func(b *sqlx.Builder) {
+			b.MapComma(clauses, func(i int, b *sqlx.Builder) {
+				b.WriteString(clauses[i])
+			})
+		})
+	}
+}
+
+const (
+	// Query to list foreign servers.
+	foreignServersQuery = `
+SELECT
+	s.srvname AS server_name,
+	w.fdwname AS wrapper_name,
+	s.srvtype AS server_type,
+	s.srvversion AS server_version,
+	s.srvoptions AS options
+FROM
+	pg_catalog.pg_foreign_server AS s
+	JOIN pg_catalog.pg_foreign_data_wrapper AS w ON w.oid = s.srvfdw
+ORDER BY
+	s.srvname
+`
+
+	// Query to list user mappings.
+	userMappingsQuery = `
+SELECT
+	um.usename AS user_name,
+	um.srvname AS server_name,
+	um.umoptions AS options
+FROM
+	pg_catalog.pg_user_mappings AS um
+ORDER BY
+	um.srvname, um.usename
+`
+
+	// Query to list foreign tables.
+	foreignTablesQuery = `
+SELECT
+	n.nspname AS schema_name,
+	c.relname AS table_name,
+	s.srvname AS server_name,
+	ft.ftoptions AS options
+FROM
+	pg_catalog.pg_foreign_table AS ft
+	JOIN pg_catalog.pg_class AS c ON c.oid = ft.ftrelid
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+	JOIN pg_catalog.pg_foreign_server AS s ON s.oid = ft.ftserver
+WHERE
+	n.nspname IN (%s)
+ORDER BY
+	n.nspname, c.relname
+`
+)
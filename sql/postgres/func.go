@@ -0,0 +1,272 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// addFunc appends the change for creating the function.
+func (s *state) addFunc(add *schema.AddFunc) error {
+	create, err := s.createFunc(add.F, false)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: s.dropFuncCmd("FUNCTION", add.F.Schema, add.F.Name, add.F.Args),
+		Comment: fmt.Sprintf("create function %q", add.F.Name),
+	})
+	return nil
+}
+
+// dropFunc appends the change for dropping the function.
+func (s *state) dropFunc(drop *schema.DropFunc) error {
+	create, err := s.createFunc(drop.F, false)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     s.dropFuncCmd("FUNCTION", drop.F.Schema, drop.F.Name, drop.F.Args),
+		Reverse: create,
+		Comment: fmt.Sprintf("drop function %q", drop.F.Name),
+	})
+	return nil
+}
+
+// modifyFunc appends the changes for modifying the function. Functions with the same
+// signature are replaced, and others are dropped and created, as CREATE OR REPLACE
+// cannot change the arguments or the return type of an existing function.
+func (s *state) modifyFunc(modify *schema.ModifyFunc) error {
+	from, to := *modify.From, *modify.To
+	from.Body, from.Lang, to.Body, to.Lang = "", "", "", ""
+	f1, err := s.createFunc(&from, false)
+	if err != nil {
+		return err
+	}
+	f2, err := s.createFunc(&to, false)
+	if err != nil {
+		return err
+	}
+	if f1 != f2 {
+		if err := s.dropFunc(&schema.DropFunc{F: modify.From}); err != nil {
+			return err
+		}
+		return s.addFunc(&schema.AddFunc{F: modify.To})
+	}
+	cmd, err := s.createFunc(modify.To, true)
+	if err != nil {
+		return err
+	}
+	reverse, err := s.createFunc(modify.From, true)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  modify,
+		Cmd:     cmd,
+		Reverse: reverse,
+		Comment: fmt.Sprintf("modify function %q", modify.To.Name),
+	})
+	return nil
+}
+
+// addProc appends the change for creating the procedure.
+func (s *state) addProc(add *schema.AddProc) error {
+	create, err := s.createProc(add.P, false)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: s.dropFuncCmd("PROCEDURE", add.P.Schema, add.P.Name, add.P.Args),
+		Comment: fmt.Sprintf("create procedure %q", add.P.Name),
+	})
+	return nil
+}
+
+// dropProc appends the change for dropping the procedure.
+func (s *state) dropProc(drop *schema.DropProc) error {
+	create, err := s.createProc(drop.P, false)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     s.dropFuncCmd("PROCEDURE", drop.P.Schema, drop.P.Name, drop.P.Args),
+		Reverse: create,
+		Comment: fmt.Sprintf("drop procedure %q", drop.P.Name),
+	})
+	return nil
+}
+
+// modifyProc appends the changes for modifying the procedure. Similar to
+// functions, procedures with the same arguments are replaced.
+func (s *state) modifyProc(modify *schema.ModifyProc) error {
+	from, to := *modify.From, *modify.To
+	from.Body, from.Lang, to.Body, to.Lang = "", "", "", ""
+	p1, err := s.createProc(&from, false)
+	if err != nil {
+		return err
+	}
+	p2, err := s.createProc(&to, false)
+	if err != nil {
+		return err
+	}
+	if p1 != p2 {
+		if err := s.dropProc(&schema.DropProc{P: modify.From}); err != nil {
+			return err
+		}
+		return s.addProc(&schema.AddProc{P: modify.To})
+	}
+	cmd, err := s.createProc(modify.To, true)
+	if err != nil {
+		return err
+	}
+	reverse, err := s.createProc(modify.From, true)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  modify,
+		Cmd:     cmd,
+		Reverse: reverse,
+		Comment: fmt.Sprintf("modify procedure %q", modify.To.Name),
+	})
+	return nil
+}
+
+// createFunc returns the CREATE FUNCTION statement of the function.
+func (s *state) createFunc(f *schema.Func, replace bool) (string, error) {
+	b := s.Build("CREATE")
+	if replace {
+		b.P("OR REPLACE")
+	}
+	b.P("FUNCTION").SchemaResource(f.Schema, f.Name)
+	if err := s.funcArgs(b, f.Args); err != nil {
+		return "", fmt.Errorf("postgres: function %q: %w", f.Name, err)
+	}
+	switch {
+	case f.Ret != nil:
+		t, err := FormatType(f.Ret)
+		if err != nil {
+			return "", fmt.Errorf("postgres: format return type of function %q: %w", f.Name, err)
+		}
+		b.P("RETURNS", t)
+	case len(f.RetTable) > 0:
+		var err error
+		b.P("RETURNS TABLE").Wrap(func(b *sqlx.Builder) {
+			b.MapComma(f.RetTable, func(i int, b *sqlx.Builder) {
+				b.Ident(f.RetTable[i].Name)
+				if err == nil {
+					err = s.funcArgType(b, f.RetTable[i].Type)
+				}
+			})
+		})
+		if err != nil {
+			return "", fmt.Errorf("postgres: function %q: %w", f.Name, err)
+		}
+	}
+	funcBody(b, f.Lang, f.Body)
+	return b.String(), nil
+}
+
+// createProc returns the CREATE PROCEDURE statement of the procedure.
+func (s *state) createProc(p *schema.Proc, replace bool) (string, error) {
+	b := s.Build("CREATE")
+	if replace {
+		b.P("OR REPLACE")
+	}
+	b.P("PROCEDURE").SchemaResource(p.Schema, p.Name)
+	if err := s.funcArgs(b, p.Args); err != nil {
+		return "", fmt.Errorf("postgres: procedure %q: %w", p.Name, err)
+	}
+	funcBody(b, p.Lang, p.Body)
+	return b.String(), nil
+}
+
+// funcArgs writes the arguments of a function or a procedure to the builder.
+func (s *state) funcArgs(b *sqlx.Builder, args []*schema.FuncArg) (err error) {
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(args, func(i int, b *sqlx.Builder) {
+			a := args[i]
+			if a.Mode != "" && a.Mode != schema.FuncArgModeIn {
+				b.P(string(a.Mode))
+			}
+			if a.Name != "" {
+				b.Ident(a.Name)
+			}
+			t, err1 := FormatType(a.Type)
+			if err1 != nil && err == nil {
+				err = fmt.Errorf("format type of argument %q: %w", a.Name, err1)
+			}
+			b.P(t)
+			if a.Default != nil {
+				s.columnDefault(b, &schema.Column{Type: &schema.ColumnType{Type: a.Type}, Default: a.Default})
+			}
+		})
+	})
+	return err
+}
+
+// funcArgType writes the type of the column returned by a function.
+func (s *state) funcArgType(b *sqlx.Builder, ct *schema.ColumnType) error {
+	if ct == nil {
+		return fmt.Errorf("missing column type")
+	}
+	t, err := FormatType(ct.Type)
+	if err != nil {
+		return err
+	}
+	b.P(t)
+	return nil
+}
+
+// funcBody writes the language and the body of a function or a procedure. Bodies
+// are dollar-quoted, unless they are already quoted or written in the SQL-standard
+// form (i.e., BEGIN ATOMIC ... END or RETURN expr), that does not allow it.
+func funcBody(b *sqlx.Builder, lang, body string) {
+	if lang != "" {
+		b.P("LANGUAGE", lang)
+	}
+	switch u := strings.ToUpper(strings.TrimSpace(body)); {
+	case strings.HasPrefix(u, "$"):
+		b.P("AS", body)
+	case strings.HasPrefix(u, "BEGIN ATOMIC"), strings.HasPrefix(u, "RETURN "):
+		b.P(body)
+	default:
+		tag := "$$"
+		for i := 0; strings.Contains(body, tag); i++ {
+			tag = fmt.Sprintf("$body%d$", i)
+		}
+		b.P("AS", tag+body+tag)
+	}
+}
+
+// dropFuncCmd returns the DROP statement of a function or a procedure. The argument
+// types are written to identify the object in case it is overloaded. OUT arguments
+// are not part of the signature, and therefore are omitted.
+func (s *state) dropFuncCmd(typ string, sc *schema.Schema, name string, args []*schema.FuncArg) string {
+	b := s.Build("DROP", typ).SchemaResource(sc, name)
+	var types []string
+	for _, a := range args {
+		if a.Mode == schema.FuncArgModeOut {
+			continue
+		}
+		if t, err := FormatType(a.Type); err == nil {
+			types = append(types, t)
+		}
+	}
+	b.WriteString("(" + strings.Join(types, ", ") + ")")
+	return b.String()
+}
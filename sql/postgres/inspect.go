@@ -49,11 +49,26 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 				return nil, err
 			}
 		}
+		if mode.Is(schema.InspectForeign) {
+			if err := i.inspectForeign(ctx, r); err != nil {
+				return nil, err
+			}
+		}
 		if err := i.inspectEnums(ctx, r); err != nil {
 			return nil, err
 		}
+		if mode.Is(schema.InspectPrivileges) {
+			if err := i.privileges(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+		if mode.Is(schema.InspectPublications) {
+			if err := i.inspectPublications(ctx, r); err != nil {
+				return nil, err
+			}
+		}
 	}
-	return sqlx.ExcludeRealm(r, opts.Exclude)
+	return sqlx.FilterRealm(r, opts)
 }
 
 // InspectSchema returns schema descriptions of the tables in the given schema.
@@ -85,10 +100,20 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 			return nil, err
 		}
 	}
+	if sqlx.ModeInspectSchema(opts).Is(schema.InspectForeign) {
+		if err := i.inspectForeign(ctx, r); err != nil {
+			return nil, err
+		}
+	}
 	if err := i.inspectEnums(ctx, r); err != nil {
 		return nil, err
 	}
-	return sqlx.ExcludeSchema(r.Schemas[0], opts.Exclude)
+	if sqlx.ModeInspectSchema(opts).Is(schema.InspectPrivileges) {
+		if err := i.privileges(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	return sqlx.FilterSchema(r.Schemas[0], opts)
 }
 
 func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
@@ -149,8 +174,8 @@ func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var tSchema, name, comment, partattrs, partstart, partexprs sql.NullString
-		if err := rows.Scan(&tSchema, &name, &comment, &partattrs, &partstart, &partexprs); err != nil {
+		var tSchema, name, comment, partattrs, partstart, partexprs, options sql.NullString
+		if err := rows.Scan(&tSchema, &name, &comment, &partattrs, &partstart, &partexprs, &options); err != nil {
 			return fmt.Errorf("scan table information: %w", err)
 		}
 		if !sqlx.ValidString(tSchema) || !sqlx.ValidString(name) {
@@ -172,6 +197,15 @@ func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.
 				exprs: partexprs.String,
 			})
 		}
+		if sqlx.ValidString(options) {
+			p, err := newTableStorage(options.String)
+			if err != nil {
+				return err
+			}
+			if len(p.Params) > 0 {
+				t.AddAttrs(p)
+			}
+		}
 	}
 	return rows.Close()
 }
@@ -299,6 +333,9 @@ func (i *inspect) inspectEnums(ctx context.Context, r *schema.Realm) error {
 		for _, v := range s.Views {
 			scanC(v.Columns)
 		}
+		for _, t := range foreignTablesOf(s) {
+			scanC(t.Columns)
+		}
 	}
 	if len(args) == 0 {
 		return nil
@@ -581,6 +618,77 @@ func (i *inspect) addChecks(s *schema.Schema, rows *sql.Rows) error {
 	return nil
 }
 
+// privileges queries and appends the privileges granted on the schemas and
+// tables of the realm. Privileges held by the object owner are skipped.
+func (i *inspect) privileges(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(schemaPrivsQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema privileges: %w", err)
+	}
+	if err := scanPrivileges(rows, func(name string) (*[]schema.Attr, error) {
+		s, ok := r.Schema(name)
+		if !ok {
+			return nil, fmt.Errorf("schema %q was not found in realm", name)
+		}
+		return &s.Attrs, nil
+	}); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		if len(s.Tables) == 0 {
+			continue
+		}
+		rows, err := i.querySchema(ctx, tablePrivsQuery, s)
+		if err != nil {
+			return fmt.Errorf("postgres: querying schema %q table privileges: %w", s.Name, err)
+		}
+		if err := scanPrivileges(rows, func(name string) (*[]schema.Attr, error) {
+			t, ok := s.Table(name)
+			if !ok {
+				return nil, fmt.Errorf("table %q was not found in schema", name)
+			}
+			return &t.Attrs, nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanPrivileges scans the rows and groups the privileges of each object by
+// their grantee and grant option. The rows are expected to be sorted by these
+// columns. The attrs function returns the attributes of the given object.
+func scanPrivileges(rows *sql.Rows, attrs func(string) (*[]schema.Attr, error)) error {
+	defer rows.Close()
+	var (
+		last   *Privilege
+		object string
+	)
+	for rows.Next() {
+		var (
+			grantable           bool
+			name, grantee, priv string
+		)
+		if err := rows.Scan(&name, &grantee, &priv, &grantable); err != nil {
+			return fmt.Errorf("postgres: scanning privileges: %w", err)
+		}
+		to, err := attrs(name)
+		if err != nil {
+			return fmt.Errorf("postgres: %w", err)
+		}
+		if last == nil || object != name || last.Grantee != grantee || last.GrantOption != grantable {
+			last, object = &Privilege{Grantee: grantee, GrantOption: grantable}, name
+			*to = append(*to, last)
+		}
+		last.Privileges = append(last.Privileges, priv)
+	}
+	return rows.Close()
+}
+
 // schemas returns the list of the schemas in the database.
 func (i *inspect) schemas(ctx context.Context, opts *schema.InspectRealmOption) ([]*schema.Schema, error) {
 	var (
@@ -714,6 +822,15 @@ type (
 		T string
 	}
 
+	// VectorType defines the vector types provided by the pgvector
+	// extension, and their (optional) number of dimensions.
+	// https://github.com/pgvector/pgvector
+	VectorType struct {
+		schema.Type
+		T   string
+		Dim int64
+	}
+
 	// enumType represents an enum type. It serves aa intermediate representation of a Postgres enum type,
 	// to temporary save TypeID and TypeName of an enum column until the enum values can be extracted.
 	enumType struct {
@@ -861,6 +978,9 @@ type (
 		// PagesPerRange defines pages_per_range storage
 		// parameter for BRIN indexes. Defaults to 128.
 		PagesPerRange int64
+		// Params holds the rest of the storage parameters,
+		// such as fillfactor or deduplicate_items.
+		Params []struct{ N, V string }
 	}
 
 	// IndexInclude describes the INCLUDE clause allows specifying
@@ -893,6 +1013,14 @@ type (
 		schema.Clause
 	}
 
+	// NotValid describes the NOT VALID clause to instruct Postgres to add a foreign-key
+	// or a CHECK constraint without scanning the existing rows of the table. The constraint
+	// is validated afterwards using a separate VALIDATE CONSTRAINT statement.
+	// https://www.postgresql.org/docs/current/sql-altertable.html#SQL-ALTERTABLE-DESC-ADD-TABLE-CONSTRAINT
+	NotValid struct {
+		schema.Clause
+	}
+
 	// NoInherit attribute defines the NO INHERIT flag for CHECK constraint.
 	// https://postgresql.org/docs/current/catalog-pg-constraint.html
 	NoInherit struct {
@@ -929,6 +1057,17 @@ type (
 		Attrs []schema.Attr
 	}
 
+	// Privilege describes a set of privileges granted to a role on a schema or a
+	// table. Privileges are inspected only if the InspectPrivileges mode is set,
+	// and only the grantees that are declared in the desired state are managed.
+	// https://www.postgresql.org/docs/current/ddl-priv.html
+	Privilege struct {
+		schema.Attr
+		Grantee     string   // Role name. PUBLIC stands for all roles.
+		Privileges  []string // SELECT, INSERT, USAGE, ALL, etc.
+		GrantOption bool     // WITH GRANT OPTION.
+	}
+
 	// Cascade describes that a CASCADE clause should be added to the DROP [TABLE|SCHEMA]
 	// operation. Note, this clause is automatically added to DROP SCHEMA by the planner.
 	Cascade struct {
@@ -936,6 +1075,17 @@ type (
 	}
 )
 
+func init() {
+	schema.RegisterJSON(
+		&CType{}, &UserDefinedType{}, &VectorType{}, &ArrayType{}, &BitType{}, &IntervalType{}, &NetworkType{},
+		&CurrencyType{}, &RangeType{}, &SerialType{}, &TextSearchType{}, &OIDType{}, &XMLType{}, &Constraint{},
+		&Identity{}, &IndexType{}, &IndexPredicate{}, &IndexColumnProperty{}, &IndexStorageParams{}, &IndexInclude{},
+		&IndexOpClass{}, &IndexNullsDistinct{}, &NoInherit{}, &CheckColumns{}, &Partition{}, &Privilege{},
+		&ForeignServer{}, &UserMapping{}, &ForeignTable{}, &Publication{}, &DistStyle{}, &SortKey{}, &Encode{},
+		&TableStorageParams{}, &Colocation{}, &TabletSplit{},
+	)
+}
+
 // IsUnique reports if the type is unique constraint.
 func (c Constraint) IsUnique() bool { return strings.ToLower(c.T) == "u" }
 
@@ -1080,28 +1230,35 @@ func (o *IndexOpClass) parseParams(kv string) error {
 
 // newIndexStorage parses and returns the index storage parameters.
 func newIndexStorage(opts string) (*IndexStorageParams, error) {
-	params := &IndexStorageParams{}
-	for _, p := range strings.Split(strings.Trim(opts, "{}"), ",") {
-		kv := strings.Split(p, "=")
-		if len(kv) != 2 {
-			return nil, fmt.Errorf("invalid index storage parameter: %s", p)
-		}
-		switch kv[0] {
+	params, err := parseOptions[struct{ N, V string }](opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index storage parameters: %w", err)
+	}
+	return indexStorage(params)
+}
+
+// indexStorage returns the index storage parameters of the given list.
+func indexStorage(params []struct{ N, V string }) (*IndexStorageParams, error) {
+	s := &IndexStorageParams{}
+	for _, p := range params {
+		switch p.N {
 		case "autosummarize":
-			b, err := strconv.ParseBool(kv[1])
+			b, err := strconv.ParseBool(p.V)
 			if err != nil {
-				return nil, fmt.Errorf("failed parsing autosummarize %q: %w", kv[1], err)
+				return nil, fmt.Errorf("failed parsing autosummarize %q: %w", p.V, err)
 			}
-			params.AutoSummarize = b
+			s.AutoSummarize = b
 		case "pages_per_range":
-			i, err := strconv.ParseInt(kv[1], 10, 64)
+			i, err := strconv.ParseInt(p.V, 10, 64)
 			if err != nil {
-				return nil, fmt.Errorf("failed parsing pages_per_range %q: %w", kv[1], err)
+				return nil, fmt.Errorf("failed parsing pages_per_range %q: %w", p.V, err)
 			}
-			params.PagesPerRange = i
+			s.PagesPerRange = i
+		default:
+			s.Params = append(s.Params, p)
 		}
 	}
-	return params, nil
+	return s, nil
 }
 
 // reEnumType extracts the enum type and an option schema qualifier.
@@ -1130,7 +1287,7 @@ func newEnumType(t string, id int64) *enumType {
 
 const (
 	// Query to list runtime parameters.
-	paramsQuery = `SELECT setting FROM pg_settings WHERE name IN ('lc_collate', 'lc_ctype', 'server_version_num', 'crdb_version') ORDER BY name DESC`
+	paramsQuery = `SELECT setting FROM pg_settings WHERE name IN ('lc_collate', 'lc_ctype', 'server_version', 'server_version_num', 'crdb_version') ORDER BY name DESC`
 
 	// Query to list database schemas.
 	schemasQuery = `
@@ -1165,7 +1322,8 @@ SELECT
 	pg_catalog.obj_description(t3.oid, 'pg_class') AS comment,
 	t4.partattrs AS partition_attrs,
 	t4.partstrat AS partition_strategy,
-	pg_get_expr(t4.partexprs, t4.partrelid) AS partition_exprs
+	pg_get_expr(t4.partexprs, t4.partrelid) AS partition_exprs,
+	t3.reloptions AS options
 FROM
 	INFORMATION_SCHEMA.TABLES AS t1
 	JOIN pg_catalog.pg_namespace AS t2 ON t2.nspname = t1.table_schema
@@ -1187,7 +1345,8 @@ SELECT
 	pg_catalog.obj_description(t3.oid, 'pg_class') AS comment,
 	t4.partattrs AS partition_attrs,
 	t4.partstrat AS partition_strategy,
-	pg_get_expr(t4.partexprs, t4.partrelid) AS partition_exprs
+	pg_get_expr(t4.partexprs, t4.partrelid) AS partition_exprs,
+	t3.reloptions AS options
 FROM
 	INFORMATION_SCHEMA.TABLES AS t1
 	JOIN pg_catalog.pg_namespace AS t2 ON t2.nspname = t1.table_schema
@@ -1323,6 +1482,45 @@ WHERE
 ORDER BY
 	t1.conname, array_position(t1.conkey, t2.attnum)
 `
+
+	// Query to list the privileges granted on schemas.
+	schemaPrivsQuery = `
+SELECT
+	n.nspname AS object_name,
+	COALESCE(r.rolname, 'PUBLIC') AS grantee,
+	a.privilege_type,
+	a.is_grantable
+FROM
+	pg_catalog.pg_namespace AS n
+	CROSS JOIN LATERAL aclexplode(n.nspacl) AS a
+	LEFT JOIN pg_catalog.pg_roles AS r ON r.oid = a.grantee
+WHERE
+	n.nspname IN (%s)
+	AND a.grantee <> n.nspowner
+ORDER BY
+	object_name, grantee, a.is_grantable, a.privilege_type
+`
+
+	// Query to list the privileges granted on tables.
+	tablePrivsQuery = `
+SELECT
+	c.relname AS object_name,
+	COALESCE(r.rolname, 'PUBLIC') AS grantee,
+	a.privilege_type,
+	a.is_grantable
+FROM
+	pg_catalog.pg_class AS c
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+	CROSS JOIN LATERAL aclexplode(c.relacl) AS a
+	LEFT JOIN pg_catalog.pg_roles AS r ON r.oid = a.grantee
+WHERE
+	n.nspname = $1
+	AND c.relname IN (%s)
+	AND c.relkind IN ('r', 'p')
+	AND a.grantee <> c.relowner
+ORDER BY
+	object_name, grantee, a.is_grantable, a.privilege_type
+`
 )
 
 var (
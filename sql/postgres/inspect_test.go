@@ -6,6 +6,7 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -89,6 +90,8 @@ func TestDriver_InspectTable(t *testing.T) {
  users       |  c40         | smallint                    | int4                | NO          | nextval('"Users_c40_seq"'::regclass)   |                          |                32 |                    |             0 |                     |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |         |    23
  users       |  c41         | smallint                    | int4                | NO          | nextval('foo."T_C40_seq"'::regclass)   |                          |                32 |                    |             0 |                     |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |         |    23
  users       |  c42         | smallint                    | int4                | NO          | nextval('"F"."T_C40_seq"'::regclass)   |                          |                32 |                    |             0 |                     |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |         |    23
+ users       |  c43         | USER-DEFINED                | vector(3)           | NO          |                                        |                          |                   |                    |               |                     |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |         | 16390
+ users       |  c44         | USER-DEFINED                | citext              | NO          |                                        |                          |                   |                    |               |                     |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |         | 16391
 `))
 				m.noIndexes()
 				m.noFKs()
@@ -153,6 +156,8 @@ func TestDriver_InspectTable(t *testing.T) {
 					{Name: "c40", Type: &schema.ColumnType{Raw: "smallserial", Type: &SerialType{T: "smallserial", SequenceName: "Users_c40_seq"}}},
 					{Name: "c41", Type: &schema.ColumnType{Raw: "smallserial", Type: &SerialType{T: "smallserial", SequenceName: "T_C40_seq"}}},
 					{Name: "c42", Type: &schema.ColumnType{Raw: "smallserial", Type: &SerialType{T: "smallserial", SequenceName: "T_C40_seq"}}},
+					{Name: "c43", Type: &schema.ColumnType{Raw: "USER-DEFINED", Type: &VectorType{T: "vector", Dim: 3}}},
+					{Name: "c44", Type: &schema.ColumnType{Raw: "USER-DEFINED", Type: &schema.StringType{T: "citext"}}},
 				}, t.Columns)
 				require.Equal([]schema.Object{stateE, statusE}, t.Schema.Objects)
 			},
@@ -180,8 +185,8 @@ users           | idx             | hash        |             | f        | f
 users           | idx1            | btree       |             | f        | f       | f      |                 | (id <> NULL::integer) | "left"((c11)::text, 100)  | t    | t           | f          |           |                                       |     int4_ops      |        t        |                | f
 users           | t1_c1_key       | btree       | c1          | f        | f       | t      | {"name": "u"}   |                       | c1                        | t    | t           | f          |           |                                       |     int4_ops      |        t        |                | f
 users           | t1_pkey         | btree       | id          | f        | t       | t      | {"t_pkey": "p"} |                       | id                        | t    | f           | f          |           |                                       |     int4_ops      |        t        |                | f
-users           | idx4            | btree       | c1          | f        | f       | t      |                 |                       | c1                        | f    | f           | f          |           |                                       |     int4_ops      |        t        |                | f
-users           | idx4            | btree       | id          | f        | f       | t      |                 |                       | id                        | f    | f           | t          |           |                                       |     int4_ops      |        t        |                | f
+users           | idx4            | btree       | c1          | f        | f       | t      |                 |                       | c1                        | f    | f           | f          |           | {fillfactor=70,deduplicate_items=off} |     int4_ops      |        t        |                | f
+users           | idx4            | btree       | id          | f        | f       | t      |                 |                       | id                        | f    | f           | t          |           | {fillfactor=70,deduplicate_items=off} |     int4_ops      |        t        |                | f
 users           | idx5            | btree       | c1          | f        | f       | t      |                 |                       | c1                        | f    | f           | f          |           |                                       |     int4_ops      |        t        |                | f
 users           | idx5            | btree       |             | f        | f       | t      |                 |                       | coalesce(parent_id, 0)    | f    | f           | f          |           |                                       |     int4_ops      |        t        |                | f
 users           | idx6            | brin        | c1          | f        | f       | t      |                 |                       |                           | f    | f           | f          |           | {autosummarize=true,pages_per_range=2}|     int4_ops      |        t        |                | f
@@ -209,7 +214,7 @@ users           | tsx             | gist        | ts          | f        | f
 					{Name: "idx", Table: t, Attrs: []schema.Attr{&IndexType{T: "hash"}, &schema.Comment{Text: "boring"}}, Parts: []*schema.IndexPart{{SeqNo: 1, X: &schema.RawExpr{X: `"left"((c11)::text, 100)`}, Desc: true, Attrs: []schema.Attr{&IndexColumnProperty{NullsFirst: true}}}}},
 					{Name: "idx1", Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &IndexPredicate{P: `(id <> NULL::integer)`}}, Parts: []*schema.IndexPart{{SeqNo: 1, X: &schema.RawExpr{X: `"left"((c11)::text, 100)`}, Desc: true, Attrs: []schema.Attr{&IndexColumnProperty{NullsFirst: true}}}}},
 					{Name: "t1_c1_key", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &Constraint{N: "name", T: "u"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1], Desc: true, Attrs: []schema.Attr{&IndexColumnProperty{NullsFirst: true}}}}},
-					{Name: "idx4", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}, {SeqNo: 2, C: columns[0], Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}}},
+					{Name: "idx4", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &IndexStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}, {N: "deduplicate_items", V: "off"}}}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}, {SeqNo: 2, C: columns[0], Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}}},
 					{Name: "idx5", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}, {SeqNo: 2, X: &schema.RawExpr{X: `coalesce(parent_id, 0)`}}}},
 					{Name: "idx6", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "brin"}, &IndexStorageParams{AutoSummarize: true, PagesPerRange: 2}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}}},
 					{Name: "idx2", Unique: false, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &IndexInclude{Columns: columns[1:3]}}, Parts: []*schema.IndexPart{{SeqNo: 1, X: &schema.RawExpr{X: `((c * 2))`}, Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}, {SeqNo: 2, C: columns[1], Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}, {SeqNo: 3, C: columns[0], Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}}},
@@ -370,11 +375,11 @@ func TestDriver_InspectPartitionedTable(t *testing.T) {
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "$1"))).
 		WithArgs("public").
 		WillReturnRows(sqltest.Rows(`
- table_schema | table_name  | comment | partition_attrs | partition_strategy |                  partition_exprs                   
---------------+-------------+---------+-----------------+--------------------+----------------------------------------------------
- public       | logs1       |         |                 |                    | 
- public       | logs2       |         | 1               | r                  | 
- public       | logs3       |         | 2 0 0           | l                  | (a + b), (a + (b * 2))
+ table_schema | table_name  | comment | partition_attrs | partition_strategy |                  partition_exprs                   |      options
+--------------+-------------+---------+-----------------+--------------------+----------------------------------------------------+-----------------
+ public       | logs1       |         |                 |                    |                                                    | {fillfactor=70}
+ public       | logs2       |         | 1               | r                  |                                                    |
+ public       | logs3       |         | 2 0 0           | l                  | (a + b), (a + (b * 2))                             |
 
 `))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "$2, $3, $4"))).
@@ -396,13 +401,13 @@ logs3      | c5         | integer   | integer   | NO          |                |
 		WillReturnRows(sqlmock.NewRows([]string{"table_name", "constraint_name", "expression", "column_name", "column_indexes"}))
 	mk.noEnums()
 	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
-		Mode: ^schema.InspectViews,
+		Mode: ^(schema.InspectViews | schema.InspectPrivileges | schema.InspectForeign | schema.InspectPublications),
 	})
 	require.NoError(t, err)
 
 	t1, ok := s.Table("logs1")
 	require.True(t, ok)
-	require.Empty(t, t1.Attrs)
+	require.Equal(t, []schema.Attr{&TableStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}}}}, t1.Attrs)
 
 	t2, ok := s.Table("logs2")
 	require.True(t, ok)
@@ -425,6 +430,92 @@ logs3      | c5         | integer   | integer   | NO          |                |
 	}, key.Parts)
 }
 
+func TestDriver_InspectRedshiftSchema(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape("SELECT version()")).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("PostgreSQL 8.0.2 on i686-pc-linux-gnu, compiled by GCC gcc (GCC) 3.4.2 20041017 (Red Hat 3.4.2-6.fc3), Redshift 1.0.60103"))
+	drv, err := OpenRedshift(db)
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= $1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(rsTablesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ table_schema | table_name | comment | dist_style
+--------------+------------+---------+------------
+ public       | events     | nil     | 1
+ public       | users      | nil     | 8
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(rsColumnsQuery, "$2, $3"))).
+		WithArgs("public", "events", "users").
+		WillReturnRows(sqltest.Rows(`
+ table_name | column_name |          data_type           | not_null |            column_default            | comment | encoding | dist_key | sort_key_ord
+------------+-------------+------------------------------+----------+--------------------------------------+---------+----------+----------+--------------
+ events     | id          | bigint                       | true     | "identity"(100194, 0, '1,1'::text)   | nil     | az64     | false    | 0
+ events     | user_id     | integer                      | true     | nil                                  | nil     | az64     | true     | 2
+ events     | created_at  | timestamp without time zone  | true     | nil                                  | nil     | none     | false    | 1
+ events     | payload     | character varying(256)       | false    | nil                                  | nil     | lzo      | false    | 0
+ users      | id          | integer                      | true     | nil                                  | nil     | az64     | false    | 0
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(rsConstraintsQuery, "$2, $3"))).
+		WithArgs("public", "events", "users").
+		WillReturnRows(sqltest.Rows(`
+ table_name | constraint_name  | constraint_type |                   definition                   | referenced_schema | referenced_table
+------------+------------------+-----------------+------------------------------------------------+-------------------+------------------
+ events     | events_pkey      | p               | PRIMARY KEY (id)                               | nil               | nil
+ events     | events_user_fkey | f               | FOREIGN KEY (user_id) REFERENCES users(id)     | public            | users
+ users      | users_pkey       | p               | PRIMARY KEY (id)                               | nil               | nil
+`))
+	s, err := drv.InspectSchema(context.Background(), "public", nil)
+	require.NoError(t, err)
+	events, ok := s.Table("events")
+	require.True(t, ok)
+	users, ok := s.Table("users")
+	require.True(t, ok)
+	id, userID, createdAt := events.Columns[0], events.Columns[1], events.Columns[2]
+	require.Equal(t, []schema.Attr{&Identity{Generation: "ALWAYS", Sequence: &Sequence{Start: 1, Increment: 1}}, &Encode{T: "AZ64"}}, id.Attrs)
+	require.Nil(t, id.Default)
+	require.Equal(t, []schema.Attr{&Encode{T: "RAW"}}, createdAt.Attrs)
+	require.Equal(t, &schema.ColumnType{Raw: "character varying(256)", Type: &schema.StringType{T: "character varying", Size: 256}, Null: true}, events.Columns[3].Type)
+	require.Equal(t, []schema.Attr{
+		&DistStyle{S: DistStyleKey, Key: userID},
+		&SortKey{T: SortKeyCompound, Columns: []*schema.Column{createdAt, userID}},
+	}, events.Attrs)
+	require.Equal(t, []schema.Attr{&DistStyle{S: DistStyleAll}}, users.Attrs)
+	require.Equal(t, "events_pkey", events.PrimaryKey.Name)
+	require.Equal(t, id, events.PrimaryKey.Parts[0].C)
+	require.Len(t, events.ForeignKeys, 1)
+	require.Equal(t, users, events.ForeignKeys[0].RefTable)
+	require.Equal(t, []*schema.Column{users.Columns[0]}, events.ForeignKeys[0].RefColumns)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestInspect_YugabyteTableProperties(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(ybTablesQuery, "$2, $3"))).
+		WithArgs("public", "users", "events").
+		WillReturnRows(sqltest.Rows(`
+ table_name | num_tablets | is_colocated
+------------+-------------+--------------
+ users      | 1           | t
+ events     | 4           | f
+`))
+	users := schema.NewTable("users").AddAttrs(&TableStorageParams{Params: []struct{ N, V string }{{N: "colocation", V: "true"}}})
+	events := schema.NewTable("events")
+	s := schema.New("public").AddTables(users, events)
+	i := &ybInspect{inspect{&conn{ExecQuerier: db, yugabyte: true}}}
+	require.NoError(t, i.patchSchema(context.Background(), s))
+	require.Equal(t, []schema.Attr{&TableStorageParams{Params: []struct{ N, V string }{}}, &Colocation{V: true}}, users.Attrs)
+	require.Equal(t, []schema.Attr{&TabletSplit{Tablets: 4}}, events.Attrs)
+}
+
 func TestDriver_InspectCRDBSchema(t *testing.T) {
 	db, m, err := sqlmock.New()
 	require.NoError(t, err)
@@ -434,6 +525,7 @@ func TestDriver_InspectCRDBSchema(t *testing.T) {
 					setting
 				------------
 				130000
+				13.0.0
 				en_US.utf8
 				en_US.utf8
 				cockroach
@@ -475,7 +567,7 @@ users       | idx5       | c           | false   | false  |                 | CR
 	mk.noChecks()
 	mk.noEnums()
 	s, err := drv.InspectSchema(context.Background(), "public", &schema.InspectOptions{
-		Mode: ^schema.InspectViews,
+		Mode: ^(schema.InspectViews | schema.InspectPrivileges | schema.InspectForeign | schema.InspectPublications),
 	})
 	require.NoError(t, err)
 	tbl := s.Tables[0]
@@ -516,10 +608,10 @@ func TestDriver_InspectSchema(t *testing.T) {
 `))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "$1"))).
 		WithArgs("test").
-		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs"}))
+		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs", "options"}))
 	mk.noEnums()
 	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
-		Mode: ^schema.InspectViews,
+		Mode: ^(schema.InspectViews | schema.InspectPrivileges | schema.InspectForeign | schema.InspectPublications),
 	})
 	require.NoError(t, err)
 	require.EqualValues(t, func() *schema.Schema {
@@ -558,11 +650,11 @@ func TestDriver_Realm(t *testing.T) {
 `))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "$1, $2"))).
 		WithArgs("test", "public").
-		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs"}))
+		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs", "options"}))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(enumsQuery, "$1, $2"))).
 		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "enum_name", "comment", "enum_type", "enum_value"}))
 	realm, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
-		Mode: ^schema.InspectViews,
+		Mode: ^(schema.InspectViews | schema.InspectPrivileges | schema.InspectForeign | schema.InspectPublications),
 	})
 	require.NoError(t, err)
 	require.EqualValues(t, func() *schema.Realm {
@@ -600,12 +692,12 @@ func TestDriver_Realm(t *testing.T) {
 `))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "$1, $2"))).
 		WithArgs("test", "public").
-		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs"}))
+		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs", "options"}))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(enumsQuery, "$1, $2"))).
 		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "enum_name", "comment", "enum_type", "enum_value"}))
 	realm, err = drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
 		Schemas: []string{"test", "public"},
-		Mode:    ^schema.InspectViews,
+		Mode:    ^(schema.InspectViews | schema.InspectPrivileges | schema.InspectForeign | schema.InspectPublications),
 	})
 	require.NoError(t, err)
 	require.EqualValues(t, func() *schema.Realm {
@@ -642,7 +734,7 @@ func TestDriver_Realm(t *testing.T) {
 `))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "$1"))).
 		WithArgs("test").
-		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs"}))
+		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs", "options"}))
 	mk.noEnums()
 	realm, err = drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
 		Schemas: []string{"test"},
@@ -714,6 +806,143 @@ func TestInspectMode_InspectRealm(t *testing.T) {
 	}(), realm)
 }
 
+func TestInspectMode_InspectPrivileges(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= $1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(enumsQuery, "$1"))).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "enum_name", "comment", "enum_type", "enum_value"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemaPrivsQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ object_name | grantee | privilege_type | is_grantable
+-------------+---------+----------------+--------------
+ public      | PUBLIC  | USAGE          | false
+ public      | app     | CREATE         | false
+ public      | app     | USAGE          | false
+ public      | admin   | CREATE         | true
+`))
+	s, err := drv.InspectSchema(context.Background(), "public", &schema.InspectOptions{Mode: schema.InspectSchemas | schema.InspectPrivileges})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Attr{
+		&Privilege{Grantee: "PUBLIC", Privileges: []string{"USAGE"}},
+		&Privilege{Grantee: "app", Privileges: []string{"CREATE", "USAGE"}},
+		&Privilege{Grantee: "admin", Privileges: []string{"CREATE"}, GrantOption: true},
+	}, s.Attrs)
+}
+
+func TestInspectMode_InspectForeign(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= $1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	m.ExpectQuery(sqltest.Escape(foreignServersQuery)).
+		WillReturnRows(sqltest.Rows(`
+ server_name | wrapper_name | server_type | server_version |                 options
+-------------+--------------+-------------+----------------+------------------------------------------
+ remote      | postgres_fdw | nil         | 15             | {host=localhost,"dbname=a,b",port=5432}
+`))
+	m.ExpectQuery(sqltest.Escape(userMappingsQuery)).
+		WillReturnRows(sqltest.Rows(`
+ user_name | server_name |         options
+-----------+-------------+--------------------------
+ public    | remote      | {user=app,password=pass}
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(foreignTablesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | table_name | server_name |      options
+-------------+------------+-------------+-------------------
+ public      | users      | remote      | {table_name=users}
+`))
+	m.ExpectQuery(queryColumns).
+		WithArgs("public", "users").
+		WillReturnRows(sqltest.Rows(`
+table_name | column_name |      data_type      | formatted |  is_nullable |         column_default          | character_maximum_length | numeric_precision | datetime_precision | numeric_scale | interval_type | character_set_name | collation_name | is_identity | identity_start | identity_increment |   identity_last  | identity_generation | generation_expression | comment | typtype | typelem | elemtyp |  oid
+-----------+-------------+---------------------+-----------+--------------+---------------------------------+--------------------------+-------------------+--------------------+---------------+---------------+--------------------+----------------+-------------+----------------+--------------------+------------------+---------------------+-----------------------+---------+---------+---------+---------+-------
+users      | id          | bigint              | int8      |  NO          |                                 |                          |                64 |                    |             0 |               |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |         |    20
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(enumsQuery, "$1"))).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "enum_name", "comment", "enum_type", "enum_value"}))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{Schemas: []string{"public"}, Mode: schema.InspectSchemas | schema.InspectForeign})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{
+		&ForeignServer{Name: "remote", Wrapper: "postgres_fdw", Version: "15", Options: []FDWOption{{"host", "localhost"}, {"dbname", "a,b"}, {"port", "5432"}}},
+		&UserMapping{User: "PUBLIC", Server: "remote", Options: []FDWOption{{"user", "app"}, {"password", "pass"}}},
+	}, r.Objects)
+	require.Len(t, r.Schemas[0].Objects, 1)
+	ft := r.Schemas[0].Objects[0].(*ForeignTable)
+	require.Equal(t, "users", ft.Name)
+	require.Equal(t, "remote", ft.Server)
+	require.Equal(t, []FDWOption{{"table_name", "users"}}, ft.Options)
+	require.Len(t, ft.Columns, 1)
+	require.Equal(t, "id", ft.Columns[0].Name)
+	require.Equal(t, &schema.IntegerType{T: "bigint"}, ft.Columns[0].Type.Type)
+}
+
+func TestInspectMode_InspectPublications(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= $1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(enumsQuery, "$1"))).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "enum_name", "comment", "enum_type", "enum_value"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(publicationsQuery, "p.pubtruncate"))).
+		WillReturnRows(sqltest.Rows(`
+ publication_name | all_tables | publish_insert | publish_update | publish_delete | publish_truncate
+------------------+------------+----------------+----------------+----------------+------------------
+ all              | true       | true           | true           | true           | true
+ inserts          | false      | true           | false          | false          | false
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(publicationTablesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ publication_name | schema_name | table_name
+------------------+-------------+------------
+ all              | public      | users
+ inserts          | public      | users
+ inserts          | public      | pets
+`))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{Schemas: []string{"public"}, Mode: schema.InspectSchemas | schema.InspectPublications})
+	require.NoError(t, err)
+	require.Len(t, r.Objects, 2)
+	require.Equal(t, &Publication{Name: "all", AllTables: true}, r.Objects[0])
+	p := r.Objects[1].(*Publication)
+	require.Equal(t, "inserts", p.Name)
+	require.Equal(t, []string{"insert"}, p.Publish)
+	require.Len(t, p.Tables, 2)
+	require.Equal(t, "users", p.Tables[0].Name)
+	require.Equal(t, "pets", p.Tables[1].Name)
+}
+
 func TestIndexOpClass_UnmarshalText(t *testing.T) {
 	var op IndexOpClass
 	require.NoError(t, op.UnmarshalText([]byte("int4_ops")))
@@ -736,6 +965,7 @@ func (m mock) version(version string) {
 		WillReturnRows(sqltest.Rows(`
   setting
 ------------
+ ` + version + `
  ` + version + `
  en_US.utf8
  en_US.utf8
@@ -743,9 +973,9 @@ func (m mock) version(version string) {
 }
 
 func (m mock) tableExists(schema, table string, exists bool) {
-	rows := sqlmock.NewRows([]string{"table_schema", "table_name", "table_comment", "partition_attrs", "partition_strategy", "partition_exprs"})
+	rows := sqlmock.NewRows([]string{"table_schema", "table_name", "table_comment", "partition_attrs", "partition_strategy", "partition_exprs", "options"})
 	if exists {
-		rows.AddRow(schema, table, nil, nil, nil, nil)
+		rows.AddRow(schema, table, nil, nil, nil, nil, nil)
 	}
 	m.ExpectQuery(queryTables).
 		WithArgs(schema).
@@ -771,3 +1001,37 @@ func (m mock) noEnums() {
 	m.ExpectQuery(queryEnums).
 		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "enum_name", "comment", "enum_type", "enum_value"}))
 }
+
+func TestRealm_JSON(t *testing.T) {
+	var (
+		s      = schema.New("public")
+		status = &schema.EnumType{T: "status", Values: []string{"a", "b"}, Schema: s}
+		users  = schema.NewTable("users").
+			AddColumns(
+				NewIdentityColumn("id", TypeBigInt),
+				schema.NewColumn("statuses").SetType(&ArrayType{Type: status, T: "status[]"}),
+			).
+			AddAttrs(&Partition{T: PartitionTypeHash})
+		ft = &ForeignTable{Name: "remote", Schema: s, Server: "srv", Columns: []*schema.Column{schema.NewIntColumn("id", "int")}}
+	)
+	users.Attrs[0].(*Partition).Parts = []*PartitionPart{{C: users.Columns[0]}}
+	users.AddIndexes(schema.NewIndex("users_id").AddColumns(users.Columns[0]).AddAttrs(&IndexInclude{Columns: users.Columns[1:]}))
+	r := schema.NewRealm(s.AddTables(users).AddObjects(status, ft)).AddObjects(&ForeignServer{Name: "srv", Wrapper: "postgres_fdw"})
+	b, err := json.Marshal(r)
+	require.NoError(t, err)
+
+	var got schema.Realm
+	require.NoError(t, json.Unmarshal(b, &got))
+	require.True(t, r.Equal(&got))
+	var (
+		s2     = got.Schemas[0]
+		users2 = s2.Tables[0]
+		ft2    = s2.Objects[1].(*ForeignTable)
+	)
+	require.True(t, s2.Objects[0].(*schema.EnumType) == users2.Columns[1].Type.Type.(*ArrayType).Type)
+	require.True(t, users2.Columns[0] == users2.Attrs[0].(*Partition).Parts[0].C)
+	require.True(t, users2.Columns[1] == users2.Indexes[0].Attrs[0].(*IndexInclude).Columns[0])
+	require.True(t, s2 == ft2.Schema)
+	require.Equal(t, "id", ft2.Columns[0].Name)
+	require.IsType(t, &ForeignServer{}, got.Objects[0])
+}
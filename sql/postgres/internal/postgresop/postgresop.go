@@ -192,7 +192,7 @@ var Classes = []*Class{
 	{Name: "range_ops", Method: "SPGIST", Type: "anyrange", Default: true},
 	{Name: "text_ops", Method: "SPGIST", Type: "text", Default: true},
 	{Name: "gin_trgm_ops", Method: "GIN", Type: "text", Default: false},
-	{Name: "gist_trgm_ops", Method: "GIN", Type: "text", Default: false},
+	{Name: "gist_trgm_ops", Method: "GIST", Type: "text", Default: false},
 	{Name: "btree_geography_ops", Method: "BTREE", Type: "geography", Default: true},
 	{Name: "btree_geometry_ops", Method: "BTREE", Type: "geometry", Default: true},
 	{Name: "gist_geography_ops", Method: "GIST", Type: "geography", Default: true},
@@ -30,8 +30,13 @@ func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema
 	s := &state{
 		conn: p.conn,
 		Plan: migrate.Plan{
-			Name:          name,
-			Transactional: true,
+			Name: name,
+			// CockroachDB executes schema changes online and asynchronously, and does
+			// not guarantee their atomicity when they are mixed in one transaction.
+			// See: https://www.cockroachlabs.com/docs/stable/online-schema-changes.html
+			// YugabyteDB does not guarantee the atomicity of DDL statements by default.
+			// See: https://docs.yugabyte.com/preview/explore/ysql-language-features/ddl-transactions/
+			Transactional: !p.crdb && !p.yugabyte,
 		},
 	}
 	for _, o := range opts {
@@ -43,6 +48,7 @@ func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema
 	if err := sqlx.SetReversible(&s.Plan); err != nil {
 		return nil, err
 	}
+	sqlx.Annotate(&s.Plan, s.Annotations)
 	return &s.Plan, nil
 }
 
@@ -63,6 +69,29 @@ type state struct {
 	droppedT []*schema.Table
 }
 
+// customAttrs holds the custom attributes that were registered by RegisterAttr.
+var customAttrs = make(sqlx.AttrFormatters)
+
+// RegisterAttr registers a function for formatting custom attributes of the same type
+// as a (i.e. table and column options that are not modeled by Atlas) when they are planned
+// in table and column definitions. schema.RawClause attributes are formatted as is, and
+// do not require registration. For example:
+//
+//	type Compression struct {
+//		schema.Attr
+//		V string
+//	}
+//
+//	postgres.RegisterAttr(&Compression{}, func(a schema.Attr) string {
+//		return "COMPRESSION " + a.(*Compression).V
+//	})
+//
+// Note, custom attributes are formatted only when tables and columns are created, and
+// changes to them are not detected by the differ. RegisterAttr is not safe for concurrent use, and should be called on initialization.
+func RegisterAttr(a schema.Attr, f func(schema.Attr) string) {
+	customAttrs.Register(a, f)
+}
+
 // Exec executes the changes on the database. An error is returned
 // if one of the operations fail, or a change is not supported.
 func (s *state) plan(changes []schema.Change) error {
@@ -80,8 +109,14 @@ func (s *state) plan(changes []schema.Change) error {
 	}
 	var (
 		views []schema.Change
+		pubs  []schema.Change
 		dropT []*schema.DropTable
 		dropO []*schema.DropObject
+		seqs  []schema.Change
+		dropS []*schema.DropSequence
+		trigs []schema.Change
+		funcs []schema.Change
+		dropF []schema.Change
 	)
 	for _, c := range planned {
 		switch c := c.(type) {
@@ -97,6 +132,18 @@ func (s *state) plan(changes []schema.Change) error {
 			dropT = append(dropT, c)
 		case *schema.DropObject:
 			dropO = append(dropO, c)
+		case *schema.AddObject, *schema.ModifyObject:
+			pubs = append(pubs, c)
+		case *schema.AddSequence, *schema.ModifySequence:
+			seqs = append(seqs, c)
+		case *schema.DropSequence:
+			dropS = append(dropS, c)
+		case *schema.AddTrigger, *schema.DropTrigger, *schema.ModifyTrigger:
+			trigs = append(trigs, c)
+		case *schema.AddFunc, *schema.ModifyFunc, *schema.AddProc, *schema.ModifyProc:
+			funcs = append(funcs, c)
+		case *schema.DropFunc, *schema.DropProc:
+			dropF = append(dropF, c)
 		default:
 			err = fmt.Errorf("unsupported change %T", c)
 		}
@@ -104,6 +151,10 @@ func (s *state) plan(changes []schema.Change) error {
 			return err
 		}
 	}
+	// Sequences are owned by their columns after the tables were created.
+	for _, c := range seqs {
+		s.ownSequence(c)
+	}
 	if views, err = sqlx.PlanViewChanges(views); err != nil {
 		return err
 	}
@@ -119,15 +170,80 @@ func (s *state) plan(changes []schema.Change) error {
 			s.renameView(c)
 		}
 	}
+	// Functions and procedures are created after the objects they may depend on,
+	// and before the triggers that may execute them.
+	for _, c := range funcs {
+		switch c := c.(type) {
+		case *schema.AddFunc:
+			err = s.addFunc(c)
+		case *schema.ModifyFunc:
+			err = s.modifyFunc(c)
+		case *schema.AddProc:
+			err = s.addProc(c)
+		case *schema.ModifyProc:
+			err = s.modifyProc(c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Triggers are planned after the tables and the views they are defined on.
+	for _, c := range trigs {
+		switch c := c.(type) {
+		case *schema.AddTrigger:
+			err = s.addTrigger(c)
+		case *schema.DropTrigger:
+			err = s.dropTrigger(c)
+		case *schema.ModifyTrigger:
+			err = s.modifyTrigger(c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Publications are modified before their tables are dropped.
+	for _, c := range pubs {
+		switch c := c.(type) {
+		case *schema.AddObject:
+			s.addPublication(c)
+		case *schema.ModifyObject:
+			s.modifyPublication(c)
+		}
+	}
 	for _, c := range dropT {
 		if err := s.dropTable(c); err != nil {
 			return err
 		}
 	}
+	// Functions and procedures are dropped after the
+	// tables and the triggers that may use them.
+	for _, c := range dropF {
+		switch c := c.(type) {
+		case *schema.DropFunc:
+			err = s.dropFunc(c)
+		case *schema.DropProc:
+			err = s.dropProc(c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range dropS {
+		if err := s.dropSequence(c); err != nil {
+			return err
+		}
+	}
 	for _, c := range dropO {
+		if _, ok := c.O.(*Publication); ok {
+			s.dropPublication(c)
+			continue
+		}
 		e, ok := c.O.(*schema.EnumType)
 		if !ok {
-			return fmt.Errorf("unsupported object %T", c.O)
+			if err := s.dropForeign(c); err != nil {
+				return err
+			}
+			continue
 		}
 		create, drop := s.createDropEnum(e)
 		s.append(&migrate.Change{
@@ -164,24 +280,23 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 			if cm := (schema.Comment{}); sqlx.Has(c.S.Attrs, &cm) {
 				s.append(s.schemaComment(c.S, cm.Text, ""))
 			}
+			for _, a := range c.S.Attrs {
+				if p, ok := a.(*Privilege); ok {
+					s.append(s.privileges(c, "SCHEMA", s.Build().Ident(c.S.Name).String(), nil, p, schemaPrivileges)...)
+				}
+			}
 		case *schema.ModifySchema:
 			for i := range c.Changes {
 				switch change := c.Changes[i].(type) {
-				// Add schema attributes to an existing schema only if
-				// it is different from the default server configuration.
-				case *schema.AddAttr:
-					a, ok := change.A.(*schema.Comment)
+				case *schema.AddAttr, *schema.ModifyAttr:
+					from, to, ok := privilegeChange(change)
 					if !ok {
-						return nil, fmt.Errorf("unexpected schema AddAttr: %T", change.A)
+						if err := s.schemaCommentChange(c.S, change); err != nil {
+							return nil, err
+						}
+						continue
 					}
-					s.append(s.schemaComment(c.S, a.Text, ""))
-				case *schema.ModifyAttr:
-					to, ok1 := change.To.(*schema.Comment)
-					from, ok2 := change.From.(*schema.Comment)
-					if !ok1 || !ok2 {
-						return nil, fmt.Errorf("unexpected schema ModifyAttr: (%T, %T)", change.To, change.From)
-					}
-					s.append(s.schemaComment(c.S, to.Text, from.Text))
+					s.append(s.privileges(c, "SCHEMA", s.Build().Ident(c.S.Name).String(), from, to, schemaPrivileges)...)
 				default:
 					return nil, fmt.Errorf("unsupported ModifySchema change: %T", change)
 				}
@@ -197,10 +312,25 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 				Source:  c,
 				Comment: fmt.Sprintf("Drop schema named %q", c.S.Name),
 			})
+		case *schema.RenameSchema:
+			s.append(&migrate.Change{
+				Cmd:     s.Build("ALTER SCHEMA").Ident(c.From.Name).P("RENAME TO").Ident(c.To.Name).String(),
+				Source:  c,
+				Reverse: s.Build("ALTER SCHEMA").Ident(c.To.Name).P("RENAME TO").Ident(c.From.Name).String(),
+				Comment: fmt.Sprintf("rename a schema from %q to %q", c.From.Name, c.To.Name),
+			})
 		case *schema.AddObject:
 			e, ok := c.O.(*schema.EnumType)
+			if _, isP := c.O.(*Publication); isP {
+				// Publications are created after their tables.
+				planned = append(planned, c)
+				continue
+			}
 			if !ok {
-				return nil, fmt.Errorf("unsupported object %T", c.O)
+				if err := s.addForeign(c); err != nil {
+					return nil, err
+				}
+				continue
 			}
 			create, drop := s.createDropEnum(e)
 			s.append(&migrate.Change{
@@ -210,9 +340,30 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 				Comment: fmt.Sprintf("create enum type %q", e.T),
 			})
 		case *schema.ModifyObject:
+			if _, ok := c.From.(*Publication); ok {
+				planned = append(planned, c)
+				continue
+			}
+			if _, ok := c.From.(*schema.EnumType); !ok {
+				if err := s.modifyForeign(c); err != nil {
+					return nil, err
+				}
+				continue
+			}
 			if err := s.alterEnum(c); err != nil {
 				return nil, err
 			}
+		case *schema.AddSequence:
+			if err := s.addSequence(c); err != nil {
+				return nil, err
+			}
+			// The owner is set after the tables were created.
+			planned = append(planned, c)
+		case *schema.ModifySequence:
+			if err := s.modifySequence(c); err != nil {
+				return nil, err
+			}
+			planned = append(planned, c)
 		case *schema.RenameObject:
 			e1, ok1 := c.From.(*schema.EnumType)
 			e2, ok2 := c.To.(*schema.EnumType)
@@ -272,6 +423,25 @@ func (s *state) addTable(add *schema.AddTable) error {
 		}
 		b.P(s)
 	}
+	p := TableStorageParams{}
+	if sqlx.Has(add.T.Attrs, &p); s.yugabyte {
+		p.Params = ybTableParams(add.T, p.Params)
+	}
+	if len(p.Params) > 0 {
+		b.P("WITH").Wrap(func(b *sqlx.Builder) {
+			storageParams(b, p.Params)
+		})
+	}
+	if s.yugabyte {
+		ybSplit(b, add.T.Attrs)
+	}
+	b.P(customAttrs.Custom(add.T.Attrs)...)
+	if s.redshift {
+		if len(add.T.Indexes) > 0 {
+			errs = append(errs, "indexes are not supported by Redshift")
+		}
+		s.rsTableAttrs(b, add.T)
+	}
 	if len(errs) > 0 {
 		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
 	}
@@ -288,6 +458,11 @@ func (s *state) addTable(add *schema.AddTable) error {
 		}
 	}
 	s.addComments(add.T)
+	for _, a := range add.T.Attrs {
+		if p, ok := a.(*Privilege); ok {
+			s.append(s.privileges(add, "TABLE", s.Build().Table(add.T).String(), nil, p, tablePrivileges)...)
+		}
+	}
 	return nil
 }
 
@@ -333,6 +508,14 @@ func (s *state) dropTable(drop *schema.DropTable) error {
 
 // modifyTable builds the statements that bring the table into its modified state.
 func (s *state) modifyTable(modify *schema.ModifyTable) error {
+	if s.redshift {
+		return s.rsModifyTable(modify)
+	}
+	if s.yugabyte {
+		if err := ybModifyTable(modify); err != nil {
+			return err
+		}
+	}
 	var (
 		alter   []schema.Change
 		addI    []*schema.AddIndex
@@ -342,6 +525,16 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 	for _, change := range skipAutoChanges(modify.Changes) {
 		switch change := change.(type) {
 		case *schema.AddAttr, *schema.ModifyAttr:
+			if from, to, ok := privilegeChange(change); ok {
+				changes = append(changes, s.privileges(modify, "TABLE", s.Build().Table(modify.T).String(), from, to, tablePrivileges)...)
+				continue
+			}
+			if m, ok := change.(*schema.ModifyAttr); ok {
+				if from, ok := m.From.(*TableStorageParams); ok {
+					changes = append(changes, s.alterStorage(modify.T, change, from, m.To.(*TableStorageParams)))
+					continue
+				}
+			}
 			from, to, err := commentChange(change)
 			if err != nil {
 				return err
@@ -383,6 +576,10 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 					continue
 				}
 			}
+			if from, to, ok := indexStorageChange(change, k); ok {
+				changes = append(changes, s.alterIndexStorage(modify.T, change, change.To, from, to))
+				continue
+			}
 			// Index modification requires rebuilding the index.
 			addI = append(addI, &schema.AddIndex{I: change.To})
 			dropI = append(dropI, &schema.DropIndex{I: change.From})
@@ -506,12 +703,20 @@ func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
 			case *schema.AddForeignKey:
 				b.P("ADD")
 				s.fks(b, change.F)
+				if sqlx.Has(change.Extra, &NotValid{}) {
+					b.P("NOT VALID")
+					alter.after = append(alter.after, s.validateConstraint(t, change, change.F.Symbol))
+				}
 				reverse = append(reverse, &schema.DropForeignKey{F: change.F})
 			case *schema.DropForeignKey:
 				b.P("DROP CONSTRAINT").Ident(change.F.Symbol)
 				reverse = append(reverse, &schema.AddForeignKey{F: change.F})
 			case *schema.AddCheck:
 				check(b.P("ADD"), change.C)
+				if sqlx.Has(change.Extra, &NotValid{}) {
+					b.P("NOT VALID")
+					alter.after = append(alter.after, s.validateConstraint(t, change, change.C.Name))
+				}
 				// Reverse operation is supported if
 				// the constraint name is not generated.
 				if reversible = reversible && change.C.Name != ""; reversible {
@@ -584,15 +789,29 @@ func (a *changeGroup) append(s *state) {
 	s.append(a.after...)
 }
 
+// validateConstraint returns the statement for validating a
+// constraint that was added to the table with the NOT VALID clause.
+// The statement has no reverse, as dropping the constraint is the
+// reverse of adding it.
+func (s *state) validateConstraint(t *schema.Table, source schema.Change, name string) *migrate.Change {
+	return &migrate.Change{
+		Source:  source,
+		Cmd:     s.Build("ALTER TABLE").Table(t).P("VALIDATE CONSTRAINT").Ident(name).String(),
+		Comment: fmt.Sprintf("validate constraint %q of table %q", name, t.Name),
+		Reverse: []string{},
+	}
+}
+
 func (s *state) alterColumn(b *sqlx.Builder, alter *changeGroup, t *schema.Table, c *schema.ModifyColumn) error {
 	for k := c.Change; !k.Is(schema.NoChange); {
 		b.P("ALTER COLUMN").Ident(c.To.Name)
 		switch {
-		case k.Is(schema.ChangeType):
+		// Changing the column collation requires rewriting its type.
+		case k.Is(schema.ChangeType), k.Is(schema.ChangeCollate):
 			if err := s.alterType(b, alter, t, c); err != nil {
 				return err
 			}
-			k &= ^schema.ChangeType
+			k &= ^(schema.ChangeType | schema.ChangeCollate)
 		case k.Is(schema.ChangeNull) && c.To.Type.Null:
 			if t, ok := c.To.Type.Type.(*SerialType); ok {
 				return fmt.Errorf("NOT NULL constraint is required for %s column %q", t.T, c.To.Name)
@@ -720,13 +939,37 @@ func (s *state) alterType(b *sqlx.Builder, alter *changeGroup, t *schema.Table,
 		}
 		b.P("TYPE", f)
 	}
-	if collate := (schema.Collation{}); sqlx.Has(c.To.Attrs, &collate) {
-		b.P("COLLATE", collate.V)
+	switch collate := (schema.Collation{}); {
+	case sqlx.Has(c.To.Attrs, &collate):
+		b.P("COLLATE").Ident(collate.V)
+	// Collation was reset to the default one.
+	case c.Change.Is(schema.ChangeCollate):
+		b.P("COLLATE").Ident("default")
 	}
 	return nil
 }
 
 func (s *state) renameTable(c *schema.RenameTable) {
+	// Tables are moved between schemas with SET SCHEMA,
+	// and then renamed within their new schema, if needed.
+	if c.From.Schema != nil && c.To.Schema != nil && c.From.Schema.Name != c.To.Schema.Name {
+		moved := schema.NewTable(c.From.Name).SetSchema(c.To.Schema)
+		s.append(&migrate.Change{
+			Source:  c,
+			Comment: fmt.Sprintf("move a table from schema %q to %q", c.From.Schema.Name, c.To.Schema.Name),
+			Cmd:     s.Build("ALTER TABLE").Table(c.From).P("SET SCHEMA").Ident(c.To.Schema.Name).String(),
+			Reverse: s.Build("ALTER TABLE").Table(moved).P("SET SCHEMA").Ident(c.From.Schema.Name).String(),
+		})
+		if c.From.Name != c.To.Name {
+			s.append(&migrate.Change{
+				Source:  c,
+				Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
+				Cmd:     s.Build("ALTER TABLE").Table(moved).P("RENAME TO").Ident(c.To.Name).String(),
+				Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Ident(c.From.Name).String(),
+			})
+		}
+		return
+	}
 	s.append(&migrate.Change{
 		Source:  c,
 		Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
@@ -761,6 +1004,95 @@ func (s *state) schemaComment(sc *schema.Schema, to, from string) *migrate.Chang
 	}
 }
 
+// schemaCommentChange appends the statement for changing the comment of a schema.
+func (s *state) schemaCommentChange(sc *schema.Schema, c schema.Change) error {
+	switch c := c.(type) {
+	// Add schema attributes to an existing schema only if
+	// it is different from the default server configuration.
+	case *schema.AddAttr:
+		a, ok := c.A.(*schema.Comment)
+		if !ok {
+			return fmt.Errorf("unexpected schema AddAttr: %T", c.A)
+		}
+		s.append(s.schemaComment(sc, a.Text, ""))
+	case *schema.ModifyAttr:
+		to, ok1 := c.To.(*schema.Comment)
+		from, ok2 := c.From.(*schema.Comment)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("unexpected schema ModifyAttr: (%T, %T)", c.To, c.From)
+		}
+		s.append(s.schemaComment(sc, to.Text, from.Text))
+	}
+	return nil
+}
+
+// privileges returns the GRANT and REVOKE statements for migrating the privileges of
+// a grantee on the given object from one state to the other. A nil "from" indicates
+// the grantee does not hold any privileges on the object.
+func (s *state) privileges(source schema.Change, kind, object string, from, to *Privilege, all []string) []*migrate.Change {
+	var (
+		have    []string
+		grantee = granteeName(to.Grantee)
+		want    = privilegeSet(to, all)
+	)
+	if from != nil {
+		have = privilegeSet(from, all)
+	}
+	grantB := func(privs []string, opt bool) string {
+		b := s.Build("GRANT", strings.Join(privs, ", "), "ON", kind, object, "TO")
+		granteeIdent(b, grantee)
+		if opt {
+			b.P("WITH GRANT OPTION")
+		}
+		return b.String()
+	}
+	revokeB := func(privs []string) string {
+		b := s.Build("REVOKE", strings.Join(privs, ", "), "ON", kind, object, "FROM")
+		return granteeIdent(b, grantee).String()
+	}
+	var changes []*migrate.Change
+	if grant := valuesExcept(want, have); len(grant) > 0 {
+		changes = append(changes, &migrate.Change{
+			Source:  source,
+			Cmd:     grantB(grant, to.GrantOption),
+			Reverse: revokeB(grant),
+			Comment: fmt.Sprintf("grant privileges on %s %s to %q", strings.ToLower(kind), object, grantee),
+		})
+	}
+	if revoke := valuesExcept(have, want); len(revoke) > 0 {
+		changes = append(changes, &migrate.Change{
+			Source:  source,
+			Cmd:     revokeB(revoke),
+			Reverse: grantB(revoke, from.GrantOption),
+			Comment: fmt.Sprintf("revoke privileges on %s %s from %q", strings.ToLower(kind), object, grantee),
+		})
+	}
+	return changes
+}
+
+// valuesExcept returns the values in a that do not exist in b.
+func valuesExcept(a, b []string) []string {
+	var except []string
+search:
+	for _, v := range a {
+		for _, u := range b {
+			if u == v {
+				continue search
+			}
+		}
+		except = append(except, v)
+	}
+	return except
+}
+
+// granteeIdent writes the grantee to the builder.
+func granteeIdent(b *sqlx.Builder, grantee string) *sqlx.Builder {
+	if grantee == "PUBLIC" {
+		return b.P(grantee)
+	}
+	return b.Ident(grantee)
+}
+
 func (s *state) tableComment(t *schema.Table, to, from string) *migrate.Change {
 	b := s.Build("COMMENT ON TABLE").Table(t).P("IS")
 	return &migrate.Change{
@@ -771,9 +1103,7 @@ func (s *state) tableComment(t *schema.Table, to, from string) *migrate.Change {
 }
 
 func (s *state) columnComment(t *schema.Table, c *schema.Column, to, from string) *migrate.Change {
-	b := s.Build("COMMENT ON COLUMN").Table(t)
-	b.WriteByte('.')
-	b.Ident(c.Name).P("IS")
+	b := s.Build("COMMENT ON COLUMN").TableResource(t, c).P("IS")
 	return &migrate.Change{
 		Cmd:     b.Clone().P(quote(to)).String(),
 		Comment: fmt.Sprintf("set comment to column: %q on table: %q", c.Name, t.Name),
@@ -782,7 +1112,7 @@ func (s *state) columnComment(t *schema.Table, c *schema.Column, to, from string
 }
 
 func (s *state) indexComment(t *schema.Table, idx *schema.Index, to, from string) *migrate.Change {
-	b := s.Build("COMMENT ON INDEX").Ident(idx.Name).P("IS")
+	b := s.Build("COMMENT ON INDEX").SchemaResource(t.Schema, idx.Name).P("IS")
 	return &migrate.Change{
 		Cmd:     b.Clone().P(quote(to)).String(),
 		Comment: fmt.Sprintf("set comment to index: %q on table: %q", idx.Name, t.Name),
@@ -850,6 +1180,9 @@ func (s *state) addIndexes(t *schema.Table, adds ...*schema.AddIndex) error {
 		if err := s.index(b, idx); err != nil {
 			return err
 		}
+		if s.yugabyte {
+			ybSplit(b, idx.Attrs)
+		}
 		s.append(&migrate.Change{
 			Cmd:     b.String(),
 			Comment: fmt.Sprintf("create index %q to table: %q", idx.Name, t.Name),
@@ -874,6 +1207,9 @@ func (s *state) addIndexes(t *schema.Table, adds ...*schema.AddIndex) error {
 }
 
 func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
+	if s.redshift {
+		return s.rsColumn(b, c)
+	}
 	f, err := s.formatType(c)
 	if err != nil {
 		return err
@@ -894,7 +1230,11 @@ func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
 		case *Identity, *schema.GeneratedExpr:
 			// Handled below.
 		default:
-			return fmt.Errorf("unexpected column attribute: %T", attr)
+			c, ok := customAttrs.Format(attr)
+			if !ok {
+				return fmt.Errorf("unexpected column attribute: %T", attr)
+			}
+			b.P(c)
 		}
 	}
 	switch hasI, hasX := sqlx.Has(c.Attrs, &Identity{}), sqlx.Has(c.Attrs, &schema.GeneratedExpr{}); {
@@ -1016,14 +1356,7 @@ func (s *state) index(b *sqlx.Builder, idx *schema.Index) error {
 	if p, ok := indexStorageParams(idx.Attrs); ok {
 		b.P("WITH")
 		b.Wrap(func(b *sqlx.Builder) {
-			var parts []string
-			if p.AutoSummarize {
-				parts = append(parts, "autosummarize = true")
-			}
-			if p.PagesPerRange != 0 && p.PagesPerRange != defaultPagePerRange {
-				parts = append(parts, fmt.Sprintf("pages_per_range = %d", p.PagesPerRange))
-			}
-			b.WriteString(strings.Join(parts, ", "))
+			storageParams(b, p.list())
 		})
 	}
 	if p := (IndexPredicate{}); sqlx.Has(idx.Attrs, &p) {
@@ -1031,7 +1364,7 @@ func (s *state) index(b *sqlx.Builder, idx *schema.Index) error {
 	}
 	for _, attr := range idx.Attrs {
 		switch attr.(type) {
-		case *schema.Comment, *IndexType, *IndexInclude, *Constraint, *IndexPredicate, *IndexStorageParams, *IndexNullsDistinct:
+		case *schema.Comment, *IndexType, *IndexInclude, *Constraint, *IndexPredicate, *IndexStorageParams, *IndexNullsDistinct, *TabletSplit:
 		default:
 			return fmt.Errorf("postgres: unexpected index attribute: %T", attr)
 		}
@@ -1138,6 +1471,20 @@ func commentChange(c schema.Change) (from, to string, err error) {
 	return
 }
 
+// privilegeChange extracts the information for modifying privileges from the given change.
+func privilegeChange(c schema.Change) (from, to *Privilege, ok bool) {
+	switch c := c.(type) {
+	case *schema.AddAttr:
+		to, ok = c.A.(*Privilege)
+	case *schema.ModifyAttr:
+		from, ok = c.From.(*Privilege)
+		if ok {
+			to, ok = c.To.(*Privilege)
+		}
+	}
+	return
+}
+
 // checks writes the CHECK constraint to the builder.
 func check(b *sqlx.Builder, c *schema.Check) {
 	if c.Name != "" {
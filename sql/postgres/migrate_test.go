@@ -190,6 +190,39 @@ func TestPlanChanges(t *testing.T) {
 				Transactional: true,
 				Changes:       []*migrate.Change{{Cmd: `CREATE SCHEMA "test"`, Reverse: `DROP SCHEMA "test" CASCADE`}}},
 		},
+		{
+			changes: []schema.Change{
+				&schema.AddSchema{S: schema.New("test").AddAttrs(&Privilege{Grantee: "app", Privileges: []string{"USAGE"}})},
+				&schema.ModifySchema{
+					S: schema.New("public"),
+					Changes: schema.Changes{
+						&schema.AddAttr{A: &Privilege{Grantee: "PUBLIC", Privileges: []string{"ALL"}}},
+					},
+				},
+				&schema.ModifyTable{
+					T: schema.NewTable("users").SetSchema(schema.New("public")),
+					Changes: schema.Changes{
+						&schema.AddAttr{A: &Privilege{Grantee: "app", Privileges: []string{"SELECT", "INSERT"}, GrantOption: true}},
+						&schema.ModifyAttr{
+							From: &Privilege{Grantee: "reader", Privileges: []string{"SELECT", "UPDATE"}},
+							To:   &Privilege{Grantee: "reader", Privileges: []string{"select", "REFERENCES"}},
+						},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: `CREATE SCHEMA "test"`, Reverse: `DROP SCHEMA "test" CASCADE`},
+					{Cmd: `GRANT USAGE ON SCHEMA "test" TO "app"`, Reverse: `REVOKE USAGE ON SCHEMA "test" FROM "app"`},
+					{Cmd: `GRANT CREATE, USAGE ON SCHEMA "public" TO PUBLIC`, Reverse: `REVOKE CREATE, USAGE ON SCHEMA "public" FROM PUBLIC`},
+					{Cmd: `GRANT INSERT, SELECT ON TABLE "public"."users" TO "app" WITH GRANT OPTION`, Reverse: `REVOKE INSERT, SELECT ON TABLE "public"."users" FROM "app"`},
+					{Cmd: `GRANT REFERENCES ON TABLE "public"."users" TO "reader"`, Reverse: `REVOKE REFERENCES ON TABLE "public"."users" FROM "reader"`},
+					{Cmd: `REVOKE UPDATE ON TABLE "public"."users" FROM "reader"`, Reverse: `GRANT UPDATE ON TABLE "public"."users" TO "reader"`},
+				},
+			},
+		},
 		{
 			changes: []schema.Change{
 				&schema.DropSchema{S: &schema.Schema{Name: "atlas"}},
@@ -244,6 +277,101 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			changes: []schema.Change{
+				&schema.RenameSchema{From: schema.New("old"), To: schema.New("new")},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER SCHEMA "old" RENAME TO "new"`, Reverse: `ALTER SCHEMA "new" RENAME TO "old"`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: schema.NewTable("logs").
+						AddColumns(schema.NewIntColumn("id", "integer")).
+						AddAttrs(&TableStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}, {N: "autovacuum_vacuum_scale_factor", V: "0.05"}}}),
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: `CREATE TABLE "logs" ("id" integer NOT NULL) WITH (fillfactor = 70, autovacuum_vacuum_scale_factor = 0.05)`, Reverse: `DROP TABLE "logs"`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: schema.NewTable("logs"),
+					Changes: []schema.Change{
+						&schema.ModifyAttr{
+							From: &TableStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}, {N: "autovacuum_enabled", V: "false"}}},
+							To:   &TableStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "80"}}},
+						},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE "logs" SET (fillfactor = 80), RESET (autovacuum_enabled)`, Reverse: `ALTER TABLE "logs" SET (fillfactor = 70, autovacuum_enabled = false)`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: schema.NewTable("logs"),
+					Changes: []schema.Change{
+						&schema.AddIndex{
+							I: schema.NewIndex("logs_id").
+								AddColumns(schema.NewIntColumn("id", "integer")).
+								AddAttrs(&IndexStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}, {N: "deduplicate_items", V: "off"}}}),
+						},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: `CREATE INDEX "logs_id" ON "logs" ("id") WITH (fillfactor = 70, deduplicate_items = off)`, Reverse: `DROP INDEX "logs_id"`},
+				},
+			},
+		},
+		{
+			changes: func() []schema.Change {
+				t := schema.NewTable("logs").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("id", "integer"))
+				return []schema.Change{
+					&schema.ModifyTable{
+						T: t,
+						Changes: []schema.Change{
+							&schema.ModifyIndex{
+								From: schema.NewIndex("logs_id").SetTable(t).AddColumns(t.Columns[0]).
+									AddAttrs(&IndexStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "70"}, {N: "deduplicate_items", V: "off"}}}),
+								To: schema.NewIndex("logs_id").SetTable(t).AddColumns(t.Columns[0]).
+									AddAttrs(&IndexStorageParams{Params: []struct{ N, V string }{{N: "fillfactor", V: "80"}}}),
+								Change: schema.ChangeAttr,
+							},
+						},
+					},
+				}
+			}(),
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER INDEX "public"."logs_id" SET (fillfactor = 80), RESET (deduplicate_items)`, Reverse: `ALTER INDEX "public"."logs_id" SET (fillfactor = 70, deduplicate_items = off)`},
+				},
+			},
+		},
 		{
 			changes: []schema.Change{
 				&schema.AddTable{
@@ -395,6 +523,167 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			changes: func() []schema.Change {
+				public := schema.New("public")
+				users := schema.NewTable("users").SetSchema(public).AddColumns(schema.NewIntColumn("id", "bigint"))
+				seq := &schema.Sequence{Name: "users_id", Schema: public, Type: &schema.IntegerType{T: "bigint"}, Start: 100, Increment: 10, Cycle: true, Owner: schema.SequenceOwner{T: users, C: users.Columns[0]}}
+				from := &schema.Sequence{Name: "orders_id", Schema: public, Start: 5, Min: 5, Cache: 10}
+				to := &schema.Sequence{Name: "orders_id", Schema: public, Max: 1000, Cycle: true}
+				return []schema.Change{
+					&schema.AddSequence{S: seq},
+					&schema.ModifySequence{From: from, To: to},
+					&schema.AddTable{T: users},
+					&schema.DropTable{T: schema.NewTable("logs").SetSchema(public).AddColumns(schema.NewIntColumn("id", "bigint"))},
+					&schema.DropSequence{S: &schema.Sequence{Name: "logs_id", Schema: public, Increment: -1}},
+				}
+			}(),
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE SEQUENCE "public"."users_id" AS bigint INCREMENT BY 10 START WITH 100 CYCLE`,
+						Reverse: `DROP SEQUENCE "public"."users_id"`,
+					},
+					{
+						Cmd:     `ALTER SEQUENCE "public"."orders_id" NO MINVALUE MAXVALUE 1000 START WITH 1 CACHE 1 CYCLE`,
+						Reverse: `ALTER SEQUENCE "public"."orders_id" MINVALUE 5 NO MAXVALUE START WITH 5 CACHE 10 NO CYCLE`,
+					},
+					{
+						Cmd:     `CREATE TABLE "public"."users" ("id" bigint NOT NULL)`,
+						Reverse: `DROP TABLE "public"."users"`,
+					},
+					{
+						Cmd:     `ALTER SEQUENCE "public"."users_id" OWNED BY "public"."users"."id"`,
+						Reverse: `ALTER SEQUENCE "public"."users_id" OWNED BY NONE`,
+					},
+					{
+						Cmd:     `DROP TABLE "public"."logs"`,
+						Reverse: `CREATE TABLE "public"."logs" ("id" bigint NOT NULL)`,
+					},
+					{
+						Cmd:     `DROP SEQUENCE "public"."logs_id"`,
+						Reverse: `CREATE SEQUENCE "public"."logs_id" INCREMENT BY -1`,
+					},
+				},
+			},
+		},
+		{
+			changes: func() []schema.Change {
+				public := schema.New("public")
+				users := schema.NewTable("users").SetSchema(public).AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewStringColumn("name", "text"))
+				active := schema.NewView("active", "SELECT * FROM users").SetSchema(public)
+				users.AddTriggers(
+					&schema.Trigger{Name: "users_audit", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert, schema.TriggerEventUpdateOf(users.Columns[1])}, For: schema.TriggerForRow, Body: "EXECUTE FUNCTION audit()"},
+					&schema.Trigger{Name: "users_truncate", ActionTime: schema.TriggerTimeBefore, Events: []schema.TriggerEvent{schema.TriggerEventTruncate}, For: schema.TriggerForStmt, Body: "EXECUTE FUNCTION audit()"},
+					&schema.Trigger{Name: "users_truncate", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventTruncate}, For: schema.TriggerForStmt, Body: "EXECUTE FUNCTION audit()"},
+				)
+				active.AddTriggers(&schema.Trigger{Name: "active_insert", ActionTime: schema.TriggerTimeInstead, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, For: schema.TriggerForRow, Body: "EXECUTE FUNCTION insert_user()"})
+				return []schema.Change{
+					&schema.AddTrigger{T: users.Triggers[0]},
+					&schema.ModifyTrigger{From: users.Triggers[1], To: users.Triggers[2]},
+					&schema.DropTrigger{T: active.Triggers[0]},
+				}
+			}(),
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE TRIGGER "users_audit" AFTER INSERT OR UPDATE OF "name" ON "public"."users" FOR EACH ROW EXECUTE FUNCTION audit()`,
+						Reverse: `DROP TRIGGER "users_audit" ON "public"."users"`,
+					},
+					{
+						Cmd:     `DROP TRIGGER "users_truncate" ON "public"."users"`,
+						Reverse: `CREATE TRIGGER "users_truncate" BEFORE TRUNCATE ON "public"."users" FOR EACH STATEMENT EXECUTE FUNCTION audit()`,
+					},
+					{
+						Cmd:     `CREATE TRIGGER "users_truncate" AFTER TRUNCATE ON "public"."users" FOR EACH STATEMENT EXECUTE FUNCTION audit()`,
+						Reverse: `DROP TRIGGER "users_truncate" ON "public"."users"`,
+					},
+					{
+						Cmd:     `DROP TRIGGER "active_insert" ON "public"."active"`,
+						Reverse: `CREATE TRIGGER "active_insert" INSTEAD OF INSERT ON "public"."active" FOR EACH ROW EXECUTE FUNCTION insert_user()`,
+					},
+				},
+			},
+		},
+		{
+			changes: func() []schema.Change {
+				public := schema.New("public")
+				add := &schema.Func{
+					Name:   "add",
+					Schema: public,
+					Args: []*schema.FuncArg{
+						{Name: "a", Type: &schema.IntegerType{T: "integer"}},
+						{Name: "b", Type: &schema.IntegerType{T: "integer"}, Default: &schema.Literal{V: "1"}},
+					},
+					Ret:  &schema.IntegerType{T: "integer"},
+					Lang: "sql",
+					Body: "SELECT a + b",
+				}
+				users := &schema.Func{
+					Name:     "users",
+					Schema:   public,
+					Args:     []*schema.FuncArg{{Name: "n", Type: &schema.StringType{T: "text"}}},
+					RetTable: []*schema.Column{schema.NewIntColumn("id", "bigint")},
+					Lang:     "plpgsql",
+					Body:     "BEGIN RETURN QUERY SELECT 1::bigint; END",
+				}
+				users2 := *users
+				users2.Body = "BEGIN RETURN QUERY SELECT 2::bigint; END"
+				sum := &schema.Func{
+					Name:   "sum",
+					Schema: public,
+					Args:   []*schema.FuncArg{{Type: &schema.IntegerType{T: "integer"}}, {Name: "r", Type: &schema.IntegerType{T: "integer"}, Mode: schema.FuncArgModeOut}},
+					Lang:   "sql",
+					Body:   "SELECT $1 + 1",
+				}
+				sum2 := *sum
+				sum2.Args = []*schema.FuncArg{{Type: &schema.IntegerType{T: "bigint"}}}
+				sum2.Ret = &schema.IntegerType{T: "bigint"}
+				log := &schema.Proc{
+					Name:   "log",
+					Schema: public,
+					Args:   []*schema.FuncArg{{Name: "msg", Type: &schema.StringType{T: "text"}}, {Name: "id", Type: &schema.IntegerType{T: "bigint"}, Mode: schema.FuncArgModeInOut}},
+					Lang:   "sql",
+					Body:   "$$ INSERT INTO logs VALUES (msg) $$",
+				}
+				return []schema.Change{
+					&schema.AddFunc{F: add},
+					&schema.ModifyFunc{From: users, To: &users2},
+					&schema.ModifyFunc{From: sum, To: &sum2},
+					&schema.DropProc{P: log},
+				}
+			}(),
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE FUNCTION "public"."add" ("a" integer, "b" integer DEFAULT 1) RETURNS integer LANGUAGE sql AS $$SELECT a + b$$`,
+						Reverse: `DROP FUNCTION "public"."add" (integer, integer)`,
+					},
+					{
+						Cmd:     `CREATE OR REPLACE FUNCTION "public"."users" ("n" text) RETURNS TABLE ("id" bigint) LANGUAGE plpgsql AS $$BEGIN RETURN QUERY SELECT 2::bigint; END$$`,
+						Reverse: `CREATE OR REPLACE FUNCTION "public"."users" ("n" text) RETURNS TABLE ("id" bigint) LANGUAGE plpgsql AS $$BEGIN RETURN QUERY SELECT 1::bigint; END$$`,
+					},
+					{
+						Cmd:     `DROP FUNCTION "public"."sum" (integer)`,
+						Reverse: `CREATE FUNCTION "public"."sum" (integer, OUT "r" integer) LANGUAGE sql AS $$SELECT $1 + 1$$`,
+					},
+					{
+						Cmd:     `CREATE FUNCTION "public"."sum" (bigint) RETURNS bigint LANGUAGE sql AS $$SELECT $1 + 1$$`,
+						Reverse: `DROP FUNCTION "public"."sum" (bigint)`,
+					},
+					{
+						Cmd:     `DROP PROCEDURE "public"."log" (text, bigint)`,
+						Reverse: `CREATE PROCEDURE "public"."log" ("msg" text, INOUT "id" bigint) LANGUAGE sql AS $$ INSERT INTO logs VALUES (msg) $$`,
+					},
+				},
+			},
+		},
 		{
 			changes: []schema.Change{
 				&schema.DropTable{
@@ -686,12 +975,12 @@ func TestPlanChanges(t *testing.T) {
 						Reverse: `DROP INDEX "nulls_not_distinct"`,
 					},
 					{
-						Cmd:     `COMMENT ON COLUMN "users" ."name" IS 'foo'`,
-						Reverse: `COMMENT ON COLUMN "users" ."name" IS ''`,
+						Cmd:     `COMMENT ON COLUMN "users"."name" IS 'foo'`,
+						Reverse: `COMMENT ON COLUMN "users"."name" IS ''`,
 					},
 					{
-						Cmd:     `COMMENT ON COLUMN "users" ."last" IS 'bar'`,
-						Reverse: `COMMENT ON COLUMN "users" ."last" IS ''`,
+						Cmd:     `COMMENT ON COLUMN "users"."last" IS 'bar'`,
+						Reverse: `COMMENT ON COLUMN "users"."last" IS ''`,
 					},
 					{
 						Cmd:     `COMMENT ON INDEX "id_key" IS 'comment'`,
@@ -700,6 +989,35 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: schema.NewTable("users"),
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   schema.NewStringColumn("name", "text"),
+							To:     schema.NewStringColumn("name", "text").SetCollation("C"),
+							Change: schema.ChangeCollate,
+						},
+						&schema.ModifyColumn{
+							From:   schema.NewStringColumn("nick", "varchar", schema.StringSize(255)).SetCollation("C"),
+							To:     schema.NewStringColumn("nick", "text").SetCollation("en_US"),
+							Change: schema.ChangeType | schema.ChangeCollate,
+						},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `ALTER TABLE "users" ALTER COLUMN "name" TYPE text COLLATE "C", ALTER COLUMN "nick" TYPE text COLLATE "en_US"`,
+						Reverse: `ALTER TABLE "users" ALTER COLUMN "nick" TYPE character varying(255) COLLATE "C", ALTER COLUMN "name" TYPE text COLLATE "default"`,
+					},
+				},
+			},
+		},
 		{
 			changes: []schema.Change{
 				func() schema.Change {
@@ -733,8 +1051,8 @@ func TestPlanChanges(t *testing.T) {
 						Reverse: `ALTER TABLE "users" ALTER COLUMN "id" SET GENERATED BY DEFAULT SET START WITH 1 SET INCREMENT BY 1 RESTART, ADD COLUMN "name" character varying NOT NULL`,
 					},
 					{
-						Cmd:     `COMMENT ON COLUMN "users" ."id" IS ''`,
-						Reverse: `COMMENT ON COLUMN "users" ."id" IS 'comment'`,
+						Cmd:     `COMMENT ON COLUMN "users"."id" IS ''`,
+						Reverse: `COMMENT ON COLUMN "users"."id" IS 'comment'`,
 					},
 				},
 			},
@@ -1035,7 +1353,7 @@ func TestPlanChanges(t *testing.T) {
 				Transactional: true,
 				Changes: []*migrate.Change{
 					{Cmd: `ALTER TYPE "public"."state" ADD VALUE 'unknown'`},
-					{Cmd: `COMMENT ON COLUMN "public"."users" ."state" IS ''`, Reverse: `COMMENT ON COLUMN "public"."users" ."state" IS 'foo'`},
+					{Cmd: `COMMENT ON COLUMN "public"."users"."state" IS ''`, Reverse: `COMMENT ON COLUMN "public"."users"."state" IS 'foo'`},
 				},
 			},
 		},
@@ -1065,7 +1383,7 @@ func TestPlanChanges(t *testing.T) {
 				Reversible:    true,
 				Transactional: true,
 				Changes: []*migrate.Change{
-					{Cmd: `COMMENT ON COLUMN "users" ."state" IS 'foo'`, Reverse: `COMMENT ON COLUMN "users" ."state" IS 'bar'`},
+					{Cmd: `COMMENT ON COLUMN "users"."state" IS 'foo'`, Reverse: `COMMENT ON COLUMN "users"."state" IS 'bar'`},
 				},
 			},
 		},
@@ -1103,6 +1421,33 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Index comments are qualified with the schema name, like the index itself.
+		{
+			changes: []schema.Change{
+				func() schema.Change {
+					users := schema.NewTable("users").
+						SetSchema(schema.New("public")).
+						AddColumns(schema.NewIntColumn("id", "bigint"))
+					return &schema.ModifyTable{
+						T: users,
+						Changes: []schema.Change{
+							&schema.ModifyIndex{
+								From:   schema.NewIndex("id_key").AddColumns(users.Columns[0]),
+								To:     schema.NewIndex("id_key").AddColumns(users.Columns[0]).SetComment("bar"),
+								Change: schema.ChangeComment,
+							},
+						},
+					}
+				}(),
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: `COMMENT ON INDEX "public"."id_key" IS 'bar'`, Reverse: `COMMENT ON INDEX "public"."id_key" IS ''`},
+				},
+			},
+		},
 		// Modify default values.
 		{
 			changes: []schema.Change{
@@ -1160,8 +1505,12 @@ func TestPlanChanges(t *testing.T) {
 				Transactional: true,
 				Changes: []*migrate.Change{
 					{
-						Cmd:     `ALTER TABLE "s1"."t1" RENAME TO "s2"."t2"`,
-						Reverse: `ALTER TABLE "s2"."t2" RENAME TO "s1"."t1"`,
+						Cmd:     `ALTER TABLE "s1"."t1" SET SCHEMA "s2"`,
+						Reverse: `ALTER TABLE "s2"."t1" SET SCHEMA "s1"`,
+					},
+					{
+						Cmd:     `ALTER TABLE "s2"."t1" RENAME TO "t2"`,
+						Reverse: `ALTER TABLE "s2"."t2" RENAME TO "t1"`,
 					},
 				},
 			},
@@ -1509,6 +1858,103 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Foreign-data objects.
+		{
+			changes: []schema.Change{
+				&schema.AddObject{O: &ForeignServer{Name: "remote", Wrapper: "postgres_fdw", Version: "15", Options: []FDWOption{{"host", "localhost"}, {"dbname", "app"}}}},
+				&schema.ModifyObject{
+					From: &ForeignServer{Name: "other", Wrapper: "postgres_fdw", Options: []FDWOption{{"host", "a"}, {"port", "5432"}}},
+					To:   &ForeignServer{Name: "other", Wrapper: "postgres_fdw", Options: []FDWOption{{"host", "b"}, {"dbname", "app"}}},
+				},
+				&schema.AddObject{O: &UserMapping{User: "PUBLIC", Server: "remote", Options: []FDWOption{{"user", "app"}}}},
+				&schema.AddObject{O: &ForeignTable{Name: "users", Schema: schema.New("public"), Server: "remote", Columns: []*schema.Column{schema.NewIntColumn("id", "int")}, Options: []FDWOption{{"table_name", "users"}}}},
+				&schema.DropObject{O: &ForeignTable{Name: "pets", Schema: schema.New("public"), Server: "other", Columns: []*schema.Column{schema.NewIntColumn("id", "int")}}},
+				&schema.DropObject{O: &UserMapping{User: "app", Server: "other"}},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE SERVER "remote" VERSION '15' FOREIGN DATA WRAPPER "postgres_fdw" OPTIONS (host 'localhost', dbname 'app')`,
+						Reverse: `DROP SERVER "remote"`,
+					},
+					{
+						Cmd:     `ALTER SERVER "other" OPTIONS (SET host 'b', ADD dbname 'app', DROP port)`,
+						Reverse: `ALTER SERVER "other" OPTIONS (SET host 'a', ADD port '5432', DROP dbname)`,
+					},
+					{
+						Cmd:     `CREATE USER MAPPING FOR PUBLIC SERVER "remote" OPTIONS (user 'app')`,
+						Reverse: `DROP USER MAPPING FOR PUBLIC SERVER "remote"`,
+					},
+					{
+						Cmd:     `CREATE FOREIGN TABLE "public"."users" ("id" integer NOT NULL) SERVER "remote" OPTIONS (table_name 'users')`,
+						Reverse: `DROP FOREIGN TABLE "public"."users"`,
+					},
+					{
+						Cmd:     `DROP FOREIGN TABLE "public"."pets"`,
+						Reverse: `CREATE FOREIGN TABLE "public"."pets" ("id" integer NOT NULL) SERVER "other"`,
+					},
+					{
+						Cmd:     `DROP USER MAPPING FOR "app" SERVER "other"`,
+						Reverse: `CREATE USER MAPPING FOR "app" SERVER "other"`,
+					},
+				},
+			},
+		},
+		// Publications.
+		{
+			changes: func() []schema.Change {
+				var (
+					users = schema.NewTable("users").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("id", "int"))
+					pets  = schema.NewTable("pets").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("id", "int"))
+				)
+				return []schema.Change{
+					&schema.AddObject{O: &Publication{Name: "p1", Tables: []*schema.Table{users}, Publish: []string{"insert", "update"}}},
+					&schema.AddTable{T: users},
+					&schema.ModifyObject{
+						From: &Publication{Name: "p2", Tables: []*schema.Table{pets}},
+						To:   &Publication{Name: "p2", Tables: []*schema.Table{users}, Publish: []string{"insert"}},
+					},
+					&schema.DropTable{T: pets},
+					&schema.DropObject{O: &Publication{Name: "p3", AllTables: true}},
+				}
+			}(),
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE TABLE "public"."users" ("id" integer NOT NULL)`,
+						Reverse: `DROP TABLE "public"."users"`,
+					},
+					{
+						Cmd:     `CREATE PUBLICATION "p1" FOR TABLE "public"."users" WITH (publish = 'insert, update')`,
+						Reverse: `DROP PUBLICATION "p1"`,
+					},
+					{
+						Cmd:     `ALTER PUBLICATION "p2" DROP TABLE "public"."pets"`,
+						Reverse: `ALTER PUBLICATION "p2" ADD TABLE "public"."pets"`,
+					},
+					{
+						Cmd:     `ALTER PUBLICATION "p2" ADD TABLE "public"."users"`,
+						Reverse: `ALTER PUBLICATION "p2" DROP TABLE "public"."users"`,
+					},
+					{
+						Cmd:     `ALTER PUBLICATION "p2" SET (publish = 'insert')`,
+						Reverse: `ALTER PUBLICATION "p2" SET (publish = 'insert, update, delete, truncate')`,
+					},
+					{
+						Cmd:     `DROP TABLE "public"."pets"`,
+						Reverse: `CREATE TABLE "public"."pets" ("id" integer NOT NULL)`,
+					},
+					{
+						Cmd:     `DROP PUBLICATION "p3"`,
+						Reverse: `CREATE PUBLICATION "p3" FOR ALL TABLES`,
+					},
+				},
+			},
+		},
 		// Empty qualifier in multi-schema mode should fail.
 		{
 			changes: []schema.Change{
@@ -1562,6 +2008,160 @@ func TestDefaultPlan(t *testing.T) {
 	require.EqualError(t, err, `create "t1" table: cannot execute statements without a database connection. use Open to create a new Driver`)
 }
 
+func TestPlanChanges_CRDB(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(paramsQuery)).
+		WillReturnRows(sqltest.Rows(`
+  setting
+------------
+ 130000
+ 13.0.0
+ en_US.utf8
+ en_US.utf8
+ cockroach
+`))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	plan, err := drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{
+			T: schema.NewTable("users").AddColumns(
+				schema.NewStringColumn("name", TypeString),
+				schema.NewStringColumn("nick", TypeString, schema.StringSize(32)),
+			),
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, plan.Transactional, "schema changes are not transactional in CockroachDB")
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, `CREATE TABLE "users" ("name" text NOT NULL, "nick" character varying(32) NOT NULL)`, plan.Changes[0].Cmd)
+
+	_, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddObject{O: &ForeignServer{Name: "remote", Wrapper: "postgres_fdw"}},
+	})
+	require.EqualError(t, err, `foreign server "remote" is not supported by CockroachDB`)
+}
+
+func TestPlanChanges_Redshift(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape("SELECT version()")).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("PostgreSQL 8.0.2 on i686-pc-linux-gnu, Redshift 1.0.60103"))
+	drv, err := OpenRedshift(db)
+	require.NoError(t, err)
+
+	var (
+		id      = schema.NewIntColumn("id", "bigint").AddAttrs(&Identity{Sequence: &Sequence{Start: 1, Increment: 1}}, &Encode{T: "AZ64"})
+		userID  = schema.NewIntColumn("user_id", "integer")
+		created = schema.NewTimeColumn("created_at", "timestamp")
+		events  = schema.NewTable("events").
+			AddColumns(id, userID, created).
+			AddAttrs(
+				&DistStyle{S: DistStyleKey, Key: userID},
+				&SortKey{T: SortKeyCompound, Columns: []*schema.Column{created}},
+			)
+	)
+	events.SetPrimaryKey(schema.NewPrimaryKey(id))
+	plan, err := drv.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: events}})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, `CREATE TABLE "events" ("id" bigint IDENTITY(1, 1) ENCODE AZ64 NOT NULL, "user_id" integer NOT NULL, "created_at" timestamp NOT NULL, PRIMARY KEY ("id")) DISTSTYLE KEY DISTKEY ("user_id") SORTKEY ("created_at")`, plan.Changes[0].Cmd)
+
+	payload := schema.NewStringColumn("payload", "character varying", schema.StringSize(256)).AddAttrs(&Encode{T: "ZSTD"})
+	plan, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: events,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: payload},
+				&schema.ModifyColumn{
+					From:   schema.NewIntColumn("user_id", "integer").AddAttrs(&Encode{T: "AZ64"}),
+					To:     schema.NewIntColumn("user_id", "integer").AddAttrs(&Encode{T: "RAW"}),
+					Change: schema.ChangeAttr,
+				},
+				&schema.ModifyAttr{From: &DistStyle{S: DistStyleKey, Key: userID}, To: &DistStyle{S: DistStyleEven}},
+				&schema.ModifyAttr{From: &SortKey{T: SortKeyCompound, Columns: []*schema.Column{created}}, To: &SortKey{T: SortKeyCompound, Columns: []*schema.Column{created, userID}}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 4)
+	for i, c := range []struct{ cmd, reverse string }{
+		{`ALTER TABLE "events" ADD COLUMN "payload" character varying(256) ENCODE ZSTD NOT NULL`, `ALTER TABLE "events" DROP COLUMN "payload"`},
+		{`ALTER TABLE "events" ALTER COLUMN "user_id" ENCODE RAW`, `ALTER TABLE "events" ALTER COLUMN "user_id" ENCODE AZ64`},
+		{`ALTER TABLE "events" ALTER DISTSTYLE EVEN`, `ALTER TABLE "events" ALTER DISTSTYLE KEY DISTKEY "user_id"`},
+		{`ALTER TABLE "events" ALTER SORTKEY ("created_at", "user_id")`, `ALTER TABLE "events" ALTER SORTKEY ("created_at")`},
+	} {
+		require.Equal(t, c.cmd, plan.Changes[i].Cmd)
+		require.Equal(t, c.reverse, plan.Changes[i].Reverse)
+	}
+
+	_, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T:       events,
+			Changes: []schema.Change{&schema.AddIndex{I: schema.NewIndex("idx").AddColumns(created)}},
+		},
+	})
+	require.EqualError(t, err, `postgres: indexes are not supported by Redshift (table "events")`)
+}
+
+func TestPlanChanges_Yugabyte(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(paramsQuery)).
+		WillReturnRows(sqltest.Rows(`
+  setting
+------------
+ 110002
+ 11.2-YB-2.18.0.0-b0
+ en_US.utf8
+ en_US.utf8
+`))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	var (
+		id     = schema.NewIntColumn("id", "bigint")
+		name   = schema.NewStringColumn("name", "text")
+		events = schema.NewTable("events").
+			AddColumns(id, name).
+			AddAttrs(&Colocation{V: false}, &TabletSplit{Tablets: 4})
+	)
+	events.SetPrimaryKey(schema.NewPrimaryKey(id))
+	events.AddIndexes(schema.NewIndex("events_name").AddColumns(name).AddAttrs(&TabletSplit{At: []string{"('a')", "('m')"}}))
+	plan, err := drv.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: events}})
+	require.NoError(t, err)
+	require.False(t, plan.Transactional)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, `CREATE TABLE "events" ("id" bigint NOT NULL, "name" text NOT NULL, PRIMARY KEY ("id")) WITH (colocation = false) SPLIT INTO 4 TABLETS`, plan.Changes[0].Cmd)
+	require.Equal(t, `CREATE INDEX "events_name" ON "events" ("name") SPLIT AT VALUES (('a'), ('m'))`, plan.Changes[1].Cmd)
+
+	for _, tt := range []struct {
+		change  schema.Change
+		wantErr string
+	}{
+		{
+			change:  &schema.ModifyAttr{From: &Colocation{V: false}, To: &Colocation{V: true}},
+			wantErr: `postgres: changing the colocation of table "events" requires recreating it in YugabyteDB`,
+		},
+		{
+			change: &schema.ModifyColumn{
+				From:   name,
+				To:     schema.NewIntColumn("name", "integer"),
+				Change: schema.ChangeType,
+			},
+			wantErr: `postgres: changing the type of column "name" requires a table rewrite, which is not supported by YugabyteDB`,
+		},
+		{
+			change:  &schema.DropPrimaryKey{P: events.PrimaryKey},
+			wantErr: `postgres: changing the primary key of table "events" requires a table rewrite, which is not supported by YugabyteDB`,
+		},
+	} {
+		_, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{
+			&schema.ModifyTable{T: events, Changes: []schema.Change{tt.change}},
+		})
+		require.EqualError(t, err, tt.wantErr)
+	}
+}
+
 func TestIndentedPlan(t *testing.T) {
 	tests := []struct {
 		T   *schema.Table
@@ -0,0 +1,407 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+type (
+	// Publication describes a logical replication publication. Publications
+	// are not bound to a specific schema, and therefore, are stored in the
+	// realm objects.
+	// https://www.postgresql.org/docs/current/sql-createpublication.html
+	Publication struct {
+		schema.Object
+		Name      string
+		AllTables bool            // FOR ALL TABLES.
+		Tables    []*schema.Table // FOR TABLE t1, t2.
+		// Publish holds the published operations (e.g. insert, update).
+		// An empty list means all operations are published.
+		Publish []string
+	}
+
+	// PublicationSpec holds a specification for a publication.
+	PublicationSpec struct {
+		Name      string           `spec:",name"`
+		AllTables bool             `spec:"all_tables,omitempty"`
+		Tables    []*schemahcl.Ref `spec:"tables,omitempty"`
+		Publish   []string         `spec:"publish,omitempty"`
+		schemahcl.DefaultExtension
+	}
+)
+
+func init() {
+	schemahcl.Register("publication", &PublicationSpec{})
+}
+
+// List of operations that can be published.
+const (
+	PublishInsert   = "insert"
+	PublishUpdate   = "update"
+	PublishDelete   = "delete"
+	PublishTruncate = "truncate"
+)
+
+// inspectPublications queries and appends the publications to the realm.
+func (i *inspect) inspectPublications(ctx context.Context, r *schema.Realm) error {
+	truncate := "p.pubtruncate"
+	// The TRUNCATE operation is published since PostgreSQL 11.
+	if i.version < 11_00_00 {
+		truncate = "true"
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(publicationsQuery, truncate))
+	if err != nil {
+		return fmt.Errorf("postgres: querying publications: %w", err)
+	}
+	defer rows.Close()
+	var found bool
+	for rows.Next() {
+		var (
+			name                             string
+			all, insert, update, del, trunct bool
+		)
+		if err := rows.Scan(&name, &all, &insert, &update, &del, &trunct); err != nil {
+			return fmt.Errorf("postgres: scanning publication: %w", err)
+		}
+		p := &Publication{Name: name, AllTables: all}
+		// Operations are stored only if some are not published.
+		if !insert || !update || !del || !trunct {
+			for _, op := range []struct {
+				name string
+				ok   bool
+			}{{PublishInsert, insert}, {PublishUpdate, update}, {PublishDelete, del}, {PublishTruncate, trunct}} {
+				if op.ok {
+					p.Publish = append(p.Publish, op.name)
+				}
+			}
+		}
+		r.AddObjects(p)
+		found = true
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if !found || len(r.Schemas) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err = i.QueryContext(ctx, fmt.Sprintf(publicationTablesQuery, nArgs(0, len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying publication tables: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, ns, table string
+		if err := rows.Scan(&name, &ns, &table); err != nil {
+			return fmt.Errorf("postgres: scanning publication table: %w", err)
+		}
+		p, ok := findPublication(r, name)
+		// Tables of FOR ALL TABLES publications are implicit.
+		if !ok || p.AllTables {
+			continue
+		}
+		t := schema.NewTable(table).SetSchema(schema.New(ns))
+		if s, ok := r.Schema(ns); ok {
+			if t1, ok := s.Table(table); ok {
+				t = t1
+			}
+		}
+		p.Tables = append(p.Tables, t)
+	}
+	return rows.Close()
+}
+
+// publicationsDiff returns a changeset for migrating the publications from one state to the other.
+func publicationsDiff(from, to *schema.Realm) []schema.Change {
+	var changes []schema.Change
+	for _, o := range from.Objects {
+		p1, ok := o.(*Publication)
+		if !ok {
+			continue
+		}
+		switch p2, ok := findPublication(to, p1.Name); {
+		case !ok:
+			changes = append(changes, &schema.DropObject{O: p1})
+		case publicationChanged(p1, p2):
+			changes = append(changes, &schema.ModifyObject{From: p1, To: p2})
+		}
+	}
+	for _, o := range to.Objects {
+		p2, ok := o.(*Publication)
+		if !ok {
+			continue
+		}
+		if _, ok := findPublication(from, p2.Name); !ok {
+			changes = append(changes, &schema.AddObject{O: p2})
+		}
+	}
+	return changes
+}
+
+// publicationChanged reports if the publication definition was changed.
+func publicationChanged(from, to *Publication) bool {
+	if from.AllTables != to.AllTables || !sqlx.ValuesEqual(publishOps(from), publishOps(to)) {
+		return true
+	}
+	added, dropped := publicationTables(from, to)
+	return len(added) > 0 || len(dropped) > 0
+}
+
+// publicationTables returns the tables that were added to and dropped from the publication.
+func publicationTables(from, to *Publication) (added, dropped []*schema.Table) {
+	has := func(ts []*schema.Table, t *schema.Table) bool {
+		for _, t1 := range ts {
+			if t1.Name == t.Name && tableSchema(t1) == tableSchema(t) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, t := range to.Tables {
+		if !has(from.Tables, t) {
+			added = append(added, t)
+		}
+	}
+	for _, t := range from.Tables {
+		if !has(to.Tables, t) {
+			dropped = append(dropped, t)
+		}
+	}
+	return added, dropped
+}
+
+// publishOps returns the published operations of the publication in their canonical order.
+func publishOps(p *Publication) []string {
+	if len(p.Publish) == 0 {
+		return []string{PublishInsert, PublishUpdate, PublishDelete, PublishTruncate}
+	}
+	var ops []string
+	for _, op := range []string{PublishInsert, PublishUpdate, PublishDelete, PublishTruncate} {
+		for _, o := range p.Publish {
+			if strings.EqualFold(strings.TrimSpace(o), op) {
+				ops = append(ops, op)
+				break
+			}
+		}
+	}
+	return ops
+}
+
+// tableSchema returns the schema name of the table, if it is set.
+func tableSchema(t *schema.Table) string {
+	if t.Schema == nil {
+		return ""
+	}
+	return t.Schema.Name
+}
+
+// relinkPublication returns a copy of the publication, if the given object is a
+// publication, that references the tables of the given realm. Other objects are
+// returned as-is.
+func relinkPublication(r *schema.Realm, o schema.Object) (schema.Object, error) {
+	p, ok := o.(*Publication)
+	if !ok || len(p.Tables) == 0 {
+		return o, nil
+	}
+	pc := *p
+	pc.Tables = make([]*schema.Table, len(p.Tables))
+	for i, t := range p.Tables {
+		s, ok := r.Schema(tableSchema(t))
+		if !ok {
+			return nil, fmt.Errorf("postgres: schema %q of publication %q was not found", tableSchema(t), p.Name)
+		}
+		if pc.Tables[i], ok = s.Table(t.Name); !ok {
+			return nil, fmt.Errorf("postgres: table %q of publication %q was not found", t.Name, p.Name)
+		}
+	}
+	return &pc, nil
+}
+
+func findPublication(r *schema.Realm, name string) (*Publication, bool) {
+	o, ok := r.Object(func(o schema.Object) bool {
+		p, ok := o.(*Publication)
+		return ok && p.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*Publication), true
+}
+
+// addPublication appends the statements for creating a publication.
+func (s *state) addPublication(c *schema.AddObject) {
+	p := c.O.(*Publication)
+	s.append(&migrate.Change{
+		Source:  c,
+		Cmd:     s.createPublication(p),
+		Reverse: s.Build("DROP PUBLICATION").Ident(p.Name).String(),
+		Comment: fmt.Sprintf("create publication %q", p.Name),
+	})
+}
+
+// dropPublication appends the statement for dropping a publication.
+func (s *state) dropPublication(c *schema.DropObject) {
+	p := c.O.(*Publication)
+	s.append(&migrate.Change{
+		Source:  c,
+		Cmd:     s.Build("DROP PUBLICATION").Ident(p.Name).String(),
+		Reverse: s.createPublication(p),
+		Comment: fmt.Sprintf("drop publication %q", p.Name),
+	})
+}
+
+// createPublication returns the statement for creating the publication.
+func (s *state) createPublication(p *Publication) string {
+	b := s.Build("CREATE PUBLICATION").Ident(p.Name)
+	switch {
+	case p.AllTables:
+		b.P("FOR ALL TABLES")
+	case len(p.Tables) > 0:
+		b.P("FOR TABLE").MapComma(p.Tables, func(i int, b *sqlx.Builder) {
+			b.Table(p.Tables[i])
+		})
+	}
+	if len(p.Publish) > 0 {
+		b.P("WITH").Wrap(func(b *sqlx.Builder) {
+			b.P("publish =", quote(strings.Join(publishOps(p), ", ")))
+		})
+	}
+	return b.String()
+}
+
+// modifyPublication appends the statements for modifying a publication.
+func (s *state) modifyPublication(c *schema.ModifyObject) {
+	from, to := c.From.(*Publication), c.To.(*Publication)
+	// Publications cannot be altered from or to FOR ALL TABLES.
+	if from.AllTables != to.AllTables {
+		s.dropPublication(&schema.DropObject{O: from})
+		s.addPublication(&schema.AddObject{O: to})
+		return
+	}
+	alter := func(op string, ts []*schema.Table) *sqlx.Builder {
+		return s.Build("ALTER PUBLICATION").Ident(to.Name).P(op, "TABLE").MapComma(ts, func(i int, b *sqlx.Builder) {
+			b.Table(ts[i])
+		})
+	}
+	if added, dropped := publicationTables(from, to); len(added) > 0 || len(dropped) > 0 {
+		if len(dropped) > 0 {
+			s.append(&migrate.Change{
+				Source:  c,
+				Cmd:     alter("DROP", dropped).String(),
+				Reverse: alter("ADD", dropped).String(),
+				Comment: fmt.Sprintf("drop tables from publication %q", to.Name),
+			})
+		}
+		if len(added) > 0 {
+			s.append(&migrate.Change{
+				Source:  c,
+				Cmd:     alter("ADD", added).String(),
+				Reverse: alter("DROP", added).String(),
+				Comment: fmt.Sprintf("add tables to publication %q", to.Name),
+			})
+		}
+	}
+	if ops1, ops2 := publishOps(from), publishOps(to); !sqlx.ValuesEqual(ops1, ops2) {
+		set := func(ops []string) string {
+			return s.Build("ALTER PUBLICATION").Ident(to.Name).P("SET").Wrap(func(b *sqlx.Builder) {
+				b.P("publish =", quote(strings.Join(ops, ", ")))
+			}).String()
+		}
+		s.append(&migrate.Change{
+			Source:  c,
+			Cmd:     set(ops2),
+			Reverse: set(ops1),
+			Comment: fmt.Sprintf("modify published operations of publication %q", to.Name),
+		})
+	}
+}
+
+// convertPublications converts the publication specs to realm objects.
+func convertPublications(specs []*PublicationSpec, r *schema.Realm) error {
+	for _, spec := range specs {
+		if spec.AllTables && len(spec.Tables) > 0 {
+			return fmt.Errorf("publication %q: all_tables and tables are mutually exclusive", spec.Name)
+		}
+		p := &Publication{Name: spec.Name, AllTables: spec.AllTables, Publish: spec.Publish}
+		for _, ref := range spec.Tables {
+			t, err := specutil.TableByRef(r, ref)
+			if err != nil {
+				return fmt.Errorf("publication %q: %w", spec.Name, err)
+			}
+			p.Tables = append(p.Tables, t)
+		}
+		r.AddObjects(p)
+	}
+	return nil
+}
+
+// publicationSpecs converts the realm publications to their specs.
+// Table references are qualified if their specs are qualified.
+func publicationSpecs(r *schema.Realm, tables []*sqlspec.Table) []*PublicationSpec {
+	var specs []*PublicationSpec
+	for _, o := range r.Objects {
+		p, ok := o.(*Publication)
+		if !ok {
+			continue
+		}
+		spec := &PublicationSpec{Name: p.Name, AllTables: p.AllTables, Publish: p.Publish}
+		for _, t := range p.Tables {
+			var qualifier string
+			for _, ts := range tables {
+				if ns, err := specutil.SchemaName(ts.Schema); err == nil && ts.Name == t.Name && ns == tableSchema(t) {
+					qualifier = ts.Qualifier
+					break
+				}
+			}
+			spec.Tables = append(spec.Tables, specutil.TableRef(t.Name, qualifier))
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+const (
+	// Query to list publications.
+	publicationsQuery = `
+SELECT
+	p.pubname AS publication_name,
+	p.puballtables AS all_tables,
+	p.pubinsert AS publish_insert,
+	p.pubupdate AS publish_update,
+	p.pubdelete AS publish_delete,
+	%s AS publish_truncate
+FROM
+	pg_catalog.pg_publication AS p
+ORDER BY
+	p.pubname
+`
+
+	// Query to list the tables of publications.
+	publicationTablesQuery = `
+SELECT
+	pt.pubname AS publication_name,
+	pt.schemaname AS schema_name,
+	pt.tablename AS table_name
+FROM
+	pg_catalog.pg_publication_tables AS pt
+WHERE
+	pt.schemaname IN (%s)
+ORDER BY
+	pt.pubname, pt.schemaname, pt.tablename
+`
+)
@@ -0,0 +1,892 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+// RedshiftDriverName holds the name used for registering the Amazon Redshift driver.
+const RedshiftDriverName = "redshift"
+
+type (
+	rsDiff    struct{ diff }
+	rsInspect struct{ inspect }
+
+	// redshiftParser implements the sqlclient.URLParser interface for Redshift URLs.
+	redshiftParser struct{ parser }
+
+	// DistStyle describes the distribution style of a Redshift table.
+	// https://docs.aws.amazon.com/redshift/latest/dg/c_choosing_dist_sort.html
+	DistStyle struct {
+		schema.Attr
+		S string // AUTO, EVEN, KEY or ALL.
+		// Key is the distribution key column in case the style is KEY.
+		Key *schema.Column
+	}
+
+	// SortKey describes the sort key of a Redshift table.
+	// https://docs.aws.amazon.com/redshift/latest/dg/t_Sorting_data.html
+	SortKey struct {
+		schema.Attr
+		T       string // COMPOUND or INTERLEAVED.
+		Columns []*schema.Column
+	}
+
+	// Encode describes the compression encoding of a Redshift column.
+	// https://docs.aws.amazon.com/redshift/latest/dg/c_Compression_encodings.html
+	Encode struct {
+		schema.Attr
+		T string // e.g. AZ64, LZO, ZSTD or RAW.
+	}
+)
+
+// Redshift distribution styles and sort key types.
+const (
+	DistStyleAuto = "AUTO"
+	DistStyleEven = "EVEN"
+	DistStyleKey  = "KEY"
+	DistStyleAll  = "ALL"
+
+	SortKeyCompound    = "COMPOUND"
+	SortKeyInterleaved = "INTERLEAVED"
+)
+
+// encodings lists the column compression encodings supported by Redshift.
+var encodings = []string{
+	"AUTO", "RAW", "AZ64", "BYTEDICT", "DELTA", "DELTA32K", "LZO",
+	"MOSTLY8", "MOSTLY16", "MOSTLY32", "RUNLENGTH", "TEXT255", "TEXT32K", "ZSTD",
+}
+
+var _ sqlx.DiffDriver = (*rsDiff)(nil)
+
+// ParseURL implements the sqlclient.URLParser interface. The DSN
+// is rewritten to use the PostgreSQL scheme expected by the driver.
+func (p redshiftParser) ParseURL(u *url.URL) *sqlclient.URL {
+	ur := p.parser.ParseURL(u)
+	nu := *u
+	nu.Scheme = DriverName
+	ur.DSN = nu.String()
+	return ur
+}
+
+func redshiftOpener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+	ur := redshiftParser{}.ParseURL(u)
+	db, err := sql.Open(DriverName, ur.DSN)
+	if err != nil {
+		return nil, err
+	}
+	drv, err := OpenRedshift(db)
+	if err != nil {
+		if cerr := db.Close(); cerr != nil {
+			err = fmt.Errorf("%w: %v", err, cerr)
+		}
+		return nil, err
+	}
+	drv.(noLockDriver).noLocker.(*Driver).schema = ur.Schema
+	return &sqlclient.Client{
+		Name:   RedshiftDriverName,
+		DB:     db,
+		URL:    ur,
+		Driver: drv,
+	}, nil
+}
+
+// OpenRedshift opens a new Amazon Redshift driver. Redshift is handled as a PostgreSQL
+// flavor, but with its own inspection queries, table attributes and limited ALTER support.
+func OpenRedshift(db schema.ExecQuerier) (migrate.Driver, error) {
+	rows, err := db.QueryContext(context.Background(), "SELECT version()")
+	if err != nil {
+		return nil, fmt.Errorf("postgres: querying redshift version: %w", err)
+	}
+	var version string
+	if err := sqlx.ScanOne(rows, &version); err != nil {
+		return nil, fmt.Errorf("postgres: scanning redshift version: %w", err)
+	}
+	if !strings.Contains(version, "Redshift") {
+		return nil, fmt.Errorf("postgres: unexpected redshift version: %q", version)
+	}
+	c := &conn{ExecQuerier: db, redshift: true}
+	// Redshift does not support advisory locks.
+	return noLockDriver{
+		&Driver{
+			conn:        c,
+			Differ:      &sqlx.Diff{DiffDriver: &rsDiff{diff{c}}},
+			Inspector:   &rsInspect{inspect{c}},
+			PlanApplier: &planApply{c},
+		},
+	}, nil
+}
+
+// InspectRealm returns schema descriptions of all resources in the given realm.
+func (i *rsInspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	schemas, err := i.schemas(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &schema.InspectRealmOption{}
+	}
+	r := schema.NewRealm(schemas...)
+	if len(schemas) > 0 && sqlx.ModeInspectRealm(opts).Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, nil); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.FilterRealm(r, opts)
+}
+
+// InspectSchema returns schema descriptions of the tables in the given schema.
+// If the schema name is empty, the result will be the attached schema.
+func (i *rsInspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	schemas, err := i.schemas(ctx, &schema.InspectRealmOption{Schemas: []string{name}})
+	if err != nil {
+		return nil, err
+	}
+	switch n := len(schemas); {
+	case n == 0:
+		return nil, &schema.NotExistError{Err: fmt.Errorf("postgres: schema %q was not found", name)}
+	case n > 1:
+		return nil, fmt.Errorf("postgres: %d schemas were found for %q", n, name)
+	}
+	if opts == nil {
+		opts = &schema.InspectOptions{}
+	}
+	r := schema.NewRealm(schemas...)
+	if sqlx.ModeInspectSchema(opts).Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, opts); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.FilterSchema(r.Schemas[0], opts)
+}
+
+// schemas returns the user schemas, excluding the internal ones of Redshift.
+func (i *rsInspect) schemas(ctx context.Context, opts *schema.InspectRealmOption) ([]*schema.Schema, error) {
+	schemas, err := i.inspect.schemas(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	filtered := schemas[:0]
+	for _, s := range schemas {
+		if !strings.HasPrefix(s.Name, "pg_") && s.Name != "catalog_history" {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered, nil
+}
+
+func (i *rsInspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
+	if err := i.tables(ctx, r, opts); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		if len(s.Tables) == 0 {
+			continue
+		}
+		if err := i.columns(ctx, s); err != nil {
+			return err
+		}
+		if err := i.constraints(ctx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tables queries and appends the tables of the realm, including their distribution style.
+func (i *rsInspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.InspectOptions) error {
+	var (
+		args  []any
+		query = fmt.Sprintf(rsTablesQuery, nArgs(0, len(realm.Schemas)))
+	)
+	for _, s := range realm.Schemas {
+		args = append(args, s.Name)
+	}
+	if opts != nil && len(opts.Tables) > 0 {
+		for _, t := range opts.Tables {
+			args = append(args, t)
+		}
+		query = fmt.Sprintf(rsTablesQueryArgs, nArgs(0, len(realm.Schemas)), nArgs(len(realm.Schemas), len(opts.Tables)))
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying redshift tables: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			tSchema, name string
+			comment       sql.NullString
+			style         sql.NullInt64
+		)
+		if err := rows.Scan(&tSchema, &name, &comment, &style); err != nil {
+			return fmt.Errorf("scan table information: %w", err)
+		}
+		s, ok := realm.Schema(tSchema)
+		if !ok {
+			return fmt.Errorf("schema %q was not found in realm", tSchema)
+		}
+		t := schema.NewTable(name)
+		s.AddTables(t)
+		if sqlx.ValidString(comment) {
+			t.SetComment(comment.String)
+		}
+		// See: https://docs.aws.amazon.com/redshift/latest/dg/r_PG_CLASS_INFO.html
+		switch style.Int64 {
+		case 0:
+			t.AddAttrs(&DistStyle{S: DistStyleEven})
+		case 1:
+			t.AddAttrs(&DistStyle{S: DistStyleKey})
+		case 8:
+			t.AddAttrs(&DistStyle{S: DistStyleAll})
+		default:
+			t.AddAttrs(&DistStyle{S: DistStyleAuto})
+		}
+	}
+	return rows.Close()
+}
+
+// reIdentity matches the default expression of identity columns in Redshift.
+// e.g. "identity"(100194, 0, '1,1'::text).
+var reIdentity = regexp.MustCompile(`^"identity"\(\d+, *\d+, *'(-?\d+),(-?\d+)'(?:::text)?\)$`)
+
+// columns queries and appends the columns of the given schema tables.
+func (i *rsInspect) columns(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.querySchema(ctx, rsColumnsQuery, s)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	var sortKeys = make(map[*schema.Table][]struct {
+		ord int64
+		c   *schema.Column
+	})
+	for rows.Next() {
+		var (
+			tName, name, typ          string
+			notnull, distkey          bool
+			sortord                   int64
+			defaults, comment, encode sql.NullString
+		)
+		if err := rows.Scan(&tName, &name, &typ, &notnull, &defaults, &comment, &encode, &distkey, &sortord); err != nil {
+			return fmt.Errorf("postgres: scanning redshift column: %w", err)
+		}
+		t, ok := s.Table(tName)
+		if !ok {
+			return fmt.Errorf("table %q was not found in schema", tName)
+		}
+		ct, err := ParseType(typ)
+		if err != nil {
+			return err
+		}
+		c := &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Raw: typ, Type: ct, Null: !notnull},
+		}
+		switch m := reIdentity.FindStringSubmatch(defaults.String); {
+		case len(m) == 3:
+			start, _ := strconv.ParseInt(m[1], 10, 64)
+			incr, _ := strconv.ParseInt(m[2], 10, 64)
+			c.Attrs = append(c.Attrs, &Identity{
+				Generation: "ALWAYS",
+				Sequence:   &Sequence{Start: start, Increment: incr},
+			})
+		case sqlx.ValidString(defaults):
+			defaultExpr(c, defaults.String)
+		}
+		if sqlx.ValidString(comment) {
+			c.SetComment(comment.String)
+		}
+		if sqlx.ValidString(encode) {
+			c.Attrs = append(c.Attrs, &Encode{T: encodeType(encode.String)})
+		}
+		t.AddColumns(c)
+		if distkey {
+			for _, a := range t.Attrs {
+				if d, ok := a.(*DistStyle); ok {
+					d.Key = c
+				}
+			}
+		}
+		if sortord != 0 {
+			sortKeys[t] = append(sortKeys[t], struct {
+				ord int64
+				c   *schema.Column
+			}{ord: sortord, c: c})
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for t, keys := range sortKeys {
+		// Interleaved sort keys are stored with negative ordinals.
+		k := &SortKey{T: SortKeyCompound, Columns: make([]*schema.Column, len(keys))}
+		for _, p := range keys {
+			ord := p.ord
+			if ord < 0 {
+				ord, k.T = -ord, SortKeyInterleaved
+			}
+			if ord > int64(len(keys)) {
+				return fmt.Errorf("postgres: unexpected sort key ordinal %d for table %q", p.ord, t.Name)
+			}
+			k.Columns[ord-1] = p.c
+		}
+		t.AddAttrs(k)
+	}
+	return nil
+}
+
+// reConstraintCols extracts the column lists from a constraint definition.
+var reConstraintCols = regexp.MustCompile(`\(([^)]+)\)`)
+
+// constraints queries and appends the primary-key, unique and foreign-key
+// constraints of the given schema tables. Note, these constraints are
+// informational only in Redshift and are not enforced by the database.
+func (i *rsInspect) constraints(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.querySchema(ctx, rsConstraintsQuery, s)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q constraints: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			tName, name, typ, def string
+			refSchema, refTable   sql.NullString
+		)
+		if err := rows.Scan(&tName, &name, &typ, &def, &refSchema, &refTable); err != nil {
+			return fmt.Errorf("postgres: scanning redshift constraint: %w", err)
+		}
+		t, ok := s.Table(tName)
+		if !ok {
+			return fmt.Errorf("table %q was not found in schema", tName)
+		}
+		lists := reConstraintCols.FindAllStringSubmatch(def, 2)
+		if len(lists) == 0 {
+			return fmt.Errorf("postgres: unexpected constraint definition %q for table %q", def, tName)
+		}
+		columns, err := columnsOf(t, lists[0][1])
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case "p", "u":
+			idx := &schema.Index{Name: name, Unique: true, Table: t}
+			for i, c := range columns {
+				idx.Parts = append(idx.Parts, &schema.IndexPart{SeqNo: i + 1, C: c})
+				c.Indexes = append(c.Indexes, idx)
+			}
+			if typ == "p" {
+				t.PrimaryKey = idx
+			} else {
+				idx.AddAttrs(&Constraint{T: typ})
+				t.Indexes = append(t.Indexes, idx)
+			}
+		case "f":
+			if len(lists) != 2 {
+				return fmt.Errorf("postgres: unexpected foreign key definition %q for table %q", def, tName)
+			}
+			fk := &schema.ForeignKey{Symbol: name, Table: t, Columns: columns, OnUpdate: schema.NoAction, OnDelete: schema.NoAction}
+			// Referenced tables and columns are linked by sqlx.LinkSchemaTables.
+			fk.RefTable = &schema.Table{Name: refTable.String, Schema: schema.New(refSchema.String)}
+			for _, n := range strings.Split(lists[1][1], ",") {
+				fk.RefColumns = append(fk.RefColumns, &schema.Column{Name: strings.Trim(strings.TrimSpace(n), `"`)})
+			}
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+			for _, c := range columns {
+				c.ForeignKeys = append(c.ForeignKeys, fk)
+			}
+		}
+	}
+	return rows.Close()
+}
+
+// columnsOf returns the table columns of the given comma-separated list.
+func columnsOf(t *schema.Table, list string) ([]*schema.Column, error) {
+	var columns []*schema.Column
+	for _, n := range strings.Split(list, ",") {
+		n = strings.Trim(strings.TrimSpace(n), `"`)
+		c, ok := t.Column(n)
+		if !ok {
+			return nil, fmt.Errorf("postgres: column %q was not found in table %q", n, t.Name)
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+// encodeType returns the normalized (uppercase) form of the column encoding.
+func encodeType(t string) string {
+	switch t = strings.ToUpper(t); t {
+	case "", "NONE":
+		return "RAW"
+	default:
+		return t
+	}
+}
+
+// distStyle returns the distribution style of the table. Tables without
+// a style are distributed by KEY if a key is defined, or AUTO otherwise.
+func distStyle(t *schema.Table) *DistStyle {
+	d := &DistStyle{}
+	if !sqlx.Has(t.Attrs, d) || d.S == "" {
+		d.S = DistStyleAuto
+		if d.Key != nil {
+			d.S = DistStyleKey
+		}
+	}
+	d.S = strings.ToUpper(d.S)
+	return d
+}
+
+// sortKey returns the sort key of the table, or an empty key if none was defined.
+func sortKey(t *schema.Table) *SortKey {
+	k := &SortKey{}
+	if sqlx.Has(t.Attrs, k) && len(k.Columns) > 0 && k.T == "" {
+		k.T = SortKeyCompound
+	}
+	k.T = strings.ToUpper(k.T)
+	return k
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (d *rsDiff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
+	changes, err := d.diff.TableAttrDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if d1, d2 := distStyle(from), distStyle(to); d1.S != d2.S || columnName(d1.Key) != columnName(d2.Key) {
+		changes = append(changes, &schema.ModifyAttr{From: d1, To: d2})
+	}
+	if k1, k2 := sortKey(from), sortKey(to); k1.T != k2.T || !sameColumns(k1.Columns, k2.Columns) {
+		changes = append(changes, &schema.ModifyAttr{From: k1, To: k2})
+	}
+	return changes, nil
+}
+
+// ColumnChange returns the schema changes (if any) for migrating one column to the other.
+func (d *rsDiff) ColumnChange(t *schema.Table, from, to *schema.Column) (schema.ChangeKind, error) {
+	change, err := d.diff.ColumnChange(t, from, to)
+	if err != nil {
+		return schema.NoChange, err
+	}
+	// Encodings that were not set explicitly are chosen by Redshift.
+	if e2 := (&Encode{}); sqlx.Has(to.Attrs, e2) {
+		if e1 := (&Encode{}); !sqlx.Has(from.Attrs, e1) || encodeType(e1.T) != encodeType(e2.T) {
+			change |= schema.ChangeAttr
+		}
+	}
+	return change, nil
+}
+
+func columnName(c *schema.Column) string {
+	if c == nil {
+		return ""
+	}
+	return c.Name
+}
+
+func sameColumns(c1, c2 []*schema.Column) bool {
+	if len(c1) != len(c2) {
+		return false
+	}
+	for i := range c1 {
+		if c1[i].Name != c2[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// rsTableAttrs writes the table attributes of Redshift tables.
+func (s *state) rsTableAttrs(b *sqlx.Builder, t *schema.Table) {
+	if d := (&DistStyle{}); sqlx.Has(t.Attrs, d) {
+		if d.S != "" {
+			b.P("DISTSTYLE", strings.ToUpper(d.S))
+		}
+		if d.Key != nil {
+			b.P("DISTKEY").Wrap(func(b *sqlx.Builder) {
+				b.Ident(d.Key.Name)
+			})
+		}
+	}
+	if k := sortKey(t); len(k.Columns) > 0 {
+		if k.T == SortKeyInterleaved {
+			b.P(k.T)
+		}
+		b.P("SORTKEY").Wrap(func(b *sqlx.Builder) {
+			b.MapComma(k.Columns, func(i int, b *sqlx.Builder) {
+				b.Ident(k.Columns[i].Name)
+			})
+		})
+	}
+}
+
+// rsColumn writes the column definition of Redshift tables.
+func (s *state) rsColumn(b *sqlx.Builder, c *schema.Column) error {
+	f, err := s.formatType(c)
+	if err != nil {
+		return err
+	}
+	b.Ident(c.Name).P(f)
+	s.columnDefault(b, c)
+	for _, attr := range c.Attrs {
+		switch a := attr.(type) {
+		case *schema.Comment:
+		case *schema.Collation:
+			b.P("COLLATE", a.V)
+		case *Identity:
+			if strings.ToUpper(a.Generation) == "BY DEFAULT" {
+				b.P("GENERATED BY DEFAULT AS")
+			}
+			start, incr := int64(defaultSeqStart), int64(defaultSeqIncrement)
+			if a.Sequence != nil {
+				start, incr = a.Sequence.Start, a.Sequence.Increment
+			}
+			b.P(fmt.Sprintf("IDENTITY(%d, %d)", start, incr))
+		case *Encode:
+			b.P("ENCODE", encodeType(a.T))
+		default:
+			return fmt.Errorf("unexpected redshift column attribute: %T", attr)
+		}
+	}
+	if !c.Type.Null {
+		b.P("NOT")
+	}
+	b.P("NULL")
+	return nil
+}
+
+// rsModifyTable builds the statements that bring the Redshift table into its modified
+// state. Redshift supports only one action per ALTER TABLE statement, and a limited set
+// of column alterations.
+func (s *state) rsModifyTable(modify *schema.ModifyTable) error {
+	var (
+		t       = modify.T
+		changes []*migrate.Change
+	)
+	for _, change := range skipAutoChanges(modify.Changes) {
+		switch change := change.(type) {
+		case *schema.AddAttr, *schema.ModifyAttr:
+			if from, to, ok := privilegeChange(change); ok {
+				changes = append(changes, s.privileges(modify, "TABLE", s.Build().Table(t).String(), from, to, tablePrivileges)...)
+				continue
+			}
+			if m, ok := change.(*schema.ModifyAttr); ok {
+				switch from := m.From.(type) {
+				case *DistStyle:
+					changes = append(changes, s.alterDistStyle(t, m, from, m.To.(*DistStyle)))
+					continue
+				case *SortKey:
+					c, err := s.alterSortKey(t, m, from, m.To.(*SortKey))
+					if err != nil {
+						return err
+					}
+					changes = append(changes, c)
+					continue
+				case *TableStorageParams:
+					return fmt.Errorf("postgres: storage parameters are not supported by Redshift (table %q)", t.Name)
+				}
+			}
+			from, to, err := commentChange(change)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, s.tableComment(t, to, from))
+		case *schema.AddColumn:
+			if err := s.rsAlterTable(t, change); err != nil {
+				return err
+			}
+			if c := (schema.Comment{}); sqlx.Has(change.C.Attrs, &c) {
+				changes = append(changes, s.columnComment(t, change.C, c.Text, ""))
+			}
+		case *schema.ModifyColumn:
+			k := change.Change
+			if k.Is(schema.ChangeComment) {
+				from, to, err := commentChange(sqlx.CommentDiff(change.From.Attrs, change.To.Attrs))
+				if err != nil {
+					return err
+				}
+				changes = append(changes, s.columnComment(t, change.To, to, from))
+				k &= ^schema.ChangeComment
+			}
+			if k.Is(schema.ChangeAttr) {
+				e1, e2 := &Encode{}, &Encode{}
+				sqlx.Has(change.From.Attrs, e1)
+				sqlx.Has(change.To.Attrs, e2)
+				b := s.Build("ALTER TABLE").Table(t).P("ALTER COLUMN").Ident(change.To.Name).P("ENCODE")
+				c := &migrate.Change{
+					Source:  change,
+					Cmd:     b.Clone().P(encodeType(e2.T)).String(),
+					Comment: fmt.Sprintf("modify the encoding of column %q", change.To.Name),
+				}
+				if e1.T != "" {
+					c.Reverse = b.P(encodeType(e1.T)).String()
+				}
+				changes = append(changes, c)
+				k &= ^schema.ChangeAttr
+			}
+			switch {
+			case k.Is(schema.NoChange):
+			case k == schema.ChangeType:
+				// Redshift supports only increasing the size of VARCHAR columns, and
+				// rejects other type changes. Leave the validation to the database.
+				if err := s.rsAlterTable(t, &schema.ModifyColumn{From: change.From, To: change.To, Change: k}); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("postgres: unsupported change of column %q in Redshift (only type, encoding and comment changes are supported)", change.To.Name)
+			}
+		case *schema.ModifyPrimaryKey:
+			if err := s.rsAlterTable(t, &schema.DropPrimaryKey{P: change.From}, &schema.AddPrimaryKey{P: change.To}); err != nil {
+				return err
+			}
+		case *schema.ModifyForeignKey:
+			if err := s.rsAlterTable(t, &schema.DropForeignKey{F: change.From}, &schema.AddForeignKey{F: change.To}); err != nil {
+				return err
+			}
+		case *schema.RenameColumn:
+			b := s.Build("ALTER TABLE").Table(t).P("RENAME COLUMN")
+			r := b.Clone()
+			changes = append(changes, &migrate.Change{
+				Source:  change,
+				Comment: fmt.Sprintf("rename a column from %q to %q", change.From.Name, change.To.Name),
+				Cmd:     b.Ident(change.From.Name).P("TO").Ident(change.To.Name).String(),
+				Reverse: r.Ident(change.To.Name).P("TO").Ident(change.From.Name).String(),
+			})
+		case *schema.DropColumn, *schema.AddPrimaryKey, *schema.DropPrimaryKey, *schema.AddForeignKey, *schema.DropForeignKey:
+			if err := s.rsAlterTable(t, change); err != nil {
+				return err
+			}
+		case *schema.AddIndex, *schema.DropIndex, *schema.ModifyIndex, *schema.RenameIndex:
+			return fmt.Errorf("postgres: indexes are not supported by Redshift (table %q)", t.Name)
+		case *schema.AddCheck, *schema.DropCheck, *schema.ModifyCheck:
+			return fmt.Errorf("postgres: check constraints are not supported by Redshift (table %q)", t.Name)
+		default:
+			return fmt.Errorf("postgres: unsupported change type for Redshift: %T", change)
+		}
+	}
+	s.append(changes...)
+	return nil
+}
+
+// rsAlterTable executes each change in a separate ALTER TABLE statement.
+func (s *state) rsAlterTable(t *schema.Table, changes ...schema.Change) error {
+	for _, c := range changes {
+		if err := s.alterTable(t, []schema.Change{c}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterDistStyle returns the change for altering the distribution style of a table.
+func (s *state) alterDistStyle(t *schema.Table, change schema.Change, from, to *DistStyle) *migrate.Change {
+	alter := func(d *DistStyle) string {
+		b := s.Build("ALTER TABLE").Table(t).P("ALTER")
+		if d.S == DistStyleKey && d.Key != nil {
+			return b.P("DISTSTYLE KEY DISTKEY").Ident(d.Key.Name).String()
+		}
+		return b.P("DISTSTYLE", d.S).String()
+	}
+	return &migrate.Change{
+		Source:  change,
+		Cmd:     alter(to),
+		Reverse: alter(from),
+		Comment: fmt.Sprintf("modify the distribution style of %q table", t.Name),
+	}
+}
+
+// alterSortKey returns the change for altering the sort key of a table.
+func (s *state) alterSortKey(t *schema.Table, change schema.Change, from, to *SortKey) (*migrate.Change, error) {
+	if from.T == SortKeyInterleaved || to.T == SortKeyInterleaved {
+		return nil, fmt.Errorf("postgres: interleaved sort key of table %q cannot be altered in Redshift", t.Name)
+	}
+	alter := func(k *SortKey) string {
+		b := s.Build("ALTER TABLE").Table(t).P("ALTER SORTKEY")
+		if len(k.Columns) == 0 {
+			return b.P("NONE").String()
+		}
+		return b.Wrap(func(b *sqlx.Builder) {
+			b.MapComma(k.Columns, func(i int, b *sqlx.Builder) {
+				b.Ident(k.Columns[i].Name)
+			})
+		}).String()
+	}
+	return &migrate.Change{
+		Source:  change,
+		Cmd:     alter(to),
+		Reverse: alter(from),
+		Comment: fmt.Sprintf("modify the sort key of %q table", t.Name),
+	}, nil
+}
+
+// convertRedshiftTable converts and appends the Redshift attributes of the table.
+func convertRedshiftTable(spec *sqlspec.Table, t *schema.Table) error {
+	var attrs struct {
+		DistStyle string         `spec:"dist_style"`
+		DistKey   *schemahcl.Ref `spec:"dist_key"`
+	}
+	if err := spec.Extra.As(&attrs); err != nil {
+		return fmt.Errorf("parsing %s distribution attributes: %w", t.Name, err)
+	}
+	if attrs.DistStyle != "" || attrs.DistKey != nil {
+		d := &DistStyle{S: strings.ToUpper(attrs.DistStyle)}
+		if attrs.DistKey != nil {
+			c, err := specutil.ColumnByRef(t, attrs.DistKey)
+			if err != nil {
+				return err
+			}
+			d.Key = c
+		}
+		t.AddAttrs(d)
+	}
+	r, ok := spec.Extra.Resource("sort_key")
+	if !ok {
+		return nil
+	}
+	var key struct {
+		Type    string           `spec:"type"`
+		Columns []*schemahcl.Ref `spec:"columns"`
+	}
+	if err := r.As(&key); err != nil {
+		return fmt.Errorf("parsing %s.sort_key: %w", t.Name, err)
+	}
+	k := &SortKey{T: strings.ToUpper(key.Type)}
+	if k.T == "" {
+		k.T = SortKeyCompound
+	}
+	for _, ref := range key.Columns {
+		c, err := specutil.ColumnByRef(t, ref)
+		if err != nil {
+			return err
+		}
+		k.Columns = append(k.Columns, c)
+	}
+	t.AddAttrs(k)
+	return nil
+}
+
+// fromRedshiftTable appends the Redshift attributes of the table to its spec.
+func fromRedshiftTable(spec *sqlspec.Table, t *schema.Table) {
+	if d := (&DistStyle{}); sqlx.Has(t.Attrs, d) {
+		if d.S != "" {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("dist_style", strings.ToUpper(d.S)))
+		}
+		if d.Key != nil {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.RefAttr("dist_key", specutil.ColumnRef(d.Key.Name)))
+		}
+	}
+	if k := (&SortKey{}); sqlx.Has(t.Attrs, k) && len(k.Columns) > 0 {
+		r := &schemahcl.Resource{Type: "sort_key"}
+		if k.T != "" && strings.ToUpper(k.T) != SortKeyCompound {
+			r.Attrs = append(r.Attrs, specutil.VarAttr("type", strings.ToUpper(k.T)))
+		}
+		columns := make([]*schemahcl.Ref, 0, len(k.Columns))
+		for _, c := range k.Columns {
+			columns = append(columns, specutil.ColumnRef(c.Name))
+		}
+		r.Attrs = append(r.Attrs, schemahcl.RefsAttr("columns", columns...))
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
+}
+
+const (
+	// Query to list Redshift tables and their distribution style.
+	rsTablesQuery = `
+SELECT
+	n.nspname AS table_schema,
+	c.relname AS table_name,
+	pg_catalog.obj_description(c.oid, 'pg_class') AS comment,
+	c.reldiststyle AS dist_style
+FROM
+	pg_catalog.pg_class AS c
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+WHERE
+	c.relkind = 'r'
+	AND n.nspname IN (%s)
+ORDER BY
+	n.nspname, c.relname
+`
+	rsTablesQueryArgs = `
+SELECT
+	n.nspname AS table_schema,
+	c.relname AS table_name,
+	pg_catalog.obj_description(c.oid, 'pg_class') AS comment,
+	c.reldiststyle AS dist_style
+FROM
+	pg_catalog.pg_class AS c
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+WHERE
+	c.relkind = 'r'
+	AND n.nspname IN (%s)
+	AND c.relname IN (%s)
+ORDER BY
+	n.nspname, c.relname
+`
+	// Query to list Redshift table columns, including their encoding and key attributes.
+	rsColumnsQuery = `
+SELECT
+	c.relname AS table_name,
+	a.attname AS column_name,
+	pg_catalog.format_type(a.atttypid, a.atttypmod) AS data_type,
+	a.attnotnull AS not_null,
+	d.adsrc AS column_default,
+	pg_catalog.col_description(a.attrelid, a.attnum) AS comment,
+	pg_catalog.format_encoding(a.attencodingtype::integer) AS encoding,
+	a.attisdistkey AS dist_key,
+	a.attsortkeyord AS sort_key_ord
+FROM
+	pg_catalog.pg_attribute AS a
+	JOIN pg_catalog.pg_class AS c ON c.oid = a.attrelid
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+	LEFT JOIN pg_catalog.pg_attrdef AS d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE
+	n.nspname = $1
+	AND c.relname IN (%s)
+	AND a.attnum > 0
+	AND NOT a.attisdropped
+ORDER BY
+	c.relname, a.attnum
+`
+	// Query to list Redshift table constraints.
+	rsConstraintsQuery = `
+SELECT
+	c.relname AS table_name,
+	con.conname AS constraint_name,
+	con.contype::text AS constraint_type,
+	pg_catalog.pg_get_constraintdef(con.oid) AS definition,
+	rn.nspname AS referenced_schema,
+	rc.relname AS referenced_table
+FROM
+	pg_catalog.pg_constraint AS con
+	JOIN pg_catalog.pg_class AS c ON c.oid = con.conrelid
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+	LEFT JOIN pg_catalog.pg_class AS rc ON rc.oid = con.confrelid
+	LEFT JOIN pg_catalog.pg_namespace AS rn ON rn.oid = rc.relnamespace
+WHERE
+	n.nspname = $1
+	AND c.relname IN (%s)
+	AND con.contype IN ('p', 'u', 'f')
+ORDER BY
+	c.relname, con.conname
+`
+)
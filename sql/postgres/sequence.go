@@ -0,0 +1,187 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// addSequence creates a sequence without its owner. The OWNED BY clause is
+// set by ownSequence after the owner table was created, as tables may use
+// the sequence in their default values.
+func (s *state) addSequence(add *schema.AddSequence) error {
+	b := s.Build("CREATE SEQUENCE")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	b.SchemaResource(add.S.Schema, add.S.Name)
+	if err := seqOptions(b, &schema.Sequence{}, add.S, false); err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     b.String(),
+		Reverse: s.Build("DROP SEQUENCE").SchemaResource(add.S.Schema, add.S.Name).String(),
+		Comment: fmt.Sprintf("create sequence %q", add.S.Name),
+	})
+	return nil
+}
+
+// modifySequence alters the options of a sequence. A change to the owner
+// of the sequence is planned by ownSequence.
+func (s *state) modifySequence(modify *schema.ModifySequence) error {
+	from, to := modify.From, modify.To
+	cmd := s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name)
+	if err := seqOptions(cmd, from, to, true); err != nil {
+		return err
+	}
+	reverse := s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name)
+	if err := seqOptions(reverse, to, from, true); err != nil {
+		return err
+	}
+	// Only the owner was changed.
+	if cmd.String() == s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name).String() {
+		return nil
+	}
+	s.append(&migrate.Change{
+		Source:  modify,
+		Cmd:     cmd.String(),
+		Reverse: reverse.String(),
+		Comment: fmt.Sprintf("modify sequence %q", to.Name),
+	})
+	return nil
+}
+
+// ownSequence sets the owner column of an added or a modified sequence.
+func (s *state) ownSequence(c schema.Change) {
+	var from, to *schema.Sequence
+	switch c := c.(type) {
+	case *schema.AddSequence:
+		from, to = &schema.Sequence{}, c.S
+	case *schema.ModifySequence:
+		from, to = c.From, c.To
+	}
+	if ownedBy(s, from) == ownedBy(s, to) {
+		return
+	}
+	s.append(&migrate.Change{
+		Source:  c,
+		Cmd:     s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name).P("OWNED BY", ownedBy(s, to)).String(),
+		Reverse: s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name).P("OWNED BY", ownedBy(s, from)).String(),
+		Comment: fmt.Sprintf("set the owner of sequence %q", to.Name),
+	})
+}
+
+// dropSequence drops a sequence. Sequences are dropped after the tables
+// that use them in their default values.
+func (s *state) dropSequence(drop *schema.DropSequence) error {
+	b := s.Build("DROP SEQUENCE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	b.SchemaResource(drop.S.Schema, drop.S.Name)
+	// The sequence is recreated without its owner, as its
+	// owner column might not exist when the change is reverted.
+	reverse := s.Build("CREATE SEQUENCE").SchemaResource(drop.S.Schema, drop.S.Name)
+	if err := seqOptions(reverse, &schema.Sequence{}, drop.S, false); err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     b.String(),
+		Reverse: reverse.String(),
+		Comment: fmt.Sprintf("drop sequence %q", drop.S.Name),
+	})
+	return nil
+}
+
+// ownedBy returns the OWNED BY argument of the sequence.
+func ownedBy(s *state, seq *schema.Sequence) string {
+	if seq.Owner.T == nil || seq.Owner.C == nil {
+		return "NONE"
+	}
+	return s.Build().TableResource(seq.Owner.T, seq.Owner.C).String()
+}
+
+// seqOptions writes the options of the sequence "to" that differ from the
+// sequence "from". In ALTER commands, options that were reset to their zero
+// value are written with their database default.
+func seqOptions(b *sqlx.Builder, from, to *schema.Sequence, alter bool) error {
+	if to.Type != nil {
+		t, err := FormatType(to.Type)
+		if err != nil {
+			return fmt.Errorf("postgres: format sequence %q type: %w", to.Name, err)
+		}
+		if from.Type == nil {
+			b.P("AS", t)
+		} else if f, err := FormatType(from.Type); err != nil || f != t {
+			b.P("AS", t)
+		}
+	}
+	switch {
+	case from.Increment == to.Increment:
+	case to.Increment != 0:
+		b.P("INCREMENT BY", strconv.FormatInt(to.Increment, 10))
+	case alter:
+		b.P("INCREMENT BY 1")
+	}
+	switch {
+	case from.Min == to.Min:
+	case to.Min != 0:
+		b.P("MINVALUE", strconv.FormatInt(to.Min, 10))
+	case alter:
+		b.P("NO MINVALUE")
+	}
+	switch {
+	case from.Max == to.Max:
+	case to.Max != 0:
+		b.P("MAXVALUE", strconv.FormatInt(to.Max, 10))
+	case alter:
+		b.P("NO MAXVALUE")
+	}
+	switch {
+	case from.Start == to.Start:
+	case to.Start != 0:
+		b.P("START WITH", strconv.FormatInt(to.Start, 10))
+	case alter:
+		// The default start value is the minimum value for
+		// ascending sequences and the maximum for descending.
+		b.P("START WITH", strconv.FormatInt(seqDefaultStart(to), 10))
+	}
+	switch {
+	case from.Cache == to.Cache:
+	case to.Cache != 0:
+		b.P("CACHE", strconv.FormatInt(to.Cache, 10))
+	case alter:
+		b.P("CACHE 1")
+	}
+	switch {
+	case from.Cycle == to.Cycle:
+	case to.Cycle:
+		b.P("CYCLE")
+	case alter:
+		b.P("NO CYCLE")
+	}
+	return nil
+}
+
+// seqDefaultStart returns the default start value of the sequence.
+func seqDefaultStart(seq *schema.Sequence) int64 {
+	switch {
+	case seq.Increment >= 0 && seq.Min != 0:
+		return seq.Min
+	case seq.Increment >= 0:
+		return 1
+	case seq.Max != 0:
+		return seq.Max
+	default:
+		return -1
+	}
+}
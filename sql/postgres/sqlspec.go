@@ -25,8 +25,12 @@ type (
 	doc struct {
 		Tables  []*sqlspec.Table  `spec:"table"`
 		Views   []*sqlspec.View   `spec:"view"`
+		Funcs   []*sqlspec.Func   `spec:"function"`
+		Procs   []*sqlspec.Proc   `spec:"procedure"`
 		Enums   []*Enum           `spec:"enum"`
 		Schemas []*sqlspec.Schema `spec:"schema"`
+		// Publications are supported only on realm level.
+		Publications []*PublicationSpec `spec:"publication"`
 	}
 	// Enum holds a specification for an enum, that can be referenced as a column type.
 	Enum struct {
@@ -50,16 +54,22 @@ func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
 			return err
 		}
 		if err := specutil.Scan(v,
-			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views},
-			&specutil.ScanFuncs{Table: convertTable, View: convertView},
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views, Funcs: d.Funcs, Procs: d.Procs},
+			&specutil.ScanFuncs{Table: convertTable, View: convertView, Func: convertFunc, Proc: convertProc},
 		); err != nil {
 			return fmt.Errorf("specutil: failed converting to *schema.Realm: %w", err)
 		}
+		if err := convertSchemaPrivileges(d.Schemas, v); err != nil {
+			return err
+		}
 		if len(d.Enums) > 0 {
 			if err := convertEnums(d.Tables, d.Enums, v); err != nil {
 				return err
 			}
 		}
+		if err := convertPublications(d.Publications, v); err != nil {
+			return err
+		}
 	case *schema.Schema:
 		var d doc
 		if err := hclState.Eval(p, &d, input); err != nil {
@@ -70,11 +80,14 @@ func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
 		}
 		r := &schema.Realm{}
 		if err := specutil.Scan(r,
-			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views},
-			&specutil.ScanFuncs{Table: convertTable, View: convertView},
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views, Funcs: d.Funcs, Procs: d.Procs},
+			&specutil.ScanFuncs{Table: convertTable, View: convertView, Func: convertFunc, Proc: convertProc},
 		); err != nil {
 			return err
 		}
+		if err := convertSchemaPrivileges(d.Schemas, r); err != nil {
+			return err
+		}
 		if err := convertEnums(d.Tables, d.Enums, r); err != nil {
 			return err
 		}
@@ -99,6 +112,8 @@ func MarshalSpec(v any, marshaler schemahcl.Marshaler) ([]byte, error) {
 		}
 		d.Tables = doc.Tables
 		d.Views = doc.Views
+		d.Funcs = doc.Funcs
+		d.Procs = doc.Procs
 		d.Schemas = doc.Schemas
 		d.Enums = doc.Enums
 	case *schema.Realm:
@@ -109,6 +124,8 @@ func MarshalSpec(v any, marshaler schemahcl.Marshaler) ([]byte, error) {
 			}
 			d.Tables = append(d.Tables, doc.Tables...)
 			d.Views = append(d.Views, doc.Views...)
+			d.Funcs = append(d.Funcs, doc.Funcs...)
+			d.Procs = append(d.Procs, doc.Procs...)
 			d.Schemas = append(d.Schemas, doc.Schemas...)
 			d.Enums = append(d.Enums, doc.Enums...)
 		}
@@ -118,9 +135,13 @@ func MarshalSpec(v any, marshaler schemahcl.Marshaler) ([]byte, error) {
 		if err := specutil.QualifyViews(d.Views); err != nil {
 			return nil, err
 		}
+		if err := specutil.QualifyFuncs(d.Funcs, d.Procs); err != nil {
+			return nil, err
+		}
 		if err := specutil.QualifyReferences(d.Tables, s); err != nil {
 			return nil, err
 		}
+		d.Publications = publicationSpecs(s, d.Tables)
 	default:
 		return nil, fmt.Errorf("specutil: failed marshaling spec. %T is not supported", v)
 	}
@@ -128,14 +149,38 @@ func MarshalSpec(v any, marshaler schemahcl.Marshaler) ([]byte, error) {
 }
 
 var (
-	hclState = schemahcl.New(append(specOptions,
+	hclState = newState()
+	// MarshalHCL marshals v into an Atlas HCL DDL document.
+	MarshalHCL = schemahcl.MarshalerFunc(func(v any) ([]byte, error) {
+		return MarshalSpec(v, hclState)
+	})
+	// EvalHCL implements the schemahcl.Evaluator interface.
+	EvalHCL = schemahcl.EvalFunc(evalSpec)
+
+	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
+	// of from an hclparse.Parser instance.
+	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
+)
+
+// newState returns the HCL state of the driver, configured with the types of the TypeRegistry.
+func newState() *schemahcl.State {
+	return schemahcl.New(append(specOptions,
 		schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
 		schemahcl.WithTypes("view.column.type", TypeRegistry.Specs()),
+		schemahcl.WithTypes("function.arg.type", TypeRegistry.Specs()),
+		schemahcl.WithTypes("function.return", TypeRegistry.Specs()),
+		schemahcl.WithTypes("function.column.type", TypeRegistry.Specs()),
+		schemahcl.WithTypes("procedure.arg.type", TypeRegistry.Specs()),
+		schemahcl.WithScopedEnums("function.arg.mode", string(schema.FuncArgModeIn), string(schema.FuncArgModeOut), string(schema.FuncArgModeInOut), string(schema.FuncArgModeVariadic)),
+		schemahcl.WithScopedEnums("procedure.arg.mode", string(schema.FuncArgModeIn), string(schema.FuncArgModeOut), string(schema.FuncArgModeInOut), string(schema.FuncArgModeVariadic)),
 		schemahcl.WithScopedEnums("view.check_option", schema.ViewCheckOptionLocal, schema.ViewCheckOptionCascaded),
-		schemahcl.WithScopedEnums("table.index.type", IndexTypeBTree, IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, "GiST", IndexTypeSPGiST, "SPGiST"),
+		schemahcl.WithScopedEnums("table.index.type", IndexTypeBTree, IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, "GiST", IndexTypeSPGiST, "SPGiST", "btree", "brin", "hash", "gin", "gist", "spgist"),
 		schemahcl.WithScopedEnums("table.partition.type", PartitionTypeRange, PartitionTypeList, PartitionTypeHash),
 		schemahcl.WithScopedEnums("table.column.identity.generated", GeneratedTypeAlways, GeneratedTypeByDefault),
 		schemahcl.WithScopedEnums("table.column.as.type", "STORED"),
+		schemahcl.WithScopedEnums("table.column.encode", encodings...),
+		schemahcl.WithScopedEnums("table.dist_style", DistStyleAuto, DistStyleEven, DistStyleKey, DistStyleAll),
+		schemahcl.WithScopedEnums("table.sort_key.type", SortKeyCompound, SortKeyInterleaved),
 		schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
 		schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
 		schemahcl.WithScopedEnums("table.index.on.ops", func() (ops []string) {
@@ -145,17 +190,7 @@ var (
 			return ops
 		}()...))...,
 	)
-	// MarshalHCL marshals v into an Atlas HCL DDL document.
-	MarshalHCL = schemahcl.MarshalerFunc(func(v any) ([]byte, error) {
-		return MarshalSpec(v, hclState)
-	})
-	// EvalHCL implements the schemahcl.Evaluator interface.
-	EvalHCL = schemahcl.EvalFunc(evalSpec)
-
-	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
-	// of from an hclparse.Parser instance.
-	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
-)
+}
 
 // convertTable converts a sqlspec.Table to a schema.Table. Table conversion is done without converting
 // ForeignKeySpecs into ForeignKeys, as the target tables do not necessarily exist in the schema
@@ -168,6 +203,22 @@ func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, er
 	if err := convertPartition(spec.Extra, t); err != nil {
 		return nil, err
 	}
+	if err := convertRedshiftTable(spec, t); err != nil {
+		return nil, err
+	}
+	if err := convertYugabyteTable(spec, t); err != nil {
+		return nil, err
+	}
+	if err := convertPrivileges(spec.Extra, &t.Attrs); err != nil {
+		return nil, fmt.Errorf("table %q: %w", t.Name, err)
+	}
+	if r, ok := spec.Extra.Resource("storage_params"); ok {
+		params, err := convertStorageParams(r)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s.storage_params: %w", t.Name, err)
+		}
+		t.AddAttrs(&TableStorageParams{Params: params})
+	}
 	return t, nil
 }
 
@@ -238,6 +289,66 @@ func convertPartition(spec schemahcl.Resource, table *schema.Table) error {
 	return nil
 }
 
+// convertSchemaPrivileges converts and appends the privilege blocks of the schemas
+// into their attributes. Schema attributes are not handled by specutil.Scan.
+func convertSchemaPrivileges(specs []*sqlspec.Schema, r *schema.Realm) error {
+	for _, spec := range specs {
+		s, ok := r.Schema(spec.Name)
+		if !ok {
+			return fmt.Errorf("schema %q was not found in realm", spec.Name)
+		}
+		if err := convertPrivileges(spec.Extra, &s.Attrs); err != nil {
+			return fmt.Errorf("schema %q: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// convertPrivileges converts and appends the privilege blocks into the attributes.
+func convertPrivileges(spec schemahcl.Resource, attrs *[]schema.Attr) error {
+	for _, r := range spec.Children {
+		if r.Type != "privilege" {
+			continue
+		}
+		var p struct {
+			Grantee     string   `spec:"grantee"`
+			Privileges  []string `spec:"privileges"`
+			GrantOption bool     `spec:"grant_option"`
+		}
+		if err := r.As(&p); err != nil {
+			return fmt.Errorf("parsing privilege: %w", err)
+		}
+		if p.Grantee == "" {
+			return fmt.Errorf("missing attribute privilege.grantee")
+		}
+		*attrs = append(*attrs, &Privilege{Grantee: p.Grantee, Privileges: p.Privileges, GrantOption: p.GrantOption})
+	}
+	return nil
+}
+
+// fromPrivileges returns the resource specs for representing the privilege blocks.
+func fromPrivileges(attrs []schema.Attr) []*schemahcl.Resource {
+	var specs []*schemahcl.Resource
+	for _, a := range attrs {
+		p, ok := a.(*Privilege)
+		if !ok {
+			continue
+		}
+		r := &schemahcl.Resource{
+			Type: "privilege",
+			Attrs: []*schemahcl.Attr{
+				schemahcl.StringAttr("grantee", p.Grantee),
+				schemahcl.StringsAttr("privileges", p.Privileges...),
+			},
+		}
+		if p.GrantOption {
+			r.Attrs = append(r.Attrs, schemahcl.BoolAttr("grant_option", true))
+		}
+		specs = append(specs, r)
+	}
+	return specs
+}
+
 // fromPartition returns the resource spec for representing the partition block.
 func fromPartition(p Partition) *schemahcl.Resource {
 	key := &schemahcl.Resource{
@@ -292,6 +403,20 @@ func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error
 	if err := specutil.ConvertGenExpr(spec.Remain(), c, generatedType); err != nil {
 		return nil, err
 	}
+	if a, ok := spec.Attr("collate"); ok {
+		v, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("column %q: parsing collate: %w", c.Name, err)
+		}
+		c.SetCollation(v)
+	}
+	if a, ok := spec.Attr("encode"); ok {
+		v, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("column %q: parsing encode: %w", c.Name, err)
+		}
+		c.AddAttrs(&Encode{T: strings.ToUpper(v)})
+	}
 	return c, nil
 }
 
@@ -370,9 +495,31 @@ func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
 		}
 		idx.Attrs = append(idx.Attrs, &IndexNullsDistinct{V: v})
 	}
+	split, ok, err := convertTabletSplit(spec.Extra, idx.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		idx.Attrs = append(idx.Attrs, split)
+	}
 	if err := convertIndexPK(spec, t, idx); err != nil {
 		return nil, err
 	}
+	if r, ok := spec.Extra.Resource("storage_params"); ok {
+		params, err := convertStorageParams(r)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s.storage_params: %w", idx.Name, err)
+		}
+		p, err := indexStorage(params)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s.storage_params: %w", idx.Name, err)
+		}
+		// Merge with the page_per_range attribute, if it was set.
+		if prev := (&IndexStorageParams{}); sqlx.Has(idx.Attrs, prev) && p.PagesPerRange == 0 {
+			p.PagesPerRange = prev.PagesPerRange
+		}
+		idx.Attrs = append(schema.RemoveAttr[*IndexStorageParams](idx.Attrs), p)
+	}
 	return idx, nil
 }
 
@@ -543,12 +690,18 @@ func schemaSpec(s *schema.Schema) (*doc, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := specutil.FromFuncs(s, spec, columnTypeSpec); err != nil {
+		return nil, err
+	}
 	d := &doc{
 		Tables:  spec.Tables,
 		Views:   spec.Views,
+		Funcs:   spec.Funcs,
+		Procs:   spec.Procs,
 		Schemas: []*sqlspec.Schema{spec.Schema},
 		Enums:   make([]*Enum, 0, len(s.Objects)),
 	}
+	spec.Schema.Extra.Children = append(spec.Schema.Extra.Children, fromPrivileges(s.Attrs)...)
 	for _, o := range s.Objects {
 		if e, ok := o.(*schema.EnumType); ok {
 			d.Enums = append(d.Enums, &Enum{
@@ -561,6 +714,16 @@ func schemaSpec(s *schema.Schema) (*doc, error) {
 	return d, nil
 }
 
+// convertFunc converts a sqlspec.Func to a schema.Func.
+func convertFunc(spec *sqlspec.Func, parent *schema.Schema) (*schema.Func, error) {
+	return specutil.Func(spec, parent, convertColumnType)
+}
+
+// convertProc converts a sqlspec.Proc to a schema.Proc.
+func convertProc(spec *sqlspec.Proc, parent *schema.Schema) (*schema.Proc, error) {
+	return specutil.Proc(spec, parent, convertColumnType)
+}
+
 // tableSpec converts from a concrete Postgres sqlspec.Table to a schema.Table.
 func tableSpec(table *schema.Table) (*sqlspec.Table, error) {
 	spec, err := specutil.FromTable(
@@ -577,6 +740,12 @@ func tableSpec(table *schema.Table) (*sqlspec.Table, error) {
 	if p := (Partition{}); sqlx.Has(table.Attrs, &p) {
 		spec.Extra.Children = append(spec.Extra.Children, fromPartition(p))
 	}
+	if p := (TableStorageParams{}); sqlx.Has(table.Attrs, &p) && len(p.Params) > 0 {
+		spec.Extra.Children = append(spec.Extra.Children, fromStorageParams(p.Params))
+	}
+	fromRedshiftTable(spec, table)
+	fromYugabyteTable(spec, table)
+	spec.Extra.Children = append(spec.Extra.Children, fromPrivileges(table.Attrs)...)
 	return spec, nil
 }
 
@@ -615,7 +784,17 @@ func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
 	if i := (IndexNullsDistinct{}); sqlx.Has(idx.Attrs, &i) && !i.V {
 		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("nulls_distinct", i.V))
 	}
+	if r, ok := fromTabletSplit(idx.Attrs); ok {
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
 	spec.Extra.Attrs = indexPKSpec(idx, spec.Extra.Attrs)
+	if p, ok := indexStorageParams(idx.Attrs); ok && (p.AutoSummarize || len(p.Params) > 0) {
+		params := p.Params
+		if p.AutoSummarize {
+			params = append([]struct{ N, V string }{{N: "autosummarize", V: "true"}}, params...)
+		}
+		spec.Extra.Children = append(spec.Extra.Children, fromStorageParams(params))
+	}
 	return spec, nil
 }
 
@@ -627,7 +806,7 @@ func indexPKSpec(idx *schema.Index, attrs []*schemahcl.Attr) []*schemahcl.Attr {
 		}
 		attrs = append(attrs, schemahcl.RefsAttr("include", refs...))
 	}
-	if p, ok := indexStorageParams(idx.Attrs); ok {
+	if p, ok := indexStorageParams(idx.Attrs); ok && p.PagesPerRange != 0 && p.PagesPerRange != defaultPagePerRange {
 		attrs = append(attrs, schemahcl.Int64Attr("page_per_range", p.PagesPerRange))
 	}
 	return attrs
@@ -662,6 +841,12 @@ func tableColumnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error)
 	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
 		s.Extra.Children = append(s.Extra.Children, specutil.FromGenExpr(x, generatedType))
 	}
+	if c, ok := sqlx.Collate(c.Attrs, nil); ok {
+		s.Extra.Attrs = append(s.Extra.Attrs, schemahcl.StringAttr("collate", c))
+	}
+	if e := (&Encode{}); sqlx.Has(c.Attrs, e) {
+		s.Extra.Attrs = append(s.Extra.Attrs, specutil.VarAttr("encode", encodeType(e.T)))
+	}
 	return s, nil
 }
 
@@ -700,6 +885,40 @@ func columnTypeSpec(t schema.Type) (*sqlspec.Column, error) {
 	return &sqlspec.Column{Type: st}, nil
 }
 
+// customTypes holds the custom types that were registered by RegisterTypes.
+var customTypes = schemahcl.NewRegistry()
+
+// RegisterTypes registers custom types in the driver (e.g. types that are provided by
+// extensions or proprietary types). Registered types are used for parsing inspected column
+// types, formatting types in migration plans, and converting types from and to HCL. Types
+// are parsed from their database representation by their TypeSpec, in which the type
+// arguments are mapped to the attributes of the spec by their order. For example:
+//
+//	type GeoType struct {
+//		schema.Type
+//		T    string
+//		Size int
+//	}
+//
+//	postgres.RegisterTypes(&schemahcl.TypeSpec{
+//		Name:       "geo",
+//		T:          "geo",
+//		Attributes: []*schemahcl.TypeAttr{schemahcl.SizeTypeAttr(false)},
+//		RType:      reflect.TypeOf(GeoType{}),
+//	})
+//
+// RegisterTypes is not safe for concurrent use, and should be called on initialization.
+func RegisterTypes(specs ...*schemahcl.TypeSpec) error {
+	if err := customTypes.Register(specs...); err != nil {
+		return err
+	}
+	if err := TypeRegistry.Register(specs...); err != nil {
+		return err
+	}
+	hclState = newState()
+	return nil
+}
+
 // TypeRegistry contains the supported TypeSpecs for the Postgres driver.
 var TypeRegistry = schemahcl.NewRegistry(
 	schemahcl.WithSpecFunc(typeSpec),
@@ -770,7 +989,13 @@ var TypeRegistry = schemahcl.NewRegistry(
 		schemahcl.NewTypeSpec(TypeTSTZMultiRange),
 		schemahcl.NewTypeSpec(TypeDateRange),
 		schemahcl.NewTypeSpec(TypeDateMultiRange),
-		schemahcl.NewTypeSpec("hstore"),
+		schemahcl.NewTypeSpec(TypeHStore),
+		schemahcl.NewTypeSpec(TypeCIText),
+		schemahcl.NewTypeSpec(TypeLTree),
+		schemahcl.NewTypeSpec(TypeLQuery),
+		schemahcl.NewTypeSpec(TypeVector, schemahcl.WithAttributes(&schemahcl.TypeAttr{Name: "dim", Kind: reflect.Int64})),
+		schemahcl.NewTypeSpec(TypeHalfVec, schemahcl.WithAttributes(&schemahcl.TypeAttr{Name: "dim", Kind: reflect.Int64})),
+		schemahcl.NewTypeSpec(TypeSparseVec, schemahcl.WithAttributes(&schemahcl.TypeAttr{Name: "dim", Kind: reflect.Int64})),
 		schemahcl.NewTypeSpec("sql", schemahcl.WithAttributes(&schemahcl.TypeAttr{Name: "def", Required: true, Kind: reflect.String})),
 	),
 	// PostgreSQL internal and special types.
@@ -6,9 +6,12 @@ package postgres
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
+	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/internal/spectest"
 	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/schema"
@@ -510,6 +513,15 @@ table "t" {
 		require.NoError(t, EvalHCLBytes([]byte(r), &s, nil))
 		idx = s.Tables[0].Indexes[0]
 		require.Equal(t, IndexTypeGiST, idx.Attrs[0].(*IndexType).T)
+
+		// Lowercase index methods are accepted as well.
+		for _, m := range []string{"btree", "brin", "hash", "gin", "gist", "spgist"} {
+			s = schema.Schema{}
+			r = fmt.Sprintf(f, m)
+			require.NoError(t, EvalHCLBytes([]byte(r), &s, nil))
+			idx = s.Tables[0].Indexes[0]
+			require.Equal(t, strings.ToUpper(m), idx.Attrs[0].(*IndexType).T)
+		}
 	})
 }
 
@@ -777,6 +789,173 @@ schema "test" {
 	})
 }
 
+func TestMarshalSpec_StorageParams(t *testing.T) {
+	s := schema.New("test").
+		AddTables(
+			schema.NewTable("logs").
+				AddColumns(schema.NewStringColumn("name", "text")).
+				AddAttrs(&TableStorageParams{Params: []struct{ N, V string }{{N: "autovacuum_enabled", V: "false"}, {N: "autovacuum_vacuum_scale_factor", V: "0.05"}, {N: "fillfactor", V: "70"}}}),
+		)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "logs" {
+  schema = schema.test
+  column "name" {
+    null = false
+    type = text
+  }
+  storage_params {
+    autovacuum_enabled             = false
+    autovacuum_vacuum_scale_factor = 0.05
+    fillfactor                     = 70
+  }
+}
+schema "test" {
+}
+`, string(buf))
+	got := schema.New("test")
+	require.NoError(t, EvalHCLBytes(buf, got, nil))
+	tb, ok := got.Table("logs")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&TableStorageParams{Params: []struct{ N, V string }{{N: "autovacuum_enabled", V: "false"}, {N: "autovacuum_vacuum_scale_factor", V: "0.05"}, {N: "fillfactor", V: "70"}}}}, tb.Attrs)
+}
+
+func TestMarshalSpec_IndexStorageParams(t *testing.T) {
+	s := schema.New("test").
+		AddTables(
+			schema.NewTable("logs").
+				AddColumns(schema.NewStringColumn("name", "text")),
+		)
+	s.Tables[0].AddIndexes(
+		schema.NewIndex("logs_name").
+			AddColumns(s.Tables[0].Columns[0]).
+			AddAttrs(&IndexStorageParams{Params: []struct{ N, V string }{{N: "deduplicate_items", V: "off"}, {N: "fillfactor", V: "70"}}}),
+		schema.NewIndex("logs_brin").
+			AddColumns(s.Tables[0].Columns[0]).
+			AddAttrs(&IndexType{T: IndexTypeBRIN}, &IndexStorageParams{AutoSummarize: true, PagesPerRange: 2}),
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "logs" {
+  schema = schema.test
+  column "name" {
+    null = false
+    type = text
+  }
+  index "logs_name" {
+    columns = [column.name]
+    storage_params {
+      deduplicate_items = "off"
+      fillfactor        = 70
+    }
+  }
+  index "logs_brin" {
+    columns        = [column.name]
+    type           = BRIN
+    page_per_range = 2
+    storage_params {
+      autosummarize = true
+    }
+  }
+}
+schema "test" {
+}
+`, string(buf))
+	got := schema.New("test")
+	require.NoError(t, EvalHCLBytes(buf, got, nil))
+	tb, ok := got.Table("logs")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&IndexStorageParams{Params: []struct{ N, V string }{{N: "deduplicate_items", V: "off"}, {N: "fillfactor", V: "70"}}}}, tb.Indexes[0].Attrs)
+	require.Equal(t, []schema.Attr{&IndexType{T: IndexTypeBRIN}, &IndexStorageParams{AutoSummarize: true, PagesPerRange: 2}}, tb.Indexes[1].Attrs)
+}
+
+func TestMarshalSpec_Redshift(t *testing.T) {
+	var (
+		userID  = schema.NewIntColumn("user_id", "integer").AddAttrs(&Encode{T: "AZ64"})
+		created = schema.NewTimeColumn("created_at", "timestamp")
+		s       = schema.New("test").
+			AddTables(
+				schema.NewTable("events").
+					AddColumns(userID, created).
+					AddAttrs(
+						&DistStyle{S: DistStyleKey, Key: userID},
+						&SortKey{T: SortKeyInterleaved, Columns: []*schema.Column{created, userID}},
+					),
+			)
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "events" {
+  schema     = schema.test
+  dist_style = KEY
+  dist_key   = column.user_id
+  column "user_id" {
+    null   = false
+    type   = integer
+    encode = AZ64
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  sort_key {
+    type    = INTERLEAVED
+    columns = [column.created_at, column.user_id]
+  }
+}
+schema "test" {
+}
+`, string(buf))
+	got := schema.New("test")
+	require.NoError(t, EvalHCLBytes(buf, got, nil))
+	events, ok := got.Table("events")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&Encode{T: "AZ64"}}, events.Columns[0].Attrs)
+	require.Equal(t, []schema.Attr{
+		&DistStyle{S: DistStyleKey, Key: events.Columns[0]},
+		&SortKey{T: SortKeyInterleaved, Columns: []*schema.Column{events.Columns[1], events.Columns[0]}},
+	}, events.Attrs)
+}
+
+func TestMarshalSpec_Yugabyte(t *testing.T) {
+	var (
+		id     = schema.NewIntColumn("id", "bigint")
+		events = schema.NewTable("events").
+			AddColumns(id).
+			AddAttrs(&Colocation{V: false}, &TabletSplit{Tablets: 4})
+		s = schema.New("test").AddTables(events)
+	)
+	events.AddIndexes(schema.NewIndex("events_id").AddColumns(id).AddAttrs(&TabletSplit{At: []string{"(100)", "(200)"}}))
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "events" {
+  schema     = schema.test
+  colocation = false
+  column "id" {
+    null = false
+    type = bigint
+  }
+  index "events_id" {
+    columns = [column.id]
+    split {
+      at = ["(100)", "(200)"]
+    }
+  }
+  split {
+    tablets = 4
+  }
+}
+schema "test" {
+}
+`, string(buf))
+	got := schema.New("test")
+	require.NoError(t, EvalHCLBytes(buf, got, nil))
+	tb, ok := got.Table("events")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&Colocation{V: false}, &TabletSplit{Tablets: 4}}, tb.Attrs)
+	require.Equal(t, []schema.Attr{&TabletSplit{At: []string{"(100)", "(200)"}}}, tb.Indexes[0].Attrs)
+}
+
 func TestMarshalSpec_IndexPredicate(t *testing.T) {
 	s := &schema.Schema{
 		Name: "test",
@@ -1092,6 +1271,90 @@ table "t" {
 	})
 }
 
+func TestUnmarshalSpec_Privileges(t *testing.T) {
+	var (
+		s schema.Schema
+		f = `
+schema "s" {
+	privilege {
+		grantee    = "app"
+		privileges = ["USAGE"]
+	}
+}
+table "t" {
+	schema = schema.s
+	column "c" {
+		type = int
+	}
+	privilege {
+		grantee      = "app"
+		privileges   = ["SELECT", "INSERT"]
+		grant_option = true
+	}
+}
+`
+	)
+	err := EvalHCLBytes([]byte(f), &s, nil)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Attr{&Privilege{Grantee: "app", Privileges: []string{"USAGE"}}}, s.Attrs)
+	require.Equal(t, []schema.Attr{&Privilege{Grantee: "app", Privileges: []string{"SELECT", "INSERT"}, GrantOption: true}}, s.Tables[0].Attrs)
+
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.s
+  column "c" {
+    null = false
+    type = int
+  }
+  privilege {
+    grantee      = "app"
+    privileges   = ["SELECT", "INSERT"]
+    grant_option = true
+  }
+}
+schema "s" {
+  privilege {
+    grantee    = "app"
+    privileges = ["USAGE"]
+  }
+}
+`, string(buf))
+}
+
+func TestUnmarshalSpec_Collation(t *testing.T) {
+	var (
+		s schema.Schema
+		f = `
+schema "s" {}
+table "t" {
+	schema = schema.s
+	column "c" {
+		type    = text
+		collate = "C"
+	}
+}
+`
+	)
+	err := EvalHCLBytes([]byte(f), &s, nil)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Attr{&schema.Collation{V: "C"}}, s.Tables[0].Columns[0].Attrs)
+
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.s
+  column "c" {
+    null    = false
+    type    = text
+    collate = "C"
+  }
+}
+schema "s" {
+}
+`, string(buf))
+}
+
 func TestUnmarshalSpec_IndexInclude(t *testing.T) {
 	f := `
 schema "s" {}
@@ -1459,6 +1722,26 @@ func TestTypes(t *testing.T) {
 			typeExpr: `hstore`,
 			expected: &UserDefinedType{T: "hstore"},
 		},
+		{
+			typeExpr: "citext",
+			expected: &schema.StringType{T: TypeCIText},
+		},
+		{
+			typeExpr: "ltree",
+			expected: &UserDefinedType{T: TypeLTree},
+		},
+		{
+			typeExpr: "vector",
+			expected: &VectorType{T: TypeVector},
+		},
+		{
+			typeExpr: "vector(1536)",
+			expected: &VectorType{T: TypeVector, Dim: 1536},
+		},
+		{
+			typeExpr: "halfvec(3)",
+			expected: &VectorType{T: TypeHalfVec, Dim: 3},
+		},
 		{
 			typeExpr: "bit_varying(10)",
 			expected: &BitType{T: TypeBitVar, Len: 10},
@@ -2164,3 +2447,209 @@ func TestEvalHCLError(t *testing.T) {
 		require.EqualError(t, err, tt.err)
 	}
 }
+
+func TestUnmarshalSpec_Publications(t *testing.T) {
+	var (
+		r schema.Realm
+		f = `
+schema "s1" {}
+schema "s2" {}
+table "users" {
+	schema = schema.s1
+	column "id" {
+		type = int
+	}
+}
+table "s1" "pets" {
+	schema = schema.s1
+	column "id" {
+		type = int
+	}
+}
+table "s2" "pets" {
+	schema = schema.s2
+	column "id" {
+		type = int
+	}
+}
+publication "p1" {
+	tables  = [table.users, table.s2.pets]
+	publish = ["insert", "update"]
+}
+publication "p2" {
+	all_tables = true
+}
+`
+	)
+	err := EvalHCLBytes([]byte(f), &r, nil)
+	require.NoError(t, err)
+	require.Len(t, r.Objects, 2)
+	p1, p2 := r.Objects[0].(*Publication), r.Objects[1].(*Publication)
+	require.Equal(t, "p1", p1.Name)
+	require.Equal(t, []string{"insert", "update"}, p1.Publish)
+	require.Equal(t, []*schema.Table{r.Schemas[0].Tables[0], r.Schemas[1].Tables[0]}, p1.Tables)
+	require.Equal(t, &Publication{Name: "p2", AllTables: true}, p2)
+
+	buf, err := MarshalHCL(&r)
+	require.NoError(t, err)
+	var r2 schema.Realm
+	require.NoError(t, EvalHCLBytes(buf, &r2, nil))
+	require.Len(t, r2.Objects, 2)
+	require.Equal(t, []*schema.Table{r2.Schemas[0].Tables[0], r2.Schemas[1].Tables[0]}, r2.Objects[0].(*Publication).Tables)
+
+	err = EvalHCLBytes([]byte(`
+schema "s" {}
+table "t" {
+	schema = schema.s
+	column "id" {
+		type = int
+	}
+}
+publication "p" {
+	all_tables = true
+	tables     = [table.t]
+}
+`), &r, nil)
+	require.EqualError(t, err, `publication "p": all_tables and tables are mutually exclusive`)
+}
+
+type geoType struct {
+	schema.Type
+	T    string
+	Size int
+}
+
+func TestRegisterTypes(t *testing.T) {
+	err := RegisterTypes(&schemahcl.TypeSpec{
+		Name:       "geo",
+		T:          "geo",
+		Attributes: []*schemahcl.TypeAttr{schemahcl.SizeTypeAttr(false)},
+		RType:      reflect.TypeOf(geoType{}),
+	})
+	require.NoError(t, err)
+	require.Error(t, RegisterTypes(schemahcl.NewTypeSpec("geo")), "type already registered")
+
+	var (
+		s schema.Schema
+		f = `
+schema "public" {}
+table "places" {
+	schema = schema.public
+	column "loc" {
+		type = geo(3)
+	}
+}
+`
+	)
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	require.Equal(t, &geoType{T: "geo", Size: 3}, s.Tables[0].Columns[0].Type.Type)
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Contains(t, string(buf), "type = geo(3)")
+
+	typ, err := FormatType(&geoType{T: "geo", Size: 3})
+	require.NoError(t, err)
+	require.Equal(t, "geo(3)", typ)
+	parsed, err := ParseType("geo(3)")
+	require.NoError(t, err)
+	require.Equal(t, &geoType{T: "geo", Size: 3}, parsed)
+	// Inspected user-defined types are parsed from their formatted definition.
+	parsed, err = columnType(&columnDesc{typ: TypeUserDefined, fmtype: "geo(4)"})
+	require.NoError(t, err)
+	require.Equal(t, &geoType{T: "geo", Size: 4}, parsed)
+
+	changed, err := (&diff{}).typeChanged(
+		schema.NewColumn("loc").SetType(&geoType{T: "geo", Size: 3}),
+		schema.NewColumn("loc").SetType(&geoType{T: "geo", Size: 4}),
+	)
+	require.NoError(t, err)
+	require.True(t, changed)
+}
+
+func TestMarshalSpec_Funcs(t *testing.T) {
+	s := schema.New("public")
+	s.AddFuncs(
+		&schema.Func{
+			Name: "add",
+			Args: []*schema.FuncArg{
+				{Name: "a", Type: &schema.IntegerType{T: TypeInteger}},
+				{Name: "b", Type: &schema.IntegerType{T: TypeInteger}, Default: &schema.Literal{V: "1"}},
+			},
+			Ret:  &schema.IntegerType{T: TypeInteger},
+			Lang: "sql",
+			Body: "SELECT a + b",
+		},
+		&schema.Func{
+			Name:     "users",
+			Args:     []*schema.FuncArg{{Name: "n", Type: &schema.StringType{T: TypeText}, Mode: schema.FuncArgModeVariadic}},
+			RetTable: []*schema.Column{schema.NewIntColumn("id", TypeBigInt)},
+			Lang:     "plpgsql",
+			Body:     "BEGIN\n  RETURN QUERY SELECT 1;\nEND",
+		},
+	)
+	s.AddProcs(&schema.Proc{
+		Name: "log",
+		Args: []*schema.FuncArg{{Name: "id", Type: &schema.IntegerType{T: TypeBigInt}, Mode: schema.FuncArgModeInOut}},
+		Lang: "sql",
+		Body: "INSERT INTO logs VALUES (id)",
+	})
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `function "add" {
+  schema = schema.public
+  lang   = "sql"
+  return = integer
+  as     = "SELECT a + b"
+  arg "a" {
+    type = integer
+  }
+  arg "b" {
+    type    = integer
+    default = 1
+  }
+}
+function "users" {
+  schema = schema.public
+  lang   = "plpgsql"
+  as     = <<-SQL
+  BEGIN
+    RETURN QUERY SELECT 1;
+  END
+  SQL
+  arg "n" {
+    type = text
+    mode = VARIADIC
+  }
+  column "id" {
+    null = false
+    type = bigint
+  }
+}
+procedure "log" {
+  schema = schema.public
+  lang   = "sql"
+  as     = "INSERT INTO logs VALUES (id)"
+  arg "id" {
+    type = bigint
+    mode = INOUT
+  }
+}
+schema "public" {
+}
+`, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Funcs, 2)
+	require.Len(t, got.Procs, 1)
+	for i, f := range s.Funcs {
+		require.Equal(t, f.Name, got.Funcs[i].Name)
+		require.Equal(t, f.Lang, got.Funcs[i].Lang)
+		require.Equal(t, f.Body, got.Funcs[i].Body)
+		require.Equal(t, f.Args, got.Funcs[i].Args)
+		require.Equal(t, f.Ret, got.Funcs[i].Ret)
+		require.Len(t, got.Funcs[i].RetTable, len(f.RetTable))
+		require.Equal(t, "public", got.Funcs[i].Schema.Name)
+	}
+	require.Equal(t, s.Procs[0].Args, got.Procs[0].Args)
+	require.Equal(t, s.Procs[0].Body, got.Procs[0].Body)
+}
@@ -0,0 +1,201 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TableStorageParams describes table storage parameters that are added with
+// the WITH clause, such as fillfactor or per-table autovacuum settings.
+// https://www.postgresql.org/docs/current/sql-createtable.html#SQL-CREATETABLE-STORAGE-PARAMETERS
+type TableStorageParams struct {
+	schema.Attr
+	Params []struct{ N, V string }
+}
+
+// newTableStorage parses and returns the table storage parameters.
+func newTableStorage(opts string) (*TableStorageParams, error) {
+	params, err := parseOptions[struct{ N, V string }](opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table storage parameters: %w", err)
+	}
+	return &TableStorageParams{Params: params}, nil
+}
+
+// tableStorageDiff returns the change (if any) of the table storage parameters.
+func tableStorageDiff(from, to []schema.Attr) schema.Change {
+	var p1, p2 TableStorageParams
+	sqlx.Has(from, &p1)
+	sqlx.Has(to, &p2)
+	if !optionsChanged(p1.Params, p2.Params) {
+		return nil
+	}
+	return &schema.ModifyAttr{From: &p1, To: &p2}
+}
+
+// alterStorage returns the change for migrating the table storage parameters.
+func (s *state) alterStorage(t *schema.Table, change schema.Change, from, to *TableStorageParams) *migrate.Change {
+	alter := func(from, to []struct{ N, V string }) string {
+		b := s.Build("ALTER TABLE").Table(t)
+		setResetParams(b, from, to)
+		return b.String()
+	}
+	return &migrate.Change{
+		Source:  change,
+		Cmd:     alter(from.Params, to.Params),
+		Reverse: alter(to.Params, from.Params),
+		Comment: fmt.Sprintf("modify storage parameters of %q table", t.Name),
+	}
+}
+
+// list returns the storage parameters of the index, excluding the defaults.
+func (p *IndexStorageParams) list() []struct{ N, V string } {
+	var params []struct{ N, V string }
+	if p.AutoSummarize {
+		params = append(params, struct{ N, V string }{N: "autosummarize", V: "true"})
+	}
+	if p.PagesPerRange != 0 && p.PagesPerRange != defaultPagePerRange {
+		params = append(params, struct{ N, V string }{N: "pages_per_range", V: strconv.FormatInt(p.PagesPerRange, 10)})
+	}
+	return append(params, p.Params...)
+}
+
+// indexStorageChange reports if the given index modification changes only
+// the storage parameters that can be altered without rebuilding the index.
+// BRIN parameters are not altered, as they require rebuilding the index.
+func indexStorageChange(m *schema.ModifyIndex, k schema.ChangeKind) (from, to *IndexStorageParams, ok bool) {
+	if k != schema.ChangeAttr {
+		return nil, nil, false
+	}
+	from, to = &IndexStorageParams{}, &IndexStorageParams{}
+	sqlx.Has(m.From.Attrs, from)
+	sqlx.Has(m.To.Attrs, to)
+	var (
+		fromA = schema.RemoveAttr[*IndexStorageParams](m.From.Attrs)
+		toA   = schema.RemoveAttr[*IndexStorageParams](m.To.Attrs)
+	)
+	if (&diff{}).IndexAttrChanged(fromA, toA) || !brinStorageEqual(from, to) {
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// alterIndexStorage returns the change for migrating the index storage parameters.
+func (s *state) alterIndexStorage(t *schema.Table, change schema.Change, idx *schema.Index, from, to *IndexStorageParams) *migrate.Change {
+	alter := func(from, to []struct{ N, V string }) string {
+		b := s.Build("ALTER INDEX")
+		if t.Schema != nil {
+			b.WriteString(s.schemaPrefix(t.Schema))
+		}
+		b.Ident(idx.Name)
+		setResetParams(b, from, to)
+		return b.String()
+	}
+	return &migrate.Change{
+		Source:  change,
+		Cmd:     alter(from.Params, to.Params),
+		Reverse: alter(to.Params, from.Params),
+		Comment: fmt.Sprintf("modify storage parameters of %q index", idx.Name),
+	}
+}
+
+// setResetParams writes the SET and RESET clauses for migrating
+// the storage parameters from one state to the other.
+func setResetParams(b *sqlx.Builder, from, to []struct{ N, V string }) {
+	var (
+		set   []struct{ N, V string }
+		reset []string
+	)
+	for _, p := range to {
+		if v, ok := optionValue(from, p.N); !ok || v != p.V {
+			set = append(set, p)
+		}
+	}
+	for _, p := range from {
+		if _, ok := optionValue(to, p.N); !ok {
+			reset = append(reset, p.N)
+		}
+	}
+	if len(set) > 0 {
+		b.P("SET").Wrap(func(b *sqlx.Builder) {
+			storageParams(b, set)
+		})
+	}
+	if len(reset) > 0 {
+		if len(set) > 0 {
+			b.Comma()
+		}
+		b.P("RESET").Wrap(func(b *sqlx.Builder) {
+			b.WriteString(strings.Join(reset, ", "))
+		})
+	}
+}
+
+// storageParams writes the given storage parameters to the builder.
+func storageParams(b *sqlx.Builder, params []struct{ N, V string }) {
+	b.MapComma(params, func(i int, b *sqlx.Builder) {
+		v := params[i].V
+		if !reStorageValue.MatchString(v) {
+			v = quote(v)
+		}
+		b.P(params[i].N, "=", v)
+	})
+}
+
+// reStorageValue matches storage parameter values that can be written without quotes.
+var reStorageValue = regexp.MustCompile(`^[\w.+-]+$`)
+
+// convertStorageParams converts the storage_params block into a list of parameters.
+func convertStorageParams(r *schemahcl.Resource) ([]struct{ N, V string }, error) {
+	params := make([]struct{ N, V string }, 0, len(r.Attrs))
+	for _, a := range r.Attrs {
+		var v string
+		switch t := a.V.Type(); {
+		case t == cty.String:
+			v = a.V.AsString()
+		case t == cty.Number:
+			v = a.V.AsBigFloat().Text('f', -1)
+		case t == cty.Bool:
+			v = strconv.FormatBool(a.V.True())
+		default:
+			return nil, fmt.Errorf("unexpected type %s for storage parameter %q", t.FriendlyName(), a.K)
+		}
+		params = append(params, struct{ N, V string }{N: a.K, V: v})
+	}
+	// Attributes are not ordered in HCL.
+	sort.Slice(params, func(i, j int) bool { return params[i].N < params[j].N })
+	return params, nil
+}
+
+// fromStorageParams returns the storage_params block of the given parameters.
+func fromStorageParams(params []struct{ N, V string }) *schemahcl.Resource {
+	r := &schemahcl.Resource{Type: "storage_params"}
+	for _, p := range params {
+		switch v := strings.ToLower(p.V); {
+		case v == "true" || v == "false":
+			r.SetAttr(schemahcl.BoolAttr(p.N, v == "true"))
+		default:
+			if f, ok := new(big.Float).SetString(p.V); ok {
+				r.SetAttr(&schemahcl.Attr{K: p.N, V: cty.NumberVal(f)})
+			} else {
+				r.SetAttr(schemahcl.StringAttr(p.N, p.V))
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,90 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"fmt"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// addTrigger appends the change for creating the trigger.
+func (s *state) addTrigger(add *schema.AddTrigger) error {
+	create, drop, err := s.createDropTrigger(add.T)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create trigger %q", add.T.Name),
+	})
+	return nil
+}
+
+// dropTrigger appends the change for dropping the trigger.
+func (s *state) dropTrigger(drop *schema.DropTrigger) error {
+	create, cmd, err := s.createDropTrigger(drop.T)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     cmd,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop trigger %q", drop.T.Name),
+	})
+	return nil
+}
+
+// modifyTrigger appends the changes for modifying the trigger. The trigger is
+// dropped and created, as CREATE OR REPLACE TRIGGER requires PostgreSQL 14.
+func (s *state) modifyTrigger(modify *schema.ModifyTrigger) error {
+	if err := s.dropTrigger(&schema.DropTrigger{T: modify.From}); err != nil {
+		return err
+	}
+	return s.addTrigger(&schema.AddTrigger{T: modify.To})
+}
+
+// createDropTrigger returns the CREATE and DROP statements of the trigger.
+func (s *state) createDropTrigger(tr *schema.Trigger) (string, string, error) {
+	var on string
+	switch {
+	case tr.Table != nil:
+		on = s.Build().Table(tr.Table).String()
+	case tr.View != nil:
+		on = s.Build().View(tr.View).String()
+	default:
+		return "", "", fmt.Errorf("postgres: missing table or view for trigger %q", tr.Name)
+	}
+	if len(tr.Events) == 0 {
+		return "", "", fmt.Errorf("postgres: missing events for trigger %q", tr.Name)
+	}
+	if tr.ActionTime == "" {
+		return "", "", fmt.Errorf("postgres: missing action time for trigger %q", tr.Name)
+	}
+	b := s.Build("CREATE TRIGGER").Ident(tr.Name).P(string(tr.ActionTime))
+	for i, e := range tr.Events {
+		if i > 0 {
+			b.P("OR")
+		}
+		b.P(e.Name)
+		if len(e.Columns) > 0 {
+			b.P("OF")
+			b.MapComma(e.Columns, func(i int, b *sqlx.Builder) {
+				b.Ident(e.Columns[i].Name)
+			})
+		}
+	}
+	b.P("ON", on)
+	if tr.For != "" {
+		b.P("FOR EACH", string(tr.For))
+	}
+	b.P(tr.Body)
+	return b.String(), s.Build("DROP TRIGGER").Ident(tr.Name).P("ON", on).String(), nil
+}
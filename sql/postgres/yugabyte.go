@@ -0,0 +1,263 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+type (
+	ybDiff    struct{ diff }
+	ybInspect struct{ inspect }
+
+	// Colocation describes whether a YugabyteDB table is colocated, i.e. shares
+	// a single tablet with the other colocated tables of its database.
+	// https://docs.yugabyte.com/preview/explore/colocation/
+	Colocation struct {
+		schema.Attr
+		V bool
+	}
+
+	// TabletSplit describes the tablet splitting clause of a YugabyteDB table or index.
+	// https://docs.yugabyte.com/preview/api/ysql/the-sql-language/statements/ddl_create_table/#split-into
+	TabletSplit struct {
+		schema.Attr
+		Tablets int      // SPLIT INTO <n> TABLETS.
+		At      []string // SPLIT AT VALUES ((v1), (v2), ...).
+	}
+)
+
+var _ sqlx.DiffDriver = (*ybDiff)(nil)
+
+func (i *ybInspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	s, err := i.inspect.InspectSchema(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.patchSchema(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (i *ybInspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	r, err := i.inspect.InspectRealm(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range r.Schemas {
+		if err := i.patchSchema(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// patchSchema appends the YugabyteDB properties of the schema tables.
+func (i *ybInspect) patchSchema(ctx context.Context, s *schema.Schema) error {
+	if len(s.Tables) == 0 {
+		return nil
+	}
+	rows, err := i.querySchema(ctx, ybTablesQuery, s)
+	if err != nil {
+		return fmt.Errorf("postgres: querying yugabyte table properties: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name      string
+			tablets   sql.NullInt64
+			colocated sql.NullBool
+		)
+		if err := rows.Scan(&name, &tablets, &colocated); err != nil {
+			return fmt.Errorf("postgres: scanning yugabyte table properties: %w", err)
+		}
+		t, ok := s.Table(name)
+		if !ok {
+			return fmt.Errorf("table %q was not found in schema", name)
+		}
+		// The colocation option is reported by the storage parameters
+		// of the table, but it is not a storage parameter in YugabyteDB.
+		for _, a := range t.Attrs {
+			if p, ok := a.(*TableStorageParams); ok {
+				params := p.Params[:0]
+				for _, o := range p.Params {
+					if o.N != "colocation" && o.N != "colocated" {
+						params = append(params, o)
+					}
+				}
+				p.Params = params
+			}
+		}
+		switch {
+		case colocated.Bool:
+			t.AddAttrs(&Colocation{V: true})
+		case tablets.Valid && tablets.Int64 > 0:
+			t.AddAttrs(&TabletSplit{Tablets: int(tablets.Int64)})
+		}
+	}
+	return rows.Close()
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (d *ybDiff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
+	changes, err := d.diff.TableAttrDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	// Colocation is compared only if it was set explicitly on the desired
+	// table, as tables inherit the colocation mode of their database.
+	if c2 := (&Colocation{}); sqlx.Has(to.Attrs, c2) {
+		c1 := &Colocation{}
+		if sqlx.Has(from.Attrs, c1); c1.V != c2.V {
+			changes = append(changes, &schema.ModifyAttr{From: c1, To: c2})
+		}
+	}
+	// Tablet splitting is applied only on creation, as the number of tablets
+	// is changed by YugabyteDB over time when automatic splitting is enabled.
+	return changes, nil
+}
+
+// ybModifyTable returns an error if the table modification is not supported by YugabyteDB,
+// such as changes that require a table rewrite.
+func ybModifyTable(modify *schema.ModifyTable) error {
+	for _, change := range modify.Changes {
+		switch change := change.(type) {
+		case *schema.AddAttr, *schema.ModifyAttr, *schema.DropAttr:
+			var a schema.Attr
+			switch change := change.(type) {
+			case *schema.AddAttr:
+				a = change.A
+			case *schema.ModifyAttr:
+				a = change.To
+			case *schema.DropAttr:
+				a = change.A
+			}
+			switch a.(type) {
+			case *Colocation:
+				return fmt.Errorf("postgres: changing the colocation of table %q requires recreating it in YugabyteDB", modify.T.Name)
+			case *TabletSplit:
+				return fmt.Errorf("postgres: changing the tablet splitting of table %q is not supported by YugabyteDB", modify.T.Name)
+			}
+		case *schema.ModifyColumn:
+			if change.Change.Is(schema.ChangeType) {
+				return fmt.Errorf("postgres: changing the type of column %q requires a table rewrite, which is not supported by YugabyteDB", change.To.Name)
+			}
+		case *schema.AddPrimaryKey, *schema.DropPrimaryKey, *schema.ModifyPrimaryKey:
+			return fmt.Errorf("postgres: changing the primary key of table %q requires a table rewrite, which is not supported by YugabyteDB", modify.T.Name)
+		}
+	}
+	return nil
+}
+
+// ybTableParams returns the table storage parameters, including the colocation option.
+func ybTableParams(t *schema.Table, params []struct{ N, V string }) []struct{ N, V string } {
+	if c := (Colocation{}); sqlx.Has(t.Attrs, &c) {
+		params = append(params, struct{ N, V string }{N: "colocation", V: strconv.FormatBool(c.V)})
+	}
+	return params
+}
+
+// ybSplit writes the tablet splitting clause of a table or an index.
+func ybSplit(b *sqlx.Builder, attrs []schema.Attr) {
+	s := &TabletSplit{}
+	switch {
+	case !sqlx.Has(attrs, s):
+	case len(s.At) > 0:
+		b.P("SPLIT AT VALUES").Wrap(func(b *sqlx.Builder) {
+			b.WriteString(strings.Join(s.At, ", "))
+		})
+	case s.Tablets > 0:
+		b.P("SPLIT INTO", strconv.Itoa(s.Tablets), "TABLETS")
+	}
+}
+
+// convertTabletSplit converts the split block into a TabletSplit attribute, if exists.
+func convertTabletSplit(spec schemahcl.Resource, name string) (*TabletSplit, bool, error) {
+	r, ok := spec.Resource("split")
+	if !ok {
+		return nil, false, nil
+	}
+	var s struct {
+		Tablets int      `spec:"tablets"`
+		At      []string `spec:"at"`
+	}
+	if err := r.As(&s); err != nil {
+		return nil, false, fmt.Errorf("parsing %s.split: %w", name, err)
+	}
+	if s.Tablets > 0 && len(s.At) > 0 {
+		return nil, false, fmt.Errorf(`multiple definitions for %s.split, use "tablets" or "at"`, name)
+	}
+	return &TabletSplit{Tablets: s.Tablets, At: s.At}, true, nil
+}
+
+// convertYugabyteTable converts the YugabyteDB attributes of the table spec.
+func convertYugabyteTable(spec *sqlspec.Table, t *schema.Table) error {
+	if attr, ok := spec.Attr("colocation"); ok {
+		v, err := attr.Bool()
+		if err != nil {
+			return err
+		}
+		t.AddAttrs(&Colocation{V: v})
+	}
+	s, ok, err := convertTabletSplit(spec.Extra, t.Name)
+	if err != nil {
+		return err
+	}
+	if ok {
+		t.AddAttrs(s)
+	}
+	return nil
+}
+
+// fromTabletSplit returns the split block of the given attributes, if exists.
+func fromTabletSplit(attrs []schema.Attr) (*schemahcl.Resource, bool) {
+	s := &TabletSplit{}
+	if !sqlx.Has(attrs, s) || s.Tablets == 0 && len(s.At) == 0 {
+		return nil, false
+	}
+	r := &schemahcl.Resource{Type: "split"}
+	if len(s.At) > 0 {
+		r.Attrs = append(r.Attrs, schemahcl.StringsAttr("at", s.At...))
+	} else {
+		r.Attrs = append(r.Attrs, schemahcl.IntAttr("tablets", s.Tablets))
+	}
+	return r, true
+}
+
+// fromYugabyteTable appends the YugabyteDB attributes of the table to its spec.
+func fromYugabyteTable(spec *sqlspec.Table, t *schema.Table) {
+	if c := (Colocation{}); sqlx.Has(t.Attrs, &c) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("colocation", c.V))
+	}
+	if r, ok := fromTabletSplit(t.Attrs); ok {
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
+}
+
+// Query to list the YugabyteDB properties of the schema tables.
+// https://docs.yugabyte.com/preview/api/ysql/exprs/func_yb_table_properties/
+const ybTablesQuery = `
+SELECT
+	c.relname AS table_name,
+	p.num_tablets,
+	p.is_colocated
+FROM
+	pg_catalog.pg_class AS c
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace,
+	LATERAL yb_table_properties(c.oid) AS p
+WHERE
+	n.nspname = $1
+	AND c.relname IN (%s)
+`
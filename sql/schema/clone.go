@@ -0,0 +1,274 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import "reflect"
+
+// cloner clones schema objects, and links the references between
+// the cloned objects (e.g. foreign-keys) after they were all cloned.
+type cloner struct {
+	tables  map[*Table]*Table
+	columns map[*Column]*Column
+	objects map[Object]Object
+	fks     []*ForeignKey
+	deps    [][]Object
+	seqs    []*Sequence
+}
+
+func newCloner() *cloner {
+	return &cloner{
+		tables:  make(map[*Table]*Table),
+		columns: make(map[*Column]*Column),
+		objects: make(map[Object]Object),
+	}
+}
+
+// Clone returns a deep copy of the realm. The references between the objects of
+// the realm (e.g. foreign-keys and view dependencies) are linked to their copies.
+// Types, expressions and driver-specific objects are shared with the original realm,
+// and attributes are copied shallowly.
+func (r *Realm) Clone() *Realm {
+	c := newCloner()
+	rc := &Realm{Attrs: cloneAttrs(r.Attrs), Objects: cloneSlice(r.Objects)}
+	if r.Schemas != nil {
+		rc.Schemas = make([]*Schema, len(r.Schemas))
+		for i, s := range r.Schemas {
+			rc.Schemas[i] = c.schema(s)
+			rc.Schemas[i].Realm = rc
+		}
+	}
+	c.link()
+	return rc
+}
+
+// Clone returns a deep copy of the schema. The references between the objects of the
+// schema are linked to their copies, and references to objects of other schemas are kept.
+// Note that the copy is linked to the realm of the original schema, but it is not added
+// to it.
+func (s *Schema) Clone() *Schema {
+	c := newCloner()
+	sc := c.schema(s)
+	c.link()
+	return sc
+}
+
+// Clone returns a deep copy of the table, including its columns, indexes, foreign-keys,
+// and triggers. Self-references are linked to the copy, and references to other tables
+// are kept. Note that the copy is linked to the schema of the original table, but it is
+// not added to it.
+func (t *Table) Clone() *Table {
+	c := newCloner()
+	tc := c.table(t)
+	c.link()
+	return tc
+}
+
+// Clone returns a copy of the column. Note that the copy
+// is not linked to the indexes and foreign-keys of the
+// original column.
+func (c *Column) Clone() *Column {
+	return newCloner().column(c)
+}
+
+func (c *cloner) schema(s *Schema) *Schema {
+	sc := *s
+	sc.Attrs, sc.Objects = cloneAttrs(s.Attrs), cloneSlice(s.Objects)
+	sc.Tables = cloneEach(s.Tables, func(t *Table) *Table {
+		tc := c.table(t)
+		tc.Schema = &sc
+		return tc
+	})
+	sc.Views = cloneEach(s.Views, func(v *View) *View {
+		vc := c.view(v)
+		vc.Schema = &sc
+		return vc
+	})
+	sc.Funcs = cloneEach(s.Funcs, func(f *Func) *Func {
+		fc := *f
+		fc.Schema, fc.Attrs = &sc, cloneAttrs(f.Attrs)
+		fc.Args, fc.RetTable = cloneArgs(f.Args), cloneEach(f.RetTable, c.column)
+		fc.Deps = c.dependsOn(f.Deps)
+		c.objects[f] = &fc
+		return &fc
+	})
+	sc.Procs = cloneEach(s.Procs, func(p *Proc) *Proc {
+		pc := *p
+		pc.Schema, pc.Attrs, pc.Args = &sc, cloneAttrs(p.Attrs), cloneArgs(p.Args)
+		pc.Deps = c.dependsOn(p.Deps)
+		c.objects[p] = &pc
+		return &pc
+	})
+	sc.Sequences = cloneEach(s.Sequences, func(sq *Sequence) *Sequence {
+		sqc := *sq
+		sqc.Schema, sqc.Attrs = &sc, cloneAttrs(sq.Attrs)
+		c.objects[sq] = &sqc
+		c.seqs = append(c.seqs, &sqc)
+		return &sqc
+	})
+	return &sc
+}
+
+func (c *cloner) table(t *Table) *Table {
+	tc := *t
+	c.tables[t], c.objects[t] = &tc, &tc
+	tc.Attrs = cloneAttrs(t.Attrs)
+	tc.Columns = cloneEach(t.Columns, c.column)
+	tc.Indexes = cloneEach(t.Indexes, func(idx *Index) *Index {
+		return c.index(&tc, idx)
+	})
+	if t.PrimaryKey != nil {
+		tc.PrimaryKey = c.index(&tc, t.PrimaryKey)
+	}
+	tc.ForeignKeys = cloneEach(t.ForeignKeys, func(fk *ForeignKey) *ForeignKey {
+		fkc := *fk
+		fkc.Table = &tc
+		fkc.Columns, fkc.RefColumns = cloneSlice(fk.Columns), cloneSlice(fk.RefColumns)
+		c.fks = append(c.fks, &fkc)
+		return &fkc
+	})
+	tc.Triggers = cloneEach(t.Triggers, func(tr *Trigger) *Trigger {
+		trc := c.trigger(tr)
+		trc.Table = &tc
+		return trc
+	})
+	return &tc
+}
+
+func (c *cloner) view(v *View) *View {
+	vc := *v
+	c.objects[v] = &vc
+	vc.Attrs, vc.Columns = cloneAttrs(v.Attrs), cloneEach(v.Columns, c.column)
+	vc.Deps = c.dependsOn(v.Deps)
+	vc.Triggers = cloneEach(v.Triggers, func(tr *Trigger) *Trigger {
+		trc := c.trigger(tr)
+		trc.View = &vc
+		return trc
+	})
+	return &vc
+}
+
+func (c *cloner) trigger(tr *Trigger) *Trigger {
+	trc := *tr
+	c.objects[tr] = &trc
+	trc.Attrs, trc.Deps = cloneAttrs(tr.Attrs), c.dependsOn(tr.Deps)
+	trc.Events = cloneEach(tr.Events, func(e TriggerEvent) TriggerEvent {
+		e.Columns = cloneEach(e.Columns, c.col)
+		return e
+	})
+	return &trc
+}
+
+func (c *cloner) column(col *Column) *Column {
+	cc := *col
+	c.columns[col] = &cc
+	if col.Type != nil {
+		ct := *col.Type
+		cc.Type = &ct
+	}
+	cc.Attrs = cloneAttrs(col.Attrs)
+	// Indexes and foreign-keys are linked when they are cloned.
+	cc.Indexes, cc.ForeignKeys = nil, nil
+	return &cc
+}
+
+func (c *cloner) index(t *Table, idx *Index) *Index {
+	ic := *idx
+	ic.Table, ic.Attrs = t, cloneAttrs(idx.Attrs)
+	ic.Parts = cloneEach(idx.Parts, func(p *IndexPart) *IndexPart {
+		pc := *p
+		pc.Attrs = cloneAttrs(p.Attrs)
+		if pc.C = c.col(p.C); pc.C != nil && !pc.C.hasIndex(&ic) {
+			pc.C.Indexes = append(pc.C.Indexes, &ic)
+		}
+		return &pc
+	})
+	return &ic
+}
+
+// dependsOn returns a copy of the dependencies, to be linked after all objects were cloned.
+func (c *cloner) dependsOn(deps []Object) []Object {
+	deps = cloneSlice(deps)
+	c.deps = append(c.deps, deps)
+	return deps
+}
+
+// link links the references of the cloned objects to their copies.
+func (c *cloner) link() {
+	for _, fk := range c.fks {
+		for i := range fk.Columns {
+			if fk.Columns[i] = c.col(fk.Columns[i]); !fk.Columns[i].hasForeignKey(fk) {
+				fk.Columns[i].ForeignKeys = append(fk.Columns[i].ForeignKeys, fk)
+			}
+		}
+		if t, ok := c.tables[fk.RefTable]; ok {
+			fk.RefTable = t
+		}
+		for i := range fk.RefColumns {
+			fk.RefColumns[i] = c.col(fk.RefColumns[i])
+		}
+	}
+	for _, deps := range c.deps {
+		for i, d := range deps {
+			if o, ok := c.objects[d]; ok {
+				deps[i] = o
+			}
+		}
+	}
+	for _, s := range c.seqs {
+		if t, ok := c.tables[s.Owner.T]; ok {
+			s.Owner.T = t
+		}
+		s.Owner.C = c.col(s.Owner.C)
+	}
+}
+
+// col returns the copy of the column, if it was cloned.
+func (c *cloner) col(col *Column) *Column {
+	if cc, ok := c.columns[col]; ok {
+		return cc
+	}
+	return col
+}
+
+func cloneArgs(args []*FuncArg) []*FuncArg {
+	return cloneEach(args, func(a *FuncArg) *FuncArg {
+		ac := *a
+		ac.Attrs = cloneAttrs(a.Attrs)
+		return &ac
+	})
+}
+
+// cloneAttrs returns a shallow copy of the attributes.
+func cloneAttrs(attrs []Attr) []Attr {
+	return cloneEach(attrs, func(a Attr) Attr {
+		v := reflect.ValueOf(a)
+		if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return a
+		}
+		c := reflect.New(v.Elem().Type())
+		c.Elem().Set(v.Elem())
+		if ca, ok := c.Interface().(Attr); ok {
+			return ca
+		}
+		return a
+	})
+}
+
+func cloneSlice[T any](s []T) []T {
+	return cloneEach(s, func(v T) T { return v })
+}
+
+// cloneEach returns a copy of the slice, with its elements cloned by f.
+// A nil slice is returned for nil slices, to keep the zero values intact.
+func cloneEach[T any](s []T, f func(T) T) []T {
+	if s == nil {
+		return nil
+	}
+	c := make([]T, len(s))
+	for i := range s {
+		c[i] = f(s[i])
+	}
+	return c
+}
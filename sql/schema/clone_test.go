@@ -0,0 +1,100 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCloneRealm() *schema.Realm {
+	var (
+		status = &schema.EnumType{T: "status", Values: []string{"active", "inactive"}}
+		users  = schema.NewTable("users").
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewColumn("status").SetType(status).SetComment("user status"))
+		pets = schema.NewTable("pets").
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner_id", "int")).
+			AddChecks(schema.NewCheck().SetName("positive_id").SetExpr("id > 0"))
+	)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	users.AddIndexes(schema.NewIndex("users_status").AddColumns(users.Columns[1]))
+	pets.AddForeignKeys(schema.NewForeignKey("owner").AddColumns(pets.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	v := schema.NewView("active_users", "SELECT * FROM users").AddDeps(users)
+	seq := schema.NewSequence("pets_id_seq").SetOwner(pets, pets.Columns[0])
+	return schema.NewRealm(
+		schema.New("public").AddTables(users, pets).AddViews(v).AddSequences(seq).AddObjects(status).SetCharset("utf8"),
+	)
+}
+
+func TestRealm_Clone(t *testing.T) {
+	r := newCloneRealm()
+	c := r.Clone()
+	require.True(t, r.Equal(c))
+	require.True(t, c.Equal(r))
+
+	var (
+		s, sc         = r.Schemas[0], c.Schemas[0]
+		users, usersC = s.Tables[0], sc.Tables[0]
+		pets, petsC   = s.Tables[1], sc.Tables[1]
+	)
+	require.Equal(t, c, sc.Realm)
+	require.Equal(t, sc, usersC.Schema)
+	require.True(t, users != usersC)
+	// References are linked to the copies.
+	require.True(t, usersC == petsC.ForeignKeys[0].RefTable)
+	require.True(t, usersC.Columns[0] == petsC.ForeignKeys[0].RefColumns[0])
+	require.True(t, petsC.Columns[1] == petsC.ForeignKeys[0].Columns[0])
+	require.Equal(t, []*schema.ForeignKey{petsC.ForeignKeys[0]}, petsC.Columns[1].ForeignKeys)
+	require.True(t, usersC == usersC.Indexes[0].Table)
+	require.True(t, usersC.Columns[1] == usersC.Indexes[0].Parts[0].C)
+	require.Equal(t, []*schema.Index{usersC.Indexes[0]}, usersC.Columns[1].Indexes)
+	require.True(t, usersC == sc.Views[0].Deps[0])
+	require.True(t, petsC == sc.Sequences[0].Owner.T)
+	require.True(t, petsC.Columns[0] == sc.Sequences[0].Owner.C)
+
+	// Mutating the copy does not affect the original.
+	usersC.Columns[1].Attrs[0].(*schema.Comment).Text = "changed"
+	require.False(t, r.Equal(c))
+	require.Equal(t, "user status", users.Columns[1].Attrs[0].(*schema.Comment).Text)
+	petsC.AddColumns(schema.NewIntColumn("age", "int"))
+	require.Len(t, pets.Columns, 2)
+	require.False(t, pets.Equal(petsC))
+}
+
+func TestTable_Clone(t *testing.T) {
+	r := newCloneRealm()
+	pets := r.Schemas[0].Tables[1]
+	pets.AddForeignKeys(schema.NewForeignKey("parent").AddColumns(pets.Columns[1]).SetRefTable(pets).AddRefColumns(pets.Columns[0]))
+	c := pets.Clone()
+	require.True(t, pets.Equal(c))
+	require.True(t, pets.Schema == c.Schema)
+	// References to other tables are kept, and self-references are linked to the copy.
+	require.True(t, pets.ForeignKeys[0].RefTable == c.ForeignKeys[0].RefTable)
+	require.True(t, c == c.ForeignKeys[1].RefTable)
+	require.True(t, c.Columns[0] == c.ForeignKeys[1].RefColumns[0])
+
+	col := pets.Columns[1].Clone()
+	require.Empty(t, col.ForeignKeys)
+	require.True(t, col.Equal(pets.Columns[1]))
+	col.Type.Null = true
+	require.False(t, col.Equal(pets.Columns[1]))
+	require.False(t, pets.Columns[1].Type.Null)
+}
+
+func TestTable_Equal(t *testing.T) {
+	t1, t2 := newCloneRealm().Schemas[0].Tables[1], newCloneRealm().Schemas[0].Tables[1]
+	require.True(t, t1.Equal(t2))
+	// Attributes are compared regardless of their order.
+	t1.SetComment("c").SetCharset("utf8")
+	t2.SetCharset("utf8").SetComment("c")
+	require.True(t, t1.Equal(t2))
+	t2.ForeignKeys[0].OnDelete = schema.Cascade
+	require.False(t, t1.Equal(t2))
+	require.False(t, t1.Equal(nil))
+	require.True(t, (*schema.Table)(nil).Equal(nil))
+}
@@ -79,6 +79,33 @@ func (s *Schema) AddViews(views ...*View) *Schema {
 	return s
 }
 
+// AddFuncs adds and links the given functions to the schema.
+func (s *Schema) AddFuncs(funcs ...*Func) *Schema {
+	for _, f := range funcs {
+		f.Schema = s
+	}
+	s.Funcs = append(s.Funcs, funcs...)
+	return s
+}
+
+// AddProcs adds and links the given procedures to the schema.
+func (s *Schema) AddProcs(procs ...*Proc) *Schema {
+	for _, p := range procs {
+		p.Schema = s
+	}
+	s.Procs = append(s.Procs, procs...)
+	return s
+}
+
+// AddSequences adds and links the given sequences to the schema.
+func (s *Schema) AddSequences(seqs ...*Sequence) *Schema {
+	for _, sq := range seqs {
+		sq.Schema = s
+	}
+	s.Sequences = append(s.Sequences, seqs...)
+	return s
+}
+
 // AddObjects adds the given objects to the schema.
 func (s *Schema) AddObjects(objs ...Object) *Schema {
 	s.Objects = append(s.Objects, objs...)
@@ -103,6 +130,12 @@ func (r *Realm) AddSchemas(schemas ...*Schema) *Realm {
 	return r
 }
 
+// AddObjects adds the given objects to the realm.
+func (r *Realm) AddObjects(objs ...Object) *Realm {
+	r.Objects = append(r.Objects, objs...)
+	return r
+}
+
 // SetCharset sets or appends the Charset attribute
 // to the realm with the given value.
 func (r *Realm) SetCharset(v string) *Realm {
@@ -220,6 +253,15 @@ func (t *Table) AddForeignKeys(fks ...*ForeignKey) *Table {
 	return t
 }
 
+// AddTriggers appends and links the given triggers to the table.
+func (t *Table) AddTriggers(triggers ...*Trigger) *Table {
+	for _, tr := range triggers {
+		tr.Table, tr.View = t, nil
+	}
+	t.Triggers = append(t.Triggers, triggers...)
+	return t
+}
+
 // AddAttrs adds and additional attributes to the table.
 func (t *Table) AddAttrs(attrs ...Attr) *Table {
 	t.Attrs = append(t.Attrs, attrs...)
@@ -231,6 +273,11 @@ func NewView(name, def string) *View {
 	return &View{Name: name, Def: def}
 }
 
+// NewMaterializedView creates a new materialized View.
+func NewMaterializedView(name, def string) *View {
+	return NewView(name, def).SetMaterialized(true)
+}
+
 // SetSchema sets the schema (named-database) of the view.
 func (v *View) SetSchema(s *Schema) *View {
 	v.Schema = s
@@ -262,12 +309,54 @@ func (v *View) AddDeps(objs ...Object) *View {
 	return v
 }
 
+// AddTriggers appends and links the given triggers to the view.
+func (v *View) AddTriggers(triggers ...*Trigger) *View {
+	for _, tr := range triggers {
+		tr.Table, tr.View = nil, v
+	}
+	v.Triggers = append(v.Triggers, triggers...)
+	return v
+}
+
 // SetCheckOption sets the check option of the view.
 func (v *View) SetCheckOption(opt string) *View {
 	ReplaceOrAppend(&v.Attrs, &ViewCheckOption{V: opt})
 	return v
 }
 
+// SetMaterialized sets or removes the Materialized attribute of the view.
+func (v *View) SetMaterialized(b bool) *View {
+	if b {
+		ReplaceOrAppend(&v.Attrs, &Materialized{})
+	} else {
+		del(&v.Attrs, &Materialized{})
+	}
+	return v
+}
+
+// NewSequence creates a new Sequence.
+func NewSequence(name string) *Sequence {
+	return &Sequence{Name: name}
+}
+
+// SetStart sets the start value of the sequence.
+func (s *Sequence) SetStart(v int64) *Sequence {
+	s.Start = v
+	return s
+}
+
+// SetIncrement sets the increment value of the sequence.
+func (s *Sequence) SetIncrement(v int64) *Sequence {
+	s.Increment = v
+	return s
+}
+
+// SetOwner sets the column that owns the sequence.
+func (s *Sequence) SetOwner(t *Table, c *Column) *Sequence {
+	s.Owner = SequenceOwner{T: t, C: c}
+	return s
+}
+
 // NewColumn creates a new column with the given name.
 func NewColumn(name string) *Column {
 	return &Column{Name: name}
@@ -619,6 +708,12 @@ func (c *Check) SetExpr(expr string) *Check {
 	return c
 }
 
+// SetEnforced sets the ENFORCED attribute of the check constraint.
+func (c *Check) SetEnforced(b bool) *Check {
+	ReplaceOrAppend(&c.Attrs, &Enforced{V: b})
+	return c
+}
+
 // AddAttrs adds additional attributes to the check constraint.
 func (c *Check) AddAttrs(attrs ...Attr) *Check {
 	c.Attrs = append(c.Attrs, attrs...)
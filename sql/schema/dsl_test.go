@@ -141,6 +141,15 @@ func TestSchema_Views(t *testing.T) {
 	v1, v2 := schema.NewView("v1", "SELECT 1"), schema.NewView("v2", "SELECT 2")
 	s.AddViews(v1, v2)
 	require.Equal(t, []*schema.View{v1, v2}, s.Views)
+	require.False(t, v1.Materialized())
+
+	v3 := schema.NewMaterializedView("v3", "SELECT 3")
+	require.True(t, v3.Materialized())
+	v3.SetMaterialized(true)
+	require.Len(t, v3.Attrs, 1)
+	v3.SetMaterialized(false)
+	require.False(t, v3.Materialized())
+	require.Empty(t, v3.Attrs)
 }
 
 func TestSchema_SetCharset(t *testing.T) {
@@ -184,8 +193,13 @@ func TestSchema_SetGeneratedExpr(t *testing.T) {
 	c := schema.NewIntColumn("c", "int")
 	require.Empty(t, c.Attrs)
 	x := &schema.GeneratedExpr{Expr: "d*2", Type: "VIRTUAL"}
+	_, ok := c.Generated()
+	require.False(t, ok)
 	c.SetGeneratedExpr(x)
 	require.Equal(t, []schema.Attr{x}, c.Attrs)
+	g, ok := c.Generated()
+	require.True(t, ok)
+	require.Equal(t, x, g)
 }
 
 func TestCheck(t *testing.T) {
@@ -213,6 +227,14 @@ func TestCheck(t *testing.T) {
 		Expr:  "price1 > 0",
 		Attrs: []schema.Attr{enforced},
 	}, tbl.Attrs[1])
+
+	c := schema.NewCheck().SetExpr("price1 > 0")
+	require.True(t, c.Enforced())
+	c.SetEnforced(false)
+	require.False(t, c.Enforced())
+	c.SetEnforced(true)
+	require.True(t, c.Enforced())
+	require.Equal(t, []schema.Attr{&schema.Enforced{V: true}}, c.Attrs)
 }
 
 func TestRemoveAttr(t *testing.T) {
@@ -0,0 +1,195 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import "reflect"
+
+// Equal reports if the two realms are structurally equal. See Table.Equal for details.
+func (r *Realm) Equal(o *Realm) bool {
+	if r == nil || o == nil {
+		return r == o
+	}
+	return attrsEqual(r.Attrs, o.Attrs) && objectsEqual(r.Objects, o.Objects) &&
+		sliceEqual(r.Schemas, o.Schemas, (*Schema).Equal)
+}
+
+// Equal reports if the two schemas, and their objects, are structurally equal.
+// See Table.Equal for details.
+func (s *Schema) Equal(o *Schema) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return s.Name == o.Name && attrsEqual(s.Attrs, o.Attrs) && objectsEqual(s.Objects, o.Objects) &&
+		sliceEqual(s.Tables, o.Tables, (*Table).Equal) &&
+		sliceEqual(s.Views, o.Views, viewEqual) &&
+		sliceEqual(s.Funcs, o.Funcs, funcEqual) &&
+		sliceEqual(s.Procs, o.Procs, procEqual) &&
+		sliceEqual(s.Sequences, o.Sequences, sequenceEqual)
+}
+
+// Equal reports if the two tables are structurally equal. Unlike reflect.DeepEqual, the
+// back-references of the objects (e.g. the schema of a table, or the table of an index)
+// are not followed, and references to other objects, for example, the columns of an index
+// or the referenced table of a foreign-key, are compared by their names. Attributes are
+// compared regardless of their order.
+func (t *Table) Equal(o *Table) bool {
+	if t == nil || o == nil {
+		return t == o
+	}
+	return t.Name == o.Name && attrsEqual(t.Attrs, o.Attrs) &&
+		sliceEqual(t.Columns, o.Columns, (*Column).Equal) &&
+		indexEqual(t.PrimaryKey, o.PrimaryKey) &&
+		sliceEqual(t.Indexes, o.Indexes, indexEqual) &&
+		sliceEqual(t.ForeignKeys, o.ForeignKeys, fkEqual) &&
+		sliceEqual(t.Triggers, o.Triggers, triggerEqual)
+}
+
+// Equal reports if the two columns are structurally equal. See Table.Equal for details.
+func (c *Column) Equal(o *Column) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	return c.Name == o.Name && columnTypeEqual(c.Type, o.Type) &&
+		reflect.DeepEqual(c.Default, o.Default) && attrsEqual(c.Attrs, o.Attrs)
+}
+
+func columnTypeEqual(t1, t2 *ColumnType) bool {
+	if t1 == nil || t2 == nil {
+		return t1 == t2
+	}
+	return t1.Raw == t2.Raw && t1.Null == t2.Null && typeEqual(t1.Type, t2.Type)
+}
+
+// typeEqual reports if the two types are equal. Enum types
+// are compared by their names, values and schema names.
+func typeEqual(t1, t2 Type) bool {
+	e1, ok1 := t1.(*EnumType)
+	e2, ok2 := t2.(*EnumType)
+	if ok1 && ok2 {
+		return e1.T == e2.T && reflect.DeepEqual(e1.Values, e2.Values) && schemaName(e1.Schema) == schemaName(e2.Schema)
+	}
+	return reflect.DeepEqual(t1, t2)
+}
+
+func indexEqual(i1, i2 *Index) bool {
+	if i1 == nil || i2 == nil {
+		return i1 == i2
+	}
+	return i1.Name == i2.Name && i1.Unique == i2.Unique && attrsEqual(i1.Attrs, i2.Attrs) &&
+		sliceEqual(i1.Parts, i2.Parts, func(p1, p2 *IndexPart) bool {
+			return p1.Desc == p2.Desc && columnName(p1.C) == columnName(p2.C) && (p1.C == nil) == (p2.C == nil) &&
+				reflect.DeepEqual(p1.X, p2.X) && attrsEqual(p1.Attrs, p2.Attrs)
+		})
+}
+
+func fkEqual(f1, f2 *ForeignKey) bool {
+	names := func(c1, c2 *Column) bool { return columnName(c1) == columnName(c2) }
+	return f1.Symbol == f2.Symbol && f1.OnUpdate == f2.OnUpdate && f1.OnDelete == f2.OnDelete &&
+		tableName(f1.RefTable) == tableName(f2.RefTable) &&
+		sliceEqual(f1.Columns, f2.Columns, names) && sliceEqual(f1.RefColumns, f2.RefColumns, names)
+}
+
+func viewEqual(v1, v2 *View) bool {
+	return v1.Name == v2.Name && v1.Def == v2.Def && attrsEqual(v1.Attrs, v2.Attrs) &&
+		sliceEqual(v1.Columns, v2.Columns, (*Column).Equal) &&
+		sliceEqual(v1.Triggers, v2.Triggers, triggerEqual)
+}
+
+func triggerEqual(t1, t2 *Trigger) bool {
+	return t1.Name == t2.Name && t1.ActionTime == t2.ActionTime && t1.For == t2.For && t1.Body == t2.Body &&
+		attrsEqual(t1.Attrs, t2.Attrs) && sliceEqual(t1.Events, t2.Events, func(e1, e2 TriggerEvent) bool {
+		return e1.Name == e2.Name && sliceEqual(e1.Columns, e2.Columns, func(c1, c2 *Column) bool {
+			return columnName(c1) == columnName(c2)
+		})
+	})
+}
+
+func funcEqual(f1, f2 *Func) bool {
+	return f1.Name == f2.Name && f1.Lang == f2.Lang && f1.Body == f2.Body && typeEqual(f1.Ret, f2.Ret) &&
+		attrsEqual(f1.Attrs, f2.Attrs) && sliceEqual(f1.Args, f2.Args, argEqual) &&
+		sliceEqual(f1.RetTable, f2.RetTable, (*Column).Equal)
+}
+
+func procEqual(p1, p2 *Proc) bool {
+	return p1.Name == p2.Name && p1.Lang == p2.Lang && p1.Body == p2.Body &&
+		attrsEqual(p1.Attrs, p2.Attrs) && sliceEqual(p1.Args, p2.Args, argEqual)
+}
+
+func argEqual(a1, a2 *FuncArg) bool {
+	return a1.Name == a2.Name && a1.Mode == a2.Mode && typeEqual(a1.Type, a2.Type) &&
+		reflect.DeepEqual(a1.Default, a2.Default) && attrsEqual(a1.Attrs, a2.Attrs)
+}
+
+func sequenceEqual(s1, s2 *Sequence) bool {
+	return s1.Name == s2.Name && typeEqual(s1.Type, s2.Type) && s1.Start == s2.Start &&
+		s1.Increment == s2.Increment && s1.Min == s2.Min && s1.Max == s2.Max && s1.Cache == s2.Cache &&
+		s1.Cycle == s2.Cycle && tableName(s1.Owner.T) == tableName(s2.Owner.T) &&
+		columnName(s1.Owner.C) == columnName(s2.Owner.C) && attrsEqual(s1.Attrs, s2.Attrs)
+}
+
+// attrsEqual reports if the two lists hold the same attributes, regardless of their order.
+func attrsEqual(a1, a2 []Attr) bool {
+	if len(a1) != len(a2) {
+		return false
+	}
+	used := make([]bool, len(a2))
+Attrs:
+	for _, a := range a1 {
+		for j, b := range a2 {
+			if !used[j] && reflect.DeepEqual(a, b) {
+				used[j] = true
+				continue Attrs
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// objectsEqual reports if the two lists hold the same driver-specific objects.
+func objectsEqual(o1, o2 []Object) bool {
+	return sliceEqual(o1, o2, func(o1, o2 Object) bool {
+		t1, ok1 := o1.(Type)
+		t2, ok2 := o2.(Type)
+		if ok1 && ok2 {
+			return typeEqual(t1, t2)
+		}
+		return reflect.DeepEqual(o1, o2)
+	})
+}
+
+func sliceEqual[T any](s1, s2 []T, eq func(T, T) bool) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for i := range s1 {
+		if !eq(s1[i], s2[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func columnName(c *Column) string {
+	if c == nil {
+		return ""
+	}
+	return c.Name
+}
+
+// tableName returns the name of the table, qualified with its schema name.
+func tableName(t *Table) string {
+	if t == nil {
+		return ""
+	}
+	return schemaName(t.Schema) + "." + t.Name
+}
+
+func schemaName(s *Schema) string {
+	if s == nil {
+		return ""
+	}
+	return s.Name
+}
@@ -0,0 +1,354 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// A Graph describes the dependencies between schema objects. Tables depend on the tables
+	// they reference with foreign keys and on the object types of their columns (e.g. enums
+	// in PostgreSQL) and on the sequences they own, views, functions and procedures depend on
+	// the objects they use in their definition, and triggers depend on the tables or views they
+	// are attached to, and on their dependencies. Other dependencies, for example, between
+	// driver-specific objects, can be added with AddDeps.
+	//
+	// Sort returns the objects in their creation order, and ReverseSort in their drop order.
+	Graph struct {
+		objs  []Object            // objects in the order they were added
+		index map[Object]int      // objects positions in objs
+		deps  map[Object][]Object // objects and their dependencies
+	}
+
+	// A CycleError is returned by Graph.Sort when some objects in the graph depend on
+	// each other, and cannot be ordered without detaching (some of) their references.
+	CycleError struct {
+		// Objects that form the cycle. Each object depends
+		// on the one that follows it, and the last object
+		// depends on the first.
+		Objects []Object
+	}
+)
+
+// NewGraph returns a graph of the given objects and their dependencies.
+func NewGraph(objs ...Object) *Graph {
+	g := &Graph{index: make(map[Object]int), deps: make(map[Object][]Object)}
+	return g.Add(objs...)
+}
+
+// RealmGraph returns a graph of all objects in the realm, including the tables, views,
+// functions, procedures, sequences, triggers and driver-specific objects of its schemas.
+func RealmGraph(r *Realm) *Graph {
+	g := NewGraph(r.Objects...)
+	for _, s := range r.Schemas {
+		g.addSchema(s)
+	}
+	return g
+}
+
+// SchemaGraph returns a graph of the tables, views, functions, procedures,
+// sequences, triggers and driver-specific objects of the given schema.
+func SchemaGraph(s *Schema) *Graph {
+	return NewGraph().addSchema(s)
+}
+
+// Add adds the given objects to the graph, with the dependencies
+// they hold on other objects. Objects that already exist in the
+// graph are ignored.
+func (g *Graph) Add(objs ...Object) *Graph {
+	for _, o := range objs {
+		if _, ok := g.index[o]; ok {
+			continue
+		}
+		g.index[o] = len(g.objs)
+		g.objs = append(g.objs, o)
+		switch o := o.(type) {
+		case *Table:
+			for _, c := range o.Columns {
+				if c.Type != nil {
+					if t, ok := c.Type.Type.(Object); ok {
+						g.AddDeps(o, t)
+					}
+				}
+			}
+			for _, fk := range o.ForeignKeys {
+				if fk.RefTable != nil {
+					g.AddDeps(o, fk.RefTable)
+				}
+			}
+		case *View:
+			g.AddDeps(o, o.Deps...)
+		case *Func:
+			g.addArgs(o, o.Args)
+			if t, ok := o.Ret.(Object); ok {
+				g.AddDeps(o, t)
+			}
+			g.AddDeps(o, o.Deps...)
+		case *Proc:
+			g.addArgs(o, o.Args)
+			g.AddDeps(o, o.Deps...)
+		case *Sequence:
+			if t, ok := o.Type.(Object); ok {
+				g.AddDeps(o, t)
+			}
+			// Tables are created after the sequences they
+			// own (e.g. used by their default values), and
+			// dropped before them.
+			if o.Owner.T != nil {
+				g.AddDeps(o.Owner.T, o)
+			}
+		case *Trigger:
+			if o.Table != nil {
+				g.AddDeps(o, o.Table)
+			}
+			if o.View != nil {
+				g.AddDeps(o, o.View)
+			}
+			g.AddDeps(o, o.Deps...)
+		}
+	}
+	return g
+}
+
+// AddDeps records that the object o depends on the given objects. Note that
+// dependencies on objects that were not added to the graph are recorded, but
+// are not considered when the graph is sorted, as these objects are expected
+// to exist before (and after) the objects in the graph.
+func (g *Graph) AddDeps(o Object, deps ...Object) *Graph {
+	for _, d := range deps {
+		if d != o && !contains(g.deps[o], d) {
+			g.deps[o] = append(g.deps[o], d)
+		}
+	}
+	return g
+}
+
+// Objects returns the objects in the graph, in the order they were added.
+func (g *Graph) Objects() []Object {
+	return g.objs
+}
+
+// Deps returns the objects that the given object depends on.
+func (g *Graph) Deps(o Object) []Object {
+	return g.deps[o]
+}
+
+// Dependents returns the objects in the graph that depend on the given object.
+func (g *Graph) Dependents(o Object) []Object {
+	var objs []Object
+	for _, d := range g.objs {
+		if contains(g.deps[d], o) {
+			objs = append(objs, d)
+		}
+	}
+	return objs
+}
+
+// Sort returns the objects in the graph in topological order, in which each object
+// follows its dependencies. Hence, it is the order in which the objects can be created.
+// Objects that do not depend on each other keep the order they were added to the graph.
+// A CycleError is returned in case a cycle was detected.
+func (g *Graph) Sort() ([]Object, error) {
+	var (
+		visit    func(Object) error
+		sorted   = make([]Object, 0, len(g.objs))
+		done     = make(map[Object]bool, len(g.objs))
+		progress []Object
+	)
+	visit = func(o Object) error {
+		if done[o] {
+			return nil
+		}
+		for i := range progress {
+			if progress[i] == o {
+				return &CycleError{Objects: append([]Object(nil), progress[i:]...)}
+			}
+		}
+		progress = append(progress, o)
+		for _, d := range g.deps[o] {
+			if _, ok := g.index[d]; !ok {
+				continue
+			}
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		progress = progress[:len(progress)-1]
+		done[o] = true
+		sorted = append(sorted, o)
+		return nil
+	}
+	for _, o := range g.objs {
+		if err := visit(o); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// ReverseSort returns the objects in the graph in reversed topological order, in which
+// each object precedes its dependencies. Hence, it is the order in which the objects can
+// be dropped. A CycleError is returned in case a cycle was detected.
+func (g *Graph) ReverseSort() ([]Object, error) {
+	sorted, err := g.Sort()
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
+	}
+	return sorted, nil
+}
+
+// Cycles returns all groups of objects in the graph that depend on each other, directly
+// or indirectly. i.e. the strongly connected components of the graph that hold more than
+// one object. Objects that depend only on themselves (e.g. self-referencing tables) are
+// not reported, as their references do not affect their order.
+func (g *Graph) Cycles() [][]Object {
+	var (
+		visit   func(Object)
+		cycles  [][]Object
+		stack   []Object
+		idx     = make(map[Object]int, len(g.objs))
+		low     = make(map[Object]int, len(g.objs))
+		onStack = make(map[Object]bool, len(g.objs))
+	)
+	// Tarjan's strongly connected components algorithm.
+	visit = func(o Object) {
+		idx[o], low[o] = len(idx), len(idx)
+		stack = append(stack, o)
+		onStack[o] = true
+		for _, d := range g.deps[o] {
+			if _, ok := g.index[d]; !ok {
+				continue
+			}
+			if _, ok := idx[d]; !ok {
+				visit(d)
+				if low[d] < low[o] {
+					low[o] = low[d]
+				}
+			} else if onStack[d] && idx[d] < low[o] {
+				low[o] = idx[d]
+			}
+		}
+		if low[o] != idx[o] {
+			return
+		}
+		var c []Object
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+			c = append(c, n)
+			if n == o {
+				break
+			}
+		}
+		if len(c) > 1 {
+			// Keep the order the objects were added to the graph.
+			for i := 1; i < len(c); i++ {
+				for j := i; j > 0 && g.index[c[j]] < g.index[c[j-1]]; j-- {
+					c[j], c[j-1] = c[j-1], c[j]
+				}
+			}
+			cycles = append(cycles, c)
+		}
+	}
+	for _, o := range g.objs {
+		if _, ok := idx[o]; !ok {
+			visit(o)
+		}
+	}
+	return cycles
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	names := make([]string, 0, len(e.Objects)+1)
+	for _, o := range e.Objects {
+		names = append(names, objName(o))
+	}
+	if len(e.Objects) > 0 {
+		names = append(names, objName(e.Objects[0]))
+	}
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(names, " -> "))
+}
+
+// addSchema adds the objects of the schema to the graph.
+func (g *Graph) addSchema(s *Schema) *Graph {
+	g.Add(s.Objects...)
+	for _, sq := range s.Sequences {
+		g.Add(sq)
+	}
+	for _, t := range s.Tables {
+		g.Add(t)
+	}
+	for _, v := range s.Views {
+		g.Add(v)
+	}
+	for _, f := range s.Funcs {
+		g.Add(f)
+	}
+	for _, p := range s.Procs {
+		g.Add(p)
+	}
+	for _, t := range s.Tables {
+		for _, tr := range t.Triggers {
+			g.Add(tr)
+		}
+	}
+	for _, v := range s.Views {
+		for _, tr := range v.Triggers {
+			g.Add(tr)
+		}
+	}
+	return g
+}
+
+// addArgs adds the types of the arguments, that are objects (e.g. enums), as dependencies of o.
+func (g *Graph) addArgs(o Object, args []*FuncArg) {
+	for _, a := range args {
+		if t, ok := a.Type.(Object); ok {
+			g.AddDeps(o, t)
+		}
+	}
+}
+
+// objName returns a printable name of the object.
+func objName(o Object) string {
+	switch o := o.(type) {
+	case *Table:
+		return fmt.Sprintf("table %q", o.Name)
+	case *View:
+		if o.Materialized() {
+			return fmt.Sprintf("materialized view %q", o.Name)
+		}
+		return fmt.Sprintf("view %q", o.Name)
+	case *Func:
+		return fmt.Sprintf("function %q", o.Name)
+	case *Proc:
+		return fmt.Sprintf("procedure %q", o.Name)
+	case *Sequence:
+		return fmt.Sprintf("sequence %q", o.Name)
+	case *Trigger:
+		return fmt.Sprintf("trigger %q", o.Name)
+	case *EnumType:
+		return fmt.Sprintf("type %q", o.T)
+	default:
+		return fmt.Sprintf("%T", o)
+	}
+}
+
+// contains reports if the object exists in the list.
+func contains(objs []Object, o Object) bool {
+	for i := range objs {
+		if objs[i] == o {
+			return true
+		}
+	}
+	return false
+}
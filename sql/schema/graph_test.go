@@ -0,0 +1,102 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph_Sort(t *testing.T) {
+	var (
+		status = &schema.EnumType{T: "status", Values: []string{"active", "inactive"}}
+		users  = schema.NewTable("users").
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewColumn("status").SetType(status))
+		pets = schema.NewTable("pets").
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner_id", "int"))
+		v = schema.NewView("active_users", "SELECT * FROM users").AddDeps(users)
+	)
+	// Self-references do not affect the order.
+	users.AddForeignKeys(schema.NewForeignKey("manager").AddColumns(users.Columns[0]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	pets.AddForeignKeys(schema.NewForeignKey("owner").AddColumns(pets.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	s := schema.New("public").AddTables(pets, users).AddViews(v).AddObjects(status)
+
+	g := schema.SchemaGraph(s)
+	require.Equal(t, []schema.Object{status, pets, users, v}, g.Objects())
+	require.Equal(t, []schema.Object{status}, g.Deps(users))
+	require.Equal(t, []schema.Object{pets, v}, g.Dependents(users))
+	sorted, err := g.Sort()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{status, users, pets, v}, sorted)
+	sorted, err = g.ReverseSort()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{v, pets, users, status}, sorted)
+	require.Empty(t, g.Cycles())
+
+	// Objects outside the graph are not sorted.
+	sorted, err = schema.NewGraph(v, pets).Sort()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{v, pets}, sorted)
+
+	// Explicit dependencies.
+	sorted, err = schema.NewGraph(users, status).AddDeps(status, v).Sort()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{status, users}, sorted)
+
+	g = schema.RealmGraph(schema.NewRealm(s))
+	require.Len(t, g.Objects(), 4)
+
+	// Triggers follow their tables and the objects they use.
+	tr := &schema.Trigger{Name: "pets_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}}
+	users.AddTriggers(&schema.Trigger{Name: "users_ai", Deps: []schema.Object{pets}})
+	pets.AddTriggers(tr)
+	require.Equal(t, pets, tr.Table)
+	sorted, err = schema.SchemaGraph(s).ReverseSort()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{users.Triggers[0], tr, v, pets, users, status}, sorted)
+
+	// Functions follow the types of their arguments, and the objects they use.
+	f := &schema.Func{Name: "f", Args: []*schema.FuncArg{{Name: "s", Type: status}}, Deps: []schema.Object{v}}
+	tr.Deps = append(tr.Deps, f)
+	s.AddFuncs(f)
+	g = schema.SchemaGraph(s)
+	require.Equal(t, []schema.Object{status, v}, g.Deps(f))
+	sorted, err = g.Sort()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{status, users, pets, v, f, tr, users.Triggers[0]}, sorted)
+
+	// Tables follow the sequences they own.
+	seq := schema.NewSequence("pets_id_seq").SetOwner(pets, pets.Columns[0])
+	s.AddSequences(seq)
+	sorted, err = schema.SchemaGraph(s).Sort()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{status, seq, users, pets, v, f, tr, users.Triggers[0]}, sorted)
+}
+
+func TestGraph_Cycles(t *testing.T) {
+	var (
+		t1 = schema.NewTable("t1").AddColumns(schema.NewIntColumn("id", "int"))
+		t2 = schema.NewTable("t2").AddColumns(schema.NewIntColumn("id", "int"))
+		t3 = schema.NewTable("t3").AddColumns(schema.NewIntColumn("id", "int"))
+		t4 = schema.NewTable("t4").AddColumns(schema.NewIntColumn("id", "int"))
+	)
+	t1.AddForeignKeys(schema.NewForeignKey("t1_t2").AddColumns(t1.Columns[0]).SetRefTable(t2).AddRefColumns(t2.Columns[0]))
+	t2.AddForeignKeys(schema.NewForeignKey("t2_t3").AddColumns(t2.Columns[0]).SetRefTable(t3).AddRefColumns(t3.Columns[0]))
+	t3.AddForeignKeys(schema.NewForeignKey("t3_t1").AddColumns(t3.Columns[0]).SetRefTable(t1).AddRefColumns(t1.Columns[0]))
+	t4.AddForeignKeys(schema.NewForeignKey("t4_t1").AddColumns(t4.Columns[0]).SetRefTable(t1).AddRefColumns(t1.Columns[0]))
+
+	g := schema.NewGraph(t4, t3, t2, t1)
+	_, err := g.Sort()
+	require.EqualError(t, err, `dependency cycle detected: table "t1" -> table "t2" -> table "t3" -> table "t1"`)
+	var cerr *schema.CycleError
+	require.ErrorAs(t, err, &cerr)
+	require.Equal(t, []schema.Object{t1, t2, t3}, cerr.Objects)
+	_, err = g.ReverseSort()
+	require.Error(t, err)
+	require.Equal(t, [][]schema.Object{{t3, t2, t1}}, g.Cycles())
+}
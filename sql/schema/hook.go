@@ -0,0 +1,122 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// A DiffHook post-processes the changes computed by a Differ, before they are
+	// returned to the caller and planned. A hook may drop, rewrite or inject changes
+	// and return the new list of changes, or return an error to veto the diff. For
+	// example, rejecting destructive changes that were not approved by the user.
+	DiffHook interface {
+		// HookDiff is called with the changes computed by the Differ (or by
+		// the previous hook), and the options that were used for computing them.
+		HookDiff([]Change, *DiffOptions) ([]Change, error)
+	}
+
+	// DiffHookFunc is an adapter to allow the use of ordinary functions as DiffHook.
+	DiffHookFunc func([]Change, *DiffOptions) ([]Change, error)
+
+	// hookDiffer wraps a Differ and runs its hooks on the computed changes.
+	hookDiffer struct {
+		Differ
+		hooks []DiffHook
+	}
+)
+
+// HookDiff calls f(changes, opts).
+func (f DiffHookFunc) HookDiff(changes []Change, opts *DiffOptions) ([]Change, error) {
+	return f(changes, opts)
+}
+
+// WithDiffHooks returns a Differ that wraps d, and runs the given hooks on the changes it computes.
+// Hooks are called in the order they were given, and an error returned by a hook aborts the diff.
+// Note that hooks receive the changes of the diffed element. i.e., TableDiff calls pass the changes
+// of the table, and not a ModifyTable change. Drivers expose their Differ as an embedded field, and
+// it can be wrapped in place:
+//
+//	drv.Differ = schema.WithDiffHooks(drv.Differ, schema.DenyDrops())
+func WithDiffHooks(d Differ, hooks ...DiffHook) Differ {
+	return &hookDiffer{Differ: d, hooks: hooks}
+}
+
+// RealmDiff implements the Differ interface.
+func (d *hookDiffer) RealmDiff(from, to *Realm, opts ...DiffOption) ([]Change, error) {
+	changes, err := d.Differ.RealmDiff(from, to, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return d.hook(changes, opts)
+}
+
+// SchemaDiff implements the Differ interface.
+func (d *hookDiffer) SchemaDiff(from, to *Schema, opts ...DiffOption) ([]Change, error) {
+	changes, err := d.Differ.SchemaDiff(from, to, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return d.hook(changes, opts)
+}
+
+// TableDiff implements the Differ interface.
+func (d *hookDiffer) TableDiff(from, to *Table, opts ...DiffOption) ([]Change, error) {
+	changes, err := d.Differ.TableDiff(from, to, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return d.hook(changes, opts)
+}
+
+// hook runs the hooks on the given changes.
+func (d *hookDiffer) hook(changes []Change, opts []DiffOption) ([]Change, error) {
+	var (
+		err error
+		o   = NewDiffOptions(opts...)
+	)
+	for _, h := range d.hooks {
+		if changes, err = h.HookDiff(changes, o); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// DenyChanges returns a DiffHook that vetoes diffs with changes of the given types, including
+// changes that are nested in a ModifySchema or a ModifyTable. For example, in order to reject
+// changes that drop columns or tables, use:
+//
+//	DenyChanges(&DropColumn{}, &DropTable{})
+func DenyChanges(changes ...Change) DiffHook {
+	var denied func([]Change) error
+	denied = func(cs []Change) error {
+		for _, c := range cs {
+			for _, d := range changes {
+				if reflect.TypeOf(c) == reflect.TypeOf(d) {
+					return fmt.Errorf("sql/schema: change %T is denied", c)
+				}
+			}
+			if err := denied(nestedChanges(c)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return DiffHookFunc(func(cs []Change, _ *DiffOptions) ([]Change, error) {
+		if err := denied(cs); err != nil {
+			return nil, err
+		}
+		return cs, nil
+	})
+}
+
+// DenyDrops returns a DiffHook that vetoes diffs with changes that drop elements.
+// Unlike DiffSkipDrops, which omits such changes, the diff fails with an error.
+func DenyDrops() DiffHook {
+	return DenyChanges(dropChanges()...)
+}
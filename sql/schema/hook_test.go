@@ -0,0 +1,81 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockDiffer returns the same changes for all calls.
+type mockDiffer struct{ changes []schema.Change }
+
+func (d mockDiffer) RealmDiff(_, _ *schema.Realm, _ ...schema.DiffOption) ([]schema.Change, error) {
+	return d.changes, nil
+}
+
+func (d mockDiffer) SchemaDiff(_, _ *schema.Schema, _ ...schema.DiffOption) ([]schema.Change, error) {
+	return d.changes, nil
+}
+
+func (d mockDiffer) TableDiff(_, _ *schema.Table, _ ...schema.DiffOption) ([]schema.Change, error) {
+	return d.changes, nil
+}
+
+func TestWithDiffHooks(t *testing.T) {
+	var (
+		users   = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+		pets    = schema.NewTable("pets")
+		changes = []schema.Change{
+			&schema.AddTable{T: pets},
+			&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.DropColumn{C: users.Columns[0]}}},
+		}
+		d = schema.WithDiffHooks(mockDiffer{changes: changes},
+			// Drop the changes of the "pets" table.
+			schema.DiffHookFunc(func(cs []schema.Change, _ *schema.DiffOptions) ([]schema.Change, error) {
+				var filtered []schema.Change
+				for _, c := range cs {
+					if a, ok := c.(*schema.AddTable); !ok || a.T != pets {
+						filtered = append(filtered, c)
+					}
+				}
+				return filtered, nil
+			}),
+			// Inject a comment to the "users" table.
+			schema.DiffHookFunc(func(cs []schema.Change, opts *schema.DiffOptions) ([]schema.Change, error) {
+				if opts.IgnoreComments {
+					return cs, nil
+				}
+				return append(cs, &schema.ModifyTable{T: users, Changes: []schema.Change{&schema.AddAttr{A: &schema.Comment{Text: "users"}}}}), nil
+			}),
+		)
+	)
+	got, err := d.SchemaDiff(nil, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, changes[1], got[0])
+	require.Equal(t, &schema.AddAttr{A: &schema.Comment{Text: "users"}}, got[1].(*schema.ModifyTable).Changes[0])
+
+	// Hooks receive the options of the diff.
+	got, err = d.RealmDiff(nil, nil, schema.DiffIgnoreComments())
+	require.NoError(t, err)
+	require.Equal(t, changes[1:], got)
+
+	// Nested changes are vetoed.
+	_, err = schema.WithDiffHooks(d, schema.DenyDrops()).RealmDiff(nil, nil)
+	require.EqualError(t, err, "sql/schema: change *schema.DropColumn is denied")
+	_, err = schema.WithDiffHooks(d, schema.DenyChanges(&schema.DropTable{})).TableDiff(nil, nil)
+	require.NoError(t, err)
+
+	// Errors abort the diff.
+	_, err = schema.WithDiffHooks(d, schema.DiffHookFunc(func([]schema.Change, *schema.DiffOptions) ([]schema.Change, error) {
+		return nil, errors.New("vetoed")
+	})).TableDiff(nil, nil)
+	require.EqualError(t, err, "vetoed")
+}
@@ -46,6 +46,24 @@ const (
 
 	// InspectViews enables schema views inspection.
 	InspectViews
+
+	// InspectPrivileges enables the inspection of privileges granted on
+	// schemas and tables. Unlike the modes above, it is not included in the
+	// default (zero) mode and must be requested explicitly by the caller.
+	InspectPrivileges
+
+	// InspectForeign enables the inspection of foreign-data objects (SQL/MED),
+	// such as foreign servers, user mappings and foreign tables. Like the
+	// InspectPrivileges mode, it must be requested explicitly by the caller.
+	InspectForeign
+
+	// InspectPublications enables the inspection of logical replication
+	// publications. It must be requested explicitly by the caller as well.
+	InspectPublications
+
+	// InspectTriggers enables the inspection of table triggers. Like the
+	// InspectViews mode, it is included in the default (zero) mode.
+	InspectTriggers
 )
 
 // Is reports whether the given mode is enabled.
@@ -61,6 +79,15 @@ type (
 		// Tables to inspect. Empty means all tables in the schema.
 		Tables []string
 
+		// Include defines a list of patterns used to select the resources to inspect. Tables,
+		// views, functions, procedures and sequences that do not match any of the patterns are
+		// filtered from inspection. Empty means all resources in the schema. For example:
+		//
+		//	users   // include table (or view) 'users'.
+		//	tmp_*   // include all resources prefixed with 'tmp_'.
+		//
+		Include []string
+
 		// Exclude defines a list of glob patterns used to filter resources from inspection.
 		// The syntax used by the different drivers is implemented as follows:
 		//
@@ -71,6 +98,13 @@ type (
 		//	*.c // the last item defines the filtering; all resourced named 'c' are excluded in all tables.
 		//	*.* // the last item defines the filtering; all resourced under all tables are excluded.
 		//
+		// Views, functions, procedures and sequences are excluded by single-part patterns,
+		// like tables. In Include and Exclude, parts that are wrapped with slashes are used
+		// as regular expressions, and parts that contain dots must be quoted. For example:
+		//
+		//	"/^tmp_\d+$/"   // exclude all resources that match the regular expression.
+		//	t."/^ix_.+$/"  // exclude all resources under 't' that match the regular expression.
+		//
 		Exclude []string
 	}
 
@@ -83,6 +117,15 @@ type (
 		// Schemas to inspect. Empty means all schemas in the realm.
 		Schemas []string
 
+		// Include defines a list of patterns used to select the resources to inspect, using
+		// the syntax of InspectOptions.Include. Empty means all resources in the realm.
+		//
+		//	s     // include schema 's' and all its resources.
+		//	s.t   // include schema 's', and only its resources named 't'.
+		//	*.t*  // include the resources prefixed with 't' in all schemas.
+		//
+		Include []string
+
 		// Exclude defines a list of glob patterns used to filter resources from inspection.
 		// The syntax used by the different drivers is implemented as follows:
 		//
@@ -95,6 +138,7 @@ type (
 		//	*.*.c // the last item defines the filtering; all resourced named 'c' are excluded in all tables.
 		//	*.*.* // the last item defines the filtering; all resources are excluded in all tables.
 		//
+		// See InspectOptions.Exclude for the regular expressions syntax.
 		Exclude []string
 	}
 
@@ -115,6 +159,18 @@ type (
 // "normalizing" schema objects. i.e. converting schema objects defined in natural
 // form to their representation in the database. Thus, two schema objects are equal
 // if their normal forms are equal.
+//
+// Normalizing a desired state before diffing it resolves type aliases (e.g. "integer"
+// and "int"), implicit defaults (e.g. the table charset in MySQL), and the names of
+// indexes and constraints that are generated by the database. Implementations share
+// the following contract:
+//
+//   - The given objects are not modified, and a new normalized copy is returned.
+//   - The names and attributes of the given schemas and realm are preserved, unless
+//     their normal form is returned by the database, and realm-level objects that
+//     cannot be normalized are returned as-is.
+//   - The objects of the returned schemas are linked to each other (e.g. foreign keys
+//     reference the tables of the returned schemas).
 type Normalizer interface {
 	// NormalizeSchema returns the normal representation of a schema.
 	NormalizeSchema(context.Context, *Schema) (*Schema, error)
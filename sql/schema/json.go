@@ -0,0 +1,1307 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+var (
+	jsonTypesMu sync.RWMutex
+	jsonTypes   = make(map[string]reflect.Type)
+)
+
+func init() {
+	RegisterJSON(
+		&BoolType{}, &EnumType{}, &TimeType{}, &JSONType{}, &FloatType{}, &StringType{}, &BinaryType{},
+		&SpatialType{}, &UUIDType{}, &IntegerType{}, &DecimalType{}, &UnsupportedType{},
+		&Literal{}, &RawExpr{},
+		&Check{}, &Enforced{}, &Comment{}, &Charset{}, &Collation{}, &GeneratedExpr{},
+		&ViewCheckOption{}, &Materialized{}, &RawClause{},
+	)
+}
+
+// RegisterJSON records the types of the given values (e.g. driver-specific types, attributes
+// or objects) in the global registry that is used by Realm.UnmarshalJSON for decoding them.
+// Types are recorded by their names, qualified with their package names (e.g. mysql.BitType),
+// and their values are decoded as pointers. If RegisterJSON is called twice with the same
+// type or if a value is nil, it panics.
+func RegisterJSON(vs ...any) {
+	jsonTypesMu.Lock()
+	defer jsonTypesMu.Unlock()
+	for _, v := range vs {
+		if v == nil {
+			panic("sql/schema: RegisterJSON value is nil")
+		}
+		t := indirectType(reflect.TypeOf(v))
+		if _, dup := jsonTypes[t.String()]; dup {
+			panic("sql/schema: RegisterJSON called twice for type " + t.String())
+		}
+		jsonTypes[t.String()] = t
+	}
+}
+
+type (
+	realmJSON struct {
+		Schemas []*schemaJSON `json:"schemas,omitempty"`
+		Attrs   []*valueJSON  `json:"attrs,omitempty"`
+		Objects []*valueJSON  `json:"objects,omitempty"`
+	}
+
+	schemaJSON struct {
+		Name      string          `json:"name"`
+		Tables    []*tableJSON    `json:"tables,omitempty"`
+		Views     []*viewJSON     `json:"views,omitempty"`
+		Funcs     []*funcJSON     `json:"funcs,omitempty"`
+		Procs     []*funcJSON     `json:"procs,omitempty"`
+		Sequences []*sequenceJSON `json:"sequences,omitempty"`
+		Attrs     []*valueJSON    `json:"attrs,omitempty"`
+		Objects   []*valueJSON    `json:"objects,omitempty"`
+	}
+
+	tableJSON struct {
+		Name        string            `json:"name"`
+		Columns     []*columnJSON     `json:"columns,omitempty"`
+		PrimaryKey  *indexJSON        `json:"primary_key,omitempty"`
+		Indexes     []*indexJSON      `json:"indexes,omitempty"`
+		ForeignKeys []*foreignKeyJSON `json:"foreign_keys,omitempty"`
+		Attrs       []*valueJSON      `json:"attrs,omitempty"`
+		Triggers    []*triggerJSON    `json:"triggers,omitempty"`
+	}
+
+	viewJSON struct {
+		Name     string         `json:"name"`
+		Def      string         `json:"def,omitempty"`
+		Columns  []*columnJSON  `json:"columns,omitempty"`
+		Attrs    []*valueJSON   `json:"attrs,omitempty"`
+		Deps     []*valueJSON   `json:"deps,omitempty"`
+		Triggers []*triggerJSON `json:"triggers,omitempty"`
+	}
+
+	columnJSON struct {
+		Name    string          `json:"name"`
+		Type    *columnTypeJSON `json:"type,omitempty"`
+		Default *valueJSON      `json:"default,omitempty"`
+		Attrs   []*valueJSON    `json:"attrs,omitempty"`
+	}
+
+	columnTypeJSON struct {
+		Raw  string     `json:"raw,omitempty"`
+		Null bool       `json:"null,omitempty"`
+		Type *valueJSON `json:"type,omitempty"`
+	}
+
+	indexJSON struct {
+		Name   string           `json:"name,omitempty"`
+		Unique bool             `json:"unique,omitempty"`
+		Parts  []*indexPartJSON `json:"parts,omitempty"`
+		Attrs  []*valueJSON     `json:"attrs,omitempty"`
+	}
+
+	indexPartJSON struct {
+		SeqNo  int          `json:"seq_no,omitempty"`
+		Desc   bool         `json:"desc,omitempty"`
+		Column string       `json:"column,omitempty"`
+		Expr   *valueJSON   `json:"expr,omitempty"`
+		Attrs  []*valueJSON `json:"attrs,omitempty"`
+	}
+
+	foreignKeyJSON struct {
+		Name       string   `json:"name,omitempty"`
+		Columns    []string `json:"columns,omitempty"`
+		References struct {
+			Schema  string   `json:"schema,omitempty"`
+			Table   string   `json:"table"`
+			Columns []string `json:"columns,omitempty"`
+		} `json:"references"`
+		OnUpdate string `json:"on_update,omitempty"`
+		OnDelete string `json:"on_delete,omitempty"`
+	}
+
+	triggerJSON struct {
+		Name       string              `json:"name"`
+		ActionTime string              `json:"action_time,omitempty"`
+		Events     []*triggerEventJSON `json:"events,omitempty"`
+		For        string              `json:"for,omitempty"`
+		Body       string              `json:"body,omitempty"`
+		Attrs      []*valueJSON        `json:"attrs,omitempty"`
+		Deps       []*valueJSON        `json:"deps,omitempty"`
+	}
+
+	triggerEventJSON struct {
+		Name    string   `json:"name"`
+		Columns []string `json:"columns,omitempty"`
+	}
+
+	// funcJSON represents both functions and procedures.
+	funcJSON struct {
+		Name     string         `json:"name"`
+		Args     []*funcArgJSON `json:"args,omitempty"`
+		Ret      *valueJSON     `json:"ret,omitempty"`
+		RetTable []*columnJSON  `json:"ret_table,omitempty"`
+		Lang     string         `json:"lang,omitempty"`
+		Body     string         `json:"body,omitempty"`
+		Attrs    []*valueJSON   `json:"attrs,omitempty"`
+		Deps     []*valueJSON   `json:"deps,omitempty"`
+	}
+
+	funcArgJSON struct {
+		Name    string       `json:"name,omitempty"`
+		Type    *valueJSON   `json:"type,omitempty"`
+		Mode    string       `json:"mode,omitempty"`
+		Default *valueJSON   `json:"default,omitempty"`
+		Attrs   []*valueJSON `json:"attrs,omitempty"`
+	}
+
+	sequenceJSON struct {
+		Name      string       `json:"name"`
+		Type      *valueJSON   `json:"type,omitempty"`
+		Start     int64JSON    `json:"start,omitempty"`
+		Increment int64JSON    `json:"increment,omitempty"`
+		Min       int64JSON    `json:"min,omitempty"`
+		Max       int64JSON    `json:"max,omitempty"`
+		Cache     int64JSON    `json:"cache,omitempty"`
+		Cycle     bool         `json:"cycle,omitempty"`
+		Owner     *refJSON     `json:"owner,omitempty"`
+		Attrs     []*valueJSON `json:"attrs,omitempty"`
+	}
+
+	// valueJSON represents a type, an expression, an attribute or an object. Values
+	// are encoded along with their type names, and references to objects of the realm
+	// are encoded by their names (or positions).
+	valueJSON struct {
+		Kind  string          `json:"kind,omitempty"`
+		Value json.RawMessage `json:"value,omitempty"`
+		Ref   *refJSON        `json:"ref,omitempty"`
+	}
+
+	// int64JSON is an int64 that is encoded as a JSON number, but can also be
+	// decoded from a JSON string, as int64 values are commonly encoded by JSON
+	// encoders that do not support 64-bit integers (e.g. the proto3 JSON mapping).
+	int64JSON int64
+
+	// refJSON represents a reference to an object of the realm.
+	refJSON struct {
+		Kind   string `json:"kind,omitempty"` // table, view, func, proc, sequence, trigger, column or object.
+		Schema string `json:"schema,omitempty"`
+		Table  string `json:"table,omitempty"` // Table or view of triggers and columns.
+		Name   string `json:"name,omitempty"`
+		Index  int    `json:"index,omitempty"` // Position of driver-specific objects.
+	}
+
+	// jsonEncoder holds the state of a Realm encoding.
+	jsonEncoder struct {
+		objects map[Object]*refJSON
+	}
+
+	// jsonDecoder holds the state of a Realm decoding.
+	jsonDecoder struct {
+		realm *Realm
+		// References are linked after all objects were decoded.
+		links []func() error
+	}
+)
+
+// List of reference kinds.
+const (
+	refTable    = "table"
+	refView     = "view"
+	refFunc     = "func"
+	refProc     = "proc"
+	refSequence = "sequence"
+	refTrigger  = "trigger"
+	refColumn   = "column"
+	refObject   = "object"
+)
+
+// MarshalJSON implements json.Marshaler. The realm is encoded as a tree of its schemas and
+// their objects, where the references between objects (e.g. the referenced table of a foreign
+// key, or the dependencies of a view) are encoded by their names, and types, expressions,
+// attributes and driver-specific objects are encoded along with their type names. Objects are
+// encoded in their realm order, and therefore, the encoding of a realm is stable.
+func (r *Realm) MarshalJSON() ([]byte, error) {
+	v, err := encodeRealm(r)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The references between the objects of the
+// realm are linked to the decoded objects, and types, attributes and driver-specific
+// objects are decoded using the types that were recorded by RegisterJSON. Hence, the
+// driver packages that define them must be imported.
+func (r *Realm) UnmarshalJSON(b []byte) error {
+	var v realmJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return decodeRealm(&v, r)
+}
+
+// encodeRealm returns the tree representation of the realm.
+func encodeRealm(r *Realm) (*realmJSON, error) {
+	e := &jsonEncoder{objects: make(map[Object]*refJSON)}
+	for i, o := range r.Objects {
+		e.addObject(o, &refJSON{Kind: refObject, Index: i})
+	}
+	for _, s := range r.Schemas {
+		for i, o := range s.Objects {
+			e.addObject(o, &refJSON{Kind: refObject, Schema: s.Name, Index: i})
+		}
+	}
+	return e.realm(r)
+}
+
+// decodeRealm decodes the tree representation of a realm into r.
+func decodeRealm(v *realmJSON, r *Realm) error {
+	*r = Realm{}
+	d := &jsonDecoder{realm: r}
+	if err := d.realmJSON(v); err != nil {
+		return err
+	}
+	for _, l := range d.links {
+		if err := l(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *jsonEncoder) addObject(o Object, r *refJSON) {
+	// Only pointers can be referenced by other objects.
+	if v := reflect.ValueOf(o); v.Kind() == reflect.Ptr && !v.IsNil() {
+		e.objects[o] = r
+	}
+}
+
+func (e *jsonEncoder) realm(r *Realm) (*realmJSON, error) {
+	var (
+		err error
+		v   = &realmJSON{}
+	)
+	if v.Attrs, err = encodeValues(e, r.Attrs, nil); err != nil {
+		return nil, err
+	}
+	if v.Objects, err = e.objectValues(r.Objects); err != nil {
+		return nil, err
+	}
+	for _, s := range r.Schemas {
+		sv, err := e.schema(s)
+		if err != nil {
+			return nil, err
+		}
+		v.Schemas = append(v.Schemas, sv)
+	}
+	return v, nil
+}
+
+func (e *jsonEncoder) schema(s *Schema) (*schemaJSON, error) {
+	var (
+		err error
+		v   = &schemaJSON{Name: s.Name}
+	)
+	if v.Attrs, err = encodeValues(e, s.Attrs, nil); err != nil {
+		return nil, err
+	}
+	if v.Objects, err = e.objectValues(s.Objects); err != nil {
+		return nil, err
+	}
+	for _, t := range s.Tables {
+		tv, err := e.table(t)
+		if err != nil {
+			return nil, err
+		}
+		v.Tables = append(v.Tables, tv)
+	}
+	for _, vw := range s.Views {
+		vv, err := e.view(vw)
+		if err != nil {
+			return nil, err
+		}
+		v.Views = append(v.Views, vv)
+	}
+	for _, f := range s.Funcs {
+		fv, err := e.fn(f.Name, f.Args, f.Ret, f.RetTable, f.Lang, f.Body, f.Attrs, f.Deps)
+		if err != nil {
+			return nil, err
+		}
+		v.Funcs = append(v.Funcs, fv)
+	}
+	for _, p := range s.Procs {
+		pv, err := e.fn(p.Name, p.Args, nil, nil, p.Lang, p.Body, p.Attrs, p.Deps)
+		if err != nil {
+			return nil, err
+		}
+		v.Procs = append(v.Procs, pv)
+	}
+	for _, sq := range s.Sequences {
+		sv, err := e.sequence(sq)
+		if err != nil {
+			return nil, err
+		}
+		v.Sequences = append(v.Sequences, sv)
+	}
+	return v, nil
+}
+
+func (e *jsonEncoder) table(t *Table) (*tableJSON, error) {
+	var (
+		err error
+		v   = &tableJSON{Name: t.Name}
+	)
+	for _, c := range t.Columns {
+		cv, err := e.column(c, t)
+		if err != nil {
+			return nil, err
+		}
+		v.Columns = append(v.Columns, cv)
+	}
+	if t.PrimaryKey != nil {
+		if v.PrimaryKey, err = e.index(t.PrimaryKey, t); err != nil {
+			return nil, err
+		}
+	}
+	for _, idx := range t.Indexes {
+		iv, err := e.index(idx, t)
+		if err != nil {
+			return nil, err
+		}
+		v.Indexes = append(v.Indexes, iv)
+	}
+	for _, fk := range t.ForeignKeys {
+		fv := &foreignKeyJSON{Name: fk.Symbol, Columns: columnNames(fk.Columns), OnUpdate: string(fk.OnUpdate), OnDelete: string(fk.OnDelete)}
+		if fk.RefTable == nil {
+			return nil, fmt.Errorf("sql/schema: missing referenced table for foreign key %q", fk.Symbol)
+		}
+		// Referenced tables that are not linked to a schema reside in the schema of the table.
+		fv.References.Schema, fv.References.Table = schemaName(t.Schema), fk.RefTable.Name
+		if fk.RefTable.Schema != nil {
+			fv.References.Schema = fk.RefTable.Schema.Name
+		}
+		fv.References.Columns = columnNames(fk.RefColumns)
+		v.ForeignKeys = append(v.ForeignKeys, fv)
+	}
+	if v.Attrs, err = encodeValues(e, t.Attrs, t); err != nil {
+		return nil, err
+	}
+	if v.Triggers, err = e.triggers(t.Triggers, t); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (e *jsonEncoder) view(vw *View) (*viewJSON, error) {
+	var (
+		err error
+		v   = &viewJSON{Name: vw.Name, Def: vw.Def}
+	)
+	for _, c := range vw.Columns {
+		cv, err := e.column(c, nil)
+		if err != nil {
+			return nil, err
+		}
+		v.Columns = append(v.Columns, cv)
+	}
+	if v.Attrs, err = encodeValues(e, vw.Attrs, nil); err != nil {
+		return nil, err
+	}
+	if v.Deps, err = encodeValues(e, vw.Deps, nil); err != nil {
+		return nil, err
+	}
+	if v.Triggers, err = e.triggers(vw.Triggers, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (e *jsonEncoder) column(c *Column, t *Table) (*columnJSON, error) {
+	var (
+		err error
+		v   = &columnJSON{Name: c.Name}
+	)
+	if c.Type != nil {
+		v.Type = &columnTypeJSON{Raw: c.Type.Raw, Null: c.Type.Null}
+		if v.Type.Type, err = e.value(c.Type.Type, t); err != nil {
+			return nil, err
+		}
+	}
+	if v.Default, err = e.value(c.Default, t); err != nil {
+		return nil, err
+	}
+	if v.Attrs, err = encodeValues(e, c.Attrs, t); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (e *jsonEncoder) index(idx *Index, t *Table) (*indexJSON, error) {
+	var (
+		err error
+		v   = &indexJSON{Name: idx.Name, Unique: idx.Unique}
+	)
+	for _, p := range idx.Parts {
+		pv := &indexPartJSON{SeqNo: p.SeqNo, Desc: p.Desc}
+		if p.C != nil {
+			pv.Column = p.C.Name
+		}
+		if pv.Expr, err = e.value(p.X, t); err != nil {
+			return nil, err
+		}
+		if pv.Attrs, err = encodeValues(e, p.Attrs, t); err != nil {
+			return nil, err
+		}
+		v.Parts = append(v.Parts, pv)
+	}
+	if v.Attrs, err = encodeValues(e, idx.Attrs, t); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (e *jsonEncoder) triggers(trs []*Trigger, t *Table) ([]*triggerJSON, error) {
+	var vs []*triggerJSON
+	for _, tr := range trs {
+		var (
+			err error
+			v   = &triggerJSON{Name: tr.Name, ActionTime: string(tr.ActionTime), For: string(tr.For), Body: tr.Body}
+		)
+		for _, ev := range tr.Events {
+			v.Events = append(v.Events, &triggerEventJSON{Name: ev.Name, Columns: columnNames(ev.Columns)})
+		}
+		if v.Attrs, err = encodeValues(e, tr.Attrs, t); err != nil {
+			return nil, err
+		}
+		if v.Deps, err = encodeValues(e, tr.Deps, t); err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+func (e *jsonEncoder) fn(name string, args []*FuncArg, ret Type, retT []*Column, lang, body string, attrs []Attr, deps []Object) (*funcJSON, error) {
+	var (
+		err error
+		v   = &funcJSON{Name: name, Lang: lang, Body: body}
+	)
+	for _, a := range args {
+		av := &funcArgJSON{Name: a.Name, Mode: string(a.Mode)}
+		if av.Type, err = e.value(a.Type, nil); err != nil {
+			return nil, err
+		}
+		if av.Default, err = e.value(a.Default, nil); err != nil {
+			return nil, err
+		}
+		if av.Attrs, err = encodeValues(e, a.Attrs, nil); err != nil {
+			return nil, err
+		}
+		v.Args = append(v.Args, av)
+	}
+	if v.Ret, err = e.value(ret, nil); err != nil {
+		return nil, err
+	}
+	for _, c := range retT {
+		cv, err := e.column(c, nil)
+		if err != nil {
+			return nil, err
+		}
+		v.RetTable = append(v.RetTable, cv)
+	}
+	if v.Attrs, err = encodeValues(e, attrs, nil); err != nil {
+		return nil, err
+	}
+	if v.Deps, err = encodeValues(e, deps, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (e *jsonEncoder) sequence(s *Sequence) (*sequenceJSON, error) {
+	var (
+		err error
+		v   = &sequenceJSON{
+			Name: s.Name, Start: int64JSON(s.Start), Increment: int64JSON(s.Increment),
+			Min: int64JSON(s.Min), Max: int64JSON(s.Max), Cache: int64JSON(s.Cache), Cycle: s.Cycle,
+		}
+	)
+	if v.Type, err = e.value(s.Type, nil); err != nil {
+		return nil, err
+	}
+	if s.Owner.T != nil && s.Owner.C != nil {
+		v.Owner = &refJSON{Kind: refColumn, Schema: schemaName(s.Owner.T.Schema), Table: s.Owner.T.Name, Name: s.Owner.C.Name}
+	}
+	if v.Attrs, err = encodeValues(e, s.Attrs, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// objectValues encodes the driver-specific objects of a schema or a realm.
+func (e *jsonEncoder) objectValues(objs []Object) ([]*valueJSON, error) {
+	vs := make([]*valueJSON, 0, len(objs))
+	for _, o := range objs {
+		v, err := e.encodeValue(o, nil)
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	return vs, nil
+}
+
+func encodeValues[T any](e *jsonEncoder, s []T, t *Table) ([]*valueJSON, error) {
+	var vs []*valueJSON
+	for i := range s {
+		v, err := e.value(s[i], t)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			vs = append(vs, v)
+		}
+	}
+	return vs, nil
+}
+
+// value encodes the value, or a reference to it if it is an object of the realm.
+func (e *jsonEncoder) value(x any, t *Table) (*valueJSON, error) {
+	if x == nil {
+		return nil, nil
+	}
+	if r := e.ref(x); r != nil {
+		return &valueJSON{Ref: r}, nil
+	}
+	return e.encodeValue(x, t)
+}
+
+// encodeValue encodes the value along with its type name.
+func (e *jsonEncoder) encodeValue(x any, t *Table) (*valueJSON, error) {
+	rv := reflect.ValueOf(x)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+	enc, err := e.encode(reflect.Indirect(rv), t)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(enc)
+	if err != nil {
+		return nil, err
+	}
+	v := &valueJSON{Kind: indirectType(rv.Type()).String()}
+	if s := string(b); s != "{}" && s != "null" {
+		v.Value = b
+	}
+	return v, nil
+}
+
+// ref returns a reference to the value, if it is an object of the realm.
+func (e *jsonEncoder) ref(x any) *refJSON {
+	switch o := x.(type) {
+	case *Table:
+		return &refJSON{Kind: refTable, Schema: schemaName(o.Schema), Name: o.Name}
+	case *View:
+		return &refJSON{Kind: refView, Schema: schemaName(o.Schema), Name: o.Name}
+	case *Func:
+		return &refJSON{Kind: refFunc, Schema: schemaName(o.Schema), Name: o.Name}
+	case *Proc:
+		return &refJSON{Kind: refProc, Schema: schemaName(o.Schema), Name: o.Name}
+	case *Sequence:
+		return &refJSON{Kind: refSequence, Schema: schemaName(o.Schema), Name: o.Name}
+	case *Trigger:
+		r := &refJSON{Kind: refTrigger, Name: o.Name}
+		switch {
+		case o.Table != nil:
+			r.Schema, r.Table = schemaName(o.Table.Schema), o.Table.Name
+		case o.View != nil:
+			r.Schema, r.Table = schemaName(o.View.Schema), o.View.Name
+		}
+		return r
+	case Object:
+		if v := reflect.ValueOf(o); v.Kind() == reflect.Ptr && !v.IsNil() {
+			return e.objects[o]
+		}
+	}
+	return nil
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// encode returns the JSON representation of the value. Structs are encoded by their exported
+// non-zero fields, columns of the table t are encoded by their names, and references to other
+// schemas and objects are encoded by their names.
+func (e *jsonEncoder) encode(rv reflect.Value, t *Table) (any, error) {
+	if rv.Type().Implements(jsonMarshalerType) {
+		return rv.Interface(), nil
+	}
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return e.value(rv.Interface(), t)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		switch p := rv.Interface().(type) {
+		case *Column:
+			if t != nil && hasColumn(t, p) {
+				return p.Name, nil
+			}
+			// Columns that are not part of the table are owned by the value.
+			return e.column(p, nil)
+		case *Schema:
+			return p.Name, nil
+		case *Table, *View, *Func, *Proc, *Sequence, *Trigger:
+			return e.ref(p), nil
+		}
+		return e.encode(rv.Elem(), t)
+	case reflect.Struct:
+		m := make(map[string]any)
+		for i := 0; i < rv.NumField(); i++ {
+			f := rv.Type().Field(i)
+			if !f.IsExported() || rv.Field(i).IsZero() {
+				continue
+			}
+			x, err := e.encode(rv.Field(i), t)
+			if err != nil {
+				return nil, err
+			}
+			m[f.Name] = x
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface(), nil
+		}
+		s := make([]any, rv.Len())
+		for i := range s {
+			x, err := e.encode(rv.Index(i), t)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = x
+		}
+		return s, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("sql/schema: unsupported map type %s", rv.Type())
+		}
+		m := make(map[string]any, rv.Len())
+		for it := rv.MapRange(); it.Next(); {
+			x, err := e.encode(it.Value(), t)
+			if err != nil {
+				return nil, err
+			}
+			m[it.Key().String()] = x
+		}
+		return m, nil
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return nil, fmt.Errorf("sql/schema: unsupported type %s", rv.Type())
+	default:
+		return rv.Interface(), nil
+	}
+}
+
+func (d *jsonDecoder) realmJSON(v *realmJSON) error {
+	var err error
+	if d.realm.Attrs, err = decodeValues[Attr](d, v.Attrs, nil); err != nil {
+		return err
+	}
+	if d.realm.Objects, err = decodeValues[Object](d, v.Objects, nil); err != nil {
+		return err
+	}
+	for _, sv := range v.Schemas {
+		s, err := d.schema(sv)
+		if err != nil {
+			return err
+		}
+		d.realm.AddSchemas(s)
+	}
+	return nil
+}
+
+func (d *jsonDecoder) schema(v *schemaJSON) (*Schema, error) {
+	var (
+		err error
+		s   = New(v.Name)
+	)
+	if s.Attrs, err = decodeValues[Attr](d, v.Attrs, nil); err != nil {
+		return nil, err
+	}
+	if s.Objects, err = decodeValues[Object](d, v.Objects, nil); err != nil {
+		return nil, err
+	}
+	for _, tv := range v.Tables {
+		t, err := d.table(tv)
+		if err != nil {
+			return nil, err
+		}
+		s.AddTables(t)
+	}
+	for _, vv := range v.Views {
+		vw, err := d.view(vv)
+		if err != nil {
+			return nil, err
+		}
+		s.AddViews(vw)
+	}
+	for _, fv := range v.Funcs {
+		f := &Func{Name: fv.Name, Lang: fv.Lang, Body: fv.Body}
+		if err := d.fn(fv, &f.Args, &f.Attrs, &f.Deps); err != nil {
+			return nil, err
+		}
+		if err := d.value(fv.Ret, nil, func(x any) error { return assign(&f.Ret, x) }); err != nil {
+			return nil, err
+		}
+		for _, cv := range fv.RetTable {
+			c, err := d.column(cv, nil)
+			if err != nil {
+				return nil, err
+			}
+			f.RetTable = append(f.RetTable, c)
+		}
+		s.AddFuncs(f)
+	}
+	for _, pv := range v.Procs {
+		p := &Proc{Name: pv.Name, Lang: pv.Lang, Body: pv.Body}
+		if err := d.fn(pv, &p.Args, &p.Attrs, &p.Deps); err != nil {
+			return nil, err
+		}
+		s.AddProcs(p)
+	}
+	for _, sv := range v.Sequences {
+		sq, err := d.sequence(sv)
+		if err != nil {
+			return nil, err
+		}
+		s.AddSequences(sq)
+	}
+	return s, nil
+}
+
+func (d *jsonDecoder) table(v *tableJSON) (*Table, error) {
+	var (
+		err error
+		t   = NewTable(v.Name)
+	)
+	for _, cv := range v.Columns {
+		c, err := d.column(cv, t)
+		if err != nil {
+			return nil, err
+		}
+		t.AddColumns(c)
+	}
+	if v.PrimaryKey != nil {
+		pk, err := d.index(v.PrimaryKey, t)
+		if err != nil {
+			return nil, err
+		}
+		t.SetPrimaryKey(pk)
+	}
+	for _, iv := range v.Indexes {
+		idx, err := d.index(iv, t)
+		if err != nil {
+			return nil, err
+		}
+		t.AddIndexes(idx)
+	}
+	for _, fv := range v.ForeignKeys {
+		fk := NewForeignKey(fv.Name).SetOnUpdate(ReferenceOption(fv.OnUpdate)).SetOnDelete(ReferenceOption(fv.OnDelete))
+		columns, err := tableColumns(t, fv.Columns)
+		if err != nil {
+			return nil, err
+		}
+		t.AddForeignKeys(fk.AddColumns(columns...))
+		ref := fv.References
+		d.links = append(d.links, func() error {
+			o, err := d.resolve(&refJSON{Kind: refTable, Schema: ref.Schema, Name: ref.Table})
+			if err != nil {
+				return err
+			}
+			rt := o.(*Table)
+			columns, err := tableColumns(rt, ref.Columns)
+			if err != nil {
+				return err
+			}
+			fk.SetRefTable(rt).AddRefColumns(columns...)
+			return nil
+		})
+	}
+	if t.Attrs, err = decodeValues[Attr](d, v.Attrs, t); err != nil {
+		return nil, err
+	}
+	triggers, err := d.triggers(v.Triggers, t)
+	if err != nil {
+		return nil, err
+	}
+	return t.AddTriggers(triggers...), nil
+}
+
+func (d *jsonDecoder) view(v *viewJSON) (*View, error) {
+	var (
+		err error
+		vw  = NewView(v.Name, v.Def)
+	)
+	for _, cv := range v.Columns {
+		c, err := d.column(cv, nil)
+		if err != nil {
+			return nil, err
+		}
+		vw.AddColumns(c)
+	}
+	if vw.Attrs, err = decodeValues[Attr](d, v.Attrs, nil); err != nil {
+		return nil, err
+	}
+	if vw.Deps, err = decodeValues[Object](d, v.Deps, nil); err != nil {
+		return nil, err
+	}
+	triggers, err := d.triggers(v.Triggers, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vw.AddTriggers(triggers...), nil
+}
+
+func (d *jsonDecoder) column(v *columnJSON, t *Table) (*Column, error) {
+	var (
+		err error
+		c   = NewColumn(v.Name)
+	)
+	if v.Type != nil {
+		c.Type = &ColumnType{Raw: v.Type.Raw, Null: v.Type.Null}
+		if err := d.value(v.Type.Type, t, func(x any) error { return assign(&c.Type.Type, x) }); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.value(v.Default, t, func(x any) error { return assign(&c.Default, x) }); err != nil {
+		return nil, err
+	}
+	if c.Attrs, err = decodeValues[Attr](d, v.Attrs, t); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (d *jsonDecoder) index(v *indexJSON, t *Table) (*Index, error) {
+	var (
+		err error
+		idx = &Index{Name: v.Name, Unique: v.Unique}
+	)
+	for _, pv := range v.Parts {
+		p := &IndexPart{SeqNo: pv.SeqNo, Desc: pv.Desc}
+		if pv.Column != "" {
+			c, ok := t.Column(pv.Column)
+			if !ok {
+				return nil, fmt.Errorf("sql/schema: column %q of index %q was not found in table %q", pv.Column, v.Name, t.Name)
+			}
+			if p.C = c; !c.hasIndex(idx) {
+				c.Indexes = append(c.Indexes, idx)
+			}
+		}
+		if err := d.value(pv.Expr, t, func(x any) error { return assign(&p.X, x) }); err != nil {
+			return nil, err
+		}
+		if p.Attrs, err = decodeValues[Attr](d, pv.Attrs, t); err != nil {
+			return nil, err
+		}
+		idx.Parts = append(idx.Parts, p)
+	}
+	if idx.Attrs, err = decodeValues[Attr](d, v.Attrs, t); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+func (d *jsonDecoder) triggers(vs []*triggerJSON, t *Table) ([]*Trigger, error) {
+	triggers := make([]*Trigger, 0, len(vs))
+	for _, v := range vs {
+		var (
+			err error
+			tr  = &Trigger{Name: v.Name, ActionTime: TriggerTime(v.ActionTime), For: TriggerFor(v.For), Body: v.Body}
+		)
+		for _, ev := range v.Events {
+			e := TriggerEvent{Name: ev.Name}
+			if len(ev.Columns) > 0 {
+				if t == nil {
+					return nil, fmt.Errorf("sql/schema: unexpected columns for trigger %q", v.Name)
+				}
+				if e.Columns, err = tableColumns(t, ev.Columns); err != nil {
+					return nil, err
+				}
+			}
+			tr.Events = append(tr.Events, e)
+		}
+		if tr.Attrs, err = decodeValues[Attr](d, v.Attrs, t); err != nil {
+			return nil, err
+		}
+		if tr.Deps, err = decodeValues[Object](d, v.Deps, t); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, tr)
+	}
+	return triggers, nil
+}
+
+// fn decodes the shared parts of functions and procedures.
+func (d *jsonDecoder) fn(v *funcJSON, args *[]*FuncArg, attrs *[]Attr, deps *[]Object) error {
+	var err error
+	for _, av := range v.Args {
+		a := &FuncArg{Name: av.Name, Mode: FuncArgMode(av.Mode)}
+		if err := d.value(av.Type, nil, func(x any) error { return assign(&a.Type, x) }); err != nil {
+			return err
+		}
+		if err := d.value(av.Default, nil, func(x any) error { return assign(&a.Default, x) }); err != nil {
+			return err
+		}
+		if a.Attrs, err = decodeValues[Attr](d, av.Attrs, nil); err != nil {
+			return err
+		}
+		*args = append(*args, a)
+	}
+	if *attrs, err = decodeValues[Attr](d, v.Attrs, nil); err != nil {
+		return err
+	}
+	*deps, err = decodeValues[Object](d, v.Deps, nil)
+	return err
+}
+
+func (d *jsonDecoder) sequence(v *sequenceJSON) (*Sequence, error) {
+	var (
+		err error
+		s   = &Sequence{
+			Name: v.Name, Start: int64(v.Start), Increment: int64(v.Increment),
+			Min: int64(v.Min), Max: int64(v.Max), Cache: int64(v.Cache), Cycle: v.Cycle,
+		}
+	)
+	if err := d.value(v.Type, nil, func(x any) error { return assign(&s.Type, x) }); err != nil {
+		return nil, err
+	}
+	if s.Attrs, err = decodeValues[Attr](d, v.Attrs, nil); err != nil {
+		return nil, err
+	}
+	if r := v.Owner; r != nil {
+		d.links = append(d.links, func() error {
+			o, err := d.resolve(&refJSON{Kind: refTable, Schema: r.Schema, Name: r.Table})
+			if err != nil {
+				return err
+			}
+			t := o.(*Table)
+			c, ok := t.Column(r.Name)
+			if !ok {
+				return fmt.Errorf("sql/schema: owner column %q of sequence %q was not found", r.Name, v.Name)
+			}
+			s.SetOwner(t, c)
+			return nil
+		})
+	}
+	return s, nil
+}
+
+func decodeValues[T any](d *jsonDecoder, vs []*valueJSON, t *Table) ([]T, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	s := make([]T, len(vs))
+	for i, v := range vs {
+		i := i
+		if err := d.value(v, t, func(x any) error { return assign(&s[i], x) }); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// value decodes the value, and calls set with the result. References
+// to other objects are set after all objects of the realm were decoded.
+func (d *jsonDecoder) value(v *valueJSON, t *Table, set func(any) error) error {
+	if v == nil {
+		return nil
+	}
+	if r := v.Ref; r != nil {
+		d.links = append(d.links, func() error {
+			o, err := d.resolve(r)
+			if err != nil {
+				return err
+			}
+			return set(o)
+		})
+		return nil
+	}
+	jsonTypesMu.RLock()
+	typ, ok := jsonTypes[v.Kind]
+	jsonTypesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("sql/schema: unknown type %q. Was the package that defines it imported?", v.Kind)
+	}
+	p := reflect.New(typ)
+	if err := d.decode(v.Value, p.Elem(), t); err != nil {
+		return err
+	}
+	return set(p.Interface())
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	columnPtrType       = reflect.TypeOf((*Column)(nil))
+	schemaPtrType       = reflect.TypeOf((*Schema)(nil))
+	refPtrTypes         = map[reflect.Type]string{
+		reflect.TypeOf((*Table)(nil)):    refTable,
+		reflect.TypeOf((*View)(nil)):     refView,
+		reflect.TypeOf((*Func)(nil)):     refFunc,
+		reflect.TypeOf((*Proc)(nil)):     refProc,
+		reflect.TypeOf((*Sequence)(nil)): refSequence,
+		reflect.TypeOf((*Trigger)(nil)):  refTrigger,
+	}
+)
+
+// decode decodes the JSON data into rv. It is the inverse of jsonEncoder.encode.
+func (d *jsonDecoder) decode(data json.RawMessage, rv reflect.Value, t *Table) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	if reflect.PointerTo(rv.Type()).Implements(jsonUnmarshalerType) {
+		return json.Unmarshal(data, rv.Addr().Interface())
+	}
+	switch rv.Kind() {
+	case reflect.Interface:
+		var v valueJSON
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		return d.value(&v, t, func(x any) error { return assignValue(rv, x) })
+	case reflect.Ptr:
+		switch typ := rv.Type(); {
+		case typ == columnPtrType:
+			var name string
+			if err := json.Unmarshal(data, &name); err == nil {
+				if t == nil {
+					return fmt.Errorf("sql/schema: unexpected reference to column %q", name)
+				}
+				c, ok := t.Column(name)
+				if !ok {
+					return fmt.Errorf("sql/schema: column %q was not found in table %q", name, t.Name)
+				}
+				rv.Set(reflect.ValueOf(c))
+				return nil
+			}
+			var v columnJSON
+			if err := json.Unmarshal(data, &v); err != nil {
+				return err
+			}
+			c, err := d.column(&v, nil)
+			if err != nil {
+				return err
+			}
+			rv.Set(reflect.ValueOf(c))
+		case typ == schemaPtrType:
+			var name string
+			if err := json.Unmarshal(data, &name); err != nil {
+				return err
+			}
+			d.links = append(d.links, func() error {
+				s, ok := d.realm.Schema(name)
+				if !ok {
+					return fmt.Errorf("sql/schema: schema %q was not found", name)
+				}
+				rv.Set(reflect.ValueOf(s))
+				return nil
+			})
+		case refPtrTypes[typ] != "":
+			var r refJSON
+			if err := json.Unmarshal(data, &r); err != nil {
+				return err
+			}
+			d.links = append(d.links, func() error {
+				o, err := d.resolve(&r)
+				if err != nil {
+					return err
+				}
+				return assignValue(rv, o)
+			})
+		default:
+			p := reflect.New(typ.Elem())
+			if err := d.decode(data, p.Elem(), t); err != nil {
+				return err
+			}
+			rv.Set(p)
+		}
+	case reflect.Struct:
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		for i := 0; i < rv.NumField(); i++ {
+			f := rv.Type().Field(i)
+			if raw, ok := m[f.Name]; ok && f.IsExported() {
+				if err := d.decode(raw, rv.Field(i), t); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return json.Unmarshal(data, rv.Addr().Interface())
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(rv.Type(), len(items), len(items))
+		for i := range items {
+			if err := d.decode(items[i], s.Index(i), t); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+	case reflect.Array:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		for i := 0; i < len(items) && i < rv.Len(); i++ {
+			if err := d.decode(items[i], rv.Index(i), t); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		var items map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(rv.Type(), len(items))
+		for k, raw := range items {
+			v := reflect.New(rv.Type().Elem()).Elem()
+			if err := d.decode(raw, v, t); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), v)
+		}
+		rv.Set(m)
+	default:
+		return json.Unmarshal(data, rv.Addr().Interface())
+	}
+	return nil
+}
+
+// resolve returns the object of the realm that is referenced by r.
+func (d *jsonDecoder) resolve(r *refJSON) (any, error) {
+	if r.Kind == refObject && r.Schema == "" {
+		if r.Index < 0 || r.Index >= len(d.realm.Objects) {
+			return nil, fmt.Errorf("sql/schema: realm object %d was not found", r.Index)
+		}
+		return d.realm.Objects[r.Index], nil
+	}
+	s, ok := d.realm.Schema(r.Schema)
+	if !ok {
+		return nil, fmt.Errorf("sql/schema: schema %q was not found", r.Schema)
+	}
+	var o any
+	switch r.Kind {
+	case refTable:
+		o, ok = s.Table(r.Name)
+	case refView:
+		o, ok = s.View(r.Name)
+	case refFunc:
+		o, ok = s.Func(r.Name)
+	case refProc:
+		o, ok = s.Proc(r.Name)
+	case refSequence:
+		o, ok = s.Sequence(r.Name)
+	case refTrigger:
+		if t, ok1 := s.Table(r.Table); ok1 {
+			o, ok = t.Trigger(r.Name)
+		} else if v, ok1 := s.View(r.Table); ok1 {
+			o, ok = v.Trigger(r.Name)
+		}
+	case refObject:
+		if ok = r.Index >= 0 && r.Index < len(s.Objects); ok {
+			o = s.Objects[r.Index]
+		}
+	default:
+		return nil, fmt.Errorf("sql/schema: unknown reference kind %q", r.Kind)
+	}
+	if !ok {
+		return nil, fmt.Errorf("sql/schema: %s %q was not found in schema %q", r.Kind, r.Name, r.Schema)
+	}
+	return o, nil
+}
+
+// assign sets x to the value that is pointed by p.
+func assign[T any](p *T, x any) error {
+	v, ok := x.(T)
+	if !ok {
+		return fmt.Errorf("sql/schema: unexpected type %T for %s", x, reflect.TypeOf(p).Elem())
+	}
+	*p = v
+	return nil
+}
+
+// assignValue sets x to rv.
+func assignValue(rv reflect.Value, x any) error {
+	xv := reflect.ValueOf(x)
+	if !xv.Type().AssignableTo(rv.Type()) {
+		return fmt.Errorf("sql/schema: unexpected type %T for %s", x, rv.Type())
+	}
+	rv.Set(xv)
+	return nil
+}
+
+// tableColumns returns the columns of the table with the given names.
+func tableColumns(t *Table, names []string) ([]*Column, error) {
+	columns := make([]*Column, 0, len(names))
+	for _, name := range names {
+		c, ok := t.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("sql/schema: column %q was not found in table %q", name, t.Name)
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+func columnNames(columns []*Column) []string {
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.Name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *int64JSON) UnmarshalJSON(b []byte) error {
+	if len(b) > 1 && b[0] == '"' {
+		b = bytes.Trim(b, `"`)
+	}
+	if string(b) == "null" {
+		return nil
+	}
+	v, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return fmt.Errorf("sql/schema: invalid int64 value %q: %w", b, err)
+	}
+	*i = int64JSON(v)
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealm_JSON(t *testing.T) {
+	r := newCloneRealm()
+	s := r.Schemas[0]
+	users, pets := s.Tables[0], s.Tables[1]
+	s.Objects[0].(*schema.EnumType).Schema = s
+	users.Columns[0].SetDefault(&schema.Literal{V: "1"})
+	users.AddIndexes(schema.NewIndex("users_expr").AddExprs(&schema.RawExpr{X: "lower(status)"}))
+	pets.AddTriggers(&schema.Trigger{
+		Name:       "pets_trigger",
+		ActionTime: schema.TriggerTimeBefore,
+		For:        schema.TriggerForRow,
+		Events:     []schema.TriggerEvent{{Name: "UPDATE", Columns: pets.Columns[1:]}},
+		Body:       "EXECUTE FUNCTION f()",
+	})
+	s.AddFuncs(&schema.Func{
+		Name: "f",
+		Args: []*schema.FuncArg{{Name: "a", Type: s.Objects[0].(*schema.EnumType)}},
+		Ret:  &schema.IntegerType{T: "int"},
+		Lang: "SQL",
+		Body: "SELECT 1",
+		Deps: []schema.Object{users, s.Views[0]},
+	})
+
+	b, err := json.Marshal(r)
+	require.NoError(t, err)
+	var got schema.Realm
+	require.NoError(t, json.Unmarshal(b, &got))
+	require.True(t, r.Equal(&got))
+	// Encoding is stable.
+	b2, err := json.Marshal(&got)
+	require.NoError(t, err)
+	require.JSONEq(t, string(b), string(b2))
+
+	var (
+		s2             = got.Schemas[0]
+		users2, pets2  = s2.Tables[0], s2.Tables[1]
+		status2        = s2.Objects[0].(*schema.EnumType)
+		fk2, f2, view2 = pets2.ForeignKeys[0], s2.Funcs[0], s2.Views[0]
+	)
+	require.True(t, &got == s2.Realm)
+	require.True(t, s2 == users2.Schema)
+	require.True(t, users2 == fk2.RefTable)
+	require.True(t, pets2 == fk2.Table)
+	require.True(t, users2.Columns[0] == fk2.RefColumns[0])
+	require.Equal(t, []*schema.ForeignKey{fk2}, pets2.Columns[1].ForeignKeys)
+	require.True(t, users2 == users2.PrimaryKey.Table)
+	require.Equal(t, []*schema.Index{users2.Indexes[0]}, users2.Columns[1].Indexes)
+	require.True(t, pets2 == pets2.Triggers[0].Table)
+	require.True(t, pets2.Columns[1] == pets2.Triggers[0].Events[0].Columns[0])
+	require.True(t, users2 == view2.Deps[0])
+	require.Equal(t, []schema.Object{users2, view2}, f2.Deps)
+	require.True(t, pets2 == s2.Sequences[0].Owner.T)
+	require.True(t, pets2.Columns[0] == s2.Sequences[0].Owner.C)
+	// Types that are objects of the realm are shared.
+	require.True(t, status2 == users2.Columns[1].Type.Type)
+	require.True(t, status2 == f2.Args[0].Type)
+	require.True(t, s2 == status2.Schema)
+}
+
+func TestRealm_UnmarshalJSON(t *testing.T) {
+	var r schema.Realm
+	err := json.Unmarshal([]byte(`{"schemas":[{"name":"s","tables":[{"name":"t","columns":[{"name":"c","type":{"type":{"kind":"unknown.Type"}}}]}]}]}`), &r)
+	require.EqualError(t, err, `sql/schema: unknown type "unknown.Type". Was the package that defines it imported?`)
+	err = json.Unmarshal([]byte(`{"schemas":[{"name":"s","tables":[{"name":"t","columns":[{"name":"c"}],"foreign_keys":[{"name":"fk","columns":["c"],"references":{"schema":"s","table":"p","columns":["id"]}}]}]}]}`), &r)
+	require.EqualError(t, err, `sql/schema: table "p" was not found in schema "s"`)
+	err = json.Unmarshal([]byte(`{"schemas":[{"name":"s","tables":[{"name":"t","primary_key":{"parts":[{"column":"id"}]}}]}]}`), &r)
+	require.EqualError(t, err, `sql/schema: column "id" of index "" was not found in table "t"`)
+}
+
+func TestRealm_UnmarshalInt64String(t *testing.T) {
+	// Encoders that do not support 64-bit integers encode them as strings.
+	var r schema.Realm
+	err := json.Unmarshal([]byte(`{"schemas":[{"name":"s","sequences":[{"name":"seq","start":"10","increment":2,"max":"9223372036854775807","cycle":true}]}]}`), &r)
+	require.NoError(t, err)
+	seq := r.Schemas[0].Sequences[0]
+	require.Equal(t, "seq", seq.Name)
+	require.Equal(t, int64(10), seq.Start)
+	require.Equal(t, int64(2), seq.Increment)
+	require.Equal(t, int64(9223372036854775807), seq.Max)
+	require.True(t, seq.Cycle)
+	b, err := json.Marshal(&r)
+	require.NoError(t, err)
+	require.Equal(t, `{"schemas":[{"name":"s","sequences":[{"name":"seq","start":10,"increment":2,"max":9223372036854775807,"cycle":true}]}]}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"schemas":[{"name":"s","sequences":[{"name":"seq","start":"one"}]}]}`), &r)
+	require.Error(t, err)
+}
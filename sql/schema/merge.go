@@ -0,0 +1,681 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// A MergeError is returned by Merge when objects of the overlay schema
+	// conflict with the objects that have the same names in the base schema.
+	MergeError struct {
+		Conflicts []*MergeConflict
+	}
+
+	// A MergeConflict describes a conflict between an object
+	// of the overlay schema and an object of the base schema.
+	MergeConflict struct {
+		Path   string // Path of the object. e.g. table "users" column "id".
+		Reason string // Reason of the conflict. e.g. mismatched types.
+	}
+
+	// merger holds the state of a Merge operation.
+	merger struct {
+		tables    map[*Table]*Table   // overlay tables and their base tables
+		columns   map[*Column]*Column // overlay columns and their base columns
+		conflicts []*MergeConflict
+	}
+)
+
+// Merge merges the overlay schema into the base schema and returns it. It allows defining
+// schemas in layers, for example, a shared core schema and per-service additions, and diffing
+// the merged result.
+//
+// Objects that exist only in the overlay schema (e.g. tables, views or columns) are moved to
+// the base schema, and objects that exist in both schemas are merged: their attributes and child
+// objects are combined, and the references of the overlay objects are linked to the base ones.
+// A MergeError is returned in case objects with the same name are defined differently. e.g.
+// columns with different types. Note that both schemas are modified by Merge, and should not
+// be used in case of an error.
+func Merge(base, overlay *Schema) (*Schema, error) {
+	m := &merger{tables: make(map[*Table]*Table), columns: make(map[*Column]*Column)}
+	m.attrs(fmt.Sprintf("schema %q", base.Name), &base.Attrs, overlay.Attrs)
+	// Tables and columns are mapped first, as indexes
+	// and foreign-keys may reference any of them.
+	var merged []*Table
+	for _, t2 := range overlay.Tables {
+		t1, ok := base.Table(t2.Name)
+		if !ok {
+			base.AddTables(t2)
+			continue
+		}
+		m.tables[t2] = t1
+		merged = append(merged, t2)
+		for _, c2 := range t2.Columns {
+			c1, ok := t1.Column(c2.Name)
+			if !ok {
+				t1.AddColumns(c2)
+				continue
+			}
+			m.columns[c2] = c1
+			m.column(t1, c1, c2)
+		}
+	}
+	for _, t2 := range overlay.Tables {
+		if t1, ok := m.tables[t2]; ok {
+			m.table(t1, t2)
+		} else {
+			for _, fk := range t2.ForeignKeys {
+				m.linkFK(fk)
+			}
+		}
+	}
+	for _, t2 := range merged {
+		t1 := m.tables[t2]
+		t1.AddTriggers(m.triggers(t1.Name, t1.Triggers, t2.Triggers)...)
+	}
+	for _, v2 := range overlay.Views {
+		m.linkDeps(v2.Deps)
+		v1, ok := base.View(v2.Name)
+		switch {
+		case !ok:
+			base.AddViews(v2)
+		case v1.Def != v2.Def || v1.Materialized() != v2.Materialized():
+			m.conflict(fmt.Sprintf("view %q", v2.Name), "mismatched definitions")
+		default:
+			v1.AddTriggers(m.triggers(v1.Name, v1.Triggers, v2.Triggers)...)
+		}
+	}
+	for _, f2 := range overlay.Funcs {
+		m.linkDeps(f2.Deps)
+		if f1, ok := base.Func(f2.Name); !ok {
+			base.AddFuncs(f2)
+		} else if f1.Body != f2.Body {
+			m.conflict(fmt.Sprintf("function %q", f2.Name), "mismatched definitions")
+		}
+	}
+	for _, p2 := range overlay.Procs {
+		m.linkDeps(p2.Deps)
+		if p1, ok := base.Proc(p2.Name); !ok {
+			base.AddProcs(p2)
+		} else if p1.Body != p2.Body {
+			m.conflict(fmt.Sprintf("procedure %q", p2.Name), "mismatched definitions")
+		}
+	}
+	for _, s2 := range overlay.Sequences {
+		if t, ok := m.tables[s2.Owner.T]; ok {
+			s2.Owner.T = t
+		}
+		if c, ok := m.columns[s2.Owner.C]; ok {
+			s2.Owner.C = c
+		}
+		if s1, ok := base.Sequence(s2.Name); !ok {
+			base.AddSequences(s2)
+		} else if s1.Start != s2.Start || s1.Increment != s2.Increment || s1.Min != s2.Min || s1.Max != s2.Max || s1.Cycle != s2.Cycle {
+			m.conflict(fmt.Sprintf("sequence %q", s2.Name), "mismatched options")
+		}
+	}
+	for _, o2 := range overlay.Objects {
+		m.object(base, o2)
+	}
+	if len(m.conflicts) > 0 {
+		return nil, &MergeError{Conflicts: m.conflicts}
+	}
+	return base, nil
+}
+
+// Error implements the error interface.
+func (e *MergeError) Error() string {
+	conflicts := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		conflicts[i] = c.Path + ": " + c.Reason
+	}
+	return fmt.Sprintf("merge conflicts: %s", strings.Join(conflicts, ", "))
+}
+
+// column merges the overlay column c2 into the base column c1.
+func (m *merger) column(t *Table, c1, c2 *Column) {
+	path := fmt.Sprintf("table %q column %q", t.Name, c1.Name)
+	switch {
+	case c1.Type == nil:
+		c1.Type = c2.Type
+	case c2.Type != nil && (c1.Type.Null != c2.Type.Null || !reflect.DeepEqual(c1.Type.Type, c2.Type.Type)):
+		m.conflict(path, "mismatched types")
+	}
+	switch {
+	case c1.Default == nil:
+		c1.Default = c2.Default
+	case c2.Default != nil && !reflect.DeepEqual(c1.Default, c2.Default):
+		m.conflict(path, "mismatched default values")
+	}
+	m.attrs(path, &c1.Attrs, c2.Attrs)
+}
+
+// table merges the attributes, checks, primary-key, indexes
+// and foreign-keys of the overlay table t2 into the base table t1.
+func (m *merger) table(t1, t2 *Table) {
+	path := fmt.Sprintf("table %q", t1.Name)
+	var attrs []Attr
+	for _, a := range t2.Attrs {
+		c2, ok := a.(*Check)
+		if !ok {
+			attrs = append(attrs, a)
+			continue
+		}
+		switch c1, ok := check(t1, c2); {
+		case !ok:
+			t1.AddChecks(c2)
+		case c1.Expr != c2.Expr:
+			m.conflict(fmt.Sprintf("%s check %q", path, c2.Name), "mismatched expressions")
+		}
+	}
+	m.attrs(path, &t1.Attrs, attrs)
+	switch pk1, pk2 := t1.PrimaryKey, t2.PrimaryKey; {
+	case pk2 == nil:
+	case pk1 == nil:
+		t1.SetPrimaryKey(m.linkIndex(pk2))
+	case !sameParts(pk1, pk2):
+		m.conflict(path+" primary key", "mismatched parts")
+	}
+	for _, idx2 := range t2.Indexes {
+		switch idx1, ok := t1.Index(idx2.Name); {
+		case !ok:
+			t1.AddIndexes(m.linkIndex(idx2))
+		case idx1.Unique != idx2.Unique || !sameParts(idx1, idx2):
+			m.conflict(fmt.Sprintf("%s index %q", path, idx2.Name), "mismatched definitions")
+		}
+	}
+	for _, fk2 := range t2.ForeignKeys {
+		m.linkFK(fk2)
+		switch fk1, ok := t1.ForeignKey(fk2.Symbol); {
+		case !ok:
+			t1.AddForeignKeys(fk2)
+			for _, c := range fk2.Columns {
+				if !c.hasForeignKey(fk2) {
+					c.ForeignKeys = append(c.ForeignKeys, fk2)
+				}
+			}
+		case !sameFK(fk1, fk2):
+			m.conflict(fmt.Sprintf("%s foreign key %q", path, fk2.Symbol), "mismatched definitions")
+		}
+	}
+}
+
+// triggers returns the overlay triggers that do not exist in the base table or view.
+func (m *merger) triggers(name string, base, overlay []*Trigger) (added []*Trigger) {
+Overlay:
+	for _, tr2 := range overlay {
+		m.linkDeps(tr2.Deps)
+		for _, tr1 := range base {
+			if tr1.Name == tr2.Name {
+				if tr1.Body != tr2.Body {
+					m.conflict(fmt.Sprintf("trigger %q on %q", tr2.Name, name), "mismatched definitions")
+				}
+				continue Overlay
+			}
+		}
+		added = append(added, tr2)
+	}
+	return added
+}
+
+// attrs merges the overlay attributes into the base attributes. Attributes
+// that exist only in the overlay are added, and attributes of the same type
+// that hold different values are reported as conflicts.
+func (m *merger) attrs(path string, base *[]Attr, overlay []Attr) {
+	for _, a2 := range overlay {
+		var exists bool
+		for _, a1 := range *base {
+			if reflect.TypeOf(a1) != reflect.TypeOf(a2) {
+				continue
+			}
+			if exists = true; !reflect.DeepEqual(a1, a2) {
+				m.conflict(path, fmt.Sprintf("mismatched %T attributes", a2))
+			}
+			break
+		}
+		if !exists {
+			*base = append(*base, a2)
+		}
+	}
+}
+
+// object adds the driver-specific object to the base schema, unless it already exists.
+func (m *merger) object(base *Schema, o2 Object) {
+	for _, o1 := range base.Objects {
+		if o1 == o2 {
+			return
+		}
+		if e1, ok := o1.(*EnumType); ok {
+			if e2, ok := o2.(*EnumType); ok && e1.T == e2.T {
+				if !reflect.DeepEqual(e1.Values, e2.Values) {
+					m.conflict(fmt.Sprintf("type %q", e2.T), "mismatched values")
+				}
+				return
+			}
+		}
+	}
+	base.AddObjects(o2)
+}
+
+// linkIndex links the parts of the overlay index to the base columns.
+func (m *merger) linkIndex(idx *Index) *Index {
+	for _, p := range idx.Parts {
+		if c, ok := m.columns[p.C]; ok {
+			p.C = c
+			if !c.hasIndex(idx) {
+				c.Indexes = append(c.Indexes, idx)
+			}
+		}
+	}
+	return idx
+}
+
+// linkFK links the overlay foreign-key to the base tables and columns.
+func (m *merger) linkFK(fk *ForeignKey) {
+	for i, c := range fk.Columns {
+		if c1, ok := m.columns[c]; ok {
+			fk.Columns[i] = c1
+		}
+	}
+	if t, ok := m.tables[fk.RefTable]; ok {
+		fk.RefTable = t
+	}
+	for i, c := range fk.RefColumns {
+		if c1, ok := m.columns[c]; ok {
+			fk.RefColumns[i] = c1
+		}
+	}
+}
+
+// linkDeps links the dependencies that are overlay tables to the base tables.
+func (m *merger) linkDeps(deps []Object) {
+	for i, d := range deps {
+		if t, ok := d.(*Table); ok {
+			if t1, ok := m.tables[t]; ok {
+				deps[i] = t1
+			}
+		}
+	}
+}
+
+// conflict records a merge conflict.
+func (m *merger) conflict(path, reason string) {
+	m.conflicts = append(m.conflicts, &MergeConflict{Path: path, Reason: reason})
+}
+
+// check returns the check of the table with the same name, or with
+// the same expression, in case the given check has no name.
+func check(t *Table, c *Check) (*Check, bool) {
+	for _, a := range t.Attrs {
+		if c1, ok := a.(*Check); ok && (c.Name != "" && c1.Name == c.Name || c.Name == "" && c1.Expr == c.Expr) {
+			return c1, true
+		}
+	}
+	return nil, false
+}
+
+// sameParts reports if the two indexes have the same parts.
+func sameParts(idx1, idx2 *Index) bool {
+	if len(idx1.Parts) != len(idx2.Parts) {
+		return false
+	}
+	for i, p1 := range idx1.Parts {
+		p2 := idx2.Parts[i]
+		if (p1.C == nil) != (p2.C == nil) || p1.C != nil && p1.C.Name != p2.C.Name || p1.Desc != p2.Desc || !reflect.DeepEqual(p1.X, p2.X) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameFK reports if the two foreign-keys have the same columns and references.
+func sameFK(fk1, fk2 *ForeignKey) bool {
+	if len(fk1.Columns) != len(fk2.Columns) || len(fk1.RefColumns) != len(fk2.RefColumns) ||
+		fk1.RefTable == nil || fk2.RefTable == nil || fk1.RefTable.Name != fk2.RefTable.Name ||
+		fk1.OnUpdate != fk2.OnUpdate || fk1.OnDelete != fk2.OnDelete {
+		return false
+	}
+	for i := range fk1.Columns {
+		if fk1.Columns[i].Name != fk2.Columns[i].Name {
+			return false
+		}
+	}
+	for i := range fk1.RefColumns {
+		if fk1.RefColumns[i].Name != fk2.RefColumns[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// threeWay holds the state of a ThreeWayMerge operation.
+type threeWay struct {
+	conflicts []*MergeConflict
+}
+
+// ThreeWayMerge merges the changes that were made to the base realm in the ours and theirs
+// realms, and returns the merged realm. It allows two parties to edit the same desired state
+// concurrently (e.g. in different branches), and reconcile their edits instead of the migrations
+// that were generated from them.
+//
+// Objects are matched by their names. Objects that were added, dropped or changed only on one side
+// are taken from that side, and tables (or schemas) that were changed on both sides are merged at
+// the level of their columns, indexes, foreign-keys, triggers and attributes. Objects that were
+// changed differently on both sides, or changed on one side and dropped on the other, are conflicts.
+// In case of conflicts, a MergeError is returned along with the merged realm, in which conflicting
+// objects are kept as defined in ours. The given realms are not modified by ThreeWayMerge.
+func ThreeWayMerge(base, ours, theirs *Realm) (*Realm, error) {
+	var (
+		w     = &threeWay{}
+		r, tr = ours.Clone(), theirs.Clone()
+		label = func(s *Schema) string { return fmt.Sprintf("schema %q", s.Name) }
+	)
+	r.Attrs = merge3(w, "", base.Attrs, r.Attrs, tr.Attrs, attrLabel, attrEqual, nil)
+	r.Objects = mergeObjects(w, "", base.Objects, r.Objects, tr.Objects)
+	r.Schemas = merge3(w, "", base.Schemas, r.Schemas, tr.Schemas, label, (*Schema).Equal, w.schema)
+	relink(r)
+	if len(w.conflicts) > 0 {
+		return r, &MergeError{Conflicts: w.conflicts}
+	}
+	return r, nil
+}
+
+// schema merges the objects of the schema s2 into the schema s1.
+func (w *threeWay) schema(path string, b, s1, s2 *Schema) {
+	var (
+		table    = func(t *Table) string { return fmt.Sprintf("table %q", t.Name) }
+		view     = func(v *View) string { return fmt.Sprintf("view %q", v.Name) }
+		function = func(f *Func) string { return fmt.Sprintf("function %q", f.Name) }
+		proc     = func(p *Proc) string { return fmt.Sprintf("procedure %q", p.Name) }
+		sequence = func(s *Sequence) string { return fmt.Sprintf("sequence %q", s.Name) }
+	)
+	s1.Attrs = merge3(w, path, b.Attrs, s1.Attrs, s2.Attrs, attrLabel, attrEqual, nil)
+	s1.Objects = mergeObjects(w, path, b.Objects, s1.Objects, s2.Objects)
+	s1.Tables = merge3(w, path, b.Tables, s1.Tables, s2.Tables, table, (*Table).Equal, w.table)
+	s1.Views = merge3(w, path, b.Views, s1.Views, s2.Views, view, viewEqual, nil)
+	s1.Funcs = merge3(w, path, b.Funcs, s1.Funcs, s2.Funcs, function, funcEqual, nil)
+	s1.Procs = merge3(w, path, b.Procs, s1.Procs, s2.Procs, proc, procEqual, nil)
+	s1.Sequences = merge3(w, path, b.Sequences, s1.Sequences, s2.Sequences, sequence, sequenceEqual, nil)
+}
+
+// table merges the columns, indexes, foreign-keys, triggers and attributes of t2 into t1.
+func (w *threeWay) table(path string, b, t1, t2 *Table) {
+	var (
+		column  = func(c *Column) string { return fmt.Sprintf("column %q", c.Name) }
+		index   = func(i *Index) string { return fmt.Sprintf("index %q", i.Name) }
+		fk      = func(f *ForeignKey) string { return fmt.Sprintf("foreign key %q", f.Symbol) }
+		trigger = func(t *Trigger) string { return fmt.Sprintf("trigger %q", t.Name) }
+		pk      = func(*Index) string { return "primary key" }
+		pkSlice = func(pk *Index) []*Index {
+			if pk == nil {
+				return nil
+			}
+			return []*Index{pk}
+		}
+	)
+	t1.Attrs = merge3(w, path, b.Attrs, t1.Attrs, t2.Attrs, attrLabel, attrEqual, nil)
+	t1.Columns = merge3(w, path, b.Columns, t1.Columns, t2.Columns, column, (*Column).Equal, nil)
+	pks := merge3(w, path, pkSlice(b.PrimaryKey), pkSlice(t1.PrimaryKey), pkSlice(t2.PrimaryKey), pk, indexEqual, nil)
+	t1.PrimaryKey = nil
+	if len(pks) > 0 {
+		t1.PrimaryKey = pks[0]
+	}
+	t1.Indexes = merge3(w, path, b.Indexes, t1.Indexes, t2.Indexes, index, indexEqual, nil)
+	t1.ForeignKeys = merge3(w, path, b.ForeignKeys, t1.ForeignKeys, t2.ForeignKeys, fk, fkEqual, nil)
+	t1.Triggers = merge3(w, path, b.Triggers, t1.Triggers, t2.Triggers, trigger, triggerEqual, nil)
+}
+
+// merge3 merges the ours and theirs versions of a list of objects, that are identified by their
+// labels. Objects that were changed on both sides are merged by the given merge function, if it
+// is not nil, or reported as conflicts otherwise. The objects of theirs that were added to the
+// list follow the objects of ours.
+func merge3[T any](w *threeWay, path string, base, ours, theirs []T, label func(T) string, eq func(T, T) bool, merge func(string, T, T, T)) []T {
+	find := func(s []T, l string) (v T, ok bool) {
+		for _, v := range s {
+			if label(v) == l {
+				return v, true
+			}
+		}
+		return v, false
+	}
+	var merged []T
+	for _, o := range ours {
+		var (
+			l      = label(o)
+			p      = strings.TrimSpace(path + " " + l)
+			b, inB = find(base, l)
+			t, inT = find(theirs, l)
+		)
+		switch {
+		// Unchanged, added or changed the same on both sides, or added only in ours.
+		case inT && eq(o, t), !inT && !inB:
+			merged = append(merged, o)
+		// Dropped in theirs.
+		case !inT && eq(o, b):
+		case !inT:
+			w.conflict(p, "changed in ours and dropped in theirs")
+			merged = append(merged, o)
+		// Changed only on one side.
+		case inB && eq(o, b):
+			merged = append(merged, t)
+		case inB && eq(t, b):
+			merged = append(merged, o)
+		case inB && merge != nil:
+			merge(p, b, o, t)
+			merged = append(merged, o)
+		case inB:
+			w.conflict(p, "changed differently in ours and theirs")
+			merged = append(merged, o)
+		default:
+			w.conflict(p, "added differently in ours and theirs")
+			merged = append(merged, o)
+		}
+	}
+	for _, t := range theirs {
+		l := label(t)
+		if _, ok := find(ours, l); ok {
+			continue
+		}
+		switch b, inB := find(base, l); {
+		case !inB:
+			merged = append(merged, t)
+		case !eq(t, b):
+			w.conflict(strings.TrimSpace(path+" "+l), "dropped in ours and changed in theirs")
+		}
+	}
+	return merged
+}
+
+// mergeObjects merges the driver-specific objects. Enum types are merged by their names,
+// and other objects are combined, as they cannot be identified.
+func mergeObjects(w *threeWay, path string, base, ours, theirs []Object) []Object {
+	var (
+		enums, other [3][]Object
+		label        = func(o Object) string { return fmt.Sprintf("type %q", o.(*EnumType).T) }
+	)
+	for i, objs := range [3][]Object{base, ours, theirs} {
+		for _, o := range objs {
+			if _, ok := o.(*EnumType); ok {
+				enums[i] = append(enums[i], o)
+			} else {
+				other[i] = append(other[i], o)
+			}
+		}
+	}
+	merged := merge3(w, path, enums[0], enums[1], enums[2], label, func(o1, o2 Object) bool {
+		return typeEqual(o1.(*EnumType), o2.(*EnumType))
+	}, nil)
+	merged = append(merged, other[1]...)
+Theirs:
+	for _, o2 := range other[2] {
+		for _, o1 := range other[1] {
+			if reflect.DeepEqual(o1, o2) {
+				continue Theirs
+			}
+		}
+		merged = append(merged, o2)
+	}
+	return merged
+}
+
+// attrLabel identifies attributes by their types, and checks by their names.
+func attrLabel(a Attr) string {
+	if c, ok := a.(*Check); ok {
+		if c.Name != "" {
+			return fmt.Sprintf("check %q", c.Name)
+		}
+		return fmt.Sprintf("check %q", c.Expr)
+	}
+	return fmt.Sprintf("attribute %T", a)
+}
+
+func attrEqual(a1, a2 Attr) bool {
+	return reflect.DeepEqual(a1, a2)
+}
+
+// conflict records a merge conflict.
+func (w *threeWay) conflict(path, reason string) {
+	w.conflicts = append(w.conflicts, &MergeConflict{Path: path, Reason: reason})
+}
+
+// relink links the objects of the merged realm to their parents, and the references between
+// them (e.g. foreign-keys) to the merged objects, as objects may be taken from both sides.
+func relink(r *Realm) {
+	table := func(s *Schema, t *Table) *Table {
+		if t == nil {
+			return nil
+		}
+		if t.Schema != nil {
+			s, _ = r.Schema(t.Schema.Name)
+		}
+		if s != nil {
+			if t1, ok := s.Table(t.Name); ok {
+				return t1
+			}
+		}
+		return t
+	}
+	column := func(t *Table, c *Column) *Column {
+		if c != nil && t != nil {
+			if c1, ok := t.Column(c.Name); ok {
+				return c1
+			}
+		}
+		return c
+	}
+	deps := func(s *Schema, deps []Object) {
+		for i, d := range deps {
+			switch d := d.(type) {
+			case *Table:
+				deps[i] = table(s, d)
+			case *View:
+				if d.Schema != nil {
+					s, _ = r.Schema(d.Schema.Name)
+				}
+				if s != nil {
+					if v, ok := s.View(d.Name); ok {
+						deps[i] = v
+					}
+				}
+			}
+		}
+	}
+	triggers := func(s *Schema, t *Table, trs []*Trigger) {
+		for _, tr := range trs {
+			deps(s, tr.Deps)
+			for _, e := range tr.Events {
+				for i, c := range e.Columns {
+					e.Columns[i] = column(t, c)
+				}
+			}
+		}
+	}
+	for _, s := range r.Schemas {
+		s.Realm = r
+		for _, t := range s.Tables {
+			t.Schema = s
+			for _, c := range t.Columns {
+				c.Indexes, c.ForeignKeys = nil, nil
+				if c.Type == nil {
+					continue
+				}
+				if e, ok := c.Type.Type.(*EnumType); ok {
+					c.Type.Type = enum(r, s, e)
+				}
+			}
+		}
+	}
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			indexes := t.Indexes
+			if t.PrimaryKey != nil {
+				indexes = append([]*Index{t.PrimaryKey}, indexes...)
+			}
+			for _, idx := range indexes {
+				idx.Table = t
+				for _, p := range idx.Parts {
+					if p.C = column(t, p.C); p.C != nil && !p.C.hasIndex(idx) {
+						p.C.Indexes = append(p.C.Indexes, idx)
+					}
+				}
+			}
+			for _, fk := range t.ForeignKeys {
+				fk.Table, fk.RefTable = t, table(s, fk.RefTable)
+				for i, c := range fk.Columns {
+					if fk.Columns[i] = column(t, c); !fk.Columns[i].hasForeignKey(fk) {
+						fk.Columns[i].ForeignKeys = append(fk.Columns[i].ForeignKeys, fk)
+					}
+				}
+				for i, c := range fk.RefColumns {
+					fk.RefColumns[i] = column(fk.RefTable, c)
+				}
+			}
+			for _, tr := range t.Triggers {
+				tr.Table = t
+			}
+			triggers(s, t, t.Triggers)
+		}
+		for _, v := range s.Views {
+			v.Schema = s
+			deps(s, v.Deps)
+			for _, tr := range v.Triggers {
+				tr.View = v
+			}
+			triggers(s, nil, v.Triggers)
+		}
+		for _, f := range s.Funcs {
+			f.Schema = s
+			deps(s, f.Deps)
+		}
+		for _, p := range s.Procs {
+			p.Schema = s
+			deps(s, p.Deps)
+		}
+		for _, sq := range s.Sequences {
+			sq.Schema = s
+			sq.Owner.T = table(s, sq.Owner.T)
+			sq.Owner.C = column(sq.Owner.T, sq.Owner.C)
+		}
+	}
+}
+
+// enum returns the enum type of the merged realm with the same name as e, if exists.
+func enum(r *Realm, s *Schema, e *EnumType) *EnumType {
+	if e.Schema != nil {
+		if s1, ok := r.Schema(e.Schema.Name); ok {
+			s = s1
+		}
+	}
+	for _, o := range s.Objects {
+		if e1, ok := o.(*EnumType); ok && e1.T == e.T {
+			return e1
+		}
+	}
+	return e
+}
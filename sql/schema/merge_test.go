@@ -0,0 +1,132 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	var (
+		users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+		base  = schema.New("public").AddTables(users).SetCharset("utf8mb4")
+	)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns...))
+	var (
+		// Overlay definition of "users", with an additional column and index.
+		users2 = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("email", "varchar(255)"))
+		pets   = schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner_id", "int"))
+	)
+	users2.AddIndexes(schema.NewUniqueIndex("users_email").AddColumns(users2.Columns[1], users2.Columns[0]))
+	pets.AddForeignKeys(schema.NewForeignKey("owner").AddColumns(pets.Columns[1]).SetRefTable(users2).AddRefColumns(users2.Columns[0]))
+	v := schema.NewView("pet_owners", "SELECT * FROM pets JOIN users").AddDeps(pets, users2)
+	overlay := schema.New("public").AddTables(users2, pets).AddViews(v).SetComment("core")
+
+	s, err := schema.Merge(base, overlay)
+	require.NoError(t, err)
+	require.Equal(t, base, s)
+	require.Equal(t, []*schema.Table{users, pets}, s.Tables)
+	require.Equal(t, s, pets.Schema)
+	require.Equal(t, []schema.Attr{&schema.Charset{V: "utf8mb4"}, &schema.Comment{Text: "core"}}, s.Attrs)
+
+	// Overlay objects are linked to the base objects.
+	require.Len(t, users.Columns, 2)
+	require.Equal(t, users2.Columns[1], users.Columns[1])
+	require.Len(t, users.Indexes, 1)
+	require.Equal(t, users, users.Indexes[0].Table)
+	require.Equal(t, users.Columns[0], users.Indexes[0].Parts[1].C)
+	require.Equal(t, users, pets.ForeignKeys[0].RefTable)
+	require.Equal(t, users.Columns[0], pets.ForeignKeys[0].RefColumns[0])
+	require.Equal(t, []schema.Object{pets, users}, v.Deps)
+	require.Equal(t, []*schema.View{v}, s.Views)
+
+	// Identical definitions are merged.
+	_, err = schema.Merge(s, schema.New("public").AddTables(
+		schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+	))
+	require.NoError(t, err)
+	require.Len(t, users.Columns, 2)
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	var (
+		users = schema.NewTable("users").
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("name", "text")).
+			AddChecks(schema.NewCheck().SetName("positive_id").SetExpr("id > 0"))
+		base = schema.New("public").AddTables(users).AddViews(schema.NewView("v", "SELECT 1"))
+	)
+	users.AddIndexes(schema.NewIndex("users_name").AddColumns(users.Columns[1]))
+	var (
+		users2 = schema.NewTable("users").
+			AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewStringColumn("name", "text")).
+			AddChecks(schema.NewCheck().SetName("positive_id").SetExpr("id >= 0"))
+		overlay = schema.New("public").AddTables(users2).AddViews(schema.NewView("v", "SELECT 2"))
+	)
+	users2.AddIndexes(schema.NewUniqueIndex("users_name").AddColumns(users2.Columns[1]))
+	_, err := schema.Merge(base, overlay)
+	require.EqualError(t, err, `merge conflicts: table "users" column "id": mismatched types, table "users" check "positive_id": mismatched expressions, table "users" index "users_name": mismatched definitions, view "v": mismatched definitions`)
+	merr, ok := err.(*schema.MergeError)
+	require.True(t, ok)
+	require.Len(t, merr.Conflicts, 4)
+	require.Equal(t, &schema.MergeConflict{Path: `table "users" column "id"`, Reason: "mismatched types"}, merr.Conflicts[0])
+}
+
+func TestThreeWayMerge(t *testing.T) {
+	newRealm := func() *schema.Realm {
+		var (
+			users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("name", "text"))
+			pets  = schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner_id", "int"))
+		)
+		users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+		pets.AddForeignKeys(schema.NewForeignKey("owner").AddColumns(pets.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+		return schema.NewRealm(schema.New("public").AddTables(users, pets))
+	}
+	base, ours, theirs := newRealm(), newRealm(), newRealm()
+	// Ours adds a column and an index to "users", and drops "pets".
+	users := ours.Schemas[0].Tables[0]
+	users.AddColumns(schema.NewStringColumn("email", "text"))
+	users.AddIndexes(schema.NewUniqueIndex("users_email").AddColumns(users.Columns[2]))
+	ours.Schemas[0].Tables = ours.Schemas[0].Tables[:1]
+	// Theirs adds a column to "users", and a new table that references it.
+	users = theirs.Schemas[0].Tables[0]
+	users.AddColumns(schema.NewIntColumn("age", "int")).SetComment("users table")
+	groups := schema.NewTable("groups").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("admin_id", "int"))
+	groups.AddForeignKeys(schema.NewForeignKey("admin").AddColumns(groups.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	theirs.Schemas[0].AddTables(groups)
+
+	r, err := schema.ThreeWayMerge(base, ours, theirs)
+	require.NoError(t, err)
+	s := r.Schemas[0]
+	require.Len(t, s.Tables, 2)
+	users, groups = s.Tables[0], s.Tables[1]
+	require.Equal(t, "users", users.Name)
+	require.Equal(t, "groups", groups.Name)
+	require.Equal(t, []string{"id", "name", "email", "age"}, []string{users.Columns[0].Name, users.Columns[1].Name, users.Columns[2].Name, users.Columns[3].Name})
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "users table"}}, users.Attrs)
+	require.True(t, users.Indexes[0].Parts[0].C == users.Columns[2])
+	require.Equal(t, []*schema.Index{users.Indexes[0]}, users.Columns[2].Indexes)
+	// References are linked to the merged objects.
+	require.True(t, s == groups.Schema)
+	require.True(t, users == groups.ForeignKeys[0].RefTable)
+	require.True(t, users.Columns[0] == groups.ForeignKeys[0].RefColumns[0])
+	// The given realms are not modified.
+	require.Len(t, ours.Schemas[0].Tables[0].Columns, 3)
+	require.Len(t, theirs.Schemas[0].Tables, 3)
+	require.True(t, theirs.Schemas[0].Tables[0] == theirs.Schemas[0].Tables[2].ForeignKeys[0].RefTable)
+
+	// Conflicting changes are reported, and kept as defined in ours.
+	base, ours, theirs = newRealm(), newRealm(), newRealm()
+	ours.Schemas[0].Tables[0].Columns[1].Type.Type = &schema.StringType{T: "varchar", Size: 255}
+	theirs.Schemas[0].Tables[0].Columns[1].Type.Type = &schema.StringType{T: "varchar", Size: 100}
+	ours.Schemas[0].Tables[1].AddColumns(schema.NewIntColumn("age", "int"))
+	theirs.Schemas[0].Tables = theirs.Schemas[0].Tables[:1]
+	r, err = schema.ThreeWayMerge(base, ours, theirs)
+	require.EqualError(t, err, `merge conflicts: schema "public" table "users" column "name": changed differently in ours and theirs, schema "public" table "pets": changed in ours and dropped in theirs`)
+	require.True(t, ours.Equal(r))
+}
@@ -64,6 +64,11 @@ type (
 		Changes []Change
 	}
 
+	// RenameSchema describes a schema rename change.
+	RenameSchema struct {
+		From, To *Schema
+	}
+
 	// AddTable describes a table creation change.
 	AddTable struct {
 		T     *Table
@@ -109,6 +114,74 @@ type (
 		From, To *View
 	}
 
+	// AddFunc describes a function creation change.
+	AddFunc struct {
+		F     *Func
+		Extra []Clause // Extra clauses and options.
+	}
+
+	// DropFunc describes a function removal change.
+	DropFunc struct {
+		F     *Func
+		Extra []Clause // Extra clauses.
+	}
+
+	// ModifyFunc describes a function modification change.
+	ModifyFunc struct {
+		From, To *Func
+	}
+
+	// AddProc describes a procedure creation change.
+	AddProc struct {
+		P     *Proc
+		Extra []Clause // Extra clauses and options.
+	}
+
+	// DropProc describes a procedure removal change.
+	DropProc struct {
+		P     *Proc
+		Extra []Clause // Extra clauses.
+	}
+
+	// ModifyProc describes a procedure modification change.
+	ModifyProc struct {
+		From, To *Proc
+	}
+
+	// AddSequence describes a sequence creation change.
+	AddSequence struct {
+		S     *Sequence
+		Extra []Clause // Extra clauses and options.
+	}
+
+	// DropSequence describes a sequence removal change.
+	DropSequence struct {
+		S     *Sequence
+		Extra []Clause // Extra clauses.
+	}
+
+	// ModifySequence describes a sequence modification change.
+	ModifySequence struct {
+		From, To *Sequence
+	}
+
+	// AddTrigger describes a trigger creation change.
+	AddTrigger struct {
+		T     *Trigger
+		Extra []Clause // Extra clauses and options.
+	}
+
+	// DropTrigger describes a trigger removal change.
+	DropTrigger struct {
+		T     *Trigger
+		Extra []Clause // Extra clauses.
+	}
+
+	// ModifyTrigger describes a trigger modification change.
+	ModifyTrigger struct {
+		From, To *Trigger
+	}
+
 	// AddObject describes a generic object creation change.
 	AddObject struct {
 		O     Object
@@ -147,6 +220,9 @@ type (
 	ModifyColumn struct {
 		From, To *Column
 		Change   ChangeKind
+		// Conversion classifies the conversion of the column values, in case its
+		// type was changed and the driver supports classifying type conversions.
+		Conversion TypeConversion
 	}
 
 	// RenameColumn describes a column rename change.
@@ -195,7 +271,8 @@ type (
 
 	// AddForeignKey describes a foreign-key creation change.
 	AddForeignKey struct {
-		F *ForeignKey
+		F     *ForeignKey
+		Extra []Clause // Extra clauses and options.
 	}
 
 	// DropForeignKey describes a foreign-key removal change.
@@ -211,7 +288,8 @@ type (
 
 	// AddCheck describes a CHECK constraint creation change.
 	AddCheck struct {
-		C *Check
+		C     *Check
+		Extra []Clause // Extra clauses and options.
 	}
 
 	// DropCheck describes a CHECK constraint removal change.
@@ -311,9 +389,56 @@ func (k ChangeKind) Is(c ChangeKind) bool {
 	return k == c || k&c != 0
 }
 
+// A TypeConversion classifies the conversion of column values
+// from one type to another, when the column type is changed.
+type TypeConversion uint
+
+const (
+	// ConversionUnknown indicates that the conversion was not classified.
+	// For example, the driver does not know the types of the column.
+	ConversionUnknown TypeConversion = iota
+	// ConversionWidening indicates that all values of the old type can be
+	// represented by the new type. For example, int to bigint.
+	ConversionWidening
+	// ConversionNarrowing indicates that values of the old type might be
+	// truncated or rejected by the new type. For example, varchar(255) to
+	// varchar(50).
+	ConversionNarrowing
+	// ConversionIncompatible indicates that the types are not related, and
+	// values might not be convertible at all. For example, json to int.
+	ConversionIncompatible
+)
+
+// String implements the fmt.Stringer interface.
+func (c TypeConversion) String() string {
+	switch c {
+	case ConversionWidening:
+		return "widening"
+	case ConversionNarrowing:
+		return "narrowing"
+	case ConversionIncompatible:
+		return "incompatible"
+	default:
+		return "unknown"
+	}
+}
+
+// Lossy reports whether the conversion might lose or reject values.
+func (c TypeConversion) Lossy() bool {
+	return c == ConversionNarrowing || c == ConversionIncompatible
+}
+
 type (
 	// Differ is the interface implemented by the different
 	// drivers for comparing and diffing schema top elements.
+	//
+	// Changes are returned in a stable order, that depends only on the given states:
+	// schema changes come first, followed by the changes of their tables, views,
+	// functions and procedures, and the changes of a table are ordered by the ordinal
+	// position of its columns, followed by its indexes and foreign-keys. Tables are
+	// sorted by their dependencies and then by their names, and other objects of the
+	// same kind are sorted by their names. Hence, states that define the same objects
+	// in different orders produce the same changes.
 	Differ interface {
 		// RealmDiff returns a diff report for migrating a realm
 		// (or a database) from state "from" to state "to". An error
@@ -337,6 +462,49 @@ type (
 		// SkipChanges defines a list of change types to skip.
 		SkipChanges []Change
 
+		// RenameSchemas maps schema names in the current state to their names
+		// in the desired state. Schemas that are listed here are renamed instead
+		// of being dropped and recreated.
+		RenameSchemas map[string]string
+
+		// RenameTables maps table names in the current state to their names
+		// in the desired state, within the same schema. Tables that are listed
+		// here are renamed instead of being dropped and recreated.
+		RenameTables map[string]string
+
+		// MoveTables maps qualified table names (schema.table) in the current state to
+		// their qualified names in the desired state, for tables that were moved between
+		// schemas. Tables that are listed here are moved instead of being dropped from one
+		// schema and recreated in the other, and the foreign keys that reference them are
+		// expected to follow them.
+		MoveTables map[string]string
+
+		// RenameColumns maps column names in the current state to their names in
+		// the desired state, keyed by the name of their table in the desired state.
+		// Columns that are listed here are renamed instead of being dropped and added.
+		RenameColumns map[string]map[string]string
+
+		// DetectRenames enables a heuristic that detects renamed tables and columns
+		// that were not hinted. A dropped table (or column) is considered renamed if
+		// exactly one added table (or column) is identical to it, except for its name.
+		// On realm diffing, tables that were dropped from one schema and added with the
+		// same structure and name to another schema are considered moved.
+		DetectRenames bool
+
+		// IgnoreComments and IgnoreCharsets indicate whether changes to the comments,
+		// or to the character-sets and collations of elements should be ignored.
+		IgnoreComments, IgnoreCharsets bool
+
+		// CaseInsensitive indicates whether table and column names should be matched
+		// case-insensitively. For example, on MySQL databases that are configured with
+		// lower_case_table_names, or when the desired state is defined in another case.
+		CaseInsensitive bool
+
+		// SortByName indicates whether tables should be diffed in the alphabetical
+		// order of their names, rather than by their dependencies and then by their
+		// names. Columns are always diffed by their ordinal position.
+		SortByName bool
+
 		// Extra defines per-driver configuration. If not
 		// nil, should be set to schemahcl.Extension.
 		Extra any // avoid circular dependency with schemahcl.
@@ -365,6 +533,111 @@ func DiffSkipChanges(changes ...Change) DiffOption {
 	}
 }
 
+// DiffSkipDrops returns a DiffOption that skips all changes that drop elements.
+func DiffSkipDrops() DiffOption {
+	return DiffSkipChanges(dropChanges()...)
+}
+
+// dropChanges returns the list of change types that drop elements.
+func dropChanges() []Change {
+	return []Change{
+		&DropSchema{}, &DropTable{}, &DropView{}, &DropColumn{}, &DropIndex{},
+		&DropPrimaryKey{}, &DropForeignKey{}, &DropCheck{}, &DropFunc{}, &DropProc{},
+		&DropSequence{}, &DropTrigger{}, &DropObject{},
+	}
+}
+
+// DiffIgnoreComments returns a DiffOption that ignores changes to the comments of elements.
+func DiffIgnoreComments() DiffOption {
+	return func(o *DiffOptions) {
+		o.IgnoreComments = true
+	}
+}
+
+// DiffIgnoreCharsets returns a DiffOption that ignores changes
+// to the character-sets and collations of elements.
+func DiffIgnoreCharsets() DiffOption {
+	return func(o *DiffOptions) {
+		o.IgnoreCharsets = true
+	}
+}
+
+// DiffCaseInsensitive returns a DiffOption that matches
+// table and column names case-insensitively.
+func DiffCaseInsensitive() DiffOption {
+	return func(o *DiffOptions) {
+		o.CaseInsensitive = true
+	}
+}
+
+// DiffSortByName returns a DiffOption that diffs tables in the
+// alphabetical order of their names, regardless of their dependencies.
+func DiffSortByName() DiffOption {
+	return func(o *DiffOptions) {
+		o.SortByName = true
+	}
+}
+
+// DiffRenameSchema returns a DiffOption that hints the differ that the schema
+// named "from" in the current state was renamed to "to" in the desired state.
+func DiffRenameSchema(from, to string) DiffOption {
+	return func(o *DiffOptions) {
+		if o.RenameSchemas == nil {
+			o.RenameSchemas = make(map[string]string)
+		}
+		o.RenameSchemas[from] = to
+	}
+}
+
+// DiffRenameTable returns a DiffOption that hints the differ that the table
+// named "from" in the current state was renamed to "to" in the desired state.
+func DiffRenameTable(from, to string) DiffOption {
+	return func(o *DiffOptions) {
+		if o.RenameTables == nil {
+			o.RenameTables = make(map[string]string)
+		}
+		o.RenameTables[from] = to
+	}
+}
+
+// DiffMoveTable returns a DiffOption that hints the differ that the table named "from" in
+// the current state was moved to "to" in the desired state. Both names are qualified with
+// their schema names. For example:
+//
+//	schema.DiffMoveTable("public.users", "auth.users")
+func DiffMoveTable(from, to string) DiffOption {
+	return func(o *DiffOptions) {
+		if o.MoveTables == nil {
+			o.MoveTables = make(map[string]string)
+		}
+		o.MoveTables[from] = to
+	}
+}
+
+// DiffRenameColumn returns a DiffOption that hints the differ that the column named "from"
+// in the current state was renamed to "to" in the desired state. The table is identified by
+// its name in the desired state.
+func DiffRenameColumn(table, from, to string) DiffOption {
+	return func(o *DiffOptions) {
+		if o.RenameColumns == nil {
+			o.RenameColumns = make(map[string]map[string]string)
+		}
+		if o.RenameColumns[table] == nil {
+			o.RenameColumns[table] = make(map[string]string)
+		}
+		o.RenameColumns[table][from] = to
+	}
+}
+
+// DiffDetectRenames returns a DiffOption that enables the detection of renamed tables and
+// columns that were not hinted, instead of reporting them as dropped and added. Note, the
+// detection is based on the structure of the elements, and it should be reviewed by users.
+func DiffDetectRenames() DiffOption {
+	return func(o *DiffOptions) {
+		o.DetectRenames = true
+	}
+}
+
 // Skipped reports whether the given change should be skipped.
 func (o *DiffOptions) Skipped(c Change) bool {
 	for _, s := range o.SkipChanges {
@@ -526,6 +799,67 @@ func (c Changes) rsearch(f func(Change) bool) int {
 	return -1
 }
 
+// Annotations maps changes to arbitrary key-value metadata, such as their owner, ticket or
+// risk level. Drivers copy the annotations of the planned changes to their statements (i.e.
+// migrate.Change), allowing formatters to render them as comments in migration files.
+//
+//	a := make(schema.Annotations).
+//		Annotate(changes[0], "owner", "payments").
+//		Annotate(changes[0], "ticket", "DB-42")
+type Annotations map[Change]map[string]string
+
+// Annotate sets the annotation k of the change to v.
+func (a Annotations) Annotate(c Change, k, v string) Annotations {
+	if a[c] == nil {
+		a[c] = make(map[string]string)
+	}
+	a[c][k] = v
+	return a
+}
+
+// Lookup returns the annotations of the change. Changes that are nested in a ModifySchema or
+// a ModifyTable inherit the annotations of their parent, and the annotations of the nested
+// changes are inherited by their parent. This is because drivers may plan nested changes as
+// separate statements, or combine them into a single statement. The own annotations of the
+// change take precedence over inherited ones.
+func (a Annotations) Lookup(c Change) map[string]string {
+	if len(a) == 0 || c == nil {
+		return nil
+	}
+	var kv map[string]string
+	set := func(m map[string]string) {
+		for k, v := range m {
+			if kv == nil {
+				kv = make(map[string]string)
+			}
+			kv[k] = v
+		}
+	}
+	for _, n := range nestedChanges(c) {
+		set(a[n])
+	}
+	for p, m := range a {
+		for _, n := range nestedChanges(p) {
+			if n == c {
+				set(m)
+			}
+		}
+	}
+	set(a[c])
+	return kv
+}
+
+// nestedChanges returns the changes that are nested in c, if any.
+func nestedChanges(c Change) []Change {
+	switch c := c.(type) {
+	case *ModifySchema:
+		return c.Changes
+	case *ModifyTable:
+		return c.Changes
+	}
+	return nil
+}
+
 // changes.
 func (*AddAttr) change()          {}
 func (*DropAttr) change()         {}
@@ -533,6 +867,7 @@ func (*ModifyAttr) change()       {}
 func (*AddSchema) change()        {}
 func (*DropSchema) change()       {}
 func (*ModifySchema) change()     {}
+func (*RenameSchema) change()     {}
 func (*AddTable) change()         {}
 func (*DropTable) change()        {}
 func (*ModifyTable) change()      {}
@@ -541,6 +876,18 @@ func (*AddView) change()          {}
 func (*DropView) change()         {}
 func (*ModifyView) change()       {}
 func (*RenameView) change()       {}
+func (*AddFunc) change()          {}
+func (*DropFunc) change()         {}
+func (*ModifyFunc) change()       {}
+func (*AddProc) change()          {}
+func (*DropProc) change()         {}
+func (*ModifyProc) change()       {}
+func (*AddSequence) change()      {}
+func (*DropSequence) change()     {}
+func (*ModifySequence) change()   {}
+func (*AddTrigger) change()       {}
+func (*DropTrigger) change()      {}
+func (*ModifyTrigger) change()    {}
 func (*AddObject) change()        {}
 func (*DropObject) change()       {}
 func (*ModifyObject) change()     {}
@@ -124,3 +124,22 @@ func ExampleChanges_Replace() {
 	// *schema.AddColumn(created_at)
 	// *schema.RenameColumn(old_name -> new_name)
 }
+
+func TestAnnotations_Lookup(t *testing.T) {
+	var (
+		add    = &schema.AddColumn{C: schema.NewColumn("c")}
+		drop   = &schema.DropColumn{C: schema.NewColumn("d")}
+		modify = &schema.ModifyTable{T: schema.NewTable("t"), Changes: []schema.Change{add, drop}}
+		a      = make(schema.Annotations).
+			Annotate(modify, "owner", "payments").
+			Annotate(modify, "risk", "low").
+			Annotate(add, "risk", "high")
+	)
+	require.Equal(t, map[string]string{"owner": "payments", "risk": "high"}, a.Lookup(add))
+	require.Equal(t, map[string]string{"owner": "payments", "risk": "low"}, a.Lookup(drop))
+	require.Equal(t, map[string]string{"owner": "payments", "risk": "low"}, a.Lookup(modify))
+	// Changes that were combined by the driver inherit the annotations of the nested changes.
+	require.Equal(t, map[string]string{"risk": "high"}, a.Lookup(&schema.ModifyTable{T: modify.T, Changes: []schema.Change{add}}))
+	require.Nil(t, a.Lookup(&schema.AddTable{T: modify.T}))
+	require.Nil(t, a.Lookup(nil))
+}
@@ -0,0 +1,352 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"ariga.io/atlas/sql/schema/schemapb"
+)
+
+//go:generate go run ariga.io/atlas/sql/internal/protogen/cmd/protogen -o schemapb/schema.pb.go schema.proto
+
+// RealmToProto returns the protobuf representation of the realm, as defined in schema.proto.
+// Similar to MarshalJSON, references between objects are encoded by their names, and types,
+// attributes and driver-specific objects are encoded along with their registered type names.
+func RealmToProto(r *Realm) (*schemapb.Realm, error) {
+	v, err := encodeRealm(r)
+	if err != nil {
+		return nil, err
+	}
+	return &schemapb.Realm{
+		Schemas: mapProto(v.Schemas, schemaToProto),
+		Attrs:   valuesToProto(v.Attrs),
+		Objects: valuesToProto(v.Objects),
+	}, nil
+}
+
+// RealmFromProto returns the realm represented by the given message. Similar to UnmarshalJSON,
+// the driver packages that define the types and attributes of the realm must be imported.
+func RealmFromProto(m *schemapb.Realm) (*Realm, error) {
+	v := &realmJSON{
+		Schemas: mapProto(m.Schemas, schemaFromProto),
+		Attrs:   valuesFromProto(m.Attrs),
+		Objects: valuesFromProto(m.Objects),
+	}
+	r := &Realm{}
+	if err := decodeRealm(v, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// mapProto maps a list of messages (or their tree representation) using f.
+func mapProto[T, V any](s []T, f func(T) V) []V {
+	if len(s) == 0 {
+		return nil
+	}
+	vs := make([]V, len(s))
+	for i := range s {
+		vs[i] = f(s[i])
+	}
+	return vs
+}
+
+func schemaToProto(v *schemaJSON) *schemapb.Schema {
+	return &schemapb.Schema{
+		Name:      v.Name,
+		Tables:    mapProto(v.Tables, tableToProto),
+		Views:     mapProto(v.Views, viewToProto),
+		Funcs:     mapProto(v.Funcs, funcToProto),
+		Procs:     mapProto(v.Procs, funcToProto),
+		Sequences: mapProto(v.Sequences, sequenceToProto),
+		Attrs:     valuesToProto(v.Attrs),
+		Objects:   valuesToProto(v.Objects),
+	}
+}
+
+func schemaFromProto(m *schemapb.Schema) *schemaJSON {
+	return &schemaJSON{
+		Name:      m.Name,
+		Tables:    mapProto(m.Tables, tableFromProto),
+		Views:     mapProto(m.Views, viewFromProto),
+		Funcs:     mapProto(m.Funcs, funcFromProto),
+		Procs:     mapProto(m.Procs, funcFromProto),
+		Sequences: mapProto(m.Sequences, sequenceFromProto),
+		Attrs:     valuesFromProto(m.Attrs),
+		Objects:   valuesFromProto(m.Objects),
+	}
+}
+
+func tableToProto(v *tableJSON) *schemapb.Table {
+	m := &schemapb.Table{
+		Name:        v.Name,
+		Columns:     mapProto(v.Columns, columnToProto),
+		Indexes:     mapProto(v.Indexes, indexToProto),
+		ForeignKeys: mapProto(v.ForeignKeys, foreignKeyToProto),
+		Attrs:       valuesToProto(v.Attrs),
+		Triggers:    mapProto(v.Triggers, triggerToProto),
+	}
+	if v.PrimaryKey != nil {
+		m.PrimaryKey = indexToProto(v.PrimaryKey)
+	}
+	return m
+}
+
+func tableFromProto(m *schemapb.Table) *tableJSON {
+	v := &tableJSON{
+		Name:        m.Name,
+		Columns:     mapProto(m.Columns, columnFromProto),
+		Indexes:     mapProto(m.Indexes, indexFromProto),
+		ForeignKeys: mapProto(m.ForeignKeys, foreignKeyFromProto),
+		Attrs:       valuesFromProto(m.Attrs),
+		Triggers:    mapProto(m.Triggers, triggerFromProto),
+	}
+	if m.PrimaryKey != nil {
+		v.PrimaryKey = indexFromProto(m.PrimaryKey)
+	}
+	return v
+}
+
+func viewToProto(v *viewJSON) *schemapb.View {
+	return &schemapb.View{
+		Name:     v.Name,
+		Def:      v.Def,
+		Columns:  mapProto(v.Columns, columnToProto),
+		Attrs:    valuesToProto(v.Attrs),
+		Deps:     valuesToProto(v.Deps),
+		Triggers: mapProto(v.Triggers, triggerToProto),
+	}
+}
+
+func viewFromProto(m *schemapb.View) *viewJSON {
+	return &viewJSON{
+		Name:     m.Name,
+		Def:      m.Def,
+		Columns:  mapProto(m.Columns, columnFromProto),
+		Attrs:    valuesFromProto(m.Attrs),
+		Deps:     valuesFromProto(m.Deps),
+		Triggers: mapProto(m.Triggers, triggerFromProto),
+	}
+}
+
+func columnToProto(v *columnJSON) *schemapb.Column {
+	m := &schemapb.Column{
+		Name:    v.Name,
+		Default: valueToProto(v.Default),
+		Attrs:   valuesToProto(v.Attrs),
+	}
+	if v.Type != nil {
+		m.Type = &schemapb.ColumnType{Raw: v.Type.Raw, Null: v.Type.Null, Type: valueToProto(v.Type.Type)}
+	}
+	return m
+}
+
+func columnFromProto(m *schemapb.Column) *columnJSON {
+	v := &columnJSON{
+		Name:    m.Name,
+		Default: valueFromProto(m.Default),
+		Attrs:   valuesFromProto(m.Attrs),
+	}
+	if m.Type != nil {
+		v.Type = &columnTypeJSON{Raw: m.Type.Raw, Null: m.Type.Null, Type: valueFromProto(m.Type.Type)}
+	}
+	return v
+}
+
+func indexToProto(v *indexJSON) *schemapb.Index {
+	return &schemapb.Index{
+		Name:   v.Name,
+		Unique: v.Unique,
+		Parts: mapProto(v.Parts, func(p *indexPartJSON) *schemapb.IndexPart {
+			return &schemapb.IndexPart{
+				SeqNo:  int32(p.SeqNo),
+				Desc:   p.Desc,
+				Column: p.Column,
+				Expr:   valueToProto(p.Expr),
+				Attrs:  valuesToProto(p.Attrs),
+			}
+		}),
+		Attrs: valuesToProto(v.Attrs),
+	}
+}
+
+func indexFromProto(m *schemapb.Index) *indexJSON {
+	return &indexJSON{
+		Name:   m.Name,
+		Unique: m.Unique,
+		Parts: mapProto(m.Parts, func(p *schemapb.IndexPart) *indexPartJSON {
+			return &indexPartJSON{
+				SeqNo:  int(p.SeqNo),
+				Desc:   p.Desc,
+				Column: p.Column,
+				Expr:   valueFromProto(p.Expr),
+				Attrs:  valuesFromProto(p.Attrs),
+			}
+		}),
+		Attrs: valuesFromProto(m.Attrs),
+	}
+}
+
+func foreignKeyToProto(v *foreignKeyJSON) *schemapb.ForeignKey {
+	return &schemapb.ForeignKey{
+		Name:    v.Name,
+		Columns: v.Columns,
+		References: &schemapb.ForeignKey_References{
+			Schema:  v.References.Schema,
+			Table:   v.References.Table,
+			Columns: v.References.Columns,
+		},
+		OnUpdate: v.OnUpdate,
+		OnDelete: v.OnDelete,
+	}
+}
+
+func foreignKeyFromProto(m *schemapb.ForeignKey) *foreignKeyJSON {
+	v := &foreignKeyJSON{
+		Name:     m.Name,
+		Columns:  m.Columns,
+		OnUpdate: m.OnUpdate,
+		OnDelete: m.OnDelete,
+	}
+	if r := m.References; r != nil {
+		v.References.Schema, v.References.Table, v.References.Columns = r.Schema, r.Table, r.Columns
+	}
+	return v
+}
+
+func triggerToProto(v *triggerJSON) *schemapb.Trigger {
+	return &schemapb.Trigger{
+		Name:       v.Name,
+		ActionTime: v.ActionTime,
+		Events: mapProto(v.Events, func(e *triggerEventJSON) *schemapb.Trigger_Event {
+			return &schemapb.Trigger_Event{Name: e.Name, Columns: e.Columns}
+		}),
+		For:   v.For,
+		Body:  v.Body,
+		Attrs: valuesToProto(v.Attrs),
+		Deps:  valuesToProto(v.Deps),
+	}
+}
+
+func triggerFromProto(m *schemapb.Trigger) *triggerJSON {
+	return &triggerJSON{
+		Name:       m.Name,
+		ActionTime: m.ActionTime,
+		Events: mapProto(m.Events, func(e *schemapb.Trigger_Event) *triggerEventJSON {
+			return &triggerEventJSON{Name: e.Name, Columns: e.Columns}
+		}),
+		For:   m.For,
+		Body:  m.Body,
+		Attrs: valuesFromProto(m.Attrs),
+		Deps:  valuesFromProto(m.Deps),
+	}
+}
+
+func funcToProto(v *funcJSON) *schemapb.Func {
+	return &schemapb.Func{
+		Name: v.Name,
+		Args: mapProto(v.Args, func(a *funcArgJSON) *schemapb.Func_Arg {
+			return &schemapb.Func_Arg{
+				Name:    a.Name,
+				Type:    valueToProto(a.Type),
+				Mode:    a.Mode,
+				Default: valueToProto(a.Default),
+				Attrs:   valuesToProto(a.Attrs),
+			}
+		}),
+		Ret:      valueToProto(v.Ret),
+		RetTable: mapProto(v.RetTable, columnToProto),
+		Lang:     v.Lang,
+		Body:     v.Body,
+		Attrs:    valuesToProto(v.Attrs),
+		Deps:     valuesToProto(v.Deps),
+	}
+}
+
+func funcFromProto(m *schemapb.Func) *funcJSON {
+	return &funcJSON{
+		Name: m.Name,
+		Args: mapProto(m.Args, func(a *schemapb.Func_Arg) *funcArgJSON {
+			return &funcArgJSON{
+				Name:    a.Name,
+				Type:    valueFromProto(a.Type),
+				Mode:    a.Mode,
+				Default: valueFromProto(a.Default),
+				Attrs:   valuesFromProto(a.Attrs),
+			}
+		}),
+		Ret:      valueFromProto(m.Ret),
+		RetTable: mapProto(m.RetTable, columnFromProto),
+		Lang:     m.Lang,
+		Body:     m.Body,
+		Attrs:    valuesFromProto(m.Attrs),
+		Deps:     valuesFromProto(m.Deps),
+	}
+}
+
+func sequenceToProto(v *sequenceJSON) *schemapb.Sequence {
+	return &schemapb.Sequence{
+		Name:      v.Name,
+		Type:      valueToProto(v.Type),
+		Start:     int64(v.Start),
+		Increment: int64(v.Increment),
+		Min:       int64(v.Min),
+		Max:       int64(v.Max),
+		Cache:     int64(v.Cache),
+		Cycle:     v.Cycle,
+		Owner:     refToProto(v.Owner),
+		Attrs:     valuesToProto(v.Attrs),
+	}
+}
+
+func sequenceFromProto(m *schemapb.Sequence) *sequenceJSON {
+	return &sequenceJSON{
+		Name:      m.Name,
+		Type:      valueFromProto(m.Type),
+		Start:     int64JSON(m.Start),
+		Increment: int64JSON(m.Increment),
+		Min:       int64JSON(m.Min),
+		Max:       int64JSON(m.Max),
+		Cache:     int64JSON(m.Cache),
+		Cycle:     m.Cycle,
+		Owner:     refFromProto(m.Owner),
+		Attrs:     valuesFromProto(m.Attrs),
+	}
+}
+
+func valuesToProto(vs []*valueJSON) []*schemapb.Value {
+	return mapProto(vs, valueToProto)
+}
+
+func valuesFromProto(ms []*schemapb.Value) []*valueJSON {
+	return mapProto(ms, valueFromProto)
+}
+
+func valueToProto(v *valueJSON) *schemapb.Value {
+	if v == nil {
+		return nil
+	}
+	return &schemapb.Value{Kind: v.Kind, Value: v.Value, Ref: refToProto(v.Ref)}
+}
+
+func valueFromProto(m *schemapb.Value) *valueJSON {
+	if m == nil {
+		return nil
+	}
+	return &valueJSON{Kind: m.Kind, Value: m.Value, Ref: refFromProto(m.Ref)}
+}
+
+func refToProto(r *refJSON) *schemapb.Ref {
+	if r == nil {
+		return nil
+	}
+	return &schemapb.Ref{Kind: r.Kind, Schema: r.Schema, Table: r.Table, Name: r.Name, Index: int32(r.Index)}
+}
+
+func refFromProto(m *schemapb.Ref) *refJSON {
+	if m == nil {
+		return nil
+	}
+	return &refJSON{Kind: m.Kind, Schema: m.Schema, Table: m.Table, Name: m.Name, Index: int(m.Index)}
+}
@@ -0,0 +1,59 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/protogen"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/schema/schemapb"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealm_Proto(t *testing.T) {
+	r := newCloneRealm()
+	s := r.Schemas[0]
+	s.Objects[0].(*schema.EnumType).Schema = s
+	s.Tables[0].Columns[0].SetDefault(&schema.Literal{V: "1"})
+	s.Tables[0].AddIndexes(schema.NewIndex("users_expr").AddExprs(&schema.RawExpr{X: "lower(status)"}))
+	// Integers that cannot be represented by a float64 keep their precision.
+	s.Sequences[0].Max = math.MaxInt64
+	s.Sequences[0].Min = math.MinInt64 + 1
+
+	m, err := schema.RealmToProto(r)
+	require.NoError(t, err)
+	b, err := m.Marshal()
+	require.NoError(t, err)
+	var m2 schemapb.Realm
+	require.NoError(t, m2.Unmarshal(b))
+	require.Equal(t, m, &m2)
+	require.Equal(t, int64(math.MaxInt64), m2.Schemas[0].Sequences[0].Max)
+
+	got, err := schema.RealmFromProto(&m2)
+	require.NoError(t, err)
+	require.True(t, r.Equal(got))
+	require.Equal(t, int64(math.MaxInt64), got.Schemas[0].Sequences[0].Max)
+	require.Equal(t, int64(math.MinInt64+1), got.Schemas[0].Sequences[0].Min)
+	users, pets := got.Schemas[0].Tables[0], got.Schemas[0].Tables[1]
+	require.True(t, users == pets.ForeignKeys[0].RefTable)
+	require.True(t, pets == got.Schemas[0].Sequences[0].Owner.T)
+
+	_, err = schema.RealmFromProto(&schemapb.Realm{
+		Schemas: []*schemapb.Schema{{Name: "s", Tables: []*schemapb.Table{{Name: "t", Columns: []*schemapb.Column{{Name: "c", Type: &schemapb.ColumnType{Type: &schemapb.Value{Kind: "unknown.Type"}}}}}}}},
+	})
+	require.EqualError(t, err, `sql/schema: unknown type "unknown.Type". Was the package that defines it imported?`)
+}
+
+func TestProto_Generated(t *testing.T) {
+	want, err := protogen.Generate("schema.proto")
+	require.NoError(t, err)
+	got, err := os.ReadFile("schemapb/schema.pb.go")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got), "run go generate to update the generated code")
+}
@@ -10,16 +10,20 @@ type (
 	Realm struct {
 		Schemas []*Schema
 		Attrs   []Attr
+		Objects []Object // Driver specific objects (e.g. foreign servers).
 	}
 
 	// A Schema describes a database schema (i.e. named database).
 	Schema struct {
-		Name    string
-		Realm   *Realm
-		Tables  []*Table
-		Views   []*View
-		Attrs   []Attr   // Attrs and options.
-		Objects []Object // Driver specific objects.
+		Name      string
+		Realm     *Realm
+		Tables    []*Table
+		Views     []*View
+		Funcs     []*Func
+		Procs     []*Proc
+		Sequences []*Sequence
+		Attrs     []Attr   // Attrs and options.
+		Objects   []Object // Driver specific objects.
 	}
 
 	// An Object represents a generic database object.
@@ -37,17 +41,113 @@ type (
 		Indexes     []*Index
 		PrimaryKey  *Index
 		ForeignKeys []*ForeignKey
-		Attrs       []Attr // Attrs, constraints and options.
+		Attrs       []Attr     // Attrs, constraints and options.
+		Triggers    []*Trigger // Triggers on the table.
 	}
 
 	// A View represents a view definition.
 	View struct {
-		Name    string
-		Def     string
-		Schema  *Schema
-		Columns []*Column
-		Attrs   []Attr   // Attrs and options.
-		Deps    []Object // Tables and views used in view definition.
+		Name     string
+		Def      string
+		Schema   *Schema
+		Columns  []*Column
+		Attrs    []Attr     // Attrs and options.
+		Deps     []Object   // Tables and views used in view definition.
+		Triggers []*Trigger // Triggers on the view.
+	}
+
+	// A Trigger represents a trigger definition.
+	Trigger struct {
+		Name string
+		// The table or the view that the trigger is attached to.
+		// Only one of them is set.
+		Table *Table
+		View  *View
+		// The action time of the trigger (BEFORE, AFTER or INSTEAD OF),
+		// the events that fire it, and if it fires for each row or for
+		// each statement.
+		ActionTime TriggerTime
+		Events     []TriggerEvent
+		For        TriggerFor
+		// Body holds the statements or the function call that are executed
+		// when the trigger is fired, e.g. "EXECUTE FUNCTION f()" in PostgreSQL
+		// or "BEGIN ... END" in MySQL and SQLite.
+		Body  string
+		Attrs []Attr   // WHEN expression, REFERENCING clause, etc.
+		Deps  []Object // Objects used by the trigger body.
+	}
+
+	// A Func represents a function definition.
+	Func struct {
+		Name   string
+		Schema *Schema
+		Args   []*FuncArg
+		// The return type of the function, or the columns
+		// of functions that return tables (RETURNS TABLE).
+		Ret      Type
+		RetTable []*Column
+		Lang     string   // Language of the body, e.g. SQL or PLpgSQL.
+		Body     string   // Body of the function, without its signature.
+		Attrs    []Attr   // Volatility, security, etc.
+		Deps     []Object // Objects used by the function body.
+	}
+
+	// A Proc represents a procedure definition.
+	Proc struct {
+		Name   string
+		Schema *Schema
+		Args   []*FuncArg
+		Lang   string   // Language of the body, e.g. SQL or PLpgSQL.
+		Body   string   // Body of the procedure, without its signature.
+		Attrs  []Attr   // Security, etc.
+		Deps   []Object // Objects used by the procedure body.
+	}
+
+	// A Sequence represents a sequence definition. The zero value
+	// of its numeric options means the database default is used.
+	Sequence struct {
+		Name      string
+		Schema    *Schema
+		Type      Type // Optional data type, e.g. bigint.
+		Start     int64
+		Increment int64
+		Min, Max  int64
+		Cache     int64
+		Cycle     bool
+		Owner     SequenceOwner // Optional owner column.
+		Attrs     []Attr
+	}
+
+	// SequenceOwner describes the column that owns a sequence, for
+	// example, the OWNED BY clause in PostgreSQL. Sequences that are
+	// owned by a column are dropped with their column or table.
+	SequenceOwner struct {
+		T *Table
+		C *Column
+	}
+
+	// A FuncArg represents an argument of a function or a procedure.
+	FuncArg struct {
+		Name    string      // Optional name.
+		Type    Type        // Argument type.
+		Mode    FuncArgMode // Optional mode.
+		Default Expr        // Optional default value.
+		Attrs   []Attr
+	}
+
+	// FuncArgMode represents the mode of a function or a procedure argument.
+	FuncArgMode string
+
+	// TriggerTime represents the action time of a trigger.
+	TriggerTime string
+
+	// TriggerFor represents the FOR EACH clause of a trigger.
+	TriggerFor string
+
+	// TriggerEvent represents an event that fires a trigger.
+	TriggerEvent struct {
+		Name    string    // INSERT, UPDATE, DELETE or TRUNCATE.
+		Columns []*Column // Columns of UPDATE OF events.
 	}
 
 	// A Column represents a column definition.
@@ -114,6 +214,16 @@ func (r *Realm) Schema(name string) (*Schema, bool) {
 	return nil, false
 }
 
+// Object returns the first realm-level object that matched the given predicate.
+func (r *Realm) Object(f func(Object) bool) (Object, bool) {
+	for _, o := range r.Objects {
+		if f(o) {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
 // Table returns the first table that matched the given name.
 func (s *Schema) Table(name string) (*Table, bool) {
 	for _, t := range s.Tables {
@@ -134,6 +244,36 @@ func (s *Schema) View(name string) (*View, bool) {
 	return nil, false
 }
 
+// Func returns the first function that matched the given name.
+func (s *Schema) Func(name string) (*Func, bool) {
+	for _, f := range s.Funcs {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Proc returns the first procedure that matched the given name.
+func (s *Schema) Proc(name string) (*Proc, bool) {
+	for _, p := range s.Procs {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// Sequence returns the first sequence that matched the given name.
+func (s *Schema) Sequence(name string) (*Sequence, bool) {
+	for _, sq := range s.Sequences {
+		if sq.Name == name {
+			return sq, true
+		}
+	}
+	return nil, false
+}
+
 // Object returns the first object that matched the given predicate.
 func (s *Schema) Object(f func(Object) bool) (Object, bool) {
 	for _, o := range s.Objects {
@@ -174,6 +314,57 @@ func (t *Table) ForeignKey(symbol string) (*ForeignKey, bool) {
 	return nil, false
 }
 
+// Generated returns the generation expression of the column,
+// and reports if it is a generated column.
+func (c *Column) Generated() (*GeneratedExpr, bool) {
+	for _, a := range c.Attrs {
+		if x, ok := a.(*GeneratedExpr); ok {
+			return x, true
+		}
+	}
+	return nil, false
+}
+
+// Enforced reports if the CHECK constraint is enforced.
+func (c *Check) Enforced() bool {
+	for _, a := range c.Attrs {
+		if e, ok := a.(*Enforced); ok {
+			return e.V
+		}
+	}
+	return true
+}
+
+// Trigger returns the first trigger that matched the given name.
+func (t *Table) Trigger(name string) (*Trigger, bool) {
+	for _, tr := range t.Triggers {
+		if tr.Name == name {
+			return tr, true
+		}
+	}
+	return nil, false
+}
+
+// Trigger returns the first trigger that matched the given name.
+func (v *View) Trigger(name string) (*Trigger, bool) {
+	for _, tr := range v.Triggers {
+		if tr.Name == name {
+			return tr, true
+		}
+	}
+	return nil, false
+}
+
+// Materialized reports if the view is a materialized view.
+func (v *View) Materialized() bool {
+	for _, a := range v.Attrs {
+		if _, ok := a.(*Materialized); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Column returns the first column that matched the given name.
 func (v *View) Column(name string) (*Column, bool) {
 	for _, c := range v.Columns {
@@ -366,19 +557,80 @@ type (
 		Attrs []Attr // Additional attributes (e.g. ENFORCED).
 	}
 
+	// Enforced describes the ENFORCED or the NOT ENFORCED clause of a CHECK
+	// constraint. A constraint without this attribute is enforced.
+	Enforced struct {
+		V bool // V indicates if the CHECK is enforced or not.
+	}
+
 	// GeneratedExpr describes the expression used for generating
 	// the value of a generated/virtual column.
 	GeneratedExpr struct {
 		Expr string
-		Type string // Optional type. e.g. STORED or VIRTUAL.
+		Type string // Optional type. e.g. GeneratedStored or GeneratedVirtual.
 	}
 
 	// ViewCheckOption describes the standard 'WITH CHECK OPTION clause' of a view.
 	ViewCheckOption struct {
 		V string // LOCAL, CASCADED, NONE, or driver specific.
 	}
+
+	// Materialized describes a materialized view, whose results are stored
+	// in the database. e.g. CREATE MATERIALIZED VIEW in PostgreSQL.
+	Materialized struct{}
+
+	// RawClause describes a table or column option that is not modeled by Atlas, and
+	// is emitted verbatim by drivers at the end of the element definition. For example:
+	//
+	//	t.AddAttrs(&schema.RawClause{X: "ROW_FORMAT=COMPRESSED"})
+	//
+	RawClause struct {
+		X string
+	}
+)
+
+// A list of known generated column types. Drivers may support only some of
+// them, use other names (e.g. PERSISTENT in MariaDB), or a different default
+// type for expressions without an explicit type.
+const (
+	GeneratedStored  = "STORED"
+	GeneratedVirtual = "VIRTUAL"
+)
+
+// A list of known function and procedure argument modes.
+const (
+	FuncArgModeIn       FuncArgMode = "IN"
+	FuncArgModeOut      FuncArgMode = "OUT"
+	FuncArgModeInOut    FuncArgMode = "INOUT"
+	FuncArgModeVariadic FuncArgMode = "VARIADIC"
 )
 
+// A list of known trigger action times.
+const (
+	TriggerTimeBefore  TriggerTime = "BEFORE"
+	TriggerTimeAfter   TriggerTime = "AFTER"
+	TriggerTimeInstead TriggerTime = "INSTEAD OF"
+)
+
+// A list of known trigger FOR EACH clauses.
+const (
+	TriggerForRow  TriggerFor = "ROW"
+	TriggerForStmt TriggerFor = "STATEMENT"
+)
+
+// A list of known trigger events.
+var (
+	TriggerEventInsert   = TriggerEvent{Name: "INSERT"}
+	TriggerEventUpdate   = TriggerEvent{Name: "UPDATE"}
+	TriggerEventDelete   = TriggerEvent{Name: "DELETE"}
+	TriggerEventTruncate = TriggerEvent{Name: "TRUNCATE"}
+)
+
+// TriggerEventUpdateOf returns an UPDATE OF event for the given columns.
+func TriggerEventUpdateOf(columns ...*Column) TriggerEvent {
+	return TriggerEvent{Name: "UPDATE", Columns: columns}
+}
+
 // A list of known view check options.
 const (
 	ViewCheckOptionNone     = "NONE"
@@ -389,6 +641,10 @@ const (
 // objects.
 func (*Table) obj()    {}
 func (*View) obj()     {}
+func (*Func) obj()     {}
+func (*Proc) obj()     {}
+func (*Sequence) obj() {}
+func (*Trigger) obj()  {}
 func (*EnumType) obj() {}
 
 // expressions.
@@ -411,11 +667,14 @@ func (*UnsupportedType) typ() {}
 
 // attributes.
 func (*Check) attr()           {}
+func (*Enforced) attr()        {}
 func (*Comment) attr()         {}
 func (*Charset) attr()         {}
 func (*Collation) attr()       {}
 func (*GeneratedExpr) attr()   {}
 func (*ViewCheckOption) attr() {}
+func (*Materialized) attr()    {}
+func (*RawClause) attr()       {}
 
 // UnderlyingExpr returns the underlying expression of x.
 func UnderlyingExpr(x Expr) Expr {
@@ -0,0 +1,219 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// A Diagnostic describes a problem that was found by Validate in a schema object.
+	Diagnostic struct {
+		Path string // Path of the object. e.g. schema "public" table "users" index "users_email".
+		Text string // Description of the problem.
+	}
+
+	// A ValidateError is returned by Validate when problems were found in the realm.
+	ValidateError struct {
+		Diagnostics []*Diagnostic
+	}
+
+	// ValidateOptions configures Validate.
+	ValidateOptions struct {
+		// ColumnDefault validates the default value of a column in a driver-specific
+		// way, in addition to the generic validation. For example, rejecting literal
+		// default values of BLOB and TEXT columns in MySQL.
+		ColumnDefault func(*Table, *Column) error
+	}
+
+	// ValidateOption allows configuring the ValidateOptions using functional options.
+	ValidateOption func(*ValidateOptions)
+
+	// validator holds the state of a Validate operation.
+	validator struct {
+		*ValidateOptions
+		realm *Realm
+		diags []*Diagnostic
+	}
+)
+
+// ValidateColumnDefault returns a ValidateOption that sets the driver-specific
+// validation of column default values.
+func ValidateColumnDefault(f func(*Table, *Column) error) ValidateOption {
+	return func(o *ValidateOptions) {
+		o.ColumnDefault = f
+	}
+}
+
+// Validate validates the realm, and returns a ValidateError with the problems that were
+// found in its objects, if any. It allows detecting problems in desired states before they
+// are planned and executed on the database, and fail with less descriptive errors. The
+// following problems are reported:
+//
+//   - Schemas, tables (or views), columns, indexes and foreign-keys with duplicate names.
+//   - Primary-keys and indexes with parts that reference columns of other tables, or with
+//     parts that do not reference any column or expression.
+//   - Foreign-keys that reference tables that do not exist in the realm, or columns that do
+//     not exist in their tables, and foreign-keys with mismatched number of columns.
+//   - Columns with both a default value and a generation expression, and invalid default
+//     values, as reported by the ColumnDefault option.
+func Validate(r *Realm, opts ...ValidateOption) error {
+	v := &validator{ValidateOptions: &ValidateOptions{}, realm: r}
+	for _, opt := range opts {
+		opt(v.ValidateOptions)
+	}
+	names := make(map[string]bool, len(r.Schemas))
+	for _, s := range r.Schemas {
+		path := fmt.Sprintf("schema %q", s.Name)
+		if names[s.Name] {
+			v.report(path, "duplicate schema name")
+		}
+		names[s.Name] = true
+		v.schema(path, s)
+	}
+	if len(v.diags) > 0 {
+		return &ValidateError{Diagnostics: v.diags}
+	}
+	return nil
+}
+
+// Error implements the error interface.
+func (e *ValidateError) Error() string {
+	diags := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		diags[i] = d.Path + ": " + d.Text
+	}
+	return fmt.Sprintf("invalid schema: %s", strings.Join(diags, ", "))
+}
+
+func (v *validator) schema(path string, s *Schema) {
+	names := make(map[string]bool, len(s.Tables)+len(s.Views))
+	for _, t := range s.Tables {
+		tpath := fmt.Sprintf("%s table %q", path, t.Name)
+		if names[t.Name] {
+			v.report(tpath, "duplicate table name")
+		}
+		names[t.Name] = true
+		v.table(tpath, t)
+	}
+	for _, vw := range s.Views {
+		if names[vw.Name] {
+			v.report(fmt.Sprintf("%s view %q", path, vw.Name), "name is already used by another table or view")
+		}
+		names[vw.Name] = true
+	}
+}
+
+func (v *validator) table(path string, t *Table) {
+	columns := make(map[string]bool, len(t.Columns))
+	for _, c := range t.Columns {
+		cpath := fmt.Sprintf("%s column %q", path, c.Name)
+		if columns[c.Name] {
+			v.report(cpath, "duplicate column name")
+		}
+		columns[c.Name] = true
+		if _, ok := c.Generated(); ok && c.Default != nil {
+			v.report(cpath, "column cannot have both a default value and a generation expression")
+		}
+		if c.Default != nil && v.ColumnDefault != nil {
+			if err := v.ColumnDefault(t, c); err != nil {
+				v.report(cpath, err.Error())
+			}
+		}
+	}
+	if t.PrimaryKey != nil {
+		v.index(path+" primary key", t, t.PrimaryKey)
+	}
+	indexes := make(map[string]bool, len(t.Indexes))
+	for _, idx := range t.Indexes {
+		ipath := fmt.Sprintf("%s index %q", path, idx.Name)
+		if idx.Name != "" && indexes[idx.Name] {
+			v.report(ipath, "duplicate index name")
+		}
+		indexes[idx.Name] = true
+		v.index(ipath, t, idx)
+	}
+	fks := make(map[string]bool, len(t.ForeignKeys))
+	for _, fk := range t.ForeignKeys {
+		fpath := fmt.Sprintf("%s foreign key %q", path, fk.Symbol)
+		if fk.Symbol != "" && fks[fk.Symbol] {
+			v.report(fpath, "duplicate foreign key name")
+		}
+		fks[fk.Symbol] = true
+		v.foreignKey(fpath, t, fk)
+	}
+}
+
+func (v *validator) index(path string, t *Table, idx *Index) {
+	if len(idx.Parts) == 0 {
+		v.report(path, "index has no parts")
+	}
+	for i, p := range idx.Parts {
+		switch {
+		case p.C == nil && p.X == nil:
+			v.report(path, fmt.Sprintf("part %d does not reference a column or an expression", i))
+		case p.C != nil && !hasColumn(t, p.C):
+			v.report(path, fmt.Sprintf("part %d references column %q that does not exist in the table", i, p.C.Name))
+		}
+	}
+}
+
+func (v *validator) foreignKey(path string, t *Table, fk *ForeignKey) {
+	for _, c := range fk.Columns {
+		if !hasColumn(t, c) {
+			v.report(path, fmt.Sprintf("column %q does not exist in the table", c.Name))
+		}
+	}
+	switch {
+	case fk.RefTable == nil:
+		v.report(path, "missing referenced table")
+		return
+	case !v.hasTable(t, fk.RefTable):
+		v.report(path, fmt.Sprintf("referenced table %q does not exist", fk.RefTable.Name))
+		return
+	}
+	if len(fk.Columns) != len(fk.RefColumns) {
+		v.report(path, fmt.Sprintf("mismatched number of columns (%d) and referenced columns (%d)", len(fk.Columns), len(fk.RefColumns)))
+	}
+	for _, c := range fk.RefColumns {
+		if !hasColumn(fk.RefTable, c) {
+			v.report(path, fmt.Sprintf("referenced column %q does not exist in table %q", c.Name, fk.RefTable.Name))
+		}
+	}
+}
+
+// hasTable reports if the referenced table exists in the realm. Tables that are not
+// linked to a schema are looked up in the schema of the referencing table.
+func (v *validator) hasTable(t, ref *Table) bool {
+	s := ref.Schema
+	if s == nil {
+		s = t.Schema
+	}
+	if s == nil {
+		return false
+	}
+	s, ok := v.realm.Schema(s.Name)
+	if !ok {
+		return false
+	}
+	t2, ok := s.Table(ref.Name)
+	return ok && t2 == ref
+}
+
+// hasColumn reports if the column is a column of the table.
+func hasColumn(t *Table, c *Column) bool {
+	for _, tc := range t.Columns {
+		if tc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// report records a diagnostic.
+func (v *validator) report(path, text string) {
+	v.diags = append(v.diags, &Diagnostic{Path: path, Text: text})
+}
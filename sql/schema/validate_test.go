@@ -0,0 +1,60 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	var (
+		users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+		pets  = schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("owner_id", "int"))
+	)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns...))
+	pets.AddForeignKeys(schema.NewForeignKey("owner").AddColumns(pets.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	r := schema.NewRealm(schema.New("public").AddTables(users, pets).AddViews(schema.NewView("v", "SELECT 1")))
+	require.NoError(t, schema.Validate(r))
+
+	// Dangling references and duplicate names.
+	other := schema.NewTable("other").AddColumns(schema.NewIntColumn("id", "int"))
+	pets.AddColumns(schema.NewIntColumn("id", "bigint"))
+	pets.AddIndexes(
+		schema.NewIndex("pets_owner").AddColumns(pets.Columns[1]),
+		schema.NewIndex("pets_owner").AddColumns(other.Columns[0]),
+		schema.NewIndex("pets_expr").AddParts(schema.NewIndexPart()),
+	)
+	pets.AddForeignKeys(schema.NewForeignKey("other").AddColumns(pets.Columns[1]).SetRefTable(other).AddRefColumns(other.Columns[0]))
+	r.Schemas[0].AddViews(schema.NewView("users", "SELECT 1"))
+	err := schema.Validate(r)
+	var verr *schema.ValidateError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, []*schema.Diagnostic{
+		{Path: `schema "public" table "pets" column "id"`, Text: "duplicate column name"},
+		{Path: `schema "public" table "pets" index "pets_owner"`, Text: "duplicate index name"},
+		{Path: `schema "public" table "pets" index "pets_owner"`, Text: `part 0 references column "id" that does not exist in the table`},
+		{Path: `schema "public" table "pets" index "pets_expr"`, Text: "part 0 does not reference a column or an expression"},
+		{Path: `schema "public" table "pets" foreign key "other"`, Text: `referenced table "other" does not exist`},
+		{Path: `schema "public" view "users"`, Text: "name is already used by another table or view"},
+	}, verr.Diagnostics)
+	require.Contains(t, err.Error(), `invalid schema: schema "public" table "pets" column "id": duplicate column name, `)
+
+	// Driver-specific validation of default values.
+	r = schema.NewRealm(schema.New("public").AddTables(
+		schema.NewTable("t").AddColumns(
+			schema.NewIntColumn("a", "int").SetDefault(&schema.Literal{V: "1"}),
+			schema.NewIntColumn("b", "int").SetDefault(&schema.Literal{V: "1"}).SetGeneratedExpr(&schema.GeneratedExpr{Expr: "a+1"}),
+		),
+	))
+	err = schema.Validate(r, schema.ValidateColumnDefault(func(_ *schema.Table, c *schema.Column) error {
+		return errors.New("unsupported default")
+	}))
+	require.EqualError(t, err, `invalid schema: schema "public" table "t" column "a": unsupported default, schema "public" table "t" column "b": column cannot have both a default value and a generation expression, schema "public" table "t" column "b": unsupported default`)
+}
@@ -74,6 +74,7 @@ var (
 	codeModUniqueI  = sqlcheck.Code("MF102")
 	codeAddNotNullC = sqlcheck.Code("MF103")
 	codeModNotNullC = sqlcheck.Code("MF104")
+	codeModTypeC    = sqlcheck.Code("MF105")
 )
 
 // Diagnostics runs the common analysis on the file and returns its diagnostics.
@@ -130,6 +131,14 @@ func (a *Analyzer) Diagnostics(_ context.Context, p *sqlcheck.Pass) (diags []sql
 						diags = append(diags, d...)
 					}
 				case *schema.ModifyColumn:
+					// The conversion of the column values is classified by the driver.
+					if c.Conversion.Lossy() && p.File.TableSpan(m.T)&sqlcheck.SpanAdded == 0 {
+						diags = append(diags, sqlcheck.Diagnostic{
+							Code: codeModTypeC,
+							Pos:  sc.Stmt.Pos,
+							Text: fmt.Sprintf("Modifying the type of column %q is a %s conversion that might fail or truncate its values", c.To.Name, c.Conversion),
+						})
+					}
 					switch {
 					case p.File.TableSpan(m.T)&sqlcheck.SpanAdded == 1 || !(c.From.Type.Null && !c.To.Type.Null):
 					case a.ModifyNotNull != nil:
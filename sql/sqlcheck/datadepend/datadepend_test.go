@@ -177,6 +177,61 @@ func TestAnalyzer_ModifyNullability(t *testing.T) {
 	require.Equal(t, `Modifying nullable column "a" to non-nullable might fail in case it contains NULL values`, report.Diagnostics[0].Text)
 }
 
+func TestAnalyzer_ModifyType(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		pass   = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Text: "ALTER TABLE users",
+						},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: schema.NewTable("users").
+									SetSchema(schema.New("test")).
+									AddColumns(
+										schema.NewStringColumn("a", "varchar", schema.StringSize(50)),
+										schema.NewIntColumn("b", "bigint"),
+									),
+								Changes: []schema.Change{
+									&schema.ModifyColumn{
+										From:       schema.NewStringColumn("a", "varchar", schema.StringSize(255)),
+										To:         schema.NewStringColumn("a", "varchar", schema.StringSize(50)),
+										Change:     schema.ChangeType,
+										Conversion: schema.ConversionNarrowing,
+									},
+									// Widening conversions are not reported.
+									&schema.ModifyColumn{
+										From:       schema.NewIntColumn("b", "int"),
+										To:         schema.NewIntColumn("b", "bigint"),
+										Change:     schema.ChangeType,
+										Conversion: schema.ConversionWidening,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	az, err := datadepend.New(nil, datadepend.Handler{})
+	require.NoError(t, err)
+	err = az.Analyze(context.Background(), pass)
+	require.NoError(t, err)
+	require.Equal(t, "data dependent changes detected", report.Text)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "MF105", report.Diagnostics[0].Code)
+	require.Equal(t, `Modifying the type of column "a" is a narrowing conversion that might fail or truncate its values`, report.Diagnostics[0].Text)
+}
+
 func TestAnalyzer_Options(t *testing.T) {
 	var (
 		report *sqlcheck.Report
@@ -76,6 +76,37 @@ func TestAnalyzer_DropTable(t *testing.T) {
 	require.Equal(t, `Dropping table "posts"`, report.Diagnostics[1].Text)
 }
 
+func TestAnalyzer_PlanFile(t *testing.T) {
+	users := schema.NewTable("users").SetSchema(schema.New("test"))
+	users.AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("age", "int"))
+	var (
+		report *sqlcheck.Report
+		drop   = &schema.ModifyTable{T: users, Changes: schema.Changes{&schema.DropColumn{C: users.Columns[1]}}}
+		pass   = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{},
+			File: sqlcheck.PlanFile(&migrate.Plan{
+				Name: "drop_age",
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE TABLE `pets` (`id` int)", Comment: "create \"pets\" table", Source: &schema.AddTable{T: schema.NewTable("pets").SetSchema(users.Schema)}},
+					{Cmd: "ALTER TABLE `users` DROP COLUMN `age`", Comment: "modify \"users\" table", Source: drop},
+				},
+			}),
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	require.Equal(t, "drop_age.sql", pass.File.Name())
+	require.Equal(t, "-- Create \"pets\" table\nCREATE TABLE `pets` (`id` int);\n-- Modify \"users\" table\nALTER TABLE `users` DROP COLUMN `age`;\n", string(pass.File.Bytes()))
+	require.Len(t, pass.File.Sum, 2)
+	az, err := destructive.New(nil)
+	require.NoError(t, err)
+	require.Error(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, `Dropping non-virtual column "age"`, report.Diagnostics[0].Text)
+	require.Equal(t, "ALTER TABLE `users` DROP COLUMN `age`;", string(pass.File.Bytes()[report.Diagnostics[0].Pos:len(pass.File.Bytes())-1]))
+}
+
 func TestAnalyzer_SkipTemporaryTable(t *testing.T) {
 	var (
 		report *sqlcheck.Report
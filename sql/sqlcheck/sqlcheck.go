@@ -11,7 +11,10 @@
 package sqlcheck
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 
 	"ariga.io/atlas/schemahcl"
@@ -125,6 +128,42 @@ func (f AnalyzerFunc) Analyze(ctx context.Context, p *Pass) error {
 	return f(ctx, p)
 }
 
+// PlanFile returns a File that represents the given migration plan. It allows running
+// analyzers on planned changes before they are written to a migration directory or
+// applied to the database. The statements of the file are formatted the same way the
+// migrate.DefaultFormatter formats them, and each one is associated with the schema
+// change that caused it, if any.
+func PlanFile(p *migrate.Plan) *File {
+	var (
+		f   = &File{}
+		buf bytes.Buffer
+	)
+	for _, c := range p.Changes {
+		stmt := &migrate.Stmt{Text: c.Cmd + ";"}
+		if c.Comment != "" {
+			stmt.Comments = []string{fmt.Sprintf("-- %s%s\n", strings.ToUpper(c.Comment[:1]), c.Comment[1:])}
+			buf.WriteString(stmt.Comments[0])
+		}
+		stmt.Pos = buf.Len()
+		buf.WriteString(stmt.Text + "\n")
+		fc := &Change{Stmt: stmt}
+		if c.Source != nil {
+			fc.Changes = schema.Changes{c.Source}
+			// Statements that were generated by the same change are summarized once.
+			if len(f.Sum) == 0 || f.Sum[len(f.Sum)-1] != c.Source {
+				f.Sum = append(f.Sum, c.Source)
+			}
+		}
+		f.Changes = append(f.Changes, fc)
+	}
+	name := p.Name
+	if p.Version != "" {
+		name = p.Version + "_" + name
+	}
+	f.File = migrate.NewLocalFile(name+".sql", buf.Bytes())
+	return f
+}
+
 // ReportWriterFunc is a function that implements Reporter.
 type ReportWriterFunc func(Report)
 
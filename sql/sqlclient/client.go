@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"sort"
 	"sync"
 
 	"ariga.io/atlas/schemahcl"
@@ -199,14 +200,10 @@ func OpenURL(ctx context.Context, u *url.URL, opts ...OpenOption) (*Client, erro
 			return nil, err
 		}
 	}
-	if u.Scheme == "" {
-		return nil, errors.New("sql/sqlclient: missing driver. See: https://atlasgo.io/url")
-	}
-	v, ok := drivers.Load(u.Scheme)
-	if !ok {
-		return nil, fmt.Errorf("sql/sqlclient: unknown driver %q. See: https://atlasgo.io/url", u.Scheme)
+	drv, err := lookup(u)
+	if err != nil {
+		return nil, err
 	}
-	drv := v.(*driver)
 	// If there is a schema given and the driver allows to change the schema for the url, do it.
 	if cfg.schema != nil {
 		sc, ok := drv.parser.(SchemaChanger)
@@ -359,7 +356,19 @@ func RegisterTxOpener(open TxOpener) RegisterOption {
 	}
 }
 
-// Register registers a client Opener (i.e. creator) with the given name.
+// Register registers a client Opener (i.e. creator) with the given name. The name
+// and the registered flavours are used as the URL schemes of the driver. Drivers
+// that are not part of Atlas can be registered by external packages in the same
+// way, and are resolved by Open and OpenURL like the builtin drivers.
+//
+//	func init() {
+//		sqlclient.Register(
+//			"mydb",
+//			sqlclient.DriverOpener(Open),
+//			sqlclient.RegisterURLParser(parser{}),
+//			sqlclient.RegisterCodec(MarshalHCL, EvalHCL),
+//		)
+//	}
 func Register(name string, opener Opener, opts ...RegisterOption) {
 	if opener == nil {
 		panic("sql/sqlclient: Register opener is nil")
@@ -402,3 +411,41 @@ func Register(name string, opener Opener, opts ...RegisterOption) {
 		drivers.Store(f, drv)
 	}
 }
+
+// Drivers returns the sorted URL schemes of the registered drivers,
+// including their flavours.
+func Drivers() []string {
+	var names []string
+	drivers.Range(func(k, _ any) bool {
+		names = append(names, k.(string))
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// ParseURL parses the given url string using the URL parser of its
+// registered driver, without opening a connection to the database.
+func ParseURL(s string) (*URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("sql/sqlclient: parse url: %w", err)
+	}
+	drv, err := lookup(u)
+	if err != nil {
+		return nil, err
+	}
+	return drv.parser.ParseURL(u), nil
+}
+
+// lookup returns the registered driver of the URL scheme.
+func lookup(u *url.URL) (*driver, error) {
+	if u.Scheme == "" {
+		return nil, errors.New("sql/sqlclient: missing driver. See: https://atlasgo.io/url")
+	}
+	v, ok := drivers.Load(u.Scheme)
+	if !ok {
+		return nil, fmt.Errorf("sql/sqlclient: unknown driver %q. See: https://atlasgo.io/url", u.Scheme)
+	}
+	return v.(*driver), nil
+}
@@ -60,6 +60,33 @@ func TestRegisterOpen(t *testing.T) {
 	require.EqualError(t, err, `sql/sqlclient: unknown driver "postgres". See: https://atlasgo.io/url`)
 }
 
+func TestDrivers(t *testing.T) {
+	sqlclient.Register(
+		"custom",
+		sqlclient.OpenerFunc(func(context.Context, *url.URL) (*sqlclient.Client, error) {
+			return &sqlclient.Client{}, nil
+		}),
+		sqlclient.RegisterFlavours("custom+tcp"),
+		sqlclient.RegisterURLParser(sqlclient.URLParserFunc(func(u *url.URL) *sqlclient.URL {
+			return &sqlclient.URL{URL: u, DSN: u.Host, Schema: u.Path[1:]}
+		})),
+	)
+	names := sqlclient.Drivers()
+	require.Contains(t, names, "custom")
+	require.Contains(t, names, "custom+tcp")
+	require.IsIncreasing(t, names)
+
+	u, err := sqlclient.ParseURL("custom+tcp://localhost:1234/public")
+	require.NoError(t, err)
+	require.Equal(t, "localhost:1234", u.DSN)
+	require.Equal(t, "public", u.Schema)
+
+	_, err = sqlclient.ParseURL("localhost:1234")
+	require.EqualError(t, err, `sql/sqlclient: unknown driver "localhost". See: https://atlasgo.io/url`)
+	_, err = sqlclient.ParseURL("/path")
+	require.EqualError(t, err, `sql/sqlclient: missing driver. See: https://atlasgo.io/url`)
+}
+
 func TestOpen_Errors(t *testing.T) {
 	c, err := sqlclient.Open(context.Background(), "missing")
 	require.EqualError(t, err, `sql/sqlclient: missing driver. See: https://atlasgo.io/url`)
@@ -17,6 +17,9 @@ import (
 // and use a set of rules to define the type affinity.
 // See: https://www.sqlite.org/datatype3.html
 func FormatType(t schema.Type) (string, error) {
+	if f, ok, err := customTypes.FormatType(t); ok {
+		return f, err
+	}
 	var f string
 	switch t := t.(type) {
 	case *schema.BoolType:
@@ -42,6 +45,10 @@ func FormatType(t schema.Type) (string, error) {
 	case *UUIDType:
 		f = strings.ToLower(t.T)
 	case *schema.UnsupportedType:
+		// The ANY type has no affinity, and it is meaningful only in STRICT tables.
+		if strings.EqualFold(t.T, TypeAny) {
+			return TypeAny, nil
+		}
 		return "", fmt.Errorf("sqlite: unsupported type: %q", t.T)
 	default:
 		return "", fmt.Errorf("sqlite: invalid schema type: %T", t)
@@ -53,6 +60,9 @@ func FormatType(t schema.Type) (string, error) {
 // It is expected to be one of the types in https://www.sqlite.org/datatypes.html,
 // or some of the common types used by ORMs like Ent.
 func ParseType(c string) (schema.Type, error) {
+	if t, ok, err := customTypes.ParseType(c); ok {
+		return t, err
+	}
 	// A datatype may be zero or more names.
 	if c == "" {
 		return &schema.BinaryType{T: "blob"}, nil
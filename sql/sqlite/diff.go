@@ -30,8 +30,8 @@ func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
 
 // SchemaObjectDiff returns a changeset for migrating schema objects from
 // one state to the other.
-func (*diff) SchemaObjectDiff(_, _ *schema.Schema) ([]schema.Change, error) {
-	return nil, nil
+func (*diff) SchemaObjectDiff(from, to *schema.Schema) ([]schema.Change, error) {
+	return virtualTablesDiff(from, to), nil
 }
 
 // TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
@@ -49,16 +49,64 @@ func (d *diff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
 			})
 		}
 	}
-	return append(changes, sqlx.CheckDiff(from, to)...), nil
+	return append(changes, checksDiff(from, to)...), nil
+}
+
+// checksDiff returns the changes for migrating the CHECK constraints of the table. SQLite keeps
+// the expressions as they were written by the user, and therefore, they are compared after their
+// formatting is normalized.
+func checksDiff(from, to *schema.Table) []schema.Change {
+	var (
+		drops   = make(map[string]int)
+		changes = sqlx.CheckDiff(from, to, func(c1, c2 *schema.Check) bool {
+			return checkExpr(c1) == checkExpr(c2)
+		})
+	)
+	// Unnamed constraints are matched by their expressions. Hence,
+	// a drop and an addition of equal expressions are omitted.
+	for i, c := range changes {
+		if d, ok := c.(*schema.DropCheck); ok && d.C.Name == "" {
+			drops[checkExpr(d.C)] = i
+		}
+	}
+	skip := make(map[int]bool)
+	for i, c := range changes {
+		a, ok := c.(*schema.AddCheck)
+		if !ok || a.C.Name != "" {
+			continue
+		}
+		if j, ok := drops[checkExpr(a.C)]; ok && !skip[j] {
+			skip[i], skip[j] = true, true
+		}
+	}
+	filtered := make([]schema.Change, 0, len(changes))
+	for i, c := range changes {
+		if !skip[i] {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// checkExpr returns the normalized expression of the CHECK constraint.
+func checkExpr(c *schema.Check) string {
+	return sqlx.MayWrap(normalizeDef(c.Expr))
 }
 
 func (d *diff) ViewAttrChanged(_, _ *schema.View) bool {
 	return false // Not implemented.
 }
 
+// IndexCommentChanged implements the sqlx.IndexCommentDiffer interface. SQLite does not
+// support comments, and changing them should not cause the index to be recreated.
+func (d *diff) IndexCommentChanged(_, _ *schema.Index) bool {
+	return false
+}
+
 // ColumnChange returns the schema changes (if any) for migrating one column to the other.
+// Comments are ignored, as SQLite does not support them, and they are never inspected.
 func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column) (schema.ChangeKind, error) {
-	change := sqlx.CommentChange(from.Attrs, to.Attrs)
+	var change schema.ChangeKind
 	if from.Type.Null != to.Type.Null {
 		change |= schema.ChangeNull
 	}
@@ -72,12 +120,29 @@ func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column) (schema.Ch
 	if changed := d.defaultChanged(from, to); changed {
 		change |= schema.ChangeDefault
 	}
+	if !strings.EqualFold(collation(from.Attrs), collation(to.Attrs)) {
+		change |= schema.ChangeCollate
+	}
 	if d.generatedChanged(from, to) {
 		change |= schema.ChangeGenerated
 	}
 	return change, nil
 }
 
+// TypeConversion classifies the conversion of the column values, when its type affinity is
+// changed. SQLite does not enforce the sizes of types, and columns with TEXT or BLOB affinity
+// can store values of all other types.
+func (*diff) TypeConversion(from, to *schema.Column) schema.TypeConversion {
+	switch to.Type.Type.(type) {
+	case *schema.StringType, *schema.BinaryType:
+		return schema.ConversionWidening
+	}
+	// Integers and real numbers are stored in up to 8 bytes.
+	return sqlx.ClassifyConversion(from.Type.Type, to.Type.Type, map[string]int64{
+		"integer": 8, "int": 8, "bigint": 8, "real": 8, "double": 8, "float": 8,
+	})
+}
+
 // typeChanged reports if the column type was changed.
 func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 	fromT, toT := from.Type.Type, to.Type.Type
@@ -90,26 +155,36 @@ func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 
 // defaultChanged reports if the default value of a column was changed.
 func (d *diff) defaultChanged(from, to *schema.Column) bool {
-	d1, ok1 := sqlx.DefaultValue(from)
-	d2, ok2 := sqlx.DefaultValue(to)
-	if ok1 != ok2 {
-		return true
+	d1, ok1 := defaultKey(from)
+	d2, ok2 := defaultKey(to)
+	return ok1 != ok2 || d1 != d2
+}
+
+// defaultKey returns the normalized default value of the column used for comparison.
+// SQLite stores default values as they were written by the user. Hence, redundant
+// parentheses, quotes and the case of expressions (e.g. CURRENT_TIMESTAMP) are ignored.
+func defaultKey(c *schema.Column) (string, bool) {
+	x, ok := sqlx.DefaultValue(c)
+	if !ok {
+		return "", false
 	}
-	if d1 == d2 {
-		return false
+	x = unwrapParens(strings.TrimSpace(x))
+	if sqlx.IsQuoted(x, '"', '\'') {
+		if u, err := sqlx.Unquote(x); err == nil {
+			return u, true
+		}
 	}
-	x1, err1 := sqlx.Unquote(d1)
-	x2, err2 := sqlx.Unquote(d2)
-	return err1 != nil || err2 != nil || x1 != x2
+	// Unlike literals, the case of raw expressions
+	// is ignored, except for their quoted parts.
+	if _, ok := schema.UnderlyingExpr(c.Default).(*schema.RawExpr); ok {
+		return normalizeDef(x), true
+	}
+	return x, true
 }
 
 // generatedChanged reports if the generated expression of a column was changed.
 func (*diff) generatedChanged(from, to *schema.Column) bool {
-	var (
-		fromX, toX     schema.GeneratedExpr
-		fromHas, toHas = sqlx.Has(from.Attrs, &fromX), sqlx.Has(to.Attrs, &toX)
-	)
-	return fromHas != toHas || fromHas && (sqlx.MayWrap(fromX.Expr) != sqlx.MayWrap(toX.Expr) || storedOrVirtual(fromX.Type) != storedOrVirtual(toX.Type))
+	return sqlx.GeneratedChanged(from, to, storedOrVirtual)
 }
 
 // IsGeneratedIndexName reports if the index name was generated by the database.
@@ -124,14 +199,33 @@ func (d *diff) IsGeneratedIndexName(t *schema.Table, idx *schema.Index) bool {
 }
 
 // IndexAttrChanged reports if the index attributes were changed.
+// Index predicates are compared after their formatting is normalized,
+// as SQLite keeps them as they were written by the user.
 func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
 	var p1, p2 IndexPredicate
-	return sqlx.Has(from, &p1) != sqlx.Has(to, &p2) || (p1.P != p2.P && p1.P != sqlx.MayWrap(p2.P))
+	return sqlx.Has(from, &p1) != sqlx.Has(to, &p2) || sqlx.MayWrap(normalizeDef(p1.P)) != sqlx.MayWrap(normalizeDef(p2.P))
 }
 
 // IndexPartAttrChanged reports if the index-part attributes were changed.
-func (*diff) IndexPartAttrChanged(_, _ *schema.Index, _ int) bool {
-	return false
+func (*diff) IndexPartAttrChanged(from, to *schema.Index, i int) bool {
+	return !strings.EqualFold(partCollation(from.Parts[i]), partCollation(to.Parts[i]))
+}
+
+// collation returns the collation defined in the attributes, or BINARY, the default collation.
+func collation(attrs []schema.Attr) string {
+	if c := (schema.Collation{}); sqlx.Has(attrs, &c) && c.V != "" {
+		return c.V
+	}
+	return CollateBinary
+}
+
+// partCollation returns the collation of the index part. Unless
+// defined explicitly, the collation of the column is used.
+func partCollation(p *schema.IndexPart) string {
+	if c := (schema.Collation{}); p.C != nil && !sqlx.Has(p.Attrs, &c) {
+		return collation(p.C.Attrs)
+	}
+	return collation(p.Attrs)
 }
 
 // ReferenceChanged reports if the foreign key referential action was changed.
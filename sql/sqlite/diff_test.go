@@ -89,6 +89,127 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "normalized check expressions",
+			from: &schema.Table{
+				Name: "t1",
+				Attrs: []schema.Attr{
+					&schema.Check{Name: "positive", Expr: "(C1 > 0)"},
+					&schema.Check{Expr: "(length(name)  >  0 AND name <> 'A')"},
+				},
+			},
+			to: &schema.Table{
+				Name: "t1",
+				Attrs: []schema.Attr{
+					&schema.Check{Name: "positive", Expr: "c1 > 0"},
+					&schema.Check{Expr: "length(name) > 0 and name <> 'A'"},
+				},
+			},
+		},
+		func() testcase {
+			from := &schema.Table{
+				Name: "t1",
+				Attrs: []schema.Attr{
+					&schema.Check{Name: "positive", Expr: "(c1 > 0)"},
+					&schema.Check{Expr: "(name <> 'a')"},
+				},
+			}
+			to := &schema.Table{
+				Name: "t1",
+				Attrs: []schema.Attr{
+					&schema.Check{Name: "positive", Expr: "(c1 >= 0)"},
+					&schema.Check{Expr: "(name <> 'A')"},
+				},
+			}
+			return testcase{
+				name: "modify check expressions",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyCheck{From: from.Attrs[0].(*schema.Check), To: to.Attrs[0].(*schema.Check)},
+					&schema.DropCheck{C: from.Attrs[1].(*schema.Check)},
+					&schema.AddCheck{C: to.Attrs[1].(*schema.Check)},
+				},
+			}
+		}(),
+		func() testcase {
+			var (
+				from = schema.NewTable("t1").
+					AddColumns(
+						schema.NewStringColumn("c1", "text"),
+						schema.NewStringColumn("c2", "text").SetCollation("nocase"),
+						schema.NewStringColumn("c3", "text").SetCollation("BINARY"),
+					)
+				to = schema.NewTable("t1").
+					AddColumns(
+						schema.NewStringColumn("c1", "text").SetCollation("NOCASE"),
+						schema.NewStringColumn("c2", "text").SetCollation("NOCASE"),
+						schema.NewStringColumn("c3", "text"),
+					)
+			)
+			from.AddIndexes(
+				schema.NewIndex("c2").AddColumns(from.Columns[1]),
+				schema.NewIndex("c3").AddColumns(from.Columns[2]),
+			)
+			to.AddIndexes(
+				schema.NewIndex("c2").AddParts(schema.NewColumnPart(to.Columns[1]).AddAttrs(&schema.Collation{V: "NOCASE"})),
+				schema.NewIndex("c3").AddParts(schema.NewColumnPart(to.Columns[2]).AddAttrs(&schema.Collation{V: "RTRIM"})),
+			)
+			return testcase{
+				name: "collations",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{
+						From:   from.Columns[0],
+						To:     to.Columns[0],
+						Change: schema.ChangeCollate,
+					},
+					&schema.ModifyIndex{
+						From:   from.Indexes[1],
+						To:     to.Indexes[1],
+						Change: schema.ChangeParts,
+					},
+				},
+			}
+		}(),
+		func() testcase {
+			var (
+				from = schema.NewTable("t1").
+					AddColumns(
+						schema.NewTimeColumn("c1", "datetime").SetDefault(&schema.RawExpr{X: "CURRENT_TIMESTAMP"}),
+						schema.NewIntColumn("c2", "int").SetDefault(&schema.Literal{V: "(1)"}),
+						schema.NewStringColumn("c3", "text").SetDefault(&schema.Literal{V: "'a'"}),
+						schema.NewStringColumn("c4", "text").SetDefault(&schema.RawExpr{X: "lower('A')"}),
+						schema.NewStringColumn("c5", "text").SetDefault(&schema.Literal{V: "'A'"}),
+					)
+				to = schema.NewTable("t1").
+					AddColumns(
+						schema.NewTimeColumn("c1", "datetime").SetDefault(&schema.RawExpr{X: "(current_timestamp)"}),
+						schema.NewIntColumn("c2", "int").SetDefault(&schema.Literal{V: "1"}),
+						schema.NewStringColumn("c3", "text").SetDefault(&schema.Literal{V: "a"}),
+						schema.NewStringColumn("c4", "text").SetDefault(&schema.RawExpr{X: "(LOWER( 'a' ))"}),
+						schema.NewStringColumn("c5", "text").SetDefault(&schema.Literal{V: "'a'"}),
+					)
+			)
+			return testcase{
+				name: "normalized default values",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{
+						From:   from.Columns[3],
+						To:     to.Columns[3],
+						Change: schema.ChangeDefault,
+					},
+					&schema.ModifyColumn{
+						From:   from.Columns[4],
+						To:     to.Columns[4],
+						Change: schema.ChangeDefault,
+					},
+				},
+			}
+		}(),
 		func() testcase {
 			var (
 				from = &schema.Table{
@@ -122,7 +243,7 @@ func TestDiff_TableDiff(t *testing.T) {
 					&schema.ModifyColumn{
 						From:   from.Columns[0],
 						To:     to.Columns[0],
-						Change: schema.ChangeNull | schema.ChangeComment | schema.ChangeDefault,
+						Change: schema.ChangeNull | schema.ChangeDefault,
 					},
 					&schema.DropColumn{C: from.Columns[1]},
 					&schema.AddColumn{C: to.Columns[1]},
@@ -201,6 +322,7 @@ func TestDiff_TableDiff(t *testing.T) {
 				{Name: "c3_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}},
 				{Name: "c3_desc", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: to.Columns[1]}}},
 				{Name: "c4_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "(c4 <> NULL)"}}},
+				{Name: "c5_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "C5 IS NOT NULL AND c5 <> 'A'"}}},
 			}
 			to.Indexes = []*schema.Index{
 				{Name: "c1_index", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[0]}}},
@@ -208,6 +330,7 @@ func TestDiff_TableDiff(t *testing.T) {
 				{Name: "c3_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "c3 <> NULL"}}},
 				{Name: "c3_desc", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, Desc: true, C: to.Columns[1]}}},
 				{Name: "c4_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "c4 <> NULL"}}},
+				{Name: "c5_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "(c5 is not null\n  and c5 <> 'A')"}}},
 			}
 			return testcase{
 				name: "indexes",
@@ -216,12 +339,28 @@ func TestDiff_TableDiff(t *testing.T) {
 				wantChanges: []schema.Change{
 					&schema.ModifyIndex{From: from.Indexes[0], To: to.Indexes[0], Change: schema.ChangeUnique},
 					&schema.DropIndex{I: from.Indexes[1]},
-					&schema.ModifyIndex{From: from.Indexes[2], To: to.Indexes[2], Change: schema.ChangeAttr},
 					&schema.ModifyIndex{From: from.Indexes[3], To: to.Indexes[3], Change: schema.ChangeParts},
+					&schema.ModifyIndex{From: from.Indexes[2], To: to.Indexes[2], Change: schema.ChangeAttr},
 					&schema.AddIndex{I: to.Indexes[1]},
 				},
 			}
 		}(),
+		func() testcase {
+			from := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(schema.NewIntColumn("c1", "int"))
+			from.AddIndexes(schema.NewIndex("c1_index").AddColumns(from.Columns[0]))
+			to := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(schema.NewIntColumn("c1", "int").SetComment("c1"))
+			to.AddIndexes(schema.NewIndex("c1_index").AddColumns(to.Columns[0]).SetComment("c1_index"))
+			// SQLite does not support comments, and they are ignored.
+			return testcase{
+				name: "ignore comments",
+				from: from,
+				to:   to,
+			}
+		}(),
 		func() testcase {
 			from := schema.NewTable("t1").
 				SetSchema(schema.New("test")).
@@ -343,12 +482,84 @@ func TestDiff_SchemaDiff(t *testing.T) {
 	changes, err := drv.SchemaDiff(from, to)
 	require.NoError(t, err)
 	require.EqualValues(t, []schema.Change{
-		&schema.ModifyTable{T: to.Tables[0], Changes: []schema.Change{&schema.AddColumn{C: to.Tables[0].Columns[0]}}},
 		&schema.DropTable{T: from.Tables[1]},
+		&schema.ModifyTable{T: to.Tables[0], Changes: []schema.Change{&schema.AddColumn{C: to.Tables[0].Columns[0]}}},
 		&schema.AddTable{T: to.Tables[1]},
 	}, changes)
 }
 
+func TestDiff_ViewDef(t *testing.T) {
+	from := schema.New("main").AddViews(
+		schema.NewView("v1", "SELECT id, name FROM users WHERE name <> 'a  b'"),
+		schema.NewView("v2", "SELECT 1"),
+	)
+	to := schema.New("main").AddViews(
+		schema.NewView("v1", "select id,name\n  FROM users\n  WHERE name <> 'a  b';"),
+		schema.NewView("v2", "SELECT 2"),
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyView{From: from.Views[1], To: to.Views[1]},
+	}, changes)
+
+	// Quoted strings are compared as-is.
+	to.Views[0].Def = "SELECT id, name FROM users WHERE name <> 'a b'"
+	changes, err = DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+}
+
+func TestDiff_Triggers(t *testing.T) {
+	var (
+		from = schema.New("main").AddTables(schema.NewTable("t1").AddColumns(schema.NewIntColumn("c1", "int")))
+		to   = schema.New("main").AddTables(schema.NewTable("t1").AddColumns(schema.NewIntColumn("c1", "int")))
+		add  = func(tt *schema.Table, name, def string) {
+			tr, err := parseTrigger(tt, name, def)
+			require.NoError(t, err)
+			tt.AddTriggers(tr)
+		}
+	)
+	add(from.Tables[0], "t1_ad", "CREATE TRIGGER t1_ad AFTER DELETE ON t1 BEGIN SELECT 1; END")
+	add(from.Tables[0], "t1_ai", "CREATE TRIGGER t1_ai AFTER INSERT ON t1 BEGIN SELECT 1; END")
+	add(from.Tables[0], "t1_au", "CREATE TRIGGER t1_au AFTER UPDATE ON t1 BEGIN SELECT 1; END")
+	add(from.Tables[0], "t1_bu", "CREATE TRIGGER t1_bu UPDATE OF c1 ON t1 WHEN NEW.c1 > 0 BEGIN SELECT 1; END")
+	add(to.Tables[0], "t1_ai", "CREATE TRIGGER t1_ai\nAFTER INSERT ON `t1`\nFOR EACH ROW\nBEGIN\n  SELECT 1;\nEND;")
+	add(to.Tables[0], "t1_au", "CREATE TRIGGER t1_au AFTER UPDATE ON t1 BEGIN SELECT 2; END")
+	add(to.Tables[0], "t1_bi", "CREATE TRIGGER t1_bi BEFORE INSERT ON t1 BEGIN SELECT 1; END")
+	add(to.Tables[0], "t1_bu", "CREATE TRIGGER t1_bu BEFORE UPDATE OF \"c1\" ON t1 WHEN NEW.c1 > 1 BEGIN SELECT 1; END")
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.DropTrigger{T: from.Tables[0].Triggers[0]},
+		&schema.ModifyTrigger{From: from.Tables[0].Triggers[2], To: to.Tables[0].Triggers[1]},
+		&schema.ModifyTrigger{From: from.Tables[0].Triggers[3], To: to.Tables[0].Triggers[3]},
+		&schema.AddTrigger{T: to.Tables[0].Triggers[2]},
+	}, changes)
+}
+
+func TestDiff_VirtualTables(t *testing.T) {
+	var (
+		from = schema.New("main").AddObjects(
+			&VirtualTable{Name: "docs", Module: "fts5", Args: []string{"title", "body"}},
+			&VirtualTable{Name: "posts", Module: "fts5", Args: []string{"title"}},
+			&VirtualTable{Name: "old", Module: "fts5", Args: []string{"title"}},
+		)
+		to = schema.New("main").AddObjects(
+			&VirtualTable{Name: "docs", Module: "FTS5", Args: []string{"title", "body"}},
+			&VirtualTable{Name: "posts", Module: "fts5", Args: []string{"title", "body"}},
+			&VirtualTable{Name: "new", Module: "rtree", Args: []string{"id", "minX", "maxX"}},
+		)
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[1], To: to.Objects[1]},
+		&schema.DropObject{O: from.Objects[2]},
+		&schema.AddObject{O: to.Objects[2]},
+	}, changes)
+}
+
 func TestDefaultDiff(t *testing.T) {
 	changes, err := DefaultDiff.SchemaDiff(
 		schema.New("main").
@@ -7,6 +7,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"net/url"
@@ -20,6 +21,8 @@ import (
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
+
+	"golang.org/x/mod/semver"
 )
 
 type (
@@ -38,12 +41,17 @@ type (
 		// System variables that are set on `Open`.
 		version    string
 		collations []string
+		// libSQL extensions are supported.
+		libsql bool
 	}
 )
 
 // DriverName holds the name used for registration.
 const DriverName = "sqlite3"
 
+// LibSQLDriverName holds the name used for registering the libSQL (e.g. Turso) driver.
+const LibSQLDriverName = "libsql"
+
 func init() {
 	sqlclient.Register(
 		DriverName,
@@ -61,11 +69,11 @@ func init() {
 		})),
 	)
 	sqlclient.Register(
-		"libsql",
-		sqlclient.DriverOpener(Open),
+		LibSQLDriverName,
+		sqlclient.DriverOpener(OpenLibSQL),
 		sqlclient.RegisterTxOpener(OpenTx),
 		sqlclient.RegisterCodec(MarshalHCL, EvalHCL),
-		sqlclient.RegisterFlavours("libsql+wss"),
+		sqlclient.RegisterFlavours("libsql+wss", "libsql+ws", "libsql+https", "libsql+http"),
 		sqlclient.RegisterURLParser(sqlclient.URLParserFunc(func(u *url.URL) *sqlclient.URL {
 			return &sqlclient.URL{URL: u, DSN: strings.TrimPrefix(u.String(), "libsql+"), Schema: mainFile}
 		})),
@@ -99,22 +107,94 @@ func Open(db schema.ExecQuerier) (migrate.Driver, error) {
 	}, nil
 }
 
+// MemNormalizer is a schema.Normalizer that replays schemas in a private in-memory
+// database, and inspects them from there to get their normal representation. Hence,
+// users of SQLite do not need to manage a separate dev database for normalizing types
+// and expressions. Note, it is not used by default, as it requires a database/sql
+// driver that supports in-memory databases (unlike libSQL remote databases).
+//
+//	n := &sqlite.MemNormalizer{Driver: db.Driver()}
+//	pl := migrate.NewPlanner(drv, dir, migrate.PlanWithNormalizer(n))
+type MemNormalizer struct {
+	// Driver is the database/sql driver that is used
+	// for opening the in-memory database.
+	Driver driver.Driver
+}
+
+var _ schema.Normalizer = (*MemNormalizer)(nil)
+
+// NormalizeRealm returns the normal representation of the given database. See NormalizeSchema for more info.
+func (n *MemNormalizer) NormalizeRealm(ctx context.Context, r *schema.Realm) (*schema.Realm, error) {
+	nr := &schema.Realm{Attrs: r.Attrs, Objects: r.Objects}
+	// Each schema (attached database) is normalized on its own,
+	// as objects in SQLite cannot reference other databases.
+	for _, s := range r.Schemas {
+		ns, err := n.NormalizeSchema(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		nr.AddSchemas(ns)
+	}
+	sqlx.LinkSchemaTables(nr.Schemas)
+	return nr, nil
+}
+
+// NormalizeSchema returns the normal representation of the given schema.
+func (n *MemNormalizer) NormalizeSchema(ctx context.Context, s *schema.Schema) (*schema.Schema, error) {
+	mem := sql.OpenDB(memConnector{drv: n.Driver})
+	defer mem.Close()
+	// Each connection to an in-memory database
+	// opens a different (and empty) database.
+	mem.SetMaxOpenConns(1)
+	dev, err := Open(mem)
+	if err != nil {
+		return nil, err
+	}
+	return (&sqlx.DevDriver{Driver: dev.(*Driver)}).NormalizeSchema(ctx, s)
+}
+
+// memConnector implements the driver.Connector interface
+// for opening connections to in-memory databases.
+type memConnector struct{ drv driver.Driver }
+
+// Connect implements the driver.Connector interface.
+func (c memConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.drv.Open(memoryDSN)
+}
+
+// Driver implements the driver.Connector interface.
+func (c memConnector) Driver() driver.Driver {
+	return c.drv
+}
+
 // Snapshot implements migrate.Snapshoter.
 func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
 	r, err := d.InspectRealm(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
-	if !(r == nil || (len(r.Schemas) == 1 && r.Schemas[0].Name == mainFile && len(r.Schemas[0].Tables) == 0)) {
-		return nil, &migrate.NotCleanError{Reason: fmt.Sprintf("found table %q", r.Schemas[0].Tables[0].Name)}
+	var names []string
+	if r != nil {
+		for _, s := range r.Schemas {
+			if len(s.Tables) > 0 {
+				return nil, &migrate.NotCleanError{Reason: fmt.Sprintf("found table %q", s.Tables[0].Name)}
+			}
+			names = append(names, s.Name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, mainFile)
 	}
 	return func(ctx context.Context) error {
-		for _, stmt := range []string{
-			"PRAGMA writable_schema = 1;",
-			"DELETE FROM sqlite_master WHERE type IN ('table', 'view', 'index', 'trigger');",
-			"PRAGMA writable_schema = 0;",
-			"VACUUM;",
-		} {
+		stmts := []string{"PRAGMA writable_schema = 1;"}
+		for _, n := range names {
+			stmts = append(stmts, fmt.Sprintf("DELETE FROM `%s`.sqlite_master WHERE type IN ('table', 'view', 'index', 'trigger');", n))
+		}
+		stmts = append(stmts, "PRAGMA writable_schema = 0;")
+		for _, n := range names {
+			stmts = append(stmts, fmt.Sprintf("VACUUM `%s`;", n))
+		}
+		for _, stmt := range stmts {
 			if _, err := d.ExecContext(ctx, stmt); err != nil {
 				return err
 			}
@@ -163,6 +243,19 @@ func (d *Driver) Lock(_ context.Context, name string, timeout time.Duration) (sc
 	return acquireLock(path, timeout)
 }
 
+// Capabilities implements migrate.Capable. Changes that are not supported
+// by the ALTER TABLE statement are applied by recreating the table.
+func (d *Driver) Capabilities() migrate.Capabilities {
+	v := "v" + d.conn.version
+	return migrate.Capabilities{
+		TransactionalDDL: true,
+		Check:            true,
+		ForeignKey:       true,
+		DropColumn:       semver.Compare(v, "v3.35.0") >= 0,
+		RenameColumn:     semver.Compare(v, "v3.25.0") >= 0,
+	}
+}
+
 // Version returns the version of the connected database.
 func (d *Driver) Version() string {
 	return d.conn.version
@@ -185,6 +278,17 @@ type violation struct {
 	row, index int
 }
 
+// OpenLibSQL opens a new SQLite driver for libSQL databases (e.g. Turso). It is similar to Open,
+// but allows the driver to use the libSQL extensions to SQLite, such as ALTER COLUMN.
+func OpenLibSQL(db schema.ExecQuerier) (migrate.Driver, error) {
+	drv, err := Open(db)
+	if err != nil {
+		return nil, err
+	}
+	drv.(*Driver).conn.libsql = true
+	return drv, nil
+}
+
 // OpenTx opens a transaction. If foreign keys are enabled, it disables them, checks for constraint violations,
 // opens the transaction and before committing ensures no new violations have been introduced by whatever Atlas was
 // doing.
@@ -325,10 +429,25 @@ const (
 	TypeReal    = "real"    // SQLITE_TYPE_REAL
 	TypeText    = "text"    // SQLITE_TYPE_TEXT
 	TypeBlob    = "blob"    // SQLITE_TYPE_BLOB
+	TypeInt     = "int"     // COLTYPE_INT
+	TypeAny     = "any"     // COLTYPE_ANY
+)
+
+// strictTypes holds the column types that are allowed in STRICT tables.
+// https://www.sqlite.org/stricttables.html
+var strictTypes = []string{TypeInt, TypeInteger, TypeReal, TypeText, TypeBlob, TypeAny}
+
+// SQLite built-in collating sequences. Other collations
+// can be registered by applications using the SQLite API.
+// https://www.sqlite.org/datatype3.html#collating_sequences
+const (
+	CollateBinary = "BINARY"
+	CollateNoCase = "NOCASE"
+	CollateRTrim  = "RTRIM"
 )
 
 // SQLite generated columns types.
 const (
-	virtual = "VIRTUAL"
-	stored  = "STORED"
+	virtual = schema.GeneratedVirtual
+	stored  = schema.GeneratedStored
 )
@@ -6,31 +6,6 @@
 
 package sqlite
 
-import (
-	"context"
-
-	"ariga.io/atlas/schemahcl"
-	"ariga.io/atlas/sql/schema"
-)
+import "ariga.io/atlas/schemahcl"
 
 var specOptions []schemahcl.Option
-
-func (*inspect) inspectViews(context.Context, *schema.Realm, *schema.InspectOptions) error {
-	return nil // unimplemented.
-}
-
-func (*state) addView(*schema.AddView) error {
-	return nil // unimplemented.
-}
-
-func (*state) dropView(*schema.DropView) error {
-	return nil // unimplemented.
-}
-
-func (*state) modifyView(*schema.ModifyView) error {
-	return nil // unimplemented.
-}
-
-func (*state) renameView(*schema.RenameView) error {
-	return nil // unimplemented.
-}
@@ -8,12 +8,14 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 
+	"ariga.io/atlas/sql/internal/sqltest"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
@@ -114,6 +116,101 @@ func TestDriver_Version(t *testing.T) {
 	require.Equal(t, "3.36.0", drv.(vr).Version())
 }
 
+func TestDriver_Capabilities(t *testing.T) {
+	c := (&Driver{conn: &conn{version: "3.36.0"}}).Capabilities()
+	require.True(t, c.TransactionalDDL)
+	require.True(t, c.DropColumn)
+	require.True(t, c.RenameColumn)
+	require.False(t, c.ModifyColumn)
+	c = (&Driver{conn: &conn{version: "3.30.1"}}).Capabilities()
+	require.False(t, c.DropColumn)
+	require.True(t, c.RenameColumn)
+}
+
+func TestMemNormalizer_NormalizeSchema(t *testing.T) {
+	db, m, err := sqlmock.NewWithDSN("normalize")
+	require.NoError(t, err)
+	mk := mock{m}
+	// Opening the in-memory database.
+	mk.systemVars("3.36.0")
+	// Checking the database is clean, and taking its snapshot.
+	for i := 0; i < 2; i++ {
+		mk.emptyRealm()
+	}
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(databasesQueryArgs, "?"))).
+		WithArgs("main").
+		WillReturnRows(sqltest.Rows(`
+ name |   file    
+------+-----------
+ main |   
+`))
+	// Replaying the schema.
+	m.ExpectExec(sqltest.Escape("CREATE TABLE `t` (`id` int NOT NULL, `name` text NULL DEFAULT 'unknown')")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// Inspecting its normal form.
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(databasesQueryArgs, "?"))).
+		WithArgs("main").
+		WillReturnRows(sqltest.Rows(`
+ name |   file    
+------+-----------
+ main |   
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "sql", "wr", "strict", "type"}).
+			AddRow("t", "CREATE TABLE `t` (`id` int NOT NULL, `name` text NULL DEFAULT 'unknown')", nil, nil, "table"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "t", "main"))).
+		WillReturnRows(sqltest.Rows(`
+ name |   type       | nullable | dflt_value  | primary  | hidden
+------+--------------+----------+ ------------+----------+----------
+ id   | int          |  0       |             |  0       |  0
+ name | text         |  1       | 'unknown'   |  0       |  0
+`))
+	mk.noIndexes("t")
+	mk.noFKs("t")
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(triggersQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "tbl_name", "sql"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(viewsQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "sql"}))
+	// Restoring the snapshot.
+	for _, stmt := range []string{"PRAGMA writable_schema = 1;", "DELETE FROM `main`.sqlite_master WHERE type IN ('table', 'view', 'index', 'trigger');", "PRAGMA writable_schema = 0;", "VACUUM `main`;"} {
+		m.ExpectExec(sqltest.Escape(stmt)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	var (
+		md = &memDriver{Driver: db.Driver()}
+		n  = &MemNormalizer{Driver: md}
+		s  = schema.New("main").AddTables(
+			schema.NewTable("t").
+				AddColumns(
+					schema.NewIntColumn("id", "INT"),
+					schema.NewNullStringColumn("name", "TEXT").SetDefault(&schema.RawExpr{X: "'unknown'"}),
+				),
+		)
+	)
+	ns, err := n.NormalizeSchema(context.Background(), s)
+	require.NoError(t, err)
+	require.Equal(t, []string{":memory:"}, md.opened)
+	require.NoError(t, m.ExpectationsWereMet())
+	// Types and defaults are in their normal form.
+	tn := ns.Tables[0]
+	require.Equal(t, &schema.IntegerType{T: "int"}, tn.Columns[0].Type.Type)
+	require.Equal(t, &schema.StringType{T: "text"}, tn.Columns[1].Type.Type)
+	require.Equal(t, &schema.Literal{V: "'unknown'"}, tn.Columns[1].Default)
+	// The given schema was not modified.
+	require.Equal(t, &schema.IntegerType{T: "INT"}, s.Tables[0].Columns[0].Type.Type)
+	require.Equal(t, &schema.RawExpr{X: "'unknown'"}, s.Tables[0].Columns[1].Default)
+}
+
+type memDriver struct {
+	driver.Driver
+	opened []string
+}
+
+func (m *memDriver) Open(name string) (driver.Conn, error) {
+	m.opened = append(m.opened, name)
+	return m.Driver.Open("normalize")
+}
+
 type mockInspector struct {
 	schema.Inspector
 	realm *schema.Realm
@@ -0,0 +1,36 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlite
+
+import "ariga.io/atlas/sql/schema"
+
+// The functions below extend the schema builder API (e.g. schema.NewTable) with
+// columns of SQLite-specific types and attributes. For example:
+//
+//	users := schema.NewTable("users").
+//		AddColumns(
+//			sqlite.NewAutoIncrementColumn("id"),
+//			sqlite.NewUUIDColumn("uid"),
+//		)
+//	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+
+// NewAutoIncrementColumn creates a new INTEGER column with the AUTOINCREMENT
+// attribute. Note that SQLite requires such columns to be the primary key.
+func NewAutoIncrementColumn(name string) *schema.Column {
+	return schema.NewIntColumn(name, TypeInteger).
+		AddAttrs(&AutoIncrement{})
+}
+
+// NewUUIDColumn creates a new UUID column.
+func NewUUIDColumn(name string) *schema.Column {
+	return schema.NewColumn(name).
+		SetType(&UUIDType{T: "uuid"})
+}
+
+// NewNullUUIDColumn creates a new nullable UUID column.
+func NewNullUUIDColumn(name string) *schema.Column {
+	return NewUUIDColumn(name).
+		SetNull(true)
+}
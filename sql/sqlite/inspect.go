@@ -27,25 +27,28 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 	if err != nil {
 		return nil, err
 	}
-	if len(schemas) > 1 {
-		return nil, fmt.Errorf("sqlite: multiple database files are not supported by the driver. got: %d", len(schemas))
-	}
 	if opts == nil {
 		opts = &schema.InspectRealmOption{}
 	}
 	r := schema.NewRealm(schemas...)
 	if sqlx.ModeInspectRealm(opts).Is(schema.InspectTables) {
 		for _, s := range schemas {
-			tables, err := i.tables(ctx, nil)
+			tables, vts, err := i.tables(ctx, s.Name, nil)
 			if err != nil {
 				return nil, err
 			}
 			s.AddTables(tables...)
+			addVirtualTables(s, vts)
 			for _, t := range tables {
 				if err := i.inspectTable(ctx, t); err != nil {
 					return nil, err
 				}
 			}
+			if sqlx.ModeInspectRealm(opts).Is(schema.InspectTriggers) {
+				if err := i.triggers(ctx, s); err != nil {
+					return nil, err
+				}
+			}
 		}
 		sqlx.LinkSchemaTables(r.Schemas)
 	}
@@ -54,7 +57,7 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 			return nil, err
 		}
 	}
-	return sqlx.ExcludeRealm(r, opts.Exclude)
+	return sqlx.FilterRealm(r, opts)
 }
 
 // InspectSchema returns schema descriptions of the tables in the given schema.
@@ -79,16 +82,22 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 	}
 	r := schema.NewRealm(schemas...)
 	if sqlx.ModeInspectSchema(opts).Is(schema.InspectTables) {
-		tables, err := i.tables(ctx, opts)
+		tables, vts, err := i.tables(ctx, name, opts)
 		if err != nil {
 			return nil, err
 		}
 		r.Schemas[0].AddTables(tables...)
+		addVirtualTables(r.Schemas[0], vts)
 		for _, t := range tables {
 			if err := i.inspectTable(ctx, t); err != nil {
 				return nil, err
 			}
 		}
+		if sqlx.ModeInspectSchema(opts).Is(schema.InspectTriggers) {
+			if err := i.triggers(ctx, r.Schemas[0]); err != nil {
+				return nil, err
+			}
+		}
 		sqlx.LinkSchemaTables(schemas)
 	}
 	if sqlx.ModeInspectSchema(opts).Is(schema.InspectViews) {
@@ -96,7 +105,7 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 			return nil, err
 		}
 	}
-	return sqlx.ExcludeSchema(r.Schemas[0], opts.Exclude)
+	return sqlx.FilterSchema(r.Schemas[0], opts)
 }
 
 func (i *inspect) inspectTable(ctx context.Context, t *schema.Table) error {
@@ -114,7 +123,7 @@ func (i *inspect) inspectTable(ctx context.Context, t *schema.Table) error {
 
 // columns queries and appends the columns of the given table.
 func (i *inspect) columns(ctx context.Context, t *schema.Table) error {
-	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, t.Name))
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, t.Name, schemaName(t.Schema)))
 	if err != nil {
 		return fmt.Errorf("sqlite: querying %q columns: %w", t.Name, err)
 	}
@@ -152,6 +161,9 @@ func (i *inspect) addColumn(t *schema.Table, rows *sql.Rows) error {
 	if defaults.Valid {
 		c.Default = defaultExpr(defaults.String)
 	}
+	if err := setCollation(t, c); err != nil {
+		return err
+	}
 	// The hidden flag is set to 2 for VIRTUAL columns, and to
 	// 3 for STORED columns. See: sqlite/pragma.c#sqlite3Pragma.
 	if hidden.Int64 >= 2 {
@@ -179,7 +191,7 @@ func (i *inspect) addColumn(t *schema.Table, rows *sql.Rows) error {
 
 // indexes queries and appends the indexes of the given table.
 func (i *inspect) indexes(ctx context.Context, t *schema.Table) error {
-	rows, err := i.QueryContext(ctx, fmt.Sprintf(indexesQuery, t.Name))
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(indexesQuery, t.Name, schemaName(t.Schema)))
 	if err != nil {
 		return fmt.Errorf("sqlite: querying %q indexes: %w", t.Name, err)
 	}
@@ -218,12 +230,12 @@ func (i *inspect) addIndexes(t *schema.Table, rows *sql.Rows) error {
 			},
 		}
 		if partial {
-			i := strings.Index(stmt.String, "WHERE")
-			if i == -1 {
+			m := reIdxWhere.FindStringSubmatch(stmt.String)
+			if len(m) != 2 {
 				return fmt.Errorf("missing partial WHERE clause in: %s", stmt.String)
 			}
 			idx.Attrs = append(idx.Attrs, &IndexPredicate{
-				P: strings.TrimSpace(stmt.String[i+5:]),
+				P: strings.TrimSpace(m[1]),
 			})
 		}
 		t.Indexes = append(t.Indexes, idx)
@@ -235,12 +247,16 @@ var (
 	// A regexp to extract index parts.
 	reIdxParts = regexp.MustCompile("(?i)ON\\s+[\"`]*(?:\\w+)[\"`]*\\s*\\((.+?)\\)(\\s*WHERE\\s+.+)?$")
 	reIdxDesc  = regexp.MustCompile("(?i)\\s+DESC\\s*$")
+	// A regexp to extract the COLLATE clause of index parts.
+	reIdxCollate = regexp.MustCompile("(?i)\\s+COLLATE\\s+[\"`\\[]?\\w+[\"`\\]]?\\s*$")
+	// A regexp to extract the predicate of partial indexes.
+	reIdxWhere = regexp.MustCompile("(?is)\\)\\s*WHERE\\s+(.+)$")
 )
 
 func (i *inspect) indexInfo(ctx context.Context, t *schema.Table, idx *schema.Index) error {
 	var (
 		hasExpr   bool
-		rows, err = i.QueryContext(ctx, fmt.Sprintf(indexColumnsQuery, idx.Name))
+		rows, err = i.QueryContext(ctx, fmt.Sprintf(indexColumnsQuery, idx.Name, schemaName(t.Schema)))
 	)
 	if err != nil {
 		return fmt.Errorf("sqlite: querying %q indexes: %w", t.Name, err)
@@ -248,10 +264,10 @@ func (i *inspect) indexInfo(ctx context.Context, t *schema.Table, idx *schema.In
 	defer rows.Close()
 	for rows.Next() {
 		var (
-			desc sql.NullBool
-			name sql.NullString
+			desc       sql.NullBool
+			name, coll sql.NullString
 		)
-		if err := rows.Scan(&name, &desc); err != nil {
+		if err := rows.Scan(&name, &desc, &coll); err != nil {
 			return fmt.Errorf("sqlite: scanning index names: %w", err)
 		}
 		part := &schema.IndexPart{
@@ -261,11 +277,19 @@ func (i *inspect) indexInfo(ctx context.Context, t *schema.Table, idx *schema.In
 		switch c, ok := t.Column(name.String); {
 		case ok:
 			part.C = c
+			// The collation of the part is recorded only if it differs from
+			// the column collation, which is used by default by SQLite.
+			if coll.Valid && !strings.EqualFold(coll.String, collation(c.Attrs)) {
+				part.Attrs = append(part.Attrs, &schema.Collation{V: coll.String})
+			}
 		// NULL name indicates that the index-part is an expression and we
 		// should extract it from the `CREATE INDEX` statement (not supported atm).
 		case !sqlx.ValidString(name):
 			hasExpr = true
 			part.X = &schema.RawExpr{X: "<unsupported>"}
+			if coll.Valid && !strings.EqualFold(coll.String, CollateBinary) {
+				part.Attrs = append(part.Attrs, &schema.Collation{V: coll.String})
+			}
 		default:
 			return fmt.Errorf("sqlite: column %q was not found for index %q", name.String, idx.Name)
 		}
@@ -286,12 +310,13 @@ func (i *inspect) indexInfo(ctx context.Context, t *schema.Table, idx *schema.In
 			return nil
 		}
 		if p.X != nil {
-			// Remove any extra spaces and the "DESC" clause
-			// in case the key-part is descending.
+			// Remove any extra spaces, the "DESC" clause in case
+			// the key-part is descending, and the "COLLATE" clause.
 			kx := strings.TrimSpace(x[:j+1])
 			if p.Desc {
 				kx = reIdxDesc.ReplaceAllString(kx, "")
 			}
+			kx = reIdxCollate.ReplaceAllString(kx, "")
 			p.X.(*schema.RawExpr).X = kx
 		}
 		x = strings.TrimLeft(x[j+1:], ", ")
@@ -301,7 +326,7 @@ func (i *inspect) indexInfo(ctx context.Context, t *schema.Table, idx *schema.In
 
 // fks queries and appends the foreign-keys of the given table.
 func (i *inspect) fks(ctx context.Context, t *schema.Table) error {
-	rows, err := i.QueryContext(ctx, fmt.Sprintf(fksQuery, t.Name))
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(fksQuery, t.Name, schemaName(t.Schema)))
 	if err != nil {
 		return fmt.Errorf("sqlite: querying %q foreign-keys: %w", t.Name, err)
 	}
@@ -363,11 +388,13 @@ func (i *inspect) addFKs(t *schema.Table, rows *sql.Rows) error {
 	return nil
 }
 
-// tableNames returns a list of all tables exist in the schema.
-func (i *inspect) tables(ctx context.Context, opts *schema.InspectOptions) ([]*schema.Table, error) {
+// tables returns a list of all tables and virtual tables exist in the schema (database).
+// Shadow tables, which are used by virtual tables to store their content,
+// are managed by their virtual table modules, and therefore, are skipped.
+func (i *inspect) tables(ctx context.Context, name string, opts *schema.InspectOptions) ([]*schema.Table, []*VirtualTable, error) {
 	var (
 		args  []any
-		query = tablesQuery
+		query = fmt.Sprintf(tablesQuery, name)
 	)
 	if opts != nil && len(opts.Tables) > 0 {
 		query += " AND sqlite_master.name IN (" + strings.Repeat("?, ", len(opts.Tables)-1) + "?)"
@@ -377,19 +404,34 @@ func (i *inspect) tables(ctx context.Context, opts *schema.InspectOptions) ([]*s
 	}
 	rows, err := i.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("sqlite: querying schema tables: %w", err)
+		return nil, nil, fmt.Errorf("sqlite: querying schema tables: %w", err)
 	}
 	defer rows.Close()
-	var tables []*schema.Table
+	var (
+		tables []*schema.Table
+		vts    []*VirtualTable
+	)
 	for rows.Next() {
 		var (
 			name, stmt string
+			typ        sql.NullString
 			wr, strict sql.NullBool
 		)
-		if err := rows.Scan(&name, &stmt, &wr, &strict); err != nil {
-			return nil, fmt.Errorf("sqlite: scanning table: %w", err)
+		if err := rows.Scan(&name, &stmt, &wr, &strict, &typ); err != nil {
+			return nil, nil, fmt.Errorf("sqlite: scanning table: %w", err)
 		}
 		stmt = strings.TrimSpace(stmt)
+		switch typ.String {
+		case "shadow":
+			continue
+		case "virtual":
+			vt, err := newVirtualTable(name, stmt)
+			if err != nil {
+				return nil, nil, err
+			}
+			vts = append(vts, vt)
+			continue
+		}
 		t := &schema.Table{
 			Name: name,
 			Attrs: []schema.Attr{
@@ -404,7 +446,15 @@ func (i *inspect) tables(ctx context.Context, opts *schema.InspectOptions) ([]*s
 		}
 		tables = append(tables, t)
 	}
-	return tables, nil
+	return tables, vts, rows.Err()
+}
+
+// addVirtualTables adds the virtual tables to the schema objects.
+func addVirtualTables(s *schema.Schema, vts []*VirtualTable) {
+	for _, vt := range vts {
+		vt.Schema = s
+		s.AddObjects(vt)
+	}
 }
 
 // schemas returns the list of the schemas in the database.
@@ -443,6 +493,15 @@ func (i *inspect) databases(ctx context.Context, opts *schema.InspectRealmOption
 	return schemas, nil
 }
 
+// schemaName returns the name of the given schema (database),
+// or "main" if the schema is missing or its name is empty.
+func schemaName(s *schema.Schema) string {
+	if s == nil || s.Name == "" {
+		return mainFile
+	}
+	return s.Name
+}
+
 type (
 	// File describes a database file.
 	File struct {
@@ -502,6 +561,13 @@ type (
 	}
 )
 
+func init() {
+	schema.RegisterJSON(
+		&File{}, &CreateStmt{}, &AutoIncrement{}, &WithoutRowID{}, &Strict{}, &IndexPredicate{},
+		&IndexOrigin{}, &UUIDType{}, &TriggerWhen{}, &VirtualTable{},
+	)
+}
+
 func columnParts(t string) []string {
 	t = strings.TrimSpace(strings.ToLower(t))
 	parts := strings.FieldsFunc(t, func(r rune) bool {
@@ -518,10 +584,18 @@ func columnParts(t string) []string {
 }
 
 func defaultExpr(x string) schema.Expr {
+	x = strings.TrimSpace(x)
+	// Literals and keywords wrapped in redundant parentheses, like
+	// "DEFAULT (1)" or "DEFAULT (current_timestamp)", are unwrapped.
+	if u := unwrapParens(x); isLiteral(u) || isTimeKeyword(u) {
+		x = u
+	}
 	switch {
+	case isTimeKeyword(x):
+		return &schema.RawExpr{X: strings.ToUpper(x)}
 	// Literals definition.
 	// https://www.sqlite.org/syntax/literal-value.html
-	case sqlx.IsLiteralBool(x), sqlx.IsLiteralNumber(x), sqlx.IsQuoted(x, '"', '\''), isBlob(x):
+	case isLiteral(x):
 		return &schema.Literal{V: x}
 	default:
 		// We wrap the CURRENT_TIMESTAMP literals in raw-expressions
@@ -530,6 +604,35 @@ func defaultExpr(x string) schema.Expr {
 	}
 }
 
+// isLiteral reports if the given string is a literal value.
+// https://www.sqlite.org/syntax/literal-value.html
+func isLiteral(x string) bool {
+	return sqlx.IsLiteralBool(x) || sqlx.IsLiteralNumber(x) || sqlx.IsQuoted(x, '"', '\'') || isBlob(x)
+}
+
+// isTimeKeyword reports if the given string is one of the
+// CURRENT_TIME, CURRENT_DATE or CURRENT_TIMESTAMP keywords.
+func isTimeKeyword(x string) bool {
+	switch strings.ToUpper(x) {
+	case "CURRENT_TIME", "CURRENT_DATE", "CURRENT_TIMESTAMP":
+		return true
+	default:
+		return false
+	}
+}
+
+// unwrapParens strips the redundant parentheses that wrap the given expression.
+func unwrapParens(x string) string {
+	for n := len(x); n > 1 && x[0] == '(' && x[n-1] == ')'; n = len(x) {
+		inner := strings.TrimSpace(x[1 : n-1])
+		if sqlx.ExprLastIndex(inner) != len(inner)-1 {
+			break
+		}
+		x = inner
+	}
+	return x
+}
+
 // blob literals are hex strings preceded by 'x' (or 'X).
 func isBlob(s string) bool {
 	if (strings.HasPrefix(s, "x'") || strings.HasPrefix(s, "X'")) && strings.HasSuffix(s, "'") {
@@ -577,7 +680,9 @@ func setGenExpr(t *schema.Table, c *schema.Column, f int64) error {
 	if !sqlx.Has(t.Attrs, &s) {
 		return fmt.Errorf("missing CREATE statement for table: %q", t.Name)
 	}
-	re, err := regexp.Compile(fmt.Sprintf("(?:[(,]\\s*)[\"`]*(%s)[\"`]*[^,]*(?i:GENERATED\\s+ALWAYS)*\\s*(?i:AS){1}\\s*\\(", c.Name))
+	// The column name must be followed by a whitespace or a closing quote to avoid
+	// matching columns that share the same prefix. e.g., "a" and "ab".
+	re, err := regexp.Compile(fmt.Sprintf("(?:[(,]\\s*)[\"`\\[]?(%s)[\"`\\]]?(?:\\s+[^,]*?)?\\s+(?i:GENERATED\\s+ALWAYS\\s+)?(?i:AS)\\s*\\(", regexp.QuoteMeta(c.Name)))
 	if err != nil {
 		return err
 	}
@@ -597,6 +702,26 @@ func setGenExpr(t *schema.Table, c *schema.Column, f int64) error {
 	return nil
 }
 
+// setCollation extracts the collation of the column from the CREATE statement
+// and appends it to the column, as SQLite does not expose it in its pragmas.
+func setCollation(t *schema.Table, c *schema.Column) error {
+	var s CreateStmt
+	if !sqlx.Has(t.Attrs, &s) || !reCollate.MatchString(s.S) {
+		return nil
+	}
+	re, err := regexp.Compile(fmt.Sprintf("(?:[(,]\\s*)[\"`\\[]?(?:%s)[\"`\\]]?\\s+[^,]*?(?i:COLLATE)\\s+[\"`\\[]?(\\w+)", regexp.QuoteMeta(c.Name)))
+	if err != nil {
+		return err
+	}
+	if m := re.FindStringSubmatch(s.S); len(m) == 2 {
+		c.Attrs = append(c.Attrs, &schema.Collation{V: m[1]})
+	}
+	return nil
+}
+
+// reCollate reports if a statement contains a COLLATE clause.
+var reCollate = regexp.MustCompile(`(?i)\bCOLLATE\b`)
+
 // The following regexes extract named FKs and CHECK constraints defined in table-constraints or inlined
 // as column-constraints. Note, we assume the SQL statements are valid as they are returned by SQLite.
 var (
@@ -720,27 +845,30 @@ func scanExpr(expr string) string {
 const (
 	// Name of main database file.
 	mainFile = "main"
+	// DSN for opening private in-memory databases.
+	memoryDSN = ":memory:"
 	// Query to list attached database files.
 	databasesQuery     = "SELECT `name`, `file` FROM pragma_database_list() WHERE `name` <> 'temp'"
 	databasesQueryArgs = "SELECT `name`, `file` FROM pragma_database_list() WHERE `name` IN (%s)"
 	// Query to list database tables.
 	tablesQuery = `
 SELECT
-	sqlite_master.name, sqlite_master.sql, wr, strict
+	sqlite_master.name, sqlite_master.sql, wr, strict, pragma_table_list.type
 FROM
-	sqlite_master
+	"%[1]s".sqlite_master
 	JOIN pragma_table_list(sqlite_master.name)
 WHERE
-	sqlite_master.type = 'table'
-	AND sqlite_master.name NOT LIKE 'sqlite_%'
-	AND sqlite_master.name NOT LIKE 'libsql_%'
+	pragma_table_list.schema = '%[1]s'
+	AND sqlite_master.type = 'table'
+	AND sqlite_master.name NOT LIKE 'sqlite_%%'
+	AND sqlite_master.name NOT LIKE 'libsql_%%'
 `
 	// Query to list table information.
-	columnsQuery = "SELECT `name`, `type`, (not `notnull`) AS `nullable`, `dflt_value`, (`pk` <> 0) AS `pk`, `hidden` FROM pragma_table_xinfo('%s') ORDER BY `cid`"
+	columnsQuery = "SELECT `name`, `type`, (not `notnull`) AS `nullable`, `dflt_value`, (`pk` <> 0) AS `pk`, `hidden` FROM pragma_table_xinfo('%s', '%s') ORDER BY `cid`"
 	// Query to list table indexes.
-	indexesQuery = "SELECT `il`.`name`, `il`.`unique`, `il`.`origin`, `il`.`partial`, `m`.`sql` FROM pragma_index_list('%s') AS il JOIN sqlite_master AS m ON il.name = m.name"
+	indexesQuery = "SELECT `il`.`name`, `il`.`unique`, `il`.`origin`, `il`.`partial`, `m`.`sql` FROM pragma_index_list('%[1]s', '%[2]s') AS il JOIN `%[2]s`.sqlite_master AS m ON il.name = m.name"
 	// Query to list index columns.
-	indexColumnsQuery = "SELECT name, desc FROM pragma_index_xinfo('%s') WHERE key = 1 ORDER BY seqno"
+	indexColumnsQuery = "SELECT name, desc, coll FROM pragma_index_xinfo('%s', '%s') WHERE key = 1 ORDER BY seqno"
 	// Query to list table foreign-keys.
-	fksQuery = "SELECT `id`, `from`, `to`, `table`, `on_update`, `on_delete` FROM pragma_foreign_key_list('%s') ORDER BY id, seq"
+	fksQuery = "SELECT `id`, `from`, `to`, `table`, `on_update`, `on_delete` FROM pragma_foreign_key_list('%s', '%s') ORDER BY id, seq"
 )
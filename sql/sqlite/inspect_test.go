@@ -26,7 +26,7 @@ func TestDriver_InspectTable(t *testing.T) {
 			name: "table columns",
 			before: func(m mock) {
 				m.tableExists("users", true, "CREATE TABLE users(id INTEGER PRIMARY KEY AUTOINCREMENT, w INT GENERATED ALWAYS AS (a*10), x TEXT AS (typeof(c)) STORED, y TEXT AS (substr(b,a,a+2)))")
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -81,8 +81,8 @@ func TestDriver_InspectTable(t *testing.T) {
 		{
 			name: "table indexes",
 			before: func(m mock) {
-				m.tableExists("users", true, "CREATE TABLE users(id INTEGER PRIMARY KEY)")
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users"))).
+				m.tableExists("users", true, "CREATE TABLE users(c1 int, c2 integer NOT NULL COLLATE NOCASE, c3 json NOT NULL)")
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -90,41 +90,41 @@ func TestDriver_InspectTable(t *testing.T) {
  c2   | integer       |  0      |             |  0       |  0
  c3   | json          |  0      |             |  0       |  0
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name  |   unique     | origin | partial  |                      sql 
 -------+--------------+--------+----------+-------------------------------------------------------
- c1u   |  1           |  c     |  0       | CREATE UNIQUE INDEX c1u on users(c1, c2)
+ c1u   |  1           |  c     |  0       | CREATE UNIQUE INDEX c1u on users(c1 COLLATE RTRIM, c2)
  c1_c2 |  0           |  c     |  1       | CREATE INDEX c1_c2 on users(c1, c2*2) WHERE c1 <> NULL
- c1_x  |  0           |  c     |  0       | CREATE INDEX c1_x ON users (f(c1))
+ c1_x  |  0           |  c     |  0       | CREATE INDEX c1_x ON users (f(c1) COLLATE NOCASE)
  c3_x  |  0           |  c     |  0       | CREATE INDEX c3_x ON users (json_extract(c3, '$.x') desc, json_extract(c3, '$.y') desc)
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1u"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1u", "main"))).
 					WillReturnRows(sqltest.Rows(`
- name  |   desc |
--------+--------+
- c1   |  1      |
- c2   |  0      |
+ name  |   desc | coll
+-------+--------+--------
+ c1   |  1      | RTRIM
+ c2   |  0      | NOCASE
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1_c2"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1_c2", "main"))).
 					WillReturnRows(sqltest.Rows(`
- name  |   desc |     
--------+--------+     
- c1    |  0     |     
- nil   |  0     |     
+ name  |   desc | coll
+-------+--------+--------
+ c1    |  0     | BINARY
+ nil   |  0     | BINARY
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1_x"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1_x", "main"))).
 					WillReturnRows(sqltest.Rows(`
- name  |   desc |
--------+--------+
- nil   |  0     |
+ name  |   desc | coll
+-------+--------+--------
+ nil   |  0     | NOCASE
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c3_x"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c3_x", "main"))).
 					WillReturnRows(sqltest.Rows(`
- name  |   desc |
--------+--------+
- nil   |  1     |
- nil   |  1     |
+ name  |   desc | coll
+-------+--------+--------
+ nil   |  1     | BINARY
+ nil   |  1     | BINARY
 `))
 				m.noFKs("users")
 			},
@@ -132,7 +132,7 @@ func TestDriver_InspectTable(t *testing.T) {
 				require.NoError(err)
 				columns := []*schema.Column{
 					{Name: "c1", Type: &schema.ColumnType{Null: true, Type: &schema.IntegerType{T: "int"}, Raw: "int"}},
-					{Name: "c2", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer"}},
+					{Name: "c2", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "integer"}, Raw: "integer"}, Attrs: []schema.Attr{&schema.Collation{V: "NOCASE"}}},
 					{Name: "c3", Type: &schema.ColumnType{Type: &schema.JSONType{T: "json"}, Raw: "json"}},
 				}
 				indexes := []*schema.Index{
@@ -141,11 +141,11 @@ func TestDriver_InspectTable(t *testing.T) {
 						Unique: true,
 						Table:  t,
 						Parts: []*schema.IndexPart{
-							{SeqNo: 1, C: columns[0], Desc: true},
+							{SeqNo: 1, C: columns[0], Desc: true, Attrs: []schema.Attr{&schema.Collation{V: "RTRIM"}}},
 							{SeqNo: 2, C: columns[1]},
 						},
 						Attrs: []schema.Attr{
-							&CreateStmt{S: "CREATE UNIQUE INDEX c1u on users(c1, c2)"},
+							&CreateStmt{S: "CREATE UNIQUE INDEX c1u on users(c1 COLLATE RTRIM, c2)"},
 							&IndexOrigin{O: "c"},
 						},
 					},
@@ -166,10 +166,10 @@ func TestDriver_InspectTable(t *testing.T) {
 						Name:  "c1_x",
 						Table: t,
 						Parts: []*schema.IndexPart{
-							{SeqNo: 1, X: &schema.RawExpr{X: "f(c1)"}},
+							{SeqNo: 1, X: &schema.RawExpr{X: "f(c1)"}, Attrs: []schema.Attr{&schema.Collation{V: "NOCASE"}}},
 						},
 						Attrs: []schema.Attr{
-							&CreateStmt{S: "CREATE INDEX c1_x ON users (f(c1))"},
+							&CreateStmt{S: "CREATE INDEX c1_x ON users (f(c1) COLLATE NOCASE)"},
 							&IndexOrigin{O: "c"},
 						},
 					},
@@ -203,7 +203,7 @@ CREATE TABLE users(
 	CONSTRAINT "id_nonzero" CHECK (id <> 0)
 )
 `)
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -212,7 +212,7 @@ CREATE TABLE users(
  c3   | integer       |  0      |             |  0       |  0
 `))
 				m.noIndexes("users")
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  id |   from    | to | table  | on_update   | on_delete   
 ----+-----------+-------------+-------------+-----------
@@ -255,12 +255,12 @@ CREATE TABLE users(
 ------+-----------
  main |
 `))
-				rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict"})
-				rows.AddRow("users", "CREATE TABLE users(id INTEGER PRIMARY KEY) without rowid, strict", 1, 1)
-				m.ExpectQuery(sqltest.Escape(tablesQuery + " AND sqlite_master.name IN (?)")).
+				rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict", "type"})
+				rows.AddRow("users", "CREATE TABLE users(id INTEGER PRIMARY KEY) without rowid, strict", 1, 1, "table")
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main") + " AND sqlite_master.name IN (?)")).
 					WithArgs("users").
 					WillReturnRows(rows)
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -288,7 +288,7 @@ CREATE TABLE users(
 			tt.before(mk)
 			s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
 				Tables: []string{"users"},
-				Mode:   ^schema.InspectViews,
+				Mode:   ^(schema.InspectViews | schema.InspectTriggers),
 			})
 			require.NoError(t, err)
 			tt.expect(require.New(t), s.Tables[0], err)
@@ -296,6 +296,174 @@ CREATE TABLE users(
 	}
 }
 
+func TestDriver_InspectViews(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.systemVars("3.36.0")
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(databasesQueryArgs, "?"))).
+		WithArgs("main").
+		WillReturnRows(sqltest.Rows(`
+ name |   file
+------+-----------
+ main |
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(viewsQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "sql"}).
+			AddRow("active", "CREATE VIEW active AS SELECT id, name FROM users WHERE active;").
+			AddRow("v2", "CREATE VIEW IF NOT EXISTS `v2`(a) AS\nSELECT 1"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "active", "main"))).
+		WillReturnRows(sqltest.Rows(`
+ name |   type       | nullable | dflt_value  | primary  | hidden
+------+--------------+----------+ ------------+----------+----------
+ id   | integer      |  1       |             |  0       |  0
+ name | text         |  0       |             |  0       |  0
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "v2", "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "dflt_value", "primary", "hidden"}).
+			AddRow("a", "", true, nil, false, 0))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
+		Mode: schema.InspectViews,
+	})
+	require.NoError(t, err)
+	require.Len(t, s.Views, 2)
+	require.Equal(t, "active", s.Views[0].Name)
+	require.Equal(t, "SELECT id, name FROM users WHERE active", s.Views[0].Def)
+	require.Equal(t, []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "integer", Null: true, Type: &schema.IntegerType{T: "integer"}}},
+		{Name: "name", Type: &schema.ColumnType{Raw: "text", Type: &schema.StringType{T: "text"}}},
+	}, s.Views[0].Columns)
+	require.Equal(t, "v2", s.Views[1].Name)
+	require.Equal(t, "SELECT 1", s.Views[1].Def)
+}
+
+func TestDriver_InspectTriggers(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.systemVars("3.36.0")
+	mk.tableExists("users", true, "CREATE TABLE users(id int)")
+	mk.noColumns("users")
+	mk.noIndexes("users")
+	mk.noFKs("users")
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(triggersQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "tbl_name", "sql"}).
+			AddRow("pets_ai", "pets", "CREATE TRIGGER pets_ai AFTER INSERT ON pets BEGIN SELECT 1; END").
+			AddRow("users_ai", "users", "CREATE TRIGGER users_ai AFTER INSERT ON users BEGIN SELECT 1; END"))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
+		Tables: []string{"users"},
+		Mode:   schema.InspectTables | schema.InspectTriggers,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*schema.Trigger{
+		{
+			Name:       "users_ai",
+			Table:      s.Tables[0],
+			ActionTime: schema.TriggerTimeAfter,
+			Events:     []schema.TriggerEvent{schema.TriggerEventInsert},
+			For:        schema.TriggerForRow,
+			Body:       "BEGIN SELECT 1; END",
+		},
+	}, s.Tables[0].Triggers)
+}
+
+func TestDriver_InspectVirtualTables(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.systemVars("3.37.0")
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(databasesQueryArgs, "?"))).
+		WithArgs("main").
+		WillReturnRows(sqltest.Rows(`
+ name |   file
+------+-----------
+ main |
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "sql", "wr", "strict", "type"}).
+			AddRow("docs", "CREATE VIRTUAL TABLE docs USING fts5(title, body, tokenize = 'porter ascii')", 0, 0, "virtual").
+			AddRow("docs_data", "CREATE TABLE 'docs_data'(id INTEGER PRIMARY KEY, block BLOB)", 0, 0, "shadow").
+			AddRow("docs_config", "CREATE TABLE 'docs_config'(k PRIMARY KEY, v) WITHOUT ROWID", 1, 0, "shadow"))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
+		Mode: schema.InspectTables,
+	})
+	require.NoError(t, err)
+	require.Empty(t, s.Tables)
+	require.Equal(t, []schema.Object{
+		&VirtualTable{Name: "docs", Schema: s, Module: "fts5", Args: []string{"title", "body", "tokenize = 'porter ascii'"}},
+	}, s.Objects)
+}
+
+func TestDriver_InspectRealm_Attached(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.systemVars("3.37.0")
+	m.ExpectQuery(sqltest.Escape(databasesQuery)).
+		WillReturnRows(sqltest.Rows(`
+ name |   file
+------+-----------
+ main | main.db
+ aux  | aux.db
+`))
+	for _, n := range []string{"main", "aux"} {
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, n))).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "sql", "wr", "strict", "type"}).
+				AddRow("users", "CREATE TABLE users(id int)", 0, 0, "table"))
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", n))).
+			WillReturnRows(sqltest.Rows(`
+ name |   type       | nullable | dflt_value  | primary  | hidden
+------+--------------+----------+ ------------+----------+----------
+ id   | int          |  0       |             |  0       |  0
+`))
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, "users", n))).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "unique", "origin", "partial", "sql"}))
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, "users", n))).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "from", "to", "table", "on_update", "on_delete"}))
+	}
+	drv, err := Open(db)
+	require.NoError(t, err)
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode: schema.InspectSchemas | schema.InspectTables,
+	})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 2)
+	for i, n := range []string{"main", "aux"} {
+		s := r.Schemas[i]
+		require.Equal(t, n, s.Name)
+		require.Equal(t, []schema.Attr{&File{Name: n + ".db"}}, s.Attrs)
+		require.Len(t, s.Tables, 1)
+		require.Equal(t, "users", s.Tables[0].Name)
+		require.Equal(t, s, s.Tables[0].Schema)
+	}
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDefaultExpr(t *testing.T) {
+	for _, tt := range []struct {
+		input  string
+		expect schema.Expr
+	}{
+		{input: "1", expect: &schema.Literal{V: "1"}},
+		{input: "(1)", expect: &schema.Literal{V: "1"}},
+		{input: "((-1.5))", expect: &schema.Literal{V: "-1.5"}},
+		{input: "('a')", expect: &schema.Literal{V: "'a'"}},
+		{input: "x'a'", expect: &schema.Literal{V: "x'a'"}},
+		{input: "current_timestamp", expect: &schema.RawExpr{X: "CURRENT_TIMESTAMP"}},
+		{input: "(Current_Date)", expect: &schema.RawExpr{X: "CURRENT_DATE"}},
+		{input: "(1) + (2)", expect: &schema.RawExpr{X: "(1) + (2)"}},
+		{input: "(lower('A'))", expect: &schema.RawExpr{X: "(lower('A'))"}},
+	} {
+		require.Equal(t, tt.expect, defaultExpr(tt.input), tt.input)
+	}
+}
+
 func TestRegex_TableFK(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -391,6 +559,36 @@ CREATE TABLE t1 (
 	}
 }
 
+func TestRegex_IndexWhere(t *testing.T) {
+	tests := []struct {
+		input string
+		match string
+	}{
+		{
+			input: "CREATE INDEX i ON t(a) WHERE a > 0",
+			match: "a > 0",
+		},
+		{
+			input: "CREATE INDEX i ON t (lower(a), b) where\n\tb IS NOT NULL",
+			match: "b IS NOT NULL",
+		},
+		{
+			input: "CREATE INDEX i ON t(a, (b*2)) WHERE (a IN (1, 2))",
+			match: "(a IN (1, 2))",
+		},
+		{
+			input: "CREATE INDEX i ON t(where_c)",
+		},
+	}
+	for _, tt := range tests {
+		m := reIdxWhere.FindStringSubmatch(tt.input)
+		require.Equal(t, len(m) != 0, tt.match != "")
+		if len(m) > 0 {
+			require.Equal(t, tt.match, m[1])
+		}
+	}
+}
+
 func TestRegex_Checks(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -481,7 +679,7 @@ func TestRegex_Checks(t *testing.T) {
 		require.NoError(t, err)
 		s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
 			Tables: []string{name},
-			Mode:   ^schema.InspectViews,
+			Mode:   ^(schema.InspectViews | schema.InspectTriggers),
 		})
 		require.NoError(t, err)
 		table := s.Tables[0]
@@ -527,6 +725,21 @@ func TestRegex_GeneratedExpr(t *testing.T) {
 			column: schema.NewColumn("c0").
 				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(('a', 9) < ('b', c1))", Type: "VIRTUAL"}),
 		},
+		{
+			input: "CREATE TABLE t1(ab int AS (1), a int GENERATED ALWAYS AS (2) STORED);",
+			column: schema.NewColumn("a").
+				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(2)", Type: "VIRTUAL"}),
+		},
+		{
+			input: "CREATE TABLE t1(a int, [b.c] text AS (upper(a)), `d` AS(a+1));",
+			column: schema.NewColumn("b.c").
+				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(upper(a))", Type: "VIRTUAL"}),
+		},
+		{
+			input: "CREATE TABLE t1(a int, [b.c] text AS (upper(a)), `d` AS(a+1));",
+			column: schema.NewColumn("d").
+				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(a+1)", Type: "VIRTUAL"}),
+		},
 	}
 	for _, tt := range tests {
 		const name = "users"
@@ -535,7 +748,7 @@ func TestRegex_GeneratedExpr(t *testing.T) {
 		mk := mock{m}
 		mk.systemVars("3.36.0")
 		mk.tableExists(name, true, tt.input)
-		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, name))).
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, name, "main"))).
 			WillReturnRows(sqltest.Rows(fmt.Sprintf(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -547,7 +760,7 @@ func TestRegex_GeneratedExpr(t *testing.T) {
 		require.NoError(t, err)
 		s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
 			Tables: []string{name},
-			Mode:   ^schema.InspectViews,
+			Mode:   ^(schema.InspectViews | schema.InspectTriggers),
 		})
 		require.NoError(t, err)
 		require.Equal(t, tt.column.Attrs, s.Tables[0].Columns[0].Attrs)
@@ -585,26 +798,41 @@ func (m mock) tableExists(table string, exists bool, stmt ...string) {
 ------+-----------
  main |   
 `))
-	rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict"})
+	rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict", "type"})
 	if exists {
-		rows.AddRow(table, stmt[0], nil, nil)
+		rows.AddRow(table, stmt[0], nil, nil, "table")
 	}
-	m.ExpectQuery(sqltest.Escape(tablesQuery + " AND sqlite_master.name IN (?)")).
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main") + " AND sqlite_master.name IN (?)")).
 		WithArgs(table).
 		WillReturnRows(rows)
 }
 
+func (m mock) emptyRealm() {
+	m.ExpectQuery(sqltest.Escape(databasesQuery)).
+		WillReturnRows(sqltest.Rows(`
+ name |   file    
+------+-----------
+ main |   
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "sql", "wr", "strict", "type"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(triggersQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "tbl_name", "sql"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(viewsQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "sql"}))
+}
+
 func (m mock) noColumns(table string) {
-	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, table))).
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, table, "main"))).
 		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "dflt_value", "primary"}))
 }
 
 func (m mock) noIndexes(table string) {
-	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, table))).
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, table, "main"))).
 		WillReturnRows(sqlmock.NewRows([]string{"name", "unique", "origin", "partial", "sql"}))
 }
 
 func (m mock) noFKs(table string) {
-	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, table))).
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, table, "main"))).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "from", "to", "table", "on_update", "on_delete"}))
 }
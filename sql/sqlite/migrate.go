@@ -6,6 +6,7 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -30,28 +31,29 @@ func (p *planApply) PlanChanges(ctx context.Context, name string, changes []sche
 			Name:          name,
 			Transactional: true,
 		},
-		PlanOptions: migrate.PlanOptions{
-			// Currently, the driver does not support attached
-			// schemas and assumed the connected schema is "main".
-			SchemaQualifier: new(string),
-		},
 	}
 	for _, o := range opts {
 		o(&s.PlanOptions)
 	}
+	// Statements are qualified with the database (schema) name only if the plan
+	// involves attached databases. Otherwise, the "main" database is assumed.
+	if s.SchemaQualifier == nil && !attached(changes) {
+		s.SchemaQualifier = new(string)
+	}
 	if err := s.plan(ctx, changes); err != nil {
 		return nil, err
 	}
 	if err := sqlx.SetReversible(&s.Plan); err != nil {
 		return nil, err
 	}
+	sqlx.Annotate(&s.Plan, s.Annotations)
 	// Disable foreign-keys enforcement if it is required
 	// by one of the changes in the plan.
 	if s.skipFKs {
 		// Callers should note that these 2 pragmas are no-op in transactions,
 		// See: https://sqlite.org/pragma.html#pragma_foreign_keys.
-		s.Changes = append([]*migrate.Change{{Cmd: "PRAGMA foreign_keys = off", Comment: "disable the enforcement of foreign-keys constraints"}}, s.Changes...)
-		s.append(&migrate.Change{Cmd: "PRAGMA foreign_keys = on", Comment: "enable back the enforcement of foreign-keys constraints"})
+		s.Changes = append([]*migrate.Change{{Cmd: fksOff, Comment: "disable the enforcement of foreign-keys constraints"}}, s.Changes...)
+		s.append(&migrate.Change{Cmd: fksOn, Comment: "enable back the enforcement of foreign-keys constraints"})
 	}
 	return &s.Plan, nil
 }
@@ -59,8 +61,51 @@ func (p *planApply) PlanChanges(ctx context.Context, name string, changes []sche
 // ApplyChanges applies the changes on the database. An error is returned
 // if the driver is unable to produce a plan to it, or one of the statements
 // is failed or unsupported.
+//
+// In case the plan disables the enforcement of foreign keys (e.g. table rebuilds),
+// the foreign keys are checked before they are enabled back, and violations that
+// were introduced by the plan are returned as errors.
 func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
-	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+	return sqlx.ApplyChanges(ctx, changes, &fkChecker{planApply: p}, opts...)
+}
+
+// Statements for toggling the enforcement of foreign keys.
+const (
+	fksOff = "PRAGMA foreign_keys = off"
+	fksOn  = "PRAGMA foreign_keys = on"
+)
+
+// fkChecker wraps the planApply and checks the foreign keys
+// before their enforcement is enabled back by the plan.
+type fkChecker struct {
+	*planApply
+	before []violation
+}
+
+// ExecContext implements the schema.ExecQuerier interface.
+func (c *fkChecker) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	switch query {
+	case fksOff:
+		vs, err := violations(ctx, c.conn)
+		if err != nil {
+			return nil, err
+		}
+		c.before = vs
+	case fksOn:
+		after, err := violations(ctx, c.conn)
+		if err != nil {
+			return nil, err
+		}
+		if vs := violationsDiff(c.before, after); len(vs) > 0 {
+			err := fmt.Errorf("sql/sqlite: foreign key mismatch: %+v", vs)
+			// Enable the enforcement back, even if the plan failed.
+			if _, err2 := c.planApply.ExecContext(ctx, query, args...); err2 != nil {
+				err = fmt.Errorf("%v: %w", err2, err)
+			}
+			return nil, err
+		}
+	}
+	return c.planApply.ExecContext(ctx, query, args...)
 }
 
 // state represents the state of a planning. It's not part of
@@ -71,11 +116,50 @@ type state struct {
 	migrate.Plan
 	migrate.PlanOptions
 	skipFKs bool
+	// Tables that were rebuilt by the plan. Their triggers
+	// were dropped and recreated with them.
+	rebuilt map[string]bool
+	// Views that are created or dropped by the plan. A nil
+	// value in dropV indicates the view was already dropped.
+	addV  map[string]bool
+	dropV map[string]*schema.DropView
+}
+
+// customAttrs holds the custom attributes that were registered by RegisterAttr.
+var customAttrs = make(sqlx.AttrFormatters)
+
+// RegisterAttr registers a function for formatting custom attributes of the same type
+// as a (i.e. table and column options that are not modeled by Atlas) when they are planned
+// in table and column definitions. schema.RawClause attributes are formatted as is, and
+// do not require registration. For example:
+//
+//	type Compression struct {
+//		schema.Attr
+//		V string
+//	}
+//
+//	sqlite.RegisterAttr(&Compression{}, func(a schema.Attr) string {
+//		return "COMPRESSION " + a.(*Compression).V
+//	})
+//
+// Note, custom attributes are formatted only when tables and columns are created, and
+// changes to them are not detected by the differ. RegisterAttr is not safe for concurrent use, and should be called on initialization.
+func RegisterAttr(a schema.Attr, f func(schema.Attr) string) {
+	customAttrs.Register(a, f)
 }
 
 // Exec executes the changes on the database. An error is returned
 // if one of the operations fail, or a change is not supported.
 func (s *state) plan(ctx context.Context, changes []schema.Change) (err error) {
+	s.addV, s.dropV, s.rebuilt = make(map[string]bool), make(map[string]*schema.DropView), make(map[string]bool)
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddView:
+			s.addV[c.V.Name] = true
+		case *schema.DropView:
+			s.dropV[c.V.Name] = c
+		}
+	}
 	for _, c := range changes {
 		switch c := c.(type) {
 		case *schema.AddTable:
@@ -86,14 +170,35 @@ func (s *state) plan(ctx context.Context, changes []schema.Change) (err error) {
 			err = s.modifyTable(ctx, c)
 		case *schema.RenameTable:
 			s.renameTable(c)
+		case *schema.AddSchema:
+			err = s.attachSchema(c)
+		case *schema.DropSchema:
+			s.detachSchema(c)
 		case *schema.AddView:
 			err = s.addView(c)
 		case *schema.DropView:
-			err = s.dropView(c)
+			// Skip views that were dropped by a table rebuild.
+			if d, ok := s.dropV[c.V.Name]; !ok || d != nil {
+				err = s.dropView(c)
+			}
 		case *schema.ModifyView:
 			err = s.modifyView(c)
 		case *schema.RenameView:
 			err = s.renameView(c)
+		case *schema.AddObject, *schema.DropObject, *schema.ModifyObject:
+			err = s.planObject(c)
+		case *schema.AddTrigger:
+			if !s.rebuiltWith(c.T) {
+				err = s.addTrigger(c)
+			}
+		case *schema.DropTrigger:
+			if !s.rebuiltWith(c.T) {
+				err = s.dropTrigger(c)
+			}
+		case *schema.ModifyTrigger:
+			if !s.rebuiltWith(c.To) {
+				err = s.modifyTrigger(c)
+			}
 		default:
 			err = fmt.Errorf("unsupported change %T", c)
 		}
@@ -106,6 +211,14 @@ func (s *state) plan(ctx context.Context, changes []schema.Change) (err error) {
 
 // addTable builds and executes the query for creating a table in a schema.
 func (s *state) addTable(ctx context.Context, add *schema.AddTable) error {
+	if err := checkStrict(add.T, add.T.Columns...); err != nil {
+		return err
+	}
+	// AUTOINCREMENT is implemented using the rowid, and therefore,
+	// cannot be used in tables that were created WITHOUT ROWID.
+	if pk := add.T.PrimaryKey; pk != nil && autoincPK(pk) && sqlx.Has(add.T.Attrs, &WithoutRowID{}) {
+		return fmt.Errorf("sqlite: AUTOINCREMENT is not allowed on WITHOUT ROWID table %q", add.T.Name)
+	}
 	var (
 		errs []string
 		b    = s.Build("CREATE TABLE").Table(add.T)
@@ -145,6 +258,7 @@ func (s *state) addTable(ctx context.Context, add *schema.AddTable) error {
 	if sqlx.Has(add.T.Attrs, &Strict{}) {
 		options = append(options, "STRICT")
 	}
+	options = append(options, customAttrs.Custom(add.T.Attrs)...)
 	b.MapComma(options, func(i int, b *sqlx.Builder) {
 		b.P(options[i])
 	})
@@ -160,14 +274,42 @@ func (s *state) addTable(ctx context.Context, add *schema.AddTable) error {
 	return s.addIndexes(add.T, add.T.Indexes...)
 }
 
+// checkStrict checks that the given columns can be defined in the table, in case it is
+// a STRICT table. i.e., their types are one of: INT, INTEGER, REAL, TEXT, BLOB or ANY.
+func checkStrict(t *schema.Table, columns ...*schema.Column) error {
+	if !sqlx.Has(t.Attrs, &Strict{}) {
+		return nil
+	}
+	for _, c := range columns {
+		f, err := FormatType(c.Type.Type)
+		if err != nil {
+			return err
+		}
+		var valid bool
+		for _, st := range strictTypes {
+			if f == st {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("sqlite: invalid type %q for column %q in STRICT table %q (expect one of: %s)", f, c.Name, t.Name, strings.Join(strictTypes, ", "))
+		}
+	}
+	return nil
+}
+
 // dropTable builds and executes the query for dropping a table from a schema.
 func (s *state) dropTable(ctx context.Context, drop *schema.DropTable) error {
 	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
 	if err := rs.addTable(ctx, &schema.AddTable{T: drop.T}); err != nil {
 		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
 	}
+	if err := rs.addTriggers(drop.T); err != nil {
+		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
+	}
 	s.skipFKs = true
-	b := s.Build("DROP TABLE").Ident(drop.T.Name)
+	b := s.Build("DROP TABLE").Table(drop.T)
 	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
 		b.P("IF EXISTS")
 	}
@@ -196,10 +338,11 @@ func (s *state) dropTable(ctx context.Context, drop *schema.DropTable) error {
 // addition, the changes are applied using a temporary table following the procedure mentioned
 // in: https://www.sqlite.org/lang_altertable.html#making_other_kinds_of_table_schema_changes.
 func (s *state) modifyTable(ctx context.Context, modify *schema.ModifyTable) error {
-	if alterable(modify) {
+	if s.alterable(modify) {
 		return s.alterTable(modify)
 	}
 	s.skipFKs = true
+	recreateViews := s.dropViewsOf(modify.T)
 	newT := *modify.T
 	indexes := newT.Indexes
 	newT.Indexes = nil
@@ -214,7 +357,7 @@ func (s *state) modifyTable(ctx context.Context, modify *schema.ModifyTable) err
 	}
 	// Drop the current table, and rename the new one to its real name.
 	s.append(&migrate.Change{
-		Cmd:    s.Build("DROP TABLE").Ident(modify.T.Name).String(),
+		Cmd:    s.Build("DROP TABLE").Table(modify.T).String(),
 		Source: modify,
 		Comment: fmt.Sprintf("drop %q table %s", modify.T.Name, func() string {
 			if copied {
@@ -223,20 +366,67 @@ func (s *state) modifyTable(ctx context.Context, modify *schema.ModifyTable) err
 			return "without copying rows (no columns)"
 		}()),
 	})
+	// Renaming the new table fails if triggers of other tables reference the table,
+	// as it was dropped. In this case, the legacy behavior is enabled for the rename.
+	legacy := s.triggersReference(modify.T)
+	if legacy {
+		s.append(&migrate.Change{Cmd: "PRAGMA legacy_alter_table = on", Comment: "enable legacy alter table behavior for renaming the temporary table"})
+	}
 	s.append(&migrate.Change{
-		Cmd:     s.Build("ALTER TABLE").Ident(newT.Name).P("RENAME TO").Ident(modify.T.Name).String(),
+		Cmd:     s.Build("ALTER TABLE").Table(&newT).P("RENAME TO").Ident(modify.T.Name).String(),
 		Source:  modify,
 		Comment: fmt.Sprintf("rename temporary table %q to %q", newT.Name, modify.T.Name),
 	})
-	return s.addIndexes(modify.T, indexes...)
+	if legacy {
+		s.append(&migrate.Change{Cmd: "PRAGMA legacy_alter_table = off", Comment: "disable back the legacy alter table behavior"})
+	}
+	if err := s.addIndexes(modify.T, indexes...); err != nil {
+		return err
+	}
+	// Triggers are dropped with their table, and therefore,
+	// the desired ones are created after it was rebuilt.
+	if err := s.addTriggers(modify.T); err != nil {
+		return err
+	}
+	if s.rebuilt != nil {
+		s.rebuilt[modify.T.Name] = true
+	}
+	recreateViews()
+	return nil
+}
+
+// rebuiltWith reports if the table of the trigger was rebuilt
+// by the plan, and the trigger was already dropped or recreated.
+func (s *state) rebuiltWith(tr *schema.Trigger) bool {
+	return tr.Table != nil && s.rebuilt[tr.Table.Name]
+}
+
+// triggersReference reports if triggers of other tables in the schema reference the given table.
+func (s *state) triggersReference(t *schema.Table) bool {
+	if t.Schema == nil {
+		return false
+	}
+	for _, o := range t.Schema.Tables {
+		if o.Name == t.Name {
+			continue
+		}
+		for _, tr := range o.Triggers {
+			w := &TriggerWhen{}
+			sqlx.Has(tr.Attrs, w)
+			if references(tr.Body, t.Name) || references(w.X, t.Name) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (s *state) renameTable(c *schema.RenameTable) {
 	s.append(&migrate.Change{
 		Source:  c,
 		Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
-		Cmd:     s.Build("ALTER TABLE").Table(c.From).P("RENAME TO").Table(c.To).String(),
-		Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Table(c.From).String(),
+		Cmd:     s.Build("ALTER TABLE").Table(c.From).P("RENAME TO").Ident(c.To.Name).String(),
+		Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Ident(c.From.Name).String(),
 	})
 }
 
@@ -257,6 +447,9 @@ func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
 		}
 		b.P("DEFAULT", x)
 	}
+	if coll := (schema.Collation{}); sqlx.Has(c.Attrs, &coll) && coll.V != "" {
+		b.P("COLLATE", coll.V)
+	}
 	switch hasA, hasX := sqlx.Has(c.Attrs, &AutoIncrement{}), sqlx.Has(c.Attrs, &schema.GeneratedExpr{}); {
 	case hasA && hasX:
 		return fmt.Errorf("both autoincrement and generation expression specified for column %q", c.Name)
@@ -267,11 +460,12 @@ func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
 		sqlx.Has(c.Attrs, x)
 		b.P("AS", sqlx.MayWrap(x.Expr), x.Type)
 	}
+	b.P(customAttrs.Custom(c.Attrs)...)
 	return nil
 }
 
 func (s *state) dropIndexes(t *schema.Table, indexes ...*schema.Index) error {
-	rs := &state{conn: s.conn}
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
 	if err := rs.addIndexes(t, indexes...); err != nil {
 		return err
 	}
@@ -295,8 +489,10 @@ func (s *state) addIndexes(t *schema.Table, indexes ...*schema.Index) error {
 			b.P("UNIQUE")
 		}
 		b.P("INDEX")
+		// The index name is qualified with the schema name,
+		// but the table must be in the same schema (database).
 		if idx.Name != "" {
-			b.Ident(idx.Name)
+			b.SchemaResource(t.Schema, idx.Name)
 		}
 		b.P("ON").Ident(t.Name)
 		s.indexParts(b, idx.Parts)
@@ -306,7 +502,7 @@ func (s *state) addIndexes(t *schema.Table, indexes ...*schema.Index) error {
 		s.append(&migrate.Change{
 			Cmd:     b.String(),
 			Source:  &schema.AddIndex{I: idx},
-			Reverse: s.Build("DROP INDEX").Ident(idx.Name).String(),
+			Reverse: s.Build("DROP INDEX").SchemaResource(t.Schema, idx.Name).String(),
 			Comment: fmt.Sprintf("create index %q to table: %q", idx.Name, t.Name),
 		})
 	}
@@ -347,6 +543,9 @@ func (s *state) indexParts(b *sqlx.Builder, parts []*schema.IndexPart) {
 			case part.X != nil:
 				b.WriteString(sqlx.MayWrap(part.X.(*schema.RawExpr).X))
 			}
+			if c := (schema.Collation{}); sqlx.Has(parts[i].Attrs, &c) && c.V != "" {
+				b.P("COLLATE", c.V)
+			}
 			if parts[i].Desc {
 				b.P("DESC")
 			}
@@ -441,8 +640,8 @@ func (s *state) copyRows(from *schema.Table, to *schema.Table, changes []schema.
 	if insert {
 		s.append(&migrate.Change{
 			Cmd: fmt.Sprintf(
-				"INSERT INTO `%s` (%s) SELECT %s FROM `%s`",
-				to.Name, identComma(toC), identComma(fromC), from.Name,
+				"INSERT INTO %s (%s) SELECT %s FROM %s",
+				s.Build().Table(to), identComma(toC), identComma(fromC), s.Build().Table(from),
 			),
 			Comment: fmt.Sprintf("copy rows from old table %q to new temporary table %q", from.Name, to.Name),
 		})
@@ -470,7 +669,10 @@ func (s *state) alterTable(modify *schema.ModifyTable) error {
 				return err
 			}
 		case *schema.AddColumn:
-			b := s.Build("ALTER TABLE").Ident(modify.T.Name)
+			if err := checkStrict(modify.T, change.C); err != nil {
+				return err
+			}
+			b := s.Build("ALTER TABLE").Table(modify.T)
 			r := b.Clone()
 			if err := s.column(b.P("ADD COLUMN"), change.C); err != nil {
 				return err
@@ -481,8 +683,26 @@ func (s *state) alterTable(modify *schema.ModifyTable) error {
 				Reverse: r.P("DROP COLUMN").Ident(change.C.Name).String(),
 				Comment: fmt.Sprintf("add column %q to table: %q", change.C.Name, modify.T.Name),
 			})
+		case *schema.ModifyColumn:
+			if err := checkStrict(modify.T, change.To); err != nil {
+				return err
+			}
+			b := s.Build("ALTER TABLE").Table(modify.T).P("ALTER COLUMN").Ident(change.From.Name).P("TO")
+			r := b.Clone()
+			if err := s.column(b, change.To); err != nil {
+				return err
+			}
+			if err := s.column(r, change.From); err != nil {
+				return err
+			}
+			s.append(&migrate.Change{
+				Source:  change,
+				Cmd:     b.String(),
+				Reverse: r.String(),
+				Comment: fmt.Sprintf("modify column %q of table: %q", change.To.Name, modify.T.Name),
+			})
 		case *schema.RenameColumn:
-			b := s.Build("ALTER TABLE").Ident(modify.T.Name).P("RENAME COLUMN")
+			b := s.Build("ALTER TABLE").Table(modify.T).P("RENAME COLUMN")
 			r := b.Clone()
 			s.append(&migrate.Change{
 				Source:  change,
@@ -513,12 +733,16 @@ func (s *state) tableSeq(ctx context.Context, add *schema.AddTable) error {
 	// whenever the first "PRIMARY KEY AUTOINCREMENT" is created. However, rows in this table are populated after the
 	// first insertion to the associated table (name, seq). Therefore, we check if the sequence table and the row exist,
 	// and in case they are not, we insert a new non-zero sequence to it.
-	rows, err := s.QueryContext(ctx, "SELECT seq FROM sqlite_sequence WHERE name = ?", add.T.Name)
+	seq := "sqlite_sequence"
+	if q := s.qualifier(add.T.Schema); q != "" {
+		seq = fmt.Sprintf("`%s`.%s", q, seq)
+	}
+	rows, err := s.QueryContext(ctx, fmt.Sprintf("SELECT seq FROM %s WHERE name = ?", seq), add.T.Name)
 	if err != nil || !rows.Next() {
 		s.append(&migrate.Change{
-			Cmd:     fmt.Sprintf("INSERT INTO sqlite_sequence (name, seq) VALUES (%q, %d)", add.T.Name, inc.Seq),
+			Cmd:     fmt.Sprintf("INSERT INTO %s (name, seq) VALUES (%q, %d)", seq, add.T.Name, inc.Seq),
 			Source:  add,
-			Reverse: fmt.Sprintf("UPDATE sqlite_sequence SET seq = 0 WHERE name = %q", add.T.Name),
+			Reverse: fmt.Sprintf("UPDATE %s SET seq = 0 WHERE name = %q", seq, add.T.Name),
 			Comment: fmt.Sprintf("set sequence for %q table", add.T.Name),
 		})
 	}
@@ -528,11 +752,106 @@ func (s *state) tableSeq(ctx context.Context, add *schema.AddTable) error {
 	return nil
 }
 
+// attachSchema attaches the database file of the schema to the connection.
+func (s *state) attachSchema(add *schema.AddSchema) error {
+	var f File
+	if !sqlx.Has(add.S.Attrs, &f) || f.Name == "" {
+		return fmt.Errorf("sqlite: missing database file for attaching schema %q", add.S.Name)
+	}
+	// ATTACH and DETACH cannot be executed in transactions.
+	s.Transactional = false
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     s.Build("ATTACH DATABASE").P(fileLiteral(f.Name), "AS").Ident(add.S.Name).String(),
+		Reverse: s.Build("DETACH DATABASE").Ident(add.S.Name).String(),
+		Comment: fmt.Sprintf("attach database %q as schema %q", f.Name, add.S.Name),
+	})
+	return nil
+}
+
+// detachSchema detaches the database file of the schema from the connection.
+// Note, the database file itself and its content are kept as-is.
+func (s *state) detachSchema(drop *schema.DropSchema) {
+	c := &migrate.Change{
+		Source:  drop,
+		Cmd:     s.Build("DETACH DATABASE").Ident(drop.S.Name).String(),
+		Comment: fmt.Sprintf("detach schema %q", drop.S.Name),
+	}
+	if f := (File{}); sqlx.Has(drop.S.Attrs, &f) && f.Name != "" {
+		c.Reverse = s.Build("ATTACH DATABASE").P(fileLiteral(f.Name), "AS").Ident(drop.S.Name).String()
+	}
+	s.Transactional = false
+	s.append(c)
+}
+
+// fileLiteral returns the database file name as an SQL string literal.
+func fileLiteral(name string) string {
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
+
+// qualifier returns the schema qualifier that is used
+// for the given schema in the plan, or empty if none.
+func (s *state) qualifier(sc *schema.Schema) string {
+	switch {
+	case s.SchemaQualifier != nil:
+		return *s.SchemaQualifier
+	case sc != nil:
+		return sc.Name
+	default:
+		return ""
+	}
+}
+
+// attached reports if the changes involve databases other than "main".
+func attached(changes []schema.Change) bool {
+	var ss []*schema.Schema
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddSchema, *schema.DropSchema:
+			return true
+		case *schema.AddTable:
+			ss = append(ss, c.T.Schema)
+		case *schema.DropTable:
+			ss = append(ss, c.T.Schema)
+		case *schema.ModifyTable:
+			ss = append(ss, c.T.Schema)
+		case *schema.RenameTable:
+			ss = append(ss, c.From.Schema)
+		case *schema.AddView:
+			ss = append(ss, c.V.Schema)
+		case *schema.DropView:
+			ss = append(ss, c.V.Schema)
+		case *schema.ModifyView:
+			ss = append(ss, c.To.Schema)
+		case *schema.RenameView:
+			ss = append(ss, c.From.Schema)
+		case *schema.AddObject:
+			if vt, ok := c.O.(*VirtualTable); ok {
+				ss = append(ss, vt.Schema)
+			}
+		case *schema.DropObject:
+			if vt, ok := c.O.(*VirtualTable); ok {
+				ss = append(ss, vt.Schema)
+			}
+		case *schema.ModifyObject:
+			if vt, ok := c.To.(*VirtualTable); ok {
+				ss = append(ss, vt.Schema)
+			}
+		}
+	}
+	for _, sc := range ss {
+		if sc != nil && sc.Name != "" && sc.Name != mainFile {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *state) append(c *migrate.Change) {
 	s.Changes = append(s.Changes, c)
 }
 
-func alterable(modify *schema.ModifyTable) bool {
+func (s *state) alterable(modify *schema.ModifyTable) bool {
 	for _, change := range modify.Changes {
 		switch change := change.(type) {
 		case *schema.RenameColumn, *schema.RenameIndex, *schema.DropIndex, *schema.AddIndex:
@@ -544,6 +863,19 @@ func alterable(modify *schema.ModifyTable) bool {
 			if x := (schema.GeneratedExpr{}); sqlx.Has(change.C.Attrs, &x) && storedOrVirtual(x.Type) == stored {
 				return false
 			}
+		// libSQL allows modifying column definitions using ALTER COLUMN, except
+		// for generated columns and columns that are part of the primary key.
+		case *schema.ModifyColumn:
+			if !s.libsql || change.Change.Is(schema.ChangeGenerated) || sqlx.Has(change.To.Attrs, &schema.GeneratedExpr{}) {
+				return false
+			}
+			if pk := modify.T.PrimaryKey; pk != nil {
+				for _, p := range pk.Parts {
+					if p.C != nil && p.C.Name == change.To.Name {
+						return false
+					}
+				}
+			}
 		default:
 			return false
 		}
@@ -191,6 +191,195 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Add, drop and modify views.
+		{
+			changes: []schema.Change{
+				&schema.AddView{V: schema.NewView("v1", "SELECT id FROM users")},
+				&schema.DropView{V: schema.NewView("v2", "SELECT 1")},
+				&schema.ModifyView{From: schema.NewView("v3", "SELECT 1"), To: schema.NewView("v3", "SELECT 2")},
+			},
+			plan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE VIEW `v1` AS SELECT id FROM users", Reverse: "DROP VIEW `v1`"},
+					{Cmd: "DROP VIEW `v2`", Reverse: "CREATE VIEW `v2` AS SELECT 1"},
+					{Cmd: "DROP VIEW `v3`", Reverse: "CREATE VIEW `v3` AS SELECT 1"},
+					{Cmd: "CREATE VIEW `v3` AS SELECT 2", Reverse: "DROP VIEW `v3`"},
+				},
+			},
+		},
+		// Views that depend on a rebuilt table are re-created.
+		{
+			changes: func() []schema.Change {
+				users := schema.NewTable("users").
+					AddColumns(
+						schema.NewIntColumn("id", "bigint"),
+						schema.NewIntColumn("nid", "bigint").
+							SetGeneratedExpr(&schema.GeneratedExpr{Expr: "1", Type: "STORED"}),
+					)
+				schema.New("main").
+					AddTables(users).
+					AddViews(
+						schema.NewView("active", "SELECT id FROM `users` WHERE id > 0"),
+						schema.NewView("active_ids", "SELECT id FROM active"),
+						schema.NewView("pets", "SELECT 1"),
+						schema.NewView("created", "SELECT * FROM users"),
+					)
+				return []schema.Change{
+					&schema.ModifyTable{
+						T:       users,
+						Changes: []schema.Change{&schema.AddColumn{C: users.Columns[1]}},
+					},
+					&schema.AddView{V: users.Schema.Views[3]},
+					&schema.DropView{V: schema.NewView("old", "SELECT id FROM users")},
+				}
+			}(),
+			plan: &migrate.Plan{
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "PRAGMA foreign_keys = off"},
+					{Cmd: "DROP VIEW `old`", Reverse: "CREATE VIEW `old` AS SELECT id FROM users"},
+					{Cmd: "DROP VIEW `active`", Reverse: "CREATE VIEW `active` AS SELECT id FROM `users` WHERE id > 0"},
+					{Cmd: "DROP VIEW `active_ids`", Reverse: "CREATE VIEW `active_ids` AS SELECT id FROM active"},
+					{Cmd: "CREATE TABLE `new_users` (`id` bigint NOT NULL, `nid` bigint NOT NULL AS (1) STORED)", Reverse: "DROP TABLE `new_users`"},
+					{Cmd: "INSERT INTO `new_users` (`id`) SELECT `id` FROM `users`"},
+					{Cmd: "DROP TABLE `users`"},
+					{Cmd: "ALTER TABLE `new_users` RENAME TO `users`"},
+					{Cmd: "CREATE VIEW `active` AS SELECT id FROM `users` WHERE id > 0", Reverse: "DROP VIEW `active`"},
+					{Cmd: "CREATE VIEW `active_ids` AS SELECT id FROM active", Reverse: "DROP VIEW `active_ids`"},
+					{Cmd: "CREATE VIEW `created` AS SELECT * FROM users", Reverse: "DROP VIEW `created`"},
+					{Cmd: "PRAGMA foreign_keys = on"},
+				},
+			},
+		},
+		// Add, drop and modify triggers.
+		{
+			changes: func() []schema.Change {
+				users := schema.NewTable("users").
+					AddColumns(schema.NewIntColumn("id", "int")).
+					AddTriggers(&schema.Trigger{Name: "users_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "BEGIN SELECT 1; END"})
+				pets := schema.NewTable("pets").
+					AddColumns(schema.NewIntColumn("id", "int")).
+					AddTriggers(
+						&schema.Trigger{Name: "pets_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "BEGIN SELECT 1; END"},
+						&schema.Trigger{Name: "pets_ad", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventDelete}, Body: "BEGIN SELECT 1; END"},
+						&schema.Trigger{Name: "pets_au", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventUpdate}, Body: "BEGIN SELECT 1; END"},
+						&schema.Trigger{Name: "pets_au", Events: []schema.TriggerEvent{schema.TriggerEventUpdateOf(schema.NewIntColumn("id", "int"))}, Body: "BEGIN SELECT 2; END", Attrs: []schema.Attr{&TriggerWhen{X: "NEW.id > 0"}}},
+					)
+				return []schema.Change{
+					&schema.AddTable{T: users},
+					&schema.AddTrigger{T: users.Triggers[0]},
+					&schema.AddTrigger{T: pets.Triggers[0]},
+					&schema.DropTrigger{T: pets.Triggers[1]},
+					&schema.ModifyTrigger{From: pets.Triggers[2], To: pets.Triggers[3]},
+				}
+			}(),
+			plan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE TABLE `users` (`id` int NOT NULL)", Reverse: "DROP TABLE `users`"},
+					{Cmd: "CREATE TRIGGER `users_ai` AFTER INSERT ON `users` FOR EACH ROW BEGIN SELECT 1; END", Reverse: "DROP TRIGGER `users_ai`"},
+					{Cmd: "CREATE TRIGGER `pets_ai` AFTER INSERT ON `pets` FOR EACH ROW BEGIN SELECT 1; END", Reverse: "DROP TRIGGER `pets_ai`"},
+					{Cmd: "DROP TRIGGER `pets_ad`", Reverse: "CREATE TRIGGER `pets_ad` AFTER DELETE ON `pets` FOR EACH ROW BEGIN SELECT 1; END"},
+					{Cmd: "DROP TRIGGER `pets_au`", Reverse: "CREATE TRIGGER `pets_au` AFTER UPDATE ON `pets` FOR EACH ROW BEGIN SELECT 1; END"},
+					{Cmd: "CREATE TRIGGER `pets_au` BEFORE UPDATE OF `id` ON `pets` FOR EACH ROW WHEN NEW.id > 0 BEGIN SELECT 2; END", Reverse: "DROP TRIGGER `pets_au`"},
+				},
+			},
+		},
+		// Triggers are re-created after the table is rebuilt.
+		{
+			changes: func() []schema.Change {
+				users := schema.NewTable("users").
+					AddColumns(
+						schema.NewIntColumn("id", "bigint"),
+						schema.NewIntColumn("nid", "bigint").
+							SetGeneratedExpr(&schema.GeneratedExpr{Expr: "1", Type: "STORED"}),
+					).
+					AddTriggers(
+						&schema.Trigger{Name: "users_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "BEGIN SELECT 1; END"},
+						&schema.Trigger{Name: "users_au", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventUpdate}, Body: "BEGIN SELECT 1; END"},
+					)
+				return []schema.Change{
+					&schema.ModifyTable{
+						T:       users,
+						Changes: []schema.Change{&schema.AddColumn{C: users.Columns[1]}},
+					},
+					// Triggers of rebuilt tables are created with them.
+					&schema.AddTrigger{T: users.Triggers[1]},
+				}
+			}(),
+			plan: &migrate.Plan{
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "PRAGMA foreign_keys = off"},
+					{Cmd: "CREATE TABLE `new_users` (`id` bigint NOT NULL, `nid` bigint NOT NULL AS (1) STORED)", Reverse: "DROP TABLE `new_users`"},
+					{Cmd: "INSERT INTO `new_users` (`id`) SELECT `id` FROM `users`"},
+					{Cmd: "DROP TABLE `users`"},
+					{Cmd: "ALTER TABLE `new_users` RENAME TO `users`"},
+					{Cmd: "CREATE TRIGGER `users_ai` AFTER INSERT ON `users` FOR EACH ROW BEGIN SELECT 1; END", Reverse: "DROP TRIGGER `users_ai`"},
+					{Cmd: "CREATE TRIGGER `users_au` AFTER UPDATE ON `users` FOR EACH ROW BEGIN SELECT 1; END", Reverse: "DROP TRIGGER `users_au`"},
+					{Cmd: "PRAGMA foreign_keys = on"},
+				},
+			},
+		},
+		// Triggers of other tables that reference the rebuilt table.
+		{
+			changes: func() []schema.Change {
+				sc := schema.New("main")
+				users := schema.NewTable("users").
+					AddColumns(
+						schema.NewIntColumn("id", "bigint"),
+						schema.NewIntColumn("nid", "bigint").
+							SetGeneratedExpr(&schema.GeneratedExpr{Expr: "1", Type: "STORED"}),
+					)
+				pets := schema.NewTable("pets").
+					AddColumns(schema.NewIntColumn("owner_id", "bigint")).
+					AddTriggers(&schema.Trigger{Name: "pets_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "BEGIN INSERT INTO users (id) VALUES (NEW.owner_id); END"})
+				sc.AddTables(users, pets)
+				return []schema.Change{
+					&schema.ModifyTable{
+						T:       users,
+						Changes: []schema.Change{&schema.AddColumn{C: users.Columns[1]}},
+					},
+				}
+			}(),
+			plan: &migrate.Plan{
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "PRAGMA foreign_keys = off"},
+					{Cmd: "CREATE TABLE `new_users` (`id` bigint NOT NULL, `nid` bigint NOT NULL AS (1) STORED)", Reverse: "DROP TABLE `new_users`"},
+					{Cmd: "INSERT INTO `new_users` (`id`) SELECT `id` FROM `users`"},
+					{Cmd: "DROP TABLE `users`"},
+					{Cmd: "PRAGMA legacy_alter_table = on"},
+					{Cmd: "ALTER TABLE `new_users` RENAME TO `users`"},
+					{Cmd: "PRAGMA legacy_alter_table = off"},
+					{Cmd: "PRAGMA foreign_keys = on"},
+				},
+			},
+		},
+		// Add, drop and modify virtual tables.
+		{
+			changes: []schema.Change{
+				&schema.AddObject{O: &VirtualTable{Name: "docs", Module: "fts5", Args: []string{"title", "body", "tokenize='porter'"}}},
+				&schema.DropObject{O: &VirtualTable{Name: "posts", Module: "fts5", Args: []string{"title"}}},
+				&schema.ModifyObject{
+					From: &VirtualTable{Name: "tags", Module: "fts5", Args: []string{"name"}},
+					To:   &VirtualTable{Name: "tags", Module: "fts5", Args: []string{"name", "prefix='2 3'"}},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE VIRTUAL TABLE `docs` USING fts5 (title, body, tokenize='porter')", Reverse: "DROP TABLE `docs`"},
+					{Cmd: "DROP TABLE `posts`", Reverse: "CREATE VIRTUAL TABLE `posts` USING fts5 (title)"},
+					{Cmd: "DROP TABLE `tags`", Reverse: "CREATE VIRTUAL TABLE `tags` USING fts5 (name)"},
+					{Cmd: "CREATE VIRTUAL TABLE `tags` USING fts5 (name, prefix='2 3')", Reverse: "DROP TABLE `tags`"},
+				},
+			},
+		},
 		// Add STORED column.
 		{
 			changes: []schema.Change{
@@ -413,6 +602,42 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Attached databases.
+		func() struct {
+			changes []schema.Change
+			options []migrate.PlanOption
+			mock    func(mock)
+			plan    *migrate.Plan
+		} {
+			aux := schema.New("aux").AddAttrs(&File{Name: "aux.db"})
+			t1 := schema.NewTable("t1").SetSchema(aux).AddColumns(schema.NewIntColumn("a", "int"))
+			t1.AddIndexes(schema.NewIndex("t1_a").AddColumns(t1.Columns[0]))
+			t1.AddTriggers(&schema.Trigger{Name: "t1_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "BEGIN SELECT 1; END"})
+			t2, t3 := schema.NewTable("t2").SetSchema(aux), schema.NewTable("t3").SetSchema(aux)
+			return struct {
+				changes []schema.Change
+				options []migrate.PlanOption
+				mock    func(mock)
+				plan    *migrate.Plan
+			}{
+				changes: []schema.Change{
+					&schema.AddSchema{S: aux},
+					&schema.AddTable{T: t1},
+					&schema.AddTrigger{T: t1.Triggers[0]},
+					&schema.RenameTable{From: t2, To: t3},
+				},
+				plan: &migrate.Plan{
+					Reversible: true,
+					Changes: []*migrate.Change{
+						{Cmd: "ATTACH DATABASE 'aux.db' AS `aux`", Reverse: "DETACH DATABASE `aux`"},
+						{Cmd: "CREATE TABLE `aux`.`t1` (`a` int NOT NULL)", Reverse: "DROP TABLE `aux`.`t1`"},
+						{Cmd: "CREATE INDEX `aux`.`t1_a` ON `t1` (`a`)", Reverse: "DROP INDEX `aux`.`t1_a`"},
+						{Cmd: "CREATE TRIGGER `aux`.`t1_ai` AFTER INSERT ON `t1` FOR EACH ROW BEGIN SELECT 1; END", Reverse: "DROP TRIGGER `aux`.`t1_ai`"},
+						{Cmd: "ALTER TABLE `aux`.`t2` RENAME TO `t3`", Reverse: "ALTER TABLE `aux`.`t3` RENAME TO `t2`"},
+					},
+				},
+			}
+		}(),
 	}
 	for i, tt := range tests {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
@@ -451,6 +676,149 @@ func TestDefaultPlan(t *testing.T) {
 	require.EqualError(t, err, `create "t1" table: cannot execute statements without a database connection. use Open to create a new Driver`)
 }
 
+func TestApplyChanges_FKViolations(t *testing.T) {
+	db, mk, err := sqlmock.New()
+	require.NoError(t, err)
+	m := mock{mk}
+	m.systemVars("3.36.0")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "bigint"),
+			schema.NewIntColumn("nid", "bigint").
+				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "1", Type: "STORED"}),
+		)
+	m.ExpectQuery(sqltest.Escape("PRAGMA foreign_key_check")).
+		WillReturnRows(sqlmock.NewRows([]string{"table", "rowid", "parent", "fkid"}))
+	m.ExpectExec(sqltest.Escape("PRAGMA foreign_keys = off")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("CREATE TABLE `new_users` (`id` bigint NOT NULL, `nid` bigint NOT NULL AS (1) STORED)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("INSERT INTO `new_users` (`id`) SELECT `id` FROM `users`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("DROP TABLE `users`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("ALTER TABLE `new_users` RENAME TO `users`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectQuery(sqltest.Escape("PRAGMA foreign_key_check")).
+		WillReturnRows(sqlmock.NewRows([]string{"table", "rowid", "parent", "fkid"}).AddRow("pets", 1, "users", 0))
+	m.ExpectExec(sqltest.Escape("PRAGMA foreign_keys = on")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = drv.ApplyChanges(context.Background(), []schema.Change{
+		&schema.ModifyTable{
+			T:       users,
+			Changes: []schema.Change{&schema.AddColumn{C: users.Columns[1]}},
+		},
+	})
+	require.EqualError(t, err, `enable back the enforcement of foreign-keys constraints: sql/sqlite: foreign key mismatch: [{tbl:pets ref:users row:1 index:0}]`)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestPlanChanges_Strict(t *testing.T) {
+	tbl := schema.NewTable("t1").
+		AddColumns(
+			schema.NewIntColumn("a", "int"),
+			schema.NewColumn("b").SetType(&schema.UnsupportedType{T: "any"}),
+		).
+		AddAttrs(&Strict{})
+	changes, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE `t1` (`a` int NOT NULL, `b` any NOT NULL) STRICT", changes.Changes[0].Cmd)
+
+	tbl.AddColumns(schema.NewStringColumn("c", "varchar"))
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+	require.EqualError(t, err, `sqlite: invalid type "varchar" for column "c" in STRICT table "t1" (expect one of: int, integer, real, text, blob, any)`)
+
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: tbl,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: schema.NewFloatColumn("d", "double").SetNull(true)},
+			},
+		},
+	})
+	require.EqualError(t, err, `sqlite: invalid type "double" for column "d" in STRICT table "t1" (expect one of: int, integer, real, text, blob, any)`)
+}
+
+func TestPlanChanges_WithoutRowID(t *testing.T) {
+	tbl := schema.NewTable("t1").
+		AddColumns(schema.NewIntColumn("id", "integer"), schema.NewStringColumn("name", "text")).
+		AddAttrs(&WithoutRowID{})
+	tbl.SetPrimaryKey(schema.NewPrimaryKey(tbl.Columns[0]))
+	changes, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE `t1` (`id` integer NOT NULL, `name` text NOT NULL, PRIMARY KEY (`id`)) WITHOUT ROWID", changes.Changes[0].Cmd)
+
+	tbl.Columns[0].AddAttrs(&AutoIncrement{})
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+	require.EqualError(t, err, `sqlite: AUTOINCREMENT is not allowed on WITHOUT ROWID table "t1"`)
+}
+
+func TestPlanChanges_Collation(t *testing.T) {
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewStringColumn("name", "text").SetCollation(CollateNoCase),
+			schema.NewStringColumn("email", "text"),
+		)
+	users.AddIndexes(
+		schema.NewIndex("users_email").AddParts(
+			schema.NewColumnPart(users.Columns[1]).AddAttrs(&schema.Collation{V: CollateRTrim}).SetDesc(true),
+		),
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: users}})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, "CREATE TABLE `users` (`name` text NOT NULL COLLATE NOCASE, `email` text NOT NULL)", plan.Changes[0].Cmd)
+	require.Equal(t, "CREATE INDEX `users_email` ON `users` (`email` COLLATE RTRIM DESC)", plan.Changes[1].Cmd)
+}
+
+func TestPlanChanges_LibSQL(t *testing.T) {
+	db, mk, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{mk}.systemVars("3.44.0")
+	drv, err := OpenLibSQL(db)
+	require.NoError(t, err)
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "integer"),
+			schema.NewStringColumn("name", "text"),
+		)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	plan, err := drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{
+					From:   schema.NewNullStringColumn("name", "text"),
+					To:     users.Columns[1],
+					Change: schema.ChangeNull,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, "ALTER TABLE `users` ALTER COLUMN `name` TO `name` text NOT NULL", plan.Changes[0].Cmd)
+	require.Equal(t, "ALTER TABLE `users` ALTER COLUMN `name` TO `name` text NULL", plan.Changes[0].Reverse)
+
+	// Columns that are part of the primary key require rebuilding the table.
+	plan, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{
+					From:   schema.NewIntColumn("id", "int"),
+					To:     users.Columns[0],
+					Change: schema.ChangeType,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "PRAGMA foreign_keys = off", plan.Changes[0].Cmd)
+}
+
 func TestIndentedPlan(t *testing.T) {
 	tests := []struct {
 		T   *schema.Table
@@ -20,9 +20,10 @@ import (
 )
 
 type doc struct {
-	Tables  []*sqlspec.Table  `spec:"table"`
-	Views   []*sqlspec.View   `spec:"view"`
-	Schemas []*sqlspec.Schema `spec:"schema"`
+	Tables        []*sqlspec.Table    `spec:"table"`
+	Views         []*sqlspec.View     `spec:"view"`
+	VirtualTables []*VirtualTableSpec `spec:"virtual_table"`
+	Schemas       []*sqlspec.Schema   `spec:"schema"`
 }
 
 // evalSpec evaluates an Atlas DDL document using an unmarshaler into v by using the input.
@@ -39,6 +40,12 @@ func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
 		); err != nil {
 			return fmt.Errorf("specutil: failed converting to *schema.Realm: %w", err)
 		}
+		if err := convertSchemaFiles(d.Schemas, v); err != nil {
+			return err
+		}
+		if err := convertVirtualTables(d.VirtualTables, v); err != nil {
+			return err
+		}
 	case *schema.Schema:
 		var d doc
 		if err := hclState.Eval(p, &d, input); err != nil {
@@ -54,6 +61,12 @@ func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
 		); err != nil {
 			return err
 		}
+		if err := convertSchemaFiles(d.Schemas, r); err != nil {
+			return err
+		}
+		if err := convertVirtualTables(d.VirtualTables, r); err != nil {
+			return err
+		}
 		*v = *r.Schemas[0]
 	case schema.Schema, schema.Realm:
 		return fmt.Errorf("sqlite: Eval expects a pointer: received %[1]T, expected *%[1]T", v)
@@ -65,7 +78,48 @@ func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
 
 // MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
 func MarshalSpec(v any, marshaler schemahcl.Marshaler) ([]byte, error) {
-	return specutil.Marshal(v, marshaler, schemaSpec)
+	return specutil.Marshal(v, marshaler, func(s *schema.Schema) (*specutil.SchemaSpec, error) {
+		spec, err := schemaSpec(s)
+		if err != nil {
+			return nil, err
+		}
+		schemaFileSpec(s, spec.Schema)
+		for _, vt := range virtualTableSpecs(s) {
+			r, err := specutil.ExtraBlock("virtual_table", vt)
+			if err != nil {
+				return nil, err
+			}
+			spec.Extra = append(spec.Extra, r)
+		}
+		return spec, nil
+	})
+}
+
+// convertSchemaFiles converts the "file" attribute of the schema specs. The attribute
+// defines the database file of attached schemas (databases), and it is used for
+// attaching them to the connection in case they do not exist.
+func convertSchemaFiles(specs []*sqlspec.Schema, r *schema.Realm) error {
+	for _, spec := range specs {
+		attr, ok := spec.Attr("file")
+		if !ok {
+			continue
+		}
+		f, err := attr.String()
+		if err != nil {
+			return fmt.Errorf("expect string value for attribute schema.%s.file: %w", spec.Name, err)
+		}
+		if s, ok := r.Schema(spec.Name); ok {
+			s.AddAttrs(&File{Name: f})
+		}
+	}
+	return nil
+}
+
+// schemaFileSpec sets the "file" attribute of attached schemas.
+func schemaFileSpec(s *schema.Schema, spec *sqlspec.Schema) {
+	if f := (File{}); s.Name != mainFile && sqlx.Has(s.Attrs, &f) && f.Name != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("file", f.Name))
+	}
 }
 
 // convertTable converts a sqlspec.Table to a schema.Table. Table conversion is done without converting
@@ -94,6 +148,9 @@ func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, er
 			t.AddAttrs(&Strict{})
 		}
 	}
+	if err := convertTriggers(&spec.Extra, t); err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
@@ -110,7 +167,7 @@ func convertView(spec *sqlspec.View, parent *schema.Schema) (*schema.View, error
 
 // convertIndex converts a sqlspec.Index into a schema.Index.
 func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
-	idx, err := specutil.Index(spec, t)
+	idx, err := specutil.Index(spec, t, convertPart)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +181,18 @@ func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
 	return idx, nil
 }
 
+// convertPart converts the attributes of a sqlspec.IndexPart into the schema.IndexPart.
+func convertPart(spec *sqlspec.IndexPart, part *schema.IndexPart) error {
+	if attr, ok := spec.Attr("collate"); ok {
+		v, err := attr.String()
+		if err != nil {
+			return err
+		}
+		part.AddAttrs(&schema.Collation{V: v})
+	}
+	return nil
+}
+
 // convertColumn converts a sqlspec.Column into a schema.Column.
 func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error) {
 	c, err := specutil.Column(spec, convertColumnType)
@@ -139,6 +208,13 @@ func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error
 			c.AddAttrs(&AutoIncrement{})
 		}
 	}
+	if attr, ok := spec.Attr("collate"); ok {
+		v, err := attr.String()
+		if err != nil {
+			return nil, err
+		}
+		c.SetCollation(v)
+	}
 	if err := specutil.ConvertGenExpr(spec.Remain(), c, storedOrVirtual); err != nil {
 		return nil, err
 	}
@@ -178,6 +254,13 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 	if options != nil {
 		spec.Extra.Children = append(spec.Extra.Children, options)
 	}
+	for _, tr := range t.Triggers {
+		r, err := triggerSpec(tr)
+		if err != nil {
+			return nil, err
+		}
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
 	return spec, nil
 }
 
@@ -193,7 +276,7 @@ func viewSpec(view *schema.View) (*sqlspec.View, error) {
 }
 
 func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
-	spec, err := specutil.FromIndex(idx)
+	spec, err := specutil.FromIndex(idx, partAttr)
 	if err != nil {
 		return nil, err
 	}
@@ -203,6 +286,13 @@ func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
 	return spec, nil
 }
 
+func partAttr(_ *schema.Index, part *schema.IndexPart, spec *sqlspec.IndexPart) error {
+	if c, ok := sqlx.Collate(part.Attrs, nil); ok {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("collate", c))
+	}
+	return nil
+}
+
 // columnSpec converts from a concrete SQLite schema.Column into a sqlspec.Column.
 func columnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error) {
 	s, err := specutil.FromColumn(c, columnTypeSpec)
@@ -212,6 +302,9 @@ func columnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error) {
 	if sqlx.Has(c.Attrs, &AutoIncrement{}) {
 		s.Extra.Attrs = append(s.Extra.Attrs, schemahcl.BoolAttr("auto_increment", true))
 	}
+	if c, ok := sqlx.Collate(c.Attrs, nil); ok {
+		s.Extra.Attrs = append(s.Extra.Attrs, schemahcl.StringAttr("collate", c))
+	}
 	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
 		s.Extra.Children = append(s.Extra.Children, specutil.FromGenExpr(x, storedOrVirtual))
 	}
@@ -227,6 +320,40 @@ func columnTypeSpec(t schema.Type) (*sqlspec.Column, error) {
 	return &sqlspec.Column{Type: st}, nil
 }
 
+// customTypes holds the custom types that were registered by RegisterTypes.
+var customTypes = schemahcl.NewRegistry()
+
+// RegisterTypes registers custom types in the driver (e.g. types that are provided by
+// extensions or proprietary types). Registered types are used for parsing inspected column
+// types, formatting types in migration plans, and converting types from and to HCL. Types
+// are parsed from their database representation by their TypeSpec, in which the type
+// arguments are mapped to the attributes of the spec by their order. For example:
+//
+//	type GeoType struct {
+//		schema.Type
+//		T    string
+//		Size int
+//	}
+//
+//	sqlite.RegisterTypes(&schemahcl.TypeSpec{
+//		Name:       "geo",
+//		T:          "geo",
+//		Attributes: []*schemahcl.TypeAttr{schemahcl.SizeTypeAttr(false)},
+//		RType:      reflect.TypeOf(GeoType{}),
+//	})
+//
+// RegisterTypes is not safe for concurrent use, and should be called on initialization.
+func RegisterTypes(specs ...*schemahcl.TypeSpec) error {
+	if err := customTypes.Register(specs...); err != nil {
+		return err
+	}
+	if err := TypeRegistry.Register(specs...); err != nil {
+		return err
+	}
+	hclState = newState()
+	return nil
+}
+
 // TypeRegistry contains the supported TypeSpecs for the sqlite driver.
 var TypeRegistry = schemahcl.NewRegistry(
 	schemahcl.WithFormatter(FormatType),
@@ -257,6 +384,7 @@ var TypeRegistry = schemahcl.NewRegistry(
 		schemahcl.NewTypeSpec("clob", schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
 		schemahcl.NewTypeSpec("numeric", schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr(), schemahcl.ScaleTypeAttr())),
 		schemahcl.NewTypeSpec("decimal", schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr(), schemahcl.ScaleTypeAttr())),
+		schemahcl.NewTypeSpec(TypeAny),
 		schemahcl.NewTypeSpec("bool"),
 		schemahcl.NewTypeSpec("boolean"),
 		schemahcl.NewTypeSpec("date"),
@@ -267,14 +395,7 @@ var TypeRegistry = schemahcl.NewRegistry(
 )
 
 var (
-	hclState = schemahcl.New(append(
-		specOptions,
-		schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
-		schemahcl.WithTypes("view.column.type", TypeRegistry.Specs()),
-		schemahcl.WithScopedEnums("table.column.as.type", stored, virtual),
-		schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
-		schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
-	)...)
+	hclState = newState()
 	// MarshalHCL marshals v into an Atlas HCL DDL document.
 	MarshalHCL = schemahcl.MarshalerFunc(func(v any) ([]byte, error) {
 		return MarshalSpec(v, hclState)
@@ -287,6 +408,18 @@ var (
 	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
 )
 
+// newState returns the HCL state of the driver, configured with the types of the TypeRegistry.
+func newState() *schemahcl.State {
+	return schemahcl.New(append(
+		specOptions,
+		schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
+		schemahcl.WithTypes("view.column.type", TypeRegistry.Specs()),
+		schemahcl.WithScopedEnums("table.column.as.type", stored, virtual),
+		schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
+		schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
+	)...)
+}
+
 // storedOrVirtual returns a STORED or VIRTUAL
 // generated type option based on the given string.
 func storedOrVirtual(s string) string {
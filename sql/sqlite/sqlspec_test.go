@@ -458,6 +458,10 @@ func TestTypes(t *testing.T) {
 			typeExpr: "uuid",
 			expected: &UUIDType{T: "uuid"},
 		},
+		{
+			typeExpr: "any",
+			expected: &schema.UnsupportedType{T: "any"},
+		},
 	} {
 		t.Run(tt.typeExpr, func(t *testing.T) {
 			var test schema.Schema
@@ -515,6 +519,161 @@ schema "test" {
 	require.EqualValues(t, expected, string(buf))
 }
 
+func TestMarshalSpec_Triggers(t *testing.T) {
+	s := schema.New("test").
+		AddTables(
+			schema.NewTable("users").
+				AddColumns(
+					schema.NewIntColumn("id", "int"),
+				).
+				AddTriggers(
+					&schema.Trigger{Name: "users_ai", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "BEGIN SELECT 1; END"},
+					&schema.Trigger{Name: "users_ad", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventDelete}, Body: "BEGIN\n  SELECT 1;\nEND", Attrs: []schema.Attr{&TriggerWhen{X: "OLD.id > 0"}}},
+				),
+		)
+	s.Tables[0].SetSchema(s)
+	buf, err := MarshalSpec(s, hclState)
+	require.NoError(t, err)
+	const expected = `table "users" {
+  schema = schema.test
+  column "id" {
+    null = false
+    type = int
+  }
+  trigger "users_ai" {
+    as = "CREATE TRIGGER ` + "`users_ai`" + ` AFTER INSERT ON ` + "`users`" + ` FOR EACH ROW BEGIN SELECT 1; END"
+  }
+  trigger "users_ad" {
+    as = <<-SQL
+    CREATE TRIGGER ` + "`users_ad`" + ` AFTER DELETE ON ` + "`users`" + ` FOR EACH ROW WHEN OLD.id > 0 BEGIN
+      SELECT 1;
+    END
+    SQL
+  }
+}
+schema "test" {
+}
+`
+	require.EqualValues(t, expected, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Tables[0].Triggers, 2)
+	for i, tr := range got.Tables[0].Triggers {
+		require.Equal(t, s.Tables[0].Triggers[i].Name, tr.Name)
+		require.Equal(t, got.Tables[0], tr.Table)
+		require.False(t, (&diff{}).TriggerChanged(s.Tables[0].Triggers[i], tr))
+	}
+}
+
+func TestMarshalSpec_VirtualTables(t *testing.T) {
+	s := schema.New("main").
+		AddTables(
+			schema.NewTable("users").
+				AddColumns(
+					schema.NewIntColumn("id", "int"),
+				),
+		)
+	s.AddObjects(&VirtualTable{Name: "docs", Schema: s, Module: "fts5", Args: []string{"title", "body", "tokenize='porter'"}})
+	buf, err := MarshalSpec(s, hclState)
+	require.NoError(t, err)
+	const expected = `table "users" {
+  schema = schema.main
+  column "id" {
+    null = false
+    type = int
+  }
+}
+virtual_table "docs" {
+  schema = schema.main
+  using  = "fts5"
+  args   = ["title", "body", "tokenize='porter'"]
+}
+schema "main" {
+}
+`
+	require.EqualValues(t, expected, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Objects, 1)
+	vt := got.Objects[0].(*VirtualTable)
+	require.Equal(t, "docs", vt.Name)
+	require.Equal(t, "fts5", vt.Module)
+	require.Equal(t, []string{"title", "body", "tokenize='porter'"}, vt.Args)
+}
+
+func TestMarshalSpec_AttachedSchema(t *testing.T) {
+	r := schema.NewRealm(
+		schema.New("main").AddAttrs(&File{Name: "main.db"}),
+		schema.New("aux").
+			AddAttrs(&File{Name: "aux.db"}).
+			AddTables(schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))),
+	)
+	buf, err := MarshalSpec(r, hclState)
+	require.NoError(t, err)
+	const expected = `table "users" {
+  schema = schema.aux
+  column "id" {
+    null = false
+    type = int
+  }
+}
+schema "main" {
+}
+schema "aux" {
+  file = "aux.db"
+}
+`
+	require.EqualValues(t, expected, string(buf))
+	var got schema.Realm
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Schemas, 2)
+	require.Empty(t, got.Schemas[0].Attrs)
+	require.Equal(t, []schema.Attr{&File{Name: "aux.db"}}, got.Schemas[1].Attrs)
+	require.Equal(t, "aux", got.Schemas[1].Tables[0].Schema.Name)
+}
+
+func TestMarshalSpec_Collation(t *testing.T) {
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewStringColumn("name", "text").SetCollation("NOCASE"),
+			schema.NewStringColumn("email", "text"),
+		)
+	users.AddIndexes(
+		schema.NewIndex("users_email").AddParts(
+			schema.NewColumnPart(users.Columns[1]).AddAttrs(&schema.Collation{V: "RTRIM"}),
+		),
+	)
+	s := schema.New("main").AddTables(users)
+	buf, err := MarshalSpec(s, hclState)
+	require.NoError(t, err)
+	const expected = `table "users" {
+  schema = schema.main
+  column "name" {
+    null    = false
+    type    = text
+    collate = "NOCASE"
+  }
+  column "email" {
+    null = false
+    type = text
+  }
+  index "users_email" {
+    on {
+      column  = column.email
+      collate = "RTRIM"
+    }
+  }
+}
+schema "main" {
+}
+`
+	require.EqualValues(t, expected, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Equal(t, []schema.Attr{&schema.Collation{V: "NOCASE"}}, got.Tables[0].Columns[0].Attrs)
+	require.Equal(t, []schema.Attr{&schema.Collation{V: "RTRIM"}}, got.Tables[0].Indexes[0].Parts[0].Attrs)
+}
+
 func TestInputVars(t *testing.T) {
 	spectest.TestInputVars(t, EvalHCL)
 }
@@ -0,0 +1,250 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// TriggerWhen describes the WHEN clause of a trigger.
+// See: https://www.sqlite.org/lang_createtrigger.html
+type TriggerWhen struct {
+	schema.Attr
+	X string
+}
+
+// triggers queries and appends the triggers of the schema tables.
+func (i *inspect) triggers(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(triggersQuery, s.Name))
+	if err != nil {
+		return fmt.Errorf("sqlite: querying schema triggers: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, table, stmt string
+		if err := rows.Scan(&name, &table, &stmt); err != nil {
+			return fmt.Errorf("sqlite: scanning trigger: %w", err)
+		}
+		// Triggers that are defined on views or on tables
+		// that were not inspected (filtered) are skipped.
+		t, ok := s.Table(table)
+		if !ok {
+			continue
+		}
+		tr, err := parseTrigger(t, name, stmt)
+		if err != nil {
+			return err
+		}
+		t.AddTriggers(tr)
+	}
+	return rows.Err()
+}
+
+// Identifiers in trigger definitions are either quoted or plain names.
+const triggerIdent = `(?:"[^"]+"|` + "`[^`]+`" + `|\[[^]]+]|[^\s.(]+)`
+
+var (
+	// reTrigger matches the parts of a CREATE TRIGGER statement: the action time, the event,
+	// the columns of an UPDATE OF event, the WHEN expression, and the trigger body.
+	reTrigger = regexp.MustCompile(`(?is)^CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?TRIGGER\s+(?:IF\s+NOT\s+EXISTS\s+)?(?:` + triggerIdent + `\s*\.\s*)?` + triggerIdent +
+		`\s+(?:(BEFORE|AFTER|INSTEAD\s+OF)\s+)?(DELETE|INSERT|UPDATE)(?:\s+OF\s+(.+?))?\s+ON\s+` + triggerIdent + `\s+(?:FOR\s+EACH\s+ROW\s+)?(?:WHEN\s+(.+?)\s+)?(BEGIN\b.*)$`)
+	reSpaces = regexp.MustCompile(`\s+`)
+)
+
+// parseTrigger parses the CREATE TRIGGER statement of the trigger defined on the given table.
+func parseTrigger(t *schema.Table, name, stmt string) (*schema.Trigger, error) {
+	m := reTrigger.FindStringSubmatch(strings.TrimRight(strings.TrimSpace(stmt), ";"))
+	if m == nil {
+		return nil, fmt.Errorf("sqlite: unexpected definition for trigger %q: %s", name, stmt)
+	}
+	tr := &schema.Trigger{
+		Name:       name,
+		Table:      t,
+		ActionTime: schema.TriggerTimeBefore,
+		Events:     []schema.TriggerEvent{{Name: strings.ToUpper(m[2])}},
+		For:        schema.TriggerForRow,
+		Body:       m[5],
+	}
+	if m[1] != "" {
+		tr.ActionTime = schema.TriggerTime(strings.ToUpper(reSpaces.ReplaceAllString(m[1], " ")))
+	}
+	if m[3] != "" {
+		for _, n := range strings.Split(m[3], ",") {
+			n = strings.Trim(strings.TrimSpace(n), "\"`[]")
+			c, ok := t.Column(n)
+			if !ok {
+				return nil, fmt.Errorf("sqlite: column %q of trigger %q was not found in table %q", n, name, t.Name)
+			}
+			tr.Events[0].Columns = append(tr.Events[0].Columns, c)
+		}
+	}
+	if m[4] != "" {
+		tr.Attrs = append(tr.Attrs, &TriggerWhen{X: m[4]})
+	}
+	return tr, nil
+}
+
+// triggerDef writes the rest of the CREATE TRIGGER statement to the builder, that
+// holds the CREATE TRIGGER clause and the trigger name, and returns its string.
+func triggerDef(b *sqlx.Builder, tr *schema.Trigger) (string, error) {
+	if tr.Table == nil {
+		return "", fmt.Errorf("sqlite: trigger %q is not defined on a table", tr.Name)
+	}
+	if len(tr.Events) != 1 {
+		return "", fmt.Errorf("sqlite: trigger %q must be fired by exactly one event, got %d", tr.Name, len(tr.Events))
+	}
+	if tr.For == schema.TriggerForStmt {
+		return "", fmt.Errorf("sqlite: trigger %q cannot be fired for each statement", tr.Name)
+	}
+	at := tr.ActionTime
+	if at == "" {
+		at = schema.TriggerTimeBefore
+	}
+	e := tr.Events[0]
+	b.P(string(at), e.Name)
+	if len(e.Columns) > 0 {
+		b.P("OF")
+		b.MapComma(e.Columns, func(i int, b *sqlx.Builder) {
+			b.Ident(e.Columns[i].Name)
+		})
+	}
+	// The table is not qualified, as triggers
+	// are created in the schema of their table.
+	b.P("ON").Ident(tr.Table.Name).P("FOR EACH ROW")
+	if w := (TriggerWhen{}); sqlx.Has(tr.Attrs, &w) {
+		b.P("WHEN", w.X)
+	}
+	b.P(tr.Body)
+	return b.String(), nil
+}
+
+// TriggerChanged reports if the trigger definition was changed. The
+// statements are compared after their formatting is normalized.
+func (*diff) TriggerChanged(from, to *schema.Trigger) bool {
+	d1, err1 := triggerDef(&sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`'}, from)
+	d2, err2 := triggerDef(&sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`'}, to)
+	return err1 != nil || err2 != nil || normalizeDef(d1) != normalizeDef(d2)
+}
+
+// addTriggers creates the triggers of the table. It is called after the table
+// was rebuilt, as dropping a table drops its triggers.
+func (s *state) addTriggers(t *schema.Table) error {
+	for _, tr := range t.Triggers {
+		if err := s.addTrigger(&schema.AddTrigger{T: tr}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTrigger appends the change for creating the trigger.
+func (s *state) addTrigger(add *schema.AddTrigger) error {
+	create, drop, err := s.createDropTrigger(add.T)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create trigger %q on table: %q", add.T.Name, add.T.Table.Name),
+	})
+	return nil
+}
+
+// dropTrigger appends the change for dropping the trigger.
+func (s *state) dropTrigger(drop *schema.DropTrigger) error {
+	create, cmd, err := s.createDropTrigger(drop.T)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     cmd,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop trigger %q from table: %q", drop.T.Name, drop.T.Table.Name),
+	})
+	return nil
+}
+
+// modifyTrigger appends the changes for modifying the trigger. SQLite
+// does not support altering triggers, and therefore, it is recreated.
+func (s *state) modifyTrigger(modify *schema.ModifyTrigger) error {
+	if err := s.dropTrigger(&schema.DropTrigger{T: modify.From}); err != nil {
+		return err
+	}
+	return s.addTrigger(&schema.AddTrigger{T: modify.To})
+}
+
+// createDropTrigger returns the CREATE and DROP statements of the trigger. SQLite
+// creates unqualified triggers in the "main" database. Hence, triggers of attached
+// databases are qualified with their schema name.
+func (s *state) createDropTrigger(tr *schema.Trigger) (string, string, error) {
+	if tr.Table == nil {
+		return "", "", fmt.Errorf("sqlite: planning triggers on views is not supported. trigger: %q", tr.Name)
+	}
+	name := s.Build().Ident(tr.Name).String()
+	if q := s.qualifier(tr.Table.Schema); q != "" {
+		name = s.Build().Ident(q).String() + "." + name
+	}
+	create, err := triggerDef(s.Build("CREATE TRIGGER", name), tr)
+	if err != nil {
+		return "", "", err
+	}
+	return create, s.Build("DROP TRIGGER", name).String(), nil
+}
+
+// convertTriggers converts the trigger blocks of the table spec.
+func convertTriggers(r *schemahcl.Resource, t *schema.Table) error {
+	for _, c := range r.Children {
+		if c.Type != "trigger" {
+			continue
+		}
+		as, ok := c.Attr("as")
+		if !ok {
+			return fmt.Errorf("missing 'as' definition for trigger %q", c.Name)
+		}
+		def, err := as.String()
+		if err != nil {
+			return fmt.Errorf("expect string definition for attribute trigger.%s.as: %w", c.Name, err)
+		}
+		tr, err := parseTrigger(t, c.Name, def)
+		if err != nil {
+			return err
+		}
+		t.AddTriggers(tr)
+	}
+	return nil
+}
+
+// triggerSpec returns the trigger block of the given trigger.
+func triggerSpec(tr *schema.Trigger) (*schemahcl.Resource, error) {
+	b := &sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`'}
+	as, err := triggerDef(b.P("CREATE TRIGGER").Ident(tr.Name), tr)
+	if err != nil {
+		return nil, err
+	}
+	// Similar to views, multi-line definitions are formatted as indented
+	// heredoc. Trigger blocks are nested in tables, hence, four spaces.
+	if lines := strings.Split(as, "\n"); len(lines) > 1 {
+		as = fmt.Sprintf("<<-SQL\n    %s\n    SQL", strings.Join(lines, "\n    "))
+	}
+	return &schemahcl.Resource{
+		Type:  "trigger",
+		Name:  tr.Name,
+		Attrs: []*schemahcl.Attr{schemahcl.StringAttr("as", as)},
+	}, nil
+}
+
+// Query to list database triggers.
+const triggersQuery = "SELECT `name`, `tbl_name`, `sql` FROM `%s`.sqlite_master WHERE `type` = 'trigger' ORDER BY `name`"
@@ -0,0 +1,318 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// inspectViews queries and appends the views of the schemas in the realm.
+func (i *inspect) inspectViews(ctx context.Context, r *schema.Realm, _ *schema.InspectOptions) error {
+	for _, s := range r.Schemas {
+		views, err := i.views(ctx, s.Name)
+		if err != nil {
+			return err
+		}
+		s.AddViews(views...)
+		for _, v := range views {
+			if err := i.viewColumns(ctx, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// views returns the views defined in the given database (schema).
+func (i *inspect) views(ctx context.Context, name string) ([]*schema.View, error) {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(viewsQuery, name))
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: querying schema views: %w", err)
+	}
+	defer rows.Close()
+	var views []*schema.View
+	for rows.Next() {
+		var name, stmt string
+		if err := rows.Scan(&name, &stmt); err != nil {
+			return nil, fmt.Errorf("sqlite: scanning view: %w", err)
+		}
+		stmt = strings.TrimSpace(stmt)
+		def, err := viewDef(stmt)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, schema.NewView(name, def).AddAttrs(&CreateStmt{S: stmt}))
+	}
+	return views, rows.Err()
+}
+
+// viewColumns queries and appends the columns of the given view.
+func (i *inspect) viewColumns(ctx context.Context, v *schema.View) error {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, v.Name, schemaName(v.Schema)))
+	if err != nil {
+		return fmt.Errorf("sqlite: querying %q columns: %w", v.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			nullable, primary   bool
+			hidden              sql.NullInt64
+			name, typ, defaults sql.NullString
+		)
+		if err := rows.Scan(&name, &typ, &nullable, &defaults, &primary, &hidden); err != nil {
+			return fmt.Errorf("sqlite: %w", err)
+		}
+		t, err := ParseType(typ.String)
+		if err != nil {
+			return fmt.Errorf("sqlite: %w", err)
+		}
+		v.AddColumns(&schema.Column{
+			Name: name.String,
+			Type: &schema.ColumnType{Raw: typ.String, Null: nullable, Type: t},
+		})
+	}
+	return rows.Err()
+}
+
+// reViewDef extracts the view definition (SELECT statement) from its CREATE statement.
+var reViewDef = regexp.MustCompile(`(?is)^CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?(?:"[^"]+"|` + "`[^`]+`" + `|\[[^]]+]|[^\s(]+)\s*(?:\([^)]*\))?\s*AS\s+(.+)$`)
+
+// viewDef returns the definition of the view from its CREATE statement.
+func viewDef(stmt string) (string, error) {
+	m := reViewDef.FindStringSubmatch(stmt)
+	if len(m) != 2 {
+		return "", fmt.Errorf("sqlite: unexpected view definition: %q", stmt)
+	}
+	return sqlx.TrimViewExtra(m[1]), nil
+}
+
+// ViewDefChanged reports if the view definition was changed. SQLite keeps the
+// definition as it was written by the user, and therefore, definitions are
+// compared after their formatting is normalized.
+func (*diff) ViewDefChanged(from, to *schema.View) bool {
+	return normalizeDef(from.Def) != normalizeDef(to.Def)
+}
+
+// normalizeDef returns a normalized form of a view or trigger definition for comparison.
+// i.e., whitespaces are collapsed, and keywords and unquoted identifiers are lowercased.
+func normalizeDef(def string) string {
+	var (
+		b     strings.Builder
+		space bool
+		s     = sqlx.TrimViewExtra(def)
+	)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			end := c
+			if c == '[' {
+				end = ']'
+			}
+			j := strings.IndexByte(s[i+1:], end)
+			if j == -1 {
+				j = len(s) - i - 1
+			}
+			writeSpace(&b, space, c)
+			b.WriteString(s[i : i+j+2])
+			i += j + 1
+			space = false
+		default:
+			writeSpace(&b, space, c)
+			b.WriteString(strings.ToLower(string(c)))
+			space = false
+		}
+	}
+	return b.String()
+}
+
+// writeSpace writes a single space before c, unless it is
+// the first character, or it is adjacent to a punctuation.
+func writeSpace(b *strings.Builder, space bool, c byte) {
+	if !space || b.Len() == 0 || strings.IndexByte("(),", c) != -1 {
+		return
+	}
+	if last := b.String()[b.Len()-1]; strings.IndexByte("(,", last) == -1 {
+		b.WriteByte(' ')
+	}
+}
+
+// addView builds and executes the query for creating a view.
+func (s *state) addView(add *schema.AddView) error {
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     s.createView(add.V, add.Extra...),
+		Reverse: s.Build("DROP VIEW").View(add.V).String(),
+		Comment: fmt.Sprintf("create %q view", add.V.Name),
+	})
+	return nil
+}
+
+// dropView builds and executes the query for dropping a view.
+func (s *state) dropView(drop *schema.DropView) error {
+	b := s.Build("DROP VIEW")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     b.View(drop.V).String(),
+		Reverse: s.createView(drop.V),
+		Comment: fmt.Sprintf("drop %q view", drop.V.Name),
+	})
+	return nil
+}
+
+// modifyView builds and executes the queries for modifying a view. SQLite does
+// not support altering views, and therefore, the view is dropped and re-created.
+func (s *state) modifyView(modify *schema.ModifyView) error {
+	s.append(&migrate.Change{
+		Source:  modify,
+		Cmd:     s.Build("DROP VIEW").View(modify.From).String(),
+		Reverse: s.createView(modify.From),
+		Comment: fmt.Sprintf("drop %q view", modify.From.Name),
+	})
+	s.append(&migrate.Change{
+		Source:  modify,
+		Cmd:     s.createView(modify.To),
+		Reverse: s.Build("DROP VIEW").View(modify.To).String(),
+		Comment: fmt.Sprintf("create %q view", modify.To.Name),
+	})
+	return nil
+}
+
+// renameView builds and executes the queries for renaming a view.
+// SQLite does not support renaming views, and therefore, the view
+// is dropped and re-created with its new name.
+func (s *state) renameView(rename *schema.RenameView) error {
+	return s.modifyView(&schema.ModifyView{From: rename.From, To: rename.To})
+}
+
+// createView returns the CREATE VIEW statement of the given view.
+func (s *state) createView(v *schema.View, extra ...schema.Clause) string {
+	b := s.Build("CREATE VIEW")
+	if sqlx.Has(extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	return b.View(v).P("AS", sqlx.TrimViewExtra(v.Def)).String()
+}
+
+// viewsOf returns the views that depend on the given table (directly or through other
+// views). In SQLite, renaming a table fails if the schema contains views that reference
+// tables that do not exist. Therefore, these views are dropped before the table is rebuilt
+// and re-created after. Views that are dropped by the plan are returned in the second list.
+func (s *state) viewsOf(t *schema.Table) (recreate, drop []*schema.View) {
+	var (
+		views []*schema.View
+		names = map[string]bool{t.Name: true}
+	)
+	if t.Schema != nil {
+		for _, v := range t.Schema.Views {
+			// Views that are created by the plan do not exist yet.
+			if !s.addV[v.Name] {
+				views = append(views, v)
+			}
+		}
+	}
+	for _, d := range s.dropV {
+		if d != nil {
+			views = append(views, d.V)
+		}
+	}
+	// Keep the order of the dropped views deterministic.
+	sort.SliceStable(views, func(i, j int) bool {
+		_, d1 := s.dropV[views[i].Name]
+		_, d2 := s.dropV[views[j].Name]
+		return d1 && !d2 || d1 && d2 && views[i].Name < views[j].Name
+	})
+	for found := true; found; {
+		found = false
+		for _, v := range views {
+			if !names[v.Name] && dependsOn(v, names) {
+				names[v.Name], found = true, true
+			}
+		}
+	}
+	for _, v := range views {
+		switch {
+		case !names[v.Name]:
+		case s.dropV[v.Name] != nil:
+			drop = append(drop, v)
+		default:
+			recreate = append(recreate, v)
+		}
+	}
+	return recreate, drop
+}
+
+// dropViewsOf drops the views that depend on the given table before it is rebuilt,
+// and returns a function for re-creating them after the table is renamed back.
+func (s *state) dropViewsOf(t *schema.Table) func() {
+	recreate, drop := s.viewsOf(t)
+	for _, v := range drop {
+		// The view is dropped by the plan. Hence, we drop it
+		// here and skip its DropView change when it is planned.
+		d := s.dropV[v.Name]
+		s.dropV[v.Name] = nil
+		_ = s.dropView(d)
+	}
+	for _, v := range recreate {
+		s.append(&migrate.Change{
+			Cmd:     s.Build("DROP VIEW").View(v).String(),
+			Reverse: s.createView(v),
+			Comment: fmt.Sprintf("drop %q view before rebuilding table %q", v.Name, t.Name),
+		})
+	}
+	return func() {
+		for _, v := range recreate {
+			s.append(&migrate.Change{
+				Cmd:     s.createView(v),
+				Reverse: s.Build("DROP VIEW").View(v).String(),
+				Comment: fmt.Sprintf("re-create %q view after rebuilding table %q", v.Name, t.Name),
+			})
+		}
+	}
+}
+
+// dependsOn reports if the view depends on one of the given tables or views.
+func dependsOn(v *schema.View, names map[string]bool) bool {
+	for _, d := range v.Deps {
+		switch d := d.(type) {
+		case *schema.Table:
+			if names[d.Name] {
+				return true
+			}
+		case *schema.View:
+			if names[d.Name] {
+				return true
+			}
+		}
+	}
+	for n := range names {
+		if references(v.Def, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// references reports if the given definition (e.g. view or trigger) references the given name.
+func references(def, name string) bool {
+	re, err := regexp.Compile(`(?i)(?:^|[^\w$])["` + "`" + `\[]?` + regexp.QuoteMeta(name) + `["` + "`" + `\]]?(?:[^\w$]|$)`)
+	return err == nil && re.MatchString(def)
+}
+
+// Query to list database views.
+const viewsQuery = "SELECT `name`, `sql` FROM `%s`.sqlite_master WHERE `type` = 'view' AND `name` NOT LIKE 'sqlite_%%' ORDER BY `name`"
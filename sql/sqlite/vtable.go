@@ -0,0 +1,222 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// VirtualTable describes a virtual table, such as an FTS5 full-text search
+	// table. Virtual tables are stored in the objects of their schema, and their
+	// module arguments are kept as-is, as their meaning is defined by the module.
+	// See: https://www.sqlite.org/vtab.html
+	VirtualTable struct {
+		schema.Object
+		Name   string
+		Schema *schema.Schema
+		Module string   // e.g. fts5.
+		Args   []string // Module arguments. e.g. columns and options.
+	}
+
+	// VirtualTableSpec holds a specification for a virtual table.
+	VirtualTableSpec struct {
+		Name   string         `spec:",name"`
+		Schema *schemahcl.Ref `spec:"schema"`
+		Using  string         `spec:"using"`
+		Args   []string       `spec:"args,omitempty"`
+		schemahcl.DefaultExtension
+	}
+)
+
+func init() {
+	schemahcl.Register("virtual_table", &VirtualTableSpec{})
+}
+
+// reVirtualTable extracts the module name and arguments from the CREATE VIRTUAL TABLE statement.
+var reVirtualTable = regexp.MustCompile(`(?is)^CREATE\s+VIRTUAL\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?.+?\s+USING\s+(\w+)\s*(?:\((.*)\))?\s*;?$`)
+
+// newVirtualTable parses and returns the virtual table from its CREATE statement.
+func newVirtualTable(name, stmt string) (*VirtualTable, error) {
+	m := reVirtualTable.FindStringSubmatch(stmt)
+	if len(m) != 3 {
+		return nil, fmt.Errorf("sqlite: unexpected virtual table definition: %q", stmt)
+	}
+	return &VirtualTable{Name: name, Module: m[1], Args: splitArgs(m[2])}, nil
+}
+
+// splitArgs splits the module arguments by commas that
+// are not wrapped with parentheses or quotes.
+func splitArgs(s string) []string {
+	var (
+		args  []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '\'', '"', '`':
+			if j := strings.IndexByte(s[i+1:], s[i]); j != -1 {
+				i += j + 1
+			}
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if a := strings.TrimSpace(s[start:]); a != "" {
+		args = append(args, a)
+	}
+	return args
+}
+
+// virtualTablesOf returns the virtual tables of the schema.
+func virtualTablesOf(s *schema.Schema) []*VirtualTable {
+	var vts []*VirtualTable
+	for _, o := range s.Objects {
+		if vt, ok := o.(*VirtualTable); ok {
+			vts = append(vts, vt)
+		}
+	}
+	return vts
+}
+
+// findVirtualTable returns the virtual table with the given name, if exists.
+func findVirtualTable(s *schema.Schema, name string) (*VirtualTable, bool) {
+	for _, vt := range virtualTablesOf(s) {
+		if vt.Name == name {
+			return vt, true
+		}
+	}
+	return nil, false
+}
+
+// virtualTablesDiff returns the changes for migrating the virtual tables of the schema.
+func virtualTablesDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, v1 := range virtualTablesOf(from) {
+		switch v2, ok := findVirtualTable(to, v1.Name); {
+		case !ok:
+			changes = append(changes, &schema.DropObject{O: v1})
+		case !strings.EqualFold(v1.Module, v2.Module) || !sqlx.ValuesEqual(v1.Args, v2.Args):
+			changes = append(changes, &schema.ModifyObject{From: v1, To: v2})
+		}
+	}
+	for _, v2 := range virtualTablesOf(to) {
+		if _, ok := findVirtualTable(from, v2.Name); !ok {
+			changes = append(changes, &schema.AddObject{O: v2})
+		}
+	}
+	return changes
+}
+
+// createVirtualTable returns the CREATE VIRTUAL TABLE statement of the given table.
+func (s *state) createVirtualTable(vt *VirtualTable) string {
+	b := s.Build("CREATE VIRTUAL TABLE").SchemaResource(vt.Schema, vt.Name).P("USING", vt.Module)
+	if len(vt.Args) > 0 {
+		b.Wrap(func(b *sqlx.Builder) {
+			b.WriteString(strings.Join(vt.Args, ", "))
+		})
+	}
+	return b.String()
+}
+
+// addVirtualTable appends the change for creating the virtual table.
+func (s *state) addVirtualTable(c schema.Change, vt *VirtualTable) {
+	s.append(&migrate.Change{
+		Source:  c,
+		Cmd:     s.createVirtualTable(vt),
+		Reverse: s.Build("DROP TABLE").SchemaResource(vt.Schema, vt.Name).String(),
+		Comment: fmt.Sprintf("create %q virtual table", vt.Name),
+	})
+}
+
+// dropVirtualTable appends the change for dropping the virtual table.
+func (s *state) dropVirtualTable(c schema.Change, vt *VirtualTable) {
+	s.append(&migrate.Change{
+		Source:  c,
+		Cmd:     s.Build("DROP TABLE").SchemaResource(vt.Schema, vt.Name).String(),
+		Reverse: s.createVirtualTable(vt),
+		Comment: fmt.Sprintf("drop %q virtual table", vt.Name),
+	})
+}
+
+// planObject plans the creation, removal or modification of schema objects.
+// Virtual tables cannot be altered, and therefore, they are re-created.
+func (s *state) planObject(c schema.Change) error {
+	switch c := c.(type) {
+	case *schema.AddObject:
+		vt, ok := c.O.(*VirtualTable)
+		if !ok {
+			return fmt.Errorf("unsupported object type %T", c.O)
+		}
+		s.addVirtualTable(c, vt)
+	case *schema.DropObject:
+		vt, ok := c.O.(*VirtualTable)
+		if !ok {
+			return fmt.Errorf("unsupported object type %T", c.O)
+		}
+		s.dropVirtualTable(c, vt)
+	case *schema.ModifyObject:
+		from, ok1 := c.From.(*VirtualTable)
+		to, ok2 := c.To.(*VirtualTable)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("unsupported object types %T -> %T", c.From, c.To)
+		}
+		s.dropVirtualTable(c, from)
+		s.addVirtualTable(c, to)
+	}
+	return nil
+}
+
+// convertVirtualTables converts the virtual table specs and appends them to their schemas.
+func convertVirtualTables(specs []*VirtualTableSpec, r *schema.Realm) error {
+	for _, spec := range specs {
+		name, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from virtual table reference: %w", err)
+		}
+		s, ok := r.Schema(name)
+		if !ok {
+			return fmt.Errorf("schema %q defined on virtual table %q was not found in realm", name, spec.Name)
+		}
+		if _, ok := findVirtualTable(s, spec.Name); ok {
+			return fmt.Errorf("duplicate virtual table %q in schema %q", spec.Name, name)
+		}
+		if spec.Using == "" {
+			return fmt.Errorf("missing module definition (using) for virtual table %q", spec.Name)
+		}
+		s.AddObjects(&VirtualTable{Name: spec.Name, Schema: s, Module: spec.Using, Args: spec.Args})
+	}
+	return nil
+}
+
+// virtualTableSpecs returns the specs of the virtual tables in the schema.
+func virtualTableSpecs(s *schema.Schema) []*VirtualTableSpec {
+	var specs []*VirtualTableSpec
+	for _, vt := range virtualTablesOf(s) {
+		specs = append(specs, &VirtualTableSpec{
+			Name:   vt.Name,
+			Schema: specutil.SchemaRef(s.Name),
+			Using:  vt.Module,
+			Args:   vt.Args,
+		})
+	}
+	return specs
+}
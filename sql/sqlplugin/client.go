@@ -0,0 +1,374 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlplugin
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+)
+
+// Register registers the plugin executable located at path as
+// the driver of the given URL scheme in sqlclient. Opening the
+// driver starts the plugin process.
+func Register(name, path string, opts ...sqlclient.RegisterOption) {
+	sqlclient.Register(name, Opener(path), opts...)
+}
+
+// Opener returns a sqlclient.Opener that starts the plugin executable located
+// at path, connects to it after the handshake, and opens its driver with the
+// given URL.
+func Opener(path string, args ...string) sqlclient.Opener {
+	return sqlclient.OpenerFunc(func(ctx context.Context, u *url.URL) (*sqlclient.Client, error) {
+		h := &handshake{line: make(chan string, 1)}
+		cmd := exec.Command(path, args...)
+		cmd.Env = append(os.Environ(), MagicCookieKey+"="+MagicCookieValue)
+		cmd.Stdout, cmd.Stderr = h, os.Stderr
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("sqlplugin: starting plugin %q: %w", path, err)
+		}
+		exited := make(chan error, 1)
+		go func() { exited <- cmd.Wait() }()
+		kill := func(err error) (*sqlclient.Client, error) {
+			cmd.Process.Kill()
+			<-exited
+			return nil, err
+		}
+		var line string
+		select {
+		case line = <-h.line:
+		case err := <-exited:
+			return nil, fmt.Errorf("sqlplugin: plugin %q exited before handshake: %v", path, err)
+		case <-ctx.Done():
+			return kill(ctx.Err())
+		}
+		network, addr, err := parseHandshake(line)
+		if err != nil {
+			return kill(fmt.Errorf("sqlplugin: plugin %q: %w", path, err))
+		}
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, network, addr)
+		if err != nil {
+			return kill(fmt.Errorf("sqlplugin: connecting to plugin %q: %w", path, err))
+		}
+		c, err := OpenConn(ctx, &procConn{Conn: conn, exited: exited}, u)
+		if err != nil {
+			return kill(err)
+		}
+		return c, nil
+	})
+}
+
+// parseHandshake parses the handshake line written by the plugin, and
+// returns the network and the address it listens on.
+func parseHandshake(line string) (network, addr string, err error) {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != 4 {
+		return "", "", fmt.Errorf("unexpected handshake %q", line)
+	}
+	if v, err := strconv.Atoi(parts[0]); err != nil || v != ProtocolVersion {
+		return "", "", fmt.Errorf("unsupported protocol version %q, expect %d", parts[0], ProtocolVersion)
+	}
+	if parts[3] != "jsonrpc" {
+		return "", "", fmt.Errorf("unsupported rpc protocol %q", parts[3])
+	}
+	return parts[1], parts[2], nil
+}
+
+// handshake captures the first line written by the plugin to its standard
+// output, and forwards the rest of its output to the standard error of Atlas.
+type handshake struct {
+	buf  []byte
+	line chan string
+	done bool
+}
+
+func (h *handshake) Write(p []byte) (int, error) {
+	if h.done {
+		return os.Stderr.Write(p)
+	}
+	i := bytes.IndexByte(p, '\n')
+	if i == -1 {
+		h.buf = append(h.buf, p...)
+		return len(p), nil
+	}
+	h.done = true
+	h.line <- string(append(h.buf, p[:i]...))
+	if _, err := os.Stderr.Write(p[i+1:]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// OpenConn opens the driver of a plugin that is served on the given
+// connection, and returns a client that uses it.
+func OpenConn(ctx context.Context, conn io.ReadWriteCloser, u *url.URL) (*sqlclient.Client, error) {
+	rc := jsonrpc.NewClient(conn)
+	var reply OpenReply
+	if err := call(ctx, rc, "Open", &OpenArgs{URL: u.String()}, &reply); err != nil {
+		rc.Close()
+		return nil, err
+	}
+	// Each connection of the database handle holds a session in the plugin.
+	db := sql.OpenDB(&connector{rc: rc})
+	c := &sqlclient.Client{
+		Name:   u.Scheme,
+		DB:     db,
+		URL:    &sqlclient.URL{URL: u, DSN: u.String(), Schema: reply.Schema},
+		Driver: &Driver{ExecQuerier: db, rc: rc, caps: reply.Capabilities},
+	}
+	c.AddClosers(closerFunc(func() error {
+		// Connections are closed first, to end their sessions.
+		err := db.Close()
+		if cerr := call(context.Background(), rc, "Close", &struct{}{}, &struct{}{}); cerr != nil && err == nil {
+			err = cerr
+		}
+		if cerr := rc.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		return err
+	}))
+	return c, nil
+}
+
+// Driver implements the migrate.Driver interface by
+// calling the driver that is served by a plugin.
+type Driver struct {
+	schema.ExecQuerier
+	rc   *rpc.Client
+	caps *migrate.Capabilities
+}
+
+var (
+	_ migrate.Driver  = (*Driver)(nil)
+	_ migrate.Capable = (*Driver)(nil)
+)
+
+// Capabilities implements migrate.Capable. If the plugin
+// driver does not report its capabilities, none are reported.
+func (d *Driver) Capabilities() migrate.Capabilities {
+	if d.caps == nil {
+		return migrate.Capabilities{}
+	}
+	return *d.caps
+}
+
+// InspectSchema returns the schema description by its name.
+func (d *Driver) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	args := &InspectArgs{Schema: name}
+	if opts != nil {
+		args.Mode, args.Tables, args.Include, args.Exclude = uint(opts.Mode), opts.Tables, opts.Include, opts.Exclude
+	}
+	var reply InspectReply
+	if err := call(ctx, d.rc, "InspectSchema", args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.NotExist != "" {
+		return nil, &schema.NotExistError{Err: errors.New(reply.NotExist)}
+	}
+	if reply.Realm == nil || len(reply.Realm.Schemas) != 1 {
+		return nil, errors.New("sqlplugin: expect exactly one inspected schema")
+	}
+	return reply.Realm.Schemas[0], nil
+}
+
+// InspectRealm returns the description of the connected database.
+func (d *Driver) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	args := &InspectArgs{}
+	if opts != nil {
+		args.Mode, args.Schemas, args.Include, args.Exclude = uint(opts.Mode), opts.Schemas, opts.Include, opts.Exclude
+	}
+	var reply InspectReply
+	if err := call(ctx, d.rc, "InspectRealm", args, &reply); err != nil {
+		return nil, err
+	}
+	return (&decoder{}).realm(reply.Realm)
+}
+
+// RealmDiff returns a diff report for migrating a realm (or a database) from state "from" to state "to".
+func (d *Driver) RealmDiff(from, to *schema.Realm, opts ...schema.DiffOption) ([]schema.Change, error) {
+	return d.diff("RealmDiff", from.Schemas, to.Schemas, nil, nil, opts)
+}
+
+// SchemaDiff returns a diff report for migrating a schema from state "from" to state "to".
+func (d *Driver) SchemaDiff(from, to *schema.Schema, opts ...schema.DiffOption) ([]schema.Change, error) {
+	return d.diff("SchemaDiff", []*schema.Schema{from}, []*schema.Schema{to}, &Ref{Schema: from.Name}, &Ref{Schema: to.Name}, opts)
+}
+
+// TableDiff returns a diff report for migrating a table from state "from" to state "to".
+func (d *Driver) TableDiff(from, to *schema.Table, opts ...schema.DiffOption) ([]schema.Change, error) {
+	args := &DiffArgs{FromRef: tableRef(from), ToRef: tableRef(to)}
+	var err error
+	if args.From, err = portableTable(from); err != nil {
+		return nil, err
+	}
+	if args.To, err = portableTable(to); err != nil {
+		return nil, err
+	}
+	return d.call("TableDiff", args, opts)
+}
+
+func (d *Driver) diff(method string, from, to []*schema.Schema, fromRef, toRef *Ref, opts []schema.DiffOption) ([]schema.Change, error) {
+	args := &DiffArgs{FromRef: fromRef, ToRef: toRef}
+	var err error
+	if args.From, err = portableRealm(from); err != nil {
+		return nil, err
+	}
+	if args.To, err = portableRealm(to); err != nil {
+		return nil, err
+	}
+	return d.call(method, args, opts)
+}
+
+func (d *Driver) call(method string, args *DiffArgs, opts []schema.DiffOption) ([]schema.Change, error) {
+	o := schema.NewDiffOptions(opts...)
+	args.RenameSchemas = o.RenameSchemas
+	for _, c := range o.SkipChanges {
+		args.SkipChanges = append(args.SkipChanges, changeKind(c))
+	}
+	var reply ChangesReply
+	if err := call(context.Background(), d.rc, method, args, &reply); err != nil {
+		return nil, err
+	}
+	return (&decoder{}).changes(reply.Changes)
+}
+
+// PlanChanges returns a migration plan for the given schema changes.
+func (d *Driver) PlanChanges(ctx context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	args, err := planArgs(name, changes, opts)
+	if err != nil {
+		return nil, err
+	}
+	var reply PlanReply
+	if err := call(ctx, d.rc, "PlanChanges", args, &reply); err != nil {
+		return nil, err
+	}
+	plan := &migrate.Plan{
+		Name:          reply.Name,
+		Version:       reply.Version,
+		Reversible:    reply.Reversible,
+		Transactional: reply.Transactional,
+	}
+	for _, wc := range reply.Changes {
+		c := &migrate.Change{Cmd: wc.Cmd, Comment: wc.Comment}
+		if c.Args, err = anyValues(wc.Args); err != nil {
+			return nil, err
+		}
+		switch len(wc.Reverse) {
+		case 0:
+		case 1:
+			c.Reverse = wc.Reverse[0]
+		default:
+			c.Reverse = wc.Reverse
+		}
+		plan.Changes = append(plan.Changes, c)
+	}
+	return plan, nil
+}
+
+// ApplyChanges applies the changes on the database.
+func (d *Driver) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	var o migrate.PlanOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// Functions cannot be passed to the plugin. Hence,
+	// dry-runs are planned by the plugin and reported here.
+	if o.DryRun != nil {
+		plan, err := d.PlanChanges(ctx, "apply", changes, opts...)
+		if err != nil {
+			return err
+		}
+		o.DryRun(plan)
+		return nil
+	}
+	// Similarly, plans are approved here, and applied by the plugin
+	// only if they were not modified, as plans cannot be passed to it.
+	if o.Approver != nil {
+		plan, err := d.PlanChanges(ctx, "apply", changes, opts...)
+		if err != nil {
+			return err
+		}
+		approved, err := migrate.Approve(ctx, o.Approver, plan)
+		if err != nil {
+			return err
+		}
+		if approved != plan {
+			return errors.New("sqlplugin: applying modified plans is not supported")
+		}
+	}
+	args, err := planArgs("", changes, opts)
+	if err != nil {
+		return err
+	}
+	return call(ctx, d.rc, "ApplyChanges", args, &struct{}{})
+}
+
+func planArgs(name string, changes []schema.Change, opts []migrate.PlanOption) (*PlanArgs, error) {
+	wcs, err := EncodeChanges(changes)
+	if err != nil {
+		return nil, err
+	}
+	var o migrate.PlanOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &PlanArgs{
+		Name:            name,
+		Changes:         wcs,
+		SchemaQualifier: o.SchemaQualifier,
+		Indent:          o.Indent,
+		Mode:            uint8(o.Mode),
+	}, nil
+}
+
+// call calls the method of the plugin service and waits for its
+// completion. The plugin is not notified if the context is canceled.
+func call(ctx context.Context, rc *rpc.Client, method string, args, reply any) error {
+	c := rc.Go(ServiceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.Done:
+		var serr rpc.ServerError
+		if errors.As(c.Error, &serr) {
+			return errors.New(string(serr))
+		}
+		return c.Error
+	}
+}
+
+// procConn is the connection to a plugin process.
+type procConn struct {
+	net.Conn
+	exited <-chan error
+}
+
+// Close closes the connection, and waits for the plugin to exit.
+func (c *procConn) Close() error {
+	if err := c.Conn.Close(); err != nil {
+		return err
+	}
+	return <-c.exited
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
@@ -0,0 +1,208 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlplugin
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/rpc"
+)
+
+// The types below implement a database/sql driver that executes
+// statements and queries using the connections of the plugin. It
+// allows Atlas to use the plugin connections as a regular *sql.DB.
+// Each connection holds a session (i.e. a connection) in the plugin.
+type (
+	connector struct{ rc *rpc.Client }
+	conn      struct {
+		rc      *rpc.Client
+		session int
+		inTx    bool // Transaction was started.
+	}
+	stmt struct {
+		c     *conn
+		query string
+	}
+	tx   struct{ c *conn }
+	rows struct {
+		columns []string
+		values  [][]*Value
+	}
+	result struct{ r *ExecReply }
+)
+
+var (
+	_ driver.Connector      = (*connector)(nil)
+	_ driver.ConnBeginTx    = (*conn)(nil)
+	_ driver.ExecerContext  = (*conn)(nil)
+	_ driver.QueryerContext = (*conn)(nil)
+)
+
+// Connect implements the driver.Connector interface.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	var reply SessionArgs
+	if err := call(ctx, c.rc, "Connect", &struct{}{}, &reply); err != nil {
+		return nil, err
+	}
+	return &conn{rc: c.rc, session: reply.Session}, nil
+}
+
+// Driver implements the driver.Connector interface.
+func (c *connector) Driver() driver.Driver {
+	return c
+}
+
+// Open implements the driver.Driver interface.
+func (*connector) Open(string) (driver.Conn, error) {
+	return nil, errors.New("sqlplugin: plugin connections cannot be opened by name")
+}
+
+// Prepare implements the driver.Conn interface.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c: c, query: query}, nil
+}
+
+// Close implements the driver.Conn interface. It ends
+// the session, and releases its connection in the plugin.
+func (c *conn) Close() error {
+	return call(context.Background(), c.rc, "Disconnect", &SessionArgs{Session: c.session}, &struct{}{})
+}
+
+// Begin implements the driver.Conn interface.
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx implements the driver.ConnBeginTx interface.
+func (c *conn) BeginTx(ctx context.Context, _ driver.TxOptions) (driver.Tx, error) {
+	if c.inTx {
+		return nil, errors.New("sqlplugin: transaction already started")
+	}
+	if err := call(ctx, c.rc, "Begin", &SessionArgs{Session: c.session}, &struct{}{}); err != nil {
+		return nil, err
+	}
+	c.inTx = true
+	return &tx{c: c}, nil
+}
+
+// ExecContext implements the driver.ExecerContext interface.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	values, err := namedValues(args)
+	if err != nil {
+		return nil, err
+	}
+	var reply ExecReply
+	if err := call(ctx, c.rc, "Exec", &ExecArgs{Session: c.session, Query: query, Args: values}, &reply); err != nil {
+		return nil, err
+	}
+	return &result{r: &reply}, nil
+}
+
+// QueryContext implements the driver.QueryerContext interface.
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	values, err := namedValues(args)
+	if err != nil {
+		return nil, err
+	}
+	var reply QueryReply
+	if err := call(ctx, c.rc, "Query", &ExecArgs{Session: c.session, Query: query, Args: values}, &reply); err != nil {
+		return nil, err
+	}
+	return &rows{columns: reply.Columns, values: reply.Rows}, nil
+}
+
+// Close implements the driver.Stmt interface.
+func (*stmt) Close() error {
+	return nil
+}
+
+// NumInput implements the driver.Stmt interface.
+func (*stmt) NumInput() int {
+	return -1
+}
+
+// Exec implements the driver.Stmt interface.
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.c.ExecContext(context.Background(), s.query, toNamed(args))
+}
+
+// Query implements the driver.Stmt interface.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.c.QueryContext(context.Background(), s.query, toNamed(args))
+}
+
+// Commit implements the driver.Tx interface.
+func (t *tx) Commit() error {
+	return t.end("Commit")
+}
+
+// Rollback implements the driver.Tx interface.
+func (t *tx) Rollback() error {
+	return t.end("Rollback")
+}
+
+func (t *tx) end(method string) error {
+	t.c.inTx = false
+	return call(context.Background(), t.c.rc, method, &SessionArgs{Session: t.c.session}, &struct{}{})
+}
+
+// Columns implements the driver.Rows interface.
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+// Close implements the driver.Rows interface.
+func (r *rows) Close() error {
+	r.values = nil
+	return nil
+}
+
+// Next implements the driver.Rows interface.
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	row := r.values[0]
+	r.values = r.values[1:]
+	for i := range row {
+		v, err := row[i].Any()
+		if err != nil {
+			return err
+		}
+		dest[i] = v
+	}
+	return nil
+}
+
+// LastInsertId implements the driver.Result interface.
+func (r *result) LastInsertId() (int64, error) {
+	return r.r.LastInsertID, nil
+}
+
+// RowsAffected implements the driver.Result interface.
+func (r *result) RowsAffected() (int64, error) {
+	return r.r.RowsAffected, nil
+}
+
+func namedValues(args []driver.NamedValue) ([]*Value, error) {
+	vs := make([]any, len(args))
+	for i := range args {
+		if args[i].Name != "" {
+			return nil, errors.New("sqlplugin: named arguments are not supported")
+		}
+		vs[i] = args[i].Value
+	}
+	return newValues(vs)
+}
+
+func toNamed(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: args[i]}
+	}
+	return named
+}
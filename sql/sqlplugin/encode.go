@@ -0,0 +1,770 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlplugin
+
+import (
+	"fmt"
+	"reflect"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// schemaPkg is the path of the schema package. Types, expressions and
+// attributes that are defined outside of it (i.e. by drivers) are not
+// known to the other side of the protocol, and are not sent as is.
+var schemaPkg = reflect.TypeOf(schema.Realm{}).PkgPath()
+
+// copier copies schema objects into a realm that can be encoded by the JSON encoding
+// of the schema package, and decoded by the other side of the protocol. Driver-specific
+// types are replaced with their raw form, and driver-specific attributes are dropped.
+type copier struct {
+	r       *schema.Realm
+	tables  map[*schema.Table]*schema.Table
+	full    map[*schema.Table]bool
+	columns map[*schema.Column]*schema.Column
+	indexes map[*schema.Index]*schema.Index
+	fks     map[*schema.ForeignKey]*schema.ForeignKey
+	checks  map[*schema.Check]*schema.Check
+}
+
+func newCopier() *copier {
+	return &copier{
+		r:       schema.NewRealm(),
+		tables:  make(map[*schema.Table]*schema.Table),
+		full:    make(map[*schema.Table]bool),
+		columns: make(map[*schema.Column]*schema.Column),
+		indexes: make(map[*schema.Index]*schema.Index),
+		fks:     make(map[*schema.ForeignKey]*schema.ForeignKey),
+		checks:  make(map[*schema.Check]*schema.Check),
+	}
+}
+
+// portableRealm returns a portable copy of the schemas and their tables.
+func portableRealm(ss []*schema.Schema) (*schema.Realm, error) {
+	c := newCopier()
+	var ts []*schema.Table
+	for _, s := range ss {
+		c.schema(s)
+		ts = append(ts, s.Tables...)
+	}
+	if err := c.copyTables(ts...); err != nil {
+		return nil, err
+	}
+	return c.r, nil
+}
+
+// portableTable returns a portable copy of the table. Other tables
+// of its schema are not copied, unless they are referenced by it.
+func portableTable(t *schema.Table) (*schema.Realm, error) {
+	c := newCopier()
+	if err := c.copyTables(t); err != nil {
+		return nil, err
+	}
+	return c.r, nil
+}
+
+// schema returns the copy of the schema, or creates it.
+func (c *copier) schema(s *schema.Schema) *schema.Schema {
+	var name string
+	if s != nil {
+		name = s.Name
+	}
+	if cs, ok := c.r.Schema(name); ok {
+		return cs
+	}
+	cs := schema.New(name)
+	if s != nil {
+		cs.Attrs = portableAttrs(s.Attrs)
+	}
+	c.r.AddSchemas(cs)
+	return cs
+}
+
+// table returns the copy of the table, or creates an empty one.
+func (c *copier) table(t *schema.Table) *schema.Table {
+	if ct, ok := c.tables[t]; ok {
+		return ct
+	}
+	s := c.schema(t.Schema)
+	ct, ok := s.Table(t.Name)
+	if !ok {
+		ct = schema.NewTable(t.Name)
+		s.AddTables(ct)
+	}
+	c.tables[t] = ct
+	return ct
+}
+
+// copyTables copies the tables with all their resources. Columns are
+// copied first, to keep their order in tables that are referenced by
+// foreign keys of the other tables.
+func (c *copier) copyTables(ts ...*schema.Table) error {
+	var copied []*schema.Table
+	for _, t := range ts {
+		if c.full[t] {
+			continue
+		}
+		c.full[t] = true
+		copied = append(copied, t)
+		for _, col := range t.Columns {
+			if _, err := c.column(t, col); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range copied {
+		ct := c.table(t)
+		if t.PrimaryKey != nil {
+			if _, err := c.index(t, t.PrimaryKey, true); err != nil {
+				return err
+			}
+		}
+		for _, idx := range t.Indexes {
+			if _, err := c.index(t, idx, false); err != nil {
+				return err
+			}
+		}
+		for _, fk := range t.ForeignKeys {
+			if _, err := c.foreignKey(t, fk); err != nil {
+				return err
+			}
+		}
+		for _, a := range t.Attrs {
+			if ck, ok := a.(*schema.Check); ok {
+				c.check(t, ck)
+			} else if isPortable(a) {
+				ct.Attrs = append(ct.Attrs, a)
+			}
+		}
+	}
+	return nil
+}
+
+// column returns the copy of the table column, or creates it.
+func (c *copier) column(t *schema.Table, col *schema.Column) (*schema.Column, error) {
+	if cc, ok := c.columns[col]; ok {
+		return cc, nil
+	}
+	cc := &schema.Column{Name: col.Name, Attrs: portableAttrs(col.Attrs)}
+	if col.Default != nil && isPortable(col.Default) {
+		cc.Default = col.Default
+	}
+	if ct := col.Type; ct != nil {
+		cc.Type = &schema.ColumnType{Type: ct.Type, Raw: ct.Raw, Null: ct.Null}
+		if ct.Type != nil && !isPortable(ct.Type) {
+			if ct.Raw == "" {
+				return nil, fmt.Errorf("sqlplugin: column %q.%q: unsupported type %T without a raw representation", t.Name, col.Name, ct.Type)
+			}
+			cc.Type.Type = &schema.UnsupportedType{T: ct.Raw}
+		}
+	}
+	c.table(t).AddColumns(cc)
+	c.columns[col] = cc
+	return cc, nil
+}
+
+// index returns the copy of the table index (or primary key), or creates it.
+func (c *copier) index(t *schema.Table, idx *schema.Index, pk bool) (*schema.Index, error) {
+	if ci, ok := c.indexes[idx]; ok {
+		return ci, nil
+	}
+	ct := c.table(t)
+	ci := &schema.Index{Name: idx.Name, Unique: idx.Unique, Table: ct, Attrs: portableAttrs(idx.Attrs)}
+	for _, p := range idx.Parts {
+		cp := &schema.IndexPart{SeqNo: p.SeqNo, Desc: p.Desc, Attrs: portableAttrs(p.Attrs)}
+		if p.X != nil && isPortable(p.X) {
+			cp.X = p.X
+		}
+		if p.C != nil {
+			cc, err := c.column(t, p.C)
+			if err != nil {
+				return nil, err
+			}
+			cp.C = cc
+			cc.Indexes = append(cc.Indexes, ci)
+		}
+		ci.Parts = append(ci.Parts, cp)
+	}
+	if pk {
+		ct.PrimaryKey = ci
+	} else {
+		ct.Indexes = append(ct.Indexes, ci)
+	}
+	c.indexes[idx] = ci
+	return ci, nil
+}
+
+// foreignKey returns the copy of the table foreign key, or creates it.
+// The referenced table is created with the referenced columns, if it
+// was not copied before.
+func (c *copier) foreignKey(t *schema.Table, fk *schema.ForeignKey) (*schema.ForeignKey, error) {
+	if cf, ok := c.fks[fk]; ok {
+		return cf, nil
+	}
+	if fk.RefTable == nil {
+		return nil, fmt.Errorf("sqlplugin: missing referenced table for foreign key %q", fk.Symbol)
+	}
+	ct := c.table(t)
+	cf := &schema.ForeignKey{Symbol: fk.Symbol, Table: ct, RefTable: c.table(fk.RefTable), OnUpdate: fk.OnUpdate, OnDelete: fk.OnDelete}
+	for _, col := range fk.Columns {
+		cc, err := c.column(t, col)
+		if err != nil {
+			return nil, err
+		}
+		cf.Columns = append(cf.Columns, cc)
+		cc.ForeignKeys = append(cc.ForeignKeys, cf)
+	}
+	for _, col := range fk.RefColumns {
+		cc, err := c.column(fk.RefTable, col)
+		if err != nil {
+			return nil, err
+		}
+		cf.RefColumns = append(cf.RefColumns, cc)
+	}
+	ct.ForeignKeys = append(ct.ForeignKeys, cf)
+	c.fks[fk] = cf
+	return cf, nil
+}
+
+// check returns the copy of the table check, or creates it.
+func (c *copier) check(t *schema.Table, ck *schema.Check) *schema.Check {
+	if cc, ok := c.checks[ck]; ok {
+		return cc
+	}
+	cc := &schema.Check{Name: ck.Name, Expr: ck.Expr, Attrs: portableAttrs(ck.Attrs)}
+	ct := c.table(t)
+	ct.Attrs = append(ct.Attrs, cc)
+	c.checks[ck] = cc
+	return cc
+}
+
+// isPortable reports if the value is defined by the schema package.
+func isPortable(x any) bool {
+	t := reflect.TypeOf(x)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath() == schemaPkg
+}
+
+func portableAttrs(attrs []schema.Attr) []schema.Attr {
+	var ps []schema.Attr
+	for _, a := range attrs {
+		if _, ok := a.(*schema.Check); !ok && isPortable(a) {
+			ps = append(ps, a)
+		}
+	}
+	return ps
+}
+
+// encoder encodes schema changes to their wire representation.
+type encoder struct {
+	from, to *copier
+}
+
+// EncodeChanges returns the wire representation of the given changes.
+func EncodeChanges(changes []schema.Change) (*Changes, error) {
+	e := &encoder{from: newCopier(), to: newCopier()}
+	// Tables are copied before their resources are referenced,
+	// to keep the order of the columns of the copied tables.
+	for _, c := range changes {
+		var err error
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			e.to.schema(c.S)
+		case *schema.DropSchema:
+			e.from.schema(c.S)
+		case *schema.AddTable:
+			err = e.to.copyTables(c.T)
+		case *schema.DropTable:
+			err = e.from.copyTables(c.T)
+		case *schema.RenameTable:
+			if err = e.from.copyTables(c.From); err == nil {
+				err = e.to.copyTables(c.To)
+			}
+		case *schema.ModifyTable:
+			err = e.to.copyTables(c.T)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	list, err := e.changes(changes, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Changes{From: e.from.r, To: e.to.r, List: list}, nil
+}
+
+func (e *encoder) changes(changes []schema.Change, t *schema.Table) ([]*Change, error) {
+	list := make([]*Change, 0, len(changes))
+	for _, c := range changes {
+		wc, err := e.change(c, t)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, wc)
+	}
+	return list, nil
+}
+
+func (e *encoder) change(c schema.Change, t *schema.Table) (*Change, error) {
+	wc := &Change{Kind: changeKind(c)}
+	if _, ok := changeTypes[wc.Kind]; !ok {
+		return nil, fmt.Errorf("sqlplugin: unsupported change %T", c)
+	}
+	if tableResource(c) != (t != nil) {
+		return nil, fmt.Errorf("sqlplugin: %s change is allowed only in ModifyTable", wc.Kind)
+	}
+	var err error
+	switch c := c.(type) {
+	case *schema.AddSchema:
+		wc.To, wc.IfNotExists = &Ref{Schema: c.S.Name}, hasClause(c.Extra, &schema.IfNotExists{})
+	case *schema.DropSchema:
+		wc.From, wc.IfExists = &Ref{Schema: c.S.Name}, hasClause(c.Extra, &schema.IfExists{})
+	case *schema.AddTable:
+		wc.To, wc.IfNotExists = tableRef(c.T), hasClause(c.Extra, &schema.IfNotExists{})
+	case *schema.DropTable:
+		wc.From, wc.IfExists = tableRef(c.T), hasClause(c.Extra, &schema.IfExists{})
+	case *schema.RenameTable:
+		wc.From, wc.To = tableRef(c.From), tableRef(c.To)
+	case *schema.ModifyTable:
+		wc.To = tableRef(c.T)
+		wc.Changes, err = e.changes(c.Changes, c.T)
+	case *schema.AddColumn:
+		wc.To, err = e.column(e.to, t, c.C)
+	case *schema.DropColumn:
+		wc.From, err = e.column(e.from, t, c.C)
+	case *schema.ModifyColumn:
+		wc.ChangeKind = uint(c.Change)
+		err = refs(e, wc, func(cp *copier, col *schema.Column) (*Ref, error) { return e.column(cp, t, col) }, c.From, c.To)
+	case *schema.RenameColumn:
+		err = refs(e, wc, func(cp *copier, col *schema.Column) (*Ref, error) { return e.column(cp, t, col) }, c.From, c.To)
+	case *schema.AddIndex:
+		wc.To, err = e.index(e.to, t, c.I, false)
+	case *schema.DropIndex:
+		wc.From, err = e.index(e.from, t, c.I, false)
+	case *schema.ModifyIndex:
+		wc.ChangeKind = uint(c.Change)
+		err = refs(e, wc, func(cp *copier, idx *schema.Index) (*Ref, error) { return e.index(cp, t, idx, false) }, c.From, c.To)
+	case *schema.RenameIndex:
+		err = refs(e, wc, func(cp *copier, idx *schema.Index) (*Ref, error) { return e.index(cp, t, idx, false) }, c.From, c.To)
+	case *schema.AddPrimaryKey:
+		wc.To, err = e.index(e.to, t, c.P, true)
+	case *schema.DropPrimaryKey:
+		wc.From, err = e.index(e.from, t, c.P, true)
+	case *schema.ModifyPrimaryKey:
+		wc.ChangeKind = uint(c.Change)
+		err = refs(e, wc, func(cp *copier, idx *schema.Index) (*Ref, error) { return e.index(cp, t, idx, true) }, c.From, c.To)
+	case *schema.AddForeignKey:
+		wc.To, err = e.foreignKey(e.to, t, c.F)
+	case *schema.DropForeignKey:
+		wc.From, err = e.foreignKey(e.from, t, c.F)
+	case *schema.ModifyForeignKey:
+		wc.ChangeKind = uint(c.Change)
+		err = refs(e, wc, func(cp *copier, fk *schema.ForeignKey) (*Ref, error) { return e.foreignKey(cp, t, fk) }, c.From, c.To)
+	case *schema.AddCheck:
+		wc.To = e.check(e.to, t, c.C)
+	case *schema.DropCheck:
+		wc.From = e.check(e.from, t, c.C)
+	case *schema.ModifyCheck:
+		wc.ChangeKind = uint(c.Change)
+		wc.From, wc.To = e.check(e.from, t, c.From), e.check(e.to, t, c.To)
+	case *schema.AddAttr:
+		wc.ToComment, err = commentAttr(c.A)
+	case *schema.DropAttr:
+		wc.Comment, err = commentAttr(c.A)
+	case *schema.ModifyAttr:
+		if wc.Comment, err = commentAttr(c.From); err == nil {
+			wc.ToComment, err = commentAttr(c.To)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return wc, nil
+}
+
+// refs sets the From and To references of a modify or a rename change.
+func refs[T any](e *encoder, wc *Change, ref func(*copier, T) (*Ref, error), from, to T) (err error) {
+	if wc.From, err = ref(e.from, from); err != nil {
+		return err
+	}
+	wc.To, err = ref(e.to, to)
+	return err
+}
+
+func (e *encoder) column(cp *copier, t *schema.Table, col *schema.Column) (*Ref, error) {
+	cc, err := cp.column(t, col)
+	if err != nil {
+		return nil, err
+	}
+	return resourceRef(t, cp.table(t).Columns, cc), nil
+}
+
+func (e *encoder) index(cp *copier, t *schema.Table, idx *schema.Index, pk bool) (*Ref, error) {
+	ci, err := cp.index(t, idx, pk)
+	if err != nil {
+		return nil, err
+	}
+	if pk {
+		return tableRef(t), nil
+	}
+	return resourceRef(t, cp.table(t).Indexes, ci), nil
+}
+
+func (e *encoder) foreignKey(cp *copier, t *schema.Table, fk *schema.ForeignKey) (*Ref, error) {
+	cf, err := cp.foreignKey(t, fk)
+	if err != nil {
+		return nil, err
+	}
+	return resourceRef(t, cp.table(t).ForeignKeys, cf), nil
+}
+
+func (e *encoder) check(cp *copier, t *schema.Table, ck *schema.Check) *Ref {
+	return resourceRef(t, cp.table(t).Attrs, cp.check(t, ck))
+}
+
+func tableRef(t *schema.Table) *Ref {
+	r := &Ref{Table: t.Name}
+	if t.Schema != nil {
+		r.Schema = t.Schema.Name
+	}
+	return r
+}
+
+// resourceRef returns a reference to the resource x of the table,
+// where s is the list of the copied table resources it belongs to.
+func resourceRef[T any](t *schema.Table, s []T, x any) *Ref {
+	r := tableRef(t)
+	for i := range s {
+		if any(s[i]) == x {
+			r.Pos = i
+		}
+	}
+	return r
+}
+
+// tableResource reports if the change is a change of a table
+// resource. These changes are allowed only in ModifyTable.
+func tableResource(c schema.Change) bool {
+	switch c.(type) {
+	case *schema.AddSchema, *schema.DropSchema, *schema.AddTable, *schema.DropTable, *schema.RenameTable, *schema.ModifyTable:
+		return false
+	}
+	return true
+}
+
+// changeKind returns the kind of the change, that is the name of its type.
+func changeKind(c schema.Change) string {
+	return reflect.Indirect(reflect.ValueOf(c)).Type().Name()
+}
+
+// changeTypes maps the supported change kinds to their types.
+var changeTypes = map[string]func() schema.Change{
+	"AddSchema":        func() schema.Change { return &schema.AddSchema{} },
+	"DropSchema":       func() schema.Change { return &schema.DropSchema{} },
+	"AddTable":         func() schema.Change { return &schema.AddTable{} },
+	"DropTable":        func() schema.Change { return &schema.DropTable{} },
+	"RenameTable":      func() schema.Change { return &schema.RenameTable{} },
+	"ModifyTable":      func() schema.Change { return &schema.ModifyTable{} },
+	"AddColumn":        func() schema.Change { return &schema.AddColumn{} },
+	"DropColumn":       func() schema.Change { return &schema.DropColumn{} },
+	"ModifyColumn":     func() schema.Change { return &schema.ModifyColumn{} },
+	"RenameColumn":     func() schema.Change { return &schema.RenameColumn{} },
+	"AddIndex":         func() schema.Change { return &schema.AddIndex{} },
+	"DropIndex":        func() schema.Change { return &schema.DropIndex{} },
+	"ModifyIndex":      func() schema.Change { return &schema.ModifyIndex{} },
+	"RenameIndex":      func() schema.Change { return &schema.RenameIndex{} },
+	"AddPrimaryKey":    func() schema.Change { return &schema.AddPrimaryKey{} },
+	"DropPrimaryKey":   func() schema.Change { return &schema.DropPrimaryKey{} },
+	"ModifyPrimaryKey": func() schema.Change { return &schema.ModifyPrimaryKey{} },
+	"AddForeignKey":    func() schema.Change { return &schema.AddForeignKey{} },
+	"DropForeignKey":   func() schema.Change { return &schema.DropForeignKey{} },
+	"ModifyForeignKey": func() schema.Change { return &schema.ModifyForeignKey{} },
+	"AddCheck":         func() schema.Change { return &schema.AddCheck{} },
+	"DropCheck":        func() schema.Change { return &schema.DropCheck{} },
+	"ModifyCheck":      func() schema.Change { return &schema.ModifyCheck{} },
+	"AddAttr":          func() schema.Change { return &schema.AddAttr{} },
+	"DropAttr":         func() schema.Change { return &schema.DropAttr{} },
+	"ModifyAttr":       func() schema.Change { return &schema.ModifyAttr{} },
+}
+
+// commentAttr returns the text of the attribute, if it is a comment. Other
+// attributes are driver-specific, and are not supported by the protocol.
+func commentAttr(a schema.Attr) (*string, error) {
+	c, ok := a.(*schema.Comment)
+	if !ok {
+		return nil, fmt.Errorf("sqlplugin: unsupported attribute change %T", a)
+	}
+	return &c.Text, nil
+}
+
+func hasClause(clauses []schema.Clause, c schema.Clause) bool {
+	for _, x := range clauses {
+		if reflect.TypeOf(x) == reflect.TypeOf(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// decoder decodes the wire representation of schema objects and changes.
+type decoder struct {
+	parseType func(string) (schema.Type, error)
+}
+
+// realm prepares a decoded realm for use. Column types are parsed
+// from their raw form, if the decoder was configured to do so.
+func (d *decoder) realm(r *schema.Realm) (*schema.Realm, error) {
+	if r == nil {
+		return schema.NewRealm(), nil
+	}
+	if d.parseType == nil {
+		return r, nil
+	}
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			for _, c := range t.Columns {
+				if c.Type == nil || c.Type.Raw == "" {
+					continue
+				}
+				typ, err := d.parseType(c.Type.Raw)
+				if err != nil {
+					return nil, fmt.Errorf("sqlplugin: parse type of column %q.%q: %w", t.Name, c.Name, err)
+				}
+				c.Type.Type = typ
+			}
+		}
+	}
+	return r, nil
+}
+
+// changes decodes the changes from their wire representation.
+func (d *decoder) changes(wcs *Changes) ([]schema.Change, error) {
+	if wcs == nil {
+		return nil, nil
+	}
+	from, err := d.realm(wcs.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := d.realm(wcs.To)
+	if err != nil {
+		return nil, err
+	}
+	return (&changesDecoder{from: from, to: to}).changes(wcs.List, false)
+}
+
+// changesDecoder resolves the references of changes to their realms.
+type changesDecoder struct {
+	from, to *schema.Realm
+}
+
+func (d *changesDecoder) changes(wcs []*Change, inTable bool) ([]schema.Change, error) {
+	changes := make([]schema.Change, 0, len(wcs))
+	for _, wc := range wcs {
+		c, err := d.change(wc, inTable)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+func (d *changesDecoder) change(wc *Change, inTable bool) (schema.Change, error) {
+	f, ok := changeTypes[wc.Kind]
+	if !ok {
+		return nil, fmt.Errorf("sqlplugin: unsupported change kind %q", wc.Kind)
+	}
+	var (
+		err    error
+		change = f()
+	)
+	if tableResource(change) != inTable {
+		return nil, fmt.Errorf("sqlplugin: %s change is allowed only in ModifyTable", wc.Kind)
+	}
+	switch c := change.(type) {
+	case *schema.AddSchema:
+		if c.S, err = schemaOf(d.to, wc.To); err == nil && wc.IfNotExists {
+			c.Extra = append(c.Extra, &schema.IfNotExists{})
+		}
+	case *schema.DropSchema:
+		if c.S, err = schemaOf(d.from, wc.From); err == nil && wc.IfExists {
+			c.Extra = append(c.Extra, &schema.IfExists{})
+		}
+	case *schema.AddTable:
+		if c.T, err = tableOf(d.to, wc.To); err == nil && wc.IfNotExists {
+			c.Extra = append(c.Extra, &schema.IfNotExists{})
+		}
+	case *schema.DropTable:
+		if c.T, err = tableOf(d.from, wc.From); err == nil && wc.IfExists {
+			c.Extra = append(c.Extra, &schema.IfExists{})
+		}
+	case *schema.RenameTable:
+		if c.From, err = tableOf(d.from, wc.From); err == nil {
+			c.To, err = tableOf(d.to, wc.To)
+		}
+	case *schema.ModifyTable:
+		if c.T, err = tableOf(d.to, wc.To); err == nil {
+			c.Changes, err = d.changes(wc.Changes, true)
+		}
+	case *schema.AddColumn:
+		c.C, err = columnOf(d.to, wc.To)
+	case *schema.DropColumn:
+		c.C, err = columnOf(d.from, wc.From)
+	case *schema.ModifyColumn:
+		c.Change = schema.ChangeKind(wc.ChangeKind)
+		if c.From, err = columnOf(d.from, wc.From); err == nil {
+			c.To, err = columnOf(d.to, wc.To)
+		}
+	case *schema.RenameColumn:
+		if c.From, err = columnOf(d.from, wc.From); err == nil {
+			c.To, err = columnOf(d.to, wc.To)
+		}
+	case *schema.AddIndex:
+		c.I, err = indexOf(d.to, wc.To)
+	case *schema.DropIndex:
+		c.I, err = indexOf(d.from, wc.From)
+	case *schema.ModifyIndex:
+		c.Change = schema.ChangeKind(wc.ChangeKind)
+		if c.From, err = indexOf(d.from, wc.From); err == nil {
+			c.To, err = indexOf(d.to, wc.To)
+		}
+	case *schema.RenameIndex:
+		if c.From, err = indexOf(d.from, wc.From); err == nil {
+			c.To, err = indexOf(d.to, wc.To)
+		}
+	case *schema.AddPrimaryKey:
+		c.P, err = primaryKeyOf(d.to, wc.To)
+	case *schema.DropPrimaryKey:
+		c.P, err = primaryKeyOf(d.from, wc.From)
+	case *schema.ModifyPrimaryKey:
+		c.Change = schema.ChangeKind(wc.ChangeKind)
+		if c.From, err = primaryKeyOf(d.from, wc.From); err == nil {
+			c.To, err = primaryKeyOf(d.to, wc.To)
+		}
+	case *schema.AddForeignKey:
+		c.F, err = foreignKeyOf(d.to, wc.To)
+	case *schema.DropForeignKey:
+		c.F, err = foreignKeyOf(d.from, wc.From)
+	case *schema.ModifyForeignKey:
+		c.Change = schema.ChangeKind(wc.ChangeKind)
+		if c.From, err = foreignKeyOf(d.from, wc.From); err == nil {
+			c.To, err = foreignKeyOf(d.to, wc.To)
+		}
+	case *schema.AddCheck:
+		c.C, err = checkOf(d.to, wc.To)
+	case *schema.DropCheck:
+		c.C, err = checkOf(d.from, wc.From)
+	case *schema.ModifyCheck:
+		c.Change = schema.ChangeKind(wc.ChangeKind)
+		if c.From, err = checkOf(d.from, wc.From); err == nil {
+			c.To, err = checkOf(d.to, wc.To)
+		}
+	case *schema.AddAttr:
+		c.A, err = comment(wc.ToComment)
+	case *schema.DropAttr:
+		c.A, err = comment(wc.Comment)
+	case *schema.ModifyAttr:
+		if c.From, err = comment(wc.Comment); err == nil {
+			c.To, err = comment(wc.ToComment)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlplugin: decode %s change: %w", wc.Kind, err)
+	}
+	return change, nil
+}
+
+func schemaOf(r *schema.Realm, ref *Ref) (*schema.Schema, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("missing reference")
+	}
+	s, ok := r.Schema(ref.Schema)
+	if !ok {
+		return nil, fmt.Errorf("schema %q was not found", ref.Schema)
+	}
+	return s, nil
+}
+
+func tableOf(r *schema.Realm, ref *Ref) (*schema.Table, error) {
+	s, err := schemaOf(r, ref)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := s.Table(ref.Table)
+	if !ok {
+		return nil, fmt.Errorf("table %q was not found", ref.Table)
+	}
+	return t, nil
+}
+
+func columnOf(r *schema.Realm, ref *Ref) (*schema.Column, error) {
+	t, err := tableOf(r, ref)
+	if err != nil {
+		return nil, err
+	}
+	return at(t.Columns, ref.Pos)
+}
+
+func indexOf(r *schema.Realm, ref *Ref) (*schema.Index, error) {
+	t, err := tableOf(r, ref)
+	if err != nil {
+		return nil, err
+	}
+	return at(t.Indexes, ref.Pos)
+}
+
+func primaryKeyOf(r *schema.Realm, ref *Ref) (*schema.Index, error) {
+	t, err := tableOf(r, ref)
+	if err != nil {
+		return nil, err
+	}
+	if t.PrimaryKey == nil {
+		return nil, fmt.Errorf("table %q has no primary key", t.Name)
+	}
+	return t.PrimaryKey, nil
+}
+
+func foreignKeyOf(r *schema.Realm, ref *Ref) (*schema.ForeignKey, error) {
+	t, err := tableOf(r, ref)
+	if err != nil {
+		return nil, err
+	}
+	return at(t.ForeignKeys, ref.Pos)
+}
+
+func checkOf(r *schema.Realm, ref *Ref) (*schema.Check, error) {
+	t, err := tableOf(r, ref)
+	if err != nil {
+		return nil, err
+	}
+	a, err := at(t.Attrs, ref.Pos)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := a.(*schema.Check)
+	if !ok {
+		return nil, fmt.Errorf("attribute %d of table %q is not a check", ref.Pos, t.Name)
+	}
+	return c, nil
+}
+
+func at[T any](s []T, i int) (T, error) {
+	if i < 0 || i >= len(s) {
+		var zero T
+		return zero, fmt.Errorf("position %d is out of range", i)
+	}
+	return s[i], nil
+}
+
+func comment(text *string) (*schema.Comment, error) {
+	if text == nil {
+		return nil, fmt.Errorf("missing comment")
+	}
+	return &schema.Comment{Text: *text}, nil
+}
@@ -0,0 +1,489 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlplugin_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlplugin"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlugin(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv := &mockDriver{ExecQuerier: db}
+	c := openPlugin(t, sqlplugin.Config{
+		Opener: sqlclient.OpenerFunc(func(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+			require.Equal(t, "mock://localhost/test", u.String())
+			return &sqlclient.Client{DB: db, Driver: drv, URL: &sqlclient.URL{URL: u, Schema: "test"}}, nil
+		}),
+	})
+	require.Equal(t, "test", c.URL.Schema)
+	caps, ok := migrate.DriverCapabilities(c.Driver)
+	require.True(t, ok)
+	require.Equal(t, migrate.Capabilities{Check: true, DropColumn: true}, caps)
+	ctx := context.Background()
+
+	// Inspection.
+	drv.realm = schema.NewRealm(users())
+	s, err := c.InspectSchema(ctx, "test", &schema.InspectOptions{Tables: []string{"users"}})
+	require.NoError(t, err)
+	require.Equal(t, "test", drv.inspected)
+	require.Equal(t, "test", s.Name)
+	require.Len(t, s.Tables, 2)
+	tu, ok := s.Table("users")
+	require.True(t, ok)
+	require.Equal(t, tu.Columns[0], tu.PrimaryKey.Parts[0].C)
+	require.Equal(t, &schema.ColumnType{Type: &schema.StringType{T: "varchar", Size: 255}, Raw: "varchar(255)", Null: true}, tu.Columns[1].Type)
+	tp, ok := s.Table("pets")
+	require.True(t, ok)
+	require.Equal(t, tu, tp.ForeignKeys[0].RefTable, "foreign keys are linked")
+	require.Equal(t, tu.Columns[0], tp.ForeignKeys[0].RefColumns[0])
+
+	drv.realm = nil
+	_, err = c.InspectSchema(ctx, "unknown", nil)
+	require.True(t, schema.IsNotExistError(err))
+
+	// Diffing.
+	drv.changes = []schema.Change{
+		&schema.ModifyTable{
+			T: tu,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: schema.NewIntColumn("age", "int")},
+				&schema.ModifyColumn{From: tu.Columns[1], To: schema.NewStringColumn("name", "text"), Change: schema.ChangeType | schema.ChangeNull},
+				&schema.AddIndex{I: schema.NewUniqueIndex("name").AddColumns(tu.Columns[1])},
+				&schema.ModifyAttr{From: &schema.Comment{Text: "a"}, To: &schema.Comment{Text: "b"}},
+			},
+		},
+	}
+	changes, err := c.SchemaDiff(s, s, schema.DiffSkipChanges(&schema.DropTable{}))
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.DropTable{}}, drv.skipped)
+	require.Len(t, changes, 1)
+	modify := changes[0].(*schema.ModifyTable)
+	require.Equal(t, "users", modify.T.Name)
+	require.Len(t, modify.Changes, 4)
+	require.Equal(t, "age", modify.Changes[0].(*schema.AddColumn).C.Name)
+	mc := modify.Changes[1].(*schema.ModifyColumn)
+	require.Equal(t, schema.ChangeType|schema.ChangeNull, mc.Change)
+	require.Equal(t, "text", mc.To.Type.Type.(*schema.StringType).T)
+	require.True(t, modify.Changes[2].(*schema.AddIndex).I.Unique)
+	require.Equal(t, "b", modify.Changes[3].(*schema.ModifyAttr).To.(*schema.Comment).Text)
+
+	_, err = c.TableDiff(tu, tu)
+	require.NoError(t, err)
+	drv.err = errors.New("diff error")
+	_, err = c.RealmDiff(schema.NewRealm(s), schema.NewRealm(s))
+	require.EqualError(t, err, "diff error")
+	drv.err = nil
+
+	// Planning.
+	plan, err := c.PlanChanges(ctx, "add_age", changes, func(o *migrate.PlanOptions) { o.Indent = "  " })
+	require.NoError(t, err)
+	require.Equal(t, "  ", drv.opts.Indent)
+	require.Equal(t, "add_age", plan.Name)
+	require.True(t, plan.Reversible)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, "ALTER TABLE users ADD COLUMN age int", plan.Changes[0].Cmd)
+	require.Equal(t, "ALTER TABLE users DROP COLUMN age", plan.Changes[0].Reverse)
+	require.Equal(t, []any{int64(1), "a"}, plan.Changes[1].Args)
+	require.Equal(t, []string{"a", "b"}, plan.Changes[1].Reverse)
+	require.NoError(t, c.ApplyChanges(ctx, changes, migrate.ApplyDryRun(func(p *migrate.Plan) { plan = p })))
+	require.Empty(t, drv.applied)
+	require.Len(t, plan.Changes, 2)
+	require.NoError(t, c.ApplyChanges(ctx, changes))
+	require.Len(t, drv.applied, 1)
+
+	// Plans are approved by the client.
+	approve := func(d migrate.Decision) migrate.PlanOption {
+		return migrate.ApplyWithApprover(migrate.ApproverFunc(func(_ context.Context, p *migrate.Plan) (*migrate.Approval, error) {
+			return &migrate.Approval{Decision: d, Plan: &migrate.Plan{}}, nil
+		}))
+	}
+	drv.applied = nil
+	require.EqualError(t, c.ApplyChanges(ctx, changes, approve(migrate.PlanDenied)), `sql/migrate: plan "apply" was denied`)
+	require.EqualError(t, c.ApplyChanges(ctx, changes, approve(migrate.PlanModified)), "sqlplugin: applying modified plans is not supported")
+	require.Empty(t, drv.applied)
+	require.NoError(t, c.ApplyChanges(ctx, changes, approve(migrate.PlanApproved)))
+	require.Len(t, drv.applied, 1)
+
+	// Executing statements and queries.
+	m.ExpectExec("INSERT INTO users").WithArgs(int64(1), "a8m").WillReturnResult(sqlmock.NewResult(1, 1))
+	res, err := c.ExecContext(ctx, "INSERT INTO users VALUES (?, ?)", 1, "a8m")
+	require.NoError(t, err)
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	m.ExpectQuery("SELECT id, name FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a8m").AddRow(2, nil))
+	rows, err := c.QueryContext(ctx, "SELECT id, name FROM users")
+	require.NoError(t, err)
+	var (
+		ids   []int
+		names []sql.NullString
+	)
+	for rows.Next() {
+		var (
+			id   int
+			name sql.NullString
+		)
+		require.NoError(t, rows.Scan(&id, &name))
+		ids, names = append(ids, id), append(names, name)
+	}
+	require.NoError(t, rows.Close())
+	require.Equal(t, []int{1, 2}, ids)
+	require.Equal(t, []sql.NullString{{String: "a8m", Valid: true}, {}}, names)
+
+	m.ExpectBegin()
+	m.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 2))
+	m.ExpectRollback()
+	tx, err := c.DB.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec("DELETE FROM users")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	m.ExpectClose()
+	require.NoError(t, c.Close())
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestPlugin_Changes(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv := &mockDriver{ExecQuerier: db}
+	c := openPlugin(t, sqlplugin.Config{
+		Opener: sqlclient.OpenerFunc(func(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+			return &sqlclient.Client{DB: db, Driver: drv, URL: &sqlclient.URL{URL: u}}, nil
+		}),
+		ParseType: func(s string) (schema.Type, error) {
+			return &schema.SpatialType{T: s}, nil
+		},
+	})
+	s := users()
+	tu, tp := s.Tables[0], s.Tables[1]
+	tu.AddChecks(schema.NewCheck().SetName("positive").SetExpr("id > 0"))
+	tp.AddColumns(&schema.Column{Name: "loc", Type: &schema.ColumnType{Type: &unknownType{}, Raw: "point"}})
+	nick, age := schema.NewStringColumn("nick", "text"), schema.NewIntColumn("age", "int")
+	changes := []schema.Change{
+		&schema.AddSchema{S: schema.New("other"), Extra: []schema.Clause{&schema.IfNotExists{}}},
+		&schema.AddTable{T: tp},
+		&schema.ModifyTable{
+			T: tu,
+			Changes: []schema.Change{
+				&schema.DropColumn{C: age},
+				&schema.RenameColumn{From: nick, To: tu.Columns[1]},
+				&schema.DropIndex{I: schema.NewIndex("age").AddColumns(age)},
+				&schema.AddCheck{C: tu.Attrs[1].(*schema.Check)},
+			},
+		},
+		&schema.DropTable{T: schema.NewTable("logs").SetSchema(schema.New("other")).AddColumns(schema.NewIntColumn("id", "int")), Extra: []schema.Clause{&schema.IfExists{}}},
+	}
+	require.NoError(t, c.ApplyChanges(context.Background(), changes))
+	require.Len(t, drv.applied, 4)
+	add := drv.applied[0].(*schema.AddSchema)
+	require.Equal(t, "other", add.S.Name)
+	require.Equal(t, []schema.Clause{&schema.IfNotExists{}}, add.Extra)
+
+	// Tables are copied with their resources, and types are parsed by the plugin.
+	pets := drv.applied[1].(*schema.AddTable).T
+	require.Equal(t, "pets", pets.Name)
+	require.Equal(t, "test", pets.Schema.Name)
+	require.Equal(t, &schema.ColumnType{Type: &schema.SpatialType{T: "point"}, Raw: "point"}, pets.Columns[1].Type)
+	fk := pets.ForeignKeys[0]
+	require.Equal(t, "users", fk.RefTable.Name)
+	require.Equal(t, "id", fk.RefColumns[0].Name)
+	require.Equal(t, schema.Cascade, fk.OnDelete)
+
+	// Changes reference the objects of the modified table,
+	// and the removed objects are referenced by their copies.
+	modify := drv.applied[2].(*schema.ModifyTable)
+	require.Equal(t, []string{"id", "name"}, []string{modify.T.Columns[0].Name, modify.T.Columns[1].Name})
+	require.Equal(t, modify.T.Columns[0], modify.T.PrimaryKey.Parts[0].C)
+	require.Equal(t, "a", modify.T.Attrs[0].(*schema.Comment).Text)
+	require.Equal(t, "age", modify.Changes[0].(*schema.DropColumn).C.Name)
+	rename := modify.Changes[1].(*schema.RenameColumn)
+	require.Equal(t, "nick", rename.From.Name)
+	require.True(t, rename.To == modify.T.Columns[1])
+	drop := modify.Changes[2].(*schema.DropIndex)
+	require.Equal(t, "age", drop.I.Name)
+	require.Equal(t, "age", drop.I.Parts[0].C.Name)
+	require.True(t, modify.Changes[3].(*schema.AddCheck).C == modify.T.Attrs[1])
+	require.Equal(t, "id > 0", modify.Changes[3].(*schema.AddCheck).C.Expr)
+
+	dropT := drv.applied[3].(*schema.DropTable)
+	require.Equal(t, "logs", dropT.T.Name)
+	require.Equal(t, "other", dropT.T.Schema.Name)
+	require.Equal(t, []schema.Clause{&schema.IfExists{}}, dropT.Extra)
+
+	err = c.ApplyChanges(context.Background(), []schema.Change{&schema.ModifyTable{T: tu, Changes: []schema.Change{&schema.AddAttr{A: &schema.Charset{V: "utf8"}}}}})
+	require.EqualError(t, err, "sqlplugin: unsupported attribute change *schema.Charset")
+	err = c.ApplyChanges(context.Background(), []schema.Change{&schema.AddColumn{C: age}})
+	require.EqualError(t, err, "sqlplugin: AddColumn change is allowed only in ModifyTable")
+	err = c.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t").AddColumns(&schema.Column{Name: "c", Type: &schema.ColumnType{Type: &unknownType{}}})},
+	})
+	require.EqualError(t, err, `sqlplugin: column "t"."c": unsupported type *sqlplugin_test.unknownType without a raw representation`)
+
+	m.ExpectClose()
+	require.NoError(t, c.Close())
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestPlugin_Sessions(t *testing.T) {
+	d := &sessionDriver{}
+	db := sql.OpenDB(d)
+	c := openPlugin(t, sqlplugin.Config{
+		Opener: sqlclient.OpenerFunc(func(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+			return &sqlclient.Client{DB: db, Driver: &mockDriver{ExecQuerier: db}, URL: &sqlclient.URL{URL: u}}, nil
+		}),
+	})
+	ctx := context.Background()
+	c1, err := c.DB.Conn(ctx)
+	require.NoError(t, err)
+	c2, err := c.DB.Conn(ctx)
+	require.NoError(t, err)
+	for _, e := range []struct {
+		c *sql.Conn
+		q string
+	}{
+		{c1, "SET a = 1"}, {c2, "SET a = 2"}, {c1, "SET b = 1"},
+	} {
+		_, err := e.c.ExecContext(ctx, e.q)
+		require.NoError(t, err)
+	}
+	tx, err := c1.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "UPDATE t SET a = 1")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	// Statements are executed on the connections
+	// that were held for the sessions.
+	require.Equal(t, []string{"1: SET a = 1", "2: SET a = 2", "1: SET b = 1", "1: BEGIN", "1: UPDATE t SET a = 1", "1: COMMIT"}, d.log)
+	require.NoError(t, c1.Close())
+	require.NoError(t, c2.Close())
+	require.NoError(t, c.Close())
+	require.Equal(t, 2, d.closed)
+}
+
+func TestOpener(t *testing.T) {
+	t.Setenv("SQLPLUGIN_TEST_HELPER", "1")
+	ctx := context.Background()
+	c, err := sqlplugin.Opener(os.Args[0], "-test.run=^TestHelperPlugin$").Open(ctx, &url.URL{Scheme: "mock", Host: "localhost", Path: "/test"})
+	require.NoError(t, err)
+	require.Equal(t, "test", c.URL.Schema)
+	r, err := c.InspectRealm(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 1)
+	require.Len(t, r.Schemas[0].Tables, 2)
+	require.NoError(t, c.Close())
+
+	t.Setenv(sqlplugin.MagicCookieKey, "")
+	require.EqualError(t, sqlplugin.Serve(sqlplugin.Config{}), "sqlplugin: missing or invalid magic cookie")
+}
+
+// TestHelperPlugin is not a real test. It is executed
+// by TestOpener in a child process to serve the plugin.
+func TestHelperPlugin(*testing.T) {
+	if os.Getenv("SQLPLUGIN_TEST_HELPER") != "1" {
+		return
+	}
+	db, m, err := sqlmock.New()
+	if err == nil {
+		m.ExpectClose()
+		err = sqlplugin.Serve(sqlplugin.Config{
+			Opener: sqlclient.OpenerFunc(func(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+				drv := &mockDriver{ExecQuerier: db, realm: schema.NewRealm(users())}
+				return &sqlclient.Client{DB: db, Driver: drv, URL: &sqlclient.URL{URL: u, Schema: "test"}}, nil
+			}),
+		})
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestValue(t *testing.T) {
+	for _, v := range []any{nil, int64(1), 1.5, true, "a8m", []byte("a8m")} {
+		w, err := sqlplugin.NewValue(v)
+		require.NoError(t, err)
+		got, err := w.Any()
+		require.NoError(t, err)
+		require.Equal(t, v, got)
+	}
+	_, err := sqlplugin.NewValue(struct{}{})
+	require.EqualError(t, err, "sqlplugin: unsupported value type struct {}")
+}
+
+func openPlugin(t *testing.T, cfg sqlplugin.Config) *sqlclient.Client {
+	host, plugin := net.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- sqlplugin.ServeConn(cfg, plugin) }()
+	c, err := sqlplugin.OpenConn(context.Background(), host, &url.URL{Scheme: "mock", Host: "localhost", Path: "/test"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		host.Close()
+		require.NoError(t, <-done)
+	})
+	return c
+}
+
+func users() *schema.Schema {
+	id := schema.NewIntColumn("id", "int")
+	users := schema.NewTable("users").
+		AddColumns(id, schema.NewNullStringColumn("name", "varchar", schema.StringSize(255))).
+		SetPrimaryKey(schema.NewPrimaryKey(id)).
+		SetComment("a")
+	users.Columns[1].Type.Raw = "varchar(255)"
+	owner := schema.NewIntColumn("owner_id", "int")
+	pets := schema.NewTable("pets").
+		AddColumns(owner).
+		AddForeignKeys(schema.NewForeignKey("owner").AddColumns(owner).SetRefTable(users).AddRefColumns(id).SetOnDelete(schema.Cascade))
+	return schema.New("test").AddTables(users, pets)
+}
+
+type (
+	unknownType struct{ schema.Type }
+
+	// sessionDriver is a database/sql driver that logs
+	// the statements executed on each of its connections.
+	sessionDriver struct {
+		mu     sync.Mutex
+		conns  int
+		closed int
+		log    []string
+	}
+	sessionConn struct {
+		d  *sessionDriver
+		id int
+	}
+
+	mockDriver struct {
+		schema.ExecQuerier
+		realm     *schema.Realm
+		inspected string
+		changes   []schema.Change
+		skipped   []schema.Change
+		opts      migrate.PlanOptions
+		applied   []schema.Change
+		err       error
+	}
+)
+
+func (d *mockDriver) Capabilities() migrate.Capabilities {
+	return migrate.Capabilities{Check: true, DropColumn: true}
+}
+
+func (d *mockDriver) InspectSchema(_ context.Context, name string, _ *schema.InspectOptions) (*schema.Schema, error) {
+	d.inspected = name
+	if d.realm == nil {
+		return nil, &schema.NotExistError{Err: errors.New("schema does not exist")}
+	}
+	return d.realm.Schemas[0], nil
+}
+
+func (d *mockDriver) InspectRealm(context.Context, *schema.InspectRealmOption) (*schema.Realm, error) {
+	return d.realm, nil
+}
+
+func (d *mockDriver) RealmDiff(_, _ *schema.Realm, opts ...schema.DiffOption) ([]schema.Change, error) {
+	return d.diff(opts)
+}
+
+func (d *mockDriver) SchemaDiff(_, _ *schema.Schema, opts ...schema.DiffOption) ([]schema.Change, error) {
+	return d.diff(opts)
+}
+
+func (d *mockDriver) TableDiff(_, _ *schema.Table, opts ...schema.DiffOption) ([]schema.Change, error) {
+	return d.diff(opts)
+}
+
+func (d *mockDriver) diff(opts []schema.DiffOption) ([]schema.Change, error) {
+	d.skipped = schema.NewDiffOptions(opts...).SkipChanges
+	return d.changes, d.err
+}
+
+func (d *mockDriver) PlanChanges(_ context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+	add := changes[0].(*schema.ModifyTable).Changes[0].(*schema.AddColumn)
+	return &migrate.Plan{
+		Name:       name,
+		Reversible: true,
+		Changes: []*migrate.Change{
+			{Cmd: "ALTER TABLE users ADD COLUMN " + add.C.Name + " int", Reverse: "ALTER TABLE users DROP COLUMN " + add.C.Name},
+			{Cmd: "UPDATE users SET id = ? WHERE name = ?", Args: []any{1, "a"}, Reverse: []string{"a", "b"}},
+		},
+	}, nil
+}
+
+func (d *mockDriver) ApplyChanges(_ context.Context, changes []schema.Change, _ ...migrate.PlanOption) error {
+	d.applied = changes
+	return nil
+}
+
+func (d *sessionDriver) Connect(context.Context) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.conns++
+	return &sessionConn{d: d, id: d.conns}, nil
+}
+
+func (d *sessionDriver) Driver() driver.Driver { return d }
+
+func (d *sessionDriver) Open(string) (driver.Conn, error) { return d.Connect(context.Background()) }
+
+func (c *sessionConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+
+func (c *sessionConn) Begin() (driver.Tx, error) {
+	c.logf("BEGIN")
+	return c, nil
+}
+
+func (c *sessionConn) Commit() error {
+	c.logf("COMMIT")
+	return nil
+}
+
+func (c *sessionConn) Rollback() error {
+	c.logf("ROLLBACK")
+	return nil
+}
+
+func (c *sessionConn) Close() error {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.closed++
+	return nil
+}
+
+func (c *sessionConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.logf(query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *sessionConn) logf(s string) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.log = append(c.d.log, fmt.Sprintf("%d: %s", c.id, s))
+}
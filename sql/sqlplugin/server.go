@@ -0,0 +1,498 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlplugin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+)
+
+// Config configures the plugin server.
+type Config struct {
+	// Opener opens the plugin driver by the URL given by Atlas. If
+	// nil, the driver registered in sqlclient for the URL is used.
+	Opener sqlclient.Opener
+
+	// ParseType is an optional function for parsing column
+	// types from their raw form, e.g. "varchar(255)".
+	ParseType func(string) (schema.Type, error)
+}
+
+// Serve serves the plugin on a local socket, and writes its address to the standard
+// output of the process for Atlas to connect to. It is expected to be called by the
+// main function of the plugin, and it returns when Atlas closes the connection. An
+// error is returned if the plugin was not started by Atlas.
+func Serve(cfg Config) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		fmt.Fprintln(os.Stderr, "This binary is an Atlas driver plugin, and is not meant to be executed directly.")
+		return errors.New("sqlplugin: missing or invalid magic cookie")
+	}
+	l, err := listen()
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	// The handshake line: <protocol-version>|<network>|<address>|<rpc-protocol>.
+	if _, err := fmt.Fprintf(os.Stdout, "%d|%s|%s|jsonrpc\n", ProtocolVersion, l.Addr().Network(), l.Addr().String()); err != nil {
+		return err
+	}
+	conn, err := l.Accept()
+	if err != nil {
+		return fmt.Errorf("sqlplugin: accept connection: %w", err)
+	}
+	return ServeConn(cfg, conn)
+}
+
+// listen listens on a unix socket in a temporary directory that is removed
+// when the listener is closed, or on a loopback TCP port on Windows.
+func listen() (net.Listener, error) {
+	if runtime.GOOS == "windows" {
+		return net.Listen("tcp", "127.0.0.1:0")
+	}
+	dir, err := os.MkdirTemp("", "atlas-plugin")
+	if err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", filepath.Join(dir, "plugin.sock"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &dirListener{Listener: l, dir: dir}, nil
+}
+
+// dirListener removes its directory when it is closed.
+type dirListener struct {
+	net.Listener
+	dir string
+}
+
+func (l *dirListener) Close() error {
+	err := l.Listener.Close()
+	if rerr := os.RemoveAll(l.dir); rerr != nil && err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// ServeConn serves the plugin on the given connection.
+func ServeConn(cfg Config, conn io.ReadWriteCloser) error {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(ServiceName, &server{cfg: cfg, dec: &decoder{parseType: cfg.ParseType}}); err != nil {
+		return err
+	}
+	srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// server implements the RPC service of the plugin.
+type server struct {
+	cfg      Config
+	dec      *decoder
+	client   *sqlclient.Client
+	mu       sync.Mutex
+	sessions map[int]*session
+	last     int
+}
+
+// session is a connection that is held for a connection of Atlas.
+type session struct {
+	conn *sql.Conn
+	tx   *sql.Tx // Active transaction, if any.
+}
+
+// Open opens the plugin driver.
+func (s *server) Open(args *OpenArgs, reply *OpenReply) error {
+	if s.client != nil {
+		return errors.New("sqlplugin: driver is already open")
+	}
+	u, err := url.Parse(args.URL)
+	if err != nil {
+		return fmt.Errorf("sqlplugin: parse open url: %w", err)
+	}
+	var c *sqlclient.Client
+	if s.cfg.Opener != nil {
+		c, err = s.cfg.Opener.Open(context.Background(), u)
+	} else {
+		c, err = sqlclient.OpenURL(context.Background(), u)
+	}
+	if err != nil {
+		return err
+	}
+	if c.URL != nil {
+		reply.Schema = c.URL.Schema
+	}
+	if caps, ok := migrate.DriverCapabilities(c.Driver); ok {
+		reply.Capabilities = &caps
+	}
+	s.client, s.sessions = c, make(map[int]*session)
+	return nil
+}
+
+// InspectSchema inspects a schema.
+func (s *server) InspectSchema(args *InspectArgs, reply *InspectReply) error {
+	c, err := s.driver()
+	if err != nil {
+		return err
+	}
+	sch, err := c.InspectSchema(context.Background(), args.Schema, &schema.InspectOptions{
+		Mode:    schema.InspectMode(args.Mode),
+		Tables:  args.Tables,
+		Include: args.Include,
+		Exclude: args.Exclude,
+	})
+	if err != nil {
+		if schema.IsNotExistError(err) {
+			reply.NotExist = err.Error()
+			return nil
+		}
+		return err
+	}
+	reply.Realm, err = portableRealm([]*schema.Schema{sch})
+	return err
+}
+
+// InspectRealm inspects a realm.
+func (s *server) InspectRealm(args *InspectArgs, reply *InspectReply) error {
+	c, err := s.driver()
+	if err != nil {
+		return err
+	}
+	r, err := c.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode:    schema.InspectMode(args.Mode),
+		Schemas: args.Schemas,
+		Include: args.Include,
+		Exclude: args.Exclude,
+	})
+	if err != nil {
+		return err
+	}
+	reply.Realm, err = portableRealm(r.Schemas)
+	return err
+}
+
+// RealmDiff computes the changes between two realms.
+func (s *server) RealmDiff(args *DiffArgs, reply *ChangesReply) error {
+	return s.diff(args, reply, func(d migrate.Driver, from, to *schema.Realm, opts []schema.DiffOption) ([]schema.Change, error) {
+		return d.RealmDiff(from, to, opts...)
+	})
+}
+
+// SchemaDiff computes the changes between the two schemas that are
+// referenced by the arguments.
+func (s *server) SchemaDiff(args *DiffArgs, reply *ChangesReply) error {
+	return s.diff(args, reply, func(d migrate.Driver, from, to *schema.Realm, opts []schema.DiffOption) ([]schema.Change, error) {
+		s1, err := schemaOf(from, args.FromRef)
+		if err != nil {
+			return nil, fmt.Errorf("sqlplugin: schema diff: %w", err)
+		}
+		s2, err := schemaOf(to, args.ToRef)
+		if err != nil {
+			return nil, fmt.Errorf("sqlplugin: schema diff: %w", err)
+		}
+		return d.SchemaDiff(s1, s2, opts...)
+	})
+}
+
+// TableDiff computes the changes between the two tables that are
+// referenced by the arguments.
+func (s *server) TableDiff(args *DiffArgs, reply *ChangesReply) error {
+	return s.diff(args, reply, func(d migrate.Driver, from, to *schema.Realm, opts []schema.DiffOption) ([]schema.Change, error) {
+		t1, err := tableOf(from, args.FromRef)
+		if err != nil {
+			return nil, fmt.Errorf("sqlplugin: table diff: %w", err)
+		}
+		t2, err := tableOf(to, args.ToRef)
+		if err != nil {
+			return nil, fmt.Errorf("sqlplugin: table diff: %w", err)
+		}
+		return d.TableDiff(t1, t2, opts...)
+	})
+}
+
+func (s *server) diff(args *DiffArgs, reply *ChangesReply, fn func(migrate.Driver, *schema.Realm, *schema.Realm, []schema.DiffOption) ([]schema.Change, error)) error {
+	c, err := s.driver()
+	if err != nil {
+		return err
+	}
+	from, err := s.dec.realm(args.From)
+	if err != nil {
+		return err
+	}
+	to, err := s.dec.realm(args.To)
+	if err != nil {
+		return err
+	}
+	var opts []schema.DiffOption
+	for _, k := range args.SkipChanges {
+		f, ok := changeTypes[k]
+		if !ok {
+			return fmt.Errorf("sqlplugin: unknown change kind %q", k)
+		}
+		opts = append(opts, schema.DiffSkipChanges(f()))
+	}
+	for from, to := range args.RenameSchemas {
+		opts = append(opts, schema.DiffRenameSchema(from, to))
+	}
+	changes, err := fn(c, from, to, opts)
+	if err != nil {
+		return err
+	}
+	reply.Changes, err = EncodeChanges(changes)
+	return err
+}
+
+// PlanChanges plans the given changes.
+func (s *server) PlanChanges(args *PlanArgs, reply *PlanReply) error {
+	c, err := s.driver()
+	if err != nil {
+		return err
+	}
+	changes, err := s.dec.changes(args.Changes)
+	if err != nil {
+		return err
+	}
+	plan, err := c.PlanChanges(context.Background(), args.Name, changes, planOption(args))
+	if err != nil {
+		return err
+	}
+	reply.Name, reply.Version = plan.Name, plan.Version
+	reply.Reversible, reply.Transactional = plan.Reversible, plan.Transactional
+	for _, pc := range plan.Changes {
+		wc := &PlanChange{Cmd: pc.Cmd, Comment: pc.Comment}
+		if wc.Args, err = newValues(pc.Args); err != nil {
+			return err
+		}
+		if wc.Reverse, err = pc.ReverseStmts(); err != nil {
+			return err
+		}
+		reply.Changes = append(reply.Changes, wc)
+	}
+	return nil
+}
+
+// ApplyChanges applies the given changes.
+func (s *server) ApplyChanges(args *PlanArgs, _ *struct{}) error {
+	c, err := s.driver()
+	if err != nil {
+		return err
+	}
+	changes, err := s.dec.changes(args.Changes)
+	if err != nil {
+		return err
+	}
+	return c.ApplyChanges(context.Background(), changes, planOption(args))
+}
+
+// Exec executes a statement.
+func (s *server) Exec(args *ExecArgs, reply *ExecReply) error {
+	e, err := s.execQuerier(args.Session)
+	if err != nil {
+		return err
+	}
+	values, err := anyValues(args.Args)
+	if err != nil {
+		return err
+	}
+	res, err := e.ExecContext(context.Background(), args.Query, values...)
+	if err != nil {
+		return err
+	}
+	// Not all drivers support the results below.
+	reply.LastInsertID, _ = res.LastInsertId()
+	reply.RowsAffected, _ = res.RowsAffected()
+	return nil
+}
+
+// Query executes a query and returns all its rows.
+func (s *server) Query(args *ExecArgs, reply *QueryReply) error {
+	e, err := s.execQuerier(args.Session)
+	if err != nil {
+		return err
+	}
+	values, err := anyValues(args.Args)
+	if err != nil {
+		return err
+	}
+	rows, err := e.QueryContext(context.Background(), args.Query, values...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if reply.Columns, err = rows.Columns(); err != nil {
+		return err
+	}
+	for rows.Next() {
+		dest := make([]any, len(reply.Columns))
+		for i := range dest {
+			dest[i] = new(any)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		row := make([]*Value, len(dest))
+		for i := range dest {
+			if row[i], err = NewValue(*dest[i].(*any)); err != nil {
+				return err
+			}
+		}
+		reply.Rows = append(reply.Rows, row)
+	}
+	return rows.Err()
+}
+
+// Connect starts a session, and holds a connection for it.
+func (s *server) Connect(_ *struct{}, reply *SessionArgs) error {
+	if s.client == nil {
+		return errors.New("sqlplugin: driver is not open")
+	}
+	conn, err := s.client.DB.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last++
+	s.sessions[s.last] = &session{conn: conn}
+	reply.Session = s.last
+	return nil
+}
+
+// Disconnect ends a session, aborts its open transaction
+// and releases its connection.
+func (s *server) Disconnect(args *SessionArgs, _ *struct{}) error {
+	s.mu.Lock()
+	ss, ok := s.sessions[args.Session]
+	delete(s.sessions, args.Session)
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("sqlplugin: unknown session %d", args.Session)
+	}
+	return ss.close()
+}
+
+// Begin starts a transaction on the session connection.
+func (s *server) Begin(args *SessionArgs, _ *struct{}) error {
+	ss, err := s.session(args.Session)
+	if err != nil {
+		return err
+	}
+	if ss.tx != nil {
+		return errors.New("sqlplugin: transaction already started")
+	}
+	ss.tx, err = ss.conn.BeginTx(context.Background(), nil)
+	return err
+}
+
+// Commit commits the session transaction.
+func (s *server) Commit(args *SessionArgs, _ *struct{}) error {
+	tx, err := s.popTx(args.Session)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Rollback aborts the session transaction.
+func (s *server) Rollback(args *SessionArgs, _ *struct{}) error {
+	tx, err := s.popTx(args.Session)
+	if err != nil {
+		return err
+	}
+	return tx.Rollback()
+}
+
+// Close closes the plugin driver, and ends its open sessions.
+func (s *server) Close(_ *struct{}, _ *struct{}) error {
+	if s.client == nil {
+		return nil
+	}
+	s.mu.Lock()
+	for id, ss := range s.sessions {
+		ss.close()
+		delete(s.sessions, id)
+	}
+	s.mu.Unlock()
+	c := s.client
+	s.client = nil
+	return c.Close()
+}
+
+func (s *server) driver() (migrate.Driver, error) {
+	if s.client == nil {
+		return nil, errors.New("sqlplugin: driver is not open")
+	}
+	return s.client.Driver, nil
+}
+
+func (s *server) session(id int) (*session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ss, ok := s.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("sqlplugin: unknown session %d", id)
+	}
+	return ss, nil
+}
+
+// execQuerier returns the transaction of the session, if one was started, or its connection.
+func (s *server) execQuerier(id int) (interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}, error) {
+	ss, err := s.session(id)
+	if err != nil {
+		return nil, err
+	}
+	if ss.tx != nil {
+		return ss.tx, nil
+	}
+	return ss.conn, nil
+}
+
+func (s *server) popTx(id int) (*sql.Tx, error) {
+	ss, err := s.session(id)
+	if err != nil {
+		return nil, err
+	}
+	if ss.tx == nil {
+		return nil, errors.New("sqlplugin: no active transaction")
+	}
+	tx := ss.tx
+	ss.tx = nil
+	return tx, nil
+}
+
+// close aborts the session transaction and releases its connection.
+func (ss *session) close() error {
+	if ss.tx != nil {
+		ss.tx.Rollback()
+		ss.tx = nil
+	}
+	return ss.conn.Close()
+}
+
+func planOption(args *PlanArgs) migrate.PlanOption {
+	return func(o *migrate.PlanOptions) {
+		o.SchemaQualifier = args.SchemaQualifier
+		o.Indent = args.Indent
+		o.Mode = migrate.PlanMode(args.Mode)
+	}
+}
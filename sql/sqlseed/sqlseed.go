@@ -0,0 +1,390 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package sqlseed provides support for managing seed (or reference) data, like the rows
+// of lookup tables, alongside the schema. The declared rows are diffed against the rows
+// stored in the database, and the planned statements insert the missing rows and update
+// the changed ones. Hence, applying the same plan twice has no effect.
+package sqlseed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// Data describes the seed rows of a table. Rows are identified by the primary
+	// key of the table, and therefore, Columns must contain all its columns.
+	Data struct {
+		Table   *schema.Table
+		Columns []string // Columns of the rows.
+		Rows    [][]any  // Values of the rows, ordered as Columns.
+	}
+
+	// Dialect describes how statements are formatted for a specific database.
+	Dialect struct {
+		// QuoteOpening and QuoteClosing are used for quoting identifiers.
+		QuoteOpening, QuoteClosing byte
+		// BackslashEscapes indicates if backslashes in string literals are escape characters.
+		BackslashEscapes bool
+	}
+)
+
+// List of builtin dialects.
+var (
+	MySQL    = Dialect{QuoteOpening: '`', QuoteClosing: '`', BackslashEscapes: true}
+	Postgres = Dialect{QuoteOpening: '"', QuoteClosing: '"'}
+	SQLite   = Dialect{QuoteOpening: '`', QuoteClosing: '`'}
+)
+
+// Plan returns a migration plan for bringing the seed data to the database. Rows that do
+// not exist in the database are inserted, and rows with different values are updated. Rows
+// that exist only in the database are kept as is. The values of the planned statements are
+// inlined, so they can be written to migration files, and each change can be reversed.
+//
+// A migrate.ErrNoPlan is returned in case the database is in sync with the seed data.
+func Plan(ctx context.Context, db schema.ExecQuerier, name string, d Dialect, data ...*Data) (*migrate.Plan, error) {
+	plan := &migrate.Plan{Name: name, Reversible: true, Transactional: true}
+	for _, sd := range data {
+		changes, err := d.plan(ctx, db, sd)
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	if len(plan.Changes) == 0 {
+		return nil, migrate.ErrNoPlan
+	}
+	return plan, nil
+}
+
+// plan returns the changes of the given seed data.
+func (d Dialect) plan(ctx context.Context, db schema.ExecQuerier, sd *Data) ([]*migrate.Change, error) {
+	t := sd.Table
+	if t == nil {
+		return nil, errors.New("sql/sqlseed: missing table for seed data")
+	}
+	if t.PrimaryKey == nil || len(t.PrimaryKey.Parts) == 0 {
+		return nil, fmt.Errorf("sql/sqlseed: table %q has no primary key", t.Name)
+	}
+	keys := make([]int, len(t.PrimaryKey.Parts))
+	for i, p := range t.PrimaryKey.Parts {
+		if p.C == nil {
+			return nil, fmt.Errorf("sql/sqlseed: unexpected primary key expression in table %q", t.Name)
+		}
+		if keys[i] = indexOf(sd.Columns, p.C.Name); keys[i] == -1 {
+			return nil, fmt.Errorf("sql/sqlseed: primary key column %q of table %q is missing in seed data", p.C.Name, t.Name)
+		}
+	}
+	for _, r := range sd.Rows {
+		if len(r) != len(sd.Columns) {
+			return nil, fmt.Errorf("sql/sqlseed: expect %d values for rows of table %q, got %d", len(sd.Columns), t.Name, len(r))
+		}
+	}
+	current, err := d.rows(ctx, db, sd, keys)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		changes []*migrate.Change
+		inserts [][]any
+	)
+	for _, r := range sd.Rows {
+		k, err := key(r, keys)
+		if err != nil {
+			return nil, err
+		}
+		cur, ok := current[k]
+		if !ok {
+			inserts = append(inserts, r)
+			continue
+		}
+		var set []int
+		for i := range sd.Columns {
+			if !equal(r[i], cur[i]) {
+				set = append(set, i)
+			}
+		}
+		if len(set) == 0 {
+			continue
+		}
+		c, err := d.update(sd, keys, set, r, cur)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	if len(inserts) > 0 {
+		c, err := d.insert(sd, keys, inserts)
+		if err != nil {
+			return nil, err
+		}
+		changes = append([]*migrate.Change{c}, changes...)
+	}
+	return changes, nil
+}
+
+// rows returns the rows that are stored in the database, keyed by their primary key.
+func (d Dialect) rows(ctx context.Context, db schema.ExecQuerier, sd *Data, keys []int) (map[string][]any, error) {
+	b := d.build().P("SELECT")
+	b.MapComma(sd.Columns, func(i int, b *sqlx.Builder) {
+		b.Ident(sd.Columns[i])
+	})
+	rows, err := db.QueryContext(ctx, b.P("FROM").Table(sd.Table).String())
+	if err != nil {
+		return nil, fmt.Errorf("sql/sqlseed: query rows of table %q: %w", sd.Table.Name, err)
+	}
+	defer rows.Close()
+	current := make(map[string][]any)
+	for rows.Next() {
+		r := make([]any, len(sd.Columns))
+		dest := make([]any, len(r))
+		for i := range r {
+			dest[i] = &r[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("sql/sqlseed: scan rows of table %q: %w", sd.Table.Name, err)
+		}
+		k, err := key(r, keys)
+		if err != nil {
+			return nil, err
+		}
+		current[k] = r
+	}
+	return current, rows.Err()
+}
+
+// insert returns the change for inserting the given rows.
+func (d Dialect) insert(sd *Data, keys []int, rows [][]any) (*migrate.Change, error) {
+	b := d.build().P("INSERT INTO").Table(sd.Table).Wrap(func(b *sqlx.Builder) {
+		b.MapComma(sd.Columns, func(i int, b *sqlx.Builder) {
+			b.Ident(sd.Columns[i])
+		})
+	}).P("VALUES")
+	if err := b.MapCommaErr(rows, func(i int, b *sqlx.Builder) error {
+		vs, err := d.literals(rows[i])
+		if err != nil {
+			return err
+		}
+		b.WriteString("(" + strings.Join(vs, ", ") + ")")
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	reverse, err := d.delete(sd, keys, rows)
+	if err != nil {
+		return nil, err
+	}
+	return &migrate.Change{
+		Cmd:     b.String(),
+		Comment: fmt.Sprintf("insert %d seed rows to %q table", len(rows), sd.Table.Name),
+		Reverse: reverse,
+	}, nil
+}
+
+// delete returns the statement for deleting the given rows.
+func (d Dialect) delete(sd *Data, keys []int, rows [][]any) (string, error) {
+	conds := make([]string, len(rows))
+	for i := range rows {
+		c, err := d.where(sd, keys, rows[i])
+		if err != nil {
+			return "", err
+		}
+		if len(keys) > 1 && len(rows) > 1 {
+			c = "(" + c + ")"
+		}
+		conds[i] = c
+	}
+	return d.build().P("DELETE FROM").Table(sd.Table).P("WHERE", strings.Join(conds, " OR ")).String(), nil
+}
+
+// update returns the change for updating the given columns of a row.
+func (d Dialect) update(sd *Data, keys, set []int, r, cur []any) (*migrate.Change, error) {
+	cmd, err := d.set(sd, keys, set, r)
+	if err != nil {
+		return nil, err
+	}
+	reverse, err := d.set(sd, keys, set, cur)
+	if err != nil {
+		return nil, err
+	}
+	return &migrate.Change{
+		Cmd:     cmd,
+		Comment: fmt.Sprintf("update seed row in %q table", sd.Table.Name),
+		Reverse: reverse,
+	}, nil
+}
+
+// set returns the statement for setting the given columns of a row.
+func (d Dialect) set(sd *Data, keys, set []int, r []any) (string, error) {
+	b := d.build().P("UPDATE").Table(sd.Table).P("SET")
+	if err := b.MapCommaErr(set, func(i int, b *sqlx.Builder) error {
+		v, err := d.literal(r[set[i]])
+		if err != nil {
+			return err
+		}
+		b.Ident(sd.Columns[set[i]]).P("=", v)
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	c, err := d.where(sd, keys, r)
+	if err != nil {
+		return "", err
+	}
+	return b.P("WHERE", c).String(), nil
+}
+
+// where returns the condition for matching the given row by its primary key.
+func (d Dialect) where(sd *Data, keys []int, r []any) (string, error) {
+	b := d.build()
+	for i, k := range keys {
+		if i > 0 {
+			b.P("AND")
+		}
+		v, err := d.literal(r[k])
+		if err != nil {
+			return "", err
+		}
+		b.Ident(sd.Columns[k]).P("=", v)
+	}
+	return b.String(), nil
+}
+
+func (d Dialect) build() *sqlx.Builder {
+	return &sqlx.Builder{QuoteOpening: d.QuoteOpening, QuoteClosing: d.QuoteClosing}
+}
+
+func (d Dialect) literals(r []any) ([]string, error) {
+	vs := make([]string, len(r))
+	for i := range r {
+		v, err := d.literal(r[i])
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+// literal returns the SQL literal of the given value.
+func (d Dialect) literal(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return d.quote(v), nil
+	case []byte:
+		return d.quote(string(v)), nil
+	case time.Time:
+		return d.quote(v.Format("2006-01-02 15:04:05.999999")), nil
+	default:
+		if s, ok := number(v); ok {
+			return s, nil
+		}
+		return "", fmt.Errorf("sql/sqlseed: unsupported value type %T", v)
+	}
+}
+
+func (d Dialect) quote(s string) string {
+	if d.BackslashEscapes {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// text returns the textual representation of the given value, used for comparing
+// seed values with the values scanned from the database. Note, drivers may return
+// numbers and booleans as text, and booleans as numbers.
+func text(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case bool:
+		if v {
+			return "1", true
+		}
+		return "0", true
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999"), true
+	default:
+		if s, ok := number(v); ok {
+			return s, true
+		}
+		return fmt.Sprint(v), true
+	}
+}
+
+func number(v any) (string, bool) {
+	switch v := v.(type) {
+	case int:
+		return strconv.FormatInt(int64(v), 10), true
+	case int8:
+		return strconv.FormatInt(int64(v), 10), true
+	case int16:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// equal reports if the seed value equals the value stored in the database.
+func equal(v, cur any) bool {
+	t1, ok1 := text(v)
+	t2, ok2 := text(cur)
+	return ok1 == ok2 && t1 == t2
+}
+
+// key returns the key of the row for matching seed rows with database rows.
+func key(r []any, keys []int) (string, error) {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		t, ok := text(r[k])
+		if !ok {
+			return "", errors.New("sql/sqlseed: unexpected NULL value in primary key column")
+		}
+		parts[i] = strconv.Quote(t)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+func indexOf(s []string, v string) int {
+	for i := range s {
+		if s[i] == v {
+			return i
+		}
+	}
+	return -1
+}
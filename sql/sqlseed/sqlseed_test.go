@@ -0,0 +1,80 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlseed_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlseed"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	var (
+		id      = schema.NewIntColumn("id", "int")
+		name    = schema.NewStringColumn("name", "varchar")
+		country = schema.NewTable("countries").
+			SetSchema(schema.New("public")).
+			AddColumns(id, name).
+			SetPrimaryKey(schema.NewPrimaryKey(id))
+		data = &sqlseed.Data{
+			Table:   country,
+			Columns: []string{"id", "name"},
+			Rows: [][]any{
+				{1, "Israel"},
+				{2, "Cote d'Ivoire"},
+				{3, "Greece"},
+				{4, `C:\`},
+			},
+		}
+	)
+	m.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name` FROM `public`.`countries`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow([]byte("1"), []byte("Israel")).
+			AddRow(int64(3), "Greek").
+			AddRow(int64(5), "Spain"))
+	plan, err := sqlseed.Plan(context.Background(), db, "seed", sqlseed.MySQL, data)
+	require.NoError(t, err)
+	require.Equal(t, "seed", plan.Name)
+	require.True(t, plan.Reversible)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, &migrate.Change{
+		Cmd:     "INSERT INTO `public`.`countries` (`id`, `name`) VALUES (2, 'Cote d''Ivoire'), (4, 'C:\\\\')",
+		Comment: `insert 2 seed rows to "countries" table`,
+		Reverse: "DELETE FROM `public`.`countries` WHERE `id` = 2 OR `id` = 4",
+	}, plan.Changes[0])
+	require.Equal(t, &migrate.Change{
+		Cmd:     "UPDATE `public`.`countries` SET `name` = 'Greece' WHERE `id` = 3",
+		Comment: `update seed row in "countries" table`,
+		Reverse: "UPDATE `public`.`countries` SET `name` = 'Greek' WHERE `id` = 3",
+	}, plan.Changes[1])
+
+	// Database is in sync with the seed data.
+	m.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "name" FROM "public"."countries"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(int64(1), "Israel").
+			AddRow(int64(2), "Cote d'Ivoire").
+			AddRow(int64(3), "Greece").
+			AddRow(int64(4), `C:\`))
+	_, err = sqlseed.Plan(context.Background(), db, "seed", sqlseed.Postgres, data)
+	require.ErrorIs(t, err, migrate.ErrNoPlan)
+
+	// Primary key columns are required.
+	_, err = sqlseed.Plan(context.Background(), db, "seed", sqlseed.Postgres, &sqlseed.Data{
+		Table:   country,
+		Columns: []string{"name"},
+		Rows:    [][]any{{"Israel"}},
+	})
+	require.EqualError(t, err, `sql/sqlseed: primary key column "id" of table "countries" is missing in seed data`)
+	require.NoError(t, m.ExpectationsWereMet())
+}
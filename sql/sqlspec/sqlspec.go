@@ -41,6 +41,39 @@ type (
 		schemahcl.DefaultExtension
 	}
 
+	// Func holds a specification for a function.
+	Func struct {
+		Name      string          `spec:",name"`
+		Qualifier string          `spec:",qualifier"`
+		Schema    *schemahcl.Ref  `spec:"schema"`
+		Lang      string          `spec:"lang,omitempty"`
+		Args      []*FuncArg      `spec:"arg"`
+		Return    *schemahcl.Type `spec:"return"`
+		// Columns of functions that return a table.
+		Columns []*Column `spec:"column"`
+		// The body is appended as additional attribute
+		// by the spec creator to marshal it last.
+		schemahcl.DefaultExtension
+	}
+
+	// Proc holds a specification for a procedure.
+	Proc struct {
+		Name      string         `spec:",name"`
+		Qualifier string         `spec:",qualifier"`
+		Schema    *schemahcl.Ref `spec:"schema"`
+		Lang      string         `spec:"lang,omitempty"`
+		Args      []*FuncArg     `spec:"arg"`
+		schemahcl.DefaultExtension
+	}
+
+	// FuncArg holds a specification for an argument of a function or a procedure.
+	FuncArg struct {
+		Name    string          `spec:",name"`
+		Type    *schemahcl.Type `spec:"type"`
+		Default cty.Value       `spec:"default"`
+		schemahcl.DefaultExtension
+	}
+
 	// Column holds a specification for a column in an SQL table.
 	Column struct {
 		Name    string          `spec:",name"`
@@ -96,6 +129,8 @@ type (
 
 func init() {
 	schemahcl.Register("view", &View{})
+	schemahcl.Register("function", &Func{})
+	schemahcl.Register("procedure", &Proc{})
 	schemahcl.Register("table", &Table{})
 	schemahcl.Register("schema", &Schema{})
 }
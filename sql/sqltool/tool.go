@@ -25,14 +25,14 @@ import (
 var (
 	// GolangMigrateFormatter returns migrate.Formatter compatible with golang-migrate/migrate.
 	GolangMigrateFormatter = templateFormatter(
-		"{{ now }}{{ with .Name }}_{{ . }}{{ end }}.up.sql",
+		"{{ with .Version }}{{ . }}{{ else }}{{ now }}{{ end }}{{ with .Name }}_{{ . }}{{ end }}.up.sql",
 		`{{ range .Changes }}{{ with .Comment }}-- {{ println . }}{{ end }}{{ printf "%s;\n" .Cmd }}{{ end }}`,
-		"{{ now }}{{ with .Name }}_{{ . }}{{ end }}.down.sql",
+		"{{ with .Version }}{{ . }}{{ else }}{{ now }}{{ end }}{{ with .Name }}_{{ . }}{{ end }}.down.sql",
 		`{{ range $c := rev .Changes }}{{ with $stmts := .ReverseStmts }}{{ with $c.Comment }}-- reverse: {{ println . }}{{ end }}{{ range $stmts }}{{ printf "%s;\n" . }}{{ end }}{{ end }}{{ end }}`,
 	)
 	// GooseFormatter returns migrate.Formatter compatible with pressly/goose.
 	GooseFormatter = templateFormatter(
-		"{{ now }}{{ with .Name }}_{{ . }}{{ end }}.sql",
+		"{{ with .Version }}{{ . }}{{ else }}{{ now }}{{ end }}{{ with .Name }}_{{ . }}{{ end }}.sql",
 		`-- +goose Up
 {{ range .Changes }}{{ with .Comment }}-- {{ println . }}{{ end }}{{ printf "%s;\n" .Cmd }}{{ end }}
 -- +goose Down
@@ -40,14 +40,14 @@ var (
 	)
 	// FlywayFormatter returns migrate.Formatter compatible with Flyway.
 	FlywayFormatter = templateFormatter(
-		"V{{ now }}{{ with .Name }}__{{ . }}{{ end }}.sql",
+		"V{{ with .Version }}{{ . }}{{ else }}{{ now }}{{ end }}{{ with .Name }}__{{ . }}{{ end }}.sql",
 		`{{ range .Changes }}{{ with .Comment }}-- {{ println . }}{{ end }}{{ printf "%s;\n" .Cmd }}{{ end }}`,
-		"U{{ now }}{{ with .Name }}__{{ . }}{{ end }}.sql",
+		"U{{ with .Version }}{{ . }}{{ else }}{{ now }}{{ end }}{{ with .Name }}__{{ . }}{{ end }}.sql",
 		`{{ range $c := rev .Changes }}{{ with $stmts := .ReverseStmts }}{{ with $c.Comment }}-- reverse: {{ println . }}{{ end }}{{ range $stmts }}{{ printf "%s;\n" . }}{{ end }}{{ end }}{{ end }}`,
 	)
 	// LiquibaseFormatter returns migrate.Formatter compatible with Liquibase.
 	LiquibaseFormatter = templateFormatter(
-		"{{ now }}{{ with .Name }}_{{ . }}{{ end }}.sql",
+		"{{ with .Version }}{{ . }}{{ else }}{{ now }}{{ end }}{{ with .Name }}_{{ . }}{{ end }}.sql",
 		`{{- $now := now -}}
 --liquibase formatted sql
 
@@ -60,7 +60,7 @@ var (
 	)
 	// DBMateFormatter returns migrate.Formatter compatible with amacneil/dbmate.
 	DBMateFormatter = templateFormatter(
-		"{{ now }}{{ with .Name }}_{{ . }}{{ end }}.sql",
+		"{{ with .Version }}{{ . }}{{ else }}{{ now }}{{ end }}{{ with .Name }}_{{ . }}{{ end }}.sql",
 		`-- migrate:up
 {{ range .Changes }}{{ with .Comment }}-- {{ println . }}{{ end }}{{ printf "%s;\n" .Cmd }}{{ end }}
 -- migrate:down
@@ -71,12 +71,25 @@ var (
 	DbmateFormatter = DBMateFormatter
 )
 
+// The migration files of the tools below keep the statements for reverting a
+// migration next to it, e.g. the "-- +goose Down" section of a goose file.
+var (
+	_ migrate.DownFile = (*DBMateFile)(nil)
+	_ migrate.DownFile = (*FlywayFile)(nil)
+	_ migrate.DownFile = (*GolangMigrateFile)(nil)
+	_ migrate.DownFile = (*GooseFile)(nil)
+	_ migrate.DownFile = (*LiquibaseFile)(nil)
+)
+
 type (
 	// GolangMigrateDir wraps fs.FS and provides a migrate.Scanner implementation able to understand files
 	// generated by the GolangMigrateFormatter for migration directory replaying.
 	GolangMigrateDir struct{ fs.FS }
 	// GolangMigrateFile wraps migrate.LocalFile with custom description function.
-	GolangMigrateFile struct{ *migrate.LocalFile }
+	GolangMigrateFile struct {
+		*migrate.LocalFile
+		down *migrate.LocalFile // paired down file, if exists.
+	}
 )
 
 // NewGolangMigrateDir returns a new GolangMigrateDir.
@@ -97,6 +110,7 @@ func (d *GolangMigrateDir) Path() string {
 }
 
 // Files implements Scanner.Files. It looks for all files with up.sql suffix and orders them by filename.
+// The down.sql file with the same version and description is paired with its up file, if exists.
 func (d *GolangMigrateDir) Files() ([]migrate.File, error) {
 	names, err := fs.Glob(d, "*.up.sql")
 	if err != nil {
@@ -112,7 +126,16 @@ func (d *GolangMigrateDir) Files() ([]migrate.File, error) {
 		if err != nil {
 			return nil, fmt.Errorf("sql/migrate: read file %q: %w", n, err)
 		}
-		ret[i] = &GolangMigrateFile{LocalFile: migrate.NewLocalFile(n, b)}
+		f := &GolangMigrateFile{LocalFile: migrate.NewLocalFile(n, b)}
+		dn := strings.TrimSuffix(n, ".up.sql") + ".down.sql"
+		switch b, err := fs.ReadFile(d, dn); {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("sql/migrate: read file %q: %w", dn, err)
+		default:
+			f.down = migrate.NewLocalFile(dn, b)
+		}
+		ret[i] = f
 	}
 	return ret, nil
 }
@@ -142,6 +165,14 @@ func (f *GolangMigrateFile) Desc() string {
 	return strings.TrimSuffix(f.LocalFile.Desc(), ".up")
 }
 
+// DownStmts returns the statements of the paired down.sql file.
+func (f *GolangMigrateFile) DownStmts() ([]string, error) {
+	if f.down == nil {
+		return nil, nil
+	}
+	return f.down.Stmts()
+}
+
 type (
 	// GooseDir wraps migrate.LocalDir and provides a migrate.Scanner implementation able to understand files
 	// generated by the GooseFormatter for migration directory replaying.
@@ -173,12 +204,27 @@ func (d *GooseDir) Files() ([]migrate.File, error) {
 
 // StmtDecls understands the migration format used by pressly/goose sql migration files.
 func (f *GooseFile) StmtDecls() ([]*migrate.Stmt, error) {
+	return f.stmtDecls(false)
+}
+
+// DownStmts returns the statements of the "Down" section of the goose migration file.
+func (f *GooseFile) DownStmts() ([]string, error) {
+	s, err := f.stmtDecls(true)
+	if err != nil {
+		return nil, err
+	}
+	return stmtsText(s), nil
+}
+
+// stmtDecls returns the statements of the "Up" or the "Down" section of the file.
+func (f *GooseFile) stmtDecls(down bool) ([]*migrate.Stmt, error) {
 	// Atlas custom delimiter is per file, goose has pragma do mark start and end of a delimiter.
 	// In order to use the Atlas lexer, we define a custom delimiter for the source SQL and edit it to use the
 	// custom delimiter.
 	const delim = "-- ATLAS_DELIM_END"
 	var (
 		state, lineCount int
+		collect          = !down // collect the lines of the current section
 		lines            = []string{"-- atlas:delimiter " + delim, ""}
 		sc               = bufio.NewScanner(bytes.NewReader(f.Bytes()))
 	)
@@ -197,11 +243,13 @@ Scan:
 					return nil, unexpectedPragmaErr(f, lineCount, "Up")
 				}
 			case "Down":
-				switch state {
-				case up: // found the "down" part
+				switch {
+				case state != up:
+					return nil, unexpectedPragmaErr(f, lineCount, "Down")
+				case !down: // found the "down" part
 					break Scan
 				default:
-					return nil, unexpectedPragmaErr(f, lineCount, "Down")
+					collect = true
 				}
 			case "StatementBegin":
 				switch state {
@@ -220,7 +268,7 @@ Scan:
 			}
 		}
 		// Write the line of the statement.
-		if !reGoosePragma.MatchString(line) && state != end {
+		if collect && !reGoosePragma.MatchString(line) && state != end {
 			// end of statement if line ends with semicolon
 			line = strings.TrimRightFunc(line, unicode.IsSpace)
 			lines = append(lines, line)
@@ -230,7 +278,9 @@ Scan:
 		}
 		if state == end {
 			state = up
-			lines = append(lines, delim)
+			if collect {
+				lines = append(lines, delim)
+			}
 		}
 	}
 	return migrate.Stmts(strings.Join(lines, "\n"))
@@ -242,11 +292,7 @@ func (f *GooseFile) Stmts() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	stmts := make([]string, len(s))
-	for i := range s {
-		stmts[i] = s[i].Text
-	}
-	return stmts, nil
+	return stmtsText(s), nil
 }
 
 type (
@@ -280,26 +326,36 @@ func (d *DBMateDir) Files() ([]migrate.File, error) {
 
 // StmtDecls understands the migration format used by amacneil/dbmate sql migration files.
 func (f *DBMateFile) StmtDecls() ([]*migrate.Stmt, error) {
+	return f.stmtDecls("up")
+}
+
+// DownStmts returns the statements of the "down" section of the dbmate migration file.
+func (f *DBMateFile) DownStmts() ([]string, error) {
+	s, err := f.stmtDecls("down")
+	if err != nil {
+		return nil, err
+	}
+	return stmtsText(s), nil
+}
+
+// stmtDecls returns the statements of the given section of the file.
+func (f *DBMateFile) stmtDecls(section string) ([]*migrate.Stmt, error) {
 	var (
-		state, lineCount int
-		lines            []string
-		sc               = bufio.NewScanner(bytes.NewReader(f.Bytes()))
+		current string
+		lines   []string
+		sc      = bufio.NewScanner(bytes.NewReader(f.Bytes()))
 	)
-Scan:
 	for sc.Scan() {
-		lineCount++
 		line := sc.Text()
 		// Handle pragmas.
 		if strings.HasPrefix(line, dbmatePragma) {
-			switch strings.TrimSpace(strings.TrimPrefix(line, dbmatePragma)) {
-			case "up":
-				state = up
-			case "down":
-				break Scan
+			switch p := strings.TrimSpace(strings.TrimPrefix(line, dbmatePragma)); p {
+			case "up", "down":
+				current = p
 			}
 		}
 		// Write the line of the statement.
-		if !reDBMatePragma.MatchString(line) && state == up {
+		if !reDBMatePragma.MatchString(line) && current == section {
 			lines = append(lines, line)
 		}
 	}
@@ -312,11 +368,7 @@ func (f *DBMateFile) Stmts() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	stmts := make([]string, len(s))
-	for i := range s {
-		stmts[i] = s[i].Text
-	}
-	return stmts, nil
+	return stmtsText(s), nil
 }
 
 type (
@@ -324,7 +376,10 @@ type (
 	// generated by the FlywayFormatter for migration directory replaying.
 	FlywayDir struct{ fs.FS }
 	// FlywayFile wraps migrate.LocalFile with custom statements function.
-	FlywayFile struct{ *migrate.LocalFile }
+	FlywayFile struct {
+		*migrate.LocalFile
+		undo *migrate.LocalFile // paired undo file, if exists.
+	}
 )
 
 // NewFlywayDir returns a new FlywayDir.
@@ -346,9 +401,12 @@ func (d *FlywayDir) Path() string {
 
 // Files implements Scanner.Files. It looks for all files with .sql suffix. The given directory is recursively scanned
 // for non-hidden subdirectories. All found files will be ordered by migration type (Baseline, Versioned, Repeatable)
-// and filename.
+// and filename. Undo files are paired with the versioned files of the same version.
 func (d *FlywayDir) Files() ([]migrate.File, error) {
-	var ff flywayFiles
+	var (
+		ff   flywayFiles
+		undo = make(map[string]string)
+	)
 	if err := fs.WalkDir(d, ".", func(path string, e fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -372,10 +430,16 @@ func (d *FlywayDir) Files() ([]migrate.File, error) {
 			base = filepath.Base(e.Name())
 			ext  = filepath.Ext(e.Name())
 		)
-		if ext != ".sql" || len(base) < 4 || (pfx != 'V' && pfx != 'B' && pfx != 'R') {
+		if ext != ".sql" || len(base) < 4 {
 			return nil
 		}
-		return ff.add(path)
+		switch pfx {
+		case 'V', 'B', 'R':
+			return ff.add(path)
+		case 'U':
+			undo[flywayVersion(path)] = path
+		}
+		return nil
 	}); err != nil {
 		return nil, err
 	}
@@ -388,7 +452,15 @@ func (d *FlywayDir) Files() ([]migrate.File, error) {
 		if err != nil {
 			return nil, fmt.Errorf("sql/migrate: read file %q: %w", n, err)
 		}
-		ret[i] = &FlywayFile{migrate.NewLocalFile(n, b)}
+		f := &FlywayFile{LocalFile: migrate.NewLocalFile(n, b)}
+		if u, ok := undo[flywayVersion(n)]; ok && filepath.Base(n)[0] == 'V' {
+			b, err := fs.ReadFile(d, u)
+			if err != nil {
+				return nil, fmt.Errorf("sql/migrate: read file %q: %w", u, err)
+			}
+			f.undo = migrate.NewLocalFile(u, b)
+		}
+		ret[i] = f
 	}
 	return ret, nil
 }
@@ -423,6 +495,14 @@ func (f FlywayFile) Version() string {
 	return flywayVersion(f.Name())
 }
 
+// DownStmts returns the statements of the paired undo file.
+func (f FlywayFile) DownStmts() ([]string, error) {
+	if f.undo == nil {
+		return nil, nil
+	}
+	return f.undo.Stmts()
+}
+
 // SetRepeatableVersion iterates over the migration files and assigns repeatable migrations a version number since
 // Atlas does not have the concept of repeatable migrations. Each repeatable migration file gets assigned the version
 // of the preceding migration file (or 0) followed by an 'R'.
@@ -452,7 +532,7 @@ func SetRepeatableVersion(ff []migrate.File) {
 	if v != "" {
 		// Every migration file following the first repeatable found are repeatable as well.
 		for i, f := range ff[idx:] {
-			ff[idx+i] = &FlywayFile{migrate.NewLocalFile(
+			ff[idx+i] = &FlywayFile{LocalFile: migrate.NewLocalFile(
 				fmt.Sprintf("V%sR__%s", v, f.Desc()),
 				f.Bytes(),
 			)}
@@ -460,9 +540,13 @@ func SetRepeatableVersion(ff []migrate.File) {
 	}
 }
 
-// LiquibaseDir wraps migrate.LocalDir and provides a migrate.Scanner implementation able to understand files
-// generated by the LiquibaseFormatter for migration directory replaying.
-type LiquibaseDir struct{ *migrate.LocalDir }
+type (
+	// LiquibaseDir wraps migrate.LocalDir and provides a migrate.Scanner implementation able to understand files
+	// generated by the LiquibaseFormatter for migration directory replaying.
+	LiquibaseDir struct{ *migrate.LocalDir }
+	// LiquibaseFile wraps migrate.LocalFile with custom down statements function.
+	LiquibaseFile struct{ *migrate.LocalFile }
+)
 
 // NewLiquibaseDir returns a new LiquibaseDir.
 func NewLiquibaseDir(path string) (*LiquibaseDir, error) {
@@ -473,18 +557,62 @@ func NewLiquibaseDir(path string) (*LiquibaseDir, error) {
 	return &LiquibaseDir{d}, nil
 }
 
+// Files looks for all files with .sql suffix and orders them by filename.
+func (d *LiquibaseDir) Files() ([]migrate.File, error) {
+	files, err := d.LocalDir.Files()
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range files {
+		files[i] = &LiquibaseFile{f.(*migrate.LocalFile)}
+	}
+	return files, nil
+}
+
+// DownStmts returns the rollback statements of the Liquibase changesets. Changesets
+// are rolled back in reverse order, and the "not required" rollbacks are ignored.
+func (f *LiquibaseFile) DownStmts() ([]string, error) {
+	var (
+		sets [][]string
+		sc   = bufio.NewScanner(bytes.NewReader(f.Bytes()))
+	)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, liquibaseChangeset):
+			sets = append(sets, nil)
+		case strings.HasPrefix(line, liquibaseRollback) && len(sets) > 0:
+			line = strings.TrimPrefix(strings.TrimPrefix(line, liquibaseRollback), ":")
+			if line = strings.TrimSpace(line); line != "" && !strings.EqualFold(line, "not required") {
+				sets[len(sets)-1] = append(sets[len(sets)-1], line)
+			}
+		}
+	}
+	var stmts []string
+	for i := len(sets) - 1; i >= 0; i-- {
+		s, err := migrate.Stmts(strings.Join(sets[i], "\n"))
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmtsText(s)...)
+	}
+	return stmts, nil
+}
+
 const (
 	none int = iota
 	up
 	begin
 	end
-	goosePragma  = "-- +goose"
-	dbmatePragma = "-- migrate:"
+	goosePragma        = "-- +goose"
+	dbmatePragma       = "-- migrate:"
+	liquibaseChangeset = "--changeset"
+	liquibaseRollback  = "--rollback"
 )
 
 var (
-	reGoosePragma  = regexp.MustCompile(regexp.QuoteMeta(goosePragma) + " Up|Down|StatementBegin|StatementEnd")
-	reDBMatePragma = regexp.MustCompile(dbmatePragma + "up|down")
+	reGoosePragma  = regexp.MustCompile(regexp.QuoteMeta(goosePragma) + " (Up|Down|StatementBegin|StatementEnd)")
+	reDBMatePragma = regexp.MustCompile(dbmatePragma + "(up|down)")
 )
 
 // flywayFiles retrieves flyway migration files by calls to add(). It will only keep the latest baseline and ignore
@@ -615,6 +743,15 @@ func templateFormatter(templates ...string) migrate.Formatter {
 	return tf
 }
 
+// stmtsText returns the text of the given statements.
+func stmtsText(s []*migrate.Stmt) []string {
+	stmts := make([]string, len(s))
+	for i := range s {
+		stmts[i] = s[i].Text
+	}
+	return stmts
+}
+
 // reverse changes for the down migration.
 func reverse(changes []*migrate.Change) []*migrate.Change {
 	n := len(changes)
@@ -158,12 +158,24 @@ DROP TABLE t1 IF EXISTS;
 	}
 }
 
+func TestFormatters_Version(t *testing.T) {
+	d := dir(t)
+	pl := migrate.NewPlanner(nil, d, migrate.PlanFormat(migrate.SequentialFormatter(sqltool.GolangMigrateFormatter, 4)), migrate.PlanWithChecksum(false))
+	require.NoError(t, pl.WritePlan(&migrate.Plan{Name: "t1", Changes: []*migrate.Change{{Cmd: "CREATE TABLE t1(c int)", Reverse: "DROP TABLE t1"}}}))
+	require.NoError(t, pl.WritePlan(&migrate.Plan{Name: "t2", Changes: []*migrate.Change{{Cmd: "CREATE TABLE t2(c int)", Reverse: "DROP TABLE t2"}}}))
+	require.Equal(t, 4, countFiles(t, d))
+	requireFileEqual(t, d, "0001_t1.up.sql", "CREATE TABLE t1(c int);\n")
+	requireFileEqual(t, d, "0001_t1.down.sql", "DROP TABLE t1;\n")
+	requireFileEqual(t, d, "0002_t2.up.sql", "CREATE TABLE t2(c int);\n")
+	requireFileEqual(t, d, "0002_t2.down.sql", "DROP TABLE t2;\n")
+}
+
 func TestScanners(t *testing.T) {
 	for _, tt := range []struct {
 		name                   string
 		dir                    migrate.Dir
 		versions, descriptions []string
-		stmts                  [][]string
+		stmts, down            [][]string
 	}{
 		{
 			name: "golang-migrate",
@@ -178,6 +190,10 @@ func TestScanners(t *testing.T) {
 				{"CREATE TABLE tbl\n(\n    col INT\n);"},
 				{"CREATE TABLE tbl_2 (col INT);"},
 			},
+			down: [][]string{
+				{"DROP TABLE tbl;"},
+				{"DROP TABLE tbl_2;"},
+			},
 		},
 		{
 			name: "goose",
@@ -199,6 +215,10 @@ func TestScanners(t *testing.T) {
 					"CREATE\nOR REPLACE FUNCTION histories_partition_creation( DATE, DATE )\nreturns void AS $$\nDECLARE\ncreate_query text;\nBEGIN\nFOR create_query IN\nSELECT 'CREATE TABLE IF NOT EXISTS histories_'\n           || TO_CHAR(d, 'YYYY_MM')\n           || ' ( CHECK( created_at >= timestamp '''\n           || TO_CHAR(d, 'YYYY-MM-DD 00:00:00')\n           || ''' AND created_at < timestamp '''\n           || TO_CHAR(d + INTERVAL '1 month', 'YYYY-MM-DD 00:00:00')\n           || ''' ) ) inherits ( histories );'\nFROM generate_series($1, $2, '1 month') AS d LOOP\n    EXECUTE create_query;\nEND LOOP;  -- LOOP END\nEND;         -- FUNCTION END\n$$\nlanguage plpgsql;",
 				},
 			},
+			down: [][]string{
+				{"DROP TABLE post;"},
+				nil,
+			},
 		},
 		{
 			name: "flyway",
@@ -218,6 +238,25 @@ func TestScanners(t *testing.T) {
 				{"ALTER TABLE tbl_2 ADD col_2 INTEGER NOT NULL;"},
 				{"CREATE VIEW `my_view` AS SELECT * FROM `post`;"},
 			},
+			down: [][]string{nil, nil, nil, nil},
+		},
+		{
+			name: "flyway undo",
+			dir: &sqltool.FlywayDir{FS: fstest.MapFS{
+				"V1__initial.sql": &fstest.MapFile{Data: []byte("CREATE TABLE t1 (c int);")},
+				"U1__initial.sql": &fstest.MapFile{Data: []byte("DROP TABLE t1;")},
+				"V2__second.sql":  &fstest.MapFile{Data: []byte("CREATE TABLE t2 (c int);")},
+			}},
+			versions:     []string{"1", "2"},
+			descriptions: []string{"initial", "second"},
+			stmts: [][]string{
+				{"CREATE TABLE t1 (c int);"},
+				{"CREATE TABLE t2 (c int);"},
+			},
+			down: [][]string{
+				{"DROP TABLE t1;"},
+				nil,
+			},
 		},
 		{
 			name: "liquibase",
@@ -236,6 +275,10 @@ func TestScanners(t *testing.T) {
 				},
 				{"CREATE TABLE tbl_2 (col INT);"},
 			},
+			down: [][]string{
+				{"ALTER TABLE post DROP created_at;", "DROP TABLE post;"},
+				{"DROP TABLE tbl_2;"},
+			},
 		},
 		{
 			name: "dbmate",
@@ -254,6 +297,10 @@ func TestScanners(t *testing.T) {
 				},
 				{"CREATE TABLE tbl_2 (col INT);"},
 			},
+			down: [][]string{
+				{"DROP TABLE post;"},
+				nil,
+			},
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
@@ -269,6 +316,12 @@ func TestScanners(t *testing.T) {
 				for j, stmt := range stmts {
 					require.Equal(t, tt.stmts[i][j], stmt)
 				}
+				down, err := files[i].(migrate.DownFile).DownStmts()
+				require.NoError(t, err)
+				require.Len(t, down, len(tt.down[i]))
+				for j, stmt := range down {
+					require.Equal(t, tt.down[i][j], stmt)
+				}
 			}
 		})
 	}
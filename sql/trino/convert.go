@@ -0,0 +1,165 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// Trino data types, as defined in its documentation.
+// https://trino.io/docs/current/language/types.html
+const (
+	TypeBoolean     = "boolean"
+	TypeTinyInt     = "tinyint"
+	TypeSmallInt    = "smallint"
+	TypeInteger     = "integer"
+	TypeBigInt      = "bigint"
+	TypeReal        = "real"
+	TypeDouble      = "double"
+	TypeDecimal     = "decimal"
+	TypeVarchar     = "varchar"
+	TypeChar        = "char"
+	TypeVarbinary   = "varbinary"
+	TypeJSON        = "json"
+	TypeDate        = "date"
+	TypeTime        = "time"
+	TypeTimeTZ      = "time with time zone"
+	TypeTimestamp   = "timestamp"
+	TypeTimestampTZ = "timestamp with time zone"
+	TypeUUID        = "uuid"
+)
+
+// FormatType converts schema type to its column form in the database.
+func FormatType(t schema.Type) (string, error) {
+	var f string
+	switch t := t.(type) {
+	case *schema.BoolType:
+		f = TypeBoolean
+	case *schema.IntegerType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeTinyInt, TypeSmallInt, TypeInteger, TypeBigInt:
+		case "int":
+			f = TypeInteger
+		default:
+			return "", fmt.Errorf("trino: unexpected integer type: %q", t.T)
+		}
+	case *schema.FloatType:
+		f = strings.ToLower(t.T)
+		if f != TypeReal {
+			f = TypeDouble
+		}
+	case *schema.DecimalType:
+		f = TypeDecimal
+		if t.Precision > 0 {
+			f = fmt.Sprintf("%s(%d,%d)", f, t.Precision, t.Scale)
+		}
+	case *schema.StringType:
+		f = strings.ToLower(t.T)
+		if f != TypeChar {
+			f = TypeVarchar
+		}
+		if t.Size > 0 {
+			f = fmt.Sprintf("%s(%d)", f, t.Size)
+		}
+	case *schema.BinaryType:
+		f = TypeVarbinary
+	case *schema.JSONType:
+		f = TypeJSON
+	case *schema.UUIDType:
+		f = TypeUUID
+	case *schema.TimeType:
+		f = strings.ToLower(t.T)
+		if t.Precision != nil {
+			// The precision is placed after the type
+			// name, and before its time zone suffix.
+			name, tz, _ := strings.Cut(f, " ")
+			f = fmt.Sprintf("%s(%d)", name, *t.Precision)
+			if tz != "" {
+				f += " " + tz
+			}
+		}
+	case *schema.UnsupportedType:
+		f = t.T
+	default:
+		return "", fmt.Errorf("trino: unexpected type: %T", t)
+	}
+	return f, nil
+}
+
+// ParseType returns the schema.Type value represented by the given raw type.
+// Structural types (e.g. array, map and row) are returned as unsupported types.
+func ParseType(raw string) (schema.Type, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	name, args := s, []string(nil)
+	if i := strings.IndexByte(s, '('); i != -1 {
+		j := strings.IndexByte(s, ')')
+		if j < i {
+			return nil, fmt.Errorf("trino: unexpected type: %q", raw)
+		}
+		name, args = s[:i], strings.Split(s[i+1:j], ",")
+		// Time types hold their precision between
+		// the name and the time zone suffix.
+		if rest := strings.TrimSpace(s[j+1:]); rest != "" {
+			name += " " + rest
+		}
+	}
+	switch name {
+	case TypeBoolean:
+		return &schema.BoolType{T: TypeBoolean}, nil
+	case TypeTinyInt, TypeSmallInt, TypeInteger, TypeBigInt:
+		return &schema.IntegerType{T: name}, nil
+	case TypeReal, TypeDouble:
+		return &schema.FloatType{T: name}, nil
+	case TypeDecimal:
+		t := &schema.DecimalType{T: TypeDecimal}
+		if len(args) > 0 {
+			p, err := strconv.Atoi(strings.TrimSpace(args[0]))
+			if err != nil {
+				return nil, fmt.Errorf("trino: parse precision %q: %w", args[0], err)
+			}
+			t.Precision = p
+		}
+		if len(args) > 1 {
+			s, err := strconv.Atoi(strings.TrimSpace(args[1]))
+			if err != nil {
+				return nil, fmt.Errorf("trino: parse scale %q: %w", args[1], err)
+			}
+			t.Scale = s
+		}
+		return t, nil
+	case TypeVarchar, TypeChar:
+		t := &schema.StringType{T: name}
+		if len(args) == 1 {
+			size, err := strconv.Atoi(strings.TrimSpace(args[0]))
+			if err != nil {
+				return nil, fmt.Errorf("trino: parse size %q: %w", args[0], err)
+			}
+			t.Size = size
+		}
+		return t, nil
+	case TypeVarbinary:
+		return &schema.BinaryType{T: TypeVarbinary}, nil
+	case TypeJSON:
+		return &schema.JSONType{T: TypeJSON}, nil
+	case TypeUUID:
+		return &schema.UUIDType{T: TypeUUID}, nil
+	case TypeDate, TypeTime, TypeTimeTZ, TypeTimestamp, TypeTimestampTZ:
+		t := &schema.TimeType{T: name}
+		if len(args) == 1 {
+			p, err := strconv.Atoi(strings.TrimSpace(args[0]))
+			if err != nil {
+				return nil, fmt.Errorf("trino: parse precision %q: %w", args[0], err)
+			}
+			t.Precision = &p
+		}
+		return t, nil
+	default:
+		return &schema.UnsupportedType{T: s}, nil
+	}
+}
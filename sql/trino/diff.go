@@ -0,0 +1,136 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultDiff provides basic diffing capabilities for Trino.
+// Note, it is recommended to call Open, create a new Driver and use its
+// Differ when a database connection is available.
+var DefaultDiff schema.Differ = &sqlx.Diff{DiffDriver: &diff{&conn{ExecQuerier: sqlx.NoRows}}}
+
+// A diff provides a Trino implementation for sqlx.DiffDriver.
+type diff struct{ *conn }
+
+// SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
+func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
+	// No special schema attribute diffing for Trino.
+	return nil
+}
+
+// SchemaObjectDiff returns a changeset for migrating schema objects from
+// one state to the other.
+func (*diff) SchemaObjectDiff(_, _ *schema.Schema) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (*diff) TableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
+	var (
+		changes []schema.Change
+		toProps = properties(to.Attrs)
+	)
+	for _, p1 := range from.Attrs {
+		p1, ok := p1.(*Property)
+		if !ok {
+			continue
+		}
+		switch p2, ok := toProps[p1.K]; {
+		case !ok:
+			changes = append(changes, &schema.DropAttr{A: p1})
+		case propValue(p1.V) != propValue(p2.V):
+			changes = append(changes, &schema.ModifyAttr{From: p1, To: p2})
+		}
+	}
+	fromProps := properties(from.Attrs)
+	for _, p2 := range to.Attrs {
+		if p2, ok := p2.(*Property); ok && fromProps[p2.K] == nil {
+			changes = append(changes, &schema.AddAttr{A: p2})
+		}
+	}
+	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// properties returns the table properties keyed by their names.
+func properties(attrs []schema.Attr) map[string]*Property {
+	props := make(map[string]*Property)
+	for _, a := range attrs {
+		if p, ok := a.(*Property); ok {
+			props[p.K] = p
+		}
+	}
+	return props
+}
+
+// propValue returns the normalized value of a property used for comparison.
+func propValue(v string) string {
+	return strings.Join(strings.Fields(v), " ")
+}
+
+func (*diff) ViewAttrChanged(_, _ *schema.View) bool {
+	return false // Not implemented.
+}
+
+// ColumnChange returns the schema changes (if any) for migrating one column to the other.
+func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column) (schema.ChangeKind, error) {
+	change := sqlx.CommentChange(from.Attrs, to.Attrs)
+	if from.Type.Null != to.Type.Null {
+		change |= schema.ChangeNull
+	}
+	changed, err := d.typeChanged(from, to)
+	if err != nil {
+		return schema.NoChange, err
+	}
+	if changed {
+		change |= schema.ChangeType
+	}
+	return change, nil
+}
+
+// typeChanged reports if the column type was changed.
+func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
+	fromT, toT := from.Type.Type, to.Type.Type
+	if fromT == nil || toT == nil {
+		return false, fmt.Errorf("trino: missing type information for column %q", from.Name)
+	}
+	from1, err := FormatType(fromT)
+	if err != nil {
+		return false, err
+	}
+	to1, err := FormatType(toT)
+	if err != nil {
+		return false, err
+	}
+	return !strings.EqualFold(strings.ReplaceAll(from1, " ", ""), strings.ReplaceAll(to1, " ", "")), nil
+}
+
+// IsGeneratedIndexName reports if the index name was generated by the database.
+func (*diff) IsGeneratedIndexName(_ *schema.Table, _ *schema.Index) bool {
+	return false
+}
+
+// IndexAttrChanged reports if the index attributes were changed.
+func (*diff) IndexAttrChanged(_, _ []schema.Attr) bool {
+	return false // Indexes are not supported by Trino.
+}
+
+// IndexPartAttrChanged reports if the index-part attributes were changed.
+func (*diff) IndexPartAttrChanged(_, _ *schema.Index, _ int) bool {
+	return false // Indexes are not supported by Trino.
+}
+
+// ReferenceChanged reports if the foreign key referential action was changed.
+func (*diff) ReferenceChanged(_, _ schema.ReferenceOption) bool {
+	return false // Foreign keys are not supported by Trino.
+}
@@ -0,0 +1,69 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_TableDiff(t *testing.T) {
+	type testcase struct {
+		name        string
+		from, to    *schema.Table
+		wantChanges []schema.Change
+	}
+	tests := []testcase{
+		{
+			name: "no changes",
+			from: schema.NewTable("orders").AddAttrs(&Property{K: "partitioned_by", V: "ARRAY['ds']"}),
+			to:   schema.NewTable("orders").AddAttrs(&Property{K: "partitioned_by", V: "ARRAY['ds']"}),
+		},
+		{
+			name: "properties",
+			from: schema.NewTable("orders").AddAttrs(&Property{K: "format", V: "'ORC'"}, &Property{K: "bucket_count", V: "8"}),
+			to:   schema.NewTable("orders").AddAttrs(&Property{K: "format", V: "'PARQUET'"}, &Property{K: "partitioned_by", V: "ARRAY['ds']"}, &schema.Comment{Text: "orders"}),
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{From: &Property{K: "format", V: "'ORC'"}, To: &Property{K: "format", V: "'PARQUET'"}},
+				&schema.DropAttr{A: &Property{K: "bucket_count", V: "8"}},
+				&schema.AddAttr{A: &Property{K: "partitioned_by", V: "ARRAY['ds']"}},
+				&schema.AddAttr{A: &schema.Comment{Text: "orders"}},
+			},
+		},
+		func() testcase {
+			var (
+				from = schema.NewTable("orders").
+					AddColumns(
+						schema.NewNullColumn("a").SetType(&schema.StringType{T: TypeVarchar}),
+						schema.NewNullColumn("b").SetType(&schema.TimeType{T: TypeTimestamp}),
+					)
+				to = schema.NewTable("orders").
+					AddColumns(
+						schema.NewNullColumn("a").SetType(&schema.StringType{T: TypeVarchar, Size: 10}),
+						schema.NewNullColumn("b").SetType(&schema.TimeType{T: TypeTimestamp}).SetComment("b"),
+					)
+			)
+			return testcase{
+				name: "columns",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeType},
+					&schema.ModifyColumn{From: from.Columns[1], To: to.Columns[1], Change: schema.ChangeComment},
+				},
+			}
+		}(),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := DefaultDiff.TableDiff(tt.from, tt.to)
+			require.NoError(t, err)
+			require.EqualValues(t, tt.wantChanges, changes)
+		})
+	}
+}
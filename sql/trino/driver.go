@@ -0,0 +1,106 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+)
+
+type (
+	// Driver represents a Trino (or Athena) driver for introspecting catalog
+	// schemas, generating diff between schema elements and apply migrations
+	// changes. The driver is scoped to the catalog of its connection.
+	Driver struct {
+		*conn
+		schema.Differ
+		schema.Inspector
+		migrate.PlanApplier
+	}
+
+	// database connection and its information.
+	conn struct {
+		schema.ExecQuerier
+	}
+)
+
+// DriverName holds the name used for registration.
+const DriverName = "trino"
+
+func init() {
+	sqlclient.Register(
+		DriverName,
+		sqlclient.DriverOpener(Open),
+		sqlclient.RegisterCodec(MarshalHCL, EvalHCL),
+		sqlclient.RegisterFlavours("athena"),
+		sqlclient.RegisterURLParser(parser{}),
+	)
+}
+
+// Open opens a new Trino driver. Note, the connected catalog
+// is used for inspection, and no queries are executed on open.
+func Open(db schema.ExecQuerier) (migrate.Driver, error) {
+	c := &conn{ExecQuerier: db}
+	return &Driver{
+		conn:        c,
+		Differ:      &sqlx.Diff{DiffDriver: &diff{c}},
+		Inspector:   &inspect{c},
+		PlanApplier: &planApply{c},
+	}, nil
+}
+
+// CheckClean implements migrate.CleanChecker.
+func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error {
+	r, err := d.InspectRealm(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		switch n := len(s.Tables); {
+		case n > 1:
+			return &migrate.NotCleanError{Reason: fmt.Sprintf("found multiple tables in schema %q: %d", s.Name, n)}
+		case n == 1 && (revT == nil || s.Tables[0].Name != revT.Name || revT.Schema != "" && s.Name != revT.Schema):
+			return &migrate.NotCleanError{Reason: fmt.Sprintf("found table %q in schema %q", s.Tables[0].Name, s.Name)}
+		}
+	}
+	return nil
+}
+
+// Capabilities implements migrate.Capable. The reported capabilities
+// are common to all connectors, as the catalogs may support more.
+func (d *Driver) Capabilities() migrate.Capabilities {
+	return migrate.Capabilities{
+		DropColumn:   true,
+		RenameColumn: true,
+	}
+}
+
+type parser struct{}
+
+// ParseURL implements the sqlclient.URLParser interface. The path of the URL
+// holds the catalog, and optionally, the schema. For example:
+// trino://user@localhost:8080/catalog/schema.
+func (parser) ParseURL(u *url.URL) *sqlclient.URL {
+	u1 := &sqlclient.URL{URL: u, DSN: u.String()}
+	if parts := strings.Split(strings.Trim(u.Path, "/"), "/"); len(parts) > 1 {
+		u1.Schema = parts[1]
+	}
+	return u1
+}
+
+// ChangeSchema implements the sqlclient.SchemaChanger interface.
+func (parser) ChangeSchema(u *url.URL, s string) *url.URL {
+	nu := *u
+	catalog := strings.Split(strings.Trim(u.Path, "/"), "/")[0]
+	nu.Path = "/" + catalog + "/" + s
+	return &nu
+}
@@ -0,0 +1,62 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_ParseURL(t *testing.T) {
+	u, err := url.Parse("trino://user@localhost:8080/hive/sales?source=atlas")
+	require.NoError(t, err)
+	ac := parser{}.ParseURL(u)
+	require.Equal(t, "sales", ac.Schema)
+	require.Equal(t, "trino://user@localhost:8080/hive/sales?source=atlas", ac.DSN)
+
+	u = parser{}.ChangeSchema(u, "other")
+	require.Equal(t, "/hive/other", u.Path)
+	require.Equal(t, "source=atlas", u.RawQuery)
+
+	u, err = url.Parse("trino://user@localhost:8080/hive")
+	require.NoError(t, err)
+	require.Empty(t, parser{}.ParseURL(u).Schema)
+	require.Equal(t, "/hive/sales", parser{}.ChangeSchema(u, "sales").Path)
+}
+
+func TestDriver_CheckClean(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	// Empty catalog.
+	m.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}))
+	require.NoError(t, drv.(migrate.CleanChecker).CheckClean(context.Background(), nil))
+
+	// Schema with tables.
+	m.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}).AddRow("sales"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "?"))).
+		WithArgs("sales").
+		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name"}).AddRow("sales", "orders"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "?"))).
+		WithArgs("sales").
+		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "column_name", "data_type", "is_nullable"}).
+			AddRow("sales", "orders", "id", "bigint", "YES"))
+	m.ExpectQuery(sqltest.Escape(`SHOW CREATE TABLE "sales"."orders"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"Create Table"}).AddRow("CREATE TABLE hive.sales.orders (\n   id bigint\n)"))
+	err = drv.(migrate.CleanChecker).CheckClean(context.Background(), nil)
+	require.EqualError(t, err, `sql/migrate: connected database is not clean: found table "orders" in schema "sales"`)
+}
@@ -0,0 +1,432 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// A inspect provides a Trino implementation for schema.Inspector.
+type inspect struct{ *conn }
+
+var _ schema.Inspector = (*inspect)(nil)
+
+// InspectRealm returns schema descriptions of all schemas in the connected catalog.
+func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	schemas, err := i.schemas(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &schema.InspectRealmOption{}
+	}
+	r := schema.NewRealm(schemas...)
+	if len(schemas) == 0 || !sqlx.ModeInspectRealm(opts).Is(schema.InspectTables) {
+		return sqlx.FilterRealm(r, opts)
+	}
+	if err := i.inspectTables(ctx, r, nil); err != nil {
+		return nil, err
+	}
+	sqlx.LinkSchemaTables(schemas)
+	return sqlx.FilterRealm(r, opts)
+}
+
+// InspectSchema returns schema descriptions of the tables in the given schema.
+// Trino connections are not attached to a schema by default, and therefore,
+// the schema name is required.
+func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	if name == "" {
+		return nil, fmt.Errorf("trino: missing schema name for inspection")
+	}
+	schemas, err := i.schemas(ctx, &schema.InspectRealmOption{
+		Schemas: []string{name},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch n := len(schemas); {
+	case n == 0:
+		return nil, &schema.NotExistError{Err: fmt.Errorf("trino: schema %q was not found", name)}
+	case n > 1:
+		return nil, fmt.Errorf("trino: %d schemas were found for %q", n, name)
+	}
+	if opts == nil {
+		opts = &schema.InspectOptions{}
+	}
+	r := schema.NewRealm(schemas...)
+	if sqlx.ModeInspectSchema(opts).Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, opts); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.FilterSchema(r.Schemas[0], opts)
+}
+
+func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
+	if err := i.tables(ctx, r, opts); err != nil {
+		return err
+	}
+	if err := i.columns(ctx, r); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			if err := i.showCreate(ctx, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemas returns the list of schemas in the connected catalog.
+func (i *inspect) schemas(ctx context.Context, opts *schema.InspectRealmOption) ([]*schema.Schema, error) {
+	var (
+		args  []any
+		query = schemasQuery
+	)
+	if opts != nil && len(opts.Schemas) > 0 {
+		query = fmt.Sprintf(schemasQueryArgs, nArgs(len(opts.Schemas)))
+		for _, s := range opts.Schemas {
+			args = append(args, s)
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("trino: querying schemas: %w", err)
+	}
+	names, err := sqlx.ScanStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("trino: scanning schemas: %w", err)
+	}
+	schemas := make([]*schema.Schema, 0, len(names))
+	for _, n := range names {
+		schemas = append(schemas, schema.New(n))
+	}
+	return schemas, nil
+}
+
+// tables queries and appends the tables of the given schemas.
+func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.InspectOptions) error {
+	var (
+		args  []any
+		query = fmt.Sprintf(tablesQuery, nArgs(len(realm.Schemas)))
+	)
+	for _, s := range realm.Schemas {
+		args = append(args, s.Name)
+	}
+	if opts != nil && len(opts.Tables) > 0 {
+		for _, t := range opts.Tables {
+			args = append(args, t)
+		}
+		query = fmt.Sprintf(tablesQueryArgs, nArgs(len(realm.Schemas)), nArgs(len(opts.Tables)))
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("trino: querying tables: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tSchema, name sql.NullString
+		if err := rows.Scan(&tSchema, &name); err != nil {
+			return fmt.Errorf("trino: scanning table information: %w", err)
+		}
+		s, ok := realm.Schema(tSchema.String)
+		if !ok {
+			return fmt.Errorf("trino: schema %q was not found in realm", tSchema.String)
+		}
+		s.AddTables(schema.NewTable(name.String))
+	}
+	return rows.Close()
+}
+
+// columns queries and appends the columns of the inspected tables.
+func (i *inspect) columns(ctx context.Context, realm *schema.Realm) error {
+	args := make([]any, 0, len(realm.Schemas))
+	for _, s := range realm.Schemas {
+		if len(s.Tables) > 0 {
+			args = append(args, s.Name)
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, nArgs(len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("trino: querying columns: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tSchema, table, name, typ, nullable sql.NullString
+		if err := rows.Scan(&tSchema, &table, &name, &typ, &nullable); err != nil {
+			return fmt.Errorf("trino: scanning columns: %w", err)
+		}
+		s, ok := realm.Schema(tSchema.String)
+		if !ok {
+			return fmt.Errorf("trino: schema %q was not found in realm", tSchema.String)
+		}
+		// Columns of views and filtered tables are returned as well.
+		t, ok := s.Table(table.String)
+		if !ok {
+			continue
+		}
+		ct, err := ParseType(typ.String)
+		if err != nil {
+			return err
+		}
+		t.AddColumns(&schema.Column{
+			Name: name.String,
+			Type: &schema.ColumnType{Type: ct, Raw: typ.String, Null: nullable.String == "YES"},
+		})
+	}
+	return rows.Close()
+}
+
+// showCreate extracts the table comment, its column comments and its properties from
+// the output of SHOW CREATE TABLE, as they are not exposed by the information schema
+// in a portable way.
+func (i *inspect) showCreate(ctx context.Context, t *schema.Table) error {
+	b := &sqlx.Builder{QuoteOpening: '"', QuoteClosing: '"'}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(showCreateQuery, b.Table(t).String()))
+	if err != nil {
+		return fmt.Errorf("trino: querying create statement of table %q: %w", t.Name, err)
+	}
+	stmts, err := sqlx.ScanStrings(rows)
+	if err != nil {
+		return fmt.Errorf("trino: scanning create statement of table %q: %w", t.Name, err)
+	}
+	if len(stmts) != 1 {
+		return fmt.Errorf("trino: unexpected number of create statements for table %q: %d", t.Name, len(stmts))
+	}
+	c, err := parseCreate(stmts[0])
+	if err != nil {
+		return fmt.Errorf("trino: parsing create statement of table %q: %w", t.Name, err)
+	}
+	if c.comment != nil {
+		t.SetComment(*c.comment)
+	}
+	for n, x := range c.columns {
+		if col, ok := t.Column(n); ok {
+			col.SetComment(x)
+		}
+	}
+	for _, p := range c.props {
+		t.AddAttrs(p)
+	}
+	return nil
+}
+
+// createStmt holds the parsed parts of a CREATE TABLE statement that are used by the inspection.
+type createStmt struct {
+	comment *string
+	columns map[string]string
+	props   []*Property
+}
+
+// parseCreate parses the output of SHOW CREATE TABLE. For example:
+//
+//	CREATE TABLE hive.s.t (
+//	   id bigint COMMENT 'id',
+//	   ds varchar
+//	)
+//	COMMENT 'comment'
+//	WITH (
+//	   format = 'ORC',
+//	   partitioned_by = ARRAY['ds']
+//	)
+func parseCreate(ddl string) (*createStmt, error) {
+	c := &createStmt{columns: make(map[string]string)}
+	start := strings.IndexByte(ddl, '(')
+	if start == -1 {
+		return nil, fmt.Errorf("missing column definitions")
+	}
+	end := closingParen(ddl, start)
+	if end == -1 {
+		return nil, fmt.Errorf("unbalanced column definitions")
+	}
+	for _, def := range splitTop(ddl[start+1 : end]) {
+		name, rest := columnName(def)
+		if i := indexTop(rest, " COMMENT '"); i != -1 {
+			v, _, err := unquote(strings.TrimSpace(rest[i+len(" COMMENT "):]))
+			if err != nil {
+				return nil, err
+			}
+			c.columns[name] = v
+		}
+	}
+	rest := strings.TrimSpace(ddl[end+1:])
+	if strings.HasPrefix(rest, "COMMENT '") {
+		v, n, err := unquote(rest[len("COMMENT "):])
+		if err != nil {
+			return nil, err
+		}
+		c.comment = &v
+		rest = strings.TrimSpace(rest[len("COMMENT ")+n:])
+	}
+	if strings.HasPrefix(rest, "WITH (") {
+		i := strings.IndexByte(rest, '(')
+		j := closingParen(rest, i)
+		if j == -1 {
+			return nil, fmt.Errorf("unbalanced table properties")
+		}
+		for _, p := range splitTop(rest[i+1 : j]) {
+			k, v, ok := strings.Cut(p, "=")
+			if !ok {
+				return nil, fmt.Errorf("unexpected table property: %q", p)
+			}
+			c.props = append(c.props, &Property{K: strings.TrimSpace(k), V: strings.TrimSpace(v)})
+		}
+	}
+	return c, nil
+}
+
+// columnName returns the (unquoted) name of the column definition, and the rest of the definition.
+func columnName(def string) (string, string) {
+	if strings.HasPrefix(def, `"`) {
+		for i := 1; i < len(def); i++ {
+			if def[i] != '"' {
+				continue
+			}
+			if i+1 < len(def) && def[i+1] == '"' {
+				i++
+				continue
+			}
+			return strings.ReplaceAll(def[1:i], `""`, `"`), def[i+1:]
+		}
+	}
+	name, rest, _ := strings.Cut(def, " ")
+	return name, " " + rest
+}
+
+// unquote returns the value of the single-quoted string at the beginning of s, and its length.
+func unquote(s string) (string, int, error) {
+	if !strings.HasPrefix(s, "'") {
+		return "", 0, fmt.Errorf("expected string literal: %q", s)
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			b.WriteByte('\'')
+			i++
+			continue
+		}
+		return b.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("unterminated string literal: %q", s)
+}
+
+// closingParen returns the index of the parenthesis that closes the one at the given position, or -1.
+func closingParen(s string, start int) int {
+	var depth int
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		case '\'', '"':
+			for i++; i < len(s) && s[i] != c; i++ {
+			}
+		}
+	}
+	return -1
+}
+
+// splitTop splits the given list by its top-level commas, ignoring
+// the ones that are nested in brackets, parentheses or quotes.
+func splitTop(s string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '\'', '"':
+			for i++; i < len(s) && s[i] != c; i++ {
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if p := strings.TrimSpace(s[start:]); p != "" {
+		parts = append(parts, p)
+	}
+	return parts
+}
+
+// indexTop returns the index of the first top-level occurrence of sub in s, or -1.
+func indexTop(s, sub string) int {
+	var depth int
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '\'':
+			for i++; i < len(s) && s[i] != c; i++ {
+			}
+		default:
+			if depth == 0 && strings.HasPrefix(s[i:], sub) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// nArgs returns the placeholders for n arguments.
+func nArgs(n int) string {
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+// Property describes a table property, as defined by the connector of the
+// catalog (e.g. format or partitioned_by). The value is kept as an SQL
+// expression. e.g. 'PARQUET' or ARRAY['ds'].
+type Property struct {
+	schema.Attr
+	K, V string
+}
+
+func init() {
+	schema.RegisterJSON(&Property{})
+}
+
+const (
+	// Query to list the schemas in the connected catalog.
+	schemasQuery     = "SELECT schema_name FROM information_schema.schemata WHERE schema_name <> 'information_schema' ORDER BY schema_name"
+	schemasQueryArgs = "SELECT schema_name FROM information_schema.schemata WHERE schema_name IN (%s) ORDER BY schema_name"
+
+	// Query to list the base tables of the given schemas.
+	tablesQuery     = "SELECT table_schema, table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema IN (%s) ORDER BY table_schema, table_name"
+	tablesQueryArgs = "SELECT table_schema, table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema IN (%s) AND table_name IN (%s) ORDER BY table_schema, table_name"
+
+	// Query to list the columns of the given schemas.
+	columnsQuery = "SELECT table_schema, table_name, column_name, data_type, is_nullable FROM information_schema.columns WHERE table_schema IN (%s) ORDER BY table_schema, table_name, ordinal_position"
+
+	// Query to show the create statement of a table.
+	showCreateQuery = "SHOW CREATE TABLE %s"
+)
@@ -0,0 +1,118 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectSchema(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	_, err = drv.InspectSchema(context.Background(), "", nil)
+	require.EqualError(t, err, "trino: missing schema name for inspection")
+
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "?"))).
+		WithArgs("sales").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}).AddRow("sales"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "?"))).
+		WithArgs("sales").
+		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name"}).
+			AddRow("sales", "orders").
+			AddRow("sales", "events"))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "?"))).
+		WithArgs("sales").
+		WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "column_name", "data_type", "is_nullable"}).
+			AddRow("sales", "events", "payload", "row(a integer, b array(varchar))", "YES").
+			AddRow("sales", "orders", "id", "bigint", "NO").
+			AddRow("sales", "orders", "total", "decimal(12,2)", "YES").
+			AddRow("sales", "orders", "created", "timestamp(3) with time zone", "YES").
+			AddRow("sales", "orders", "ds", "varchar", "YES").
+			AddRow("sales", "orders_view", "id", "bigint", "YES"))
+	m.ExpectQuery(sqltest.Escape(`SHOW CREATE TABLE "sales"."orders"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"Create Table"}).AddRow(`CREATE TABLE hive.sales.orders (
+   id bigint NOT NULL COMMENT 'order''s id',
+   total decimal(12, 2),
+   created timestamp(3) with time zone,
+   ds varchar
+)
+COMMENT 'orders, by day'
+WITH (
+   format = 'PARQUET',
+   partitioned_by = ARRAY['ds']
+)`))
+	m.ExpectQuery(sqltest.Escape(`SHOW CREATE TABLE "sales"."events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"Create Table"}).AddRow(`CREATE TABLE iceberg.sales.events (
+   "payload" ROW(a integer, b array(varchar)) COMMENT 'raw, event'
+)`))
+	s, err := drv.InspectSchema(context.Background(), "sales", nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 2)
+
+	orders := s.Tables[0]
+	require.Equal(t, "orders", orders.Name)
+	require.Equal(t, []schema.Attr{
+		&schema.Comment{Text: "orders, by day"},
+		&Property{K: "format", V: "'PARQUET'"},
+		&Property{K: "partitioned_by", V: "ARRAY['ds']"},
+	}, orders.Attrs)
+	require.Len(t, orders.Columns, 4)
+	require.Equal(t, &schema.ColumnType{Type: &schema.IntegerType{T: TypeBigInt}, Raw: "bigint"}, orders.Columns[0].Type)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "order's id"}}, orders.Columns[0].Attrs)
+	require.Equal(t, &schema.DecimalType{T: TypeDecimal, Precision: 12, Scale: 2}, orders.Columns[1].Type.Type)
+	p := 3
+	require.Equal(t, &schema.TimeType{T: TypeTimestampTZ, Precision: &p}, orders.Columns[2].Type.Type)
+	require.True(t, orders.Columns[3].Type.Null)
+
+	events := s.Tables[1]
+	require.Equal(t, "events", events.Name)
+	require.Empty(t, events.Attrs)
+	require.Equal(t, &schema.UnsupportedType{T: "row(a integer, b array(varchar))"}, events.Columns[0].Type.Type)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "raw, event"}}, events.Columns[0].Attrs)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestParseType(t *testing.T) {
+	p := func(n int) *int { return &n }
+	for _, tt := range []struct {
+		raw  string
+		want schema.Type
+	}{
+		{raw: "boolean", want: &schema.BoolType{T: TypeBoolean}},
+		{raw: "integer", want: &schema.IntegerType{T: TypeInteger}},
+		{raw: "double", want: &schema.FloatType{T: TypeDouble}},
+		{raw: "decimal(10,2)", want: &schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}},
+		{raw: "varchar", want: &schema.StringType{T: TypeVarchar}},
+		{raw: "varchar(255)", want: &schema.StringType{T: TypeVarchar, Size: 255}},
+		{raw: "char(3)", want: &schema.StringType{T: TypeChar, Size: 3}},
+		{raw: "varbinary", want: &schema.BinaryType{T: TypeVarbinary}},
+		{raw: "uuid", want: &schema.UUIDType{T: TypeUUID}},
+		{raw: "date", want: &schema.TimeType{T: TypeDate}},
+		{raw: "timestamp(6)", want: &schema.TimeType{T: TypeTimestamp, Precision: p(6)}},
+		{raw: "time(3) with time zone", want: &schema.TimeType{T: TypeTimeTZ, Precision: p(3)}},
+		{raw: "array(varchar(10))", want: &schema.UnsupportedType{T: "array(varchar(10))"}},
+		{raw: "map(varchar, bigint)", want: &schema.UnsupportedType{T: "map(varchar, bigint)"}},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			typ, err := ParseType(tt.raw)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, typ)
+			f, err := FormatType(typ)
+			require.NoError(t, err)
+			require.Equal(t, tt.raw, f)
+		})
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultPlan provides basic planning capabilities for Trino.
+// Note, it is recommended to call Open, create a new Driver and use its
+// migrate.PlanApplier when a database connection is available.
+var DefaultPlan migrate.PlanApplier = &planApply{conn: &conn{ExecQuerier: sqlx.NoRows}}
+
+// A planApply provides migration capabilities for schema elements.
+// Trino is mostly used for inspecting catalogs, and therefore, only
+// a subset of its DDL is supported by the planner.
+type planApply struct{ *conn }
+
+// PlanChanges returns a migration plan for the given schema changes.
+func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	s := &state{
+		conn: p.conn,
+		Plan: migrate.Plan{
+			Name: name,
+			// Trino does not support transactional DDL.
+			Transactional: false,
+		},
+	}
+	for _, o := range opts {
+		o(&s.PlanOptions)
+	}
+	if err := s.plan(changes); err != nil {
+		return nil, err
+	}
+	if err := sqlx.SetReversible(&s.Plan); err != nil {
+		return nil, err
+	}
+	sqlx.Annotate(&s.Plan, s.Annotations)
+	return &s.Plan, nil
+}
+
+// ApplyChanges applies the changes on the database. An error is returned
+// if the driver is unable to produce a plan to it, or one of the statements
+// is failed or unsupported.
+func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+}
+
+// state represents the state of a planning. It is not part of
+// planApply so that multiple planning/applying can be called
+// in parallel.
+type state struct {
+	*conn
+	migrate.Plan
+	migrate.PlanOptions
+}
+
+// plan builds the migration plan for applying the
+// given changes on the attached connection.
+func (s *state) plan(changes []schema.Change) error {
+	if s.SchemaQualifier != nil {
+		if err := sqlx.CheckChangesScope(s.PlanOptions, changes); err != nil {
+			return err
+		}
+	}
+	for _, c := range changes {
+		var err error
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			s.addSchema(c)
+		case *schema.DropSchema:
+			s.dropSchema(c)
+		case *schema.ModifySchema:
+			// No schema attributes are supported by Atlas.
+		case *schema.AddTable:
+			err = s.addTable(c)
+		case *schema.DropTable:
+			err = s.dropTable(c)
+		case *schema.ModifyTable:
+			err = s.modifyTable(c)
+		case *schema.RenameTable:
+			s.renameTable(c)
+		default:
+			err = fmt.Errorf("unsupported change %T", c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSchema appends the migrate.Change for creating a schema.
+func (s *state) addSchema(add *schema.AddSchema) {
+	b := s.Build("CREATE SCHEMA")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Ident(add.S.Name).String(),
+		Source:  add,
+		Reverse: s.Build("DROP SCHEMA").Ident(add.S.Name).String(),
+		Comment: fmt.Sprintf("add new schema named %q", add.S.Name),
+	})
+}
+
+// dropSchema appends the migrate.Change for dropping a schema.
+func (s *state) dropSchema(drop *schema.DropSchema) {
+	b := s.Build("DROP SCHEMA")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Ident(drop.S.Name).String(),
+		Source:  drop,
+		Comment: fmt.Sprintf("drop schema named %q", drop.S.Name),
+	})
+}
+
+// addTable builds and appends a migration change
+// for creating a table in a schema.
+func (s *state) addTable(add *schema.AddTable) error {
+	var (
+		errs []string
+		b    = s.Build("CREATE TABLE")
+	)
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	b.Table(add.T)
+	if len(add.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns", add.T.Name)
+	}
+	switch {
+	case add.T.PrimaryKey != nil:
+		return fmt.Errorf("create table %q: primary keys are not supported", add.T.Name)
+	case len(add.T.Indexes) > 0:
+		return fmt.Errorf("create table %q: indexes are not supported", add.T.Name)
+	case len(add.T.ForeignKeys) > 0:
+		return fmt.Errorf("create table %q: foreign keys are not supported", add.T.Name)
+	}
+	b.WrapIndent(func(b *sqlx.Builder) {
+		b.MapIndent(add.T.Columns, func(i int, b *sqlx.Builder) {
+			if err := s.column(b, add.T.Columns[i]); err != nil {
+				errs = append(errs, err.Error())
+			}
+		})
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
+	}
+	if c := (schema.Comment{}); sqlx.Has(add.T.Attrs, &c) {
+		b.P("COMMENT", quote(c.Text))
+	}
+	var props []*Property
+	for _, a := range add.T.Attrs {
+		if p, ok := a.(*Property); ok {
+			props = append(props, p)
+		}
+	}
+	if len(props) > 0 {
+		b.P("WITH")
+		b.Wrap(func(b *sqlx.Builder) {
+			b.MapComma(props, func(i int, b *sqlx.Builder) {
+				b.P(props[i].K, "=", props[i].V)
+			})
+		})
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP TABLE").Table(add.T).String(),
+		Comment: fmt.Sprintf("create %q table", add.T.Name),
+	})
+	return nil
+}
+
+// dropTable builds and appends the migrate.Change
+// for dropping a table from a schema.
+func (s *state) dropTable(drop *schema.DropTable) error {
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	if err := rs.addTable(&schema.AddTable{T: drop.T}); err != nil {
+		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
+	}
+	b := s.Build("DROP TABLE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Table(drop.T).String(),
+		Source:  drop,
+		Reverse: rs.Changes[0].Cmd,
+		Comment: fmt.Sprintf("drop %q table", drop.T.Name),
+	})
+	return nil
+}
+
+// modifyTable builds and appends the migration changes for bringing the
+// table into its modified state. Trino executes one ALTER action per
+// statement, and the set of supported actions depends on the connector.
+func (s *state) modifyTable(modify *schema.ModifyTable) error {
+	if len(modify.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns; drop the table instead", modify.T.Name)
+	}
+	for _, change := range modify.Changes {
+		var (
+			err error
+			cmd = s.Build("ALTER TABLE").Table(modify.T)
+			rev = s.Build("ALTER TABLE").Table(modify.T)
+		)
+		switch change := change.(type) {
+		case *schema.AddColumn:
+			cmd.P("ADD COLUMN")
+			err = s.column(cmd, change.C)
+			rev.P("DROP COLUMN").Ident(change.C.Name)
+		case *schema.DropColumn:
+			cmd.P("DROP COLUMN").Ident(change.C.Name)
+			rev.P("ADD COLUMN")
+			err = s.column(rev, change.C)
+		case *schema.RenameColumn:
+			cmd.P("RENAME COLUMN").Ident(change.From.Name).P("TO").Ident(change.To.Name)
+			rev.P("RENAME COLUMN").Ident(change.To.Name).P("TO").Ident(change.From.Name)
+		case *schema.ModifyColumn:
+			if change.Change&^schema.ChangeComment != schema.NoChange {
+				return fmt.Errorf("alter table %q: modifying column %q is not supported", modify.T.Name, change.From.Name)
+			}
+			cmd = s.Build("COMMENT ON COLUMN").TableResource(modify.T, change.To).P("IS", commentValue(change.To.Attrs))
+			rev = s.Build("COMMENT ON COLUMN").TableResource(modify.T, change.From).P("IS", commentValue(change.From.Attrs))
+		case *schema.AddAttr, *schema.DropAttr, *schema.ModifyAttr:
+			cmd, rev, err = s.tableAttr(modify.T, change)
+		case *schema.AddPrimaryKey, *schema.DropPrimaryKey, *schema.ModifyPrimaryKey:
+			return fmt.Errorf("alter table %q: primary keys are not supported", modify.T.Name)
+		case *schema.AddIndex, *schema.DropIndex, *schema.ModifyIndex, *schema.RenameIndex:
+			return fmt.Errorf("alter table %q: indexes are not supported", modify.T.Name)
+		case *schema.AddForeignKey, *schema.DropForeignKey, *schema.ModifyForeignKey:
+			return fmt.Errorf("alter table %q: foreign keys are not supported", modify.T.Name)
+		case *schema.AddCheck, *schema.DropCheck, *schema.ModifyCheck:
+			return fmt.Errorf("alter table %q: check constraints are not supported", modify.T.Name)
+		default:
+			return fmt.Errorf("alter table %q: unsupported change %T", modify.T.Name, change)
+		}
+		if err != nil {
+			return fmt.Errorf("alter table %q: %w", modify.T.Name, err)
+		}
+		s.append(&migrate.Change{
+			Cmd:     cmd.String(),
+			Reverse: rev.String(),
+			Source: &schema.ModifyTable{
+				T:       modify.T,
+				Changes: []schema.Change{change},
+			},
+			Comment: fmt.Sprintf("modify %q table", modify.T.Name),
+		})
+	}
+	return nil
+}
+
+// tableAttr returns the statement, and its reverse, for changing a table attribute.
+// Properties are reset to their connector defaults using the DEFAULT keyword.
+func (s *state) tableAttr(t *schema.Table, change schema.Change) (cmd *sqlx.Builder, rev *sqlx.Builder, err error) {
+	switch change := change.(type) {
+	case *schema.AddAttr:
+		if cmd, err = s.attr(t, change.A, true); err == nil {
+			rev, err = s.attr(t, change.A, false)
+		}
+	case *schema.DropAttr:
+		if cmd, err = s.attr(t, change.A, false); err == nil {
+			rev, err = s.attr(t, change.A, true)
+		}
+	case *schema.ModifyAttr:
+		if cmd, err = s.attr(t, change.To, true); err == nil {
+			rev, err = s.attr(t, change.From, true)
+		}
+	}
+	return cmd, rev, err
+}
+
+// attr returns the statement for setting the given table attribute, or resetting it in case set is false.
+func (s *state) attr(t *schema.Table, a schema.Attr, set bool) (*sqlx.Builder, error) {
+	switch a := a.(type) {
+	case *schema.Comment:
+		v := "NULL"
+		if set {
+			v = quote(a.Text)
+		}
+		return s.Build("COMMENT ON TABLE").Table(t).P("IS", v), nil
+	case *Property:
+		v := "DEFAULT"
+		if set {
+			v = a.V
+		}
+		return s.Build("ALTER TABLE").Table(t).P("SET PROPERTIES", a.K, "=", v), nil
+	default:
+		return nil, fmt.Errorf("unsupported table attribute %T", a)
+	}
+}
+
+func (s *state) renameTable(c *schema.RenameTable) {
+	s.append(&migrate.Change{
+		Source:  c,
+		Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
+		Cmd:     s.Build("ALTER TABLE").Table(c.From).P("RENAME TO").Table(c.To).String(),
+		Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Table(c.From).String(),
+	})
+}
+
+// column writes the column definition to the builder.
+func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
+	typ, err := FormatType(c.Type.Type)
+	if err != nil {
+		return fmt.Errorf("format type for column %q: %w", c.Name, err)
+	}
+	b.Ident(c.Name).P(typ)
+	if !c.Type.Null {
+		b.P("NOT NULL")
+	}
+	if c.Default != nil {
+		return fmt.Errorf("column %q: default values are not supported", c.Name)
+	}
+	if x := (schema.Comment{}); sqlx.Has(c.Attrs, &x) {
+		b.P("COMMENT", quote(x.Text))
+	}
+	return nil
+}
+
+func (s *state) append(c *migrate.Change) {
+	s.Changes = append(s.Changes, c)
+}
+
+// Build instantiates a new builder and writes the given phrase to it.
+func (s *state) Build(phrases ...string) *sqlx.Builder {
+	b := &sqlx.Builder{QuoteOpening: '"', QuoteClosing: '"', Schema: s.SchemaQualifier, Indent: s.Indent}
+	return b.P(phrases...)
+}
+
+// commentValue returns the comment of the given attributes as an SQL string, or NULL.
+func commentValue(attrs []schema.Attr) string {
+	if c := (schema.Comment{}); sqlx.Has(attrs, &c) && c.Text != "" {
+		return quote(c.Text)
+	}
+	return "NULL"
+}
+
+// quote returns the given string as a Trino string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
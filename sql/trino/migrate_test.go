@@ -0,0 +1,155 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanChanges(t *testing.T) {
+	orders := &schema.Table{
+		Name:   "orders",
+		Schema: schema.New("sales"),
+		Columns: []*schema.Column{
+			{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: TypeBigInt}}, Attrs: []schema.Attr{&schema.Comment{Text: "order's id"}}},
+			{Name: "tags", Type: &schema.ColumnType{Type: &schema.UnsupportedType{T: "array(varchar)"}, Null: true}},
+			{Name: "ds", Type: &schema.ColumnType{Type: &schema.StringType{T: TypeVarchar}, Null: true}},
+		},
+		Attrs: []schema.Attr{
+			&schema.Comment{Text: "orders"},
+			&Property{K: "format", V: "'PARQUET'"},
+			&Property{K: "partitioned_by", V: "ARRAY['ds']"},
+		},
+	}
+	tests := []struct {
+		name    string
+		changes []schema.Change
+		plan    *migrate.Plan
+		wantErr string
+	}{
+		{
+			name: "add schema",
+			changes: []schema.Change{
+				&schema.AddSchema{S: schema.New("sales"), Extra: []schema.Clause{&schema.IfNotExists{}}},
+				&schema.DropSchema{S: schema.New("old"), Extra: []schema.Clause{&schema.IfExists{}}},
+			},
+			plan: &migrate.Plan{
+				Changes: []*migrate.Change{
+					{Cmd: `CREATE SCHEMA IF NOT EXISTS "sales"`, Reverse: `DROP SCHEMA "sales"`},
+					{Cmd: `DROP SCHEMA IF EXISTS "old"`},
+				},
+			},
+		},
+		{
+			name:    "create table",
+			changes: []schema.Change{&schema.AddTable{T: orders}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE TABLE "sales"."orders" ("id" bigint NOT NULL COMMENT 'order''s id', "tags" array(varchar), "ds" varchar) COMMENT 'orders' WITH (format = 'PARQUET', partitioned_by = ARRAY['ds'])`,
+						Reverse: `DROP TABLE "sales"."orders"`,
+					},
+				},
+			},
+		},
+		{
+			name:    "drop table",
+			changes: []schema.Change{&schema.DropTable{T: orders, Extra: []schema.Clause{&schema.IfExists{}}}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `DROP TABLE IF EXISTS "sales"."orders"`,
+						Reverse: `CREATE TABLE "sales"."orders" ("id" bigint NOT NULL COMMENT 'order''s id', "tags" array(varchar), "ds" varchar) COMMENT 'orders' WITH (format = 'PARQUET', partitioned_by = ARRAY['ds'])`,
+					},
+				},
+			},
+		},
+		{
+			name:    "rename table",
+			changes: []schema.Change{&schema.RenameTable{From: orders, To: schema.NewTable("purchases").SetSchema(orders.Schema)}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE "sales"."orders" RENAME TO "sales"."purchases"`, Reverse: `ALTER TABLE "sales"."purchases" RENAME TO "sales"."orders"`},
+				},
+			},
+		},
+		{
+			name: "modify table",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: orders,
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewNullColumn("total").SetType(&schema.DecimalType{T: TypeDecimal, Precision: 12, Scale: 2})},
+						&schema.DropColumn{C: orders.Columns[1]},
+						&schema.ModifyColumn{From: orders.Columns[2], To: schema.NewNullColumn("ds").SetType(&schema.StringType{T: TypeVarchar}).SetComment("day"), Change: schema.ChangeComment},
+						&schema.ModifyAttr{From: &Property{K: "format", V: "'PARQUET'"}, To: &Property{K: "format", V: "'ORC'"}},
+						&schema.AddAttr{A: &Property{K: "bucket_count", V: "8"}},
+						&schema.DropAttr{A: &schema.Comment{Text: "orders"}},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE "sales"."orders" ADD COLUMN "total" decimal(12,2)`, Reverse: `ALTER TABLE "sales"."orders" DROP COLUMN "total"`},
+					{Cmd: `ALTER TABLE "sales"."orders" DROP COLUMN "tags"`, Reverse: `ALTER TABLE "sales"."orders" ADD COLUMN "tags" array(varchar)`},
+					{Cmd: `COMMENT ON COLUMN "sales"."orders"."ds" IS 'day'`, Reverse: `COMMENT ON COLUMN "sales"."orders"."ds" IS NULL`},
+					{Cmd: `ALTER TABLE "sales"."orders" SET PROPERTIES format = 'ORC'`, Reverse: `ALTER TABLE "sales"."orders" SET PROPERTIES format = 'PARQUET'`},
+					{Cmd: `ALTER TABLE "sales"."orders" SET PROPERTIES bucket_count = 8`, Reverse: `ALTER TABLE "sales"."orders" SET PROPERTIES bucket_count = DEFAULT`},
+					{Cmd: `COMMENT ON TABLE "sales"."orders" IS NULL`, Reverse: `COMMENT ON TABLE "sales"."orders" IS 'orders'`},
+				},
+			},
+		},
+		{
+			name: "modify column type",
+			changes: []schema.Change{
+				&schema.ModifyTable{T: orders, Changes: []schema.Change{
+					&schema.ModifyColumn{From: orders.Columns[0], To: schema.NewColumn("id").SetType(&schema.IntegerType{T: TypeInteger}), Change: schema.ChangeType},
+				}},
+			},
+			wantErr: `alter table "orders": modifying column "id" is not supported`,
+		},
+		{
+			name: "primary key",
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("t").SetPrimaryKey(schema.NewPrimaryKey(schema.NewIntColumn("id", TypeBigInt))).AddColumns(schema.NewIntColumn("id", TypeBigInt))},
+			},
+			wantErr: `create table "t": primary keys are not supported`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", tt.changes)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, plan.Transactional)
+			require.Equal(t, tt.plan.Reversible, plan.Reversible)
+			require.Len(t, plan.Changes, len(tt.plan.Changes))
+			for i, c := range plan.Changes {
+				require.Equal(t, tt.plan.Changes[i].Cmd, c.Cmd)
+				require.Equal(t, tt.plan.Changes[i].Reverse, c.Reverse)
+			}
+		})
+	}
+}
+
+func TestDefaultPlan(t *testing.T) {
+	err := DefaultPlan.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t1").AddColumns(schema.NewNullIntColumn("a", TypeInteger))},
+	})
+	require.EqualError(t, err, `create "t1" table: cannot execute statements without a database connection. use Open to create a new Driver`)
+}
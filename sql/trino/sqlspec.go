@@ -0,0 +1,225 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"fmt"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type doc struct {
+	Tables  []*sqlspec.Table  `spec:"table"`
+	Schemas []*sqlspec.Schema `spec:"schema"`
+}
+
+// evalSpec evaluates an Atlas DDL document using an unmarshaler into v by using the input.
+func evalSpec(p *hclparse.Parser, v any, input map[string]cty.Value) error {
+	switch v := v.(type) {
+	case *schema.Realm:
+		var d doc
+		if err := hclState.Eval(p, &d, input); err != nil {
+			return err
+		}
+		if err := specutil.Scan(v,
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables},
+			&specutil.ScanFuncs{Table: convertTable},
+		); err != nil {
+			return fmt.Errorf("specutil: failed converting to *schema.Realm: %w", err)
+		}
+	case *schema.Schema:
+		var d doc
+		if err := hclState.Eval(p, &d, input); err != nil {
+			return err
+		}
+		if len(d.Schemas) != 1 {
+			return fmt.Errorf("specutil: expecting document to contain a single schema, got %d", len(d.Schemas))
+		}
+		r := &schema.Realm{}
+		if err := specutil.Scan(r,
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables},
+			&specutil.ScanFuncs{Table: convertTable},
+		); err != nil {
+			return err
+		}
+		*v = *r.Schemas[0]
+	case schema.Schema, schema.Realm:
+		return fmt.Errorf("trino: Eval expects a pointer: received %[1]T, expected *%[1]T", v)
+	default:
+		return hclState.Eval(p, v, input)
+	}
+	return nil
+}
+
+// MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
+func MarshalSpec(v any, marshaler schemahcl.Marshaler) ([]byte, error) {
+	var d doc
+	switch s := v.(type) {
+	case *schema.Schema:
+		spec, err := schemaSpec(s)
+		if err != nil {
+			return nil, fmt.Errorf("specutil: failed converting schema to spec: %w", err)
+		}
+		d.Tables = spec.Tables
+		d.Schemas = []*sqlspec.Schema{spec.Schema}
+	case *schema.Realm:
+		for _, s := range s.Schemas {
+			spec, err := schemaSpec(s)
+			if err != nil {
+				return nil, fmt.Errorf("specutil: failed converting schema to spec: %w", err)
+			}
+			d.Tables = append(d.Tables, spec.Tables...)
+			d.Schemas = append(d.Schemas, spec.Schema)
+		}
+		if err := specutil.QualifyTables(d.Tables); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("specutil: failed marshaling spec. %T is not supported", v)
+	}
+	return marshaler.MarshalSpec(&d)
+}
+
+// convertTable converts a sqlspec.Table to a schema.Table.
+func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, error) {
+	t, err := specutil.Table(spec, parent, convertColumn, specutil.PrimaryKey, convertIndex, specutil.Check)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range spec.Extra.Children {
+		if r.Type != "property" {
+			continue
+		}
+		attr, ok := r.Attr("value")
+		if !ok {
+			return nil, fmt.Errorf("missing attribute table.%s.property.%s.value", spec.Name, r.Name)
+		}
+		v, err := attr.String()
+		if err != nil {
+			return nil, fmt.Errorf("expect string value for attribute table.%s.property.%s.value: %w", spec.Name, r.Name, err)
+		}
+		t.AddAttrs(&Property{K: r.Name, V: v})
+	}
+	return t, nil
+}
+
+// convertIndex converts a sqlspec.Index into a schema.Index.
+func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
+	return specutil.Index(spec, t)
+}
+
+// convertColumn converts a sqlspec.Column into a schema.Column.
+func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error) {
+	return specutil.Column(spec, convertColumnType)
+}
+
+// convertColumnType converts a sqlspec.Column into a concrete Trino schema.Type.
+func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
+	return TypeRegistry.Type(spec.Type, spec.Extra.Attrs)
+}
+
+// schemaSpec converts from a concrete Trino schema to Atlas specification.
+func schemaSpec(s *schema.Schema) (*specutil.SchemaSpec, error) {
+	return specutil.FromSchema(s, tableSpec, viewSpec)
+}
+
+// tableSpec converts from a concrete Trino sqlspec.Table to a schema.Table.
+func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
+	spec, err := specutil.FromTable(
+		t,
+		columnSpec,
+		specutil.FromPrimaryKey,
+		indexSpec,
+		specutil.FromForeignKey,
+		specutil.FromCheck,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range t.Attrs {
+		if p, ok := a.(*Property); ok {
+			spec.Extra.Children = append(spec.Extra.Children, &schemahcl.Resource{
+				Type:  "property",
+				Name:  p.K,
+				Attrs: []*schemahcl.Attr{schemahcl.StringAttr("value", p.V)},
+			})
+		}
+	}
+	return spec, nil
+}
+
+// viewSpec returns an error, as views are not supported by the driver.
+func viewSpec(v *schema.View) (*sqlspec.View, error) {
+	return nil, fmt.Errorf("trino: view %q is not supported", v.Name)
+}
+
+func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
+	return specutil.FromIndex(idx)
+}
+
+// columnSpec converts from a concrete Trino schema.Column into a sqlspec.Column.
+func columnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error) {
+	return specutil.FromColumn(c, columnTypeSpec)
+}
+
+// columnTypeSpec converts from a concrete Trino schema.Type into sqlspec.Column Type.
+func columnTypeSpec(t schema.Type) (*sqlspec.Column, error) {
+	st, err := TypeRegistry.Convert(t)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlspec.Column{Type: st}, nil
+}
+
+// TypeRegistry contains the supported TypeSpecs for the trino driver.
+// Structural types, such as array, map and row, are represented using
+// the sql() function. e.g. sql("array(varchar)").
+var TypeRegistry = schemahcl.NewRegistry(
+	schemahcl.WithFormatter(FormatType),
+	schemahcl.WithParser(ParseType),
+	schemahcl.WithSpecs(
+		schemahcl.NewTypeSpec(TypeBoolean),
+		schemahcl.NewTypeSpec(TypeTinyInt),
+		schemahcl.NewTypeSpec(TypeSmallInt),
+		schemahcl.NewTypeSpec(TypeInteger),
+		schemahcl.NewTypeSpec(TypeBigInt),
+		schemahcl.NewTypeSpec(TypeReal),
+		schemahcl.NewTypeSpec(TypeDouble),
+		schemahcl.NewTypeSpec(TypeDecimal, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr(), schemahcl.ScaleTypeAttr())),
+		schemahcl.NewTypeSpec(TypeVarchar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeChar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeVarbinary),
+		schemahcl.NewTypeSpec(TypeJSON),
+		schemahcl.NewTypeSpec(TypeUUID),
+		schemahcl.NewTypeSpec(TypeDate),
+		schemahcl.NewTypeSpec(TypeTime, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.NewTypeSpec(TypeTimestamp, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.AliasTypeSpec("timestamp_with_time_zone", TypeTimestampTZ, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.AliasTypeSpec("time_with_time_zone", TypeTimeTZ, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+	),
+)
+
+var (
+	hclState = schemahcl.New(append(
+		specOptions,
+		schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
+	)...)
+	// MarshalHCL marshals v into an Atlas HCL DDL document.
+	MarshalHCL = schemahcl.MarshalerFunc(func(v any) ([]byte, error) {
+		return MarshalSpec(v, hclState)
+	})
+	// EvalHCL implements the schemahcl.Evaluator interface.
+	EvalHCL = schemahcl.EvalFunc(evalSpec)
+
+	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
+	// of from an hclparse.Parser instance.
+	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
+)
@@ -0,0 +1,93 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSpec(t *testing.T) {
+	f := `
+schema "sales" {
+}
+
+table "orders" {
+  schema = schema.sales
+  column "id" {
+    type = bigint
+  }
+  column "total" {
+    null = true
+    type = decimal(12,2)
+  }
+  column "created" {
+    null = true
+    type = timestamp_with_time_zone(3)
+  }
+  column "tags" {
+    null    = true
+    type    = sql("array(varchar)")
+    comment = "order tags"
+  }
+  comment = "orders"
+  property "format" {
+    value = "'PARQUET'"
+  }
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	tbl, ok := s.Table("orders")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{
+		&schema.Comment{Text: "orders"},
+		&Property{K: "format", V: "'PARQUET'"},
+	}, tbl.Attrs)
+	p := 3
+	require.Equal(t, &schema.IntegerType{T: TypeBigInt}, tbl.Columns[0].Type.Type)
+	require.Equal(t, &schema.DecimalType{T: TypeDecimal, Precision: 12, Scale: 2}, tbl.Columns[1].Type.Type)
+	require.Equal(t, &schema.TimeType{T: TypeTimestampTZ, Precision: &p}, tbl.Columns[2].Type.Type)
+	require.Equal(t, &schema.UnsupportedType{T: "array(varchar)"}, tbl.Columns[3].Type.Type)
+
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, `table "orders" {
+  schema  = schema.sales
+  comment = "orders"
+  column "id" {
+    null = false
+    type = bigint
+  }
+  column "total" {
+    null = true
+    type = decimal(12,2)
+  }
+  column "created" {
+    null = true
+    type = timestamp_with_time_zone(3)
+  }
+  column "tags" {
+    null    = true
+    type    = sql("array(varchar)")
+    comment = "order tags"
+  }
+  property "format" {
+    value = "'PARQUET'"
+  }
+}
+schema "sales" {
+}
+`, string(buf))
+
+	var s2 schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &s2, nil))
+	changes, err := DefaultDiff.SchemaDiff(&s, &s2)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}